@@ -29,6 +29,7 @@ import (
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 	"github.com/carpenike/replog/internal/scheduler"
+	"github.com/carpenike/replog/internal/thumbnails"
 )
 
 //go:embed all:templates
@@ -63,6 +64,19 @@ func main() {
 		avatarDir = filepath.Join(filepath.Dir(dbPath), "avatars")
 	}
 
+	// Journal note images live alongside avatars unless configured otherwise.
+	attachmentDir := os.Getenv("REPLOG_ATTACHMENT_DIR")
+	if attachmentDir == "" {
+		attachmentDir = filepath.Join(filepath.Dir(dbPath), "attachments")
+	}
+
+	// Cached exercise demo thumbnails likewise.
+	thumbDir := os.Getenv("REPLOG_THUMBNAIL_DIR")
+	if thumbDir == "" {
+		thumbDir = filepath.Join(filepath.Dir(dbPath), "exercise-thumbs")
+	}
+	demoThumbs := thumbnails.New(thumbDir)
+
 	// Open database and run migrations.
 	db, err := database.Open(dbPath)
 	if err != nil {
@@ -76,6 +90,14 @@ func main() {
 
 	log.Printf("Database ready: %s", filepath.Clean(dbPath))
 
+	// Generation runs execute in-process; any left pending by a previous
+	// process will never finish.
+	if n, err := models.FailOrphanedGenerationRuns(db); err != nil {
+		log.Printf("Warning: failed to clean up orphaned generation runs: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d orphaned generation run(s) as failed", n)
+	}
+
 	// Bootstrap secret key for encrypting sensitive settings.
 	// Generates and stores a key automatically if REPLOG_SECRET_KEY is not set.
 	if _, source, err := models.GetOrCreateSecretKey(db); err != nil {
@@ -101,8 +123,10 @@ func main() {
 		log.Fatalf("Failed to bootstrap seed catalog: %v", err)
 	}
 
-	// Start background maintenance scheduler (daily: expired tokens, old notifications).
+	// Start background maintenance scheduler (daily: expired tokens, old
+	// notifications, demo thumbnail refresh).
 	maintenance := scheduler.New(db)
+	maintenance.SetThumbnails(demoThumbs)
 	maintenance.Start()
 
 	// Parse templates once at startup.
@@ -124,19 +148,34 @@ func main() {
 		log.Printf("Base URL: %s", baseURL)
 	}
 
-	// Set up session manager with SQLite store.
+	// Session settings come from admin settings, with env vars taking
+	// precedence. Reject bad env values rather than silently using defaults.
+	for _, key := range []string{"security.session_lifetime_days", "security.coach_session_lifetime_hours", "security.cookie_samesite"} {
+		sv := models.GetSettingValue(db, key)
+		if err := models.ValidateSetting(key, sv.Value); err != nil {
+			log.Fatalf("Invalid %s setting (from %s): %v", key, sv.Source, err)
+		}
+	}
+
+	// Set up session manager with SQLite store. Logins get a deadline from
+	// the current lifetime settings, so Lifetime here only covers sessions
+	// that haven't signed in.
 	sessionManager := scs.New()
 	sessionManager.Store = sqlite3store.New(db)
-	sessionManager.Lifetime = 30 * 24 * time.Hour // 30 days
+	sessionManager.Lifetime = models.GetSessionLifetime(db)
 	sessionManager.Cookie.HttpOnly = true
 	sessionManager.Cookie.SameSite = http.SameSiteLaxMode
+	if models.GetCookieSameSite(db) == "strict" {
+		sessionManager.Cookie.SameSite = http.SameSiteStrictMode
+	}
 
-	// Secure cookies: explicit override via REPLOG_SECURE_COOKIES, or auto-derived from base URL scheme.
-	switch {
-	case os.Getenv("REPLOG_SECURE_COOKIES") != "":
-		sessionManager.Cookie.Secure = os.Getenv("REPLOG_SECURE_COOKIES") == "true"
-	case strings.HasPrefix(baseURL, "https://"):
+	// Secure cookies: explicit setting (or REPLOG_SECURE_COOKIES), or
+	// auto-derived from base URL scheme.
+	switch models.GetSecureCookies(db) {
+	case "true":
 		sessionManager.Cookie.Secure = true
+	case "auto":
+		sessionManager.Cookie.Secure = strings.HasPrefix(baseURL, "https://")
 	}
 
 	// Initialize handlers.
@@ -162,6 +201,10 @@ func main() {
 		DB:        db,
 		Templates: tc,
 	}
+	substitutions := &handlers.Substitutions{
+		DB:        db,
+		Templates: tc,
+	}
 	trainingMaxes := &handlers.TrainingMaxes{
 		DB:        db,
 		Templates: tc,
@@ -169,6 +212,7 @@ func main() {
 	workouts := &handlers.Workouts{
 		DB:        db,
 		Templates: tc,
+		Sessions:  sessionManager,
 	}
 	users := &handlers.Users{
 		DB:        db,
@@ -176,6 +220,15 @@ func main() {
 		Templates: tc,
 		BaseURL:   baseURL,
 	}
+	passwordReset := &handlers.PasswordReset{
+		DB:        db,
+		Sessions:  sessionManager,
+		Templates: tc,
+	}
+	emailVerification := &handlers.EmailVerification{
+		DB:       db,
+		Sessions: sessionManager,
+	}
 	bodyWeights := &handlers.BodyWeights{
 		DB:        db,
 		Templates: tc,
@@ -188,6 +241,16 @@ func main() {
 		DB:        db,
 		Templates: tc,
 	}
+	userSessions := &handlers.UserSessions{
+		DB:        db,
+		Sessions:  sessionManager,
+		Templates: tc,
+	}
+	totp := &handlers.TOTP{
+		DB:        db,
+		Sessions:  sessionManager,
+		Templates: tc,
+	}
 	loginTokens := &handlers.LoginTokens{
 		DB:        db,
 		Sessions:  sessionManager,
@@ -199,8 +262,9 @@ func main() {
 		Templates: tc,
 	}
 	journal := &handlers.Journal{
-		DB:        db,
-		Templates: tc,
+		DB:            db,
+		Templates:     tc,
+		AttachmentDir: attachmentDir,
 	}
 	equipmentH := &handlers.Equipment{
 		DB:        db,
@@ -215,6 +279,10 @@ func main() {
 		Templates: tc,
 		AvatarDir: avatarDir,
 	}
+	exerciseThumbs := &handlers.ExerciseThumbs{
+		DB:         db,
+		Thumbnails: demoThumbs,
+	}
 	importExport := &handlers.ImportExport{
 		DB:        db,
 		Sessions:  sessionManager,
@@ -224,6 +292,15 @@ func main() {
 		DB:        db,
 		Templates: tc,
 	}
+	audit := &handlers.Audit{
+		DB:        db,
+		Templates: tc,
+	}
+	promptTemplates := &handlers.PromptTemplates{
+		DB:        db,
+		Sessions:  sessionManager,
+		Templates: tc,
+	}
 	generate := &handlers.Generate{
 		DB:        db,
 		Sessions:  sessionManager,
@@ -282,7 +359,21 @@ func main() {
 	// Set up router.
 	r := chi.NewRouter()
 
-	// Global middleware — applied to every request.
+	// REPLOG_TRUSTED_PROXIES is a comma-separated list of CIDRs or IPs whose
+	// X-Forwarded-For headers should be trusted (e.g., "127.0.0.1,10.0.0.0/8").
+	// Unset means the headers are ignored and the connection's address is
+	// the client IP.
+	trustedProxies, err := middleware.ParseTrustedProxies(os.Getenv("REPLOG_TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("Invalid REPLOG_TRUSTED_PROXIES: %v", err)
+	}
+	if len(trustedProxies) > 0 {
+		log.Printf("Trusted proxies: %v", trustedProxies)
+	}
+
+	// Global middleware — applied to every request. RealIP runs first so
+	// the access log, rate limiter and session list see the real client IP.
+	r.Use(middleware.RealIP(trustedProxies))
 	r.Use(middleware.RequestLogger)
 	r.Use(middleware.SecurityHeaders)
 
@@ -302,7 +393,7 @@ func main() {
 
 	// Middleware adapters — convert existing middleware to chi-compatible middleware.
 	withAuth := func(next http.Handler) http.Handler {
-		return middleware.RequireAuth(sessionManager, db, next)
+		return middleware.RequireAuth(sessionManager, db, middleware.BlockImpersonatedWrites(renderError, next))
 	}
 	withCSRF := func(next http.Handler) http.Handler {
 		return middleware.CSRFProtect(sessionManager, next)
@@ -321,19 +412,9 @@ func main() {
 	r.Get("/readyz", handleReadyz(db))
 	r.Get("/avatars/{filename}", avatars.Serve)
 
-	// Rate limiter for authentication endpoints — 10 attempts per minute per IP.
-	// REPLOG_TRUSTED_PROXIES is a comma-separated list of CIDRs or IPs whose
-	// X-Forwarded-For headers should be trusted (e.g., "127.0.0.1,10.0.0.0/8").
-	var trustedProxies []string
-	if tp := os.Getenv("REPLOG_TRUSTED_PROXIES"); tp != "" {
-		for _, p := range strings.Split(tp, ",") {
-			if p = strings.TrimSpace(p); p != "" {
-				trustedProxies = append(trustedProxies, p)
-			}
-		}
-		log.Printf("Trusted proxies: %v", trustedProxies)
-	}
-	authLimiter := middleware.NewRateLimiter(10, time.Minute, trustedProxies...)
+	// Rate limiter for authentication endpoints — 10 attempts per minute per
+	// client IP, as resolved by RealIP.
+	authLimiter := middleware.NewRateLimiter(10, time.Minute)
 
 	// --- Session-loaded routes — login/logout/token auth ---
 	r.Group(func(r chi.Router) {
@@ -342,9 +423,18 @@ func main() {
 
 		r.Get("/login", auth.LoginPage)
 		r.Post("/login", auth.LoginSubmit)
+		r.Post("/login/totp", auth.LoginTOTP)
 		r.Post("/logout", auth.Logout)
+		r.Post("/impersonate/stop", users.StopImpersonating)
 		r.Get("/auth/token/{token}", loginTokens.TokenLogin)
 
+		// Emailed password reset (unauthenticated, rate-limited).
+		r.Get("/auth/reset/request", passwordReset.RequestForm)
+		r.Post("/auth/reset/request", passwordReset.Request)
+		r.Get("/auth/reset/{token}", passwordReset.ResetForm)
+		r.Post("/auth/reset/{token}", passwordReset.Reset)
+		r.Get("/auth/verify-email/{token}", emailVerification.Verify)
+
 		// Passkey login ceremony (unauthenticated, session required).
 		if passkeys != nil {
 			r.Get("/passkeys/login/begin", passkeys.BeginLogin)
@@ -366,18 +456,42 @@ func main() {
 		// User Preferences (self-service — any authenticated user).
 		r.Get("/preferences", preferences.EditForm)
 		r.Post("/preferences", preferences.Update)
-
-		// Avatar upload/delete (self-service — any authenticated user).
+		r.Post("/preferences/email/verify", emailVerification.Send)
+
+		// Password change (self-service — any authenticated user).
+		r.Get("/preferences/password", users.ChangePasswordForm)
+		r.Post("/preferences/password", users.ChangePassword)
+
+		// Two-factor (TOTP) enrollment (self-service — any authenticated user).
+		r.Get("/preferences/totp", totp.Manage)
+		r.Get("/preferences/totp/begin", totp.BeginEnrollment)
+		r.Post("/preferences/totp/begin", totp.StartEnrollment)
+		r.Post("/preferences/totp/finish", totp.FinishEnrollment)
+		r.Post("/preferences/totp/cancel", totp.CancelEnrollment)
+		r.Post("/preferences/totp/recovery-codes", totp.RegenerateRecoveryCodes)
+		r.Post("/preferences/totp/disable", totp.Disable)
+
+		// Active login sessions (self-service — any authenticated user).
+		r.Get("/preferences/sessions", userSessions.List)
+		r.Post("/preferences/sessions/revoke-others", userSessions.RevokeOthers)
+		r.Post("/preferences/sessions/{sessionID}/revoke", userSessions.Revoke)
+
+		// Avatar upload/delete (self-service — any authenticated user) and
+		// generated avatars for users without one.
 		r.Post("/avatars/upload", avatars.Upload)
 		r.Post("/avatars/delete", avatars.Delete)
+		r.Get("/avatars/default/{id}", avatars.Default)
 
 		// Athletes — read access.
 		r.Get("/athletes", athletes.List)
 		r.Get("/athletes/{id}", athletes.Show)
+		r.Get("/athletes/{id}/records", athletes.Records)
+		r.Get("/athletes/{id}/workload.json", athletes.WorkloadJSON)
 
 		// Exercises — read access.
 		r.Get("/exercises", exercises.List)
 		r.Get("/exercises/{id}", exercises.Show)
+		r.Get("/exercise-thumbs/{id}", exerciseThumbs.Serve)
 
 		// Equipment — read access.
 		r.Get("/equipment", equipmentH.List)
@@ -385,6 +499,7 @@ func main() {
 		// Athlete Equipment — self-service.
 		r.Get("/athletes/{id}/equipment", equipmentH.AthleteEquipmentPage)
 		r.Post("/athletes/{id}/equipment", equipmentH.AddAthleteEquipment)
+		r.Post("/athletes/{id}/equipment/bulk", equipmentH.AddAthleteEquipmentBulk)
 		r.Post("/athletes/{id}/equipment/{equipmentID}/delete", equipmentH.RemoveAthleteEquipment)
 
 		// Accessory Plans.
@@ -399,22 +514,38 @@ func main() {
 
 		// Exercise History per athlete — read access.
 		r.Get("/athletes/{id}/exercises/{exerciseID}/history", exercises.ExerciseHistory)
+		r.Get("/athletes/{id}/exercises/{exerciseID}/volume.json", exercises.VolumeJSON)
 
 		// Body Weights.
 		r.Get("/athletes/{id}/body-weights", bodyWeights.List)
 		r.Post("/athletes/{id}/body-weights", bodyWeights.Create)
 		r.Post("/athletes/{id}/body-weights/{bwID}/delete", bodyWeights.Delete)
+		r.Get("/athletes/{id}/body-weights/import", importExport.BodyWeightImportPage)
+		r.Post("/athletes/{id}/body-weights/import", importExport.BodyWeightUpload)
+		r.Post("/athletes/{id}/body-weights/import/execute", importExport.Execute)
 
 		// Workouts — athlete self-service.
 		r.Get("/athletes/{id}/workouts", workouts.List)
 		r.Get("/athletes/{id}/workouts/new", workouts.NewForm)
+		r.Get("/athletes/{id}/warmups", workouts.WarmupSuggestion)
+		r.Get("/athletes/{id}/plates", workouts.PlateMath)
 		r.Post("/athletes/{id}/workouts", workouts.Create)
 		r.Get("/athletes/{id}/workouts/{workoutID}", workouts.Show)
+		r.Get("/athletes/{id}/workouts/{workoutID}.json", workouts.ShowJSON)
 		r.Post("/athletes/{id}/workouts/{workoutID}/notes", workouts.UpdateNotes)
+		r.Post("/athletes/{id}/workouts/{workoutID}/complete", workouts.MarkComplete)
+		r.Post("/athletes/{id}/workouts/{workoutID}/session/start", workouts.StartSession)
+		r.Post("/athletes/{id}/workouts/{workoutID}/session/stop", workouts.StopSession)
+		r.Post("/athletes/{id}/workouts/{workoutID}/duration", workouts.UpdateDuration)
 		r.Post("/athletes/{id}/workouts/{workoutID}/sets", workouts.AddSet)
 		r.Get("/athletes/{id}/workouts/{workoutID}/sets/{setID}/edit", workouts.EditSetForm)
 		r.Post("/athletes/{id}/workouts/{workoutID}/sets/{setID}", workouts.UpdateSet)
 		r.Post("/athletes/{id}/workouts/{workoutID}/sets/{setID}/delete", workouts.DeleteSet)
+		r.Post("/athletes/{id}/workouts/{workoutID}/exercises/reorder", workouts.ReorderExercises)
+		r.Post("/athletes/{id}/workouts/{workoutID}/copy-previous", workouts.CopyFromPrevious)
+		r.Post("/athletes/{id}/workouts/{workoutID}/timer/dismiss", workouts.DismissTimer)
+		r.Post("/athletes/{id}/workouts/{workoutID}/quick", workouts.QuickLog)
+		r.Post("/athletes/{id}/workouts/{workoutID}/exercises/{exerciseID}/delete", workouts.DeleteExerciseGroup)
 		r.Post("/athletes/{id}/workouts/{workoutID}/delete", workouts.Delete)
 
 		// Athlete Programs — prescription view (athlete self-service).
@@ -423,18 +554,25 @@ func main() {
 
 		// Journal — unified athlete timeline.
 		r.Get("/athletes/{id}/journal", journal.Timeline)
+		r.Get("/athletes/{id}/journal/search", journal.Search)
+		r.Get("/journal/attachments/{filename}", journal.ServeAttachment)
 
 		// Goal — self-service editing.
 		r.Post("/athletes/{id}/goal", athletes.UpdateGoal)
 
+		// Check-ins — self-service attendance without a logged workout.
+		r.Post("/athletes/{id}/check-in", athletes.CheckIn)
+
 		// Journal Notes — self-service (athletes can add their own notes).
 		r.Post("/athletes/{id}/notes", journal.CreateNote)
 		r.Post("/athletes/{id}/notes/{noteID}", journal.UpdateNote)
+		r.Post("/athletes/{id}/notes/{noteID}/acknowledge", journal.AcknowledgeNote)
 
 		// Export — self-service for own athlete data.
 		r.Get("/athletes/{id}/export", importExport.ExportPage)
 		r.Get("/athletes/{id}/export/json", importExport.ExportJSON)
 		r.Get("/athletes/{id}/export/csv", importExport.ExportCSV)
+		r.Get("/athletes/{id}/export/zip", importExport.ExportZIP)
 
 		// Passkey registration (requires auth, not coach/admin).
 		if passkeys != nil {
@@ -468,7 +606,14 @@ func main() {
 		r.Get("/athletes/{id}/edit", athletes.EditForm)
 		r.Post("/athletes/{id}", athletes.Update)
 		r.Post("/athletes/{id}/delete", athletes.Delete)
+		r.Post("/athletes/{id}/archive", athletes.Archive)
+		r.Post("/athletes/{id}/unarchive", athletes.Unarchive)
 		r.Post("/athletes/{id}/promote", athletes.Promote)
+		r.Post("/athletes/{id}/viewers", athletes.GrantViewer)
+		r.Post("/athletes/{id}/viewers/{userID}/delete", athletes.RevokeViewer)
+
+		// "View as" preview — admins and coaches (handler checks the target).
+		r.Post("/users/{id}/impersonate", users.Impersonate)
 
 		// Exercises — management.
 		r.Get("/exercises/new", exercises.NewForm)
@@ -476,6 +621,9 @@ func main() {
 		r.Get("/exercises/{id}/edit", exercises.EditForm)
 		r.Post("/exercises/{id}", exercises.Update)
 		r.Post("/exercises/{id}/delete", exercises.Delete)
+		r.Post("/exercises/{id}/unarchive", exercises.Unarchive)
+		r.Post("/exercises/{id}/aliases", exercises.AddAlias)
+		r.Post("/exercises/{id}/aliases/{aliasID}/delete", exercises.DeleteAlias)
 
 		// Exercise Equipment — management.
 		r.Post("/exercises/{id}/equipment", equipmentH.AddExerciseEquipment)
@@ -493,6 +641,8 @@ func main() {
 		r.Post("/athletes/{id}/assignments", assignments.Assign)
 		r.Post("/athletes/{id}/assignments/{assignmentID}/deactivate", assignments.Deactivate)
 		r.Post("/athletes/{id}/assignments/reactivate", assignments.Reactivate)
+		r.Post("/athletes/{id}/substitutions", substitutions.Create)
+		r.Post("/athletes/{id}/substitutions/{subID}/delete", substitutions.Delete)
 
 		// Training Maxes — management.
 		r.Get("/athletes/{id}/exercises/{exerciseID}/training-maxes/new", trainingMaxes.NewForm)
@@ -500,6 +650,10 @@ func main() {
 
 		// Workout Reviews (coach-only).
 		r.Get("/reviews/pending", reviews.PendingReviews)
+		r.Post("/reviews/bulk-approve", reviews.BulkApprove)
+		r.Get("/reviews/templates", reviews.ReviewTemplates)
+		r.Post("/reviews/templates", reviews.CreateReviewTemplate)
+		r.Post("/reviews/templates/{templateID}/delete", reviews.DeleteReviewTemplate)
 		r.Post("/athletes/{id}/workouts/{workoutID}/review", reviews.SubmitReview)
 		r.Post("/athletes/{id}/workouts/{workoutID}/review/delete", reviews.DeleteReview)
 
@@ -514,10 +668,13 @@ func main() {
 		r.Get("/programs/{id}/edit", programs.EditForm)
 		r.Post("/programs/{id}", programs.Update)
 		r.Post("/programs/{id}/delete", programs.Delete)
+		r.Post("/programs/{id}/clone", programs.Clone)
 		r.Post("/programs/{id}/sets", programs.AddSet)
+		r.Post("/programs/{id}/sets/reorder", programs.ReorderSets)
 		r.Post("/programs/{id}/sets/{setID}/update", programs.UpdateSet)
 		r.Post("/programs/{id}/sets/{setID}/delete", programs.DeleteSet)
 		r.Post("/programs/{id}/copy-week", programs.CopyWeek)
+		r.Post("/programs/{id}/deload-week", programs.AddDeloadWeek)
 
 		// Progression Rules (coach-only).
 		r.Post("/programs/{id}/progression", programs.AddProgressionRule)
@@ -528,6 +685,7 @@ func main() {
 		r.Get("/athletes/{id}/program/compatibility", programs.ProgramCompatibility)
 		r.Post("/athletes/{id}/program", programs.AssignProgram)
 		r.Post("/athletes/{id}/program/deactivate", programs.DeactivateProgram)
+		r.Post("/athletes/{id}/program/{assignmentID}/reassign", programs.ReassignProgram)
 
 		// Training Max Setup — batch TM entry after program assignment (coach-only).
 		r.Get("/athletes/{id}/training-maxes/setup", programs.TMSetupForm)
@@ -540,6 +698,11 @@ func main() {
 		// AI Coach — program generation (coach-only).
 		r.Get("/athletes/{id}/programs/generate", generate.Form)
 		r.Post("/athletes/{id}/programs/generate", generate.Submit)
+		r.Get("/athletes/{id}/programs/generate/history", generate.History)
+		r.Get("/athletes/{id}/programs/generate/runs/{runID}", generate.Status)
+		r.Get("/athletes/{id}/programs/generate/runs/{runID}/stream", generate.Stream)
+		r.Get("/athletes/{id}/programs/generate/runs/{runID}/context.json", generate.RunContext)
+		r.Get("/athletes/{id}/programs/generate/runs/{runID}/response.txt", generate.RunResponse)
 		r.Get("/athletes/{id}/programs/generate/preview", generate.Preview)
 		r.Post("/athletes/{id}/programs/generate/preview", generate.SaveEdits)
 		r.Post("/athletes/{id}/programs/generate/execute", generate.Execute)
@@ -586,6 +749,11 @@ func main() {
 		r.Post("/admin/settings", settings.Update)
 		r.Post("/admin/settings/test-llm", settings.TestConnection)
 		r.Post("/admin/settings/test-notify", notifications.TestNotify)
+		r.Get("/admin/settings/prompts", promptTemplates.List)
+		r.Post("/admin/settings/prompts", promptTemplates.Save)
+
+		// Audit log — admin-only.
+		r.Get("/admin/audit", audit.List)
 	})
 
 	// Start server with graceful shutdown.
@@ -648,6 +816,9 @@ func bootstrapAdmin(db *sql.DB) error {
 	if username == "" || password == "" {
 		return fmt.Errorf("no users exist and REPLOG_ADMIN_USER / REPLOG_ADMIN_PASS env vars are not set")
 	}
+	if err := models.ValidatePasswordStrength(db, username, password); err != nil {
+		return fmt.Errorf("REPLOG_ADMIN_PASS rejected: %w", err)
+	}
 
 	user, err := models.CreateUser(db, username, "", password, email, true, true, sql.NullInt64{})
 	if err != nil {
@@ -663,7 +834,7 @@ func bootstrapAdmin(db *sql.DB) error {
 // If exercises already exist, seeding is skipped.
 // Set REPLOG_SEED_CATALOG to an absolute path to use a custom catalog file.
 func bootstrapCatalog(db *sql.DB) error {
-	exercises, err := models.ListExercises(db, "")
+	exercises, err := models.ListExercises(db, models.ExerciseFilter{IncludeArchived: true})
 	if err != nil {
 		return fmt.Errorf("check exercises: %w", err)
 	}
@@ -21,9 +21,25 @@
  *   data-copy-from="<selector>"     Copy value from an input to clipboard.
  *   data-select-on-focus            Select the input contents on focus.
  *   data-print                      Trigger window.print().
+ *   data-check-all="<selector>"     On a checkbox: check or uncheck every matching
+ *                                   checkbox in the same form.
+ *   data-insert-into="<selector>"   On a select: append the chosen option's value
+ *                                   to the target textarea, then reset the select.
  *   data-new-athlete-toggle         Toggle new-athlete-fields based on select value.
  *   data-role-schedule-toggle        Toggle schedule-days fieldset based on role select value.
  *   data-action="dismiss-toast"     Dismiss a toast notification with animation.
+ *   data-move="up|down"             Move the closest [data-sortable-item] one
+ *                                   position within its parent list.
+ *   data-busy-text="<text>"          On a submit button: set aria-busy and swap
+ *                                   its label to text while the form posts.
+ *   data-context-section            Checkbox whose unchecked value is appended as
+ *                                   ?exclude= to every [data-context-download]
+ *                                   link's base URL.
+ *   data-context-reference          Checkbox whose checked value is appended as
+ *                                   ?reference_programs= to the same links.
+ *   data-generation-stream="<url>"  Append server-sent "token" events from url
+ *                                   to this element; on "done", fire
+ *                                   generation-done on the closest [hx-trigger].
  */
 (function () {
     "use strict";
@@ -122,6 +138,18 @@
             return;
         }
 
+        btn = e.target.closest("[data-move]");
+        if (btn) {
+            var item = up(btn, "[data-sortable-item]");
+            if (!item) return;
+            if (btn.getAttribute("data-move") === "up" && item.previousElementSibling) {
+                item.parentNode.insertBefore(item, item.previousElementSibling);
+            } else if (btn.getAttribute("data-move") === "down" && item.nextElementSibling) {
+                item.parentNode.insertBefore(item.nextElementSibling, item);
+            }
+            return;
+        }
+
         btn = e.target.closest("[data-copy-from]");
         if (btn) {
             copyToClipboard(btn, btn.getAttribute("data-copy-from"));
@@ -179,6 +207,20 @@
             var form = e.target.closest("form");
             if (form) form.submit();
         }
+        if (e.target.hasAttribute("data-check-all")) {
+            var scope = e.target.closest("form") || document;
+            scope.querySelectorAll(e.target.getAttribute("data-check-all")).forEach(function (cb) {
+                cb.checked = e.target.checked;
+            });
+        }
+        if (e.target.hasAttribute("data-insert-into") && e.target.value) {
+            var field = document.querySelector(e.target.getAttribute("data-insert-into"));
+            if (field) {
+                field.value = field.value ? field.value.replace(/\s*$/, "\n") + e.target.value : e.target.value;
+                field.focus();
+            }
+            e.target.value = "";
+        }
     });
 
     // ---- Loop toggle: disable weeks input when looping is checked ----
@@ -196,6 +238,24 @@
         }
     });
 
+    // ---- Context sections: keep the context.json download in sync ----
+    document.addEventListener("change", function (e) {
+        if (!e.target.hasAttribute("data-context-section") &&
+            !e.target.hasAttribute("data-context-reference")) return;
+        var form = e.target.closest("form");
+        if (!form) return;
+        var params = Array.prototype.slice.call(
+            form.querySelectorAll("[data-context-section]:not(:checked)")
+        ).map(function (cb) { return "exclude=" + encodeURIComponent(cb.value); });
+        Array.prototype.slice.call(
+            form.querySelectorAll("[data-context-reference]:checked")
+        ).forEach(function (cb) { params.push("reference_programs=" + encodeURIComponent(cb.value)); });
+        document.querySelectorAll("[data-context-download]").forEach(function (link) {
+            var base = link.getAttribute("data-context-download");
+            link.href = params.length ? base + "?" + params.join("&") : base;
+        });
+    });
+
     // ---- Submit delegation for long-running forms ----
     document.addEventListener("submit", function (e) {
         // Schedule serialization: collect checked day checkboxes into hidden field.
@@ -238,8 +298,38 @@
                 e.preventDefault();
             }
         }
+        // Busy text: mark the clicked button busy for slow full-page posts.
+        if (!e.defaultPrevented && e.submitter && e.submitter.hasAttribute("data-busy-text")) {
+            e.submitter.setAttribute("aria-busy", "true");
+            e.submitter.textContent = e.submitter.getAttribute("data-busy-text");
+        }
     });
 
+    // ---- AI Coach generation: stream output as it arrives ----
+    function attachGenerationStream(el) {
+        if (el.getAttribute("data-stream-attached") || !window.EventSource) return;
+        el.setAttribute("data-stream-attached", "true");
+
+        var source = new EventSource(el.getAttribute("data-generation-stream"));
+        source.addEventListener("token", function (e) {
+            el.hidden = false;
+            el.textContent += e.data;
+            el.scrollTop = el.scrollHeight;
+        });
+        source.addEventListener("done", function () {
+            source.close();
+            var poller = el.closest("[hx-trigger]");
+            if (poller && window.htmx) htmx.trigger(poller, "generation-done");
+        });
+    }
+
+    if (window.htmx) {
+        htmx.onLoad(function (root) {
+            var els = root.querySelectorAll ? root.querySelectorAll("[data-generation-stream]") : [];
+            Array.prototype.forEach.call(els, attachGenerationStream);
+        });
+    }
+
     // ---- Toast notifications: auto-dismiss and click handling ----
 
     // Dismiss a toast with slide-out animation.
@@ -1,5 +1,7 @@
 // rest-timer.js — Countdown rest timer between sets.
-// Activated by ?timer=SECONDS query parameter on workout detail page.
+// Activated by ?timer=SECONDS query parameter on workout detail page, or
+// resumed from data-timer-total / data-timer-remaining when the server has a
+// timer stored in the session (e.g. after a reload).
 // Uses an SVG ring for the countdown animation.
 (function () {
     "use strict";
@@ -8,8 +10,9 @@
     if (!timerEl) return;
 
     var params = new URLSearchParams(window.location.search);
-    var totalSeconds = parseInt(params.get("timer"), 10);
-    if (!totalSeconds || totalSeconds <= 0) {
+    var totalSeconds = parseInt(timerEl.dataset.timerTotal, 10) || parseInt(params.get("timer"), 10);
+    var startSeconds = parseInt(timerEl.dataset.timerRemaining, 10) || totalSeconds;
+    if (!totalSeconds || totalSeconds <= 0 || !startSeconds || startSeconds <= 0) {
         timerEl.hidden = true;
         return;
     }
@@ -20,7 +23,7 @@
         window.history.replaceState(null, "", cleanURL);
     }
 
-    var remaining = totalSeconds;
+    var remaining = startSeconds;
     var intervalID = null;
     var running = true;
 
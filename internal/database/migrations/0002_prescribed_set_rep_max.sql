@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Upper bound of a rep-range target (e.g. 8-12). NULL = single rep target
+-- in reps, or AMRAP when reps is also NULL.
+ALTER TABLE prescribed_sets ADD COLUMN rep_max INTEGER;
+
+-- +goose Down
+
+ALTER TABLE prescribed_sets DROP COLUMN rep_max;
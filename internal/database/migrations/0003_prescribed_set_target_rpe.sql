@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Target RPE (1-10) for RPE-based programming. NULL = no RPE target.
+ALTER TABLE prescribed_sets ADD COLUMN target_rpe REAL CHECK(target_rpe >= 1 AND target_rpe <= 10);
+
+-- +goose Down
+
+ALTER TABLE prescribed_sets DROP COLUMN target_rpe;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Display position of an exercise group within a workout. All sets for the
+-- same workout + exercise share one value. NULL = not reordered; such groups
+-- follow ordered ones by first-logged set.
+ALTER TABLE workout_sets ADD COLUMN sort_order INTEGER;
+
+-- +goose Down
+
+ALTER TABLE workout_sets DROP COLUMN sort_order;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Barbell weight used when suggesting warm-up sets. NULL = default (45).
+ALTER TABLE athletes ADD COLUMN bar_weight REAL CHECK(bar_weight > 0);
+
+-- +goose Down
+
+ALTER TABLE athletes DROP COLUMN bar_weight;
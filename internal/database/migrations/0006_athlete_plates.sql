@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Comma-separated plate weights available to the athlete (e.g. "45,25,10,5").
+-- NULL = default plate set.
+ALTER TABLE athletes ADD COLUMN plates TEXT;
+
+-- +goose Down
+
+ALTER TABLE athletes DROP COLUMN plates;
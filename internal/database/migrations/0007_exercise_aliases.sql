@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Alternate names for an exercise (e.g. "Barbell Squat" for "Back Squat").
+-- Used to match imported and quick-logged names against the catalog.
+CREATE TABLE IF NOT EXISTS exercise_aliases (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    alias       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_aliases_exercise
+    ON exercise_aliases(exercise_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS exercise_aliases;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Primary muscle group / movement pattern for catalog filtering and
+-- volume-by-muscle reporting. NULL = uncategorized.
+ALTER TABLE exercises ADD COLUMN muscle_group TEXT
+    CHECK(muscle_group IN ('push', 'pull', 'legs', 'core', 'full_body', 'conditioning'));
+
+-- +goose Down
+
+ALTER TABLE exercises DROP COLUMN muscle_group;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Single-leg/arm exercises. Sets default to rep_type 'each_side' and count
+-- both sides toward volume.
+ALTER TABLE exercises ADD COLUMN unilateral INTEGER NOT NULL DEFAULT 0 CHECK(unilateral IN (0, 1));
+
+-- +goose Down
+
+ALTER TABLE exercises DROP COLUMN unilateral;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Archived exercises are hidden from pickers and the catalog list but keep
+-- their workout, training max, and program history.
+ALTER TABLE exercises ADD COLUMN archived INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1));
+
+-- +goose Down
+
+ALTER TABLE exercises DROP COLUMN archived;
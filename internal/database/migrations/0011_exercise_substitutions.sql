@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Standing per-athlete exercise swaps (e.g. Goblet Squat for Back Squat when
+-- the athlete has no rack). Applied when building prescriptions.
+CREATE TABLE IF NOT EXISTS exercise_substitutions (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    athlete_id       INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    from_exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    to_exercise_id   INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(athlete_id, from_exercise_id),
+    CHECK(from_exercise_id != to_exercise_id)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS exercise_substitutions;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Quantity and per-item weight let inventory describe fixed-weight items
+-- such as "pair of 50 lb dumbbells". A NULL unit_weight means the weight is
+-- unspecified and compatibility checks assume the load is available.
+ALTER TABLE equipment ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1 CHECK(quantity >= 1);
+ALTER TABLE equipment ADD COLUMN unit_weight REAL CHECK(unit_weight IS NULL OR unit_weight > 0);
+
+-- +goose Down
+
+ALTER TABLE equipment DROP COLUMN unit_weight;
+ALTER TABLE equipment DROP COLUMN quantity;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- A superset accessory is performed back-to-back with the accessory listed
+-- before it on the same day.
+ALTER TABLE accessory_plans ADD COLUMN superset INTEGER NOT NULL DEFAULT 0 CHECK(superset IN (0, 1));
+
+-- +goose Down
+
+ALTER TABLE accessory_plans DROP COLUMN superset;
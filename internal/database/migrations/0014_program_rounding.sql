@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- Target weights computed from percentage × training max are rounded to a
+-- loadable increment using the template's rounding mode.
+ALTER TABLE program_templates ADD COLUMN rounding_increment REAL NOT NULL DEFAULT 5 CHECK(rounding_increment > 0);
+ALTER TABLE program_templates ADD COLUMN rounding_mode TEXT NOT NULL DEFAULT 'nearest' CHECK(rounding_mode IN ('nearest', 'down', 'up'));
+
+-- +goose Down
+
+ALTER TABLE program_templates DROP COLUMN rounding_mode;
+ALTER TABLE program_templates DROP COLUMN rounding_increment;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- A conditional progression rule only suggests a TM bump when the athlete's
+-- last AMRAP set for the exercise reached the threshold rep count.
+-- NULL condition = unconditional bump every cycle.
+ALTER TABLE progression_rules ADD COLUMN condition TEXT CHECK(condition IN ('amrap_min_reps'));
+ALTER TABLE progression_rules ADD COLUMN threshold INTEGER CHECK(threshold IS NULL OR threshold > 0);
+
+-- +goose Down
+
+ALTER TABLE progression_rules DROP COLUMN threshold;
+ALTER TABLE progression_rules DROP COLUMN condition;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Weekday bitmask of the athlete's scheduled training days for this
+-- assignment: bit 0 = Monday … bit 6 = Sunday. NULL = any day.
+ALTER TABLE athlete_programs ADD COLUMN training_days INTEGER CHECK(training_days IS NULL OR training_days BETWEEN 1 AND 127);
+
+-- +goose Down
+
+ALTER TABLE athlete_programs DROP COLUMN training_days;
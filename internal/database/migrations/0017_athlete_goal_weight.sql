@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Optional target body weight for athletes who are cutting or bulking.
+ALTER TABLE athletes ADD COLUMN goal_weight REAL CHECK(goal_weight IS NULL OR goal_weight > 0);
+
+-- +goose Down
+
+ALTER TABLE athletes DROP COLUMN goal_weight;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Body weights are stored in canonical lbs; unit records what the athlete
+-- originally logged so the entry can be shown back in that unit.
+ALTER TABLE body_weights ADD COLUMN unit TEXT NOT NULL DEFAULT 'lbs' CHECK(unit IN ('lbs', 'kg'));
+
+-- +goose Down
+
+ALTER TABLE body_weights DROP COLUMN unit;
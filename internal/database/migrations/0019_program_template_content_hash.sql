@@ -0,0 +1,52 @@
+-- +goose Up
+
+-- content_hash fingerprints an imported template's name and prescribed sets
+-- so re-importing the same program maps onto it automatically. It is NULL for
+-- templates built in the UI and is cleared whenever the template's sets or
+-- shape change, so a stale hash never matches edited content.
+ALTER TABLE program_templates ADD COLUMN content_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_program_templates_content_hash ON program_templates(content_hash);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_prescribed_sets_insert_clear_hash
+AFTER INSERT ON prescribed_sets FOR EACH ROW
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = NEW.template_id AND content_hash IS NOT NULL;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_prescribed_sets_update_clear_hash
+AFTER UPDATE OF exercise_id, week, day, set_number, reps, rep_max, percentage, absolute_weight, target_rpe, rep_type, notes ON prescribed_sets FOR EACH ROW
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = NEW.template_id AND content_hash IS NOT NULL;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_prescribed_sets_delete_clear_hash
+AFTER DELETE ON prescribed_sets FOR EACH ROW
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = OLD.template_id AND content_hash IS NOT NULL;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_program_templates_clear_hash
+AFTER UPDATE OF name, num_weeks, num_days, is_loop ON program_templates FOR EACH ROW
+WHEN OLD.content_hash IS NOT NULL
+    AND (OLD.name IS NOT NEW.name OR OLD.num_weeks IS NOT NEW.num_weeks OR OLD.num_days IS NOT NEW.num_days OR OLD.is_loop IS NOT NEW.is_loop)
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TRIGGER IF EXISTS trigger_program_templates_clear_hash;
+DROP TRIGGER IF EXISTS trigger_prescribed_sets_delete_clear_hash;
+DROP TRIGGER IF EXISTS trigger_prescribed_sets_update_clear_hash;
+DROP TRIGGER IF EXISTS trigger_prescribed_sets_insert_clear_hash;
+DROP INDEX IF EXISTS idx_program_templates_content_hash;
+ALTER TABLE program_templates DROP COLUMN content_hash;
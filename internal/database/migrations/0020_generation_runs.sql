@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- generation_runs tracks AI Coach program generations that run in the
+-- background. The request and result are stored as JSON so a coach can leave
+-- the page and come back to the preview once the run completes.
+CREATE TABLE IF NOT EXISTS generation_runs (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    athlete_id   INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    user_id      INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status       TEXT    NOT NULL DEFAULT 'queued' CHECK(status IN ('queued', 'running', 'complete', 'failed')),
+    request_json TEXT    NOT NULL,
+    result_json  TEXT,
+    error        TEXT,
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at   DATETIME,
+    finished_at  DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_generation_runs_athlete_status ON generation_runs(athlete_id, status);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_generation_runs_athlete_status;
+DROP TABLE IF EXISTS generation_runs;
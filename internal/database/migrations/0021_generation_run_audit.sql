@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Keep what was sent to and returned by the LLM for each generation run so a
+-- coach can trace why a program was produced.
+ALTER TABLE generation_runs ADD COLUMN context_json TEXT;
+ALTER TABLE generation_runs ADD COLUMN model TEXT;
+ALTER TABLE generation_runs ADD COLUMN tokens_used INTEGER;
+
+-- +goose Down
+
+ALTER TABLE generation_runs DROP COLUMN tokens_used;
+ALTER TABLE generation_runs DROP COLUMN model;
+ALTER TABLE generation_runs DROP COLUMN context_json;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- generation_usage is an append-only ledger of AI Coach token spend. It is
+-- kept separate from generation_runs so deleting an athlete (which cascades
+-- to their runs) does not reset the monthly budget.
+CREATE TABLE IF NOT EXISTS generation_usage (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    generation_run_id INTEGER REFERENCES generation_runs(id) ON DELETE SET NULL,
+    user_id           INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    model             TEXT    NOT NULL DEFAULT '',
+    tokens_used       INTEGER NOT NULL DEFAULT 0 CHECK(tokens_used >= 0),
+    cost_usd          REAL    NOT NULL DEFAULT 0,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_generation_usage_created_at ON generation_usage(created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_generation_usage_created_at;
+DROP TABLE IF EXISTS generation_usage;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- prompt_templates holds coach-edited audience guardrails for the AI Coach
+-- system prompt. A row with an empty tier applies to every athlete in the
+-- audience; a row for a specific tier overrides it. Audiences or tiers with
+-- no row use the built-in rules.
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    audience     TEXT    NOT NULL CHECK(audience IN ('youth', 'adult')),
+    tier         TEXT    NOT NULL DEFAULT '' CHECK(tier IN ('', 'foundational', 'intermediate', 'sport_performance')),
+    instructions TEXT    NOT NULL CHECK(length(trim(instructions)) > 0),
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(audience, tier),
+    CHECK(audience = 'youth' OR tier = '')
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS prompt_templates;
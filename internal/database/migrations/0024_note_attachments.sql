@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- note_attachments are images (e.g. form-check photos) attached to a journal
+-- note. Files live on disk under a random name; the row records the name
+-- and the sniffed content type.
+CREATE TABLE IF NOT EXISTS note_attachments (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    note_id      INTEGER NOT NULL REFERENCES athlete_notes(id) ON DELETE CASCADE,
+    filename     TEXT    NOT NULL UNIQUE,
+    content_type TEXT    NOT NULL,
+    size_bytes   INTEGER NOT NULL CHECK(size_bytes >= 0),
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_attachments_note ON note_attachments(note_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_note_attachments_note;
+DROP TABLE IF EXISTS note_attachments;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- acknowledged_at records when the athlete confirmed they read a note, so
+-- coaches can see whether a pinned cue landed. NULL means not yet seen.
+ALTER TABLE athlete_notes ADD COLUMN acknowledged_at DATETIME;
+
+-- +goose Down
+
+ALTER TABLE athlete_notes DROP COLUMN acknowledged_at;
@@ -0,0 +1,86 @@
+-- +goose Up
+
+-- notes_fts is a full-text index over coach/athlete notes and workout notes.
+-- kind is 'note' (athlete_notes) or 'workout' (workouts) and source_id is the
+-- row in that table. Triggers keep the index in sync with both tables.
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(
+    content,
+    kind UNINDEXED,
+    source_id UNINDEXED,
+    athlete_id UNINDEXED,
+    tokenize = 'porter unicode61'
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_athlete_notes_fts_insert
+AFTER INSERT ON athlete_notes FOR EACH ROW
+BEGIN
+    INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+    VALUES (NEW.content, 'note', NEW.id, NEW.athlete_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_athlete_notes_fts_update
+AFTER UPDATE OF content ON athlete_notes FOR EACH ROW
+BEGIN
+    DELETE FROM notes_fts WHERE kind = 'note' AND source_id = OLD.id;
+    INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+    VALUES (NEW.content, 'note', NEW.id, NEW.athlete_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_athlete_notes_fts_delete
+AFTER DELETE ON athlete_notes FOR EACH ROW
+BEGIN
+    DELETE FROM notes_fts WHERE kind = 'note' AND source_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workouts_fts_insert
+AFTER INSERT ON workouts FOR EACH ROW
+WHEN NEW.notes IS NOT NULL AND NEW.notes != ''
+BEGIN
+    INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+    VALUES (NEW.notes, 'workout', NEW.id, NEW.athlete_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workouts_fts_update
+AFTER UPDATE OF notes ON workouts FOR EACH ROW
+BEGIN
+    DELETE FROM notes_fts WHERE kind = 'workout' AND source_id = OLD.id;
+    INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+    SELECT NEW.notes, 'workout', NEW.id, NEW.athlete_id
+    WHERE NEW.notes IS NOT NULL AND NEW.notes != '';
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workouts_fts_delete
+AFTER DELETE ON workouts FOR EACH ROW
+BEGIN
+    DELETE FROM notes_fts WHERE kind = 'workout' AND source_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- Index rows that existed before the triggers.
+INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+SELECT content, 'note', id, athlete_id FROM athlete_notes;
+
+INSERT INTO notes_fts (content, kind, source_id, athlete_id)
+SELECT notes, 'workout', id, athlete_id FROM workouts
+WHERE notes IS NOT NULL AND notes != '';
+
+-- +goose Down
+
+DROP TRIGGER IF EXISTS trigger_workouts_fts_delete;
+DROP TRIGGER IF EXISTS trigger_workouts_fts_update;
+DROP TRIGGER IF EXISTS trigger_workouts_fts_insert;
+DROP TRIGGER IF EXISTS trigger_athlete_notes_fts_delete;
+DROP TRIGGER IF EXISTS trigger_athlete_notes_fts_update;
+DROP TRIGGER IF EXISTS trigger_athlete_notes_fts_insert;
+DROP TABLE IF EXISTS notes_fts;
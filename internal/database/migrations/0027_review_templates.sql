@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- review_templates are a coach's canned workout feedback ("great bar speed,
+-- watch depth") that can be dropped into a review's notes.
+CREATE TABLE IF NOT EXISTS review_templates (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    coach_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT    NOT NULL CHECK(trim(name) != ''),
+    content    TEXT    NOT NULL CHECK(trim(content) != ''),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(coach_id, name)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS review_templates;
@@ -0,0 +1,74 @@
+-- +goose Up
+
+-- Review statuses become approved, needs_changes, or flagged_injury.
+-- SQLite can't alter a CHECK constraint, so the table is rebuilt; existing
+-- needs_work reviews become needs_changes.
+CREATE TABLE workout_reviews_new (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    workout_id  INTEGER NOT NULL UNIQUE REFERENCES workouts(id) ON DELETE CASCADE,
+    coach_id    INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status      TEXT    NOT NULL CHECK(status IN ('approved', 'needs_changes', 'flagged_injury')),
+    notes       TEXT,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO workout_reviews_new (id, workout_id, coach_id, status, notes, created_at, updated_at)
+SELECT id, workout_id, coach_id,
+       CASE status WHEN 'needs_work' THEN 'needs_changes' ELSE status END,
+       notes, created_at, updated_at
+FROM workout_reviews;
+
+DROP TABLE workout_reviews;
+ALTER TABLE workout_reviews_new RENAME TO workout_reviews;
+
+CREATE INDEX IF NOT EXISTS idx_workout_reviews_workout_id
+    ON workout_reviews(workout_id);
+
+CREATE INDEX IF NOT EXISTS idx_workout_reviews_status
+    ON workout_reviews(status);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workout_reviews_updated_at
+AFTER UPDATE ON workout_reviews FOR EACH ROW
+WHEN OLD.updated_at = NEW.updated_at
+BEGIN
+    UPDATE workout_reviews SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+CREATE TABLE workout_reviews_old (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    workout_id  INTEGER NOT NULL UNIQUE REFERENCES workouts(id) ON DELETE CASCADE,
+    coach_id    INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status      TEXT    NOT NULL CHECK(status IN ('approved', 'needs_work')),
+    notes       TEXT,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO workout_reviews_old (id, workout_id, coach_id, status, notes, created_at, updated_at)
+SELECT id, workout_id, coach_id,
+       CASE status WHEN 'approved' THEN 'approved' ELSE 'needs_work' END,
+       notes, created_at, updated_at
+FROM workout_reviews;
+
+DROP TABLE workout_reviews;
+ALTER TABLE workout_reviews_old RENAME TO workout_reviews;
+
+CREATE INDEX IF NOT EXISTS idx_workout_reviews_workout_id
+    ON workout_reviews(workout_id);
+
+CREATE INDEX IF NOT EXISTS idx_workout_reviews_status
+    ON workout_reviews(status);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workout_reviews_updated_at
+AFTER UPDATE ON workout_reviews FOR EACH ROW
+WHEN OLD.updated_at = NEW.updated_at
+BEGIN
+    UPDATE workout_reviews SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- completed_at records when an athlete marked a workout complete. Only
+-- completed workouts without a review appear in the coach's pending-review
+-- queue, so workouts still being logged stay out of it.
+ALTER TABLE workouts ADD COLUMN completed_at DATETIME;
+
+-- Existing workouts are treated as complete so the current review queue is
+-- preserved. The updated_at trigger is dropped for the backfill so it does
+-- not bump every row's updated_at.
+DROP TRIGGER IF EXISTS trigger_workouts_updated_at;
+
+UPDATE workouts SET completed_at = updated_at;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_workouts_updated_at
+AFTER UPDATE ON workouts FOR EACH ROW
+WHEN OLD.updated_at = NEW.updated_at
+BEGIN
+    UPDATE workouts SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+ALTER TABLE workouts DROP COLUMN completed_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- digest_frequency bundles a user's external notifications into one email
+-- per day or week instead of one email per event. last_digest_at records
+-- when the last digest went out so each one only covers newer notifications.
+ALTER TABLE user_preferences ADD COLUMN digest_frequency TEXT NOT NULL DEFAULT 'off'
+    CHECK(digest_frequency IN ('off', 'daily', 'weekly'));
+ALTER TABLE user_preferences ADD COLUMN last_digest_at DATETIME;
+
+-- +goose Down
+
+ALTER TABLE user_preferences DROP COLUMN last_digest_at;
+ALTER TABLE user_preferences DROP COLUMN digest_frequency;
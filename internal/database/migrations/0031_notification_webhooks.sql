@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- notification_webhooks holds a user's outbound webhook: external
+-- notifications are POSTed to url as JSON. secret is optional and, like
+-- sensitive app settings, stored encrypted with an "enc:" prefix; when set,
+-- each request carries an HMAC-SHA256 signature of the body.
+CREATE TABLE IF NOT EXISTS notification_webhooks (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    url        TEXT    NOT NULL CHECK(url LIKE 'http://%' OR url LIKE 'https://%'),
+    secret     TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_notification_webhooks_updated_at
+AFTER UPDATE ON notification_webhooks FOR EACH ROW
+WHEN OLD.updated_at = NEW.updated_at
+BEGIN
+    UPDATE notification_webhooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TRIGGER IF EXISTS trigger_notification_webhooks_updated_at;
+DROP TABLE IF EXISTS notification_webhooks;
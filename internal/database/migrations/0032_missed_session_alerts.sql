@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- missed_session_alerts records each scheduled training day an athlete's
+-- coach has been notified about missing, so the daily check never reports
+-- the same athlete and date twice.
+CREATE TABLE IF NOT EXISTS missed_session_alerts (
+    athlete_id INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    date       DATE    NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (athlete_id, date)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS missed_session_alerts;
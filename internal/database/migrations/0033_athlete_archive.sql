@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Archived athletes have stopped training. They are hidden from athlete
+-- lists, the roster and scheduled checks but keep their full history.
+ALTER TABLE athletes ADD COLUMN archived INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1));
+
+-- +goose Down
+
+ALTER TABLE athletes DROP COLUMN archived;
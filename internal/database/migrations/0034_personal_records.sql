@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- personal_records logs sets that beat an athlete's prior best for an
+-- exercise. Rows are derived from workout_sets and rebuilt whenever a
+-- workout's sets change, so each set appears at most once.
+CREATE TABLE IF NOT EXISTS personal_records (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    athlete_id     INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    exercise_id    INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    workout_id     INTEGER NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    set_id         INTEGER NOT NULL UNIQUE REFERENCES workout_sets(id) ON DELETE CASCADE,
+    reps           INTEGER NOT NULL,
+    weight         REAL    NOT NULL,
+    estimated_1rm  REAL    NOT NULL,
+    previous_best  REAL,
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_athlete
+    ON personal_records(athlete_id, exercise_id);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_workout
+    ON personal_records(workout_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS personal_records;
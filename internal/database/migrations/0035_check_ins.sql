@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- check_ins record attendance for sessions with nothing to log, such as
+-- mobility or recovery work. A day with a check-in counts as trained for
+-- adherence even without a workout.
+CREATE TABLE IF NOT EXISTS check_ins (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    athlete_id  INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    date        DATE    NOT NULL,
+    notes       TEXT,
+    created_by  INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(athlete_id, date)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS check_ins;
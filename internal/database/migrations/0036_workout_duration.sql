@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- started_at is set while a session timer is running; stopping the timer
+-- records the elapsed time in duration_minutes and clears it. Coaches can
+-- also enter duration_minutes directly for sessions that weren't timed.
+ALTER TABLE workouts ADD COLUMN started_at DATETIME;
+ALTER TABLE workouts ADD COLUMN duration_minutes INTEGER CHECK(duration_minutes > 0);
+
+-- +goose Down
+
+ALTER TABLE workouts DROP COLUMN duration_minutes;
+ALTER TABLE workouts DROP COLUMN started_at;
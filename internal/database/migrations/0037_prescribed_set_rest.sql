@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- rest_seconds overrides the exercise's rest time for a single prescribed
+-- set, e.g. a longer rest after a heavy top set. NULL uses the exercise's
+-- rest_seconds, then the app default.
+ALTER TABLE prescribed_sets ADD COLUMN rest_seconds INTEGER CHECK(rest_seconds >= 0);
+
+-- +goose Down
+
+ALTER TABLE prescribed_sets DROP COLUMN rest_seconds;
@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- user_totp holds a user's time-based one-time password (RFC 6238) secret
+-- for two-factor password logins. secret is stored encrypted with an "enc:"
+-- prefix, like sensitive app settings. A row with enabled = 0 is a pending
+-- enrollment that has not yet been confirmed with a code. last_step is the
+-- most recent 30-second time step accepted, so a code cannot be replayed.
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id    INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret     TEXT    NOT NULL,
+    enabled    INTEGER NOT NULL DEFAULT 0 CHECK(enabled IN (0, 1)),
+    last_step  INTEGER NOT NULL DEFAULT 0,
+    enabled_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- totp_recovery_codes holds single-use codes that stand in for a TOTP code
+-- when the user's authenticator is unavailable. Only a SHA-256 hash of each
+-- code is stored.
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash  TEXT    NOT NULL,
+    used_at    DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_totp_recovery_codes_user_id ON totp_recovery_codes(user_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_totp_recovery_codes_user_id;
+DROP TABLE IF EXISTS totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
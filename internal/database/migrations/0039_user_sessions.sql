@@ -0,0 +1,23 @@
+-- +goose Up
+
+-- user_sessions tracks which user each scs session (sessions.token) belongs
+-- to, with the client it was last used from, so users can review and revoke
+-- their active sessions. The session data itself stays in sessions; rows
+-- whose session has expired or been destroyed are ignored and pruned by
+-- maintenance.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    token        TEXT    NOT NULL UNIQUE,
+    user_id      INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address   TEXT,
+    user_agent   TEXT,
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_user_sessions_user_id;
+DROP TABLE IF EXISTS user_sessions;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- last_used_at records the most recent sign-in with each passkey so users
+-- can spot and remove devices they no longer use. NULL until first used.
+ALTER TABLE webauthn_credentials ADD COLUMN last_used_at DATETIME;
+
+-- +goose Down
+
+ALTER TABLE webauthn_credentials DROP COLUMN last_used_at;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- athlete_viewers grants read-only access to an athlete's profile, e.g. for
+-- a parent following a young athlete. Viewers can see everything the athlete
+-- can but can't log workouts or change anything.
+CREATE TABLE IF NOT EXISTS athlete_viewers (
+    athlete_id  INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (athlete_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_athlete_viewers_user_id ON athlete_viewers(user_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_athlete_viewers_user_id;
+DROP TABLE IF EXISTS athlete_viewers;
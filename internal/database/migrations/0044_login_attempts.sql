@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- login_attempts counts recent failed password logins per username so a
+-- single account can't be guessed at slowly from many addresses. Rows are
+-- keyed by the username as typed, not by user ID, so unknown usernames are
+-- throttled exactly like real ones and lockouts don't reveal which exist.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    username        TEXT     PRIMARY KEY COLLATE NOCASE,
+    failures        INTEGER  NOT NULL DEFAULT 0,
+    last_failed_at  DATETIME NOT NULL,
+    locked_until    DATETIME
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS login_attempts;
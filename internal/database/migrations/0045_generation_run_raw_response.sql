@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Keep the LLM's unprocessed output for every run, including ones that fail
+-- because the response had no usable program, so a coach can see what came
+-- back.
+ALTER TABLE generation_runs ADD COLUMN raw_response TEXT;
+
+-- +goose Down
+
+ALTER TABLE generation_runs DROP COLUMN raw_response;
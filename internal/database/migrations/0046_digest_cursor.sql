@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- A digest is capped at 50 notifications, so last_digest_at now records the
+-- created_at of the last notification a digest included rather than when it
+-- was sent. last_digest_id breaks ties between notifications created in the
+-- same second, so a backlog carries over into the next digest.
+ALTER TABLE user_preferences ADD COLUMN last_digest_id INTEGER;
+
+-- +goose Down
+
+ALTER TABLE user_preferences DROP COLUMN last_digest_id;
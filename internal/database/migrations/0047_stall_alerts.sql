@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- stall_alerts records each stall an athlete's coach has been told about, so
+-- it is reported once however the coach receives notifications and even if
+-- the in-app notification is deleted.
+CREATE TABLE IF NOT EXISTS stall_alerts (
+    athlete_id  INTEGER NOT NULL REFERENCES athletes(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    since       DATE    NOT NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (athlete_id, exercise_id, since)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS stall_alerts;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- Prescribed set rest times are part of a template's content hash, so
+-- changing one must clear the stored hash too.
+DROP TRIGGER IF EXISTS trigger_prescribed_sets_update_clear_hash;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_prescribed_sets_update_clear_hash
+AFTER UPDATE OF exercise_id, week, day, set_number, reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, rep_type, notes ON prescribed_sets FOR EACH ROW
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = NEW.template_id AND content_hash IS NOT NULL;
+END;
+-- +goose StatementEnd
+
+-- Hashes stored before rest times were included can never match again.
+UPDATE program_templates SET content_hash = NULL WHERE content_hash IS NOT NULL;
+
+-- +goose Down
+
+DROP TRIGGER IF EXISTS trigger_prescribed_sets_update_clear_hash;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_prescribed_sets_update_clear_hash
+AFTER UPDATE OF exercise_id, week, day, set_number, reps, rep_max, percentage, absolute_weight, target_rpe, rep_type, notes ON prescribed_sets FOR EACH ROW
+BEGIN
+    UPDATE program_templates SET content_hash = NULL WHERE id = NEW.template_id AND content_hash IS NOT NULL;
+END;
+-- +goose StatementEnd
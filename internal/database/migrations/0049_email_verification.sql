@@ -0,0 +1,37 @@
+-- +goose Up
+
+-- Password reset links are only emailed to verified addresses. Changing an
+-- address clears its verification.
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS trigger_users_email_clear_verified
+AFTER UPDATE OF email ON users FOR EACH ROW
+WHEN NEW.email IS NOT OLD.email
+BEGIN
+    UPDATE users SET email_verified_at = NULL WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- email_verification_tokens holds single-use links emailed to confirm an
+-- address. The address is stored with the token so a link sent before the
+-- address changed can't verify the new one. Only a SHA-256 hash of the token
+-- is stored.
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email       TEXT    NOT NULL,
+    token_hash  TEXT    NOT NULL UNIQUE,
+    expires_at  DATETIME NOT NULL,
+    used_at     DATETIME,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_email_verification_tokens_user_id;
+DROP TABLE IF EXISTS email_verification_tokens;
+DROP TRIGGER IF EXISTS trigger_users_email_clear_verified;
+ALTER TABLE users DROP COLUMN email_verified_at;
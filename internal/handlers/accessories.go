@@ -47,7 +47,7 @@ func (h *Accessories) List(w http.ResponseWriter, r *http.Request) {
 		log.Printf("handlers: max accessory day for athlete %d: %v", athleteID, err)
 	}
 
-	exercises, err := models.ListExercises(h.DB, "")
+	exercises, err := models.ListExercises(h.DB, models.ExerciseFilter{})
 	if err != nil {
 		log.Printf("handlers: list exercises: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -124,10 +124,12 @@ func (h *Accessories) Create(w http.ResponseWriter, r *http.Request) {
 	targetRepMin, _ := strconv.Atoi(r.FormValue("target_rep_min"))
 	targetRepMax, _ := strconv.Atoi(r.FormValue("target_rep_max"))
 	targetWeight, _ := strconv.ParseFloat(r.FormValue("target_weight"), 64)
+	targetWeight = weightInputToLbs(r, targetWeight)
 	notes := r.FormValue("notes")
 	sortOrder, _ := strconv.Atoi(r.FormValue("sort_order"))
+	superset := r.FormValue("superset") == "1"
 
-	_, err = models.CreateAccessoryPlan(h.DB, athleteID, day, exerciseID, targetSets, targetRepMin, targetRepMax, targetWeight, notes, sortOrder)
+	_, err = models.CreateAccessoryPlan(h.DB, athleteID, day, exerciseID, targetSets, targetRepMin, targetRepMax, targetWeight, notes, sortOrder, superset)
 	if err != nil {
 		log.Printf("handlers: create accessory plan: %v", err)
 		accessoryRedirectWithError(w, r, athleteID, "Could not add accessory — it may already exist for that day")
@@ -177,10 +179,12 @@ func (h *Accessories) Update(w http.ResponseWriter, r *http.Request) {
 	targetRepMin, _ := strconv.Atoi(r.FormValue("target_rep_min"))
 	targetRepMax, _ := strconv.Atoi(r.FormValue("target_rep_max"))
 	targetWeight, _ := strconv.ParseFloat(r.FormValue("target_weight"), 64)
+	targetWeight = weightInputToLbs(r, targetWeight)
 	notes := r.FormValue("notes")
 	sortOrder, _ := strconv.Atoi(r.FormValue("sort_order"))
+	superset := r.FormValue("superset") == "1"
 
-	err = models.UpdateAccessoryPlan(h.DB, planID, targetSets, targetRepMin, targetRepMax, targetWeight, notes, sortOrder)
+	err = models.UpdateAccessoryPlan(h.DB, planID, targetSets, targetRepMin, targetRepMax, targetWeight, notes, sortOrder, superset)
 	if errors.Is(err, models.ErrNotFound) {
 		http.Error(w, "Plan not found", http.StatusNotFound)
 		return
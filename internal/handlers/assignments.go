@@ -31,7 +31,7 @@ func (h *Assignments) Assign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -83,7 +83,7 @@ func (h *Assignments) Deactivate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -122,7 +122,7 @@ func (h *Assignments) AssignForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -171,7 +171,7 @@ func (h *Assignments) Reactivate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
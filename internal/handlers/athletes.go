@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -21,7 +22,14 @@ type Athletes struct {
 
 // List renders the athlete list page. Coaches see their own athletes;
 // admins see all athletes; non-coaches are redirected to their own athlete profile.
+// Archived athletes are hidden unless ?archived=1. ?view=roster renders the
+// roster view instead.
 func (h *Athletes) List(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("view") == "roster" {
+		h.Roster(w, r)
+		return
+	}
+
 	user := middleware.UserFromContext(r.Context())
 	if !user.IsCoach && !user.IsAdmin {
 		if user.AthleteID.Valid {
@@ -33,7 +41,8 @@ func (h *Athletes) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	coachFilter := middleware.CoachAthleteFilter(user)
-	athletes, err := models.ListAthleteCards(h.DB, coachFilter)
+	showArchived := r.URL.Query().Get("archived") == "1"
+	athletes, err := models.ListAthleteCards(h.DB, coachFilter, showArchived)
 	if err != nil {
 		log.Printf("handlers: list athlete cards: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -41,7 +50,8 @@ func (h *Athletes) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]any{
-		"Athletes": athletes,
+		"Athletes":     athletes,
+		"ShowArchived": showArchived,
 	}
 	if err := h.Templates.Render(w, r, "athletes_list.html", data); err != nil {
 		log.Printf("handlers: athletes list template: %v", err)
@@ -49,6 +59,34 @@ func (h *Athletes) List(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Roster renders the coach roster: each athlete's last workout, program,
+// pending reviews and 7-day adherence, with athletes needing attention
+// first. Coach or admin only.
+// GET /athletes?view=roster
+func (h *Athletes) Roster(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	roster, err := models.RosterSummary(h.DB, middleware.CoachAthleteFilter(user))
+	if err != nil {
+		log.Printf("handlers: roster summary: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"Roster":        roster,
+		"AdherenceDays": models.RosterAdherenceDays,
+	}
+	if err := h.Templates.Render(w, r, "athletes_roster.html", data); err != nil {
+		log.Printf("handlers: athletes roster template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
 // NewForm renders the new athlete form. Coach or admin only.
 func (h *Athletes) NewForm(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -91,6 +129,27 @@ func (h *Athletes) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	barWeight, ok := parseOptionalWeight(r.FormValue("bar_weight"))
+	if !ok {
+		http.Error(w, "Invalid bar weight", http.StatusBadRequest)
+		return
+	}
+	barWeight = weightInputToLbs(r, barWeight)
+	goalWeight, ok := parseOptionalWeight(r.FormValue("goal_weight"))
+	if !ok {
+		http.Error(w, "Invalid goal weight", http.StatusBadRequest)
+		return
+	}
+	goalWeight = weightInputToLbs(r, goalWeight)
+	plates, err := models.ParsePlates(r.FormValue("plates"))
+	if err != nil {
+		http.Error(w, "Invalid plate weights", http.StatusBadRequest)
+		return
+	}
+	for i, p := range plates {
+		plates[i] = weightInputToLbs(r, p)
+	}
+
 	trackBW := r.FormValue("track_body_weight") != "0"
 	athlete, err := models.CreateAthlete(h.DB, name, r.FormValue("tier"), r.FormValue("notes"), r.FormValue("goal"), r.FormValue("date_of_birth"), r.FormValue("grade"), r.FormValue("gender"), sql.NullInt64{Int64: user.ID, Valid: true}, trackBW)
 	if err != nil {
@@ -99,6 +158,22 @@ func (h *Athletes) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if barWeight > 0 {
+		if err := models.SetAthleteBarWeight(h.DB, athlete.ID, barWeight); err != nil {
+			log.Printf("handlers: set bar weight for athlete %d: %v", athlete.ID, err)
+		}
+	}
+	if len(plates) > 0 {
+		if err := models.SetAthletePlates(h.DB, athlete.ID, plates); err != nil {
+			log.Printf("handlers: set plates for athlete %d: %v", athlete.ID, err)
+		}
+	}
+	if goalWeight > 0 {
+		if err := models.SetAthleteGoalWeight(h.DB, athlete.ID, goalWeight); err != nil {
+			log.Printf("handlers: set goal weight for athlete %d: %v", athlete.ID, err)
+		}
+	}
+
 	// Record initial goal in history if one was provided.
 	if goal := r.FormValue("goal"); goal != "" {
 		if _, err := models.RecordGoalChange(h.DB, athlete.ID, goal, "", user.ID, "", ""); err != nil {
@@ -137,20 +212,11 @@ func (h *Athletes) Show(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check access: admins see all, coaches see their own athletes, non-coaches see own profile.
-	if !user.IsAdmin {
-		if user.IsCoach {
-			// Coach can view own linked athlete profile or athletes they coach.
-			ownProfile := user.AthleteID.Valid && user.AthleteID.Int64 == id
-			ownsAthlete := athlete.CoachID.Valid && athlete.CoachID.Int64 == user.ID
-			if !ownProfile && !ownsAthlete {
-				h.Templates.Forbidden(w, r)
-				return
-			}
-		} else if !user.AthleteID.Valid || user.AthleteID.Int64 != id {
-			h.Templates.Forbidden(w, r)
-			return
-		}
+	// Check access: admins see all, coaches see their own athletes, non-coaches
+	// see their own profile and athletes they've been granted viewer access to.
+	if !middleware.CanAccessAthlete(h.DB, user, id) {
+		h.Templates.Forbidden(w, r)
+		return
 	}
 
 	data, err := h.loadAthleteShowData(user, athlete)
@@ -166,6 +232,89 @@ func (h *Athletes) Show(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Records renders an athlete's personal record history.
+func (h *Athletes) Records(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanAccessAthlete(h.DB, user, id) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for records: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	records, err := models.ListPersonalRecords(h.DB, id)
+	if err != nil {
+		log.Printf("handlers: list personal records for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"Athlete": athlete,
+		"Records": records,
+	}
+	if err := h.Templates.Render(w, r, "athlete_records.html", data); err != nil {
+		log.Printf("handlers: athlete records template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// WorkloadJSON returns an athlete's weekly workload (sets, reps and tonnage
+// across all exercises, with week-over-week spike flags) as JSON. The
+// optional weeks query parameter controls the window (default 12).
+func (h *Athletes) WorkloadJSON(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanAccessAthlete(h.DB, user, id) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	weeks := 12
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 104 {
+			http.Error(w, "weeks must be between 1 and 104", http.StatusBadRequest)
+			return
+		}
+		weeks = n
+	}
+
+	points, err := models.WeeklyWorkload(h.DB, id, weeks)
+	if err != nil {
+		log.Printf("handlers: weekly workload for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(points); err != nil {
+		log.Printf("handlers: encode weekly workload JSON: %v", err)
+	}
+}
+
 // loadAthleteShowData fetches all data needed for the athlete detail page.
 // Fatal queries return errors; non-fatal queries log and continue with nil/zero values.
 func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlete) (map[string]any, error) {
@@ -188,7 +337,7 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 	}
 
 	// Load recent workouts for the athlete detail page.
-	recentPage, err := models.ListWorkouts(h.DB, id, 0)
+	recentPage, err := models.ListWorkouts(h.DB, id, 0, models.WorkoutPageSize)
 	if err != nil {
 		return nil, fmt.Errorf("list workouts: %w", err)
 	}
@@ -232,6 +381,13 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 		// Non-fatal — continue without heatmap data.
 	}
 
+	// Load weekly workload for the last 12 weeks.
+	workload, err := models.WeeklyWorkload(h.DB, id, 12)
+	if err != nil {
+		log.Printf("handlers: weekly workload for athlete %d: %v", id, err)
+		// Non-fatal — continue without the workload chart.
+	}
+
 	// Load active program and today's prescription.
 	activeProgram, err := models.GetActiveProgram(h.DB, id)
 	if err != nil {
@@ -274,7 +430,7 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 			log.Printf("handlers: program equipment compat for athlete %d: %v", id, err)
 		} else if equipCompat != nil && !equipCompat.Ready {
 			for _, ex := range equipCompat.Exercises {
-				if !ex.HasRequired {
+				if ex.Blocked() {
 					missingEquip = append(missingEquip, ex)
 				}
 			}
@@ -290,6 +446,21 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 		}
 	}
 
+	// Load standing exercise substitutions and the catalog for the add form
+	// (coach/admin only for managed athletes).
+	var substitutions []*models.ExerciseSubstitution
+	var substitutionExercises []*models.Exercise
+	if middleware.CanManageAthlete(user, athlete) {
+		substitutions, err = models.ListSubstitutions(h.DB, id)
+		if err != nil {
+			log.Printf("handlers: list substitutions for athlete %d: %v", id, err)
+		}
+		substitutionExercises, err = models.ListExercises(h.DB, models.ExerciseFilter{})
+		if err != nil {
+			log.Printf("handlers: list exercises for substitutions for athlete %d: %v", id, err)
+		}
+	}
+
 	// Load featured lifts (current TM, personal best, estimated 1RM).
 	featuredLifts, err := models.ListFeaturedLifts(h.DB, id)
 	if err != nil {
@@ -297,11 +468,46 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 		// Non-fatal — continue without featured data.
 	}
 
+	// Flag stalled lifts (flat TM plus missed AMRAP targets).
+	stalls, err := models.DetectStalls(h.DB, id)
+	if err != nil {
+		log.Printf("handlers: detect stalls for athlete %d: %v", id, err)
+		// Non-fatal — continue without stall flags.
+	}
+
+	// Goal timeline, newest first.
+	goalHistory, err := models.ListGoalHistory(h.DB, id)
+	if err != nil {
+		log.Printf("handlers: list goal history for athlete %d: %v", id, err)
+		// Non-fatal — continue without the goal timeline.
+	}
+
 	// Check whether AI Coach is available (LLM provider configured).
 	aiCoachConfigured := models.IsAICoachConfigured(h.DB)
 
+	// Linked account the user can preview the app as, if any.
+	var viewAsUserID int64
+	if linkedIDs, err := models.ListAthleteUserIDs(h.DB, id); err != nil {
+		log.Printf("handlers: list linked users for athlete %d: %v", id, err)
+	} else {
+		for _, linkedID := range linkedIDs {
+			linked, err := models.GetUserByID(h.DB, linkedID)
+			if err == nil && middleware.CanImpersonate(h.DB, user, linked) {
+				viewAsUserID = linked.ID
+				break
+			}
+		}
+	}
+
+	// Age from date of birth; left out when missing or invalid.
+	var age any
+	if years, ok := models.AthleteAge(athlete.DateOfBirth.String, time.Now()); ok {
+		age = years
+	}
+
 	return map[string]any{
 		"Athlete":            athlete,
+		"Age":                age,
 		"Assignments":        assignments,
 		"TMByExercise":       tmByExercise,
 		"RecentWorkouts":     recentWorkouts,
@@ -309,16 +515,22 @@ func (h *Athletes) loadAthleteShowData(user *models.User, athlete *models.Athlet
 		"LatestWeight":       latestWeight,
 		"Streaks":            streaks,
 		"Heatmap":            heatmap,
+		"Workload":           models.WeeklyWorkloadChart(workload),
 		"ActiveProgram":      activeProgram,
 		"SupplementalPrograms": supplementalPrograms,
 		"Prescription":       prescription,
 		"ProgramTemplates":   programTemplates,
+		"Substitutions":      substitutions,
+		"SubstitutionExercises": substitutionExercises,
 		"FeaturedLifts":      featuredLifts,
 		"MissingTMs":         missingTMs,
 		"MissingEquip":       missingEquip,
+		"Stalls":             stalls,
+		"GoalHistory":        goalHistory,
 		"AICoachConfigured":  aiCoachConfigured,
 		"CanManage":          middleware.CanManageAthlete(user, athlete),
 		"IsOwnProfile":      user.AthleteID.Valid && user.AthleteID.Int64 == athlete.ID,
+		"ViewAsUserID":       viewAsUserID,
 		"TodayDate":          time.Now().Format("2006-01-02"),
 	}, nil
 }
@@ -349,9 +561,24 @@ func (h *Athletes) EditForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	viewers, err := models.ListAthleteViewers(h.DB, id)
+	if err != nil {
+		log.Printf("handlers: list viewers for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	candidates, err := models.ListViewerCandidates(h.DB, id)
+	if err != nil {
+		log.Printf("handlers: list viewer candidates for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]any{
-		"Athlete": athlete,
-		"Tiers":   tierOptions(),
+		"Athlete":          athlete,
+		"Tiers":            tierOptions(),
+		"Viewers":          viewers,
+		"ViewerCandidates": candidates,
 	}
 	if err := h.Templates.Render(w, r, "athlete_form.html", data); err != nil {
 		log.Printf("handlers: athlete edit form template: %v", err)
@@ -407,6 +634,27 @@ func (h *Athletes) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	barWeight, ok := parseOptionalWeight(r.FormValue("bar_weight"))
+	if !ok {
+		http.Error(w, "Invalid bar weight", http.StatusBadRequest)
+		return
+	}
+	barWeight = weightInputToLbs(r, barWeight)
+	goalWeight, ok := parseOptionalWeight(r.FormValue("goal_weight"))
+	if !ok {
+		http.Error(w, "Invalid goal weight", http.StatusBadRequest)
+		return
+	}
+	goalWeight = weightInputToLbs(r, goalWeight)
+	plates, err := models.ParsePlates(r.FormValue("plates"))
+	if err != nil {
+		http.Error(w, "Invalid plate weights", http.StatusBadRequest)
+		return
+	}
+	for i, p := range plates {
+		plates[i] = weightInputToLbs(r, p)
+	}
+
 	newGoal := r.FormValue("goal")
 	oldGoal := ""
 	if athlete.Goal.Valid {
@@ -430,6 +678,22 @@ func (h *Athletes) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := models.SetAthleteBarWeight(h.DB, id, barWeight); err != nil {
+		log.Printf("handlers: set bar weight for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := models.SetAthletePlates(h.DB, id, plates); err != nil {
+		log.Printf("handlers: set plates for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := models.SetAthleteGoalWeight(h.DB, id, goalWeight); err != nil {
+		log.Printf("handlers: set goal weight for athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	// Record goal change in history if the goal actually changed.
 	if newGoal != oldGoal {
 		if newGoal != "" {
@@ -487,10 +751,57 @@ func (h *Athletes) Delete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	recordAudit(h.DB, user.ID, models.AuditAthleteDelete, models.AuditTarget("athlete", athlete.ID, athlete.Name))
 
 	http.Redirect(w, r, "/athletes", http.StatusSeeOther)
 }
 
+// Archive hides an athlete who has stopped training from athlete lists while
+// keeping their history. Coach (owns athlete) or admin only.
+func (h *Athletes) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+// Unarchive restores an archived athlete to athlete lists. Coach (owns
+// athlete) or admin only.
+func (h *Athletes) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *Athletes) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for archive: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !middleware.CanManageAthlete(user, athlete) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := models.SetAthleteArchived(h.DB, id, archived); err != nil {
+		log.Printf("handlers: set athlete %d archived=%t: %v", id, archived, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(id, 10), http.StatusSeeOther)
+}
+
 // Promote advances an athlete to the next tier. Coach (owns athlete) or admin only.
 func (h *Athletes) Promote(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -559,7 +870,7 @@ func (h *Athletes) UpdateGoal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, id) {
+	if !middleware.CanWriteAthlete(h.DB, user, id) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -602,6 +913,155 @@ func (h *Athletes) UpdateGoal(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(id, 10), http.StatusSeeOther)
 }
 
+// CheckIn records attendance for a session with nothing to log. The athlete
+// themselves or their coach can check in.
+func (h *Athletes) CheckIn(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanWriteAthlete(h.DB, user, id) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	_, err = models.GetAthleteByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for check-in: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = models.CheckInAthlete(h.DB, id, r.FormValue("date"), r.FormValue("notes"), user.ID)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Invalid date", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: check in athlete %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(id, 10)+"/journal", http.StatusSeeOther)
+}
+
+// GrantViewer gives a user read-only access to an athlete, e.g. a parent
+// following their child's training. Only accounts that aren't coaches or
+// admins and aren't linked to the athlete can be viewers. Coach (owns
+// athlete) or admin only.
+func (h *Athletes) GrantViewer(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for grant viewer: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !middleware.CanManageAthlete(user, athlete) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	viewerID, err := strconv.ParseInt(r.FormValue("user_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	viewer, err := models.GetUserByID(h.DB, viewerID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get user %d for grant viewer: %v", viewerID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if viewer.IsCoach || viewer.IsAdmin || (viewer.AthleteID.Valid && viewer.AthleteID.Int64 == id) {
+		http.Error(w, "This user can't be made a viewer", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := models.GrantAthleteViewer(h.DB, id, viewerID); err != nil {
+		log.Printf("handlers: grant viewer %d on athlete %d: %v", viewerID, id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(id, 10)+"/edit#viewers", http.StatusSeeOther)
+}
+
+// RevokeViewer removes a user's read-only access to an athlete. Coach (owns
+// athlete) or admin only.
+func (h *Athletes) RevokeViewer(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+	viewerID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for revoke viewer: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !middleware.CanManageAthlete(user, athlete) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := models.RevokeAthleteViewer(h.DB, id, viewerID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			h.Templates.NotFound(w, r)
+			return
+		}
+		log.Printf("handlers: revoke viewer %d on athlete %d: %v", viewerID, id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(id, 10)+"/edit#viewers", http.StatusSeeOther)
+}
+
 func tierOptions() []struct{ Value, Label string } {
 	return []struct{ Value, Label string }{
 		{"", "— None —"},
@@ -610,3 +1070,17 @@ func tierOptions() []struct{ Value, Label string } {
 		{"sport_performance", "Sport Performance"},
 	}
 }
+
+// parseOptionalWeight parses an optional weight form field such as
+// bar_weight or goal_weight. An empty value returns 0 (unset). Returns false
+// for non-numeric or negative input.
+func parseOptionalWeight(v string) (float64, bool) {
+	if v == "" {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, false
+	}
+	return f, true
+}
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/carpenike/replog/internal/models"
 )
@@ -238,6 +243,113 @@ func TestAthletes_Update_Success(t *testing.T) {
 	}
 }
 
+func TestAthletes_Update_BarWeight(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	tests := []struct {
+		name     string
+		value    string
+		wantCode int
+		want     float64
+	}{
+		{"set", "35", http.StatusSeeOther, 35},
+		{"invalid", "heavy", http.StatusBadRequest, 35},
+		{"cleared", "", http.StatusSeeOther, models.DefaultBarWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"name": {"Alice"}, "bar_weight": {tt.value}}
+			req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID), form, coach)
+			req.SetPathValue("id", itoa(athlete.ID))
+			rr := httptest.NewRecorder()
+			h.Update(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+			updated, _ := models.GetAthleteByID(db, athlete.ID)
+			if got := updated.BarWeightOrDefault(); got != tt.want {
+				t.Errorf("bar weight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAthletes_Update_Plates(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	form := url.Values{"name": {"Alice"}, "plates": {"25, 10, 5"}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID), form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Update(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	updated, _ := models.GetAthleteByID(db, athlete.ID)
+	if updated.Plates.String != "25, 10, 5" {
+		t.Errorf("plates = %q, want 25, 10, 5", updated.Plates.String)
+	}
+
+	form.Set("plates", "25, lots")
+	req = requestWithUser("POST", "/athletes/"+itoa(athlete.ID), form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	h.Update(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid plates, got %d", rr.Code)
+	}
+}
+
+func TestAthletes_BarAndPlates_KgRoundTrip(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	form := url.Values{"name": {"Alice"}, "bar_weight": {"20"}, "plates": {"25, 20, 1.25"}}
+	req := withWeightUnit(requestWithUser("POST", "/athletes/"+itoa(athlete.ID), form, coach), "kg")
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Update(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	updated, _ := models.GetAthleteByID(db, athlete.ID)
+	if got := fmt.Sprintf("%.2f", updated.BarWeightOrDefault()); got != "44.09" {
+		t.Errorf("stored bar weight = %s, want 44.09 lbs", got)
+	}
+	if plates := updated.AvailablePlates(); len(plates) != 3 || fmt.Sprintf("%.2f", plates[0]) != "55.12" {
+		t.Errorf("stored plates = %v, want lbs equivalents of 25, 20, 1.25 kg", plates)
+	}
+
+	req = withWeightUnit(requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/edit", nil, coach), "kg")
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	h.EditForm(rr, req)
+	body := rr.Body.String()
+	if !strings.Contains(body, `value="20"`) {
+		t.Errorf("edit form does not show 20 kg bar")
+	}
+	if !strings.Contains(body, `value="25, 20, 1.25"`) {
+		t.Errorf("edit form does not show plates in kg:\n%s", body)
+	}
+}
+
 func TestAthletes_Delete_CoachOnly(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -280,6 +392,166 @@ func TestAthletes_Delete_NonCoachForbidden(t *testing.T) {
 	}
 }
 
+func TestAthletes_Show_Age(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	dob := time.Now().AddDate(-12, 0, -1).Format("2006-01-02")
+	withDOB, _ := models.CreateAthlete(db, "Youth", "", "", "", dob, "", "", sql.NullInt64{}, true)
+	noDOB := seedAthlete(t, db, "Adult", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+	show := func(id int64) string {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(id), nil, coach)
+		req.SetPathValue("id", itoa(id))
+		rr := httptest.NewRecorder()
+		h.Show(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	if body := show(withDOB.ID); !contains(body, "Age 12") {
+		t.Error("expected computed age 12")
+	}
+	if body := show(noDOB.ID); contains(body, "Age ") {
+		t.Error("expected age omitted without a date of birth")
+	}
+}
+
+func TestAthletes_Records(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Lifter", "")
+	other := seedAthlete(t, db, "Other", "")
+	ex := seedExercise(t, db, "Deadlift", "")
+	w1, _ := models.CreateWorkout(db, athlete.ID, "2026-02-03", "", 0)
+	models.AddSet(db, w1.ID, ex.ID, 3, 300, 0, "", "", "")
+	w2, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+	models.AddSet(db, w2.ID, ex.ID, 3, 315, 0, "", "", "")
+	if _, err := models.DetectPRs(db, athlete.ID, w2.ID); err != nil {
+		t.Fatalf("detect PRs: %v", err)
+	}
+
+	h := &Athletes{DB: db, Templates: tc}
+	records := func(user *models.User, id int64) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(id)+"/records", nil, user)
+		req.SetPathValue("id", itoa(id))
+		rr := httptest.NewRecorder()
+		h.Records(rr, req)
+		return rr
+	}
+
+	rr := records(coach, athlete.ID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !contains(rr.Body.String(), "Deadlift 3 × 315") {
+		t.Errorf("expected the 315 deadlift PR, got:\n%s", rr.Body.String())
+	}
+
+	nonCoach := seedNonCoach(t, db, athlete.ID)
+	if rr := records(nonCoach, athlete.ID); rr.Code != http.StatusOK {
+		t.Errorf("own records: expected 200, got %d", rr.Code)
+	}
+	if rr := records(nonCoach, other.ID); rr.Code != http.StatusForbidden {
+		t.Errorf("other athlete's records: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestAthletes_Archive(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Retired", "")
+	seedAthlete(t, db, "Training", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/archive", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Archive(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect 303, got %d", rr.Code)
+	}
+	got, err := models.GetAthleteByID(db, athlete.ID)
+	if err != nil || !got.Archived {
+		t.Fatalf("expected archived athlete, got %+v (err %v)", got, err)
+	}
+
+	t.Run("hidden from list by default", func(t *testing.T) {
+		req := requestWithUser("GET", "/athletes", nil, coach)
+		rr := httptest.NewRecorder()
+		h.List(rr, req)
+		if contains(rr.Body.String(), "Retired") {
+			t.Error("expected archived athlete to be hidden")
+		}
+		if !contains(rr.Body.String(), "Training") {
+			t.Error("expected active athlete to be listed")
+		}
+	})
+
+	t.Run("listed with archived=1", func(t *testing.T) {
+		req := requestWithUser("GET", "/athletes?archived=1", nil, coach)
+		rr := httptest.NewRecorder()
+		h.List(rr, req)
+		if !contains(rr.Body.String(), "Retired") {
+			t.Error("expected archived athlete when archived=1")
+		}
+	})
+
+	t.Run("still viewable by coach", func(t *testing.T) {
+		req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID), nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.Show(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if !contains(rr.Body.String(), "is archived") {
+			t.Error("expected archived notice on athlete page")
+		}
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/unarchive", nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.Unarchive(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rr.Code)
+		}
+		got, _ := models.GetAthleteByID(db, athlete.ID)
+		if got.Archived {
+			t.Error("expected athlete to be unarchived")
+		}
+	})
+}
+
+func TestAthletes_Archive_NonCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	nonCoach := seedNonCoach(t, db, athlete.ID)
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/archive", nil, nonCoach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Archive(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+}
+
 func TestAthletes_Promote_CoachSuccess(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -451,6 +723,196 @@ func TestAthletes_UpdateGoal_OwnProfile(t *testing.T) {
 	}
 }
 
+func TestAthletes_UpdateGoal_ShowsTimeline(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+	for _, goal := range []string{"Build strength", "Make varsity"} {
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/goal", url.Values{"goal": {goal}}, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.UpdateGoal(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+	}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID), nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Show(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !contains(body, "goal-timeline") || !contains(body, "Build strength") || !contains(body, "Make varsity") {
+		t.Errorf("expected both goals in the timeline, got:\n%s", body)
+	}
+}
+
+func TestAthletes_CheckIn(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	other := seedAthlete(t, db, "Other", "")
+	nonCoach := seedNonCoach(t, db, athlete.ID)
+
+	h := &Athletes{DB: db, Templates: tc}
+	checkIn := func(id int64, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(id)+"/check-in", form, nonCoach)
+		req.SetPathValue("id", itoa(id))
+		rr := httptest.NewRecorder()
+		h.CheckIn(rr, req)
+		return rr
+	}
+
+	rr := checkIn(athlete.ID, url.Values{"date": {"2026-02-02"}, "notes": {"Yoga"}})
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	if loc := rr.Header().Get("Location"); loc != "/athletes/"+itoa(athlete.ID)+"/journal" {
+		t.Errorf("redirect = %q, want the journal", loc)
+	}
+	page, err := models.ListJournalPage(db, athlete.ID, false, models.JournalFilter{Types: []string{"check-in"}}, 0)
+	if err != nil {
+		t.Fatalf("journal page: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Detail != "Yoga" {
+		t.Errorf("check-in entries = %+v, want one with note Yoga", page.Entries)
+	}
+
+	if rr := checkIn(athlete.ID, url.Values{"date": {"not-a-date"}}); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid date: expected 400, got %d", rr.Code)
+	}
+	if rr := checkIn(other.ID, url.Values{}); rr.Code != http.StatusForbidden {
+		t.Errorf("other athlete: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestAthletes_Viewer_ReadOnly(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	parent := seedUnlinkedNonCoach(t, db)
+	if err := models.GrantAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+		t.Fatalf("grant viewer: %v", err)
+	}
+
+	h := &Athletes{DB: db, Templates: tc}
+	wh := &Workouts{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID), nil, parent)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Show(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("show: expected 200, got %d", rr.Code)
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/workouts", nil, parent)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	wh.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("workouts list: expected 200, got %d", rr.Code)
+	}
+
+	req = requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/check-in", url.Values{"date": {"2026-02-02"}}, parent)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	h.CheckIn(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("check-in: expected 403, got %d", rr.Code)
+	}
+
+	req = requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/goal", url.Values{"goal": {"Squat 200"}}, parent)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	h.UpdateGoal(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("update goal: expected 403, got %d", rr.Code)
+	}
+
+	req = requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts", url.Values{"date": {"2026-02-02"}}, parent)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	wh.Create(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("create workout: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestAthletes_GrantRevokeViewer(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	parent := seedUnlinkedNonCoach(t, db)
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	h := &Athletes{DB: db, Templates: tc}
+	grant := func(user *models.User, userID int64) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/viewers", url.Values{"user_id": {itoa(userID)}}, user)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.GrantViewer(rr, req)
+		return rr
+	}
+	revoke := func(userID int64) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/viewers/"+itoa(userID)+"/delete", nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("userID", itoa(userID))
+		rr := httptest.NewRecorder()
+		h.RevokeViewer(rr, req)
+		return rr
+	}
+
+	if rr := grant(kid, parent.ID); rr.Code != http.StatusForbidden {
+		t.Errorf("non-coach grant: expected 403, got %d", rr.Code)
+	}
+	if rr := grant(coach, kid.ID); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("grant linked user: expected 422, got %d", rr.Code)
+	}
+	if rr := grant(coach, coach.ID); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("grant coach: expected 422, got %d", rr.Code)
+	}
+
+	rr := grant(coach, parent.ID)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("grant: expected 303, got %d", rr.Code)
+	}
+	if ok, _ := models.IsAthleteViewer(db, athlete.ID, parent.ID); !ok {
+		t.Error("expected parent to be a viewer")
+	}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/edit", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr = httptest.NewRecorder()
+	h.EditForm(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("edit form: expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "/viewers/"+itoa(parent.ID)+"/delete") {
+		t.Error("expected edit form to list the viewer")
+	}
+
+	if rr := revoke(parent.ID); rr.Code != http.StatusSeeOther {
+		t.Fatalf("revoke: expected 303, got %d", rr.Code)
+	}
+	if ok, _ := models.IsAthleteViewer(db, athlete.ID, parent.ID); ok {
+		t.Error("expected viewer access revoked")
+	}
+	if rr := revoke(parent.ID); rr.Code != http.StatusNotFound {
+		t.Errorf("revoke again: expected 404, got %d", rr.Code)
+	}
+}
+
 func TestAthletes_UpdateGoal_CoachCanUpdate(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -523,4 +985,86 @@ func TestAthletes_UpdateGoal_ClearGoal(t *testing.T) {
 	if updated.Goal.Valid {
 		t.Errorf("goal should be null after clearing, got %q", updated.Goal.String)
 	}
-}
\ No newline at end of file
+}
+func TestAthletes_Roster_CoachSeesRoster(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	seedAthlete(t, db, "Alice", "foundational")
+
+	h := &Athletes{DB: db, Templates: tc}
+	req := requestWithUser("GET", "/athletes?view=roster", nil, coach)
+	rr := httptest.NewRecorder()
+	h.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !contains(rr.Body.String(), "<h1>Roster</h1>") || !contains(rr.Body.String(), "Alice") {
+		t.Error("expected roster view listing Alice")
+	}
+}
+
+func TestAthletes_Roster_NonCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "foundational")
+	nonCoach := seedNonCoach(t, db, athlete.ID)
+
+	h := &Athletes{DB: db, Templates: tc}
+	req := requestWithUser("GET", "/athletes?view=roster", nil, nonCoach)
+	rr := httptest.NewRecorder()
+	h.List(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestAthletes_WorkloadJSON(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	kid := seedNonCoach(t, db, alice.ID)
+	squat := seedExercise(t, db, "Squat", "")
+	bench := seedExercise(t, db, "Bench Press", "")
+	workout, _ := models.CreateWorkout(db, alice.ID, time.Now().Format("2006-01-02"), "", 0)
+	models.AddSet(db, workout.ID, squat.ID, 5, 200, 0, "", "", "")
+	models.AddSet(db, workout.ID, bench.ID, 5, 100, 0, "", "", "")
+
+	h := &Athletes{DB: db, Templates: tc}
+
+	tests := []struct {
+		name      string
+		athleteID int64
+		query     string
+		wantCode  int
+	}{
+		{"own athlete", alice.ID, "?weeks=4", http.StatusOK},
+		{"invalid weeks", alice.ID, "?weeks=200", http.StatusBadRequest},
+		{"other athlete", bob.ID, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("GET", "/athletes/"+itoa(tt.athleteID)+"/workload.json"+tt.query, nil, kid)
+			req.SetPathValue("id", itoa(tt.athleteID))
+			rr := httptest.NewRecorder()
+			h.WorkloadJSON(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var points []models.WorkloadPoint
+			if err := json.Unmarshal(rr.Body.Bytes(), &points); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(points) != 4 || points[3].Sets != 2 || points[3].Tonnage != 1500 {
+				t.Errorf("points = %+v, want 4 weeks ending with 2 sets, 1500 tonnage", points)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+// Audit handles the admin audit log view.
+type Audit struct {
+	DB        *sql.DB
+	Templates TemplateCache
+}
+
+// List renders the audit log, newest first, optionally filtered by actor and
+// action. Admin only.
+// GET /admin/audit
+func (h *Audit) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter models.AuditFilter
+	if id, err := strconv.ParseInt(q.Get("user_id"), 10, 64); err == nil && id > 0 {
+		filter.UserID = id
+	}
+	filter.Action = q.Get("action")
+
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	page, err := models.ListAuditLog(h.DB, filter, offset)
+	if err != nil {
+		log.Printf("handlers: list audit log: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	users, err := models.ListUsers(h.DB)
+	if err != nil {
+		log.Printf("handlers: list users for audit log: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+	actions, err := models.ListAuditActions(h.DB)
+	if err != nil {
+		log.Printf("handlers: list audit actions: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	data := map[string]any{
+		"Entries": page.Entries,
+		"HasMore": page.HasMore,
+		"Filter":  filter,
+		"Users":   users,
+		"Actions": actions,
+	}
+	if page.HasMore {
+		next := url.Values{}
+		if filter.UserID != 0 {
+			next.Set("user_id", strconv.FormatInt(filter.UserID, 10))
+		}
+		if filter.Action != "" {
+			next.Set("action", filter.Action)
+		}
+		next.Set("offset", strconv.Itoa(offset+models.AuditPageSize))
+		data["NextURL"] = "/admin/audit?" + next.Encode()
+	}
+	if err := h.Templates.Render(w, r, "audit_log.html", data); err != nil {
+		log.Printf("handlers: render audit log: %v", err)
+	}
+}
+
+// recordAudit adds an audit log entry. Failures are logged but don't stop
+// the action being audited.
+func recordAudit(db *sql.DB, userID int64, action, target string) {
+	if err := models.RecordAudit(db, userID, action, target); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+}
+
+// auditUserTarget describes a user as an audit log target, falling back to
+// the bare ID if the user can't be loaded.
+func auditUserTarget(db *sql.DB, id int64) string {
+	if u, err := models.GetUserByID(db, id); err == nil {
+		return models.AuditTarget("user", u.ID, u.Username)
+	}
+	return models.AuditTarget("user", id, "")
+}
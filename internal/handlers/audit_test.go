@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestAudit_RecordedBySensitiveActions(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+
+	// Settings update records the changed keys, never the values.
+	settings := &Settings{DB: db, Templates: tc}
+	form := url.Values{"setting_llm.model": {"llama3"}}
+	settings.Update(httptest.NewRecorder(), requestWithUser("POST", "/admin/settings", form, coach))
+
+	// Athlete deletion records the athlete's name.
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/delete", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	(&Athletes{DB: db, Templates: tc}).Delete(httptest.NewRecorder(), req)
+
+	page, err := models.ListAuditLog(db, models.AuditFilter{UserID: coach.ID}, 0)
+	if err != nil {
+		t.Fatalf("list audit log: %v", err)
+	}
+	if len(page.Entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(page.Entries))
+	}
+	if e := page.Entries[0]; e.Action != models.AuditAthleteDelete || e.Target != "athlete "+itoa(athlete.ID)+" (Kid)" {
+		t.Errorf("athlete delete entry = %s %q", e.Action, e.Target)
+	}
+	if e := page.Entries[1]; e.Action != models.AuditSettingsUpdate || e.Target != "llm.model" {
+		t.Errorf("settings entry = %s %q", e.Action, e.Target)
+	}
+}
+
+func TestAudit_List(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	models.RecordAudit(db, coach.ID, models.AuditUserDelete, "user 7 (gone)")
+	models.RecordAudit(db, coach.ID, models.AuditProgramDelete, "program 3 (5/3/1)")
+
+	h := &Audit{DB: db, Templates: tc}
+
+	w := httptest.NewRecorder()
+	h.List(w, requestWithUser("GET", "/admin/audit", nil, coach))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "user 7 (gone)") || !strings.Contains(body, "program 3 (5/3/1)") {
+		t.Errorf("expected both entries in body, got: %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.List(w, requestWithUser("GET", "/admin/audit?action="+models.AuditProgramDelete, nil, coach))
+	body = w.Body.String()
+	if strings.Contains(body, "user 7 (gone)") || !strings.Contains(body, "program 3 (5/3/1)") {
+		t.Errorf("expected only the program entry when filtered, got: %s", body)
+	}
+}
@@ -3,10 +3,14 @@ package handlers
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/alexedwards/scs/v2"
+	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 )
 
@@ -29,7 +33,12 @@ func (a *Auth) LoginPage(w http.ResponseWriter, r *http.Request) {
 	errorMsg := a.Sessions.PopString(r.Context(), "flash_error")
 
 	data := map[string]any{
-		"Error": errorMsg,
+		"Error":   errorMsg,
+		"Success": a.Sessions.PopString(r.Context(), "flash_success"),
+		// A password was accepted and the account's second factor is due.
+		"TOTPPending": a.Sessions.GetInt64(r.Context(), "totp_user_id") != 0,
+		// Reset links need SMTP and a configured base URL to build them from.
+		"PasswordReset": models.IsEmailLinksConfigured(a.DB),
 	}
 	if err := a.Templates["login.html"].ExecuteTemplate(w, "login", data); err != nil {
 		log.Printf("handlers: login template error: %v", err)
@@ -53,39 +62,201 @@ func (a *Auth) LoginSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Per-account throttling complements the per-IP limiter, which can't
+	// tell users behind a shared proxy apart and doesn't stop slow guessing
+	// against one account from many addresses.
+	locked, until, err := models.IsAccountLocked(a.DB, username)
+	if err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	if locked {
+		log.Printf("handlers: login for %q refused: locked until %s", username, until.Format(time.RFC3339))
+		a.Sessions.Put(r.Context(), "flash_error", lockoutMessage(until))
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
 	user, err := models.Authenticate(a.DB, username, password)
 	if err != nil {
+		// Passwordless accounts get the same response as a wrong password,
+		// so the message doesn't reveal which kind of account exists.
 		if errors.Is(err, models.ErrNoPassword) {
 			log.Printf("handlers: passwordless user %q attempted password login", username)
-			a.Sessions.Put(r.Context(), "flash_error", "This account uses passwordless login. Use your login link or passkey.")
 		} else {
 			log.Printf("handlers: login failed for %q: %v", username, err)
-			a.Sessions.Put(r.Context(), "flash_error", "Invalid username or password")
+		}
+		a.Sessions.Put(r.Context(), "flash_error", "Invalid username or password")
+		until, err := models.RecordFailedLogin(a.DB, username)
+		if err != nil {
+			log.Printf("handlers: %v", err)
+		} else if !until.IsZero() {
+			log.Printf("handlers: login for %q locked until %s", username, until.Format(time.RFC3339))
+			a.Sessions.Put(r.Context(), "flash_error", lockoutMessage(until))
+		}
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	// Accounts with two-factor enabled must enter a code before the session
+	// is authenticated. Only the pending user ID is kept until then, with the
+	// username so wrong codes count toward the same lockout as passwords.
+	if models.IsTOTPEnabled(a.DB, user.ID) {
+		if err := a.Sessions.RenewToken(r.Context()); err != nil {
+			log.Printf("handlers: session renew error: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		a.Sessions.Put(r.Context(), "totp_user_id", user.ID)
+		a.Sessions.Put(r.Context(), "totp_username", username)
+		a.Sessions.Remove(r.Context(), "totp_attempts")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !a.completeLogin(w, r, user.ID) {
+		return
+	}
+	if err := models.ResetFailedLogins(a.DB, username); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+
+	// Coaches and admins required to use two-factor must enroll before
+	// using the app; RequireAuth keeps them on the enrollment page.
+	if models.IsTOTPRequired(a.DB, user) {
+		a.Sessions.Put(r.Context(), "totp_setup_required", true)
+		http.Redirect(w, r, "/preferences/totp/begin", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// lockoutMessage tells a user their account is temporarily locked after too
+// many failed logins and when to try again.
+func lockoutMessage(until time.Time) string {
+	minutes := int(math.Ceil(time.Until(until).Minutes()))
+	if minutes <= 1 {
+		return "Too many failed sign-in attempts. Try again in a minute."
+	}
+	return fmt.Sprintf("Too many failed sign-in attempts. Try again in %d minutes.", minutes)
+}
+
+// maxTOTPAttempts is the number of wrong codes allowed before a pending
+// two-factor login is abandoned and the password must be entered again.
+const maxTOTPAttempts = 5
+
+// LoginTOTP processes the second step of a password login for accounts with
+// two-factor enabled. The code may be an authenticator code or a recovery
+// code. Wrong codes count as failed logins for the account lockout.
+func (a *Auth) LoginTOTP(w http.ResponseWriter, r *http.Request) {
+	userID := a.Sessions.GetInt64(r.Context(), "totp_user_id")
+	if userID == 0 {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	username := a.Sessions.GetString(r.Context(), "totp_username")
+
+	locked, until, err := models.IsAccountLocked(a.DB, username)
+	if err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	if locked {
+		log.Printf("handlers: TOTP for user %d refused: locked until %s", userID, until.Format(time.RFC3339))
+		a.clearPendingTOTP(r)
+		a.Sessions.Put(r.Context(), "flash_error", lockoutMessage(until))
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if err := models.VerifyTOTP(a.DB, userID, r.FormValue("code")); err != nil {
+		if !errors.Is(err, models.ErrInvalidTOTPCode) {
+			log.Printf("handlers: verify TOTP for user %d: %v", userID, err)
+		}
+		until, err := models.RecordFailedLogin(a.DB, username)
+		if err != nil {
+			log.Printf("handlers: %v", err)
+		}
+		attempts := a.Sessions.GetInt(r.Context(), "totp_attempts") + 1
+		if !until.IsZero() {
+			log.Printf("handlers: login for %q locked until %s", username, until.Format(time.RFC3339))
+			a.clearPendingTOTP(r)
+			a.Sessions.Put(r.Context(), "flash_error", lockoutMessage(until))
+		} else if attempts >= maxTOTPAttempts {
+			log.Printf("handlers: too many TOTP attempts for user %d", userID)
+			a.clearPendingTOTP(r)
+			a.Sessions.Put(r.Context(), "flash_error", "Too many incorrect codes. Sign in again.")
+		} else {
+			a.Sessions.Put(r.Context(), "totp_attempts", attempts)
+			a.Sessions.Put(r.Context(), "flash_error", "Invalid authentication code")
 		}
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
+	a.clearPendingTOTP(r)
+	if !a.completeLogin(w, r, userID) {
+		return
+	}
+	if err := models.ResetFailedLogins(a.DB, username); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// clearPendingTOTP clears a pending two-factor login from the session.
+func (a *Auth) clearPendingTOTP(r *http.Request) {
+	a.Sessions.Remove(r.Context(), "totp_user_id")
+	a.Sessions.Remove(r.Context(), "totp_username")
+	a.Sessions.Remove(r.Context(), "totp_attempts")
+}
+
+// completeLogin authenticates the session as userID. It writes an error
+// response and returns false if the session could not be renewed.
+func (a *Auth) completeLogin(w http.ResponseWriter, r *http.Request, userID int64) bool {
 	// Renew session token to prevent fixation.
 	if err := a.Sessions.RenewToken(r.Context()); err != nil {
 		log.Printf("handlers: session renew error: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return false
 	}
 
-	a.Sessions.Put(r.Context(), "userID", user.ID)
+	a.Sessions.Put(r.Context(), "userID", userID)
+	if err := recordSession(a.Sessions, a.DB, r, userID); err != nil {
+		log.Printf("handlers: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return false
+	}
+	if user, err := models.GetUserByID(a.DB, userID); err == nil {
+		setSessionLifetime(a.Sessions, a.DB, r, user)
+	} else {
+		log.Printf("handlers: load user %d for session lifetime: %v", userID, err)
+	}
 
 	// Ensure default preferences exist for this user.
-	if err := models.EnsureUserPreferences(a.DB, user.ID); err != nil {
-		log.Printf("handlers: ensure preferences for user %d: %v", user.ID, err)
+	if err := models.EnsureUserPreferences(a.DB, userID); err != nil {
+		log.Printf("handlers: ensure preferences for user %d: %v", userID, err)
 		// Non-fatal — continue with login.
 	}
+	return true
+}
 
-	http.Redirect(w, r, "/", http.StatusSeeOther)
+// setSessionLifetime makes a newly authenticated session expire after the
+// lifetime configured for user's role. The session manager's own lifetime is
+// fixed at startup; this lets a changed setting apply from the next login.
+func setSessionLifetime(sm *scs.SessionManager, db *sql.DB, r *http.Request, user *models.User) {
+	sm.SetDeadline(r.Context(), time.Now().Add(models.SessionLifetimeFor(db, user)))
+}
+
+// recordSession adds a newly authenticated session to userID's session list.
+// RequireAuth only accepts sessions on the list, so revoking a user's
+// sessions also ends ones that haven't made a request yet.
+func recordSession(sm *scs.SessionManager, db *sql.DB, r *http.Request, userID int64) error {
+	return models.CreateUserSession(db, sm.Token(r.Context()), userID, middleware.ClientIP(r), r.UserAgent())
 }
 
 // Logout destroys the session and redirects to login.
 func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	endImpersonation(a.DB, a.Sessions, r)
 	if err := a.Sessions.Destroy(r.Context()); err != nil {
 		log.Printf("handlers: session destroy error: %v", err)
 	}
@@ -7,7 +7,9 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 )
 
@@ -96,6 +98,79 @@ func TestAuth_LoginSubmit_Success(t *testing.T) {
 	}
 }
 
+func TestAuth_LoginSubmit_SessionRevocableBeforeFirstRequest(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	user, err := models.CreateUser(db, "coach", "", "password123", "c@test.com", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+
+	form := url.Values{"username": {"coach"}, "password": {"password123"}}
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(auth.LoginSubmit)).ServeHTTP(rr, req)
+	cookies := rr.Result().Cookies()
+
+	// The login is recorded without going through RequireAuth, so revoking
+	// the user's sessions reaches it.
+	revoked, err := models.RevokeUserSessions(db, user.ID, "")
+	if err != nil {
+		t.Fatalf("revoke sessions: %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("revoked = %d, want 1", revoked)
+	}
+
+	handler := middleware.RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("revoked session should not reach the handler")
+	}))
+	req = httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/login" {
+		t.Errorf("revoked session: got %d %q, want 303 /login", rr.Code, loc)
+	}
+}
+
+func TestAuth_LoginSubmit_CoachSessionLifetime(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	if _, err := models.CreateUser(db, "coach", "", "password123", "c@test.com", true, false, sql.NullInt64{}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	models.SetSetting(db, "security.coach_session_lifetime_hours", "6")
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+
+	form := url.Values{"username": {"coach"}, "password": {"password123"}}
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	handler := sm.LoadAndSave(http.HandlerFunc(auth.LoginSubmit))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("no session cookie set")
+	}
+	// The cookie expires with the session, rounded to the second.
+	if limit := time.Now().Add(6*time.Hour + time.Second); cookies[0].Expires.After(limit) {
+		t.Errorf("cookie expires %v, want within 6h", cookies[0].Expires)
+	}
+}
+
 func TestAuth_LoginSubmit_InvalidCredentials(t *testing.T) {
 	db := testDB(t)
 	sm := testSessionManager()
@@ -192,4 +267,81 @@ func TestAuth_LoginSubmit_PasswordlessUserRejects(t *testing.T) {
 	if loc := rr.Header().Get("Location"); loc != "/login" {
 		t.Errorf("expected redirect to /login, got %q", loc)
 	}
+
+	// The response matches a wrong password, and the attempt counts toward
+	// the lockout.
+	req = httptest.NewRequest("GET", "/login", nil)
+	for _, c := range rr.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(auth.LoginPage)).ServeHTTP(rr, req)
+	if body := rr.Body.String(); !strings.Contains(body, "Invalid username or password") || strings.Contains(body, "passwordless") {
+		t.Error("expected the generic invalid credentials message")
+	}
+	var failures int
+	db.QueryRow(`SELECT failures FROM login_attempts WHERE username = 'kidonly'`).Scan(&failures)
+	if failures != 1 {
+		t.Errorf("failures = %d, want 1", failures)
+	}
+}
+
+func TestAuth_LoginSubmit_AccountLockout(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	_, err := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+	login := func(password string) string {
+		form := url.Values{"username": {"coach"}, "password": {password}}
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(auth.LoginSubmit)).ServeHTTP(rr, req)
+		return rr.Header().Get("Location")
+	}
+
+	// A success before the threshold clears earlier failures.
+	for i := 0; i < models.LoginLockoutThreshold-1; i++ {
+		login("wrongpassword")
+	}
+	if loc := login("password123"); loc != "/" {
+		t.Fatalf("login before threshold: redirected to %q, want /", loc)
+	}
+
+	for i := 0; i < models.LoginLockoutThreshold; i++ {
+		login("wrongpassword")
+	}
+	if locked, _, _ := models.IsAccountLocked(db, "coach"); !locked {
+		t.Fatal("expected account to be locked after repeated failures")
+	}
+	// The right password is refused while locked.
+	if loc := login("password123"); loc != "/login" {
+		t.Errorf("login while locked: redirected to %q, want /login", loc)
+	}
+
+	// Once the lock expires the right password works and resets the count.
+	db.Exec(`UPDATE login_attempts SET locked_until = NULL`)
+	if loc := login("password123"); loc != "/" {
+		t.Errorf("login after lock expired: redirected to %q, want /", loc)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM login_attempts`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected failures reset after successful login, got %d rows", n)
+	}
+}
+
+func TestLockoutMessage(t *testing.T) {
+	if got := lockoutMessage(time.Now().Add(30 * time.Second)); !strings.Contains(got, "in a minute") {
+		t.Errorf("lockoutMessage(30s) = %q", got)
+	}
+	if got := lockoutMessage(time.Now().Add(4*time.Minute + 10*time.Second)); !strings.Contains(got, "in 5 minutes") {
+		t.Errorf("lockoutMessage(4m10s) = %q", got)
+	}
 }
@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"crypto/rand"
 	"database/sql"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -11,7 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
 
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
@@ -20,13 +18,12 @@ import (
 // maxAvatarSize is the maximum allowed avatar file size (2 MB).
 const maxAvatarSize = 2 << 20
 
-// allowedAvatarTypes maps MIME types to file extensions for allowed avatar formats.
-var allowedAvatarTypes = map[string]string{
-	"image/jpeg": ".jpg",
-	"image/png":  ".png",
-	"image/gif":  ".gif",
-	"image/webp": ".webp",
-}
+// Stored avatars are scaled to fit avatarMaxDimension, with a thumbnail
+// fitting avatarThumbDimension for lists and the sidebar.
+const (
+	avatarMaxDimension   = 512
+	avatarThumbDimension = 64
+)
 
 // Avatars handles avatar upload, deletion, and serving.
 type Avatars struct {
@@ -61,71 +58,30 @@ func (h *Avatars) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Detect content type from file contents (more reliable than header).
-	buf := make([]byte, 512)
-	n, err := file.Read(buf)
-	if err != nil && err != io.EOF {
-		log.Printf("handlers: read avatar for detection: %v", err)
-		h.renderPrefsWithError(w, r, "Failed to read uploaded file.", user.ID)
-		return
-	}
-	contentType := http.DetectContentType(buf[:n])
-	ext, ok := allowedAvatarTypes[contentType]
-	if !ok {
+	filename, err := saveAvatar(file, h.AvatarDir, fmt.Sprintf("%d_", user.ID))
+	if errors.Is(err, errUnsupportedImage) {
 		h.renderPrefsWithError(w, r, "Unsupported file type. Use JPEG, PNG, GIF, or WebP.", user.ID)
 		return
 	}
-
-	// Seek back to beginning for the copy.
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		log.Printf("handlers: seek avatar file: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// Generate a unique filename.
-	randBytes := make([]byte, 16)
-	if _, err := rand.Read(randBytes); err != nil {
-		log.Printf("handlers: generate avatar filename: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	filename := fmt.Sprintf("%d_%s%s", user.ID, hex.EncodeToString(randBytes), ext)
-
-	// Ensure avatar directory exists.
-	if err := os.MkdirAll(h.AvatarDir, 0o755); err != nil {
-		log.Printf("handlers: create avatar dir: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if errors.Is(err, errImageTooLarge) {
+		h.renderPrefsWithError(w, r, "Image dimensions are too large. Use a smaller photo.", user.ID)
 		return
 	}
-
-	// Write file to disk.
-	destPath := filepath.Join(h.AvatarDir, filename)
-	dst, err := os.Create(destPath)
 	if err != nil {
-		log.Printf("handlers: create avatar file: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		log.Printf("handlers: write avatar file: %v", err)
-		os.Remove(destPath)
+		log.Printf("handlers: save avatar: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	// Delete old avatar file if one exists.
+	// Delete old avatar files if one exists.
 	if user.HasAvatar() {
-		oldPath := filepath.Join(h.AvatarDir, user.AvatarPath.String)
-		os.Remove(oldPath) // best-effort cleanup
+		removeAvatarFiles(h.AvatarDir, user.AvatarPath.String)
 	}
 
 	// Update database.
 	if err := models.UpdateAvatarPath(h.DB, user.ID, sql.NullString{String: filename, Valid: true}); err != nil {
 		log.Printf("handlers: update avatar path for user %d: %v", user.ID, err)
-		os.Remove(destPath)
+		removeAvatarFiles(h.AvatarDir, filename)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -138,8 +94,7 @@ func (h *Avatars) Delete(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
 
 	if user.HasAvatar() {
-		oldPath := filepath.Join(h.AvatarDir, user.AvatarPath.String)
-		os.Remove(oldPath) // best-effort cleanup
+		removeAvatarFiles(h.AvatarDir, user.AvatarPath.String)
 	}
 
 	if err := models.UpdateAvatarPath(h.DB, user.ID, sql.NullString{}); err != nil {
@@ -155,25 +110,115 @@ func (h *Avatars) Delete(w http.ResponseWriter, r *http.Request) {
 
 // Serve serves avatar image files from the avatar directory.
 func (h *Avatars) Serve(w http.ResponseWriter, r *http.Request) {
-	filename := r.PathValue("filename")
-
-	// Sanitize filename — prevent directory traversal.
-	filename = filepath.Base(filename)
-	if filename == "." || filename == "/" || strings.Contains(filename, "..") {
+	filePath := uploadPath(h.AvatarDir, r.PathValue("filename"))
+	if filePath == "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	filePath := filepath.Join(h.AvatarDir, filename)
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		http.NotFound(w, r)
-		return
+		// Avatars uploaded before thumbnails existed have none; serve the
+		// full image instead.
+		name, ok := models.AvatarNameFromThumb(filepath.Base(filePath))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		filePath = filepath.Join(h.AvatarDir, name)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			http.NotFound(w, r)
+			return
+		}
 	}
 
 	w.Header().Set("Cache-Control", "public, max-age=86400")
 	http.ServeFile(w, r, filePath)
 }
 
+// saveAvatar decodes an uploaded avatar, scales it to avatarMaxDimension
+// and writes it into dir under prefix plus a random name, with a thumbnail
+// beside it. Re-encoding strips EXIF and other metadata, including photo
+// location; the EXIF orientation is applied to the pixels first. Returns the
+// stored filename.
+func saveAvatar(file io.Reader, dir, prefix string) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("read upload: %w", err)
+	}
+	// Sniff the content type (never trusting the client's header or
+	// filename) before handing the bytes to a decoder.
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return "", errUnsupportedImage
+	}
+	src, format, err := decodeImage(data)
+	if err != nil {
+		return "", err
+	}
+
+	img := orient(fitImage(src, avatarMaxDimension), jpegOrientation(data))
+	thumb := fitImage(img, avatarThumbDimension)
+
+	ext := imageExt(format)
+	filename, err := randomUploadName(prefix, ext)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create dir: %w", err)
+	}
+	if err := writeImage(filepath.Join(dir, filename), img, ext); err != nil {
+		return "", err
+	}
+	if err := writeImage(filepath.Join(dir, models.AvatarThumbName(filename)), thumb, ext); err != nil {
+		os.Remove(filepath.Join(dir, filename))
+		return "", err
+	}
+	return filename, nil
+}
+
+// removeAvatarFiles deletes an avatar and its thumbnail. Best-effort: errors
+// are ignored.
+func removeAvatarFiles(dir, filename string) {
+	os.Remove(filepath.Join(dir, filename))
+	os.Remove(filepath.Join(dir, models.AvatarThumbName(filename)))
+}
+
+// Default serves the image for a user without an uploaded avatar: a
+// redirect to their Gravatar when that setting is on, they have an email
+// address and the viewer may see them, otherwise a generated identicon. The
+// Gravatar URL contains a hash of the email, so it isn't shown to everyone.
+// GET /avatars/default/{id}
+func (h *Avatars) Default(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	user, err := models.GetUserByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get user %d for default avatar: %v", id, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	viewer := middleware.UserFromContext(r.Context())
+	if models.GetSetting(h.DB, "avatars.gravatar") == "true" && canSeeGravatar(h.DB, viewer, user) {
+		if u := models.GravatarURL(user, 128); u != "" {
+			http.Redirect(w, r, u, http.StatusFound)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write([]byte(models.IdenticonFor(user)))
+}
+
 // renderPrefsWithError re-renders the preferences form with an error message.
 func (h *Avatars) renderPrefsWithError(w http.ResponseWriter, r *http.Request, msg string, userID int64) {
 	prefs, _ := models.GetUserPreferences(h.DB, userID)
@@ -194,3 +239,16 @@ func (h *Avatars) renderPrefsWithError(w http.ResponseWriter, r *http.Request, m
 		log.Printf("handlers: render preferences form with avatar error: %v", err)
 	}
 }
+
+// canSeeGravatar reports whether viewer may be shown user's Gravatar: their
+// own, any user's for admins, and for coaches, users linked to athletes they
+// coach.
+func canSeeGravatar(db *sql.DB, viewer, user *models.User) bool {
+	if viewer == nil {
+		return false
+	}
+	if viewer.ID == user.ID || viewer.IsAdmin {
+		return true
+	}
+	return viewer.IsCoach && user.AthleteID.Valid && middleware.CanWriteAthlete(db, viewer, user.AthleteID.Int64)
+}
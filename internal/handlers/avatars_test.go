@@ -90,6 +90,70 @@ func TestAvatarUpload(t *testing.T) {
 		}
 	})
 
+	t.Run("resizes and strips metadata", func(t *testing.T) {
+		// A landscape photo whose EXIF says to rotate it to portrait.
+		body, contentType := createMultipartFile(t, "avatar", "photo.jpg", createTestJPEG(t, 1024, 512, 6))
+
+		req := httptest.NewRequest(http.MethodPost, "/avatars/upload", body)
+		req.Header.Set("Content-Type", contentType)
+		currentUser, _ := models.GetUserByID(db, user.ID)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, currentUser))
+
+		rr := httptest.NewRecorder()
+		h.Upload(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusSeeOther)
+		}
+
+		updated, _ := models.GetUserByID(db, user.ID)
+		for name, want := range map[string]image.Point{
+			updated.AvatarPath.String:                         {256, 512},
+			models.AvatarThumbName(updated.AvatarPath.String): {32, 64},
+		} {
+			data, err := os.ReadFile(filepath.Join(avatarDir, name))
+			if err != nil {
+				t.Fatalf("read %s: %v", name, err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode %s: %v", name, err)
+			}
+			if format != "jpeg" || cfg.Width != want.X || cfg.Height != want.Y {
+				t.Errorf("%s: %s %dx%d, want jpeg %dx%d", name, format, cfg.Width, cfg.Height, want.X, want.Y)
+			}
+			if bytes.Contains(data, []byte("Exif")) {
+				t.Errorf("%s: EXIF block was not stripped", name)
+			}
+		}
+	})
+
+	t.Run("converts WebP to PNG", func(t *testing.T) {
+		body, contentType := createMultipartFile(t, "avatar", "photo.webp", testWebP)
+
+		req := httptest.NewRequest(http.MethodPost, "/avatars/upload", body)
+		req.Header.Set("Content-Type", contentType)
+		currentUser, _ := models.GetUserByID(db, user.ID)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, currentUser))
+
+		rr := httptest.NewRecorder()
+		h.Upload(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusSeeOther)
+		}
+
+		updated, _ := models.GetUserByID(db, user.ID)
+		if filepath.Ext(updated.AvatarPath.String) != ".png" {
+			t.Errorf("avatar = %q, want a .png", updated.AvatarPath.String)
+		}
+		data, err := os.ReadFile(filepath.Join(avatarDir, updated.AvatarPath.String))
+		if err != nil {
+			t.Fatalf("read avatar: %v", err)
+		}
+		if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || format != "png" {
+			t.Errorf("stored avatar: format %q, err %v, want png", format, err)
+		}
+	})
+
 	t.Run("rejects non-image", func(t *testing.T) {
 		body, contentType := createMultipartFile(t, "avatar", "test.txt", []byte("not an image"))
 
@@ -171,6 +235,18 @@ func TestAvatarServe(t *testing.T) {
 		}
 	})
 
+	t.Run("thumbnail falls back to full image", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/avatars/test_thumb.png", nil)
+		req.SetPathValue("filename", "test_thumb.png")
+
+		rr := httptest.NewRecorder()
+		h.Serve(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+	})
+
 	t.Run("non-existent file", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/avatars/missing.png", nil)
 		req.SetPathValue("filename", "missing.png")
@@ -196,6 +272,83 @@ func TestAvatarServe(t *testing.T) {
 	})
 }
 
+func TestAvatarDefault(t *testing.T) {
+	db := testDB(t)
+	h := &Avatars{DB: db}
+
+	coach, _ := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	otherCoach, _ := models.CreateUser(db, "othercoach", "", "password123", "", true, false, sql.NullInt64{})
+	athlete, _ := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	withEmail, _ := models.CreateUser(db, "withemail", "", "password123", "someone@example.com", false, false, sql.NullInt64{Int64: athlete.ID, Valid: true})
+	noEmail, _ := models.CreateUser(db, "noemail", "", "password123", "", false, false, sql.NullInt64{})
+
+	viewer := withEmail
+	serve := func(id string) *httptest.ResponseRecorder {
+		req := requestWithUser(http.MethodGet, "/avatars/default/"+id, nil, viewer)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		h.Default(rr, req)
+		return rr
+	}
+
+	t.Run("identicon by default", func(t *testing.T) {
+		rr := serve(itoa(withEmail.ID))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "image/svg+xml" {
+			t.Errorf("Content-Type = %q, want image/svg+xml", ct)
+		}
+		if rr.Body.String() != models.IdenticonFor(withEmail) {
+			t.Error("body is not the user's identicon")
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		if rr := serve("99999"); rr.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	models.SetSetting(db, "avatars.gravatar", "true")
+
+	t.Run("gravatar when enabled", func(t *testing.T) {
+		rr := serve(itoa(withEmail.ID))
+		if rr.Code != http.StatusFound {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusFound)
+		}
+		if loc := rr.Header().Get("Location"); loc != models.GravatarURL(withEmail, 128) {
+			t.Errorf("Location = %q", loc)
+		}
+	})
+
+	t.Run("gravatar for the user's coach", func(t *testing.T) {
+		viewer = coach
+		defer func() { viewer = withEmail }()
+		if rr := serve(itoa(withEmail.ID)); rr.Code != http.StatusFound {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusFound)
+		}
+	})
+
+	t.Run("identicon for other viewers", func(t *testing.T) {
+		defer func() { viewer = withEmail }()
+		for _, v := range []*models.User{noEmail, otherCoach} {
+			viewer = v
+			rr := serve(itoa(withEmail.ID))
+			if rr.Code != http.StatusOK || rr.Body.String() != models.IdenticonFor(withEmail) {
+				t.Errorf("viewer %s: status = %d, want the identicon", v.Username, rr.Code)
+			}
+		}
+	})
+
+	t.Run("identicon without email", func(t *testing.T) {
+		rr := serve(itoa(noEmail.ID))
+		if rr.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+	})
+}
+
 // createTestPNG generates a small valid PNG image for testing.
 func createTestPNG(t *testing.T) []byte {
 	t.Helper()
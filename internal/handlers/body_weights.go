@@ -63,16 +63,21 @@ func (h *BodyWeights) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Load chart data for body weight trend.
-	prefs := middleware.PrefsFromContext(r.Context())
-	unit := models.DefaultWeightUnit
-	if prefs != nil {
-		unit = prefs.WeightUnit
-	}
+	unit := preferredWeightUnit(r)
 	chartData, chartErr := models.BodyWeightChartData(h.DB, athleteID, 30, unit)
 	if chartErr != nil {
 		log.Printf("handlers: body weight chart for athlete %d: %v", athleteID, chartErr)
 	}
 
+	stats, err := models.BodyWeightStats(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: body weight stats for athlete %d: %v", athleteID, err)
+		// Non-fatal — render without the stats summary.
+	}
+	if stats != nil {
+		stats = stats.InUnit(unit)
+	}
+
 	data := map[string]any{
 		"Athlete":    athlete,
 		"Entries":    page.Entries,
@@ -80,6 +85,7 @@ func (h *BodyWeights) List(w http.ResponseWriter, r *http.Request) {
 		"NextOffset": offset + models.BodyWeightPageSize,
 		"Today":      time.Now().Format("2006-01-02"),
 		"Chart":      chartData,
+		"Stats":      stats,
 	}
 	if err := h.Templates.Render(w, r, "body_weights.html", data); err != nil {
 		log.Printf("handlers: body weights template: %v", err)
@@ -97,7 +103,7 @@ func (h *BodyWeights) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -144,9 +150,19 @@ func (h *BodyWeights) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Weights may be logged in either unit; they are stored as lbs.
+	unit := r.FormValue("unit")
+	if unit == "" {
+		unit = preferredWeightUnit(r)
+	}
+
 	notes := r.FormValue("notes")
 
-	_, err = models.CreateBodyWeight(h.DB, athleteID, date, weight, notes)
+	_, err = models.CreateBodyWeightInUnit(h.DB, athleteID, date, weight, unit, notes)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Invalid weight unit", http.StatusBadRequest)
+		return
+	}
 	if errors.Is(err, models.ErrDuplicateBodyWeight) {
 		athlete, dupErr := models.GetAthleteByID(h.DB, athleteID)
 		if dupErr != nil {
@@ -189,7 +205,7 @@ func (h *BodyWeights) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
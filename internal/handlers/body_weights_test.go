@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/carpenike/replog/internal/models"
@@ -29,6 +30,30 @@ func TestBodyWeights_List_CoachAccess(t *testing.T) {
 	}
 }
 
+func TestBodyWeights_List_GoalProjection(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Athlete", "")
+
+	models.CreateBodyWeight(db, a.ID, "2026-02-01", 200.0, "")
+	models.CreateBodyWeight(db, a.ID, "2026-02-08", 198.0, "")
+	models.SetAthleteGoalWeight(db, a.ID, 190)
+
+	h := &BodyWeights{DB: db, Templates: tc}
+	req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/body-weights", nil, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "projected <strong>4 weeks</strong>") {
+		t.Error("expected weeks-to-goal projection in body weight page")
+	}
+}
+
 func TestBodyWeights_List_NonCoachOwnAthlete(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -111,6 +136,48 @@ func TestBodyWeights_Create_Success(t *testing.T) {
 	}
 }
 
+func TestBodyWeights_Create_Kilograms(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Athlete", "")
+
+	h := &BodyWeights{DB: db, Templates: tc}
+
+	form := url.Values{
+		"date":   {"2026-02-01"},
+		"weight": {"100"},
+		"unit":   {"kg"},
+	}
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/body-weights", form, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	bw, _ := models.LatestBodyWeight(db, a.ID)
+	if bw == nil {
+		t.Fatal("expected body weight record")
+	}
+	if bw.Unit != "kg" || fmt.Sprintf("%.2f", bw.Weight) != "220.46" {
+		t.Errorf("stored %.2f (%s), want 220.46 lbs logged in kg", bw.Weight, bw.Unit)
+	}
+
+	form.Set("date", "2026-02-02")
+	form.Set("unit", "stone")
+	req = requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/body-weights", form, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr = httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid unit: expected 400, got %d", rr.Code)
+	}
+}
+
 func TestBodyWeights_Create_InvalidWeight(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
+)
+
+// EmailVerification handles confirming that a user receives mail at their
+// email address. Password reset links are only sent to verified addresses.
+type EmailVerification struct {
+	DB       *sql.DB
+	Sessions *scs.SessionManager
+}
+
+// Send emails a verification link to the current user's address.
+// POST /preferences/email/verify
+func (h *EmailVerification) Send(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if !user.Email.Valid || !models.IsEmailLinksConfigured(h.DB) {
+		http.Error(w, "Email verification is not available", http.StatusBadRequest)
+		return
+	}
+	if models.IsEmailVerified(h.DB, user.ID) {
+		http.Redirect(w, r, "/preferences", http.StatusSeeOther)
+		return
+	}
+
+	token, err := models.CreateEmailVerificationToken(h.DB, user.ID, user.Email.String)
+	if err != nil {
+		log.Printf("handlers: create email verification token for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	verifyURL := fmt.Sprintf("%s/auth/verify-email/%s", models.GetBaseURL(h.DB), token)
+	appName := models.GetAppName(h.DB)
+	htmlBody := notify.RenderEmailVerificationEmail(h.DB, verifyURL)
+	notify.SendToUser(h.DB, user.ID, appName+" — Verify Your Email", htmlBody)
+	log.Printf("handlers: email verification link sent to user %d", user.ID)
+
+	http.Redirect(w, r, "/preferences?success=Verification+link+sent.+Check+your+email.", http.StatusSeeOther)
+}
+
+// Verify marks the address a verification link was sent to as verified.
+// GET /auth/verify-email/{token}
+func (h *EmailVerification) Verify(w http.ResponseWriter, r *http.Request) {
+	user, err := models.VerifyEmail(h.DB, r.PathValue("token"))
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("handlers: verify email: %v", err)
+		}
+		h.Sessions.Put(r.Context(), "flash_error", "Invalid or expired verification link")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	log.Printf("handlers: email verified for user %q (id=%d)", user.Username, user.ID)
+
+	if h.Sessions.GetInt64(r.Context(), "userID") != 0 {
+		http.Redirect(w, r, "/preferences?success=Email+address+verified.", http.StatusSeeOther)
+		return
+	}
+	h.Sessions.Put(r.Context(), "flash_success", "Email address verified.")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
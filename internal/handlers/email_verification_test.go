@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestEmailVerification_SendAndVerify(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	db.Exec(`UPDATE users SET email = 'coach@example.com' WHERE id = ?`, coach.ID)
+	coach, _ = models.GetUserByID(db, coach.ID)
+
+	h := &EmailVerification{DB: db, Sessions: sm}
+
+	// Without a base URL there is nothing to build the link from.
+	models.SetSetting(db, "smtp.host", "smtp.example.com")
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Send)).ServeHTTP(rr, requestWithUser("POST", "/preferences/email/verify", nil, coach))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("send without base URL: expected 400, got %d", rr.Code)
+	}
+
+	models.SetSetting(db, "app.base_url", "https://replog.example.com")
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Send)).ServeHTTP(rr, requestWithUser("POST", "/preferences/email/verify", nil, coach))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("send: expected 303, got %d", rr.Code)
+	}
+
+	var tokens int
+	db.QueryRow(`SELECT COUNT(*) FROM email_verification_tokens WHERE user_id = ?`, coach.ID).Scan(&tokens)
+	if tokens != 1 {
+		t.Fatalf("verification tokens = %d, want 1", tokens)
+	}
+
+	// The emailed token isn't observable here, so issue a known one.
+	token, err := models.CreateEmailVerificationToken(db, coach.ID, "coach@example.com")
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	req := httptest.NewRequest("GET", "/auth/verify-email/"+token, nil)
+	req.SetPathValue("token", token)
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Verify)).ServeHTTP(rr, req)
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/login" {
+		t.Fatalf("verify: got %d %q, want 303 /login", rr.Code, loc)
+	}
+	if !models.IsEmailVerified(db, coach.ID) {
+		t.Error("address should be verified")
+	}
+}
@@ -73,7 +73,8 @@ func (h *Equipment) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := models.CreateEquipment(h.DB, name, r.FormValue("description"))
+	quantity, unitWeight := parseEquipmentLoad(r)
+	_, err := models.CreateEquipment(h.DB, name, r.FormValue("description"), quantity, unitWeight)
 	if errors.Is(err, models.ErrDuplicateEquipmentName) {
 		data := map[string]any{
 			"Error": "An equipment item with that name already exists",
@@ -158,7 +159,8 @@ func (h *Equipment) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = models.UpdateEquipment(h.DB, id, name, r.FormValue("description"))
+	quantity, unitWeight := parseEquipmentLoad(r)
+	_, err = models.UpdateEquipment(h.DB, id, name, r.FormValue("description"), quantity, unitWeight)
 	if errors.Is(err, models.ErrDuplicateEquipmentName) {
 		item, _ := models.GetEquipmentByID(h.DB, id)
 		data := map[string]any{
@@ -285,7 +287,7 @@ func (h *Equipment) AddAthleteEquipment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -310,6 +312,49 @@ func (h *Equipment) AddAthleteEquipment(w http.ResponseWriter, r *http.Request)
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/equipment", http.StatusSeeOther)
 }
 
+// AddAthleteEquipmentBulk adds every selected equipment item to an athlete's
+// inventory at once. Items already owned are skipped.
+func (h *Equipment) AddAthleteEquipmentBulk(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	athleteID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	var equipmentIDs []int64
+	for _, v := range r.Form["equipment_id"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+			return
+		}
+		equipmentIDs = append(equipmentIDs, id)
+	}
+
+	if _, err := models.AddAthleteEquipmentBatch(h.DB, athleteID, equipmentIDs); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			http.Error(w, "Unknown equipment ID", http.StatusBadRequest)
+			return
+		}
+		log.Printf("handlers: add athlete equipment bulk: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/equipment", http.StatusSeeOther)
+}
+
 // RemoveAthleteEquipment removes an equipment item from an athlete's inventory.
 func (h *Equipment) RemoveAthleteEquipment(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -319,7 +364,7 @@ func (h *Equipment) RemoveAthleteEquipment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -413,3 +458,13 @@ func (h *Equipment) AthleteEquipmentPage(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
+
+// parseEquipmentLoad reads the optional quantity and unit weight form fields,
+// converting the weight from the user's preferred unit to lbs.
+// Blank or invalid values fall back to the model defaults (1 item, weight
+// unspecified).
+func parseEquipmentLoad(r *http.Request) (int, float64) {
+	quantity, _ := strconv.Atoi(r.FormValue("quantity"))
+	unitWeight, _ := strconv.ParseFloat(r.FormValue("unit_weight"), 64)
+	return quantity, weightInputToLbs(r, unitWeight)
+}
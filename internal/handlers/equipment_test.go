@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/carpenike/replog/internal/models"
@@ -14,7 +16,7 @@ func TestEquipment_List_ReturnsOK(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	models.CreateEquipment(db, "Barbell", "")
+	models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 	req := requestWithUser("GET", "/equipment", nil, coach)
@@ -43,6 +45,60 @@ func TestEquipment_Create_Success(t *testing.T) {
 	}
 }
 
+func TestEquipment_Create_QuantityAndWeight(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &Equipment{DB: db, Templates: tc}
+
+	form := url.Values{"name": {"Dumbbells"}, "quantity": {"2"}, "unit_weight": {"50"}}
+	req := requestWithUser("POST", "/equipment", form, coach)
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	items, err := models.ListEquipment(db)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("list equipment: %v (%d items)", err, len(items))
+	}
+	if items[0].Quantity != 2 || !items[0].UnitWeight.Valid || items[0].UnitWeight.Float64 != 50 {
+		t.Errorf("quantity/weight = %d/%v, want 2/50", items[0].Quantity, items[0].UnitWeight)
+	}
+}
+
+func TestEquipment_UnitWeight_KgRoundTrip(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &Equipment{DB: db, Templates: tc}
+
+	form := url.Values{"name": {"Kettlebell"}, "quantity": {"1"}, "unit_weight": {"16"}}
+	req := withWeightUnit(requestWithUser("POST", "/equipment", form, coach), "kg")
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	items, _ := models.ListEquipment(db)
+	if len(items) != 1 || fmt.Sprintf("%.2f", items[0].UnitWeight.Float64) != "35.27" {
+		t.Fatalf("stored unit weight = %v, want 35.27 lbs", items[0].UnitWeight)
+	}
+
+	req = withWeightUnit(requestWithUser("GET", "/equipment/"+itoa(items[0].ID)+"/edit", nil, coach), "kg")
+	req.SetPathValue("id", itoa(items[0].ID))
+	rr = httptest.NewRecorder()
+	h.EditForm(rr, req)
+	if !strings.Contains(rr.Body.String(), `value="16"`) {
+		t.Errorf("edit form does not show 16 kg:\n%s", rr.Body.String())
+	}
+}
+
 func TestEquipment_Create_EmptyName(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -64,7 +120,7 @@ func TestEquipment_Create_DuplicateName(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	models.CreateEquipment(db, "Barbell", "")
+	models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -99,7 +155,7 @@ func TestEquipment_EditForm_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -134,7 +190,7 @@ func TestEquipment_Update_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -161,7 +217,7 @@ func TestEquipment_Update_EmptyName(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -180,7 +236,7 @@ func TestEquipment_Delete_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -216,7 +272,7 @@ func TestEquipment_AddExerciseEquipment_Success(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	ex := seedExercise(t, db, "Bench Press", "")
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -245,7 +301,7 @@ func TestEquipment_RemoveExerciseEquipment_Success(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	ex := seedExercise(t, db, "Bench Press", "")
-	eq, _ := models.CreateEquipment(db, "Barbell", "")
+	eq, _ := models.CreateEquipment(db, "Barbell", "", 0, 0)
 	models.AddExerciseEquipment(db, ex.ID, eq.ID, false)
 
 	h := &Equipment{DB: db, Templates: tc}
@@ -289,7 +345,7 @@ func TestEquipment_AddAthleteEquipment_Success(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	athlete := seedAthlete(t, db, "Test Kid", "")
-	eq, _ := models.CreateEquipment(db, "Dumbbells", "")
+	eq, _ := models.CreateEquipment(db, "Dumbbells", "", 0, 0)
 
 	h := &Equipment{DB: db, Templates: tc}
 
@@ -309,12 +365,81 @@ func TestEquipment_AddAthleteEquipment_Success(t *testing.T) {
 	}
 }
 
+func TestEquipment_AddAthleteEquipmentBulk(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Home Gym", "")
+	dumbbells, _ := models.CreateEquipment(db, "Dumbbells", "", 0, 0)
+	bench, _ := models.CreateEquipment(db, "Bench", "", 0, 0)
+	models.AddAthleteEquipment(db, athlete.ID, dumbbells.ID)
+
+	h := &Equipment{DB: db, Templates: tc}
+
+	t.Run("adds selected items", func(t *testing.T) {
+		form := url.Values{"equipment_id": {itoa(dumbbells.ID), itoa(bench.ID)}}
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/equipment/bulk", form, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.AddAthleteEquipmentBulk(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Errorf("expected 303, got %d", rr.Code)
+		}
+		items, _ := models.ListAthleteEquipment(db, athlete.ID)
+		if len(items) != 2 {
+			t.Errorf("athlete equipment count = %d, want 2", len(items))
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		form := url.Values{"equipment_id": {"abc"}}
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/equipment/bulk", form, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.AddAthleteEquipmentBulk(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		other := seedAthlete(t, db, "Garage", "")
+		form := url.Values{"equipment_id": {itoa(bench.ID), "99999"}}
+		req := requestWithUser("POST", "/athletes/"+itoa(other.ID)+"/equipment/bulk", form, coach)
+		req.SetPathValue("id", itoa(other.ID))
+		rr := httptest.NewRecorder()
+		h.AddAthleteEquipmentBulk(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+		if items, _ := models.ListAthleteEquipment(db, other.ID); len(items) != 0 {
+			t.Errorf("athlete equipment count = %d, want 0 after rejected batch", len(items))
+		}
+	})
+
+	t.Run("unlinked non-coach forbidden", func(t *testing.T) {
+		nonCoach := seedUnlinkedNonCoach(t, db)
+		form := url.Values{"equipment_id": {itoa(bench.ID)}}
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/equipment/bulk", form, nonCoach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.AddAthleteEquipmentBulk(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
 func TestEquipment_RemoveAthleteEquipment_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	athlete := seedAthlete(t, db, "Test Kid", "")
-	eq, _ := models.CreateEquipment(db, "Dumbbells", "")
+	eq, _ := models.CreateEquipment(db, "Dumbbells", "", 0, 0)
 	models.AddAthleteEquipment(db, athlete.ID, eq.ID)
 
 	h := &Equipment{DB: db, Templates: tc}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/thumbnails"
+)
+
+// thumbPlaceholder is served in place of a demo thumbnail that couldn't be
+// fetched: a play button on a grey 16:9 background.
+const thumbPlaceholder = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 160 90">` +
+	`<rect width="160" height="90" fill="#e5e7eb"/>` +
+	`<circle cx="80" cy="45" r="20" fill="#9ca3af"/>` +
+	`<path d="M73 34 L93 45 L73 56 Z" fill="#fff"/></svg>`
+
+// ExerciseThumbs serves cached thumbnails for exercise demo videos.
+type ExerciseThumbs struct {
+	DB         *sql.DB
+	Thumbnails *thumbnails.Cache
+}
+
+// Serve returns the thumbnail for an exercise's YouTube demo video, fetching
+// and caching it on first use. Serves a placeholder if it can't be fetched.
+// GET /exercise-thumbs/{id}
+func (h *ExerciseThumbs) Serve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	exercise, err := models.GetExerciseByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get exercise %d for thumbnail: %v", id, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	videoID := models.YouTubeVideoID(exercise.DemoURL.String)
+	if videoID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	path, err := h.Thumbnails.Get(r.Context(), videoID)
+	if err != nil {
+		if !errors.Is(err, thumbnails.ErrRecentFailure) {
+			log.Printf("handlers: %v", err)
+		}
+		// Short cache so the real thumbnail shows up soon after the fetch
+		// starts working again.
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte(thumbPlaceholder))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	http.ServeFile(w, r, path)
+}
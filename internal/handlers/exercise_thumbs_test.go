@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/thumbnails"
+)
+
+func TestExerciseThumbsServe(t *testing.T) {
+	db := testDB(t)
+
+	jpegData := createTestJPEG(t, 8, 6, 1)
+	ytimg := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(jpegData)
+	}))
+	defer ytimg.Close()
+
+	cache := thumbnails.New(t.TempDir())
+	cache.BaseURL = ytimg.URL + "/vi/"
+	h := &ExerciseThumbs{DB: db, Thumbnails: cache}
+
+	youtube, _ := models.CreateExercise(db, "Squat", "", "", "", "https://youtu.be/dQw4w9WgXcQ", 0)
+	missing, _ := models.CreateExercise(db, "Lunge", "", "", "", "https://youtu.be/aaaaaaaaaaa", 0)
+	vimeo, _ := models.CreateExercise(db, "Bench", "", "", "", "https://vimeo.com/76979871", 0)
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/exercise-thumbs/"+id, nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		h.Serve(rr, req)
+		return rr
+	}
+
+	t.Run("fetches and serves thumbnail", func(t *testing.T) {
+		rr := serve(itoa(youtube.ID))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "image/jpeg" {
+			t.Errorf("Content-Type = %q, want image/jpeg", ct)
+		}
+	})
+
+	t.Run("placeholder when fetch fails", func(t *testing.T) {
+		rr := serve(itoa(missing.ID))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "image/svg+xml" {
+			t.Errorf("Content-Type = %q, want image/svg+xml", ct)
+		}
+	})
+
+	t.Run("not a YouTube demo", func(t *testing.T) {
+		if rr := serve(itoa(vimeo.ID)); rr.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("unknown exercise", func(t *testing.T) {
+		if rr := serve("99999"); rr.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+}
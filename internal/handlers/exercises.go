@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
@@ -19,9 +22,12 @@ type Exercises struct {
 
 // List renders the exercise list page.
 func (h *Exercises) List(w http.ResponseWriter, r *http.Request) {
-	tierFilter := r.URL.Query().Get("tier")
+	filter := models.ExerciseFilter{
+		Tier:        r.URL.Query().Get("tier"),
+		MuscleGroup: r.URL.Query().Get("muscle_group"),
+	}
 
-	exercises, err := models.ListExercises(h.DB, tierFilter)
+	exercises, err := models.ListExercises(h.DB, filter)
 	if err != nil {
 		log.Printf("handlers: list exercises: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -29,10 +35,23 @@ func (h *Exercises) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]any{
-		"Exercises":  exercises,
-		"TierFilter": tierFilter,
-		"Tiers":      tierFilterOptions(),
+		"Exercises":         exercises,
+		"TierFilter":        filter.Tier,
+		"Tiers":             tierFilterOptions(),
+		"MuscleGroupFilter": filter.MuscleGroup,
+		"MuscleGroups":      muscleGroupFilterOptions(),
 	}
+
+	if user := middleware.UserFromContext(r.Context()); user != nil && user.IsCoach {
+		archived, err := models.ListArchivedExercises(h.DB)
+		if err != nil {
+			log.Printf("handlers: list archived exercises: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		data["ArchivedExercises"] = archived
+	}
+
 	if err := h.Templates.Render(w, r, "exercises_list.html", data); err != nil {
 		log.Printf("handlers: exercises list template: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -56,6 +75,7 @@ func (h *Exercises) NewForm(w http.ResponseWriter, r *http.Request) {
 
 	data := map[string]any{
 		"Tiers":            tierOptions(),
+		"MuscleGroups":     muscleGroupOptions(),
 		"AllEquipment":     allEquipment,
 		"SelectedRequired": map[int64]bool{},
 		"SelectedOptional": map[int64]bool{},
@@ -86,6 +106,7 @@ func (h *Exercises) Create(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"Error":            "Name is required",
 			"Tiers":            tierOptions(),
+			"MuscleGroups":     muscleGroupOptions(),
 			"Form":             r.Form,
 			"AllEquipment":     allEquipment,
 			"SelectedRequired": idSliceToMap(reqIDs),
@@ -102,11 +123,12 @@ func (h *Exercises) Create(w http.ResponseWriter, r *http.Request) {
 	allEquipment, _ := models.ListEquipment(h.DB)
 	reqIDs, optIDs := parseEquipmentSelections(r)
 
-	exercise, err := models.CreateExercise(h.DB, name, r.FormValue("tier"), r.FormValue("form_notes"), r.FormValue("demo_url"), restSeconds, featured)
-	if errors.Is(err, models.ErrDuplicateExerciseName) {
+	exercise, err := models.CreateExercise(h.DB, name, r.FormValue("tier"), formMuscleGroup(r), r.FormValue("form_notes"), r.FormValue("demo_url"), restSeconds, featured)
+	if msg := exerciseFormError(err); msg != "" {
 		data := map[string]any{
-			"Error":            "An exercise with that name already exists",
+			"Error":            msg,
 			"Tiers":            tierOptions(),
+			"MuscleGroups":     muscleGroupOptions(),
 			"Form":             r.Form,
 			"AllEquipment":     allEquipment,
 			"SelectedRequired": idSliceToMap(reqIDs),
@@ -122,6 +144,10 @@ func (h *Exercises) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := models.SetExerciseUnilateral(h.DB, exercise.ID, r.FormValue("unilateral") == "1"); err != nil {
+		log.Printf("handlers: set unilateral on create: %v", err)
+	}
+
 	if err := models.SyncExerciseEquipment(h.DB, exercise.ID, reqIDs, optIDs); err != nil {
 		log.Printf("handlers: sync exercise equipment on create: %v", err)
 	}
@@ -262,12 +288,22 @@ func (h *Exercises) EditForm(w http.ResponseWriter, r *http.Request) {
 	exEquip, _ := models.ListExerciseEquipment(h.DB, exercise.ID)
 	reqMap, optMap := exerciseEquipmentToMaps(exEquip)
 
+	aliases, err := models.ListExerciseAliases(h.DB, exercise.ID)
+	if err != nil {
+		log.Printf("handlers: list aliases for exercise %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]any{
 		"Exercise":         exercise,
 		"Tiers":            tierOptions(),
+		"MuscleGroups":     muscleGroupOptions(),
 		"AllEquipment":     allEquipment,
 		"SelectedRequired": reqMap,
 		"SelectedOptional": optMap,
+		"Aliases":          aliases,
+		"AliasError":       r.URL.Query().Get("alias_error"),
 	}
 	if err := h.Templates.Render(w, r, "exercise_form.html", data); err != nil {
 		log.Printf("handlers: exercise edit form template: %v", err)
@@ -304,6 +340,7 @@ func (h *Exercises) Update(w http.ResponseWriter, r *http.Request) {
 			"Error":            "Name is required",
 			"Exercise":         exercise,
 			"Tiers":            tierOptions(),
+			"MuscleGroups":     muscleGroupOptions(),
 			"Form":             r.Form,
 			"AllEquipment":     allEquipment,
 			"SelectedRequired": idSliceToMap(reqIDs),
@@ -317,17 +354,18 @@ func (h *Exercises) Update(w http.ResponseWriter, r *http.Request) {
 	restSeconds, _ := strconv.Atoi(r.FormValue("rest_seconds"))
 	featured := r.FormValue("featured") == "1"
 
-	_, err = models.UpdateExercise(h.DB, id, name, r.FormValue("tier"), r.FormValue("form_notes"), r.FormValue("demo_url"), restSeconds, featured)
+	_, err = models.UpdateExercise(h.DB, id, name, r.FormValue("tier"), formMuscleGroup(r), r.FormValue("form_notes"), r.FormValue("demo_url"), restSeconds, featured)
 	if errors.Is(err, models.ErrNotFound) {
 		http.Error(w, "Exercise not found", http.StatusNotFound)
 		return
 	}
-	if errors.Is(err, models.ErrDuplicateExerciseName) {
+	if msg := exerciseFormError(err); msg != "" {
 		exercise, _ := models.GetExerciseByID(h.DB, id)
 		data := map[string]any{
-			"Error":            "An exercise with that name already exists",
+			"Error":            msg,
 			"Exercise":         exercise,
 			"Tiers":            tierOptions(),
+			"MuscleGroups":     muscleGroupOptions(),
 			"Form":             r.Form,
 			"AllEquipment":     allEquipment,
 			"SelectedRequired": idSliceToMap(reqIDs),
@@ -343,6 +381,10 @@ func (h *Exercises) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := models.SetExerciseUnilateral(h.DB, id, r.FormValue("unilateral") == "1"); err != nil {
+		log.Printf("handlers: set unilateral on update %d: %v", id, err)
+	}
+
 	if err := models.SyncExerciseEquipment(h.DB, id, reqIDs, optIDs); err != nil {
 		log.Printf("handlers: sync exercise equipment on update %d: %v", id, err)
 	}
@@ -350,7 +392,9 @@ func (h *Exercises) Update(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/exercises/"+strconv.FormatInt(id, 10), http.StatusSeeOther)
 }
 
-// Delete removes an exercise. Coach only.
+// Delete removes an exercise. Exercises with workout history, training maxes,
+// assignments, or program references are archived instead so that history
+// stays intact. Coach only.
 func (h *Exercises) Delete(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
 	if !user.IsCoach {
@@ -370,12 +414,7 @@ func (h *Exercises) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if errors.Is(err, models.ErrExerciseInUse) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		w.WriteHeader(http.StatusConflict)
-		if err := h.Templates.RenderErrorFragment(w, "Cannot delete — exercise has been logged in workouts."); err != nil {
-			log.Printf("handlers: delete exercise error template: %v", err)
-		}
-		return
+		err = models.SetExerciseArchived(h.DB, id, true)
 	}
 	if err != nil {
 		log.Printf("handlers: delete exercise %d: %v", id, err)
@@ -386,6 +425,126 @@ func (h *Exercises) Delete(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/exercises", http.StatusSeeOther)
 }
 
+// Unarchive restores an archived exercise to the catalog. Coach only.
+func (h *Exercises) Unarchive(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	err = models.SetExerciseArchived(h.DB, id, false)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Exercise not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: unarchive exercise %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/exercises/"+strconv.FormatInt(id, 10), http.StatusSeeOther)
+}
+
+// AddAlias adds an alternate name to an exercise. Coach only.
+func (h *Exercises) AddAlias(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := models.GetExerciseByID(h.DB, id); errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Exercise not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("handlers: get exercise %d for alias: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	editURL := "/exercises/" + strconv.FormatInt(id, 10) + "/edit"
+	_, err = models.AddExerciseAlias(h.DB, id, r.FormValue("alias"))
+	switch {
+	case errors.Is(err, models.ErrInvalidInput):
+		http.Redirect(w, r, editURL+"?alias_error="+url.QueryEscape("Alias is required"), http.StatusSeeOther)
+		return
+	case errors.Is(err, models.ErrDuplicateAlias):
+		http.Redirect(w, r, editURL+"?alias_error="+url.QueryEscape("That name is already an exercise or alias"), http.StatusSeeOther)
+		return
+	case err != nil:
+		log.Printf("handlers: add alias to exercise %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, editURL, http.StatusSeeOther)
+}
+
+// DeleteAlias removes an alternate name from an exercise. Coach only.
+func (h *Exercises) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	aliasID, err := strconv.ParseInt(r.PathValue("aliasID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid alias ID", http.StatusBadRequest)
+		return
+	}
+
+	err = models.DeleteExerciseAlias(h.DB, id, aliasID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Alias not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: delete alias %d: %v", aliasID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/exercises/"+strconv.FormatInt(id, 10)+"/edit", http.StatusSeeOther)
+}
+
+// exerciseFormError maps validation errors from CreateExercise and
+// UpdateExercise to a message for the form, or "" for other errors.
+func exerciseFormError(err error) string {
+	switch {
+	case errors.Is(err, models.ErrDuplicateExerciseName):
+		return "An exercise or alias with that name already exists"
+	case errors.Is(err, models.ErrInvalidDemoURL):
+		return "Demo video URL must start with http:// or https://"
+	}
+	return ""
+}
+
 func tierFilterOptions() []struct{ Value, Label string } {
 	return []struct{ Value, Label string }{
 		{"", "All Tiers"},
@@ -396,6 +555,35 @@ func tierFilterOptions() []struct{ Value, Label string } {
 	}
 }
 
+// muscleGroupOptions returns the muscle group choices for the exercise form.
+func muscleGroupOptions() []struct{ Value, Label string } {
+	opts := []struct{ Value, Label string }{{"", "— None —"}}
+	for _, g := range models.MuscleGroups {
+		opts = append(opts, struct{ Value, Label string }{g, muscleGroupLabel(g)})
+	}
+	return opts
+}
+
+// muscleGroupFilterOptions returns the muscle group filters for the exercise
+// list, including "All" and "Uncategorized".
+func muscleGroupFilterOptions() []struct{ Value, Label string } {
+	opts := []struct{ Value, Label string }{{"", "All Groups"}}
+	for _, g := range models.MuscleGroups {
+		opts = append(opts, struct{ Value, Label string }{g, muscleGroupLabel(g)})
+	}
+	return append(opts, struct{ Value, Label string }{"none", "Uncategorized"})
+}
+
+// formMuscleGroup returns the submitted muscle group, or "" if it is not
+// one of models.MuscleGroups.
+func formMuscleGroup(r *http.Request) string {
+	g := r.FormValue("muscle_group")
+	if !models.IsValidMuscleGroup(g) {
+		return ""
+	}
+	return g
+}
+
 // ExerciseHistory renders the exercise history for a specific athlete+exercise.
 func (h *Exercises) ExerciseHistory(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -457,6 +645,12 @@ func (h *Exercises) ExerciseHistory(w http.ResponseWriter, r *http.Request) {
 		log.Printf("handlers: exercise volume chart for athlete %d exercise %d: %v", athleteID, exerciseID, chartErr)
 	}
 
+	// Load best estimated 1RM (all-time and last 30 days).
+	oneRepMax, e1rmErr := models.BestEstimated1RM(h.DB, athleteID, exerciseID)
+	if e1rmErr != nil {
+		log.Printf("handlers: best e1rm for athlete %d exercise %d: %v", athleteID, exerciseID, e1rmErr)
+	}
+
 	data := map[string]any{
 		"Athlete":     athlete,
 		"Exercise":    exercise,
@@ -464,6 +658,7 @@ func (h *Exercises) ExerciseHistory(w http.ResponseWriter, r *http.Request) {
 		"HasMore":     page.HasMore,
 		"NextOffset":  offset + models.ExerciseHistoryPageSize,
 		"VolumeChart": volumeChart,
+		"OneRepMax":   oneRepMax,
 	}
 	if err := h.Templates.Render(w, r, "exercise_history.html", data); err != nil {
 		log.Printf("handlers: exercise history template: %v", err)
@@ -471,6 +666,52 @@ func (h *Exercises) ExerciseHistory(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// VolumeJSON returns weekly volume (sets, reps, tonnage) for one exercise as
+// JSON. The optional weeks query parameter controls the window (default 12).
+func (h *Exercises) VolumeJSON(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	exerciseID, err := strconv.ParseInt(r.PathValue("exerciseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := models.GetExerciseByID(h.DB, exerciseID); errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Exercise not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("handlers: get exercise %d for volume: %v", exerciseID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	weeks := 12
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 104 {
+			http.Error(w, "weeks must be between 1 and 104", http.StatusBadRequest)
+			return
+		}
+		weeks = n
+	}
+
+	points, err := models.WeeklyVolume(h.DB, athleteID, exerciseID, weeks)
+	if err != nil {
+		log.Printf("handlers: weekly volume for athlete %d exercise %d: %v", athleteID, exerciseID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(points); err != nil {
+		log.Printf("handlers: encode weekly volume JSON: %v", err)
+	}
+}
+
 // parseEquipmentSelections reads equipment_ids and equipment_type_{id} fields
 // from the form and returns required and optional equipment ID slices.
 func parseEquipmentSelections(r *http.Request) (required, optional []int64) {
@@ -511,3 +752,15 @@ func idSliceToMap(ids []int64) map[int64]bool {
 	}
 	return m
 }
+
+// muscleGroupLabel returns the display label for a muscle group value.
+func muscleGroupLabel(g string) string {
+	switch g {
+	case "full_body":
+		return "Full Body"
+	case "":
+		return ""
+	default:
+		return strings.ToUpper(g[:1]) + g[1:]
+	}
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/carpenike/replog/internal/models"
 )
@@ -56,6 +59,27 @@ func TestExercises_List_FilterByTier(t *testing.T) {
 	}
 }
 
+func TestExercises_List_FilterByMuscleGroup(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	models.CreateExercise(db, "Bench Press", "", "push", "", "", 0)
+	models.CreateExercise(db, "Pull-ups", "", "pull", "", "", 0)
+
+	h := &Exercises{DB: db, Templates: tc}
+	req := requestWithUser("GET", "/exercises?muscle_group=pull", nil, coach)
+	rr := httptest.NewRecorder()
+	h.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Pull-ups") || strings.Contains(body, "Bench Press") {
+		t.Error("expected only pull exercises in list")
+	}
+}
+
 func TestExercises_Create_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -63,7 +87,7 @@ func TestExercises_Create_Success(t *testing.T) {
 
 	h := &Exercises{DB: db, Templates: tc}
 
-	form := url.Values{"name": {"Bulgarian Split Squat"}, "tier": {"foundational"}}
+	form := url.Values{"name": {"Bulgarian Split Squat"}, "tier": {"foundational"}, "muscle_group": {"legs"}, "unilateral": {"1"}}
 	req := requestWithUser("POST", "/exercises", form, coach)
 	rr := httptest.NewRecorder()
 	h.Create(rr, req)
@@ -71,6 +95,14 @@ func TestExercises_Create_Success(t *testing.T) {
 	if rr.Code != http.StatusSeeOther {
 		t.Errorf("expected 303, got %d", rr.Code)
 	}
+
+	exercises, _ := models.ListExercises(db, models.ExerciseFilter{MuscleGroup: "legs"})
+	if len(exercises) != 1 {
+		t.Fatalf("legs exercises = %d, want 1", len(exercises))
+	}
+	if !exercises[0].Unilateral {
+		t.Error("expected exercise to be marked unilateral")
+	}
 }
 
 func TestExercises_Create_EmptyName(t *testing.T) {
@@ -108,6 +140,26 @@ func TestExercises_Create_DuplicateName(t *testing.T) {
 	}
 }
 
+func TestExercises_Create_InvalidDemoURL(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &Exercises{DB: db, Templates: tc}
+
+	form := url.Values{"name": {"Squat"}, "demo_url": {"javascript:alert(1)"}}
+	req := requestWithUser("POST", "/exercises", form, coach)
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Demo video URL") {
+		t.Error("expected demo URL error message")
+	}
+}
+
 func TestExercises_Create_NonCoachForbidden(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -205,7 +257,7 @@ func TestExercises_Delete_Success(t *testing.T) {
 	}
 }
 
-func TestExercises_Delete_InUseReturnsConflict(t *testing.T) {
+func TestExercises_Delete_InUseArchives(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
@@ -229,8 +281,53 @@ func TestExercises_Delete_InUseReturnsConflict(t *testing.T) {
 	rr := httptest.NewRecorder()
 	h.Delete(rr, req)
 
-	if rr.Code != http.StatusConflict {
-		t.Errorf("expected 409 Conflict, got %d", rr.Code)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	got, err := models.GetExerciseByID(db, ex.ID)
+	if err != nil {
+		t.Fatalf("exercise should still exist: %v", err)
+	}
+	if !got.Archived {
+		t.Error("expected in-use exercise to be archived")
+	}
+
+	// Archived exercises show in their own section and unarchive restores them.
+	listReq := requestWithUser("GET", "/exercises", nil, coach)
+	rr = httptest.NewRecorder()
+	h.List(rr, listReq)
+	if !strings.Contains(rr.Body.String(), "/exercises/"+itoa(ex.ID)+"/unarchive") {
+		t.Error("expected archived section with unarchive action")
+	}
+
+	req = requestWithUser("POST", "/exercises/"+itoa(ex.ID)+"/unarchive", nil, coach)
+	req.SetPathValue("id", itoa(ex.ID))
+	rr = httptest.NewRecorder()
+	h.Unarchive(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("unarchive: expected 303, got %d", rr.Code)
+	}
+	got, _ = models.GetExerciseByID(db, ex.ID)
+	if got.Archived {
+		t.Error("expected exercise to be unarchived")
+	}
+}
+
+func TestExercises_Unarchive_NonCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	nonCoach := seedUnlinkedNonCoach(t, db)
+	ex := seedExercise(t, db, "Squat", "")
+	models.SetExerciseArchived(db, ex.ID, true)
+
+	h := &Exercises{DB: db, Templates: tc}
+	req := requestWithUser("POST", "/exercises/"+itoa(ex.ID)+"/unarchive", nil, nonCoach)
+	req.SetPathValue("id", itoa(ex.ID))
+	rr := httptest.NewRecorder()
+	h.Unarchive(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
 	}
 }
 
@@ -254,6 +351,79 @@ func TestExercises_ExerciseHistory_CoachCanView(t *testing.T) {
 	}
 }
 
+func TestExercises_ExerciseHistory_ShowsEstimated1RM(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+	ex := seedExercise(t, db, "Squat", "")
+	workout, _ := models.CreateWorkout(db, athlete.ID, time.Now().Format("2006-01-02"), "", 0)
+	models.AddSet(db, workout.ID, ex.ID, 5, 300, 0, "", "", "")
+
+	h := &Exercises{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/exercises/"+itoa(ex.ID)+"/history", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("exerciseID", itoa(ex.ID))
+	rr := httptest.NewRecorder()
+	h.ExerciseHistory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Estimated 1RM") || !strings.Contains(body, "350") {
+		t.Errorf("expected estimated 1RM of 350 in body")
+	}
+}
+
+func TestExercises_VolumeJSON(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	kid := seedNonCoach(t, db, alice.ID)
+	ex := seedExercise(t, db, "Squat", "")
+	workout, _ := models.CreateWorkout(db, alice.ID, time.Now().Format("2006-01-02"), "", 0)
+	models.AddSet(db, workout.ID, ex.ID, 5, 200, 0, "", "", "")
+
+	h := &Exercises{DB: db, Templates: tc}
+
+	tests := []struct {
+		name      string
+		athleteID int64
+		query     string
+		wantCode  int
+	}{
+		{"own athlete", alice.ID, "?weeks=4", http.StatusOK},
+		{"invalid weeks", alice.ID, "?weeks=0", http.StatusBadRequest},
+		{"other athlete", bob.ID, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("GET", "/athletes/"+itoa(tt.athleteID)+"/exercises/"+itoa(ex.ID)+"/volume.json"+tt.query, nil, kid)
+			req.SetPathValue("id", itoa(tt.athleteID))
+			req.SetPathValue("exerciseID", itoa(ex.ID))
+			rr := httptest.NewRecorder()
+			h.VolumeJSON(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var points []models.VolumePoint
+			if err := json.Unmarshal(rr.Body.Bytes(), &points); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(points) != 4 || points[3].Tonnage != 1000 {
+				t.Errorf("points = %+v, want 4 weeks ending with 1000 tonnage", points)
+			}
+		})
+	}
+}
+
 func TestExercises_NewForm_CoachCanView(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -511,3 +681,71 @@ func TestExercises_Show_NonCoachCanView(t *testing.T) {
 		t.Errorf("expected 200, got %d", rr.Code)
 	}
 }
+
+func TestExercises_Aliases(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	nonCoach := seedUnlinkedNonCoach(t, db)
+	ex := seedExercise(t, db, "Back Squat", "")
+	seedExercise(t, db, "Front Squat", "")
+
+	h := &Exercises{DB: db, Templates: tc}
+
+	addAlias := func(user *models.User, alias string) *httptest.ResponseRecorder {
+		req := requestWithUser("POST", "/exercises/"+itoa(ex.ID)+"/aliases", url.Values{"alias": {alias}}, user)
+		req.SetPathValue("id", itoa(ex.ID))
+		rr := httptest.NewRecorder()
+		h.AddAlias(rr, req)
+		return rr
+	}
+
+	t.Run("non-coach forbidden", func(t *testing.T) {
+		if rr := addAlias(nonCoach, "Barbell Squat"); rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("add and show on edit page", func(t *testing.T) {
+		rr := addAlias(coach, "Barbell Squat")
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); strings.Contains(loc, "alias_error") {
+			t.Fatalf("location = %q, want no error", loc)
+		}
+
+		req := requestWithUser("GET", "/exercises/"+itoa(ex.ID)+"/edit", nil, coach)
+		req.SetPathValue("id", itoa(ex.ID))
+		rr = httptest.NewRecorder()
+		h.EditForm(rr, req)
+		if !strings.Contains(rr.Body.String(), "Barbell Squat") {
+			t.Error("expected alias on edit page")
+		}
+	})
+
+	t.Run("alias matching another exercise name", func(t *testing.T) {
+		rr := addAlias(coach, "front squat")
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "alias_error=") {
+			t.Errorf("location = %q, want alias_error", loc)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		aliases, _ := models.ListExerciseAliases(db, ex.ID)
+		if len(aliases) != 1 {
+			t.Fatalf("aliases = %d, want 1", len(aliases))
+		}
+		req := requestWithUser("POST", "/exercises/"+itoa(ex.ID)+"/aliases/"+itoa(aliases[0].ID)+"/delete", nil, coach)
+		req.SetPathValue("id", itoa(ex.ID))
+		req.SetPathValue("aliasID", itoa(aliases[0].ID))
+		rr := httptest.NewRecorder()
+		h.DeleteAlias(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if aliases, _ := models.ListExerciseAliases(db, ex.ID); len(aliases) != 0 {
+			t.Errorf("aliases = %d after delete, want 0", len(aliases))
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"sort"
@@ -19,7 +20,9 @@ import (
 	"github.com/alexedwards/scs/v2"
 	"github.com/carpenike/replog/internal/importers"
 	"github.com/carpenike/replog/internal/llm"
+	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
 )
 
 func init() {
@@ -35,6 +38,9 @@ type Generate struct {
 	// Rate limiting: track in-flight generations per athlete.
 	mu        sync.Mutex
 	inflight  map[int64]time.Time
+
+	// Output of in-flight generation runs, keyed by run ID, for Stream.
+	streams map[int64]*generationStream
 }
 
 // Form renders the program generation form for an athlete.
@@ -61,7 +67,7 @@ func (h *Generate) Form(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build athlete context for the LLM preview panel.
-	athleteCtx, ctxErr := llm.BuildAthleteContext(h.DB, athleteID, time.Now())
+	athleteCtx, ctxErr := llm.BuildAthleteContextWithOptions(h.DB, athleteID, time.Now(), llm.ContextOptions{IncludePrivateNotes: true})
 	if ctxErr != nil {
 		log.Printf("handlers: build context preview for athlete %d: %v", athleteID, ctxErr)
 	}
@@ -82,6 +88,7 @@ func (h *Generate) Form(w http.ResponseWriter, r *http.Request) {
 		"IsLoop":            isLoop,
 		"Context":           athleteCtx,
 		"ReferencePrograms": refPrograms,
+		"ContextOptions":    llm.ContextOptions{},
 	}
 	if err := h.Templates.Render(w, r, "generate_form.html", data); err != nil {
 		log.Printf("handlers: render generate form: %v", err)
@@ -89,8 +96,9 @@ func (h *Generate) Form(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Submit handles the generation form POST: assembles context, calls LLM,
-// parses CatalogJSON into import mappings, and redirects to preview.
+// Submit handles the generation form POST: validates the request, records a
+// queued generation run, and starts the LLM call in the background. The coach
+// is redirected to the run's status page, which polls until it finishes.
 func (h *Generate) Submit(w http.ResponseWriter, r *http.Request) {
 	athlete, athleteID, ok := h.loadAthlete(w, r)
 	if !ok {
@@ -98,14 +106,15 @@ func (h *Generate) Submit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Rate limiting: prevent concurrent/rapid generations for the same athlete.
+	// The slot is released by the background run once it finishes.
 	if !h.acquireSlot(athleteID) {
 		h.renderFormError(w, r, athlete, llm.GenerationRequest{AthleteID: athleteID},
 			"A generation is already in progress for this athlete. Please wait for it to complete before starting another.")
 		return
 	}
-	defer h.releaseSlot(athleteID)
 
 	if err := r.ParseForm(); err != nil {
+		h.releaseSlot(athleteID)
 		http.Error(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
@@ -151,50 +160,130 @@ func (h *Generate) Submit(w http.ResponseWriter, r *http.Request) {
 		FocusAreas:           focusAreas,
 		CoachDirections:      coachDirections,
 		ReferenceTemplateIDs: refTemplateIDs,
+		ContextOptions:       contextOptionsFromForm(r),
 	}
+	// Coach-initiated generation may use the coach's private notes.
+	req.IncludePrivateNotes = true
 
 	// Create provider from settings.
 	provider, err := llm.NewProviderFromSettings(h.DB)
 	if err != nil {
+		h.releaseSlot(athleteID)
 		log.Printf("handlers: create LLM provider: %v", err)
 		h.renderFormError(w, r, athlete, req,
 			"AI Coach is not configured. Please ask an administrator to configure it in Settings.")
 		return
 	}
 
-	// Call the LLM — large prompts with 16k max tokens can take 2–4 minutes.
-	ctx, cancel := context.WithTimeout(r.Context(), 300*time.Second)
+	// Enforce the admin's monthly token cap before spending more.
+	if msg := h.tokenCapMessage(); msg != "" {
+		h.releaseSlot(athleteID)
+		h.renderFormError(w, r, athlete, req, msg)
+		return
+	}
+
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		h.releaseSlot(athleteID)
+		log.Printf("handlers: marshal generation request for athlete %d: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	var userID int64
+	if user := middleware.UserFromContext(r.Context()); user != nil {
+		userID = user.ID
+	}
+
+	run, err := models.CreateGenerationRun(h.DB, athleteID, userID, string(reqJSON))
+	if err != nil {
+		h.releaseSlot(athleteID)
+		log.Printf("handlers: create generation run for athlete %d: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	// Open the stream before the run starts so the status page can attach
+	// to it as soon as it loads.
+	stream := h.openStream(run.ID)
+	go h.runGeneration(run.ID, userID, provider, req, stream)
+
+	http.Redirect(w, r, generationRunURL(athleteID, run.ID), http.StatusSeeOther)
+}
+
+// runGeneration calls the LLM for a queued run and records the outcome. It
+// runs detached from the request, so it uses its own timeout — large prompts
+// with 16k max tokens can take 2–4 minutes.
+func (h *Generate) runGeneration(runID, userID int64, provider llm.Provider, req llm.GenerationRequest, stream *generationStream) {
+	athleteID := req.AthleteID
+	defer h.releaseSlot(athleteID)
+	// Deferred after releaseSlot so it runs first, but only once the run's
+	// outcome is in the database — Stream's "done" event triggers a status check.
+	defer h.closeStream(runID, stream)
+
+	if err := models.MarkGenerationRunRunning(h.DB, runID); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+
+	subject := req.ProgramName
+	if req.RegenerateScope != nil {
+		subject = fmt.Sprintf("%s (%s)", req.ProgramName, req.RegenerateScope)
+	}
+
+	fail := func(msg string) {
+		if err := models.FailGenerationRun(h.DB, runID, msg); err != nil {
+			log.Printf("handlers: %v", err)
+		}
+		notify.Send(h.DB, notify.Request{
+			UserID:    userID,
+			Type:      models.NotifyGenerationFailed,
+			Title:     "Program generation failed",
+			Message:   fmt.Sprintf("%s could not be generated.", subject),
+			Link:      generationRunURL(athleteID, runID),
+			AthleteID: sql.NullInt64{Int64: athleteID, Valid: true},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
 	defer cancel()
 
-	log.Printf("handlers: starting LLM generation for athlete %d (%s, %d days, %d weeks)",
-		athleteID, programName, numDays, numWeeks)
-	result, err := llm.Generate(ctx, h.DB, provider, req)
+	log.Printf("handlers: starting LLM generation run %d for athlete %d (%s, %d days, %d weeks)",
+		runID, athleteID, req.ProgramName, req.NumDays, req.NumWeeks)
+	result, err := llm.GenerateStreaming(ctx, h.DB, provider, req, stream.append)
 	if err != nil {
 		log.Printf("handlers: generate program for athlete %d: %v", athleteID, err)
 		var apiErr *llm.APIError
 		switch {
 		case errors.As(err, &apiErr):
-			h.renderFormError(w, r, athlete, req, apiErr.UserMessage())
+			fail(apiErr.UserMessage())
 		case errors.Is(err, context.DeadlineExceeded):
-			h.renderFormError(w, r, athlete, req,
-				"Generation timed out. The AI provider took too long to respond. Please try again or simplify your directions.")
+			fail("Generation timed out. The AI provider took too long to respond. Please try again or simplify your directions.")
 		case errors.Is(err, context.Canceled):
-			h.renderFormError(w, r, athlete, req,
-				"Generation was canceled. Please try again.")
+			fail("Generation was canceled. Please try again.")
 		default:
-			h.renderFormError(w, r, athlete, req,
-				fmt.Sprintf("Generation failed: %v", err))
+			fail(fmt.Sprintf("Generation failed: %v", err))
 		}
 		return
 	}
 
-	log.Printf("handlers: LLM generation complete for athlete %d: model=%s tokens=%d duration=%s catalog_bytes=%d stop_reason=%s",
-		athleteID, result.Model, result.TokensUsed, result.Duration, len(result.CatalogJSON), result.StopReason)
+	if result.ContextTrim.Trimmed() {
+		log.Printf("handlers: trimmed context for generation run %d: %s", runID, result.ContextTrim)
+	}
+
+	if err := models.RecordGenerationResponse(h.DB, runID, string(result.ContextJSON), result.RawResponse, result.Model, result.TokensUsed); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	cost := models.GenerationCostUSD(h.DB, result.Model, result.TokensUsed)
+	if err := models.RecordGenerationUsage(h.DB, runID, userID, result.Model, result.TokensUsed, cost); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+
+	log.Printf("handlers: LLM generation complete for athlete %d: model=%s tokens=%d duration=%s catalog_bytes=%d stop_reason=%s continuations=%d",
+		athleteID, result.Model, result.TokensUsed, result.Duration, len(result.CatalogJSON), result.StopReason, result.Continuations)
 
 	// Check for output truncation — the model ran out of tokens before completing JSON.
 	isTruncated := result.StopReason == "max_tokens" || result.StopReason == "length"
 
-	// Parse CatalogJSON into import structures.
 	if len(result.CatalogJSON) == 0 {
 		log.Printf("handlers: empty CatalogJSON from LLM for athlete %d, raw response length=%d stop_reason=%s",
 			athleteID, len(result.RawResponse), result.StopReason)
@@ -220,19 +309,294 @@ func (h *Generate) Submit(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMsg = "The AI Coach did not return valid program data. Please try again with different directions."
 		}
-		h.renderFormError(w, r, athlete, req, errMsg)
+		fail(errMsg)
+		return
+	}
+
+	// Validate the CatalogJSON now so a bad response fails the run rather
+	// than the preview.
+	if _, err := importers.ParseCatalogJSON(bytes.NewReader(result.CatalogJSON)); err != nil {
+		log.Printf("handlers: parse LLM CatalogJSON: %v", err)
+		fail(fmt.Sprintf("The AI Coach returned invalid data: %v. Please try again.", err))
+		return
+	}
+
+	if len(result.Issues) > 0 {
+		log.Printf("handlers: generation run %d has %d catalog issues (fatal=%t)", runID, len(result.Issues), llm.HasFatalIssues(result.Issues))
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("handlers: marshal generation result for run %d: %v", runID, err)
+		fail(fmt.Sprintf("Generation failed: %v", err))
+		return
+	}
+	if err := models.CompleteGenerationRun(h.DB, runID, string(resultJSON)); err != nil {
+		log.Printf("handlers: %v", err)
+		return
+	}
+
+	notify.Send(h.DB, notify.Request{
+		UserID:    userID,
+		Type:      models.NotifyGenerationSucceeded,
+		Title:     "Program ready for review",
+		Message:   fmt.Sprintf("%s has been generated and is ready to review.", subject),
+		Link:      generationRunURL(athleteID, runID),
+		AthleteID: sql.NullInt64{Int64: athleteID, Valid: true},
+	})
+}
+
+// Status shows the progress of a background generation run. While the run is
+// pending, htmx polls this endpoint and receives 204 until it finishes, then
+// an HX-Redirect back here. A completed run loads into the session and
+// redirects to the preview; a failed run re-renders the form with its error.
+// A regenerate-day run is merged into the program already in the session.
+// GET /athletes/{id}/programs/generate/runs/{runID}
+func (h *Generate) Status(w http.ResponseWriter, r *http.Request) {
+	athlete, athleteID, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+
+	run, ok := h.loadRun(w, r, athleteID)
+	if !ok {
+		return
+	}
+	runID := run.ID
+
+	var req llm.GenerationRequest
+	if err := json.Unmarshal([]byte(run.RequestJSON), &req); err != nil {
+		log.Printf("handlers: decode generation request for run %d: %v", runID, err)
+	}
+
+	isHTMX := r.Header.Get("HX-Request") == "true"
+	if !run.Done() {
+		if isHTMX {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		data := map[string]any{
+			"Athlete": athlete,
+			"Run":     run,
+			"Scope":   req.RegenerateScope,
+		}
+		if err := h.Templates.Render(w, r, "generate_status.html", data); err != nil {
+			log.Printf("handlers: render generate status: %v", err)
+			h.Templates.ServerError(w, r)
+		}
+		return
+	}
+	if isHTMX {
+		w.Header().Set("HX-Redirect", generationRunURL(athleteID, runID))
+		return
+	}
+
+	if req.RegenerateScope != nil {
+		h.mergeRegeneratedRun(w, r, athleteID, run, req.RegenerateScope)
+		return
+	}
+
+	if run.Status == models.GenerationFailed {
+		h.renderFormError(w, r, athlete, req, run.Error.String)
+		return
+	}
+
+	result := &llm.GenerationResult{}
+	if err := json.Unmarshal([]byte(run.ResultJSON.String), result); err != nil {
+		log.Printf("handlers: decode generation result for run %d: %v", runID, err)
+		h.Templates.ServerError(w, r)
 		return
 	}
 
 	parsed, err := importers.ParseCatalogJSON(bytes.NewReader(result.CatalogJSON))
 	if err != nil {
-		log.Printf("handlers: parse LLM CatalogJSON: %v", err)
+		log.Printf("handlers: parse stored CatalogJSON for run %d: %v", runID, err)
 		h.renderFormError(w, r, athlete, req,
 			fmt.Sprintf("The AI Coach returned invalid data: %v. Please try again.", err))
 		return
 	}
 
-	// Build entity mappings against existing catalog.
+	// Store results in session for preview/execute.
+	h.Sessions.Put(r.Context(), "generate_result", result)
+	h.Sessions.Put(r.Context(), "generate_mapping", h.buildGenerateMapping(athleteID, parsed))
+
+	http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID), http.StatusSeeOther)
+}
+
+// Stream sends a generation run's output as server-sent events while it runs.
+// "token" events carry text as the LLM produces it, starting with anything
+// already generated; a final "done" event tells the page to check the run's
+// status. Runs whose provider can't stream send only "done".
+// GET /athletes/{id}/programs/generate/runs/{runID}/stream
+func (h *Generate) Stream(w http.ResponseWriter, r *http.Request) {
+	_, athleteID, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+
+	run, ok := h.loadRun(w, r, athleteID)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	rc := http.NewResponseController(w)
+
+	h.mu.Lock()
+	stream := h.streams[run.ID]
+	h.mu.Unlock()
+	if stream == nil {
+		// Already finished (or started before a restart).
+		writeSSE(w, "done", "")
+		rc.Flush()
+		return
+	}
+
+	offset := 0
+	for {
+		text, done, changed := stream.since(offset)
+		if text != "" {
+			writeSSE(w, "token", text)
+			offset += len(text)
+		}
+		if done {
+			writeSSE(w, "done", "")
+			rc.Flush()
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeSSE writes one server-sent event. Multi-line data is split across
+// data fields, which the browser rejoins with newlines.
+func writeSSE(w io.Writer, event, data string) {
+	fmt.Fprintf(w, "event: %s\n", event)
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r", ""), "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// History lists an athlete's past generation runs so a coach can revisit
+// why a program was produced or re-open a completed run's preview.
+// GET /athletes/{id}/programs/generate/history
+func (h *Generate) History(w http.ResponseWriter, r *http.Request) {
+	athlete, athleteID, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+
+	runs, err := models.ListGenerations(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: list generations for athlete %d: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	type historyRow struct {
+		Run         *models.GenerationRun
+		ProgramName string
+	}
+	rows := make([]historyRow, len(runs))
+	for i, run := range runs {
+		var req llm.GenerationRequest
+		if err := json.Unmarshal([]byte(run.RequestJSON), &req); err != nil {
+			log.Printf("handlers: decode generation request for run %d: %v", run.ID, err)
+		}
+		rows[i] = historyRow{Run: run, ProgramName: req.ProgramName}
+	}
+
+	data := map[string]any{
+		"Athlete": athlete,
+		"Runs":    rows,
+	}
+	if err := h.Templates.Render(w, r, "generate_history.html", data); err != nil {
+		log.Printf("handlers: render generate history: %v", err)
+		h.Templates.ServerError(w, r)
+	}
+}
+
+// RunContext returns the athlete context that was sent to the LLM for a
+// generation run, as it was at generation time.
+// GET /athletes/{id}/programs/generate/runs/{runID}/context.json
+func (h *Generate) RunContext(w http.ResponseWriter, r *http.Request) {
+	_, athleteID, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+
+	run, ok := h.loadRun(w, r, athleteID)
+	if !ok {
+		return
+	}
+	if !run.ContextJSON.Valid {
+		h.Templates.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="athlete-%d-generation-%d-context.json"`, athleteID, run.ID))
+	w.Write([]byte(run.ContextJSON.String))
+}
+
+// RunResponse returns the unprocessed LLM output for a generation run, so a
+// coach can see what came back when a run failed.
+// GET /athletes/{id}/programs/generate/runs/{runID}/response.txt
+func (h *Generate) RunResponse(w http.ResponseWriter, r *http.Request) {
+	_, athleteID, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+
+	run, ok := h.loadRun(w, r, athleteID)
+	if !ok {
+		return
+	}
+	if !run.RawResponse.Valid {
+		h.Templates.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="athlete-%d-generation-%d-response.txt"`, athleteID, run.ID))
+	w.Write([]byte(run.RawResponse.String))
+}
+
+// loadRun retrieves the generation run named in the URL and checks that it
+// belongs to the athlete. Returns the run and whether the caller should continue.
+func (h *Generate) loadRun(w http.ResponseWriter, r *http.Request, athleteID int64) (*models.GenerationRun, bool) {
+	runID, err := strconv.ParseInt(r.PathValue("runID"), 10, 64)
+	if err != nil {
+		h.Templates.NotFound(w, r)
+		return nil, false
+	}
+
+	run, err := models.GetGenerationRun(h.DB, runID)
+	if errors.Is(err, models.ErrNotFound) || (err == nil && run.AthleteID != athleteID) {
+		h.Templates.NotFound(w, r)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("handlers: get generation run %d: %v", runID, err)
+		h.Templates.ServerError(w, r)
+		return nil, false
+	}
+	return run, true
+}
+
+// buildGenerateMapping maps a generated catalog onto the existing exercises,
+// equipment, and programs visible to the athlete.
+func (h *Generate) buildGenerateMapping(athleteID int64, parsed *importers.ParsedFile) *importers.MappingState {
 	existingExercises, _ := listExistingExercises(h.DB)
 	existingEquipment, _ := listExistingEquipment(h.DB)
 	existingPrograms, _ := listExistingProgramsForAthlete(h.DB, athleteID)
@@ -258,12 +622,12 @@ func (h *Generate) Submit(w http.ResponseWriter, r *http.Request) {
 		progExMappings := importers.BuildExerciseMappings(progExParsed, existingExercises)
 		ms.Exercises = importers.MergeExerciseMappings(ms.Exercises, progExMappings)
 	}
+	return ms
+}
 
-	// Store results in session for preview/execute.
-	h.Sessions.Put(r.Context(), "generate_result", result)
-	h.Sessions.Put(r.Context(), "generate_mapping", ms)
-
-	http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID), http.StatusSeeOther)
+// generationRunURL returns the status page for a generation run.
+func generationRunURL(athleteID, runID int64) string {
+	return fmt.Sprintf("/athletes/%d/programs/generate/runs/%d", athleteID, runID)
 }
 
 // Preview shows the LLM reasoning and catalog import summary.
@@ -285,7 +649,12 @@ func (h *Generate) Preview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	preview := models.BuildCatalogImportPreview(ms)
+	preview, err := models.BuildCatalogImportPreview(h.DB, ms)
+	if err != nil {
+		log.Printf("handlers: build generate preview: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
 
 	// Build structured program view for the detail display.
 	var programViews []programDayView
@@ -308,6 +677,10 @@ func (h *Generate) Preview(w http.ResponseWriter, r *http.Request) {
 		"Mapping":      ms,
 		"ProgramDays":  programViews,
 		"EditableRows": editableRows,
+		"Issues":       result.Issues,
+		"Blocked":      len(preview.Unresolved) > 0 || llm.HasFatalIssues(result.Issues),
+		"Error":        h.Sessions.PopString(r.Context(), "flash_error"),
+		"Success":      h.Sessions.PopString(r.Context(), "flash_success"),
 	}
 	if err := h.Templates.Render(w, r, "generate_preview.html", data); err != nil {
 		log.Printf("handlers: render generate preview: %v", err)
@@ -363,15 +736,184 @@ func (h *Generate) SaveEdits(w http.ResponseWriter, r *http.Request) {
 	// Store updated mapping back in session.
 	h.Sessions.Put(r.Context(), "generate_mapping", ms)
 
+	// Re-check the edited sets so fixed issues clear and new ones surface.
+	if result, ok := h.Sessions.Get(r.Context(), "generate_result").(*llm.GenerationResult); ok && result != nil {
+		catalog, err := llm.ExerciseCatalog(h.DB, athleteID)
+		if err != nil {
+			log.Printf("handlers: load exercise catalog for athlete %d: %v", athleteID, err)
+		} else {
+			result.Issues = llm.ValidateGeneratedCatalog(ms.Parsed, result.Request, catalog)
+			h.Sessions.Put(r.Context(), "generate_result", result)
+		}
+	}
+
 	action := r.FormValue("action")
 	if action == "execute" {
 		h.Execute(w, r)
 		return
 	}
+	if scope := r.FormValue("regenerate"); scope != "" {
+		h.regenerateDay(w, r, athleteID, ms, scope)
+		return
+	}
 
 	http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID), http.StatusSeeOther)
 }
 
+// regenerateDay asks the LLM to redo one day of the (already edited)
+// generated program. The scope form value is "week-day", with week 0 meaning
+// that day in every week. Like Submit, it queues a background run and
+// redirects to its status page; Status splices the new sets into the session
+// mapping once the run finishes.
+func (h *Generate) regenerateDay(w http.ResponseWriter, r *http.Request, athleteID int64, ms *importers.MappingState, scopeValue string) {
+	previewURL := fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID)
+	flash := func(msg string) {
+		h.Sessions.Put(r.Context(), "flash_error", msg)
+		http.Redirect(w, r, previewURL, http.StatusSeeOther)
+	}
+
+	result, ok := h.Sessions.Get(r.Context(), "generate_result").(*llm.GenerationResult)
+	if !ok || result == nil || ms.Parsed == nil || len(ms.Parsed.Programs) == 0 {
+		http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate", athleteID), http.StatusSeeOther)
+		return
+	}
+	program := &ms.Parsed.Programs[0].Template
+
+	weekStr, dayStr, _ := strings.Cut(scopeValue, "-")
+	week, werr := strconv.Atoi(weekStr)
+	day, derr := strconv.Atoi(dayStr)
+	if werr != nil || derr != nil || day < 1 || day > program.NumDays || week < 0 || week > program.NumWeeks {
+		flash("Choose a day of the program to regenerate.")
+		return
+	}
+
+	// The slot is released by the background run once it finishes.
+	if !h.acquireSlot(athleteID) {
+		flash("A generation is already in progress for this athlete. Please wait for it to complete.")
+		return
+	}
+
+	if msg := h.tokenCapMessage(); msg != "" {
+		h.releaseSlot(athleteID)
+		flash(msg)
+		return
+	}
+	provider, err := llm.NewProviderFromSettings(h.DB)
+	if err != nil {
+		h.releaseSlot(athleteID)
+		log.Printf("handlers: create LLM provider: %v", err)
+		flash("AI Coach is not configured. Please ask an administrator to configure it in Settings.")
+		return
+	}
+
+	req := result.Request
+	req.AthleteID = athleteID
+	req.RegenerateScope = &llm.RegenerateScope{
+		Program:    *program,
+		Day:        day,
+		Week:       week,
+		Directions: strings.TrimSpace(r.FormValue("regenerate_directions")),
+	}
+
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		h.releaseSlot(athleteID)
+		log.Printf("handlers: marshal regenerate request for athlete %d: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	var userID int64
+	if user := middleware.UserFromContext(r.Context()); user != nil {
+		userID = user.ID
+	}
+
+	run, err := models.CreateGenerationRun(h.DB, athleteID, userID, string(reqJSON))
+	if err != nil {
+		h.releaseSlot(athleteID)
+		log.Printf("handlers: create generation run for athlete %d: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	stream := h.openStream(run.ID)
+	go h.runGeneration(run.ID, userID, provider, req, stream)
+
+	http.Redirect(w, r, generationRunURL(athleteID, run.ID), http.StatusSeeOther)
+}
+
+// mergeRegeneratedRun splices a finished regenerate-day run into the program
+// in the coach's session and returns them to the preview. A run is merged at
+// most once per session, so revisiting its status page doesn't undo edits
+// made since.
+func (h *Generate) mergeRegeneratedRun(w http.ResponseWriter, r *http.Request, athleteID int64, run *models.GenerationRun, scope *llm.RegenerateScope) {
+	previewURL := fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID)
+	flash := func(msg string) {
+		h.Sessions.Put(r.Context(), "flash_error", msg)
+		http.Redirect(w, r, previewURL, http.StatusSeeOther)
+	}
+
+	result, ok := h.Sessions.Get(r.Context(), "generate_result").(*llm.GenerationResult)
+	ms, msOK := h.Sessions.Get(r.Context(), "generate_mapping").(*importers.MappingState)
+	if !ok || result == nil || !msOK || ms == nil || ms.Parsed == nil || len(ms.Parsed.Programs) == 0 {
+		http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate", athleteID), http.StatusSeeOther)
+		return
+	}
+	if h.Sessions.GetInt64(r.Context(), "generate_merged_run") == run.ID {
+		http.Redirect(w, r, previewURL, http.StatusSeeOther)
+		return
+	}
+	if run.Status == models.GenerationFailed {
+		flash(fmt.Sprintf("Regenerating %s failed: %s", scope, run.Error.String))
+		return
+	}
+	program := &ms.Parsed.Programs[0].Template
+
+	regen := &llm.GenerationResult{}
+	if err := json.Unmarshal([]byte(run.ResultJSON.String), regen); err != nil {
+		log.Printf("handlers: decode generation result for run %d: %v", run.ID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+	parsed, err := importers.ParseCatalogJSON(bytes.NewReader(regen.CatalogJSON))
+	if err != nil {
+		log.Printf("handlers: parse regenerated CatalogJSON for athlete %d: %v", athleteID, err)
+		flash(fmt.Sprintf("The AI Coach returned invalid data for %s. Your program is unchanged.", scope))
+		return
+	}
+	n, err := llm.MergeRegenerated(program, parsed, scope)
+	if err != nil {
+		log.Printf("handlers: %v", err)
+		flash(fmt.Sprintf("The AI Coach didn't return any sets for %s. Your program is unchanged.", scope))
+		return
+	}
+
+	// New exercises need mappings, and the merged program needs re-checking.
+	existingExercises, _ := listExistingExercises(h.DB)
+	progExNames := importers.CollectProgramExerciseNames(ms.Parsed.Programs)
+	progExParsed := make([]importers.ParsedExercise, len(progExNames))
+	for i, name := range progExNames {
+		progExParsed[i] = importers.ParsedExercise{Name: name}
+	}
+	ms.Exercises = importers.MergeExerciseMappings(ms.Exercises, importers.BuildExerciseMappings(progExParsed, existingExercises))
+	if catalog, err := llm.ExerciseCatalog(h.DB, athleteID); err == nil {
+		result.Issues = llm.ValidateGeneratedCatalog(ms.Parsed, result.Request, catalog)
+	} else {
+		log.Printf("handlers: load exercise catalog for athlete %d: %v", athleteID, err)
+	}
+	result.TokensUsed += regen.TokensUsed
+
+	h.Sessions.Put(r.Context(), "generate_mapping", ms)
+	h.Sessions.Put(r.Context(), "generate_result", result)
+	h.Sessions.Put(r.Context(), "generate_merged_run", run.ID)
+	sets := "sets"
+	if n == 1 {
+		sets = "set"
+	}
+	h.Sessions.Put(r.Context(), "flash_success", fmt.Sprintf("Regenerated %s (%d %s).", scope, n, sets))
+	http.Redirect(w, r, previewURL, http.StatusSeeOther)
+}
+
 // Execute approves the generated program and imports it.
 func (h *Generate) Execute(w http.ResponseWriter, r *http.Request) {
 	athlete, athleteID, ok := h.loadAthlete(w, r)
@@ -385,6 +927,19 @@ func (h *Generate) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Unresolved exercise references would silently drop sets; send the
+	// coach back to the preview, which lists them.
+	if len(models.ValidateCatalogReferences(ms)) > 0 {
+		http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID), http.StatusSeeOther)
+		return
+	}
+	// Likewise for sets outside the program's weeks/days or with bad
+	// percentages; warnings alone don't block.
+	if result, ok := h.Sessions.Get(r.Context(), "generate_result").(*llm.GenerationResult); ok && result != nil && llm.HasFatalIssues(result.Issues) {
+		http.Redirect(w, r, fmt.Sprintf("/athletes/%d/programs/generate/preview", athleteID), http.StatusSeeOther)
+		return
+	}
+
 	importResult, err := models.ExecuteCatalogImport(h.DB, ms, &athleteID)
 	if err != nil {
 		log.Printf("handlers: execute generated import for athlete %d: %v", athleteID, err)
@@ -481,6 +1036,7 @@ func (h *Generate) renderFormError(w http.ResponseWriter, r *http.Request, athle
 		"Configured":        true,
 		"ReferencePrograms": refPrograms,
 		"SelectedRefIDs":    selectedRefIDs,
+		"ContextOptions":    req.ContextOptions,
 	}
 	if err := h.Templates.Render(w, r, "generate_form.html", data); err != nil {
 		log.Printf("handlers: render generate form with error: %v", err)
@@ -515,17 +1071,91 @@ func (h *Generate) releaseSlot(athleteID int64) {
 	delete(h.inflight, athleteID)
 }
 
+// openStream registers an output buffer for a generation run.
+func (h *Generate) openStream(runID int64) *generationStream {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.streams == nil {
+		h.streams = make(map[int64]*generationStream)
+	}
+	stream := &generationStream{changed: make(chan struct{})}
+	h.streams[runID] = stream
+	return stream
+}
+
+// closeStream marks a run's output complete and unregisters it.
+func (h *Generate) closeStream(runID int64, stream *generationStream) {
+	stream.finish()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.streams, runID)
+}
+
+// generationStream buffers a run's streamed output so SSE clients can join
+// mid-generation and catch up.
+type generationStream struct {
+	mu      sync.Mutex
+	text    strings.Builder
+	done    bool
+	changed chan struct{} // closed (and replaced) whenever text or done changes
+}
+
+func (s *generationStream) append(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.text.WriteString(text)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *generationStream) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.done {
+		s.done = true
+		close(s.changed)
+	}
+}
+
+// since returns the text after offset, whether the run has finished, and a
+// channel that is closed on the next change.
+func (s *generationStream) since(offset int) (string, bool, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.text.String()[offset:], s.done, s.changed
+}
+
 // ContextJSON returns the full athlete context as JSON. This lets coaches
 // copy the context for use with external LLMs or for debugging.
 // GET /athletes/{id}/context.json
 func (h *Generate) ContextJSON(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+	athlete, id, ok := h.loadAthlete(w, r)
+	if !ok {
+		return
+	}
+	user := middleware.UserFromContext(r.Context())
+	if !middleware.CanManageAthlete(user, athlete) {
+		h.Templates.Forbidden(w, r)
 		return
 	}
 
-	ctx, err := llm.BuildAthleteContext(h.DB, id, time.Now())
+	// Mirror the form: the same section exclusions and reference program
+	// selection the coach would submit.
+	query := r.URL.Query()
+	var refIDs []int64
+	for _, v := range query["reference_programs"] {
+		if refID, err := strconv.ParseInt(v, 10, 64); err == nil {
+			refIDs = append(refIDs, refID)
+		}
+	}
+	opts := contextOptionsFromExcluded(query["exclude"])
+	opts.IncludePrivateNotes = true // only the athlete's coach or an admin gets here
+	ctx, err := llm.BuildAthleteContextWithOptions(h.DB, id, time.Now(), opts, refIDs...)
 	if err != nil {
 		log.Printf("handlers: build athlete context for %d: %v", id, err)
 		http.Error(w, "Failed to build context", http.StatusInternalServerError)
@@ -539,6 +1169,71 @@ func (h *Generate) ContextJSON(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// tokenCapMessage returns a user-facing message when this month's token usage
+// has reached the configured cap, or "" when generation may proceed.
+func (h *Generate) tokenCapMessage() string {
+	limit := models.GetMonthlyTokenCap(h.DB)
+	if limit == 0 {
+		return ""
+	}
+	usage, err := models.MonthlyTokenUsage(h.DB, time.Now())
+	if err != nil {
+		// Fail open: a broken usage query shouldn't lock coaches out.
+		log.Printf("handlers: %v", err)
+		return ""
+	}
+	if usage.TokensUsed < limit {
+		return ""
+	}
+	return fmt.Sprintf("The AI Coach monthly token budget has been reached (%d of %d tokens used). "+
+		"Generation is paused until next month unless an administrator raises the cap in Settings.",
+		usage.TokensUsed, limit)
+}
+
+// Context section names used by the generate form's "include_context"
+// checkboxes and the context.json "exclude" query parameter.
+const (
+	contextCoachNotes     = "coach_notes"
+	contextRecentWorkouts = "recent_workouts"
+	contextBodyWeights    = "body_weights"
+)
+
+// contextOptionsFromForm reads the generate form's context checkboxes. Forms
+// without the "context_sections" marker (older clients, scripted posts) keep
+// the default of sending everything.
+func contextOptionsFromForm(r *http.Request) llm.ContextOptions {
+	if !r.Form.Has("context_sections") {
+		return llm.ContextOptions{}
+	}
+	included := make(map[string]bool)
+	for _, v := range r.Form["include_context"] {
+		included[v] = true
+	}
+	var excluded []string
+	for _, section := range []string{contextCoachNotes, contextRecentWorkouts, contextBodyWeights} {
+		if !included[section] {
+			excluded = append(excluded, section)
+		}
+	}
+	return contextOptionsFromExcluded(excluded)
+}
+
+// contextOptionsFromExcluded builds ContextOptions from excluded section names.
+func contextOptionsFromExcluded(excluded []string) llm.ContextOptions {
+	var opts llm.ContextOptions
+	for _, section := range excluded {
+		switch section {
+		case contextCoachNotes:
+			opts.ExcludeCoachNotes = true
+		case contextRecentWorkouts:
+			opts.ExcludeRecentWorkouts = true
+		case contextBodyWeights:
+			opts.ExcludeBodyWeights = true
+		}
+	}
+	return opts
+}
+
 // suggestNextProgramName auto-increments a trailing number in the program name.
 // "Sport Performance Month 3" -> "Sport Performance Month 4"
 func suggestNextProgramName(current string) string {
@@ -565,11 +1260,12 @@ type editableSetRow struct {
 	Day        int
 	Exercise   string
 	NumSets    int
-	Reps       string // reps per working set ("5", "" = all AMRAP)
+	Reps       string // reps per working set ("5", "8-12", "" = all AMRAP)
 	AmrapLast  bool   // last set uses AMRAP while others use Reps
 	RepType    string // reps, each_side, seconds, distance
 	LoadType   string // "percent", "absolute", "bodyweight"
 	LoadValue  string // "75" (percent), "25" (absolute), "" (BW)
+	TargetRPE  string // "8", "8.5", "" = no RPE target
 	Notes      string
 	SortOrder  int
 }
@@ -631,6 +1327,9 @@ func buildEditableRows(programs []importers.ParsedProgram) []editableSetRow {
 				// Determine reps pattern.
 				if first.Reps != nil {
 					row.Reps = strconv.Itoa(*first.Reps)
+					if first.RepMax != nil && *first.RepMax > *first.Reps {
+						row.Reps += "-" + strconv.Itoa(*first.RepMax)
+					}
 				}
 				// AMRAP last: last set has nil reps, others have reps.
 				if last.Reps == nil && len(sets) > 1 && first.Reps != nil {
@@ -652,6 +1351,10 @@ func buildEditableRows(programs []importers.ParsedProgram) []editableSetRow {
 					row.LoadType = "bodyweight"
 				}
 
+				if first.TargetRPE != nil {
+					row.TargetRPE = strconv.FormatFloat(*first.TargetRPE, 'f', -1, 64)
+				}
+
 				// Notes from first set.
 				if first.Notes != nil {
 					row.Notes = *first.Notes
@@ -705,6 +1408,7 @@ func parseEditableRows(r *http.Request) ([]editableSetRow, error) {
 			RepType:    r.FormValue(prefix + "rep_type"),
 			LoadType:   r.FormValue(prefix + "load_type"),
 			LoadValue:  strings.TrimSpace(r.FormValue(prefix + "load_value")),
+			TargetRPE:  strings.TrimSpace(r.FormValue(prefix + "target_rpe")),
 			Notes:      strings.TrimSpace(r.FormValue(prefix + "notes")),
 			SortOrder:  sortOrder,
 		}
@@ -727,13 +1431,8 @@ func rebuildPrescribedSets(rows []editableSetRow) map[int][]importers.ParsedPres
 	result := make(map[int][]importers.ParsedPrescribedSet)
 
 	for _, row := range rows {
-		// Parse reps.
-		var reps *int
-		if row.Reps != "" {
-			if v, err := strconv.Atoi(row.Reps); err == nil {
-				reps = &v
-			}
-		}
+		// Parse reps, either a single target ("5") or a range ("8-12").
+		reps, repMax := parseRepsRange(row.Reps)
 
 		// Parse load.
 		var percentage *float64
@@ -753,6 +1452,11 @@ func rebuildPrescribedSets(rows []editableSetRow) map[int][]importers.ParsedPres
 			absoluteWeight = &zero
 		}
 
+		var targetRPE *float64
+		if v, err := strconv.ParseFloat(row.TargetRPE, 64); err == nil && v >= 1 && v <= 10 {
+			targetRPE = &v
+		}
+
 		var notes *string
 		if row.Notes != "" {
 			n := row.Notes
@@ -760,10 +1464,10 @@ func rebuildPrescribedSets(rows []editableSetRow) map[int][]importers.ParsedPres
 		}
 
 		for setNum := 1; setNum <= row.NumSets; setNum++ {
-			setReps := reps
+			setReps, setRepMax := reps, repMax
 			// AMRAP last: final set gets nil reps.
 			if row.AmrapLast && setNum == row.NumSets {
-				setReps = nil
+				setReps, setRepMax = nil, nil
 			}
 
 			ps := importers.ParsedPrescribedSet{
@@ -772,9 +1476,11 @@ func rebuildPrescribedSets(rows []editableSetRow) map[int][]importers.ParsedPres
 				Day:            row.Day,
 				SetNumber:      setNum,
 				Reps:           setReps,
+				RepMax:         setRepMax,
 				RepType:        row.RepType,
 				Percentage:     percentage,
 				AbsoluteWeight: absoluteWeight,
+				TargetRPE:      targetRPE,
 				SortOrder:      row.SortOrder,
 				Notes:          notes,
 			}
@@ -784,6 +1490,24 @@ func rebuildPrescribedSets(rows []editableSetRow) map[int][]importers.ParsedPres
 	return result
 }
 
+// parseRepsRange parses a reps field from the preview editor. It accepts a
+// single target ("5") or a rep range ("8-12"); anything unparseable is
+// treated as AMRAP (nil reps).
+func parseRepsRange(s string) (reps, repMax *int) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	v, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return nil, nil
+	}
+	reps = &v
+	if isRange {
+		if m, err := strconv.Atoi(strings.TrimSpace(hi)); err == nil && m > v {
+			repMax = &m
+		}
+	}
+	return reps, repMax
+}
+
 // programDayView groups exercises and sets for one training day in one program.
 type programDayView struct {
 	ProgramName string
@@ -809,8 +1533,8 @@ type programExerciseView struct {
 // programSetView is a single prescribed set for template display.
 type programSetView struct {
 	SetNumber int
-	RepsStr   string  // formatted reps string, e.g. "5", "AMRAP", "30s", "8 each"
-	WeightStr string  // formatted weight, e.g. "BW", "25 lbs", "75%"
+	RepsStr   string  // formatted reps string, e.g. "5", "8-12", "AMRAP", "30s", "8 each"
+	WeightStr string  // formatted weight, e.g. "BW", "25 lbs", "75%", "75% @8 RPE"
 	Notes     string
 }
 
@@ -840,8 +1564,8 @@ func buildProgramDays(tmpl importers.ParsedProgramTemplate) []programDayView {
 
 		sv := programSetView{
 			SetNumber: s.SetNumber,
-			RepsStr:   formatSetReps(s.Reps, s.RepType),
-			WeightStr: formatSetWeight(s.Percentage, s.AbsoluteWeight),
+			RepsStr:   formatSetReps(s.Reps, s.RepMax, s.RepType),
+			WeightStr: formatSetWeight(s.Percentage, s.AbsoluteWeight, s.TargetRPE),
 		}
 		if s.Notes != nil {
 			sv.Notes = *s.Notes
@@ -901,21 +1625,25 @@ func buildProgramDays(tmpl importers.ParsedProgramTemplate) []programDayView {
 	return days
 }
 
-// formatSetReps formats reps for display.
-func formatSetReps(reps *int, repType string) string {
+// formatSetReps formats reps for display. A non-nil repMax above reps
+// renders as a range (e.g. "8-12").
+func formatSetReps(reps, repMax *int, repType string) string {
 	if reps == nil {
 		return "AMRAP"
 	}
-	r := *reps
+	r := strconv.Itoa(*reps)
+	if repMax != nil && *repMax > *reps {
+		r = fmt.Sprintf("%d-%d", *reps, *repMax)
+	}
 	switch repType {
 	case "seconds":
-		return fmt.Sprintf("%ds", r)
+		return r + "s"
 	case "each_side":
-		return fmt.Sprintf("%d each", r)
+		return r + " each"
 	case "distance":
-		return fmt.Sprintf("%dm", r)
+		return r + "m"
 	default:
-		return fmt.Sprintf("%d", r)
+		return r
 	}
 }
 
@@ -952,19 +1680,28 @@ func summarizeSetsReps(sets []programSetView) string {
 	return strings.Join(parts, " + ")
 }
 
-// formatSetWeight formats the weight/loading for display.
-func formatSetWeight(percentage *float64, absoluteWeight *float64) string {
-	if percentage != nil && *percentage > 0 {
-		return fmt.Sprintf("%.0f%%", *percentage*100)
-	}
-	if absoluteWeight != nil {
-		if *absoluteWeight == 0 {
-			return "BW"
-		}
+// formatSetWeight formats the weight/loading for display, appending the
+// target RPE when set (e.g. "75% @8 RPE"). An RPE-only set renders as "@8 RPE".
+func formatSetWeight(percentage, absoluteWeight, targetRPE *float64) string {
+	var load string
+	switch {
+	case percentage != nil && *percentage > 0:
+		load = fmt.Sprintf("%.0f%%", *percentage*100)
+	case absoluteWeight != nil && *absoluteWeight != 0:
 		if *absoluteWeight == float64(int(*absoluteWeight)) {
-			return fmt.Sprintf("%.0f lbs", *absoluteWeight)
+			load = fmt.Sprintf("%.0f lbs", *absoluteWeight)
+		} else {
+			load = fmt.Sprintf("%.1f lbs", *absoluteWeight)
 		}
-		return fmt.Sprintf("%.1f lbs", *absoluteWeight)
+	case absoluteWeight != nil || targetRPE == nil:
+		load = "BW"
+	}
+	if targetRPE == nil {
+		return load
+	}
+	rpe := "@" + strconv.FormatFloat(*targetRPE, 'f', -1, 64) + " RPE"
+	if load == "" {
+		return rpe
 	}
-	return "BW"
+	return load + " " + rpe
 }
@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/carpenike/replog/internal/importers"
+	"github.com/carpenike/replog/internal/llm"
+	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 )
 
@@ -71,7 +79,7 @@ func TestGenerate_Form(t *testing.T) {
 
 	t.Run("pre-fills from active program", func(t *testing.T) {
 		// Create a program template and assign it.
-		pt, err := models.CreateProgramTemplate(db, nil, "Sport Performance Month 3", "test", 4, 4, false, "")
+		pt, err := models.CreateProgramTemplate(db, nil, "Sport Performance Month 3", "test", 4, 4, false, "", 0, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -141,6 +149,266 @@ func TestGenerate_Submit_NotConfigured(t *testing.T) {
 	}
 }
 
+func TestGenerate_Status(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	reqJSON, _ := json.Marshal(llm.GenerationRequest{AthleteID: athlete.ID, ProgramName: "Tommy Block", NumDays: 3, NumWeeks: 4})
+	run, err := models.CreateGenerationRun(db, athlete.ID, coach.ID, string(reqJSON))
+	if err != nil {
+		t.Fatalf("create generation run: %v", err)
+	}
+
+	serve := func(htmx bool, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := requestWithUser("GET", generationRunURL(athlete.ID, run.ID), nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("runID", itoa(run.ID))
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Status)).ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("pending page", func(t *testing.T) {
+		rr := serve(false, nil)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "status queued") {
+			t.Errorf("expected status page, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("pending poll", func(t *testing.T) {
+		if rr := serve(true, nil); rr.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", rr.Code)
+		}
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		resultJSON, _ := json.Marshal(&llm.GenerationResult{
+			Reasoning:   "ok",
+			CatalogJSON: []byte(`{"version":"1.0","type":"catalog","programs":[{"template":{"name":"Tommy Block","num_weeks":1,"num_days":1}}]}`),
+		})
+		if err := models.CompleteGenerationRun(db, run.ID, string(resultJSON)); err != nil {
+			t.Fatalf("complete run: %v", err)
+		}
+
+		rr := serve(true, nil)
+		if got := rr.Header().Get("HX-Redirect"); got != generationRunURL(athlete.ID, run.ID) {
+			t.Errorf("expected HX-Redirect to run, got %q", got)
+		}
+
+		rr = serve(false, nil)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); loc != fmt.Sprintf("/athletes/%d/programs/generate/preview", athlete.ID) {
+			t.Errorf("expected redirect to preview, got %s", loc)
+		}
+
+		req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/programs/generate/preview", nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		for _, c := range rr.Result().Cookies() {
+			req.AddCookie(c)
+		}
+		rr = httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Preview)).ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected preview to load from session, got %d", rr.Code)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		if err := models.FailGenerationRun(db, run.ID, "provider exploded"); err != nil {
+			t.Fatalf("fail run: %v", err)
+		}
+		rr := serve(false, nil)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "provider exploded") {
+			t.Errorf("expected form with run error, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("other athlete", func(t *testing.T) {
+		other := seedAthlete(t, db, "Other", "")
+		req := requestWithUser("GET", generationRunURL(other.ID, run.ID), nil, coach)
+		req.SetPathValue("id", itoa(other.ID))
+		req.SetPathValue("runID", itoa(run.ID))
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Status)).ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestGenerate_History(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	reqJSON, _ := json.Marshal(llm.GenerationRequest{AthleteID: athlete.ID, ProgramName: "Tommy Block"})
+	run, _ := models.CreateGenerationRun(db, athlete.ID, coach.ID, string(reqJSON))
+	if err := models.RecordGenerationResponse(db, run.ID, `{"athlete":{"name":"Tommy"}}`, "raw output", "gpt-4o", 900); err != nil {
+		t.Fatalf("record generation response: %v", err)
+	}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/programs/generate/history", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.History(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "run Tommy Block queued gpt-4o") {
+		t.Errorf("expected run in history, got: %s", rr.Body.String())
+	}
+
+	req = requestWithUser("GET", generationRunURL(athlete.ID, run.ID)+"/context.json", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("runID", itoa(run.ID))
+	rr = httptest.NewRecorder()
+	h.RunContext(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr.Body.String() != `{"athlete":{"name":"Tommy"}}` {
+		t.Errorf("expected stored context, got: %s", rr.Body.String())
+	}
+}
+
+func TestGenerate_RunGeneration_KeepsRawResponseOnFailure(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	genReq := llm.GenerationRequest{AthleteID: athlete.ID, ProgramName: "Tommy Block", NumWeeks: 1, NumDays: 1}
+	reqJSON, _ := json.Marshal(genReq)
+	run, _ := models.CreateGenerationRun(db, athlete.ID, coach.ID, string(reqJSON))
+	provider := &llm.MockProvider{FixedContent: "Sorry, I can't build that program."}
+	h.runGeneration(run.ID, coach.ID, provider, genReq, h.openStream(run.ID))
+
+	got, err := models.GetGenerationRun(db, run.ID)
+	if err != nil {
+		t.Fatalf("get run: %v", err)
+	}
+	if got.Status != models.GenerationFailed {
+		t.Fatalf("status = %q, want failed", got.Status)
+	}
+	if got.RawResponse.String != provider.FixedContent {
+		t.Errorf("raw response = %q, want provider output", got.RawResponse.String)
+	}
+
+	req := requestWithUser("GET", generationRunURL(athlete.ID, run.ID)+"/response.txt", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("runID", itoa(run.ID))
+	rr := httptest.NewRecorder()
+	h.RunResponse(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr.Body.String() != provider.FixedContent {
+		t.Errorf("response body = %q", rr.Body.String())
+	}
+}
+
+func TestGenerate_Stream(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+	run, _ := models.CreateGenerationRun(db, athlete.ID, coach.ID, `{}`)
+
+	serve := func() *httptest.ResponseRecorder {
+		req := requestWithUser("GET", generationRunURL(athlete.ID, run.ID)+"/stream", nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("runID", itoa(run.ID))
+		rr := httptest.NewRecorder()
+		h.Stream(rr, req)
+		return rr
+	}
+
+	t.Run("no stream", func(t *testing.T) {
+		rr := serve()
+		if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("content type = %q", ct)
+		}
+		if rr.Body.String() != "event: done\ndata: \n\n" {
+			t.Errorf("expected only done event, got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("catch up and follow", func(t *testing.T) {
+		stream := h.openStream(run.ID)
+		stream.append("<reasoning>Line one\n")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserContextKey, coach))
+			r.SetPathValue("id", itoa(athlete.ID))
+			r.SetPathValue("runID", itoa(run.ID))
+			h.Stream(w, r)
+		}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("get stream: %v", err)
+		}
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+
+		readEvent := func() string {
+			var lines []string
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					t.Fatalf("read event: %v", err)
+				}
+				if line == "\n" {
+					return strings.Join(lines, "")
+				}
+				lines = append(lines, line)
+			}
+		}
+
+		// Text generated before the client connected arrives first.
+		if got := readEvent(); got != "event: token\ndata: <reasoning>Line one\ndata: \n" {
+			t.Errorf("first event = %q", got)
+		}
+
+		stream.append("line two</reasoning>")
+		if got := readEvent(); got != "event: token\ndata: line two</reasoning>\n" {
+			t.Errorf("second event = %q", got)
+		}
+
+		h.closeStream(run.ID, stream)
+		if got := readEvent(); got != "event: done\ndata: \n" {
+			t.Errorf("final event = %q", got)
+		}
+	})
+}
+
 func TestGenerate_Preview_NoSession(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -189,6 +457,326 @@ func TestGenerate_Execute_NoSession(t *testing.T) {
 	}
 }
 
+func TestGenerate_Execute_UnresolvedExercises(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	five := 5
+	parsed := &importers.ParsedFile{
+		Format: importers.FormatCatalogJSON,
+		Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+			Name: "Tommy Block", NumWeeks: 1, NumDays: 1,
+			PrescribedSets: []importers.ParsedPrescribedSet{
+				{Exercise: "Zercher Carry", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+			},
+		}}},
+	}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Parsed:    parsed,
+		Exercises: importers.BuildExerciseMappings([]importers.ParsedExercise{{Name: "Zercher Carry"}}, nil),
+		Programs:  importers.BuildProgramMappings(parsed.Programs, nil),
+	}
+
+	setup := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "generate_result", &llm.GenerationResult{Reasoning: "ok"})
+		sm.Put(r.Context(), "generate_mapping", ms)
+	}))
+	rr := httptest.NewRecorder()
+	setup.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	cookies := rr.Result().Cookies()
+
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/programs/generate/execute", url.Values{}, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Execute)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	if loc := rr.Header().Get("Location"); loc != fmt.Sprintf("/athletes/%d/programs/generate/preview", athlete.ID) {
+		t.Errorf("expected redirect to preview, got %s", loc)
+	}
+	if programs, _ := models.ListProgramTemplatesForAthlete(db, athlete.ID); len(programs) != 0 {
+		t.Errorf("expected no program imported, got %d", len(programs))
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/programs/generate/preview", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Preview)).ServeHTTP(rr, req)
+
+	if !strings.Contains(rr.Body.String(), "unresolved Tommy Block: Zercher Carry") {
+		t.Errorf("expected unresolved exercise in preview, got: %s", rr.Body.String())
+	}
+}
+
+func TestGenerate_Execute_FatalIssues(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+	seedExercise(t, db, "Back Squat", "")
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	five := 5
+	parsed := &importers.ParsedFile{
+		Format: importers.FormatCatalogJSON,
+		Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+			Name: "Tommy Block", NumWeeks: 1, NumDays: 1,
+			PrescribedSets: []importers.ParsedPrescribedSet{
+				{Exercise: "Back Squat", Week: 1, Day: 3, SetNumber: 1, Reps: &five},
+			},
+		}}},
+	}
+	existing, err := listExistingExercises(db)
+	if err != nil {
+		t.Fatalf("list exercises: %v", err)
+	}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Parsed:    parsed,
+		Exercises: importers.BuildExerciseMappings([]importers.ParsedExercise{{Name: "Back Squat"}}, existing),
+		Programs:  importers.BuildProgramMappings(parsed.Programs, nil),
+	}
+	catalog, err := llm.ExerciseCatalog(db, athlete.ID)
+	if err != nil {
+		t.Fatalf("exercise catalog: %v", err)
+	}
+	genReq := llm.GenerationRequest{AthleteID: athlete.ID, NumWeeks: 1, NumDays: 1}
+	result := &llm.GenerationResult{Request: genReq, Issues: llm.ValidateGeneratedCatalog(parsed, genReq, catalog)}
+
+	setup := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "generate_result", result)
+		sm.Put(r.Context(), "generate_mapping", ms)
+	}))
+	rr := httptest.NewRecorder()
+	setup.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	cookies := rr.Result().Cookies()
+
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/programs/generate/execute", url.Values{}, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Execute)).ServeHTTP(rr, req)
+
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != fmt.Sprintf("/athletes/%d/programs/generate/preview", athlete.ID) {
+		t.Fatalf("expected 303 to preview, got %d %s", rr.Code, loc)
+	}
+	if programs, _ := models.ListProgramTemplatesForAthlete(db, athlete.ID); len(programs) != 0 {
+		t.Errorf("expected no program imported, got %d", len(programs))
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/programs/generate/preview", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Preview)).ServeHTTP(rr, req)
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "issue fatal: Back Squat is scheduled on day 3 of a 1-day program.") {
+		t.Errorf("expected day issue in preview, got: %s", body)
+	}
+	if !strings.Contains(body, "blocked") {
+		t.Errorf("expected approval to be blocked, got: %s", body)
+	}
+}
+
+func TestGenerate_SaveEdits_RegenerateDay(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+	seedExercise(t, db, "Back Squat", "")
+	seedExercise(t, db, "Lunge", "")
+	seedExercise(t, db, "Step Up", "")
+
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		prompt = body.Messages[len(body.Messages)-1].Content
+		content := `{"version":"1.0","type":"catalog","exercises":[],"programs":[{"name":"Tommy Block","num_weeks":1,"num_days":2,` +
+			`"prescribed_sets":[{"exercise":"Step Up","week":1,"day":2,"set_number":1,"reps":10,"rep_type":"reps"}]}]}`
+		// Background runs stream, so answer with server-sent chunks.
+		chunk, _ := json.Marshal(map[string]any{
+			"model":   "local",
+			"choices": []map[string]any{{"delta": map[string]string{"content": content}, "finish_reason": "stop"}},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+		fmt.Fprint(w, "data: {\"model\":\"local\",\"choices\":[],\"usage\":{\"total_tokens\":321}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+	models.SetSetting(db, "llm.provider", "openai_compatible")
+	models.SetSetting(db, "llm.base_url", srv.URL)
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	five := 5
+	parsed := &importers.ParsedFile{
+		Format: importers.FormatCatalogJSON,
+		Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+			Name: "Tommy Block", NumWeeks: 1, NumDays: 2,
+			PrescribedSets: []importers.ParsedPrescribedSet{
+				{Exercise: "Back Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, RepType: "reps"},
+				{Exercise: "Lunge", Week: 1, Day: 2, SetNumber: 1, Reps: &five, RepType: "reps"},
+			},
+		}}},
+	}
+	existing, _ := listExistingExercises(db)
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Parsed:    parsed,
+		Exercises: importers.BuildExerciseMappings([]importers.ParsedExercise{{Name: "Back Squat"}, {Name: "Lunge"}}, existing),
+		Programs:  importers.BuildProgramMappings(parsed.Programs, nil),
+	}
+	genReq := llm.GenerationRequest{AthleteID: athlete.ID, ProgramName: "Tommy Block", NumWeeks: 1, NumDays: 2, IsLoop: true}
+
+	setup := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "generate_result", &llm.GenerationResult{Request: genReq, TokensUsed: 1000})
+		sm.Put(r.Context(), "generate_mapping", ms)
+	}))
+	rr := httptest.NewRecorder()
+	setup.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	cookies := rr.Result().Cookies()
+
+	// The coach edited day 1 (Back Squat 3x5) before regenerating day 2.
+	form := url.Values{"regenerate": {"1-2"}, "regenerate_directions": {"single-leg work"}}
+	for _, row := range buildEditableRows(parsed.Programs) {
+		p := "set_" + itoa(int64(row.Index)) + "_"
+		numSets := strconv.Itoa(row.NumSets)
+		if row.Day == 1 {
+			numSets = "3"
+		}
+		form.Set("set_count", strconv.Itoa(row.Index+1))
+		form.Set(p+"exercise", row.Exercise)
+		form.Set(p+"program_idx", strconv.Itoa(row.ProgramIdx))
+		form.Set(p+"week", strconv.Itoa(row.Week))
+		form.Set(p+"day", strconv.Itoa(row.Day))
+		form.Set(p+"sort_order", strconv.Itoa(row.SortOrder))
+		form.Set(p+"rep_type", row.RepType)
+		form.Set(p+"num_sets", numSets)
+		form.Set(p+"reps", row.Reps)
+		form.Set(p+"load_type", "bodyweight")
+	}
+
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/programs/generate/preview", form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.SaveEdits)).ServeHTTP(rr, req)
+
+	// Regenerating queues a background run like Submit does.
+	runs, err := models.ListGenerations(db, athlete.ID)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected 1 generation run, got %d (%v)", len(runs), err)
+	}
+	runURL := generationRunURL(athlete.ID, runs[0].ID)
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != runURL {
+		t.Fatalf("expected 303 to %s, got %d %s", runURL, rr.Code, loc)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		run, err := models.GetGenerationRun(db, runs[0].ID)
+		if err != nil {
+			t.Fatalf("get run: %v", err)
+		}
+		if run.Done() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("regenerate run did not finish")
+		}
+	}
+	if !strings.Contains(prompt, "Replace ONLY day 2") || !strings.Contains(prompt, "single-leg work") {
+		t.Errorf("prompt should scope to day 2 with directions, got: %s", prompt)
+	}
+
+	// The status page merges the finished run into the session, once.
+	for i := 0; i < 2; i++ {
+		req = requestWithUser("GET", runURL, nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("runID", itoa(runs[0].ID))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr = httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Status)).ServeHTTP(rr, req)
+		if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != fmt.Sprintf("/athletes/%d/programs/generate/preview", athlete.ID) {
+			t.Fatalf("expected 303 to preview, got %d %s", rr.Code, loc)
+		}
+	}
+
+	var merged *importers.MappingState
+	var result *llm.GenerationResult
+	var success string
+	check := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		merged, _ = sm.Get(r.Context(), "generate_mapping").(*importers.MappingState)
+		result, _ = sm.Get(r.Context(), "generate_result").(*llm.GenerationResult)
+		success = sm.PopString(r.Context(), "flash_success")
+	}))
+	req = httptest.NewRequest("GET", "/", nil)
+	for _, c := range rr.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	for _, c := range cookies {
+		if _, err := req.Cookie(c.Name); err != nil {
+			req.AddCookie(c)
+		}
+	}
+	check.ServeHTTP(httptest.NewRecorder(), req)
+
+	if merged == nil || result == nil {
+		t.Fatal("expected session data after regenerating")
+	}
+	var day1, day2 []string
+	for _, s := range merged.Parsed.Programs[0].Template.PrescribedSets {
+		if s.Day == 1 {
+			day1 = append(day1, s.Exercise)
+		} else {
+			day2 = append(day2, s.Exercise)
+		}
+	}
+	if len(day1) != 3 {
+		t.Errorf("day 1 = %v, want the coach's 3 edited sets kept", day1)
+	}
+	if len(day2) != 1 || day2[0] != "Step Up" {
+		t.Errorf("day 2 = %v, want [Step Up]", day2)
+	}
+	if result.TokensUsed != 1321 {
+		t.Errorf("tokens = %d, want 1321", result.TokensUsed)
+	}
+	if !strings.Contains(success, "Regenerated day 2") {
+		t.Errorf("flash = %q", success)
+	}
+	if usage, _ := models.MonthlyTokenUsage(db, time.Now()); usage.TokensUsed != 321 {
+		t.Errorf("recorded usage = %d, want 321", usage.TokensUsed)
+	}
+}
+
 func TestGenerate_SaveEdits_NoSession(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -363,6 +951,98 @@ func TestRebuildPrescribedSets(t *testing.T) {
 	}
 }
 
+func TestRebuildPrescribedSets_RepRange(t *testing.T) {
+	rows := []editableSetRow{
+		{ProgramIdx: 0, Week: 1, Day: 1, Exercise: "Curl", NumSets: 3, Reps: "8-12", AmrapLast: true, RepType: "reps", LoadType: "bodyweight", SortOrder: 1},
+	}
+
+	sets := rebuildPrescribedSets(rows)[0]
+	if len(sets) != 3 {
+		t.Fatalf("expected 3 sets, got %d", len(sets))
+	}
+	if sets[0].Reps == nil || *sets[0].Reps != 8 || sets[0].RepMax == nil || *sets[0].RepMax != 12 {
+		t.Errorf("set 1 should be 8-12, got reps=%v rep_max=%v", sets[0].Reps, sets[0].RepMax)
+	}
+	if sets[2].Reps != nil || sets[2].RepMax != nil {
+		t.Error("AMRAP last set should have nil reps and rep_max")
+	}
+
+	// Round-trip back to an editable row keeps the range.
+	prog := importers.ParsedProgram{Template: importers.ParsedProgramTemplate{PrescribedSets: sets}}
+	got := buildEditableRows([]importers.ParsedProgram{prog})
+	if len(got) != 1 || got[0].Reps != "8-12" || !got[0].AmrapLast {
+		t.Errorf("buildEditableRows = %+v, want Reps 8-12 with AmrapLast", got)
+	}
+}
+
+func TestFormatSetReps(t *testing.T) {
+	five, eight, twelve := 5, 8, 12
+	tests := []struct {
+		name    string
+		reps    *int
+		repMax  *int
+		repType string
+		want    string
+	}{
+		{"amrap", nil, nil, "reps", "AMRAP"},
+		{"single", &five, nil, "reps", "5"},
+		{"range", &eight, &twelve, "reps", "8-12"},
+		{"range each side", &eight, &twelve, "each_side", "8-12 each"},
+		{"max not above reps", &eight, &five, "reps", "8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSetReps(tt.reps, tt.repMax, tt.repType); got != tt.want {
+				t.Errorf("formatSetReps = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSetWeight(t *testing.T) {
+	pct, abs, zero, rpe := 0.75, 25.0, 0.0, 8.0
+	tests := []struct {
+		name               string
+		percentage, weight *float64
+		targetRPE          *float64
+		want               string
+	}{
+		{"percentage", &pct, nil, nil, "75%"},
+		{"absolute", nil, &abs, nil, "25 lbs"},
+		{"bodyweight", nil, &zero, nil, "BW"},
+		{"percentage with RPE", &pct, nil, &rpe, "75% @8 RPE"},
+		{"RPE only", nil, nil, &rpe, "@8 RPE"},
+		{"bodyweight with RPE", nil, &zero, &rpe, "BW @8 RPE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSetWeight(tt.percentage, tt.weight, tt.targetRPE); got != tt.want {
+				t.Errorf("formatSetWeight = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebuildPrescribedSets_TargetRPE(t *testing.T) {
+	rows := []editableSetRow{
+		{ProgramIdx: 0, Week: 1, Day: 1, Exercise: "Squat", NumSets: 2, Reps: "5", RepType: "reps", LoadType: "percent", LoadValue: "70", TargetRPE: "7.5", SortOrder: 1},
+		{ProgramIdx: 0, Week: 1, Day: 1, Exercise: "Row", NumSets: 1, Reps: "10", RepType: "reps", LoadType: "absolute", LoadValue: "50", TargetRPE: "11", SortOrder: 2},
+	}
+
+	sets := rebuildPrescribedSets(rows)[0]
+	if sets[0].TargetRPE == nil || *sets[0].TargetRPE != 7.5 {
+		t.Errorf("squat target RPE = %v, want 7.5", sets[0].TargetRPE)
+	}
+	if sets[2].TargetRPE != nil {
+		t.Errorf("out-of-range RPE should be dropped, got %v", *sets[2].TargetRPE)
+	}
+
+	prog := importers.ParsedProgram{Template: importers.ParsedProgramTemplate{PrescribedSets: sets}}
+	if got := buildEditableRows([]importers.ParsedProgram{prog}); got[0].TargetRPE != "7.5" {
+		t.Errorf("editable row TargetRPE = %q, want 7.5", got[0].TargetRPE)
+	}
+}
+
 func TestParseEditableRows_DeleteRemovesRow(t *testing.T) {
 	body := url.Values{
 		"set_count":          {"2"},
@@ -440,3 +1120,164 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGenerate_ContextJSON_Exclude(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	if _, err := models.CreateAthleteNote(db, athlete.ID, coach.ID, "2026-01-10", "Sore knees this week", false, false); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	get := func(target string) string {
+		req := requestWithUser("GET", target, nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.ContextJSON(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d", target, rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	base := "/athletes/" + itoa(athlete.ID) + "/context.json"
+	if !contains(get(base), "Sore knees") {
+		t.Error("expected coach note in default context")
+	}
+	if contains(get(base+"?exclude=coach_notes"), "Sore knees") {
+		t.Error("expected coach note to be excluded")
+	}
+}
+
+func TestGenerate_ContextJSON_OtherCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+
+	coach, _ := models.CreateUser(db, "coacha", "", "password123", "", true, false, sql.NullInt64{})
+	otherCoach, _ := models.CreateUser(db, "coachb", "", "password123", "", true, false, sql.NullInt64{})
+	athlete, _ := models.CreateAthlete(db, "Tommy", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	if _, err := models.CreateAthleteNote(db, athlete.ID, coach.ID, "2026-01-10", "Private knee history", true, false); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	serve := func(user *models.User) *httptest.ResponseRecorder {
+		req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/context.json", nil, user)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.ContextJSON(rr, req)
+		return rr
+	}
+
+	rr := serve(otherCoach)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("other coach: expected 403, got %d", rr.Code)
+	}
+	if contains(rr.Body.String(), "Private knee history") {
+		t.Error("private note leaked to another coach")
+	}
+
+	rr = serve(coach)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("own coach: expected 200, got %d", rr.Code)
+	}
+	if !contains(rr.Body.String(), "Private knee history") {
+		t.Error("expected private note for the athlete's coach")
+	}
+}
+
+func TestGenerate_ContextJSON_ReferencePrograms(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	youthA, err := models.CreateProgramTemplate(db, nil, "Youth A", "", 1, 2, true, "youth", 0, "")
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := models.CreateProgramTemplate(db, nil, "Youth B", "", 1, 2, true, "youth", 0, ""); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	target := "/athletes/" + itoa(athlete.ID) + "/context.json?reference_programs=" + itoa(youthA.ID)
+	req := requestWithUser("GET", target, nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.ContextJSON(rr, req)
+
+	var ctx llm.AthleteContext
+	if err := json.Unmarshal(rr.Body.Bytes(), &ctx); err != nil {
+		t.Fatalf("decode context: %v", err)
+	}
+	if len(ctx.ReferencePrograms) != 1 || ctx.ReferencePrograms[0].Name != "Youth A" {
+		t.Errorf("reference programs = %+v, want only Youth A", ctx.ReferencePrograms)
+	}
+	if sel := ctx.ReferenceSelection; sel.Mode != "selected" || sel.Audience != "youth" || len(sel.TemplateIDs) != 1 {
+		t.Errorf("selection = %+v", sel)
+	}
+}
+
+func TestContextOptionsFromForm(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.Form = url.Values{"program_name": {"X"}}
+	if opts := contextOptionsFromForm(r); opts != (llm.ContextOptions{}) {
+		t.Errorf("form without marker: got %+v, want everything included", opts)
+	}
+
+	r.Form = url.Values{"context_sections": {"1"}, "include_context": {"recent_workouts"}}
+	opts := contextOptionsFromForm(r)
+	if !opts.ExcludeCoachNotes || opts.ExcludeRecentWorkouts || !opts.ExcludeBodyWeights {
+		t.Errorf("got %+v, want notes and body weights excluded", opts)
+	}
+}
+
+func TestGenerate_Submit_TokenCapReached(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Tommy", "sport_performance")
+
+	if err := models.SetSetting(db, "llm.provider", "openai_compatible"); err != nil {
+		t.Fatalf("set provider: %v", err)
+	}
+	if err := models.SetSetting(db, "llm.monthly_token_cap", "1000"); err != nil {
+		t.Fatalf("set cap: %v", err)
+	}
+	if err := models.RecordGenerationUsage(db, 0, coach.ID, "llama3", 1500, 0); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+
+	h := &Generate{DB: db, Sessions: sm, Templates: tc}
+
+	body := url.Values{"program_name": {"Test Program"}, "num_days": {"3"}, "num_weeks": {"4"}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/programs/generate", body, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+
+	sm.LoadAndSave(http.HandlerFunc(h.Submit)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 (re-rendered form with error), got %d", rr.Code)
+	}
+	if !contains(rr.Body.String(), "monthly token budget") {
+		t.Errorf("expected budget error, got: %s", rr.Body.String())
+	}
+	if run, err := models.ActiveGenerationRun(db, athlete.ID); err == nil && run != nil {
+		t.Error("expected no generation run to be created")
+	}
+	if !h.acquireSlot(athlete.ID) {
+		t.Error("expected rate-limit slot to be released")
+	}
+}
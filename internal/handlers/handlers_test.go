@@ -116,7 +116,7 @@ func seedAthlete(t testing.TB, db *sql.DB, name, tier string) *models.Athlete {
 // seedExercise creates an exercise and returns it.
 func seedExercise(t testing.TB, db *sql.DB, name, tier string) *models.Exercise {
 	t.Helper()
-	e, err := models.CreateExercise(db, name, tier, "", "", 0)
+	e, err := models.CreateExercise(db, name, tier, "", "", "", 0)
 	if err != nil {
 		t.Fatalf("seed exercise %q: %v", name, err)
 	}
@@ -126,7 +126,7 @@ func seedExercise(t testing.TB, db *sql.DB, name, tier string) *models.Exercise
 // seedEquipment creates an equipment item and returns it.
 func seedEquipment(t testing.TB, db *sql.DB, name string) *models.Equipment {
 	t.Helper()
-	e, err := models.CreateEquipment(db, name, "")
+	e, err := models.CreateEquipment(db, name, "", 0, 0)
 	if err != nil {
 		t.Fatalf("seed equipment %q: %v", name, err)
 	}
@@ -147,6 +147,13 @@ func requestWithUser(method, target string, body url.Values, user *models.User)
 	return r.WithContext(ctx)
 }
 
+// withWeightUnit returns r with user preferences selecting unit, as the
+// LoadPreferences middleware would.
+func withWeightUnit(r *http.Request, unit string) *http.Request {
+	prefs := &models.UserPreferences{WeightUnit: unit, DateFormat: models.DefaultDateFormat}
+	return r.WithContext(context.WithValue(r.Context(), middleware.PrefsContextKey, prefs))
+}
+
 // itoa is a shorthand for strconv.FormatInt used in test URLs.
 func itoa(id int64) string {
 	return strconv.FormatInt(id, 10)
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// createTestJPEG encodes a w×h JPEG, adding an EXIF block with the given
+// orientation when it isn't 0.
+func createTestJPEG(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode test JPEG: %v", err)
+	}
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// Big-endian TIFF header, IFD0 with a single orientation entry.
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{uint16(orientation), 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	seg := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(seg)+2))
+	app1 = append(app1, seg...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+// testWebP is a 1x1 lossless WebP image. The standard library has no WebP
+// encoder, so it is embedded.
+var testWebP = []byte{
+	'R', 'I', 'F', 'F', 0x1a, 0x00, 0x00, 0x00, 'W', 'E', 'B', 'P',
+	'V', 'P', '8', 'L', 0x0d, 0x00, 0x00, 0x00,
+	0x2f, 0x00, 0x00, 0x00, 0x10, 0x07, 0x10, 0x11, 0x11, 0x88, 0x88, 0xfe, 0x07, 0x00,
+}
+
+func TestFitImage(t *testing.T) {
+	tests := []struct {
+		w, h, max    int
+		wantW, wantH int
+	}{
+		{2000, 1000, 512, 512, 256},
+		{1000, 2000, 512, 256, 512},
+		{100, 50, 512, 100, 50},
+		{3000, 2, 64, 64, 1},
+	}
+	for _, tt := range tests {
+		got := fitImage(image.NewRGBA(image.Rect(0, 0, tt.w, tt.h)), tt.max).Bounds()
+		if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+			t.Errorf("fitImage(%dx%d, %d) = %dx%d, want %dx%d", tt.w, tt.h, tt.max, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+
+	// Downscaling averages the covered pixels.
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 200, A: 255})
+	src.Set(1, 0, color.RGBA{R: 100, A: 255})
+	if got := fitImage(src, 1).RGBAAt(0, 0); got.R != 150 || got.A != 255 {
+		t.Errorf("averaged pixel = %+v, want R=150 A=255", got)
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	if got := jpegOrientation(createTestJPEG(t, 8, 4, 0)); got != 1 {
+		t.Errorf("no EXIF: orientation = %d, want 1", got)
+	}
+	if got := jpegOrientation(createTestJPEG(t, 8, 4, 6)); got != 6 {
+		t.Errorf("orientation = %d, want 6", got)
+	}
+	if got := jpegOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("non-JPEG: orientation = %d, want 1", got)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	marker := color.RGBA{R: 255, A: 255}
+	src.Set(0, 0, marker) // top-left
+
+	tests := []struct {
+		orientation  int
+		wantW, wantH int
+		markX, markY int
+	}{
+		{1, 3, 2, 0, 0},
+		{3, 3, 2, 2, 1},
+		{6, 2, 3, 1, 0}, // rotate clockwise: top-left moves to top-right
+		{8, 2, 3, 0, 2}, // rotate counter-clockwise: top-left moves to bottom-left
+	}
+	for _, tt := range tests {
+		got := orient(src, tt.orientation)
+		b := got.Bounds()
+		if b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			continue
+		}
+		if r, _, _, _ := got.At(tt.markX, tt.markY).RGBA(); r>>8 != 255 {
+			t.Errorf("orientation %d: marker not at (%d,%d)", tt.orientation, tt.markX, tt.markY)
+		}
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	if _, format, err := decodeImage(createTestJPEG(t, 4, 4, 0)); err != nil || format != "jpeg" {
+		t.Errorf("decode JPEG: format %q, err %v", format, err)
+	}
+	if img, format, err := decodeImage(testWebP); err != nil || format != "webp" || img.Bounds().Dx() != 1 {
+		t.Errorf("decode WebP: format %q, err %v", format, err)
+	}
+	if _, _, err := decodeImage([]byte("not an image")); !errors.Is(err, errUnsupportedImage) {
+		t.Errorf("decode text: err = %v, want errUnsupportedImage", err)
+	}
+
+	// The header alone is enough to refuse images too large to decode.
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 6000, 5000)))
+	if _, _, err := decodeImage(buf.Bytes()); !errors.Is(err, errImageTooLarge) {
+		t.Errorf("decode huge PNG: err = %v, want errImageTooLarge", err)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/carpenike/replog/internal/importers"
@@ -79,11 +78,12 @@ func (h *ImportExport) ExportJSON(w http.ResponseWriter, r *http.Request) {
 		h.Templates.ServerError(w, r)
 		return
 	}
+	export.ConvertWeights(preferredWeightUnit(r))
 
 	athlete, _ := models.GetAthleteByID(h.DB, athleteID)
 	filename := "replog-export.json"
 	if athlete != nil {
-		filename = fmt.Sprintf("replog-%s.json", sanitizeFilename(athlete.Name))
+		filename = fmt.Sprintf("replog-%s.json", models.SanitizeFilename(athlete.Name))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -111,14 +111,41 @@ func (h *ImportExport) ExportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filename := fmt.Sprintf("replog-%s.csv", sanitizeFilename(athlete.Name))
+	filename := fmt.Sprintf("replog-%s.csv", models.SanitizeFilename(athlete.Name))
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
-	if err := models.WriteExportStrongCSV(w, h.DB, athleteID); err != nil {
+	if err := models.WriteExportStrongCSV(w, h.DB, athleteID, preferredWeightUnit(r)); err != nil {
 		log.Printf("handlers: write export csv: %v", err)
 	}
 }
 
+// ExportZIP downloads a zip archive with one CSV per exercise the athlete has
+// logged.
+func (h *ImportExport) ExportZIP(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		h.Templates.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for zip export: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	filename := fmt.Sprintf("replog-%s-exercises.zip", models.SanitizeFilename(athlete.Name))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := models.WriteExportPerExerciseZIP(w, h.DB, athleteID, preferredWeightUnit(r)); err != nil {
+		log.Printf("handlers: write export zip: %v", err)
+	}
+}
+
 // --- Import Handlers ---
 
 // ImportPage renders the import upload page.
@@ -213,6 +240,8 @@ func (h *ImportExport) Upload(w http.ResponseWriter, r *http.Request) {
 			format = importers.FormatHevyCSV
 		case "replog_json":
 			format = importers.FormatRepLogJSON
+		case "body_weight_csv":
+			format = importers.FormatBodyWeightCSV
 		default:
 			tplData := map[string]any{
 				"Athlete": athlete,
@@ -233,6 +262,8 @@ func (h *ImportExport) Upload(w http.ResponseWriter, r *http.Request) {
 		parsed, parseErr = importers.ParseHevyCSV(bytes.NewReader(data))
 	case importers.FormatRepLogJSON:
 		parsed, parseErr = importers.ParseRepLogJSON(bytes.NewReader(data))
+	case importers.FormatBodyWeightCSV:
+		parsed, parseErr = importers.ParseBodyWeightCSV(bytes.NewReader(data))
 	}
 	if parseErr != nil {
 		log.Printf("handlers: parse %s file: %v", format, parseErr)
@@ -244,7 +275,7 @@ func (h *ImportExport) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(parsed.Workouts) == 0 && len(parsed.Exercises) == 0 {
+	if len(parsed.Workouts) == 0 && len(parsed.Exercises) == 0 && len(parsed.BodyWeights) == 0 {
 		tplData := map[string]any{
 			"Athlete": athlete,
 			"Error":   "No data found in the uploaded file.",
@@ -262,6 +293,14 @@ func (h *ImportExport) Upload(w http.ResponseWriter, r *http.Request) {
 		weightUnit = "lbs"
 	}
 
+	// Body weights have nothing to map — go straight to the preview.
+	if format == importers.FormatBodyWeightCSV {
+		ms := &importers.MappingState{Format: format, WeightUnit: weightUnit, Parsed: parsed}
+		h.Sessions.Put(r.Context(), "import_mapping", ms)
+		h.renderImportPreview(w, r, athlete, ms)
+		return
+	}
+
 	// Build initial mappings.
 	existingExercises, err := listExistingExercises(h.DB)
 	if err != nil {
@@ -429,8 +468,13 @@ func (h *ImportExport) Preview(w http.ResponseWriter, r *http.Request) {
 	// Save updated mapping state.
 	h.Sessions.Put(r.Context(), "import_mapping", ms)
 
-	// Build preview.
-	preview, err := models.BuildImportPreview(h.DB, athleteID, ms)
+	h.renderImportPreview(w, r, athlete, ms)
+}
+
+// renderImportPreview builds the dry-run summary for a resolved mapping and
+// renders the preview page.
+func (h *ImportExport) renderImportPreview(w http.ResponseWriter, r *http.Request, athlete *models.Athlete, ms *importers.MappingState) {
+	preview, err := models.BuildImportPreview(h.DB, athlete.ID, ms)
 	if err != nil {
 		log.Printf("handlers: build import preview: %v", err)
 		h.Templates.ServerError(w, r)
@@ -438,10 +482,11 @@ func (h *ImportExport) Preview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tplData := map[string]any{
-		"Athlete":      athlete,
-		"Preview":      preview,
-		"MappingState": ms,
-		"IsRepLogJSON": ms.Format == importers.FormatRepLogJSON,
+		"Athlete":         athlete,
+		"Preview":         preview,
+		"MappingState":    ms,
+		"IsRepLogJSON":    ms.Format == importers.FormatRepLogJSON,
+		"BodyWeightsOnly": ms.Format == importers.FormatBodyWeightCSV,
 	}
 	if err := h.Templates.Render(w, r, "import_preview.html", tplData); err != nil {
 		log.Printf("handlers: render preview page: %v", err)
@@ -449,6 +494,112 @@ func (h *ImportExport) Preview(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// BodyWeightImportPage renders the scale CSV upload page. Unlike the general
+// import, athletes may use it to bring in their own weigh-ins.
+func (h *ImportExport) BodyWeightImportPage(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		h.Templates.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for body weight import: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	data := map[string]any{
+		"Athlete": athlete,
+	}
+	if err := h.Templates.Render(w, r, "body_weight_import.html", data); err != nil {
+		log.Printf("handlers: render body weight import page: %v", err)
+		h.Templates.ServerError(w, r)
+	}
+}
+
+// BodyWeightUpload parses a scale CSV and shows the import preview. Body
+// weights need no mapping step; dates already logged are skipped on execute.
+func (h *ImportExport) BodyWeightUpload(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		h.Templates.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for body weight upload: %v", athleteID, err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	renderError := func(msg string) {
+		data := map[string]any{
+			"Athlete": athlete,
+			"Error":   msg,
+		}
+		h.Templates.Render(w, r, "body_weight_import.html", data)
+	}
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		renderError("File too large. Maximum size is 10 MB.")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		renderError("Please select a file to upload.")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	if err != nil {
+		log.Printf("handlers: read body weight upload: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+	if int64(len(data)) > maxUploadSize {
+		renderError("File too large. Maximum size is 10 MB.")
+		return
+	}
+
+	parsed, err := importers.ParseBodyWeightCSV(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("handlers: parse body weight csv: %v", err)
+		renderError(fmt.Sprintf("Failed to parse file: %v", err))
+		return
+	}
+	if len(parsed.BodyWeights) == 0 {
+		renderError("No weigh-ins found in the uploaded file.")
+		return
+	}
+
+	weightUnit := r.FormValue("weight_unit")
+	if weightUnit == "" {
+		weightUnit = parsed.WeightUnit
+	}
+	if weightUnit == "" {
+		weightUnit = "lbs"
+	}
+
+	ms := &importers.MappingState{
+		Format:     importers.FormatBodyWeightCSV,
+		WeightUnit: weightUnit,
+		Parsed:     parsed,
+	}
+	h.Sessions.Put(r.Context(), "import_mapping", ms)
+	h.renderImportPreview(w, r, athlete, ms)
+}
+
 // Execute performs the actual import.
 func (h *ImportExport) Execute(w http.ResponseWriter, r *http.Request) {
 	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
@@ -473,11 +624,19 @@ func (h *ImportExport) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the coach user ID for reviews.
+	// Non-coaches reach this handler only through the body weight import.
 	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin && ms.Format != importers.FormatBodyWeightCSV {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	// Get the coach user ID for reviews.
 	coachID := user.ID
 
-	result, err := models.ExecuteImport(h.DB, athleteID, coachID, ms)
+	mode := models.ParseConflictMode(r.FormValue("conflict_mode"))
+
+	result, err := models.ExecuteImport(h.DB, athleteID, coachID, ms, mode)
 	if err != nil {
 		log.Printf("handlers: execute import for athlete %d: %v", athleteID, err)
 		tplData := map[string]any{
@@ -504,13 +663,18 @@ func (h *ImportExport) Execute(w http.ResponseWriter, r *http.Request) {
 // --- Helpers ---
 
 func listExistingExercises(db *sql.DB) ([]importers.ExistingEntity, error) {
-	exercises, err := models.ListExercises(db, "")
+	// Archived exercises still match by name so old workouts map onto them.
+	exercises, err := models.ListExercises(db, models.ExerciseFilter{IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := models.ListAliasesByExercise(db)
 	if err != nil {
 		return nil, err
 	}
 	result := make([]importers.ExistingEntity, len(exercises))
 	for i, e := range exercises {
-		result[i] = importers.ExistingEntity{ID: e.ID, Name: e.Name}
+		result[i] = importers.ExistingEntity{ID: e.ID, Name: e.Name, Aliases: aliases[e.ID]}
 	}
 	return result, nil
 }
@@ -532,9 +696,13 @@ func listExistingPrograms(db *sql.DB) ([]importers.ExistingEntity, error) {
 	if err != nil {
 		return nil, err
 	}
+	hashes, err := models.ProgramTemplateHashes(db)
+	if err != nil {
+		return nil, err
+	}
 	result := make([]importers.ExistingEntity, len(programs))
 	for i, p := range programs {
-		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name}
+		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name, Hash: hashes[p.ID]}
 	}
 	return result, nil
 }
@@ -544,29 +712,17 @@ func listExistingProgramsForAthlete(db *sql.DB, athleteID int64) ([]importers.Ex
 	if err != nil {
 		return nil, err
 	}
+	hashes, err := models.ProgramTemplateHashes(db)
+	if err != nil {
+		return nil, err
+	}
 	result := make([]importers.ExistingEntity, len(programs))
 	for i, p := range programs {
-		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name}
+		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name, Hash: hashes[p.ID]}
 	}
 	return result, nil
 }
 
-func sanitizeFilename(name string) string {
-	r := strings.NewReplacer(
-		" ", "-", "/", "-", "\\", "-",
-		".", "", ",", "", "'", "", "\"", "",
-	)
-	s := r.Replace(strings.ToLower(name))
-	// Remove any remaining non-alphanumeric chars except dash.
-	var clean []byte
-	for _, c := range []byte(s) {
-		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' {
-			clean = append(clean, c)
-		}
-	}
-	return string(clean)
-}
-
 // --- Catalog Export/Import Handlers (global — no athlete) ---
 
 // CatalogExportPage renders the catalog export page.
@@ -773,7 +929,12 @@ func (h *ImportExport) CatalogPreview(w http.ResponseWriter, r *http.Request) {
 
 	h.Sessions.Put(r.Context(), "catalog_import_mapping", ms)
 
-	preview := models.BuildCatalogImportPreview(ms)
+	preview, err := models.BuildCatalogImportPreview(h.DB, ms)
+	if err != nil {
+		log.Printf("handlers: build catalog import preview: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
 
 	tplData := map[string]any{
 		"Preview":      preview,
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestImportExport_BodyWeightUpload_AthleteOwnData(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	a := seedAthlete(t, db, "Athlete", "")
+	athleteUser := seedNonCoach(t, db, a.ID)
+	models.CreateBodyWeight(db, a.ID, "2026-02-02", 186.0, "")
+
+	h := &ImportExport{DB: db, Sessions: sm, Templates: tc}
+
+	csv := "Date,Weight (lb),Body Fat %\n2026-02-01,185.4,18.2\n2026-02-02,186.0,18.1\n2026-02-03,185.0,18.0\n"
+	body, contentType := createMultipartFile(t, "file", "scale.csv", []byte(csv))
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/body-weights/import", nil, athleteUser)
+	req.Body = io.NopCloser(body)
+	req.Header.Set("Content-Type", contentType)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.BodyWeightUpload)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("upload: expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Body Weights: 3") {
+		t.Errorf("expected 3 body weights in preview, got: %s", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "Already logged: 2026-02-02") {
+		t.Error("expected existing date to be flagged in preview")
+	}
+
+	req = requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/body-weights/import/execute", nil, athleteUser)
+	req.SetPathValue("id", itoa(a.ID))
+	for _, c := range rr.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Execute)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("execute: expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "2 created, 1 skipped") {
+		t.Errorf("expected 2 created and 1 skipped, got: %s", rr.Body.String())
+	}
+	if page, _ := models.ListBodyWeights(db, a.ID, 0); len(page.Entries) != 3 {
+		t.Errorf("body weight entries = %d, want 3", len(page.Entries))
+	}
+}
+
+func TestImportExport_BodyWeightUpload_OtherAthleteForbidden(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	a := seedAthlete(t, db, "Athlete", "")
+	other := seedAthlete(t, db, "Other", "")
+	otherUser := seedNonCoach(t, db, other.ID)
+
+	h := &ImportExport{DB: db, Sessions: sm, Templates: tc}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/body-weights/import", nil, otherUser)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.BodyWeightImportPage(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestImportExport_ExportZIP(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+
+	a := seedAthlete(t, db, "Athlete", "")
+	athleteUser := seedNonCoach(t, db, a.ID)
+	other := seedAthlete(t, db, "Other", "")
+
+	h := &ImportExport{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/export/zip", nil, athleteUser)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.ExportZIP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "replog-athlete-exercises.zip") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(other.ID)+"/export/zip", nil, athleteUser)
+	req.SetPathValue("id", itoa(other.ID))
+	rr = httptest.NewRecorder()
+	h.ExportZIP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("other athlete: expected 403, got %d", rr.Code)
+	}
+}
@@ -2,9 +2,14 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"database/sql"
@@ -13,10 +18,14 @@ import (
 	"github.com/carpenike/replog/internal/models"
 )
 
+// maxAttachmentSize is the maximum allowed note image size (5 MB).
+const maxAttachmentSize = 5 << 20
+
 // Journal holds dependencies for the journal timeline and athlete notes handlers.
 type Journal struct {
-	DB        *sql.DB
-	Templates TemplateCache
+	DB            *sql.DB
+	Templates     TemplateCache
+	AttachmentDir string // Filesystem directory where note images are stored.
 }
 
 // Timeline renders the unified journal view for an athlete.
@@ -45,19 +54,51 @@ func (h *Journal) Timeline(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	q := r.URL.Query()
+	filter := models.JournalFilter{From: q.Get("from"), To: q.Get("to")}
+	for _, t := range q["type"] {
+		if t != "" {
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
 	canManage := middleware.CanManageAthlete(user, athlete)
-	entries, err := models.ListJournalEntries(h.DB, athleteID, canManage, 200)
+	page, err := models.ListJournalPage(h.DB, athleteID, canManage, filter, offset)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Invalid journal filter", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: list journal entries for athlete %d: %v", athleteID, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	// Carry the filter into the Load More link so later pages stay filtered.
+	more := url.Values{"offset": {strconv.Itoa(offset + models.JournalPageSize)}}
+	for _, t := range filter.Types {
+		more.Add("type", t)
+	}
+	if filter.From != "" {
+		more.Set("from", filter.From)
+	}
+	if filter.To != "" {
+		more.Set("to", filter.To)
+	}
+
 	isOwnProfile := user.AthleteID.Valid && user.AthleteID.Int64 == athleteID
 
 	data := map[string]any{
 		"Athlete":      athlete,
-		"Entries":      entries,
+		"Entries":      page.Entries,
+		"HasMore":      page.HasMore,
+		"LoadMoreURL":  fmt.Sprintf("/athletes/%d/journal?%s", athleteID, more.Encode()),
+		"Filter":       filter,
 		"CanManage":    canManage,
 		"IsOwnProfile": isOwnProfile,
 		"Today":        time.Now().Format("2006-01-02"),
@@ -69,6 +110,57 @@ func (h *Journal) Timeline(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Search renders full-text search results across an athlete's notes and
+// workout notes. An empty query shows just the search form.
+func (h *Journal) Search(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	athleteID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for journal search: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	var results []*models.NoteSearchResult
+	if query != "" {
+		results, err = models.SearchNotes(h.DB, athleteID, middleware.CanManageAthlete(user, athlete), query)
+		if err != nil && !errors.Is(err, models.ErrInvalidInput) {
+			log.Printf("handlers: search notes for athlete %d: %v", athleteID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	data := map[string]any{
+		"Athlete": athlete,
+		"Query":   query,
+		"Results": results,
+	}
+
+	if err := h.Templates.Render(w, r, "journal_search.html", data); err != nil {
+		log.Printf("handlers: journal search template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
 // CreateNote handles new athlete note submission. Athletes can add their own
 // notes; coaches/admins can also set private and pinned flags.
 func (h *Journal) CreateNote(w http.ResponseWriter, r *http.Request) {
@@ -91,13 +183,16 @@ func (h *Journal) CreateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Bad request", http.StatusBadRequest)
+	// Notes with an image arrive as multipart; plain notes are urlencoded.
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+64<<10) // extra for form overhead
+	if err := r.ParseMultipartForm(maxAttachmentSize); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		log.Printf("handlers: parse note form: %v", err)
+		http.Error(w, "Image too large. Maximum size is 5 MB.", http.StatusRequestEntityTooLarge)
 		return
 	}
 
@@ -117,13 +212,51 @@ func (h *Journal) CreateNote(w http.ResponseWriter, r *http.Request) {
 	isPrivate := canManage && r.FormValue("is_private") == "1"
 	pinned := canManage && r.FormValue("pinned") == "1"
 
-	_, err = models.CreateAthleteNote(h.DB, athleteID, user.ID, date, content, isPrivate, pinned)
+	// Store the optional image before creating the note so a rejected file
+	// doesn't leave a note behind.
+	var imageName, imageType string
+	var imageSize int64
+	if file, header, err := r.FormFile("image"); err == nil {
+		defer file.Close()
+		if header.Size > maxAttachmentSize {
+			http.Error(w, "Image too large. Maximum size is 5 MB.", http.StatusRequestEntityTooLarge)
+			return
+		}
+		imageName, imageType, err = saveImageUpload(file, h.AttachmentDir, fmt.Sprintf("%d_", athleteID))
+		if errors.Is(err, errUnsupportedImage) {
+			http.Error(w, "Unsupported file type. Use JPEG, PNG, GIF, or WebP.", http.StatusUnprocessableEntity)
+			return
+		}
+		if err != nil {
+			log.Printf("handlers: save note image for athlete %d: %v", athleteID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		imageSize = header.Size
+	} else if !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart) {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	note, err := models.CreateAthleteNote(h.DB, athleteID, user.ID, date, content, isPrivate, pinned)
 	if err != nil {
 		log.Printf("handlers: create note for athlete %d: %v", athleteID, err)
+		if imageName != "" {
+			os.Remove(filepath.Join(h.AttachmentDir, imageName))
+		}
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	if imageName != "" {
+		if _, err := models.CreateNoteAttachment(h.DB, note.ID, imageName, imageType, imageSize); err != nil {
+			log.Printf("handlers: attach image to note %d: %v", note.ID, err)
+			os.Remove(filepath.Join(h.AttachmentDir, imageName))
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/journal", http.StatusSeeOther)
 }
 
@@ -208,6 +341,53 @@ func (h *Journal) UpdateNote(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/journal", http.StatusSeeOther)
 }
 
+// AcknowledgeNote records that the athlete read a note. Only the athlete's
+// own user can acknowledge; coaches see the status on the timeline.
+func (h *Journal) AcknowledgeNote(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	athleteID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !user.AthleteID.Valid || user.AthleteID.Int64 != athleteID {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	noteID, err := strconv.ParseInt(r.PathValue("noteID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return
+	}
+
+	note, err := models.GetAthleteNoteByID(h.DB, noteID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Note not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get note %d: %v", noteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	// Private notes are hidden from the athlete, so treat them as missing.
+	if note.AthleteID != athleteID || note.IsPrivate {
+		http.Error(w, "Note not found", http.StatusNotFound)
+		return
+	}
+
+	if _, err := models.AcknowledgeAthleteNote(h.DB, noteID); err != nil {
+		log.Printf("handlers: acknowledge note %d: %v", noteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/journal", http.StatusSeeOther)
+}
+
 // DeleteNote removes an athlete note (coach/admin only).
 func (h *Journal) DeleteNote(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -256,11 +436,74 @@ func (h *Journal) DeleteNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	attachments, err := models.ListNoteAttachments(h.DB, noteID)
+	if err != nil {
+		log.Printf("handlers: list attachments for note %d: %v", noteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	if err := models.DeleteAthleteNote(h.DB, noteID); err != nil {
 		log.Printf("handlers: delete note %d: %v", noteID, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	// Attachment rows cascade with the note; remove their files too.
+	for _, a := range attachments {
+		os.Remove(filepath.Join(h.AttachmentDir, a.Filename)) // best-effort cleanup
+	}
+
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/journal", http.StatusSeeOther)
 }
+
+// ServeAttachment serves a note image to users who can see the note: anyone
+// with access to the athlete, or only coaches for private notes.
+func (h *Journal) ServeAttachment(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	filePath := uploadPath(h.AttachmentDir, r.PathValue("filename"))
+	if filePath == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	attachment, err := models.GetNoteAttachmentByFilename(h.DB, filepath.Base(filePath))
+	if errors.Is(err, models.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get note attachment: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !middleware.CanAccessAthlete(h.DB, user, attachment.AthleteID) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+	if attachment.IsPrivate {
+		athlete, err := models.GetAthleteByID(h.DB, attachment.AthleteID)
+		if err != nil {
+			log.Printf("handlers: get athlete %d for attachment: %v", attachment.AthleteID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !middleware.CanManageAthlete(user, athlete) {
+			h.Templates.Forbidden(w, r)
+			return
+		}
+	}
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Access-gated, so only the browser may cache it.
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeFile(w, r, filePath)
+}
@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/carpenike/replog/internal/models"
@@ -31,6 +38,84 @@ func TestJournal_Timeline_CoachAccess(t *testing.T) {
 	}
 }
 
+func TestJournal_Timeline_Filters(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+
+	models.CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+	models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-02", "Early note", false, false)
+	models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-09", "Late note", false, false)
+
+	h := &Journal{DB: db, Templates: tc}
+	get := func(query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/journal?"+query, nil, coach)
+		req.SetPathValue("id", itoa(a.ID))
+		rr := httptest.NewRecorder()
+		h.Timeline(rr, req)
+		return rr
+	}
+
+	rr := get("type=note&from=2026-03-05")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !contains(body, "Late note") {
+		t.Error("expected the note inside the date range")
+	}
+	if contains(body, "Early note") || contains(body, `data-type="workout"`) {
+		t.Error("expected entries outside the filter to be excluded")
+	}
+
+	if rr := get("type=bogus"); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown type: expected 400, got %d", rr.Code)
+	}
+	if rr := get("to=yesterday"); rr.Code != http.StatusBadRequest {
+		t.Errorf("malformed date: expected 400, got %d", rr.Code)
+	}
+}
+
+func TestJournal_Timeline_PaginationKeepsFilter(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+
+	for i := 0; i <= models.JournalPageSize; i++ {
+		models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-02", "Note", false, false)
+	}
+	models.CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+
+	h := &Journal{DB: db, Templates: tc}
+	req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/journal?type=note&from=2026-03-01", nil, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.Timeline(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	want := fmt.Sprintf("/journal?from=2026-03-01&amp;offset=%d&amp;type=note", models.JournalPageSize)
+	if body := rr.Body.String(); !contains(body, want) {
+		t.Errorf("expected Load More link %q carrying the filter", want)
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/journal?type=note&offset="+itoa(int64(models.JournalPageSize)), nil, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr = httptest.NewRecorder()
+	h.Timeline(rr, req)
+	body := rr.Body.String()
+	if n := strings.Count(body, `class="journal-entry"`); n != 1 {
+		t.Errorf("second page has %d entries, want 1", n)
+	}
+	if contains(body, `data-type="workout"`) {
+		t.Error("second page should stay filtered to notes")
+	}
+}
+
 func TestJournal_Timeline_NonCoachOwnAthlete(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -809,3 +894,279 @@ func TestJournal_DeleteNote_AthleteNotFound(t *testing.T) {
 		t.Errorf("expected 404, got %d", rr.Code)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// AcknowledgeNote (POST)
+// ---------------------------------------------------------------------------
+
+func TestJournal_AcknowledgeNote(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+	owner := seedNonCoach(t, db, a.ID)
+	h := &Journal{DB: db, Templates: tc}
+
+	cue, _ := models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Knees out on the descent", false, true)
+	private, _ := models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Watch for fatigue", true, false)
+
+	ack := func(user *models.User, noteID int64) int {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/notes/"+itoa(noteID)+"/acknowledge", nil, user)
+		req.SetPathValue("id", itoa(a.ID))
+		req.SetPathValue("noteID", itoa(noteID))
+		rr := httptest.NewRecorder()
+		h.AcknowledgeNote(rr, req)
+		return rr.Code
+	}
+	timeline := func(user *models.User) string {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/journal", nil, user)
+		req.SetPathValue("id", itoa(a.ID))
+		rr := httptest.NewRecorder()
+		h.Timeline(rr, req)
+		return rr.Body.String()
+	}
+
+	if body := timeline(coach); !contains(body, "Not seen yet") {
+		t.Error("coach should see an unacknowledged pinned cue as not seen")
+	}
+	if body := timeline(owner); !contains(body, "Got it") {
+		t.Error("athlete should be offered an acknowledge button")
+	}
+
+	if code := ack(coach, cue.ID); code != http.StatusForbidden {
+		t.Errorf("coach acknowledge: expected 403, got %d", code)
+	}
+	if code := ack(owner, private.ID); code != http.StatusNotFound {
+		t.Errorf("private note acknowledge: expected 404, got %d", code)
+	}
+	if code := ack(owner, cue.ID); code != http.StatusSeeOther {
+		t.Fatalf("owner acknowledge: expected 303, got %d", code)
+	}
+
+	note, _ := models.GetAthleteNoteByID(db, cue.ID)
+	if !note.AcknowledgedAt.Valid {
+		t.Fatal("expected note to be acknowledged")
+	}
+	if body := timeline(coach); !contains(body, "Seen "+note.AcknowledgedAt.Time.Format("2006-01-02")) {
+		t.Error("coach should see when the athlete acknowledged the cue")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Note attachments
+// ---------------------------------------------------------------------------
+
+// noteFormWithImage builds a multipart note submission with an image file.
+func noteFormWithImage(t *testing.T, fields map[string]string, fileName string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		writer.WriteField(k, v)
+	}
+	part, err := writer.CreateFormFile("image", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+	return &buf, writer.FormDataContentType()
+}
+
+func TestJournal_CreateNote_WithImage(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+	dir := t.TempDir()
+	h := &Journal{DB: db, Templates: tc, AttachmentDir: dir}
+
+	post := func(fileName string, content []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		body, contentType := noteFormWithImage(t, map[string]string{"content": "Squat depth check", "date": "2026-03-01"}, fileName, content)
+		req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/notes", nil, coach)
+		req.Body = io.NopCloser(body)
+		req.Header.Set("Content-Type", contentType)
+		req.SetPathValue("id", itoa(a.ID))
+		rr := httptest.NewRecorder()
+		h.CreateNote(rr, req)
+		return rr
+	}
+
+	t.Run("stores image under a random name", func(t *testing.T) {
+		rr := post("../../etc/passwd.png", createTestPNG(t))
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+		notes, _ := models.ListAthleteNotes(db, a.ID, true)
+		if len(notes) != 1 {
+			t.Fatalf("expected 1 note, got %d", len(notes))
+		}
+		attachments, err := models.ListNoteAttachments(db, notes[0].ID)
+		if err != nil || len(attachments) != 1 {
+			t.Fatalf("attachments = %v, %v; want 1", attachments, err)
+		}
+		got := attachments[0]
+		if got.ContentType != "image/png" || !strings.HasSuffix(got.Filename, ".png") || strings.Contains(got.Filename, "passwd") {
+			t.Errorf("attachment = %+v, want sniffed PNG with a random name", got)
+		}
+		if _, err := os.Stat(filepath.Join(dir, got.Filename)); err != nil {
+			t.Errorf("attachment file missing: %v", err)
+		}
+	})
+
+	t.Run("rejects non-image without creating a note", func(t *testing.T) {
+		rr := post("photo.png", []byte("<html><script>alert(1)</script></html>"))
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected 422, got %d", rr.Code)
+		}
+		if notes, _ := models.ListAthleteNotes(db, a.ID, true); len(notes) != 1 {
+			t.Errorf("expected rejected upload to leave 1 note, got %d", len(notes))
+		}
+	})
+}
+
+func TestJournal_ServeAttachment(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+	other := seedAthlete(t, db, "Other", "foundational")
+	dir := t.TempDir()
+	h := &Journal{DB: db, Templates: tc, AttachmentDir: dir}
+
+	attach := func(private bool, filename string) {
+		t.Helper()
+		note, err := models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Form check", private, false)
+		if err != nil {
+			t.Fatalf("create note: %v", err)
+		}
+		png := createTestPNG(t)
+		if err := os.WriteFile(filepath.Join(dir, filename), png, 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if _, err := models.CreateNoteAttachment(db, note.ID, filename, "image/png", int64(len(png))); err != nil {
+			t.Fatalf("create attachment: %v", err)
+		}
+	}
+	attach(false, "public.png")
+	attach(true, "private.png")
+	if err := os.WriteFile(filepath.Join(dir, "orphan.png"), createTestPNG(t), 0o644); err != nil {
+		t.Fatalf("write orphan: %v", err)
+	}
+
+	serve := func(user *models.User, filename string) int {
+		t.Helper()
+		req := requestWithUser("GET", "/journal/attachments/"+filename, nil, user)
+		req.SetPathValue("filename", filename)
+		rr := httptest.NewRecorder()
+		h.ServeAttachment(rr, req)
+		return rr.Code
+	}
+
+	owner := seedNonCoach(t, db, a.ID)
+	stranger := seedNonCoachWithUsername(t, db, "stranger", other.ID)
+
+	cases := []struct {
+		name     string
+		user     *models.User
+		filename string
+		want     int
+	}{
+		{"coach sees public", coach, "public.png", http.StatusOK},
+		{"coach sees private", coach, "private.png", http.StatusOK},
+		{"athlete sees own public", owner, "public.png", http.StatusOK},
+		{"athlete cannot see private", owner, "private.png", http.StatusForbidden},
+		{"other athlete forbidden", stranger, "public.png", http.StatusForbidden},
+		{"file without a note row", coach, "orphan.png", http.StatusNotFound},
+		{"traversal", coach, "..%2Fjournal.db", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := serve(tc.user, tc.filename); got != tc.want {
+				t.Errorf("status = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJournal_DeleteNote_RemovesAttachmentFiles(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+	dir := t.TempDir()
+	h := &Journal{DB: db, Templates: tc, AttachmentDir: dir}
+
+	note, _ := models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Form check", false, false)
+	path := filepath.Join(dir, "photo.png")
+	os.WriteFile(path, createTestPNG(t), 0o644)
+	models.CreateNoteAttachment(db, note.ID, "photo.png", "image/png", 100)
+
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/notes/"+itoa(note.ID)+"/delete", nil, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	req.SetPathValue("noteID", itoa(note.ID))
+	rr := httptest.NewRecorder()
+	h.DeleteNote(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected attachment file to be removed with the note")
+	}
+	if _, err := models.GetNoteAttachmentByFilename(db, "photo.png"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("attachment row err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestJournal_Search(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Kid", "foundational")
+	kid := seedNonCoach(t, db, a.ID)
+
+	models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-02", "Brace hard before the descent", false, false)
+	models.CreateAthleteNote(db, a.ID, coach.ID, "2026-03-03", "Bracing needs work, keep an eye on it", true, false)
+	models.CreateWorkout(db, a.ID, "2026-03-04", "Bracing felt solid today", 0)
+
+	h := &Journal{DB: db, Templates: tc}
+	search := func(user *models.User, q string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/journal/search?q="+q, nil, user)
+		req.SetPathValue("id", itoa(a.ID))
+		rr := httptest.NewRecorder()
+		h.Search(rr, req)
+		return rr
+	}
+
+	rr := search(coach, "bracing")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !contains(body, "keep an eye") || !contains(body, `data-type="workout"`) {
+		t.Error("expected coach to see the private note and workout notes")
+	}
+
+	rr = search(kid, "bracing")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if contains(rr.Body.String(), "keep an eye") {
+		t.Error("expected private note hidden from the athlete")
+	}
+
+	if rr := search(coach, ""); rr.Code != http.StatusOK || contains(rr.Body.String(), "search-result") {
+		t.Errorf("empty query: expected 200 with no results, got %d", rr.Code)
+	}
+
+	other := seedAthlete(t, db, "Other", "foundational")
+	outsider := seedNonCoachWithUsername(t, db, "outsider", other.ID)
+	if rr := search(outsider, "bracing"); rr.Code != http.StatusForbidden {
+		t.Errorf("other athlete: expected 403, got %d", rr.Code)
+	}
+}
@@ -48,6 +48,12 @@ func (h *LoginTokens) TokenLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.Sessions.Put(r.Context(), "userID", user.ID)
+	if err := recordSession(h.Sessions, h.DB, r, user.ID); err != nil {
+		log.Printf("handlers: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	setSessionLifetime(h.Sessions, h.DB, r, user)
 
 	// Ensure default preferences exist for this user.
 	if err := models.EnsureUserPreferences(h.DB, user.ID); err != nil {
@@ -82,7 +88,7 @@ func (h *LoginTokens) GenerateToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify user exists.
-	_, err = models.GetUserByID(h.DB, id)
+	target, err := models.GetUserByID(h.DB, id)
 	if err != nil {
 		log.Printf("handlers: get user %d for token: %v", id, err)
 		http.Error(w, "User not found", http.StatusNotFound)
@@ -97,6 +103,7 @@ func (h *LoginTokens) GenerateToken(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
+	recordAudit(h.DB, authUser.ID, models.AuditLoginTokenCreate, models.AuditTarget("user", target.ID, target.Username))
 
 	// Build the full login URL.
 	var loginURL string
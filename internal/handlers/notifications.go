@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/carpenike/replog/internal/middleware"
@@ -43,10 +46,20 @@ func (h *Notifications) List(w http.ResponseWriter, r *http.Request) {
 }
 
 // UnreadCount returns the unread notification badge as an HTML fragment.
+// The response carries an ETag built from the user's newest notification ID
+// and unread count, so polls with a matching If-None-Match get a 304.
 // GET /notifications/count
 func (h *Notifications) UnreadCount(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
-	count, _ := models.GetUnreadCount(h.DB, user.ID)
+	maxID, count, _ := models.GetUnreadState(h.DB, user.ID)
+
+	etag := fmt.Sprintf(`"n%d-%d"`, maxID, count)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if count > 0 {
@@ -61,6 +74,18 @@ func (h *Notifications) UnreadCount(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// etagMatches reports whether an If-None-Match header value matches etag.
+// Weak validators compare equal to their strong form.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // Toast returns new unread notifications as toast HTML fragments for htmx polling.
 // GET /notifications/toast
 func (h *Notifications) Toast(w http.ResponseWriter, r *http.Request) {
@@ -168,14 +193,7 @@ func (h *Notifications) MarkAllRead(w http.ResponseWriter, r *http.Request) {
 func (h *Notifications) Preferences(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
 
-	prefs := models.ListNotificationPreferences(h.DB, user.ID)
-	externalConfigured := models.GetSetting(h.DB, "notify.urls") != ""
-
-	data := map[string]any{
-		"NotificationPrefs":  prefs,
-		"NotificationTypes":  models.AllNotificationTypes,
-		"ExternalConfigured": externalConfigured,
-	}
+	data := h.preferencesData(user.ID)
 	if err := h.Templates.Render(w, r, "notification_preferences.html", data); err != nil {
 		log.Printf("handlers: render notification preferences: %v", err)
 	}
@@ -199,20 +217,85 @@ func (h *Notifications) UpdatePreferences(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	prefs := models.ListNotificationPreferences(h.DB, user.ID)
-	externalConfigured := models.GetSetting(h.DB, "notify.urls") != ""
+	if frequency := r.FormValue("digest_frequency"); frequency != "" {
+		if err := models.SetDigestFrequency(h.DB, user.ID, frequency); err != nil {
+			log.Printf("handlers: set digest frequency for user %d: %v", user.ID, err)
+		}
+	}
 
-	data := map[string]any{
-		"NotificationPrefs":  prefs,
-		"NotificationTypes":  models.AllNotificationTypes,
-		"ExternalConfigured": externalConfigured,
-		"Success":            "Notification preferences saved.",
+	errMsg := h.saveWebhook(user.ID, r)
+
+	data := h.preferencesData(user.ID)
+	if errMsg != "" {
+		data["Error"] = errMsg
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	} else {
+		data["Success"] = "Notification preferences saved."
 	}
 	if err := h.Templates.Render(w, r, "notification_preferences.html", data); err != nil {
 		log.Printf("handlers: render notification preferences: %v", err)
 	}
 }
 
+// saveWebhook applies the webhook fields of the preferences form. An empty
+// URL removes the webhook, and the masked secret placeholder keeps the
+// current secret. Returns a user-facing error message, or "" on success.
+func (h *Notifications) saveWebhook(userID int64, r *http.Request) string {
+	webhookURL := strings.TrimSpace(r.FormValue("webhook_url"))
+	if webhookURL == "" {
+		if err := models.DeleteNotificationWebhook(h.DB, userID); err != nil {
+			log.Printf("handlers: delete webhook for user %d: %v", userID, err)
+			return "Failed to remove webhook."
+		}
+		return ""
+	}
+
+	secret := r.FormValue("webhook_secret")
+	if isMaskedPlaceholder(secret) {
+		secret = ""
+		if hook, err := models.GetNotificationWebhook(h.DB, userID); err == nil {
+			secret = hook.Secret
+		}
+	}
+
+	err := models.SetNotificationWebhook(h.DB, userID, webhookURL, secret)
+	if errors.Is(err, models.ErrPrivateWebhookAddress) {
+		return "Webhook URL must not point to a local or private network address."
+	}
+	if errors.Is(err, models.ErrInvalidInput) {
+		return "Webhook URL must start with http:// or https://."
+	}
+	if err != nil {
+		log.Printf("handlers: set webhook for user %d: %v", userID, err)
+		return "Failed to save webhook — is REPLOG_SECRET_KEY set?"
+	}
+	return ""
+}
+
+// preferencesData builds the template data for the notification preferences form.
+func (h *Notifications) preferencesData(userID int64) map[string]any {
+	digestFrequency := models.DigestOff
+	if prefs, err := models.GetUserPreferences(h.DB, userID); err != nil {
+		log.Printf("handlers: get preferences for user %d: %v", userID, err)
+	} else {
+		digestFrequency = prefs.DigestFrequency
+	}
+
+	webhook, err := models.GetNotificationWebhook(h.DB, userID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		log.Printf("handlers: get webhook for user %d: %v", userID, err)
+	}
+
+	return map[string]any{
+		"NotificationPrefs":  models.ListNotificationPreferences(h.DB, userID),
+		"NotificationTypes":  models.AllNotificationTypes,
+		"ExternalConfigured": models.GetSetting(h.DB, "notify.urls") != "" || webhook != nil,
+		"DigestFrequency":    digestFrequency,
+		"DigestFrequencies":  models.ValidDigestFrequencies,
+		"Webhook":            webhook,
+	}
+}
+
 // TestNotify sends a test notification via external channels.
 // POST /admin/settings/test-notify
 func (h *Notifications) TestNotify(w http.ResponseWriter, r *http.Request) {
@@ -241,14 +324,20 @@ func notificationIcon(nType string) string {
 	switch nType {
 	case models.NotifyReviewSubmitted:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M22 11.08V12a10 10 0 11-5.93-9.14"/><polyline points="22 4 12 14.01 9 11.01"/></svg>`
-	case models.NotifyProgramAssigned:
+	case models.NotifyProgramAssigned, models.NotifyGenerationSucceeded:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M14 2H6a2 2 0 00-2 2v16a2 2 0 002 2h12a2 2 0 002-2V8z"/><polyline points="14 2 14 8 20 8"/></svg>`
 	case models.NotifyTMUpdated:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><polyline points="23 6 13.5 15.5 8.5 10.5 1 18"/><polyline points="17 6 23 6 23 12"/></svg>`
+	case models.NotifyStallDetected:
+		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><line x1="3" y1="12" x2="21" y2="12"/><polyline points="17 8 21 12 17 16"/></svg>`
+	case models.NotifyMissedSession:
+		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><rect x="3" y="4" width="18" height="18" rx="2" ry="2"/><line x1="16" y1="2" x2="16" y2="6"/><line x1="8" y1="2" x2="8" y2="6"/><line x1="3" y1="10" x2="21" y2="10"/><line x1="10" y1="14" x2="14" y2="18"/><line x1="14" y1="14" x2="10" y2="18"/></svg>`
 	case models.NotifyWorkoutLogged:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M6.5 6.5h11M6.5 17.5h11"/><rect x="2" y="4" width="4" height="5" rx="1"/><rect x="18" y="4" width="4" height="5" rx="1"/><rect x="2" y="15" width="4" height="5" rx="1"/><rect x="18" y="15" width="4" height="5" rx="1"/><line x1="12" y1="2" x2="12" y2="22"/></svg>`
 	case models.NotifyNoteAdded:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M21 15a2 2 0 01-2 2H7l-4 4V5a2 2 0 012-2h14a2 2 0 012 2z"/></svg>`
+	case models.NotifyGenerationFailed, models.NotifyInjuryFlagged:
+		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><circle cx="12" cy="12" r="10"/><line x1="12" y1="8" x2="12" y2="12"/><line x1="12" y1="16" x2="12.01" y2="16"/></svg>`
 	default:
 		return `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M18 8A6 6 0 006 8c0 7-3 9-3 9h18s-3-2-3-9"/><path d="M13.73 21a2 2 0 01-3.46 0"/></svg>`
 	}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestNotifications_UnreadCount_ETag(t *testing.T) {
+	db := testDB(t)
+	coach := seedCoach(t, db)
+	h := &Notifications{DB: db, Templates: testTemplateCache(t)}
+
+	poll := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("GET", "/notifications/count", nil, coach)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.UnreadCount(rr, req)
+		return rr
+	}
+
+	models.CreateNotification(db, coach.ID, models.NotifyWorkoutLogged, "Workout ready", "", "/test", sql.NullInt64{})
+
+	first := poll("")
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+	if !contains(first.Body.String(), ">1</span>") {
+		t.Errorf("expected badge with count 1, got %q", first.Body.String())
+	}
+
+	t.Run("unchanged count returns 304", func(t *testing.T) {
+		rr := poll(etag)
+		if rr.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", rr.Code)
+		}
+		if rr.Body.Len() != 0 {
+			t.Errorf("expected empty body, got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("reading a notification changes the ETag", func(t *testing.T) {
+		models.MarkAllAsRead(db, coach.ID)
+		rr := poll(etag)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if rr.Header().Get("ETag") == etag {
+			t.Error("expected ETag to change after reading")
+		}
+		if !contains(rr.Body.String(), "notification-badge--empty") {
+			t.Errorf("expected empty badge, got %q", rr.Body.String())
+		}
+		etag = rr.Header().Get("ETag")
+	})
+
+	t.Run("new notification changes the ETag", func(t *testing.T) {
+		models.CreateNotification(db, coach.ID, models.NotifyWorkoutLogged, "Another", "", "/test", sql.NullInt64{})
+		rr := poll(etag)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rr.Code)
+		}
+	})
+}
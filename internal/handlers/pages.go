@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
@@ -20,17 +19,12 @@ type Pages struct {
 }
 
 // Index renders the home page for an authenticated user.
-// Coaches see the dashboard. Non-coaches with a linked athlete are redirected
-// to their profile. Unlinked non-coaches see an informative message.
+// Coaches see the dashboard with a roster overview. Non-coaches with a
+// linked athlete see their own athlete dashboard. Unlinked non-coaches see
+// an informative message.
 func (p *Pages) Index(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
 
-	// Non-coach with linked athlete → redirect to their athlete profile.
-	if !user.IsCoach && !user.IsAdmin && user.AthleteID.Valid {
-		http.Redirect(w, r, "/athletes/"+strconv.FormatInt(user.AthleteID.Int64, 10), http.StatusSeeOther)
-		return
-	}
-
 	data := map[string]any{}
 
 	// Suggest passkey registration if user has none and hasn't dismissed.
@@ -38,14 +32,36 @@ func (p *Pages) Index(w http.ResponseWriter, r *http.Request) {
 		data["ShowPasskeySuggestion"] = true
 	}
 
+	// Non-coach with linked athlete → their own dashboard.
+	if !user.IsCoach && !user.IsAdmin && user.AthleteID.Valid {
+		dashboard, err := models.BuildAthleteDashboard(p.DB, user.AthleteID.Int64)
+		if err != nil {
+			log.Printf("handlers: dashboard for athlete %d: %v", user.AthleteID.Int64, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		data["Dashboard"] = dashboard
+		data["PRWindowDays"] = models.DashboardPRWindowDays
+	}
+
+	// Non-coach with viewer access → athletes they follow.
+	if !user.IsCoach && !user.IsAdmin {
+		viewed, err := models.ListViewedAthletes(p.DB, user.ID)
+		if err != nil {
+			log.Printf("handlers: list viewed athletes for user %d: %v", user.ID, err)
+		} else {
+			data["ViewedAthletes"] = viewed
+		}
+	}
+
 	if user.IsCoach || user.IsAdmin {
 		// Coach/admin dashboard — show athletes for quick navigation.
 		coachFilter := middleware.CoachAthleteFilter(user)
-		athletes, err := models.ListAthletes(p.DB, coachFilter)
+		athletes, err := models.ListAthletes(p.DB, coachFilter, false)
 		if err != nil {
 			log.Printf("handlers: list athletes for dashboard: %v", err)
 		} else {
-			data["Athletes"] = athletes
+			data["Roster"] = p.roster(athletes)
 		}
 
 		// Load dashboard summary stats.
@@ -75,3 +91,18 @@ func (p *Pages) Index(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
+
+// roster builds a dashboard for each athlete for the coach's roster
+// overview. Athletes whose dashboard fails to load are logged and skipped.
+func (p *Pages) roster(athletes []*models.Athlete) []*models.AthleteDashboard {
+	roster := make([]*models.AthleteDashboard, 0, len(athletes))
+	for _, a := range athletes {
+		d, err := models.BuildAthleteDashboard(p.DB, a.ID)
+		if err != nil {
+			log.Printf("handlers: dashboard for athlete %d: %v", a.ID, err)
+			continue
+		}
+		roster = append(roster, d)
+	}
+	return roster
+}
@@ -3,7 +3,10 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/carpenike/replog/internal/models"
 )
 
 func TestPages_Index_CoachSeesDashboard(t *testing.T) {
@@ -22,10 +25,11 @@ func TestPages_Index_CoachSeesDashboard(t *testing.T) {
 	}
 }
 
-func TestPages_Index_NonCoachLinkedRedirects(t *testing.T) {
+func TestPages_Index_NonCoachLinkedSeesOwnDashboard(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	athlete := seedAthlete(t, db, "Kid", "")
+	seedAthlete(t, db, "Other Kid", "")
 	nonCoach := seedNonCoach(t, db, athlete.ID)
 
 	p := &Pages{DB: db, Templates: tc}
@@ -34,13 +38,42 @@ func TestPages_Index_NonCoachLinkedRedirects(t *testing.T) {
 	rr := httptest.NewRecorder()
 	p.Index(rr, req)
 
-	if rr.Code != http.StatusSeeOther {
-		t.Errorf("expected 303, got %d", rr.Code)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !contains(body, "<h2>Kid</h2>") {
+		t.Error("expected the linked athlete's dashboard")
 	}
-	loc := rr.Header().Get("Location")
-	expected := "/athletes/" + itoa(athlete.ID)
-	if loc != expected {
-		t.Errorf("expected redirect to %s, got %s", expected, loc)
+	if contains(body, "Other Kid") {
+		t.Error("expected no other athletes on a non-coach dashboard")
+	}
+}
+
+func TestPages_Index_CoachSeesRoster(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Rostered", "")
+
+	w, _ := models.CreateWorkout(db, athlete.ID, "2026-01-05", "", 0)
+	models.SetWorkoutPendingReview(db, w.ID)
+
+	p := &Pages{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/", nil, coach)
+	rr := httptest.NewRecorder()
+	p.Index(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !contains(body, "Rostered") {
+		t.Error("expected athlete in roster overview")
+	}
+	if !contains(body, "1 pending") {
+		t.Error("expected pending review count in roster overview")
 	}
 }
 
@@ -60,3 +93,30 @@ func TestPages_Index_UnlinkedNonCoachSeesMessage(t *testing.T) {
 		t.Errorf("expected 200, got %d", rr.Code)
 	}
 }
+
+func TestPages_Index_ViewerSeesFollowedAthletes(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Followed Kid", "")
+	parent := seedUnlinkedNonCoach(t, db)
+	if err := models.GrantAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+		t.Fatalf("grant viewer: %v", err)
+	}
+
+	p := &Pages{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/", nil, parent)
+	rr := httptest.NewRecorder()
+	p.Index(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Followed Kid") {
+		t.Error("expected followed athlete on the dashboard")
+	}
+	if strings.Contains(body, "not linked to an athlete profile") {
+		t.Error("expected no unlinked message for a viewer")
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -195,6 +196,12 @@ func (h *Passkeys) FinishLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.Sessions.Put(r.Context(), "userID", waUser.User.ID)
+	if err := recordSession(h.Sessions, h.DB, r, waUser.User.ID); err != nil {
+		log.Printf("handlers: %v", err)
+		jsonError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	setSessionLifetime(h.Sessions, h.DB, r, waUser.User)
 
 	// Ensure default preferences.
 	if err := models.EnsureUserPreferences(h.DB, waUser.User.ID); err != nil {
@@ -241,7 +248,15 @@ func (h *Passkeys) DeleteCredential(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := models.DeleteWebAuthnCredential(h.DB, credID, userID); err != nil {
+	// Refusing to remove a passwordless account's last passkey re-renders
+	// the list with an explanation rather than failing the htmx swap.
+	var errMsg string
+	if err := models.DeleteWebAuthnCredential(h.DB, credID, userID); errors.Is(err, models.ErrLastPasskey) {
+		errMsg = "This account has no password, so its only passkey can't be removed. Register another passkey first."
+	} else if errors.Is(err, models.ErrNotFound) {
+		h.Templates.NotFound(w, r)
+		return
+	} else if err != nil {
 		log.Printf("handlers: delete webauthn credential %d for user %d: %v", credID, userID, err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -256,14 +271,15 @@ func (h *Passkeys) DeleteCredential(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]any{
+		"Error":     errMsg,
 		"Passkeys":  creds,
 		"UserID":    userID,
 		"CSRFToken": middleware.CSRFTokenFromContext(r.Context()),
 	}
 
-	ts, ok := h.Templates["passkeys_list.html"]
+	ts, ok := h.Templates["_passkeys_list"]
 	if !ok {
-		log.Printf("handlers: passkeys_list.html template not found")
+		log.Printf("handlers: passkeys list template not found")
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestPasskeys_DeleteCredential_KeepsLastPasskeyOfPasswordless(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+
+	user, err := models.CreateUser(db, "passkeyonly", "", "", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	cred, err := models.CreateWebAuthnCredential(db, user.ID, &webauthn.Credential{
+		ID:              []byte("only-cred"),
+		PublicKey:       []byte("only-key"),
+		AttestationType: "none",
+	}, "phone")
+	if err != nil {
+		t.Fatalf("create credential: %v", err)
+	}
+
+	h := &Passkeys{DB: db, Templates: tc}
+	req := requestWithUser("POST", "/users/"+itoa(user.ID)+"/passkeys/"+itoa(cred.ID)+"/delete", nil, user)
+	req.SetPathValue("id", itoa(user.ID))
+	req.SetPathValue("credentialID", itoa(cred.ID))
+	rr := httptest.NewRecorder()
+	h.DeleteCredential(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "alert-error") {
+		t.Error("response should explain why the passkey was kept")
+	}
+	if creds, _ := models.ListWebAuthnCredentialsByUser(db, user.ID); len(creds) != 1 {
+		t.Errorf("credentials = %d, want 1", len(creds))
+	}
+}
+
+func TestPasskeys_DeleteCredential_ListsDeviceAndLastUse(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	var ids []int64
+	for _, name := range []string{"old", "key"} {
+		wc, err := models.CreateWebAuthnCredential(db, coach.ID, &webauthn.Credential{
+			ID:              []byte("list-" + name),
+			PublicKey:       []byte("key-" + name),
+			AttestationType: "none",
+			Transport:       []protocol.AuthenticatorTransport{protocol.USB},
+		}, name)
+		if err != nil {
+			t.Fatalf("create credential: %v", err)
+		}
+		ids = append(ids, wc.ID)
+	}
+
+	h := &Passkeys{DB: db, Templates: tc}
+	req := requestWithUser("POST", "/users/"+itoa(coach.ID)+"/passkeys/"+itoa(ids[0])+"/delete", nil, coach)
+	req.SetPathValue("id", itoa(coach.ID))
+	req.SetPathValue("credentialID", itoa(ids[0]))
+	rr := httptest.NewRecorder()
+	h.DeleteCredential(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "Security key — Never") {
+		t.Errorf("list should show the device hint and last use, got:\n%s", body)
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+
+	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
+)
+
+// PasswordReset handles the emailed "forgot password" flow.
+type PasswordReset struct {
+	DB        *sql.DB
+	Sessions  *scs.SessionManager
+	Templates TemplateCache
+}
+
+// RequestForm renders the form asking for the account's email address.
+// Self-service reset is off until SMTP and the base URL are configured.
+// GET /auth/reset/request
+func (h *PasswordReset) RequestForm(w http.ResponseWriter, r *http.Request) {
+	if !models.IsEmailLinksConfigured(h.DB) {
+		http.NotFound(w, r)
+		return
+	}
+	h.render(w, http.StatusOK, map[string]any{"Mode": "request"})
+}
+
+// Request emails a reset link to the account with the given address. The
+// response is the same whether or not the address matches an account, so
+// the form can't be used to discover who has one.
+// POST /auth/reset/request
+func (h *PasswordReset) Request(w http.ResponseWriter, r *http.Request) {
+	if !models.IsEmailLinksConfigured(h.DB) {
+		http.NotFound(w, r)
+		return
+	}
+
+	email := strings.TrimSpace(r.FormValue("email"))
+	if email == "" {
+		h.render(w, http.StatusUnprocessableEntity, map[string]any{
+			"Mode":  "request",
+			"Error": "Email is required.",
+		})
+		return
+	}
+
+	h.sendResetLink(email)
+
+	h.render(w, http.StatusOK, map[string]any{"Mode": "sent"})
+}
+
+// sendResetLink emails a reset link if email is the verified address of an
+// account that signs in with a password. Failures are only logged.
+func (h *PasswordReset) sendResetLink(email string) {
+	user, err := models.GetUserByEmail(h.DB, email)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("handlers: look up user for password reset: %v", err)
+		}
+		return
+	}
+	// Passwordless accounts (magic link / passkey only) stay passwordless.
+	if !user.HasPassword() {
+		log.Printf("handlers: password reset requested for passwordless user %d", user.ID)
+		return
+	}
+	// Only an address the user has proven they receive mail at can take
+	// over the account.
+	if !models.IsEmailVerified(h.DB, user.ID) {
+		log.Printf("handlers: password reset requested for unverified email of user %d", user.ID)
+		return
+	}
+
+	token, err := models.CreatePasswordResetToken(h.DB, user.ID)
+	if err != nil {
+		log.Printf("handlers: create password reset token for user %d: %v", user.ID, err)
+		return
+	}
+
+	// Links are built from the configured base URL only — never from the
+	// request's Host header, which the requester controls.
+	resetURL := fmt.Sprintf("%s/auth/reset/%s", models.GetBaseURL(h.DB), token)
+
+	// Deliver the reset link to the user's email (fire-and-forget).
+	appName := models.GetAppName(h.DB)
+	htmlBody := notify.RenderPasswordResetEmail(h.DB, resetURL)
+	notify.SendToUser(h.DB, user.ID, appName+" — Password Reset", htmlBody)
+	log.Printf("handlers: password reset link sent to user %d", user.ID)
+}
+
+// ResetForm renders the new-password form for a valid reset link.
+// GET /auth/reset/{token}
+func (h *PasswordReset) ResetForm(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	if _, err := models.ValidatePasswordResetToken(h.DB, token); err != nil {
+		h.invalidLink(w, r, err)
+		return
+	}
+
+	h.render(w, http.StatusOK, map[string]any{
+		"Mode":              "reset",
+		"Token":             token,
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
+	})
+}
+
+// Reset sets a new password using a reset link. The link is consumed, and
+// every session and login link for the account is revoked.
+// POST /auth/reset/{token}
+func (h *PasswordReset) Reset(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	user, err := models.ValidatePasswordResetToken(h.DB, token)
+	if err != nil {
+		h.invalidLink(w, r, err)
+		return
+	}
+
+	newPassword := r.FormValue("new_password")
+	fieldErrors := map[string]string{}
+	if err := models.ValidatePasswordStrength(h.DB, user.Username, newPassword); err != nil {
+		fieldErrors["new_password"] = "Password does not meet the requirements."
+		var pe *models.PasswordPolicyError
+		if errors.As(err, &pe) {
+			fieldErrors["new_password"] = pe.Reason
+		}
+	} else if newPassword != r.FormValue("confirm_password") {
+		fieldErrors["confirm_password"] = "Passwords don't match."
+	}
+	if len(fieldErrors) > 0 {
+		h.render(w, http.StatusUnprocessableEntity, map[string]any{
+			"Mode":              "reset",
+			"Token":             token,
+			"Error":             "Please correct the errors below.",
+			"FieldErrors":       fieldErrors,
+			"PasswordMinLength": models.GetPasswordMinLength(h.DB),
+		})
+		return
+	}
+
+	user, err = models.ResetPassword(h.DB, token, newPassword)
+	if err != nil {
+		h.invalidLink(w, r, err)
+		return
+	}
+
+	if err := models.DeleteLoginTokensByUser(h.DB, user.ID); err != nil {
+		log.Printf("handlers: revoke tokens after password reset for user %d: %v", user.ID, err)
+		// Non-fatal — continue.
+	}
+	if _, err := models.RevokeUserSessions(h.DB, user.ID, ""); err != nil {
+		log.Printf("handlers: revoke sessions after password reset for user %d: %v", user.ID, err)
+		// Non-fatal — continue.
+	}
+	// The reset proves control of the account, so a lockout from earlier
+	// guesses no longer applies.
+	if err := models.ResetFailedLogins(h.DB, user.Username); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	log.Printf("handlers: password reset for user %q (id=%d)", user.Username, user.ID)
+
+	h.Sessions.Put(r.Context(), "flash_success", "Password changed. Sign in with your new password.")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// invalidLink sends the user back to the login page without revealing why
+// the link didn't work.
+func (h *PasswordReset) invalidLink(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, models.ErrNotFound) {
+		log.Printf("handlers: password reset: %v", err)
+	}
+	h.Sessions.Put(r.Context(), "flash_error", "Invalid or expired password reset link")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// render executes the standalone password reset page.
+func (h *PasswordReset) render(w http.ResponseWriter, status int, data map[string]any) {
+	w.WriteHeader(status)
+	if err := h.Templates["password_reset.html"].ExecuteTemplate(w, "password_reset", data); err != nil {
+		log.Printf("handlers: password reset template error: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alexedwards/scs/sqlite3store"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestPasswordReset_RequestIsUniform(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	models.SetSetting(db, "smtp.host", "smtp.example.com")
+	models.SetSetting(db, "app.base_url", "https://replog.example.com")
+
+	user, _ := models.CreateUser(db, "coach", "", "password123", "coach@example.com", true, false, sql.NullInt64{})
+	db.Exec(`UPDATE users SET email_verified_at = CURRENT_TIMESTAMP WHERE id = ?`, user.ID)
+	models.CreateUser(db, "kid", "", "", "kid@example.com", false, false, sql.NullInt64{})
+	models.CreateUser(db, "unverified", "", "password123", "unverified@example.com", true, false, sql.NullInt64{})
+
+	h := &PasswordReset{DB: db, Sessions: sm, Templates: tc}
+	request := func(email string) *httptest.ResponseRecorder {
+		form := url.Values{"email": {email}}
+		req := httptest.NewRequest("POST", "/auth/reset/request", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Request)).ServeHTTP(rr, req)
+		return rr
+	}
+
+	known := request("Coach@Example.com")
+	for _, email := range []string{"nobody@example.com", "kid@example.com", "unverified@example.com"} {
+		rr := request(email)
+		if rr.Code != known.Code || rr.Body.String() != known.Body.String() {
+			t.Errorf("response for %s differs from a known address", email)
+		}
+	}
+	if known.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", known.Code)
+	}
+
+	var tokens int
+	db.QueryRow(`SELECT COUNT(*) FROM password_reset_tokens`).Scan(&tokens)
+	if tokens != 1 {
+		t.Errorf("reset tokens = %d, want 1 (only the verified password account)", tokens)
+	}
+	var owner int64
+	db.QueryRow(`SELECT user_id FROM password_reset_tokens`).Scan(&owner)
+	if owner != user.ID {
+		t.Errorf("token owner = %d, want %d", owner, user.ID)
+	}
+}
+
+func TestPasswordReset_DisabledWithoutBaseURL(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	models.SetSetting(db, "smtp.host", "smtp.example.com")
+	user, _ := models.CreateUser(db, "coach", "", "password123", "coach@example.com", true, false, sql.NullInt64{})
+	db.Exec(`UPDATE users SET email_verified_at = CURRENT_TIMESTAMP WHERE id = ?`, user.ID)
+
+	h := &PasswordReset{DB: db, Sessions: sm, Templates: tc}
+
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.RequestForm)).ServeHTTP(rr, httptest.NewRequest("GET", "/auth/reset/request", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("request form: expected 404, got %d", rr.Code)
+	}
+
+	form := url.Values{"email": {"coach@example.com"}}
+	req := httptest.NewRequest("POST", "/auth/reset/request", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Host = "attacker.example.net"
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Request)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("request: expected 404, got %d", rr.Code)
+	}
+
+	var tokens int
+	db.QueryRow(`SELECT COUNT(*) FROM password_reset_tokens`).Scan(&tokens)
+	if tokens != 0 {
+		t.Errorf("reset tokens = %d, want 0 without a base URL", tokens)
+	}
+}
+
+func TestPasswordReset_Reset(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	sm.Store = sqlite3store.NewWithCleanupInterval(db, 0)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	// An existing signed-in session that the reset should end.
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "userID", coach.ID)
+	})).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	session := rr.Result().Cookies()[0]
+	models.CreateUserSession(db, session.Value, coach.ID, "", "")
+
+	// A user who forgot their password has often locked themselves out.
+	for i := 0; i < models.LoginLockoutThreshold; i++ {
+		models.RecordFailedLogin(db, coach.Username)
+	}
+
+	token, err := models.CreatePasswordResetToken(db, coach.ID)
+	if err != nil {
+		t.Fatalf("create reset token: %v", err)
+	}
+
+	h := &PasswordReset{DB: db, Sessions: sm, Templates: tc}
+	serve := func(handler http.HandlerFunc, method string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/auth/reset/"+token, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("token", token)
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(handler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(h.ResetForm, "GET", nil); rr.Code != http.StatusOK {
+		t.Fatalf("reset form: expected 200, got %d", rr.Code)
+	}
+
+	rr = serve(h.Reset, "POST", url.Values{"new_password": {"new-password-1"}, "confirm_password": {"new-password-2"}})
+	if rr.Code != http.StatusUnprocessableEntity || !strings.Contains(rr.Body.String(), `id="confirm_password-error"`) {
+		t.Errorf("mismatch: expected 422 with confirm error, got %d", rr.Code)
+	}
+
+	rr = serve(h.Reset, "POST", url.Values{"new_password": {"new-password-1"}, "confirm_password": {"new-password-1"}})
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/login" {
+		t.Fatalf("reset: got %d %q, want 303 /login", rr.Code, loc)
+	}
+	if _, err := models.Authenticate(db, "coach", "new-password-1"); err != nil {
+		t.Errorf("new password should work: %v", err)
+	}
+	if locked, _, _ := models.IsAccountLocked(db, coach.Username); locked {
+		t.Error("a completed reset should clear the lockout")
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(session)
+	rr = httptest.NewRecorder()
+	middleware.RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+	if rr.Code == http.StatusOK {
+		t.Error("existing session should be logged out after a reset")
+	}
+
+	// The link only works once.
+	if rr := serve(h.ResetForm, "GET", nil); rr.Header().Get("Location") != "/login" {
+		t.Errorf("used link: expected redirect to /login, got %d", rr.Code)
+	}
+}
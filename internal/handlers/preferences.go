@@ -39,8 +39,14 @@ func (h *Preferences) EditForm(w http.ResponseWriter, r *http.Request) {
 		"DateFormats":     models.ValidDateFormats,
 		"CommonTimezones": commonTimezones,
 		"Passkeys":        passkeys,
+		"TOTPEnabled":     models.IsTOTPEnabled(h.DB, user.ID),
+		"HasPassword":     user.HasPassword(),
+		"Email":           user.Email.String,
+		"EmailVerified":   models.IsEmailVerified(h.DB, user.ID),
+		"EmailLinks":      models.IsEmailLinksConfigured(h.DB),
 		"UserID":          user.ID,
 		"AvatarUser":      user,
+		"Success":         r.URL.Query().Get("success"),
 	}
 	if err := h.Templates.Render(w, r, "preferences_form.html", data); err != nil {
 		log.Printf("handlers: render preferences form: %v", err)
@@ -113,3 +119,18 @@ var commonTimezones = []string{
 	"Pacific/Auckland",
 	"UTC",
 }
+
+// preferredWeightUnit returns the current user's weight unit, falling back to
+// the default when no preferences are loaded.
+func preferredWeightUnit(r *http.Request) string {
+	if prefs := middleware.PrefsFromContext(r.Context()); prefs != nil {
+		return prefs.WeightUnit
+	}
+	return models.DefaultWeightUnit
+}
+
+// weightInputToLbs converts a weight entered in the user's preferred unit to
+// the canonical lbs stored in the database.
+func weightInputToLbs(r *http.Request, v float64) float64 {
+	return models.ConvertWeight(v, preferredWeightUnit(r), "lbs")
+}
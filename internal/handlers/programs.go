@@ -11,6 +11,7 @@ import (
 
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
 )
 
 // Programs holds dependencies for program template handlers.
@@ -92,7 +93,13 @@ func (h *Programs) Create(w http.ResponseWriter, r *http.Request) {
 
 	isLoop := r.FormValue("is_loop") == "1"
 
-	tmpl, err := models.CreateProgramTemplate(h.DB, nil, name, description, numWeeks, numDays, isLoop, "")
+	roundingIncrement, roundingMode := parseProgramRounding(r)
+
+	tmpl, err := models.CreateProgramTemplate(h.DB, nil, name, description, numWeeks, numDays, isLoop, "", roundingIncrement, roundingMode)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Invalid rounding mode", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: create program template: %v", err)
 		http.Error(w, "Failed to create program template", http.StatusInternalServerError)
@@ -102,6 +109,13 @@ func (h *Programs) Create(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/programs/%d", tmpl.ID), http.StatusSeeOther)
 }
 
+// parseProgramRounding reads the rounding increment and mode from a program
+// form. Blank or non-positive increments fall back to the model default.
+func parseProgramRounding(r *http.Request) (float64, string) {
+	increment, _ := strconv.ParseFloat(r.FormValue("rounding_increment"), 64)
+	return increment, r.FormValue("rounding_mode")
+}
+
 // Show renders the program template detail page with its prescribed sets. Coach only.
 func (h *Programs) Show(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
@@ -173,7 +187,7 @@ func (h *Programs) Show(w http.ResponseWriter, r *http.Request) {
 		days = append(days, DaySets{Day: d, Sets: daySets, NextSet: nextSet})
 	}
 
-	exercises, err := models.ListExercises(h.DB, "")
+	exercises, err := models.ListExercises(h.DB, models.ExerciseFilter{})
 	if err != nil {
 		log.Printf("handlers: list exercises for program form: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -269,7 +283,13 @@ func (h *Programs) Update(w http.ResponseWriter, r *http.Request) {
 
 	isLoop := r.FormValue("is_loop") == "1"
 
-	_, err = models.UpdateProgramTemplate(h.DB, id, name, description, numWeeks, numDays, isLoop)
+	roundingIncrement, roundingMode := parseProgramRounding(r)
+
+	_, err = models.UpdateProgramTemplate(h.DB, id, name, description, numWeeks, numDays, isLoop, roundingIncrement, roundingMode)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Invalid rounding mode", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: update program template %d: %v", id, err)
 		http.Error(w, "Failed to update program template", http.StatusInternalServerError)
@@ -279,6 +299,63 @@ func (h *Programs) Update(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/programs/%d", id), http.StatusSeeOther)
 }
 
+// Clone copies a program template, its prescribed sets, and progression
+// rules into a new global template. Coach only.
+func (h *Programs) Clone(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid program ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		tmpl, err := models.GetProgramTemplateByID(h.DB, id)
+		if err != nil {
+			http.Error(w, "Program not found", http.StatusNotFound)
+			return
+		}
+		name, err = models.CloneName(h.DB, tmpl.Name)
+		if err != nil {
+			log.Printf("handlers: clone name for program template %d: %v", id, err)
+			http.Error(w, "Failed to clone program template", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	newID, err := models.CloneProgramTemplate(h.DB, id, name)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Program not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Program name is required", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, models.ErrDuplicateTemplateName) {
+		http.Error(w, "A program with that name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: clone program template %d: %v", id, err)
+		http.Error(w, "Failed to clone program template", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/programs/%d", newID), http.StatusSeeOther)
+}
+
 // Delete processes the delete program template action. Coach only.
 func (h *Programs) Delete(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -293,6 +370,11 @@ func (h *Programs) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	target := models.AuditTarget("program", id, "")
+	if tmpl, err := models.GetProgramTemplateByID(h.DB, id); err == nil {
+		target = models.AuditTarget("program", id, tmpl.Name)
+	}
+
 	err = models.DeleteProgramTemplate(h.DB, id)
 	if errors.Is(err, models.ErrTemplateInUse) {
 		http.Error(w, "Cannot delete: program is assigned to one or more athletes", http.StatusConflict)
@@ -303,6 +385,7 @@ func (h *Programs) Delete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to delete program template", http.StatusInternalServerError)
 		return
 	}
+	recordAudit(h.DB, user.ID, models.AuditProgramDelete, target)
 
 	http.Redirect(w, r, "/programs", http.StatusSeeOther)
 }
@@ -358,6 +441,14 @@ func (h *Programs) AddSet(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var repMax *int
+	if repMaxStr := r.FormValue("rep_max"); repMaxStr != "" {
+		v, err := strconv.Atoi(repMaxStr)
+		if err == nil {
+			repMax = &v
+		}
+	}
+
 	var percentage *float64
 	if pctStr := r.FormValue("percentage"); pctStr != "" {
 		v, err := strconv.ParseFloat(pctStr, 64)
@@ -370,16 +461,37 @@ func (h *Programs) AddSet(w http.ResponseWriter, r *http.Request) {
 	if awStr := r.FormValue("absolute_weight"); awStr != "" {
 		v, err := strconv.ParseFloat(awStr, 64)
 		if err == nil {
+			v = weightInputToLbs(r, v)
 			absoluteWeight = &v
 		}
 	}
 
+	var targetRPE *float64
+	if rpeStr := r.FormValue("target_rpe"); rpeStr != "" {
+		v, err := strconv.ParseFloat(rpeStr, 64)
+		if err == nil && v >= 1 && v <= 10 {
+			targetRPE = &v
+		}
+	}
+
+	var restSeconds *int
+	if restStr := r.FormValue("rest_seconds"); restStr != "" {
+		v, err := strconv.Atoi(restStr)
+		if err == nil && v >= 0 {
+			restSeconds = &v
+		}
+	}
+
 	sortOrder, _ := strconv.Atoi(r.FormValue("sort_order"))
 
 	notes := r.FormValue("notes")
 	repType := r.FormValue("rep_type")
 
-	_, err = models.CreatePrescribedSet(h.DB, templateID, exerciseID, week, day, setNumber, reps, percentage, absoluteWeight, sortOrder, repType, notes)
+	_, err = models.CreatePrescribedSet(h.DB, templateID, exerciseID, week, day, setNumber, reps, repMax, percentage, absoluteWeight, targetRPE, restSeconds, sortOrder, repType, notes)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Rep range maximum must be greater than reps", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: add prescribed set to template %d: %v", templateID, err)
 		http.Error(w, "Failed to add prescribed set", http.StatusInternalServerError)
@@ -472,6 +584,14 @@ func (h *Programs) UpdateSet(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var repMax *int
+	if repMaxStr := r.FormValue("rep_max"); repMaxStr != "" {
+		v, err := strconv.Atoi(repMaxStr)
+		if err == nil {
+			repMax = &v
+		}
+	}
+
 	var percentage *float64
 	if pctStr := r.FormValue("percentage"); pctStr != "" {
 		v, err := strconv.ParseFloat(pctStr, 64)
@@ -484,15 +604,36 @@ func (h *Programs) UpdateSet(w http.ResponseWriter, r *http.Request) {
 	if awStr := r.FormValue("absolute_weight"); awStr != "" {
 		v, err := strconv.ParseFloat(awStr, 64)
 		if err == nil {
+			v = weightInputToLbs(r, v)
 			absoluteWeight = &v
 		}
 	}
 
+	var targetRPE *float64
+	if rpeStr := r.FormValue("target_rpe"); rpeStr != "" {
+		v, err := strconv.ParseFloat(rpeStr, 64)
+		if err == nil && v >= 1 && v <= 10 {
+			targetRPE = &v
+		}
+	}
+
+	var restSeconds *int
+	if restStr := r.FormValue("rest_seconds"); restStr != "" {
+		v, err := strconv.Atoi(restStr)
+		if err == nil && v >= 0 {
+			restSeconds = &v
+		}
+	}
+
 	sortOrder, _ := strconv.Atoi(r.FormValue("sort_order"))
 	notes := r.FormValue("notes")
 	repType := r.FormValue("rep_type")
 
-	_, err = models.UpdatePrescribedSet(h.DB, setID, exerciseID, setNumber, reps, percentage, absoluteWeight, sortOrder, repType, notes)
+	_, err = models.UpdatePrescribedSet(h.DB, setID, exerciseID, setNumber, reps, repMax, percentage, absoluteWeight, targetRPE, restSeconds, sortOrder, repType, notes)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Rep range maximum must be greater than reps", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: update prescribed set %d: %v", setID, err)
 		http.Error(w, "Failed to update prescribed set", http.StatusInternalServerError)
@@ -536,8 +677,9 @@ func (h *Programs) AssignProgram(w http.ResponseWriter, r *http.Request) {
 		role = "primary"
 	}
 	schedule := r.FormValue("schedule")
+	trainingDays := parseTrainingDays(r)
 
-	_, err = models.AssignProgram(h.DB, athleteID, templateID, startDate, notes, goal, role, schedule)
+	ap, err := models.AssignProgram(h.DB, athleteID, templateID, startDate, notes, goal, role, schedule)
 	if errors.Is(err, models.ErrProgramAlreadyActive) {
 		http.Error(w, "Athlete already has an active primary program. Deactivate it first.", http.StatusConflict)
 		return
@@ -552,6 +694,14 @@ func (h *Programs) AssignProgram(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if trainingDays != 0 {
+		if err := models.SetTrainingDays(h.DB, ap.ID, trainingDays); err != nil {
+			log.Printf("handlers: set training days for athlete program %d: %v", ap.ID, err)
+			http.Error(w, "Failed to save training days", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Auto-assign the program's exercises to the athlete so they appear
 	// in the Assigned Exercises list (for TM management, history, etc.).
 	if n, err := models.AssignProgramExercises(h.DB, athleteID, templateID); err != nil {
@@ -560,10 +710,34 @@ func (h *Programs) AssignProgram(w http.ResponseWriter, r *http.Request) {
 		log.Printf("handlers: auto-assigned %d exercises from template %d to athlete %d", n, templateID, athleteID)
 	}
 
+	notifyProgramAssigned(h.DB, middleware.UserFromContext(r.Context()), ap)
+
 	// Redirect to TM setup so the coach can confirm/set training maxes.
 	http.Redirect(w, r, fmt.Sprintf("/athletes/%d/training-maxes/setup", athleteID), http.StatusSeeOther)
 }
 
+// notifyProgramAssigned tells the athlete about a newly assigned program.
+func notifyProgramAssigned(db *sql.DB, assigner *models.User, ap *models.AthleteProgram) {
+	notify.SendToAthlete(db, ap.AthleteID, assigner.ID, notify.Request{
+		Type:    models.NotifyProgramAssigned,
+		Title:   "New program assigned",
+		Message: fmt.Sprintf("%s starts %s.", ap.TemplateName, ap.StartDate),
+		Link:    fmt.Sprintf("/athletes/%d/prescription", ap.AthleteID),
+	})
+}
+
+// parseTrainingDays builds a training-day bitmask from the form's
+// training_day checkboxes (ISO weekdays 1=Mon..7=Sun).
+func parseTrainingDays(r *http.Request) int64 {
+	var days []int
+	for _, v := range r.Form["training_day"] {
+		if d, err := strconv.Atoi(v); err == nil {
+			days = append(days, d)
+		}
+	}
+	return models.TrainingDaysMask(days)
+}
+
 // TMSetupForm renders a form showing all program exercises with current TMs
 // pre-filled, so the coach can confirm or set initial training maxes after
 // assigning a program.
@@ -627,6 +801,13 @@ func (h *Programs) TMSetupSave(w http.ResponseWriter, r *http.Request) {
 		effectiveDate = time.Now().Format("2006-01-02")
 	}
 
+	program, err := h.tmRoundingProgram(r, athleteID)
+	if err != nil {
+		log.Printf("handlers: get active program for athlete %d: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	set := 0
 	exerciseIDs := r.Form["exercise_id"]
 	for _, eidStr := range exerciseIDs {
@@ -640,8 +821,14 @@ func (h *Programs) TMSetupSave(w http.ResponseWriter, r *http.Request) {
 		if err != nil || weight <= 0 {
 			continue // skip exercises with no weight entered
 		}
+		weight = weightInputToLbs(r, weight)
 
-		_, err = models.SetTrainingMax(h.DB, athleteID, exerciseID, weight, effectiveDate, "Initial TM setup")
+		notes := "Initial TM setup"
+		if program != nil {
+			weight, notes = program.RoundTrainingMax(weight, notes)
+		}
+
+		_, err = models.SetTrainingMax(h.DB, athleteID, exerciseID, weight, effectiveDate, notes)
 		if err != nil {
 			log.Printf("handlers: set TM (athlete=%d, exercise=%d): %v", athleteID, exerciseID, err)
 			// Continue with remaining exercises.
@@ -654,6 +841,16 @@ func (h *Programs) TMSetupSave(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/athletes/%d", athleteID), http.StatusSeeOther)
 }
 
+// tmRoundingProgram returns the athlete's active program when the form asks
+// for training maxes to be rounded (round_tm=1), or nil when no rounding
+// should be applied.
+func (h *Programs) tmRoundingProgram(r *http.Request, athleteID int64) (*models.AthleteProgram, error) {
+	if r.FormValue("round_tm") != "1" {
+		return nil, nil
+	}
+	return models.GetActiveProgram(h.DB, athleteID)
+}
+
 // DeactivateProgram deactivates an athlete's current program. Coach only.
 func (h *Programs) DeactivateProgram(w http.ResponseWriter, r *http.Request) {
 	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
@@ -681,6 +878,62 @@ func (h *Programs) DeactivateProgram(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/athletes/%d", athleteID), http.StatusSeeOther)
 }
 
+// ReassignProgram runs a previous assignment's block again: it clones the
+// assignment's template, deactivates the old assignment if it is still
+// active, and assigns the clone starting today with the same role and
+// schedule. Redirects to TM setup so the coach can set the new starting
+// point. Coach only.
+func (h *Programs) ReassignProgram(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	assignmentID, err := strconv.ParseInt(r.PathValue("assignmentID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid assignment ID", http.StatusBadRequest)
+		return
+	}
+
+	prev, err := models.GetAthleteProgramByID(h.DB, assignmentID)
+	if err != nil || prev.AthleteID != athleteID {
+		http.Error(w, "Assignment not found", http.StatusNotFound)
+		return
+	}
+
+	ap, err := models.ReassignProgram(h.DB, prev, time.Now().Format("2006-01-02"))
+	if errors.Is(err, models.ErrDuplicateTemplateName) {
+		http.Error(w, "This program was already re-assigned today.", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, models.ErrProgramAlreadyActive) {
+		http.Error(w, "Athlete already has an active primary program. Deactivate it first.", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, models.ErrScheduleConflict) {
+		http.Error(w, "Schedule conflicts with an existing active program.", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: reassign assignment %d to athlete %d: %v", prev.ID, athleteID, err)
+		http.Error(w, "Failed to re-assign program", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := models.AssignProgramExercises(h.DB, athleteID, ap.TemplateID); err != nil {
+		log.Printf("handlers: auto-assign program exercises to athlete %d: %v", athleteID, err)
+	}
+
+	notifyProgramAssigned(h.DB, middleware.UserFromContext(r.Context()), ap)
+
+	http.Redirect(w, r, fmt.Sprintf("/athletes/%d/training-maxes/setup", athleteID), http.StatusSeeOther)
+}
+
 // Prescription renders today's training prescription for an athlete.
 func (h *Programs) Prescription(w http.ResponseWriter, r *http.Request) {
 	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
@@ -741,9 +994,17 @@ func (h *Programs) AssignProgramForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	history, err := models.ListAthleteProgramHistory(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: list program history for athlete %d: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]any{
 		"Athlete":   athlete,
 		"Programs":  templates,
+		"History":   history,
 		"TodayDate": time.Now().Format("2006-01-02"),
 	}
 	if err := h.Templates.Render(w, r, "assign_program_form.html", data); err != nil {
@@ -878,7 +1139,16 @@ func (h *Programs) AddProgressionRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = models.SetProgressionRule(h.DB, templateID, exerciseID, increment)
+	// Optional AMRAP condition: only suggest the bump when the last AMRAP
+	// set reached the threshold.
+	condition := r.FormValue("condition")
+	threshold, _ := strconv.Atoi(r.FormValue("threshold"))
+
+	_, err = models.SetProgressionRule(h.DB, templateID, exerciseID, increment, condition, threshold)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "AMRAP condition requires a positive rep threshold", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("handlers: set progression rule (template=%d, exercise=%d): %v", templateID, exerciseID, err)
 		http.Error(w, "Failed to save progression rule", http.StatusInternalServerError)
@@ -945,9 +1215,16 @@ func (h *Programs) CycleReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stalls, err := models.DetectStalls(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: detect stalls for athlete %d: %v", athleteID, err)
+		// Non-fatal — continue without stall flags.
+	}
+
 	data := map[string]any{
 		"Athlete": athlete,
 		"Summary": summary,
+		"Stalls":  stalls,
 	}
 	if err := h.Templates.Render(w, r, "cycle_review.html", data); err != nil {
 		log.Printf("handlers: cycle review template: %v", err)
@@ -967,6 +1244,13 @@ func (h *Programs) ApplyTMBumps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	program, err := h.tmRoundingProgram(r, athleteID)
+	if err != nil {
+		log.Printf("handlers: get active program for athlete %d: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	today := time.Now().Format("2006-01-02")
 	applied := 0
 
@@ -989,8 +1273,12 @@ func (h *Programs) ApplyTMBumps(w http.ResponseWriter, r *http.Request) {
 		if err != nil || newTM <= 0 {
 			continue
 		}
+		newTM = weightInputToLbs(r, newTM)
 
 		notes := "Cycle progression bump"
+		if program != nil {
+			newTM, notes = program.RoundTrainingMax(newTM, notes)
+		}
 		_, err = models.SetTrainingMax(h.DB, athleteID, exerciseID, newTM, today, notes)
 		if err != nil {
 			log.Printf("handlers: apply TM bump (athlete=%d, exercise=%d): %v", athleteID, exerciseID, err)
@@ -1004,8 +1292,58 @@ func (h *Programs) ApplyTMBumps(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/athletes/%d", athleteID), http.StatusSeeOther)
 }
 
-// CopyWeek duplicates all prescribed sets from one week to another within the
-// same program template. Coach only.
+// ReorderSets saves the display order of prescribed sets within one
+// week+day. The form posts set_id values in their new order. Coach only.
+func (h *Programs) ReorderSets(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	templateID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid program ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	var setIDs []int64
+	for _, v := range r.Form["set_id"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid set ID", http.StatusBadRequest)
+			return
+		}
+		setIDs = append(setIDs, id)
+	}
+
+	err = models.ReorderPrescribedSets(h.DB, templateID, setIDs)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Sets must all belong to this program and the same day", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: reorder sets for template %d: %v", templateID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Preserve the current week tab on redirect.
+	redirectURL := fmt.Sprintf("/programs/%d", templateID)
+	if week := r.FormValue("week"); week != "" {
+		redirectURL += "?week=" + week
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// CopyWeek duplicates all prescribed sets from one week into one or more
+// other weeks of the same program template. Target weeks come from
+// target_weeks form values (or a single target_week). Coach only.
 func (h *Programs) CopyWeek(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
 	if !user.IsCoach && !user.IsAdmin {
@@ -1030,24 +1368,91 @@ func (h *Programs) CopyWeek(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	targetWeek, err := strconv.Atoi(r.FormValue("target_week"))
-	if err != nil || targetWeek < 1 {
-		http.Error(w, "Invalid target week", http.StatusBadRequest)
+	rawTargets := r.Form["target_weeks"]
+	if len(rawTargets) == 0 && r.FormValue("target_week") != "" {
+		rawTargets = []string{r.FormValue("target_week")}
+	}
+	var targetWeeks []int
+	seen := make(map[int]bool)
+	for _, v := range rawTargets {
+		tw, err := strconv.Atoi(v)
+		if err != nil || tw < 1 {
+			http.Error(w, "Invalid target week", http.StatusBadRequest)
+			return
+		}
+		if tw == sourceWeek {
+			http.Error(w, "Source and target week must be different", http.StatusBadRequest)
+			return
+		}
+		if !seen[tw] {
+			seen[tw] = true
+			targetWeeks = append(targetWeeks, tw)
+		}
+	}
+	if len(targetWeeks) == 0 {
+		http.Error(w, "Select at least one target week", http.StatusBadRequest)
 		return
 	}
 
-	if sourceWeek == targetWeek {
-		http.Error(w, "Source and target week must be different", http.StatusBadRequest)
+	counts, err := models.CopyPrescribedWeek(h.DB, templateID, sourceWeek, targetWeeks)
+	if err != nil {
+		log.Printf("handlers: copy week %d→%v for template %d: %v", sourceWeek, targetWeeks, templateID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	inserted, err := models.CopyWeek(h.DB, templateID, sourceWeek, targetWeek)
+	for _, tw := range targetWeeks {
+		log.Printf("handlers: copied %d sets from week %d to week %d (template %d)", counts[tw], sourceWeek, tw, templateID)
+	}
+	http.Redirect(w, r, fmt.Sprintf("/programs/%d?week=%d", templateID, targetWeeks[0]), http.StatusSeeOther)
+}
+
+// AddDeloadWeek appends a deload week generated from a source week.
+func (h *Programs) AddDeloadWeek(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	templateID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid program ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	sourceWeek, err := strconv.Atoi(r.FormValue("source_week"))
+	if err != nil || sourceWeek < 1 {
+		http.Error(w, "Invalid source week", http.StatusBadRequest)
+		return
+	}
+
+	// Load is entered as a percentage of the source week (e.g. 60).
+	loadPct, err := strconv.ParseFloat(r.FormValue("load_percent"), 64)
+	if err != nil || loadPct <= 0 || loadPct >= 100 {
+		http.Error(w, "Deload load must be between 1 and 99 percent", http.StatusBadRequest)
+		return
+	}
+
+	newWeek, err := models.GenerateDeloadWeek(h.DB, templateID, sourceWeek, loadPct/100)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Program not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "Source week must exist and have prescribed sets", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		log.Printf("handlers: copy week %d→%d for template %d: %v", sourceWeek, targetWeek, templateID, err)
+		log.Printf("handlers: deload week from %d for template %d: %v", sourceWeek, templateID, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("handlers: copied %d sets from week %d to week %d (template %d)", inserted, sourceWeek, targetWeek, templateID)
-	http.Redirect(w, r, fmt.Sprintf("/programs/%d?week=%d", templateID, targetWeek), http.StatusSeeOther)
+	http.Redirect(w, r, fmt.Sprintf("/programs/%d?week=%d", templateID, newWeek), http.StatusSeeOther)
 }
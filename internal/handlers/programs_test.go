@@ -16,8 +16,8 @@ func TestPrograms_List(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "")
-	models.CreateProgramTemplate(db, nil, "GZCL", "", 3, 4, false, "")
+	models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "", 0, "")
+	models.CreateProgramTemplate(db, nil, "GZCL", "", 3, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 	req := requestWithUser("GET", "/programs", nil, coach)
@@ -142,7 +142,7 @@ func TestPrograms_Show(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Show Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Show Test", "", 4, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 	req := requestWithUser("GET", "/programs/"+itoa(tmpl.ID), nil, coach)
@@ -176,7 +176,7 @@ func TestPrograms_EditForm_CoachOnly(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	nonCoach := seedUnlinkedNonCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Edit Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Edit Test", "", 4, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -207,7 +207,7 @@ func TestPrograms_Update_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Old Name", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Old Name", "", 4, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -231,6 +231,54 @@ func TestPrograms_Update_Success(t *testing.T) {
 	}
 }
 
+func TestPrograms_Update_Rounding(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rounded", "", 4, 4, false, "", 0, "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("saves increment and mode", func(t *testing.T) {
+		form := url.Values{
+			"name":               {"Rounded"},
+			"num_weeks":          {"4"},
+			"num_days":           {"4"},
+			"rounding_increment": {"2.5"},
+			"rounding_mode":      {"down"},
+		}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID), form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Update(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		updated, _ := models.GetProgramTemplateByID(db, tmpl.ID)
+		if updated.RoundingIncrement != 2.5 || updated.RoundingMode != models.RoundDown {
+			t.Errorf("rounding = %.2f/%s, want 2.50/down", updated.RoundingIncrement, updated.RoundingMode)
+		}
+	})
+
+	t.Run("rejects unknown mode", func(t *testing.T) {
+		form := url.Values{
+			"name":          {"Rounded"},
+			"num_weeks":     {"4"},
+			"num_days":      {"4"},
+			"rounding_mode": {"sideways"},
+		}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID), form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Update(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
 func TestPrograms_Update_NonCoachForbidden(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -253,7 +301,7 @@ func TestPrograms_Delete_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Delete Me", "", 1, 1, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Delete Me", "", 1, 1, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -274,7 +322,7 @@ func TestPrograms_Delete_InUseConflict(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "In Use", "", 1, 1, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "In Use", "", 1, 1, false, "", 0, "")
 	a := seedAthlete(t, db, "Athlete", "")
 	models.AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
 
@@ -311,7 +359,7 @@ func TestPrograms_AddSet_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "AddSet Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "AddSet Test", "", 4, 4, false, "", 0, "")
 	ex := seedExercise(t, db, "Bench Press", "")
 
 	h := &Programs{DB: db, Templates: tc}
@@ -339,6 +387,125 @@ func TestPrograms_AddSet_Success(t *testing.T) {
 	}
 }
 
+func TestPrograms_AddSet_RestSeconds(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rest Test", "", 4, 4, false, "", 0, "")
+	ex := seedExercise(t, db, "Squat", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	form := url.Values{
+		"exercise_id":  {itoa(ex.ID)},
+		"week":         {"1"},
+		"day":          {"1"},
+		"set_number":   {"1"},
+		"reps":         {"3"},
+		"rest_seconds": {"240"},
+	}
+	req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets", form, coach)
+	req.SetPathValue("id", itoa(tmpl.ID))
+	rr := httptest.NewRecorder()
+	h.AddSet(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	sets, _ := models.ListPrescribedSets(db, tmpl.ID)
+	if len(sets) != 1 {
+		t.Fatalf("sets = %d, want 1", len(sets))
+	}
+	if !sets[0].RestSeconds.Valid || sets[0].RestSeconds.Int64 != 240 {
+		t.Errorf("rest_seconds = %v, want 240", sets[0].RestSeconds)
+	}
+}
+
+func TestPrograms_AbsoluteWeight_KgRoundTrip(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Kg Test", "", 1, 1, false, "", 0, "")
+	ex := seedExercise(t, db, "Goblet Squat", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	form := url.Values{
+		"exercise_id":     {itoa(ex.ID)},
+		"week":            {"1"},
+		"day":             {"1"},
+		"set_number":      {"1"},
+		"reps":            {"10"},
+		"absolute_weight": {"22.5"},
+	}
+	req := withWeightUnit(requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets", form, coach), "kg")
+	req.SetPathValue("id", itoa(tmpl.ID))
+	rr := httptest.NewRecorder()
+	h.AddSet(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	sets, _ := models.ListPrescribedSets(db, tmpl.ID)
+	if len(sets) != 1 || fmt.Sprintf("%.2f", sets[0].AbsoluteWeight.Float64) != "49.60" {
+		t.Fatalf("stored absolute weight = %v, want 49.60 lbs", sets[0].AbsoluteWeight)
+	}
+
+	form.Set("absolute_weight", "24")
+	req = withWeightUnit(requestWithUser("POST", fmt.Sprintf("/programs/%d/sets/%d", tmpl.ID, sets[0].ID), form, coach), "kg")
+	req.SetPathValue("id", itoa(tmpl.ID))
+	req.SetPathValue("setID", itoa(sets[0].ID))
+	rr = httptest.NewRecorder()
+	h.UpdateSet(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("update: expected 303, got %d", rr.Code)
+	}
+
+	req = withWeightUnit(requestWithUser("GET", "/programs/"+itoa(tmpl.ID), nil, coach), "kg")
+	req.SetPathValue("id", itoa(tmpl.ID))
+	rr = httptest.NewRecorder()
+	h.Show(rr, req)
+	if !strings.Contains(rr.Body.String(), "<td>24</td>") {
+		t.Errorf("program detail does not show 24 kg:\n%s", rr.Body.String())
+	}
+}
+
+func TestPrograms_AddSet_RepRange(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "RepRange Test", "", 4, 4, false, "", 0, "")
+	ex := seedExercise(t, db, "Dumbbell Row", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	form := url.Values{
+		"exercise_id": {itoa(ex.ID)},
+		"week":        {"1"},
+		"day":         {"1"},
+		"set_number":  {"1"},
+		"reps":        {"8"},
+		"rep_max":     {"12"},
+	}
+	req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets", form, coach)
+	req.SetPathValue("id", itoa(tmpl.ID))
+	rr := httptest.NewRecorder()
+	h.AddSet(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	sets, _ := models.ListPrescribedSets(db, tmpl.ID)
+	if len(sets) != 1 {
+		t.Fatalf("sets = %d, want 1", len(sets))
+	}
+	if got := sets[0].RepsLabel(); got != "8-12" {
+		t.Errorf("RepsLabel = %q, want 8-12", got)
+	}
+}
+
 func TestPrograms_AddSet_NonCoachForbidden(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -361,7 +528,7 @@ func TestPrograms_AddSet_MissingFields(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Missing Fields", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Missing Fields", "", 4, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -395,11 +562,11 @@ func TestPrograms_DeleteSet_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "DeleteSet Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "DeleteSet Test", "", 4, 4, false, "", 0, "")
 	ex := seedExercise(t, db, "Bench", "")
 
 	reps := 5
-	ps, _ := models.CreatePrescribedSet(db, tmpl.ID, ex.ID, 1, 1, 1, &reps, nil, nil, 0, "", "")
+	ps, _ := models.CreatePrescribedSet(db, tmpl.ID, ex.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -441,16 +608,16 @@ func TestPrograms_AssignProgram_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Assign Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Assign Test", "", 4, 4, false, "", 0, "")
 	a := seedAthlete(t, db, "Athlete", "")
 
 	// Add prescribed sets with two distinct exercises.
-	ex1, _ := models.CreateExercise(db, "AA Squat", "", "", "", 0)
-	ex2, _ := models.CreateExercise(db, "AA Bench", "", "", "", 0)
+	ex1, _ := models.CreateExercise(db, "AA Squat", "", "", "", "", 0)
+	ex2, _ := models.CreateExercise(db, "AA Bench", "", "", "", "", 0)
 	reps5 := 5
 	pct75 := 75.0
-	models.CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 1, &reps5, &pct75, nil, 0, "reps", "")
-	models.CreatePrescribedSet(db, tmpl.ID, ex2.ID, 1, 2, 1, &reps5, &pct75, nil, 0, "reps", "")
+	models.CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 1, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "")
+	models.CreatePrescribedSet(db, tmpl.ID, ex2.ID, 1, 2, 1, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -482,11 +649,73 @@ func TestPrograms_AssignProgram_Success(t *testing.T) {
 	}
 }
 
+func TestPrograms_AssignProgram_NotifiesAthlete(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Notify Program", "", 4, 4, false, "", 0, "")
+	a := seedAthlete(t, db, "Athlete", "")
+	kid := seedNonCoach(t, db, a.ID)
+
+	h := &Programs{DB: db, Templates: tc}
+	form := url.Values{"template_id": {itoa(tmpl.ID)}, "start_date": {"2026-02-01"}}
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/program", form, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.AssignProgram(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	notifications, _ := models.ListNotifications(db, kid.ID, 10, 0)
+	if len(notifications) != 1 || notifications[0].Type != models.NotifyProgramAssigned {
+		t.Fatalf("athlete notifications = %v, want one program assigned", notifications)
+	}
+	if !contains(notifications[0].Message.String, "Notify Program") {
+		t.Errorf("message = %q, want the program name", notifications[0].Message.String)
+	}
+	if n, _ := models.GetUnreadCount(db, coach.ID); n != 0 {
+		t.Errorf("assigning coach got %d notifications, want 0", n)
+	}
+}
+
+func TestPrograms_AssignProgram_TrainingDays(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Days Test", "", 1, 3, false, "", 0, "")
+	a := seedAthlete(t, db, "Athlete", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	form := url.Values{
+		"template_id":  {itoa(tmpl.ID)},
+		"start_date":   {"2026-02-01"},
+		"training_day": {"1", "3", "5"},
+	}
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/program", form, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.AssignProgram(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	ap, _ := models.GetActiveProgram(db, a.ID)
+	if ap == nil {
+		t.Fatal("expected active program")
+	}
+	if got := ap.TrainingDaysLabel(); got != "Mon, Wed, Fri" {
+		t.Errorf("training days = %q, want Mon, Wed, Fri", got)
+	}
+}
+
 func TestPrograms_AssignProgram_AlreadyActive(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Conflict Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Conflict Test", "", 4, 4, false, "", 0, "")
 	a := seedAthlete(t, db, "Athlete", "")
 	models.AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
 
@@ -528,7 +757,7 @@ func TestPrograms_DeactivateProgram_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Deactivate Test", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Deactivate Test", "", 4, 4, false, "", 0, "")
 	a := seedAthlete(t, db, "Athlete", "")
 	models.AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
 
@@ -604,7 +833,7 @@ func TestPrograms_Prescription(t *testing.T) {
 	})
 
 	t.Run("with program", func(t *testing.T) {
-		tmpl, _ := models.CreateProgramTemplate(db, nil, "Rx Test", "", 4, 4, false, "")
+		tmpl, _ := models.CreateProgramTemplate(db, nil, "Rx Test", "", 4, 4, false, "", 0, "")
 		models.AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
 
 		req := requestWithUser("GET", "/athletes/"+itoa(a.ID)+"/prescription", nil, coach)
@@ -640,7 +869,7 @@ func TestPrograms_AssignProgramForm(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 	a := seedAthlete(t, db, "Athlete", "")
-	models.CreateProgramTemplate(db, nil, "Template A", "", 4, 4, false, "")
+	models.CreateProgramTemplate(db, nil, "Template A", "", 4, 4, false, "", 0, "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -692,9 +921,9 @@ func TestPrograms_ProgramCompatibility(t *testing.T) {
 	reps := 5
 	pct1 := 80.0
 	pct2 := 75.0
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Strength", "", 4, 3, false, "")
-	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, &pct1, nil, 0, "reps", "")
-	models.CreatePrescribedSet(db, tmpl.ID, benchPress.ID, 1, 2, 1, &reps, &pct2, nil, 0, "reps", "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Strength", "", 4, 3, false, "", 0, "")
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct1, nil, nil, nil, 0, "reps", "")
+	models.CreatePrescribedSet(db, tmpl.ID, benchPress.ID, 1, 2, 1, &reps, nil, &pct2, nil, nil, nil, 0, "reps", "")
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -742,6 +971,29 @@ func TestPrograms_ProgramCompatibility(t *testing.T) {
 		}
 	})
 
+	t.Run("missing optional equipment is a warning", func(t *testing.T) {
+		belt := seedEquipment(t, db, "Lifting Belt")
+		models.AddExerciseEquipment(db, squat.ID, belt.ID, true)
+
+		req := requestWithUser("GET",
+			fmt.Sprintf("/athletes/%d/program/compatibility?template_id=%d", a.ID, tmpl.ID),
+			nil, coach)
+		req.SetPathValue("id", itoa(a.ID))
+		rr := httptest.NewRecorder()
+		h.ProgramCompatibility(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rr.Code)
+		}
+		body := rr.Body.String()
+		if strings.Contains(body, "Missing Equipment") {
+			t.Errorf("optional equipment should not block readiness")
+		}
+		if !strings.Contains(body, "Lifting Belt") || !strings.Contains(body, "0 blocking, 1 with warnings") {
+			t.Errorf("expected optional warning for Lifting Belt in response body")
+		}
+	})
+
 	t.Run("no template_id returns empty section", func(t *testing.T) {
 		req := requestWithUser("GET",
 			fmt.Sprintf("/athletes/%d/program/compatibility", a.ID),
@@ -765,7 +1017,7 @@ func TestPrograms_AddProgressionRule_Success(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rule Program", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rule Program", "", 4, 4, false, "", 0, "")
 	ex := seedExercise(t, db, "Squats", "")
 
 	h := &Programs{DB: db, Templates: tc}
@@ -815,7 +1067,7 @@ func TestPrograms_AddProgressionRule_InvalidIncrement(t *testing.T) {
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rule Prog", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rule Prog", "", 4, 4, false, "", 0, "")
 	ex := seedExercise(t, db, "Bench", "")
 
 	h := &Programs{DB: db, Templates: tc}
@@ -831,14 +1083,56 @@ func TestPrograms_AddProgressionRule_InvalidIncrement(t *testing.T) {
 	}
 }
 
+func TestPrograms_AddProgressionRule_AMRAPCondition(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Rule Prog", "", 4, 4, false, "", 0, "")
+	ex := seedExercise(t, db, "Bench", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("saves condition", func(t *testing.T) {
+		form := url.Values{"exercise_id": {itoa(ex.ID)}, "increment": {"5"}, "condition": {"amrap_min_reps"}, "threshold": {"8"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/progression", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.AddProgressionRule(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		rule, err := models.GetProgressionRule(db, tmpl.ID, ex.ID)
+		if err != nil {
+			t.Fatalf("get rule: %v", err)
+		}
+		if rule.ConditionLabel() != "AMRAP ≥ 8 reps" {
+			t.Errorf("condition = %q, want AMRAP ≥ 8 reps", rule.ConditionLabel())
+		}
+	})
+
+	t.Run("missing threshold", func(t *testing.T) {
+		form := url.Values{"exercise_id": {itoa(ex.ID)}, "increment": {"5"}, "condition": {"amrap_min_reps"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/progression", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.AddProgressionRule(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
 func TestPrograms_DeleteProgressionRule_Success(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
 	coach := seedCoach(t, db)
 
-	tmpl, _ := models.CreateProgramTemplate(db, nil, "Del Rule Prog", "", 4, 4, false, "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Del Rule Prog", "", 4, 4, false, "", 0, "")
 	ex := seedExercise(t, db, "Deadlift", "")
-	rule, _ := models.SetProgressionRule(db, tmpl.ID, ex.ID, 5.0)
+	rule, _ := models.SetProgressionRule(db, tmpl.ID, ex.ID, 5.0, "", 0)
 
 	h := &Programs{DB: db, Templates: tc}
 
@@ -947,6 +1241,43 @@ func TestPrograms_ApplyTMBumps_Success(t *testing.T) {
 	}
 }
 
+func TestPrograms_ApplyTMBumps_Rounding(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	a := seedAthlete(t, db, "Rounder", "")
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Round TM", "", 1, 1, false, "", 5, models.RoundDown)
+	models.AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
+
+	ex := seedExercise(t, db, "Squat", "")
+	models.SetTrainingMax(db, a.ID, ex.ID, 200, "2026-01-01", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	form := url.Values{
+		"exercise_id":                  {itoa(ex.ID)},
+		"round_tm":                     {"1"},
+		fmt.Sprintf("apply_%d", ex.ID): {"1"},
+		fmt.Sprintf("tm_%d", ex.ID):    {"208.7"},
+	}
+	req := requestWithUser("POST", "/athletes/"+itoa(a.ID)+"/cycle-review", form, coach)
+	req.SetPathValue("id", itoa(a.ID))
+	rr := httptest.NewRecorder()
+	h.ApplyTMBumps(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	tm, _ := models.CurrentTrainingMax(db, a.ID, ex.ID)
+	if tm.Weight != 205 {
+		t.Errorf("TM weight = %v, want 205", tm.Weight)
+	}
+	if want := "Cycle progression bump (rounded from 208.7 to 205)"; tm.Notes.String != want {
+		t.Errorf("TM notes = %q, want %q", tm.Notes.String, want)
+	}
+}
+
 func TestPrograms_ApplyTMBumps_NonCoachForbidden(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -1005,3 +1336,337 @@ func TestPrograms_ApplyTMBumps_SkipsUnchecked(t *testing.T) {
 		t.Errorf("ex2 TM = %v, want 80 (unchanged)", tm2.Weight)
 	}
 }
+
+func TestPrograms_CopyWeek_MultipleTargets(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Block", "", 4, 1, false, "", 0, "")
+	squat := seedExercise(t, db, "Squat", "")
+	reps := 5
+	pct := 70.0
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("copies to each selected week", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}, "target_weeks": {"2", "3", "4"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/copy-week", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.CopyWeek(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		sets, _ := models.ListPrescribedSets(db, tmpl.ID)
+		if len(sets) != 4 {
+			t.Errorf("sets = %d, want 4 (one per week)", len(sets))
+		}
+	})
+
+	t.Run("rejects source in targets", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}, "target_weeks": {"2", "1"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/copy-week", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.CopyWeek(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("no targets selected", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/copy-week", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.CopyWeek(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestPrograms_AddDeloadWeek(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	nonCoach := seedUnlinkedNonCoach(t, db)
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Linear", "", 1, 1, false, "", 0, "")
+	squat := seedExercise(t, db, "Squat", "")
+	reps := 5
+	pct := 80.0
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("non-coach forbidden", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}, "load_percent": {"60"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/deload-week", form, nonCoach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.AddDeloadWeek(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid load", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}, "load_percent": {"120"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/deload-week", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.AddDeloadWeek(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("appends deload week", func(t *testing.T) {
+		form := url.Values{"source_week": {"1"}, "load_percent": {"60"}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/deload-week", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.AddDeloadWeek(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if loc := rr.Header().Get("Location"); loc != "/programs/"+itoa(tmpl.ID)+"?week=2" {
+			t.Errorf("redirect = %q, want week 2", loc)
+		}
+		got, _ := models.GetProgramTemplateByID(db, tmpl.ID)
+		if got.NumWeeks != 2 {
+			t.Errorf("num_weeks = %d, want 2", got.NumWeeks)
+		}
+	})
+}
+
+func TestPrograms_Clone(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	nonCoach := seedUnlinkedNonCoach(t, db)
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Base", "", 2, 2, false, "", 0, "")
+	squat := seedExercise(t, db, "Squat", "")
+	reps := 5
+	pct := 70.0
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("non-coach forbidden", func(t *testing.T) {
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/clone", url.Values{}, nonCoach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Clone(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("default copy name", func(t *testing.T) {
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/clone", url.Values{}, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Clone(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+		templates, _ := models.ListProgramTemplates(db)
+		var found bool
+		for _, pt := range templates {
+			if pt.Name == "Base (Copy)" {
+				found = true
+				if loc := rr.Header().Get("Location"); loc != "/programs/"+itoa(pt.ID) {
+					t.Errorf("redirect = %q, want /programs/%d", loc, pt.ID)
+				}
+			}
+		}
+		if !found {
+			t.Error("expected a template named Base (Copy)")
+		}
+	})
+
+	t.Run("second default copy gets a numbered name", func(t *testing.T) {
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/clone", url.Values{}, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Clone(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+		templates, _ := models.ListProgramTemplates(db)
+		var found bool
+		for _, pt := range templates {
+			if pt.Name == "Base (Copy 2)" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("expected a template named Base (Copy 2)")
+		}
+	})
+
+	t.Run("blank name rejected", func(t *testing.T) {
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/clone", url.Values{"name": {"   "}}, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Clone(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("duplicate name conflict", func(t *testing.T) {
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/clone", url.Values{"name": {"Base"}}, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.Clone(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d", rr.Code)
+		}
+	})
+}
+
+func TestPrograms_ReorderSets(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	nonCoach := seedUnlinkedNonCoach(t, db)
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Order", "", 2, 1, false, "", 0, "")
+	squat := seedExercise(t, db, "Squat", "")
+	bench := seedExercise(t, db, "Bench", "")
+	reps := 5
+	s1, _ := models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 2, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "reps", "")
+	s2, _ := models.CreatePrescribedSet(db, tmpl.ID, bench.ID, 2, 1, 1, &reps, nil, nil, nil, nil, nil, 1, "reps", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("non-coach forbidden", func(t *testing.T) {
+		form := url.Values{"week": {"2"}, "set_id": {itoa(s2.ID), itoa(s1.ID)}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets/reorder", form, nonCoach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.ReorderSets(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("saves order and keeps week", func(t *testing.T) {
+		form := url.Values{"week": {"2"}, "set_id": {itoa(s2.ID), itoa(s1.ID)}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets/reorder", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.ReorderSets(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); loc != "/programs/"+itoa(tmpl.ID)+"?week=2" {
+			t.Errorf("redirect = %q, want week 2", loc)
+		}
+		sets, _ := models.ListPrescribedSetsForDay(db, tmpl.ID, 2, 1)
+		if sets[0].ID != s2.ID {
+			t.Errorf("first set = %d, want %d", sets[0].ID, s2.ID)
+		}
+	})
+
+	t.Run("set from another template", func(t *testing.T) {
+		other, _ := models.CreateProgramTemplate(db, nil, "Other", "", 1, 1, false, "", 0, "")
+		foreign, _ := models.CreatePrescribedSet(db, other.ID, squat.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "reps", "")
+		form := url.Values{"week": {"2"}, "set_id": {itoa(s1.ID), itoa(foreign.ID)}}
+		req := requestWithUser("POST", "/programs/"+itoa(tmpl.ID)+"/sets/reorder", form, coach)
+		req.SetPathValue("id", itoa(tmpl.ID))
+		rr := httptest.NewRecorder()
+		h.ReorderSets(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestPrograms_ReassignProgram(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Runner", "")
+
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Block", "", 1, 1, false, "", 0, "")
+	squat := seedExercise(t, db, "Squat", "")
+	reps := 5
+	pct := 75.0
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+	prev, _ := models.AssignProgram(db, athlete.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
+
+	h := &Programs{DB: db, Templates: tc}
+
+	t.Run("assign form shows history", func(t *testing.T) {
+		req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/program/assign", nil, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		rr := httptest.NewRecorder()
+		h.AssignProgramForm(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), "Program History") || !strings.Contains(rr.Body.String(), "/reassign") {
+			t.Error("expected program history with a re-assign action")
+		}
+	})
+
+	t.Run("clones and assigns fresh", func(t *testing.T) {
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/program/"+itoa(prev.ID)+"/reassign", url.Values{}, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("assignmentID", itoa(prev.ID))
+		rr := httptest.NewRecorder()
+		h.ReassignProgram(rr, req)
+
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		active, _ := models.GetActiveProgram(db, athlete.ID)
+		if active == nil || active.TemplateID == tmpl.ID {
+			t.Fatalf("expected a new active assignment on a cloned template, got %+v", active)
+		}
+		sets, _ := models.ListPrescribedSets(db, active.TemplateID)
+		if len(sets) != 1 {
+			t.Errorf("cloned template sets = %d, want 1", len(sets))
+		}
+		old, _ := models.GetAthleteProgramByID(db, prev.ID)
+		if old.Active {
+			t.Error("expected previous assignment to be deactivated")
+		}
+	})
+
+	t.Run("assignment for another athlete", func(t *testing.T) {
+		other := seedAthlete(t, db, "Other", "")
+		req := requestWithUser("POST", "/athletes/"+itoa(other.ID)+"/program/"+itoa(prev.ID)+"/reassign", url.Values{}, coach)
+		req.SetPathValue("id", itoa(other.ID))
+		req.SetPathValue("assignmentID", itoa(prev.ID))
+		rr := httptest.NewRecorder()
+		h.ReassignProgram(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/carpenike/replog/internal/llm"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// PromptTemplates manages the coach-defined audience rules used in the AI
+// Coach system prompt (admin-only).
+type PromptTemplates struct {
+	DB        *sql.DB
+	Sessions  *scs.SessionManager
+	Templates TemplateCache
+}
+
+// promptTemplateScope is one editable row on the prompt templates page.
+type promptTemplateScope struct {
+	Audience string
+	Tier     string
+	Label    string
+	Help     string
+	Default  string // built-in rules, empty when they vary by tier
+	Template *models.PromptTemplate
+}
+
+// Instructions returns the text to show in the editor: the saved template,
+// or the built-in rules as a starting point.
+func (s promptTemplateScope) Instructions() string {
+	if s.Template != nil {
+		return s.Template.Instructions
+	}
+	return strings.TrimSpace(s.Default)
+}
+
+// promptTemplateScopes lists every audience and tier a template can be saved
+// for, in the order they are resolved for a youth athlete.
+func promptTemplateScopes() []promptTemplateScope {
+	return []promptTemplateScope{
+		{Audience: "youth", Tier: "foundational", Label: "Youth — Foundational", Default: llm.DefaultAudienceRules("foundational")},
+		{Audience: "youth", Tier: "intermediate", Label: "Youth — Intermediate", Default: llm.DefaultAudienceRules("intermediate")},
+		{Audience: "youth", Tier: "sport_performance", Label: "Youth — Sport Performance", Default: llm.DefaultAudienceRules("sport_performance")},
+		{Audience: "youth", Label: "Youth — All Tiers", Help: "Used for any tier without its own template. Leave empty to use the built-in rules for each tier."},
+		{Audience: "adult", Label: "Adult", Help: "Used for athletes without a tier.", Default: llm.DefaultAudienceRules("")},
+	}
+}
+
+// List renders the prompt templates page.
+func (h *PromptTemplates) List(w http.ResponseWriter, r *http.Request) {
+	saved, err := models.ListPromptTemplates(h.DB)
+	if err != nil {
+		log.Printf("handlers: list prompt templates: %v", err)
+		h.Templates.ServerError(w, r)
+		return
+	}
+
+	scopes := promptTemplateScopes()
+	for i := range scopes {
+		for _, t := range saved {
+			if t.Audience == scopes[i].Audience && t.Tier == scopes[i].Tier {
+				scopes[i].Template = t
+			}
+		}
+	}
+
+	data := map[string]any{
+		"Scopes":  scopes,
+		"Success": h.Sessions.PopString(r.Context(), "flash_success"),
+		"Error":   h.Sessions.PopString(r.Context(), "flash_error"),
+	}
+	if err := h.Templates.Render(w, r, "prompt_templates.html", data); err != nil {
+		log.Printf("handlers: render prompt templates: %v", err)
+	}
+}
+
+// Save stores the instructions for one audience and tier. Clearing the
+// instructions, or saving the built-in rules unchanged, removes the template
+// so the built-in rules apply again.
+func (h *PromptTemplates) Save(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	audience := r.FormValue("audience")
+	tier := r.FormValue("tier")
+	instructions := strings.TrimSpace(r.FormValue("instructions"))
+
+	var scope *promptTemplateScope
+	for _, s := range promptTemplateScopes() {
+		if s.Audience == audience && s.Tier == tier {
+			scope = &s
+			break
+		}
+	}
+	if scope == nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if instructions == "" || instructions == strings.TrimSpace(scope.Default) {
+		err := models.DeletePromptTemplate(h.DB, audience, tier)
+		if err != nil && !errors.Is(err, models.ErrNotFound) {
+			log.Printf("handlers: delete prompt template %s/%s: %v", audience, tier, err)
+			h.Templates.ServerError(w, r)
+			return
+		}
+		h.Sessions.Put(r.Context(), "flash_success", scope.Label+" now uses the built-in rules.")
+		http.Redirect(w, r, "/admin/settings/prompts", http.StatusSeeOther)
+		return
+	}
+
+	if _, err := models.SetPromptTemplate(h.DB, audience, tier, instructions); err != nil {
+		log.Printf("handlers: set prompt template %s/%s: %v", audience, tier, err)
+		h.Sessions.Put(r.Context(), "flash_error", "Failed to save "+scope.Label+" template.")
+		http.Redirect(w, r, "/admin/settings/prompts", http.StatusSeeOther)
+		return
+	}
+	h.Sessions.Put(r.Context(), "flash_success", scope.Label+" template saved.")
+	http.Redirect(w, r, "/admin/settings/prompts", http.StatusSeeOther)
+}
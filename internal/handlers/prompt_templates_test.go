@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/carpenike/replog/internal/llm"
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestPromptTemplates_List(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+
+	if _, err := models.SetPromptTemplate(db, "youth", "", "Custom youth guardrails"); err != nil {
+		t.Fatalf("set template: %v", err)
+	}
+
+	h := &PromptTemplates{DB: db, Sessions: sm, Templates: tc}
+	req := requestWithUser("GET", "/admin/settings/prompts", nil, coach)
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.List)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Custom youth guardrails") {
+		t.Error("expected saved youth template in body")
+	}
+	if !strings.Contains(body, "FOUNDATIONAL TIER RULES") {
+		t.Error("expected built-in foundational rules as the starting text")
+	}
+	if !strings.Contains(body, "ADULT ATHLETE PROGRAMMING RULES") {
+		t.Error("expected built-in adult rules as the starting text")
+	}
+}
+
+func TestPromptTemplates_Save(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	coach := seedCoach(t, db)
+	h := &PromptTemplates{DB: db, Sessions: sm, Templates: tc}
+
+	save := func(form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/admin/settings/prompts", form, coach)
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Save)).ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("saves template", func(t *testing.T) {
+		rr := save(url.Values{"audience": {"youth"}, "tier": {"intermediate"}, "instructions": {"Keep loads light."}})
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		got, err := models.ResolvePromptTemplate(db, "intermediate")
+		if err != nil || got.Instructions != "Keep loads light." {
+			t.Errorf("resolve = %+v, %v; want saved instructions", got, err)
+		}
+	})
+
+	t.Run("built-in text unchanged does not create a template", func(t *testing.T) {
+		rr := save(url.Values{"audience": {"adult"}, "tier": {""}, "instructions": {llm.DefaultAudienceRules("")}})
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if _, err := models.ResolvePromptTemplate(db, ""); !errors.Is(err, models.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("clearing reverts to built-in", func(t *testing.T) {
+		rr := save(url.Values{"audience": {"youth"}, "tier": {"intermediate"}, "instructions": {""}})
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if _, err := models.ResolvePromptTemplate(db, "intermediate"); !errors.Is(err, models.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("unknown scope rejected", func(t *testing.T) {
+		rr := save(url.Values{"audience": {"adult"}, "tier": {"foundational"}, "instructions": {"x"}})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html/template"
 	"io/fs"
+	"math"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -80,6 +81,8 @@ var templateFuncs = template.FuncMap{
 			return strings.ToUpper(tier[:1]) + tier[1:]
 		}
 	},
+	"reviewStatusLabel": reviewStatusLabel,
+	"muscleGroupLabel":  muscleGroupLabel,
 	"nextTier": func(tier string) string {
 		switch tier {
 		case "foundational":
@@ -109,6 +112,28 @@ var templateFuncs = template.FuncMap{
 		}
 		return prefs.WeightUnit
 	},
+	// displayWeight converts a stored (lbs) weight to the user's preferred
+	// unit and formats it. Call as {{ displayWeight $.Prefs .Weight }}.
+	"displayWeight": func(prefs *models.UserPreferences, w float64) string {
+		unit := models.DefaultWeightUnit
+		if prefs != nil {
+			unit = prefs.WeightUnit
+		}
+		return models.FormatWeight(w, unit)
+	},
+	// displayPlates converts a stored (lbs) comma-separated plate list to
+	// the user's preferred unit. Call as {{ displayPlates $.Prefs .Plates.String }}.
+	"displayPlates": func(prefs *models.UserPreferences, s string) string {
+		plates, err := models.ParsePlates(s)
+		if err != nil {
+			return s
+		}
+		unit := models.DefaultWeightUnit
+		if prefs != nil {
+			unit = prefs.WeightUnit
+		}
+		return models.FormatPlatesIn(plates, unit)
+	},
 	// formatDate formats a time.Time using the user's preferred date format
 	// and timezone. Call as {{ formatDate .Prefs .SomeTime }}.
 	"formatDate": func(prefs *models.UserPreferences, t time.Time) string {
@@ -130,6 +155,7 @@ var templateFuncs = template.FuncMap{
 	// formatWeight formats a float64 weight value for display (no trailing zeros).
 	// Call as {{ formatWeight 185.0 }}.
 	"formatWeight": func(w float64) string {
+		w = math.Round(w*10) / 10
 		if w == float64(int(w)) {
 			return fmt.Sprintf("%.0f", w)
 		}
@@ -175,26 +201,6 @@ var templateFuncs = template.FuncMap{
 		}
 		return s
 	},
-	// ageFromDOB calculates age in years from a YYYY-MM-DD date-of-birth string.
-	// Call as {{ ageFromDOB "2012-03-15" }}.
-	"ageFromDOB": func(dateStr string) int {
-		if dateStr == "" {
-			return 0
-		}
-		t, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			t, err = time.Parse(time.RFC3339, dateStr)
-		}
-		if err != nil {
-			return 0
-		}
-		now := time.Now()
-		age := now.Year() - t.Year()
-		if now.YearDay() < t.YearDay() {
-			age--
-		}
-		return age
-	},
 	// formatDateStr formats a YYYY-MM-DD date string using the user's preferred
 	// date format. Call as {{ formatDateStr .Prefs "2025-01-15" }}.
 	"formatDateStr": func(prefs *models.UserPreferences, dateStr string) string {
@@ -228,6 +234,10 @@ var templateFuncs = template.FuncMap{
 		}
 		return sets[len(sets)-1]
 	},
+	// formatDuration renders a session length in minutes as "45m" or "1h 5m".
+	"formatDuration": func(minutes int64) string {
+		return models.FormatDuration(int(minutes))
+	},
 	// deref dereferences a *float64, returning 0 if nil. Useful in templates
 	// that receive optional numeric values from model structs.
 	"deref": func(p *float64) float64 {
@@ -329,8 +339,8 @@ var templateFuncs = template.FuncMap{
 type TemplateCache map[string]*template.Template
 
 // NewTemplateCache parses all page templates from the embedded filesystem.
-// Each page is combined with the base layout; the login page is parsed
-// standalone since it has no auth context.
+// Each page is combined with the base layout; the login and password reset
+// pages are parsed standalone since they have no auth context.
 func NewTemplateCache(fsys fs.FS) (TemplateCache, error) {
 	cache := TemplateCache{}
 
@@ -342,8 +352,8 @@ func NewTemplateCache(fsys fs.FS) (TemplateCache, error) {
 	for _, page := range pages {
 		name := filepath.Base(page)
 
-		// Login page is standalone — no base layout needed.
-		if name == "login.html" {
+		// Login and password reset pages are standalone — no base layout needed.
+		if name == "login.html" || name == "password_reset.html" {
 			ts, err := template.New(name).Funcs(templateFuncs).ParseFS(fsys, page)
 			if err != nil {
 				return nil, fmt.Errorf("handlers: parse %s: %w", name, err)
@@ -415,6 +425,13 @@ func (tc TemplateCache) RenderErrorPage(w http.ResponseWriter, r *http.Request,
 		data["User"] = user
 	}
 
+	// Inject the real user behind a "view as" preview for the banner.
+	if _, exists := data["Impersonator"]; !exists {
+		if impersonator := middleware.ImpersonatorFromContext(r.Context()); impersonator != nil {
+			data["Impersonator"] = impersonator
+		}
+	}
+
 	// Inject user preferences for template helpers.
 	if prefs := middleware.PrefsFromContext(r.Context()); prefs != nil {
 		data["Prefs"] = prefs
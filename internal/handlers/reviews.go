@@ -3,12 +3,15 @@ package handlers
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
 )
 
 // Reviews holds dependencies for workout review handlers.
@@ -31,7 +34,7 @@ func (h *Reviews) SubmitReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -63,14 +66,20 @@ func (h *Reviews) SubmitReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := r.FormValue("status")
-	if status != models.ReviewStatusApproved && status != models.ReviewStatusNeedsWork {
+	status, err := models.NormalizeReviewStatus(r.FormValue("status"))
+	if err != nil {
 		http.Error(w, "Invalid review status", http.StatusBadRequest)
 		return
 	}
 
 	notes := r.FormValue("notes")
 
+	// Only a change to flagged_injury notifies, not every edit of a flagged review.
+	wasFlagged := false
+	if existing, err := models.GetWorkoutReviewByWorkoutID(h.DB, workoutID); err == nil {
+		wasFlagged = existing.Status == models.ReviewStatusFlaggedInjury
+	}
+
 	_, err = models.CreateOrUpdateWorkoutReview(h.DB, workoutID, user.ID, status, notes)
 	if err != nil {
 		log.Printf("handlers: submit review for workout %d: %v", workoutID, err)
@@ -78,9 +87,66 @@ func (h *Reviews) SubmitReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.notifyReviewed(user, workout, status, notes)
+	if status == models.ReviewStatusFlaggedInjury && !wasFlagged {
+		h.notifyInjuryFlagged(user, workout, notes)
+	}
+
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
 }
 
+// notifyReviewed tells the athlete's users that a workout was reviewed.
+func (h *Reviews) notifyReviewed(reviewer *models.User, workout *models.Workout, status, notes string) {
+	message := fmt.Sprintf("Your workout on %s was reviewed: %s.", workout.Date, reviewStatusLabel(status))
+	if notes != "" {
+		message += " " + notes
+	}
+	notify.SendToAthlete(h.DB, workout.AthleteID, reviewer.ID, notify.Request{
+		Type:    models.NotifyReviewSubmitted,
+		Title:   "Workout reviewed",
+		Message: message,
+		Link:    fmt.Sprintf("/athletes/%d/workouts/%d", workout.AthleteID, workout.ID),
+	})
+}
+
+// notifyInjuryFlagged tells the athlete's coach and every admin, other than
+// the reviewer, that a workout was flagged for a possible injury.
+func (h *Reviews) notifyInjuryFlagged(reviewer *models.User, workout *models.Workout, notes string) {
+	athlete, err := models.GetAthleteByID(h.DB, workout.AthleteID)
+	if err != nil {
+		log.Printf("handlers: get athlete %d for injury flag: %v", workout.AthleteID, err)
+		return
+	}
+
+	recipients, err := models.ListAdminIDs(h.DB)
+	if err != nil {
+		log.Printf("handlers: list admins for injury flag: %v", err)
+	}
+	if athlete.CoachID.Valid {
+		recipients = append(recipients, athlete.CoachID.Int64)
+	}
+
+	message := fmt.Sprintf("%s's workout on %s was flagged for a possible injury.", athlete.Name, workout.Date)
+	if notes != "" {
+		message += " " + notes
+	}
+	seen := map[int64]bool{reviewer.ID: true}
+	for _, id := range recipients {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		notify.Send(h.DB, notify.Request{
+			UserID:    id,
+			Type:      models.NotifyInjuryFlagged,
+			Title:     "Possible injury flagged",
+			Message:   message,
+			Link:      fmt.Sprintf("/athletes/%d/workouts/%d", athlete.ID, workout.ID),
+			AthleteID: sql.NullInt64{Int64: athlete.ID, Valid: true},
+		})
+	}
+}
+
 // DeleteReview removes a review from a workout. Coach/admin only.
 func (h *Reviews) DeleteReview(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserFromContext(r.Context())
@@ -95,7 +161,7 @@ func (h *Reviews) DeleteReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -164,12 +230,190 @@ func (h *Reviews) PendingReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Result of a bulk approval, carried over the redirect.
+	q := r.URL.Query()
+	var success string
+	if q.Has("approved") {
+		approved, _ := strconv.Atoi(q.Get("approved"))
+		skipped, _ := strconv.Atoi(q.Get("skipped"))
+		noun := "workouts"
+		if approved == 1 {
+			noun = "workout"
+		}
+		success = fmt.Sprintf("Approved %d %s.", approved, noun)
+		if skipped > 0 {
+			success += fmt.Sprintf(" Skipped %d already reviewed or unavailable.", skipped)
+		}
+	}
+
 	data := map[string]any{
 		"Unreviewed": unreviewed,
 		"Stats":      stats,
+		"Success":    success,
+		"Error":      q.Get("error"),
 	}
 	if err := h.Templates.Render(w, r, "pending_reviews.html", data); err != nil {
 		log.Printf("handlers: pending reviews template: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
+
+// BulkApprove approves every selected pending workout at once. Workouts the
+// coach can't access or that already have a review are skipped; the counts
+// are shown on the pending reviews page.
+func (h *Reviews) BulkApprove(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	var workoutIDs []int64
+	workouts := make(map[int64]*models.Workout)
+	skipped := 0
+	for _, v := range r.Form["workout_id"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+			return
+		}
+		workout, err := models.GetWorkoutByID(h.DB, id)
+		if errors.Is(err, models.ErrNotFound) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("handlers: get workout %d for bulk approve: %v", id, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !middleware.CanWriteAthlete(h.DB, user, workout.AthleteID) {
+			skipped++
+			continue
+		}
+		workoutIDs = append(workoutIDs, id)
+		workouts[id] = workout
+	}
+
+	if len(workoutIDs) == 0 && skipped == 0 {
+		http.Redirect(w, r, "/reviews/pending?error="+url.QueryEscape("Select at least one workout to approve"), http.StatusSeeOther)
+		return
+	}
+
+	approved, alreadyReviewed, err := models.ApproveWorkoutsBatch(h.DB, workoutIDs, user.ID)
+	if err != nil {
+		log.Printf("handlers: bulk approve %d workouts: %v", len(workoutIDs), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	skipped += alreadyReviewed
+	for _, id := range approved {
+		h.notifyReviewed(user, workouts[id], models.ReviewStatusApproved, "")
+	}
+
+	q := url.Values{
+		"approved": {strconv.Itoa(len(approved))},
+		"skipped":  {strconv.Itoa(skipped)},
+	}
+	http.Redirect(w, r, "/reviews/pending?"+q.Encode(), http.StatusSeeOther)
+}
+
+// ReviewTemplates renders the coach's canned review feedback management page.
+func (h *Reviews) ReviewTemplates(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	templates, err := models.ListReviewTemplates(h.DB, user.ID)
+	if err != nil {
+		log.Printf("handlers: list review templates for user %d: %v", user.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"ReviewTemplates": templates,
+		"Error":           r.URL.Query().Get("error"),
+	}
+	if err := h.Templates.Render(w, r, "review_templates.html", data); err != nil {
+		log.Printf("handlers: review templates template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// CreateReviewTemplate saves a new canned review for the current coach.
+func (h *Reviews) CreateReviewTemplate(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	_, err := models.CreateReviewTemplate(h.DB, user.ID, r.FormValue("name"), r.FormValue("content"))
+	switch {
+	case errors.Is(err, models.ErrInvalidInput):
+		http.Redirect(w, r, "/reviews/templates?error="+url.QueryEscape("Name and feedback are required"), http.StatusSeeOther)
+		return
+	case errors.Is(err, models.ErrDuplicateReviewTemplate):
+		http.Redirect(w, r, "/reviews/templates?error="+url.QueryEscape("You already have a template with that name"), http.StatusSeeOther)
+		return
+	case err != nil:
+		log.Printf("handlers: create review template for user %d: %v", user.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/reviews/templates", http.StatusSeeOther)
+}
+
+// DeleteReviewTemplate removes one of the current coach's canned reviews.
+func (h *Reviews) DeleteReviewTemplate(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("templateID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := models.DeleteReviewTemplate(h.DB, user.ID, id); errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("handlers: delete review template %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/reviews/templates", http.StatusSeeOther)
+}
+
+// reviewStatusLabel returns the display label for a review status.
+func reviewStatusLabel(status string) string {
+	switch status {
+	case models.ReviewStatusApproved:
+		return "Approved"
+	case models.ReviewStatusNeedsChanges:
+		return "Needs Changes"
+	case models.ReviewStatusFlaggedInjury:
+		return "Injury Flagged"
+	default:
+		return status
+	}
+}
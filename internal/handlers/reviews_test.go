@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -121,8 +122,8 @@ func TestReviews_SubmitReview_UpdateExisting(t *testing.T) {
 	if err != nil {
 		t.Fatalf("get review: %v", err)
 	}
-	if rev.Status != models.ReviewStatusNeedsWork {
-		t.Errorf("status = %q, want %q", rev.Status, models.ReviewStatusNeedsWork)
+	if rev.Status != models.ReviewStatusNeedsChanges {
+		t.Errorf("status = %q, want %q", rev.Status, models.ReviewStatusNeedsChanges)
 	}
 }
 
@@ -238,3 +239,251 @@ func TestReviews_PendingReviews_NonCoachForbidden(t *testing.T) {
 		t.Errorf("expected 403, got %d", rr.Code)
 	}
 }
+
+func TestReviews_ReviewTemplates(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	h := &Reviews{DB: db, Templates: tc}
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/reviews/templates", form, coach)
+		rr := httptest.NewRecorder()
+		h.CreateReviewTemplate(rr, req)
+		return rr
+	}
+
+	rr := post(url.Values{"name": {"Depth"}, "content": {"Great bar speed, watch depth"}})
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/reviews/templates" {
+		t.Fatalf("create: expected 303 to /reviews/templates, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	rr = post(url.Values{"name": {"Depth"}, "content": {"Again"}})
+	if !contains(rr.Header().Get("Location"), "error=") {
+		t.Errorf("duplicate: expected error redirect, got %q", rr.Header().Get("Location"))
+	}
+	rr = post(url.Values{"name": {""}, "content": {"No name"}})
+	if !contains(rr.Header().Get("Location"), "error=") {
+		t.Errorf("blank name: expected error redirect, got %q", rr.Header().Get("Location"))
+	}
+
+	req := requestWithUser("GET", "/reviews/templates", nil, coach)
+	rr = httptest.NewRecorder()
+	h.ReviewTemplates(rr, req)
+	if rr.Code != http.StatusOK || !contains(rr.Body.String(), "Depth: Great bar speed, watch depth") {
+		t.Fatalf("list: expected 200 with template, got %d", rr.Code)
+	}
+
+	templates, _ := models.ListReviewTemplates(db, coach.ID)
+	if len(templates) != 1 {
+		t.Fatalf("got %d templates, want 1", len(templates))
+	}
+	req = requestWithUser("POST", "/reviews/templates/"+itoa(templates[0].ID)+"/delete", nil, coach)
+	req.SetPathValue("templateID", itoa(templates[0].ID))
+	rr = httptest.NewRecorder()
+	h.DeleteReviewTemplate(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Errorf("delete: expected 303, got %d", rr.Code)
+	}
+	if templates, _ := models.ListReviewTemplates(db, coach.ID); len(templates) != 0 {
+		t.Errorf("got %d templates after delete, want 0", len(templates))
+	}
+}
+
+func TestReviews_ReviewTemplates_NonCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+	h := &Reviews{DB: db, Templates: tc}
+
+	req := requestWithUser("GET", "/reviews/templates", nil, kid)
+	rr := httptest.NewRecorder()
+	h.ReviewTemplates(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("list: expected 403, got %d", rr.Code)
+	}
+
+	req = requestWithUser("POST", "/reviews/templates", url.Values{"name": {"x"}, "content": {"y"}}, kid)
+	rr = httptest.NewRecorder()
+	h.CreateReviewTemplate(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("create: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestReviews_BulkApprove(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach, err := models.CreateUser(db, "camp-coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	mine, _ := models.CreateAthlete(db, "Mine", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	theirs := seedAthlete(t, db, "Theirs", "")
+	kid := seedNonCoach(t, db, mine.ID)
+
+	w1, _ := models.CreateWorkout(db, mine.ID, "2026-02-14", "", 0)
+	w2, _ := models.CreateWorkout(db, mine.ID, "2026-02-15", "", 0)
+	w3, _ := models.CreateWorkout(db, theirs.ID, "2026-02-15", "", 0)
+	w4, _ := models.CreateWorkout(db, mine.ID, "2026-02-16", "", 0)
+	models.CreateWorkoutReview(db, w4.ID, coach.ID, models.ReviewStatusNeedsChanges, "Redo")
+
+	h := &Reviews{DB: db, Templates: tc}
+	form := url.Values{"workout_id": {itoa(w1.ID), itoa(w2.ID), itoa(w3.ID), itoa(w4.ID)}}
+	req := requestWithUser("POST", "/reviews/bulk-approve", form, coach)
+	rr := httptest.NewRecorder()
+	h.BulkApprove(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	if loc := rr.Header().Get("Location"); loc != "/reviews/pending?approved=2&skipped=2" {
+		t.Errorf("Location = %q, want approved=2&skipped=2", loc)
+	}
+	for _, id := range []int64{w1.ID, w2.ID} {
+		if rev, err := models.GetWorkoutReviewByWorkoutID(db, id); err != nil || rev.Status != models.ReviewStatusApproved {
+			t.Errorf("workout %d: expected approved review, got %v, %v", id, rev, err)
+		}
+	}
+	if _, err := models.GetWorkoutReviewByWorkoutID(db, w3.ID); err == nil {
+		t.Error("expected another coach's athlete's workout to stay unreviewed")
+	}
+
+	// The athlete hears about each approval, as with a single review.
+	notifications, _ := models.ListNotifications(db, kid.ID, 10, 0)
+	if len(notifications) != 2 {
+		t.Fatalf("athlete notifications = %d, want 2", len(notifications))
+	}
+	for _, n := range notifications {
+		if n.Type != models.NotifyReviewSubmitted || !contains(n.Message.String, "was reviewed: Approved") {
+			t.Errorf("notification = %s %q", n.Type, n.Message.String)
+		}
+	}
+
+	// The pending page reports the counts.
+	req = requestWithUser("GET", "/reviews/pending?approved=2&skipped=2", nil, coach)
+	rr = httptest.NewRecorder()
+	h.PendingReviews(rr, req)
+	if !contains(rr.Body.String(), "Approved 2 workouts. Skipped 2") {
+		t.Errorf("expected bulk approve summary, got %q", rr.Body.String())
+	}
+}
+
+func TestReviews_BulkApprove_Validation(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+	h := &Reviews{DB: db, Templates: tc}
+
+	req := requestWithUser("POST", "/reviews/bulk-approve", url.Values{}, coach)
+	rr := httptest.NewRecorder()
+	h.BulkApprove(rr, req)
+	if !contains(rr.Header().Get("Location"), "error=") {
+		t.Errorf("empty selection: expected error redirect, got %q", rr.Header().Get("Location"))
+	}
+
+	req = requestWithUser("POST", "/reviews/bulk-approve", url.Values{"workout_id": {"abc"}}, coach)
+	rr = httptest.NewRecorder()
+	h.BulkApprove(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("bad ID: expected 400, got %d", rr.Code)
+	}
+
+	req = requestWithUser("POST", "/reviews/bulk-approve", url.Values{"workout_id": {"1"}}, kid)
+	rr = httptest.NewRecorder()
+	h.BulkApprove(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("non-coach: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestReviews_SubmitReview_FlaggedInjuryNotifies(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	admin := seedCoach(t, db)
+	coach, err := models.CreateUser(db, "team-coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	athlete, _ := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-15", "", 0)
+
+	h := &Reviews{DB: db, Templates: tc}
+	submit := func(status string) {
+		t.Helper()
+		body := url.Values{"status": {status}, "notes": {"Knee pain on the last set"}}
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/review", body, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		h.SubmitReview(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+	}
+
+	submit(models.ReviewStatusFlaggedInjury)
+	rev, err := models.GetWorkoutReviewByWorkoutID(db, workout.ID)
+	if err != nil || rev.Status != models.ReviewStatusFlaggedInjury {
+		t.Fatalf("expected flagged review, got %v, %v", rev, err)
+	}
+
+	notifications, _ := models.ListNotifications(db, admin.ID, 10, 0)
+	if len(notifications) != 1 || notifications[0].Type != models.NotifyInjuryFlagged {
+		t.Fatalf("admin notifications = %v, want one injury flag", notifications)
+	}
+	if !contains(notifications[0].Message.String, "Knee pain") {
+		t.Errorf("message = %q, want the review notes", notifications[0].Message.String)
+	}
+	if n, _ := models.GetUnreadCount(db, coach.ID); n != 0 {
+		t.Errorf("reviewing coach got %d notifications, want 0", n)
+	}
+
+	// Re-saving a flagged review doesn't notify again.
+	submit(models.ReviewStatusFlaggedInjury)
+	if n, _ := models.GetUnreadCount(db, admin.ID); n != 1 {
+		t.Errorf("admin unread = %d after re-save, want 1", n)
+	}
+}
+
+func TestReviews_SubmitReview_NotifiesAthlete(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-15", "", 0)
+
+	h := &Reviews{DB: db, Templates: tc}
+	submit := func() {
+		t.Helper()
+		body := url.Values{"status": {models.ReviewStatusNeedsChanges}, "notes": {"Brace harder"}}
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/review", body, coach)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		h.SubmitReview(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+	}
+
+	submit()
+	notifications, _ := models.ListNotifications(db, kid.ID, 10, 0)
+	if len(notifications) != 1 || notifications[0].Type != models.NotifyReviewSubmitted {
+		t.Fatalf("athlete notifications = %v, want one review submitted", notifications)
+	}
+	if !contains(notifications[0].Message.String, "Needs Changes") || !contains(notifications[0].Message.String, "Brace harder") {
+		t.Errorf("message = %q, want status and notes", notifications[0].Message.String)
+	}
+
+	// Turning the event type off stops further notifications.
+	models.SetNotificationPreference(db, kid.ID, models.NotifyReviewSubmitted, false, false)
+	submit()
+	if n, _ := models.GetUnreadCount(db, kid.ID); n != 1 {
+		t.Errorf("athlete unread = %d after opting out, want 1", n)
+	}
+}
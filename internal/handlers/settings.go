@@ -6,9 +6,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/carpenike/replog/internal/llm"
+	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 )
 
@@ -20,12 +22,7 @@ type Settings struct {
 
 // Show renders the settings page grouped by category.
 func (h *Settings) Show(w http.ResponseWriter, r *http.Request) {
-	groups := models.ListSettingsByCategoryOrdered(h.DB)
-
-	data := map[string]any{
-		"SettingGroups": groups,
-		"Registry":      models.SettingsRegistry,
-	}
+	data := h.pageData()
 	if err := h.Templates.Render(w, r, "settings.html", data); err != nil {
 		log.Printf("handlers: render settings: %v", err)
 	}
@@ -38,7 +35,7 @@ func (h *Settings) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var updated int
+	var updated []string // keys of changed settings, for the audit log
 	var errors []string
 
 	for _, def := range models.SettingsRegistry {
@@ -51,13 +48,17 @@ func (h *Settings) Update(w http.ResponseWriter, r *http.Request) {
 		newValue := r.FormValue("setting_" + def.Key)
 		oldValue := sv.Value
 
-		// If value cleared, delete the row (revert to default).
+		// If value cleared, delete the row (revert to default). A blank
+		// field over a default has nothing stored, so it isn't a change.
 		if newValue == "" && oldValue != "" {
+			if sv.Source != "db" {
+				continue
+			}
 			if err := models.DeleteSetting(h.DB, def.Key); err != nil {
 				log.Printf("handlers: delete setting %q: %v", def.Key, err)
 				errors = append(errors, "Failed to clear "+def.Label)
 			} else {
-				updated++
+				updated = append(updated, def.Key)
 			}
 			continue
 		}
@@ -69,6 +70,10 @@ func (h *Settings) Update(w http.ResponseWriter, r *http.Request) {
 
 		// Save if changed.
 		if newValue != oldValue && newValue != "" {
+			if err := models.ValidateSetting(def.Key, newValue); err != nil {
+				errors = append(errors, def.Label+" "+err.Error())
+				continue
+			}
 			if err := models.SetSetting(h.DB, def.Key, newValue); err != nil {
 				log.Printf("handlers: set setting %q: %v", def.Key, err)
 				if def.Sensitive {
@@ -77,20 +82,21 @@ func (h *Settings) Update(w http.ResponseWriter, r *http.Request) {
 					errors = append(errors, "Failed to save "+def.Label)
 				}
 			} else {
-				updated++
+				updated = append(updated, def.Key)
 			}
 		}
 	}
 
-	groups := models.ListSettingsByCategoryOrdered(h.DB)
-	data := map[string]any{
-		"SettingGroups": groups,
-		"Registry":      models.SettingsRegistry,
+	if len(updated) > 0 {
+		// Only the keys are recorded; values may be secrets.
+		recordAudit(h.DB, middleware.UserFromContext(r.Context()).ID, models.AuditSettingsUpdate, strings.Join(updated, ", "))
 	}
+
+	data := h.pageData()
 	if len(errors) > 0 {
 		data["Error"] = errors[0]
 		w.WriteHeader(http.StatusUnprocessableEntity)
-	} else if updated > 0 {
+	} else if len(updated) > 0 {
 		// Refresh cached app name in case it changed.
 		RefreshAppName(models.GetAppName(h.DB))
 		data["Success"] = "Settings saved."
@@ -103,14 +109,27 @@ func (h *Settings) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// renderError renders the settings page with an error message.
-func (h *Settings) renderError(w http.ResponseWriter, r *http.Request, msg string) {
-	groups := models.ListSettingsByCategoryOrdered(h.DB)
+// pageData builds the template data shared by every render of the settings
+// page, including this month's AI Coach usage.
+func (h *Settings) pageData() map[string]any {
 	data := map[string]any{
-		"SettingGroups": groups,
+		"SettingGroups": models.ListSettingsByCategoryOrdered(h.DB),
 		"Registry":      models.SettingsRegistry,
-		"Error":         msg,
+		"TokenCap":      models.GetMonthlyTokenCap(h.DB),
+	}
+	usage, err := models.MonthlyTokenUsage(h.DB, time.Now())
+	if err != nil {
+		log.Printf("handlers: %v", err)
+	} else {
+		data["AIUsage"] = usage
 	}
+	return data
+}
+
+// renderError renders the settings page with an error message.
+func (h *Settings) renderError(w http.ResponseWriter, r *http.Request, msg string) {
+	data := h.pageData()
+	data["Error"] = msg
 	w.WriteHeader(http.StatusUnprocessableEntity)
 	if err := h.Templates.Render(w, r, "settings.html", data); err != nil {
 		log.Printf("handlers: render settings error: %v", err)
@@ -33,6 +33,29 @@ func TestSettingsShow(t *testing.T) {
 	}
 }
 
+func TestSettingsShow_AIUsage(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	if err := models.RecordGenerationUsage(db, 0, coach.ID, "gpt-4o", 4200, 0.021); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+
+	h := &Settings{DB: db, Templates: tc}
+
+	r := requestWithUser("GET", "/admin/settings", nil, coach)
+	w := httptest.NewRecorder()
+	h.Show(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "1 generations, 4200 tokens, $0.02") {
+		t.Errorf("expected monthly usage in body, got: %s", body)
+	}
+}
+
 func TestSettingsUpdate(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -66,6 +89,38 @@ func TestSettingsUpdate(t *testing.T) {
 	}
 }
 
+func TestSettingsUpdate_RejectsInvalidValue(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &Settings{DB: db, Templates: tc}
+
+	form := url.Values{
+		"setting_security.session_lifetime_days": {"0"},
+		"setting_security.cookie_samesite":       {"none"},
+		"setting_app.name":                       {"Gym"},
+	}
+
+	r := requestWithUser("POST", "/admin/settings", form, coach)
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+	if got := models.GetSetting(db, "security.session_lifetime_days"); got != "30" {
+		t.Errorf("session lifetime = %q, want default 30", got)
+	}
+	if got := models.GetSetting(db, "security.cookie_samesite"); got != "lax" {
+		t.Errorf("samesite = %q, want default lax", got)
+	}
+	// Valid settings in the same submission are still saved.
+	if got := models.GetSetting(db, "app.name"); got != "Gym" {
+		t.Errorf("app name = %q, want Gym", got)
+	}
+}
+
 func TestSettingsUpdateClear(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
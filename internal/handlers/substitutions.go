@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// Substitutions holds dependencies for per-athlete exercise substitution
+// handlers.
+type Substitutions struct {
+	DB        *sql.DB
+	Templates TemplateCache
+}
+
+// Create sets a standing substitution for an athlete. Coach/admin only.
+func (h *Substitutions) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	athleteID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	fromID, err := strconv.ParseInt(r.FormValue("from_exercise_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise to replace", http.StatusBadRequest)
+		return
+	}
+	toID, err := strconv.ParseInt(r.FormValue("to_exercise_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid substitute exercise", http.StatusBadRequest)
+		return
+	}
+
+	_, err = models.SetSubstitution(h.DB, athleteID, fromID, toID)
+	if errors.Is(err, models.ErrInvalidInput) {
+		http.Error(w, "An exercise cannot substitute for itself", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: set substitution %d→%d for athlete %d: %v", fromID, toID, athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10), http.StatusSeeOther)
+}
+
+// Delete removes a standing substitution. Coach/admin only.
+func (h *Substitutions) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !user.IsCoach && !user.IsAdmin {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	athleteID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
+		return
+	}
+
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	subID, err := strconv.ParseInt(r.PathValue("subID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid substitution ID", http.StatusBadRequest)
+		return
+	}
+
+	err = models.DeleteSubstitution(h.DB, athleteID, subID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Substitution not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: delete substitution %d: %v", subID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10), http.StatusSeeOther)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestSubstitutions_CreateAndDelete(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+	squat := seedExercise(t, db, "Back Squat", "")
+	goblet := seedExercise(t, db, "Goblet Squat", "")
+
+	h := &Substitutions{DB: db, Templates: tc}
+
+	form := url.Values{"from_exercise_id": {itoa(squat.ID)}, "to_exercise_id": {itoa(goblet.ID)}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/substitutions", form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	subs, err := models.ListSubstitutions(db, athlete.ID)
+	if err != nil {
+		t.Fatalf("list substitutions: %v", err)
+	}
+	if len(subs) != 1 {
+		t.Fatalf("expected 1 substitution, got %d", len(subs))
+	}
+
+	req = requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/substitutions/"+itoa(subs[0].ID)+"/delete", nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("subID", itoa(subs[0].ID))
+	rr = httptest.NewRecorder()
+	h.Delete(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("delete: expected 303, got %d", rr.Code)
+	}
+	if subs, _ := models.ListSubstitutions(db, athlete.ID); len(subs) != 0 {
+		t.Errorf("expected no substitutions after delete, got %d", len(subs))
+	}
+}
+
+func TestSubstitutions_Create_SameExercise(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+	squat := seedExercise(t, db, "Back Squat", "")
+
+	h := &Substitutions{DB: db, Templates: tc}
+
+	form := url.Values{"from_exercise_id": {itoa(squat.ID)}, "to_exercise_id": {itoa(squat.ID)}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/substitutions", form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestSubstitutions_Create_NonCoachForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	nonCoach := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Back Squat", "")
+	goblet := seedExercise(t, db, "Goblet Squat", "")
+
+	h := &Substitutions{DB: db, Templates: tc}
+
+	form := url.Values{"from_exercise_id": {itoa(squat.ID)}, "to_exercise_id": {itoa(goblet.ID)}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/substitutions", form, nonCoach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// TOTP handles self-service two-factor (authenticator app) enrollment.
+type TOTP struct {
+	DB        *sql.DB
+	Sessions  *scs.SessionManager
+	Templates TemplateCache
+}
+
+// Manage renders the two-factor page for a user who has it enabled, or
+// sends one who doesn't to enrollment.
+// GET /preferences/totp
+func (h *TOTP) Manage(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if !models.IsTOTPEnabled(h.DB, user.ID) {
+		http.Redirect(w, r, "/preferences/totp/begin", http.StatusSeeOther)
+		return
+	}
+	h.renderManage(w, r, user, "", http.StatusOK)
+}
+
+// BeginEnrollment shows the pending secret started in this session for the
+// user to add to their authenticator app, or a button to start enrollment if
+// there is none. It never creates a secret, so viewing it changes nothing.
+// GET /preferences/totp/begin
+func (h *TOTP) BeginEnrollment(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if models.IsTOTPEnabled(h.DB, user.ID) {
+		http.Redirect(w, r, "/preferences/totp", http.StatusSeeOther)
+		return
+	}
+	secret, err := h.pendingSecret(r, user)
+	if err != nil {
+		log.Printf("handlers: get pending TOTP for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.renderEnroll(w, r, user, secret, "", http.StatusOK)
+}
+
+// StartEnrollment generates a secret for the user and shows it. The pending
+// secret started in this session is reused until enrollment is confirmed or
+// cancelled, so resubmitting doesn't invalidate a secret the user has
+// already scanned.
+// POST /preferences/totp/begin
+func (h *TOTP) StartEnrollment(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	secret, err := h.pendingSecret(r, user)
+	if err != nil {
+		log.Printf("handlers: get pending TOTP for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if secret == "" {
+		_, err := models.BeginTOTPEnrollment(h.DB, user.ID)
+		if errors.Is(err, models.ErrTOTPAlreadyEnabled) {
+			http.Redirect(w, r, "/preferences/totp", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Printf("handlers: begin TOTP enrollment for user %d: %v", user.ID, err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		h.Sessions.Put(r.Context(), "totp_enrolling", true)
+	}
+	http.Redirect(w, r, "/preferences/totp/begin", http.StatusSeeOther)
+}
+
+// pendingSecret returns the secret of the enrollment started in this
+// session, or "" if there is none.
+func (h *TOTP) pendingSecret(r *http.Request, user *models.User) (string, error) {
+	if !h.Sessions.GetBool(r.Context(), "totp_enrolling") {
+		return "", nil
+	}
+	pending, err := models.GetUserTOTP(h.DB, user.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if pending.Enabled {
+		return "", nil
+	}
+	return pending.Secret, nil
+}
+
+// CancelEnrollment discards the pending secret so the next enrollment starts
+// with a fresh one.
+// POST /preferences/totp/cancel
+func (h *TOTP) CancelEnrollment(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if err := models.CancelTOTPEnrollment(h.DB, user.ID); err != nil {
+		log.Printf("handlers: cancel TOTP enrollment for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.Sessions.Remove(r.Context(), "totp_enrolling")
+	http.Redirect(w, r, "/preferences", http.StatusSeeOther)
+}
+
+// FinishEnrollment confirms the pending secret with a code from the user's
+// authenticator and shows their recovery codes.
+// POST /preferences/totp/finish
+func (h *TOTP) FinishEnrollment(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	codes, err := models.ConfirmTOTPEnrollment(h.DB, user.ID, r.FormValue("code"))
+	if errors.Is(err, models.ErrTOTPAlreadyEnabled) {
+		http.Redirect(w, r, "/preferences/totp", http.StatusSeeOther)
+		return
+	}
+	if errors.Is(err, models.ErrInvalidTOTPCode) {
+		pending, err := models.GetUserTOTP(h.DB, user.ID)
+		if err != nil {
+			// Nothing pending — start over with a fresh secret.
+			http.Redirect(w, r, "/preferences/totp/begin", http.StatusSeeOther)
+			return
+		}
+		h.renderEnroll(w, r, user, pending.Secret, "That code didn't match. Check your device's clock and try again.", http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: confirm TOTP enrollment for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.Sessions.Remove(r.Context(), "totp_setup_required")
+	h.Sessions.Remove(r.Context(), "totp_enrolling")
+	h.renderRecoveryCodes(w, r, codes)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a
+// current authenticator or recovery code.
+// POST /preferences/totp/recovery-codes
+func (h *TOTP) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if !h.verify(w, r, user) {
+		return
+	}
+
+	codes, err := models.RegenerateRecoveryCodes(h.DB, user.ID)
+	if err != nil {
+		log.Printf("handlers: regenerate recovery codes for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.renderRecoveryCodes(w, r, codes)
+}
+
+// Disable turns off two-factor after checking a current authenticator or
+// recovery code. Users whose role requires two-factor cannot disable it.
+// POST /preferences/totp/disable
+func (h *TOTP) Disable(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if models.IsTOTPRequired(h.DB, user) {
+		h.renderManage(w, r, user, "Two-factor authentication is required for your account.", http.StatusForbidden)
+		return
+	}
+	if !h.verify(w, r, user) {
+		return
+	}
+
+	if err := models.DisableTOTP(h.DB, user.ID); err != nil {
+		log.Printf("handlers: disable TOTP for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/preferences", http.StatusSeeOther)
+}
+
+// verify checks the submitted code and re-renders the manage page with an
+// error if it doesn't match. Returns true if the code was accepted.
+func (h *TOTP) verify(w http.ResponseWriter, r *http.Request, user *models.User) bool {
+	err := models.VerifyTOTP(h.DB, user.ID, r.FormValue("code"))
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, models.ErrInvalidTOTPCode) {
+		log.Printf("handlers: verify TOTP for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return false
+	}
+	h.renderManage(w, r, user, "Invalid authentication code.", http.StatusUnprocessableEntity)
+	return false
+}
+
+// renderEnroll shows the secret to add to an authenticator app, or the
+// button that starts enrollment if secret is empty.
+func (h *TOTP) renderEnroll(w http.ResponseWriter, r *http.Request, user *models.User, secret, errMsg string, status int) {
+	data := map[string]any{
+		"Secret":   secret,
+		"Required": h.Sessions.GetBool(r.Context(), "totp_setup_required"),
+		"Error":    errMsg,
+	}
+	if secret != "" {
+		data["ProvisioningURI"] = models.TOTPProvisioningURI(secret, models.GetAppName(h.DB), user.Username)
+	}
+	w.WriteHeader(status)
+	if err := h.Templates.Render(w, r, "preferences_totp.html", data); err != nil {
+		log.Printf("handlers: render TOTP enrollment: %v", err)
+	}
+}
+
+// renderManage shows the enabled state with recovery code and disable forms.
+func (h *TOTP) renderManage(w http.ResponseWriter, r *http.Request, user *models.User, errMsg string, status int) {
+	remaining, err := models.CountRecoveryCodes(h.DB, user.ID)
+	if err != nil {
+		log.Printf("handlers: count recovery codes for user %d: %v", user.ID, err)
+		// Non-fatal — render without the count.
+	}
+
+	data := map[string]any{
+		"Enabled":           true,
+		"RecoveryCodesLeft": remaining,
+		"Required":          models.IsTOTPRequired(h.DB, user),
+		"Error":             errMsg,
+	}
+	w.WriteHeader(status)
+	if err := h.Templates.Render(w, r, "preferences_totp.html", data); err != nil {
+		log.Printf("handlers: render TOTP settings: %v", err)
+	}
+}
+
+// renderRecoveryCodes shows newly generated recovery codes. They are never
+// shown again.
+func (h *TOTP) renderRecoveryCodes(w http.ResponseWriter, r *http.Request, codes []string) {
+	data := map[string]any{
+		"RecoveryCodes": codes,
+	}
+	if err := h.Templates.Render(w, r, "preferences_totp.html", data); err != nil {
+		log.Printf("handlers: render recovery codes: %v", err)
+	}
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// enrollTOTP turns on two-factor for a user and returns the secret.
+func enrollTOTP(t *testing.T, db *sql.DB, userID int64) string {
+	t.Helper()
+	secret, err := models.BeginTOTPEnrollment(db, userID)
+	if err != nil {
+		t.Fatalf("begin TOTP enrollment: %v", err)
+	}
+	code, _ := models.TOTPCode(secret, time.Now())
+	if _, err := models.ConfirmTOTPEnrollment(db, userID, code); err != nil {
+		t.Fatalf("confirm TOTP enrollment: %v", err)
+	}
+	return secret
+}
+
+func TestAuth_LoginSubmit_TOTPRequiresCode(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	user, err := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	secret := enrollTOTP(t, db, user.ID)
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+	var cookies []*http.Cookie
+	post := func(h http.HandlerFunc, target string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(h).ServeHTTP(rr, req)
+		if c := rr.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return rr
+	}
+	loggedInAs := func() int64 {
+		var id int64
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = sm.GetInt64(r.Context(), "userID")
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		return id
+	}
+
+	rr := post(auth.LoginSubmit, "/login", url.Values{"username": {"coach"}, "password": {"password123"}})
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/login" {
+		t.Fatalf("password step: got %d %q, want 303 /login", rr.Code, loc)
+	}
+	if id := loggedInAs(); id != 0 {
+		t.Fatalf("session authenticated as %d before second factor", id)
+	}
+
+	// The login page now asks for the code.
+	req := httptest.NewRequest("GET", "/login", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	page := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(auth.LoginPage)).ServeHTTP(page, req)
+	if !strings.Contains(page.Body.String(), `action="/login/totp"`) {
+		t.Error("login page should show the code form")
+	}
+
+	rr = post(auth.LoginTOTP, "/login/totp", url.Values{"code": {"not-a-code"}})
+	if loc := rr.Header().Get("Location"); loc != "/login" {
+		t.Errorf("wrong code: redirect = %q, want /login", loc)
+	}
+	if id := loggedInAs(); id != 0 {
+		t.Fatalf("session authenticated as %d after wrong code", id)
+	}
+
+	code, _ := models.TOTPCode(secret, time.Now().Add(30*time.Second))
+	rr = post(auth.LoginTOTP, "/login/totp", url.Values{"code": {code}})
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/" {
+		t.Fatalf("code step: got %d %q, want 303 /", rr.Code, loc)
+	}
+	if id := loggedInAs(); id != user.ID {
+		t.Errorf("session user = %d, want %d", id, user.ID)
+	}
+}
+
+func TestAuth_LoginTOTP_TooManyAttempts(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	user, _ := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	enrollTOTP(t, db, user.ID)
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+
+	var pending int64
+	handler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "totp_user_id", user.ID)
+		sm.Put(r.Context(), "totp_username", "coach")
+		sm.Put(r.Context(), "totp_attempts", maxTOTPAttempts-1)
+		auth.LoginTOTP(w, r)
+		pending = sm.GetInt64(r.Context(), "totp_user_id")
+	}))
+	req := httptest.NewRequest("POST", "/login/totp", strings.NewReader("code=000000x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if pending != 0 {
+		t.Error("pending login should be abandoned after too many wrong codes")
+	}
+}
+
+func TestAuth_LoginTOTP_CountsTowardLockout(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	user, _ := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	secret := enrollTOTP(t, db, user.ID)
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+	var cookies []*http.Cookie
+	post := func(h http.HandlerFunc, target string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(h).ServeHTTP(rr, req)
+		if c := rr.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return rr
+	}
+	failures := func() int {
+		var n int
+		db.QueryRow(`SELECT COALESCE(SUM(failures), 0) FROM login_attempts WHERE username = 'coach'`).Scan(&n)
+		return n
+	}
+	password := url.Values{"username": {"coach"}, "password": {"password123"}}
+
+	for range models.LoginLockoutThreshold - 2 {
+		models.RecordFailedLogin(db, "coach")
+	}
+	post(auth.LoginSubmit, "/login", password)
+	if got := failures(); got != models.LoginLockoutThreshold-2 {
+		t.Fatalf("failures after password step = %d, want %d (not reset before the code)", got, models.LoginLockoutThreshold-2)
+	}
+
+	post(auth.LoginTOTP, "/login/totp", url.Values{"code": {"000000x"}})
+	post(auth.LoginTOTP, "/login/totp", url.Values{"code": {"000000x"}})
+	if locked, _, _ := models.IsAccountLocked(db, "coach"); !locked {
+		t.Fatal("wrong codes should lock the account")
+	}
+
+	// The pending login is gone, so even a correct code doesn't sign in.
+	code, _ := models.TOTPCode(secret, time.Now())
+	if rr := post(auth.LoginTOTP, "/login/totp", url.Values{"code": {code}}); rr.Header().Get("Location") != "/login" {
+		t.Errorf("locked: expected redirect to /login, got %q", rr.Header().Get("Location"))
+	}
+
+	db.Exec(`UPDATE login_attempts SET locked_until = NULL`)
+	post(auth.LoginSubmit, "/login", password)
+	code, _ = models.TOTPCode(secret, time.Now().Add(30*time.Second))
+	if rr := post(auth.LoginTOTP, "/login/totp", url.Values{"code": {code}}); rr.Header().Get("Location") != "/" {
+		t.Fatalf("code step: expected redirect to /, got %q", rr.Header().Get("Location"))
+	}
+	if got := failures(); got != 0 {
+		t.Errorf("failures after a completed login = %d, want 0", got)
+	}
+}
+
+func TestAuth_LoginSubmit_TOTPSetupRequired(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	models.SetSetting(db, "security.require_totp", "true")
+
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+
+	var required bool
+	handler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.LoginSubmit(w, r)
+		required = sm.GetBool(r.Context(), "totp_setup_required")
+	}))
+	form := url.Values{"username": {"coach"}, "password": {"password123"}}
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if loc := rr.Header().Get("Location"); loc != "/preferences/totp/begin" {
+		t.Errorf("redirect = %q, want /preferences/totp/begin", loc)
+	}
+	if !required {
+		t.Error("session should be flagged for required TOTP setup")
+	}
+}
+
+// totpEnrollClient drives the enrollment handlers as one browser session,
+// carrying the session cookie between requests.
+type totpEnrollClient struct {
+	t       *testing.T
+	h       *TOTP
+	user    *models.User
+	cookies []*http.Cookie
+}
+
+func (c *totpEnrollClient) do(handler http.HandlerFunc, method, target string, form url.Values) *httptest.ResponseRecorder {
+	c.t.Helper()
+	req := requestWithUser(method, target, form, c.user)
+	for _, ck := range c.cookies {
+		req.AddCookie(ck)
+	}
+	rr := httptest.NewRecorder()
+	c.h.Sessions.LoadAndSave(handler).ServeHTTP(rr, req)
+	if got := rr.Result().Cookies(); len(got) > 0 {
+		c.cookies = got
+	}
+	return rr
+}
+
+// start submits the set-up button and returns the pending secret.
+func (c *totpEnrollClient) start() string {
+	c.t.Helper()
+	if rr := c.do(c.h.StartEnrollment, "POST", "/preferences/totp/begin", url.Values{}); rr.Code != http.StatusSeeOther {
+		c.t.Fatalf("start: expected 303, got %d", rr.Code)
+	}
+	rr := c.do(c.h.BeginEnrollment, "GET", "/preferences/totp/begin", nil)
+	if rr.Code != http.StatusOK {
+		c.t.Fatalf("begin: expected 200, got %d", rr.Code)
+	}
+	pending, err := models.GetUserTOTP(c.h.DB, c.user.ID)
+	if err != nil {
+		c.t.Fatalf("get pending TOTP: %v", err)
+	}
+	if !strings.Contains(rr.Body.String(), pending.Secret) {
+		c.t.Error("begin page should show the pending secret")
+	}
+	return pending.Secret
+}
+
+func TestTOTP_Enrollment(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &TOTP{DB: db, Sessions: sm, Templates: tc}
+	c := &totpEnrollClient{t: t, h: h, user: coach}
+
+	// Viewing the page doesn't create a secret.
+	if rr := c.do(h.BeginEnrollment, "GET", "/preferences/totp/begin", nil); rr.Code != http.StatusOK {
+		t.Fatalf("begin: expected 200, got %d", rr.Code)
+	}
+	if _, err := models.GetUserTOTP(db, coach.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("GET should not start enrollment, got %v", err)
+	}
+
+	secret := c.start()
+
+	rr := c.do(h.FinishEnrollment, "POST", "/preferences/totp/finish", url.Values{"code": {"000000x"}})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("finish with wrong code: expected 422, got %d", rr.Code)
+	}
+
+	code, _ := models.TOTPCode(secret, time.Now())
+	rr = c.do(h.FinishEnrollment, "POST", "/preferences/totp/finish", url.Values{"code": {code}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("finish: expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "recovery-codes") {
+		t.Error("finish page should list recovery codes")
+	}
+	if !models.IsTOTPEnabled(db, coach.ID) {
+		t.Error("TOTP should be enabled")
+	}
+}
+
+func TestTOTP_StartEnrollment_ReusesPendingSecret(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	h := &TOTP{DB: db, Sessions: sm, Templates: tc}
+	c := &totpEnrollClient{t: t, h: h, user: coach}
+
+	first := c.start()
+	if second := c.start(); second != first {
+		t.Error("starting again should keep the pending secret")
+	}
+
+	if rr := c.do(h.CancelEnrollment, "POST", "/preferences/totp/cancel", url.Values{}); rr.Code != http.StatusSeeOther {
+		t.Fatalf("cancel: expected 303, got %d", rr.Code)
+	}
+	if _, err := models.GetUserTOTP(db, coach.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("cancel should discard the pending enrollment, got %v", err)
+	}
+
+	if third := c.start(); third == first {
+		t.Error("enrollment after cancel should use a new secret")
+	}
+}
+
+func TestTOTP_BeginEnrollment_RefusedWhileImpersonating(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	h := &TOTP{DB: db, Sessions: sm, Templates: tc}
+
+	// The coach's session, viewing the app as the kid.
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "userID", coach.ID)
+		sm.Put(r.Context(), middleware.ImpersonateSessionKey, kid.ID)
+	})).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	cookies := rr.Result().Cookies()
+	if err := models.CreateUserSession(db, cookies[0].Value, coach.ID, "", ""); err != nil {
+		t.Fatalf("record session: %v", err)
+	}
+
+	for _, tt := range []struct {
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"GET", h.BeginEnrollment},
+		{"POST", h.StartEnrollment},
+	} {
+		req := httptest.NewRequest(tt.method, "/preferences/totp/begin", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		middleware.RequireAuth(sm, db, middleware.BlockImpersonatedWrites(nil, tt.handler)).ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("%s begin while impersonating: expected 403, got %d", tt.method, rr.Code)
+		}
+	}
+	if _, err := models.GetUserTOTP(db, kid.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("impersonated user should have no pending secret, got %v", err)
+	}
+}
+
+func TestTOTP_Disable(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	secret := enrollTOTP(t, db, coach.ID)
+
+	h := &TOTP{DB: db, Sessions: sm, Templates: tc}
+	disable := func(code string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Disable)).ServeHTTP(rr, requestWithUser("POST", "/preferences/totp/disable", url.Values{"code": {code}}, coach))
+		return rr
+	}
+	code, _ := models.TOTPCode(secret, time.Now().Add(30*time.Second))
+
+	// Required for coaches — can't be turned off.
+	models.SetSetting(db, "security.require_totp", "true")
+	if rr := disable(code); rr.Code != http.StatusForbidden {
+		t.Errorf("disable while required: expected 403, got %d", rr.Code)
+	}
+	models.DeleteSetting(db, "security.require_totp")
+
+	if rr := disable("000000x"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("disable with wrong code: expected 422, got %d", rr.Code)
+	}
+	if !models.IsTOTPEnabled(db, coach.ID) {
+		t.Fatal("TOTP should still be enabled after a wrong code")
+	}
+
+	if rr := disable(code); rr.Code != http.StatusSeeOther {
+		t.Errorf("disable: expected 303, got %d", rr.Code)
+	}
+	if models.IsTOTPEnabled(db, coach.ID) {
+		t.Error("TOTP should be disabled")
+	}
+}
@@ -32,7 +32,7 @@ func (h *TrainingMaxes) NewForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -90,7 +90,7 @@ func (h *TrainingMaxes) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !middleware.CanAccessAthlete(h.DB, user, athleteID) {
+	if !middleware.CanWriteAthlete(h.DB, user, athleteID) {
 		h.Templates.Forbidden(w, r)
 		return
 	}
@@ -117,6 +117,7 @@ func (h *TrainingMaxes) Create(w http.ResponseWriter, r *http.Request) {
 		h.renderFormWithError(w, r, athleteID, exerciseID, "Weight must be a positive number")
 		return
 	}
+	weight = weightInputToLbs(r, weight)
 
 	effectiveDate := r.FormValue("effective_date")
 	if effectiveDate == "" {
@@ -186,21 +187,24 @@ func (h *TrainingMaxes) History(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Load chart data for TM progression.
-	prefs := middleware.PrefsFromContext(r.Context())
-	unit := models.DefaultWeightUnit
-	if prefs != nil {
-		unit = prefs.WeightUnit
-	}
+	unit := preferredWeightUnit(r)
 	chartData, chartErr := models.TrainingMaxChartData(h.DB, athleteID, exerciseID, unit)
 	if chartErr != nil {
 		log.Printf("handlers: TM chart for athlete %d exercise %d: %v", athleteID, exerciseID, chartErr)
 	}
 
+	trend, err := models.TrainingMaxTrend(h.DB, athleteID, exerciseID)
+	if err != nil {
+		log.Printf("handlers: TM trend for athlete %d exercise %d: %v", athleteID, exerciseID, err)
+		// Non-fatal — render without the trend summary.
+	}
+
 	data := map[string]any{
 		"Athlete":  athlete,
 		"Exercise": exercise,
 		"History":  history,
 		"Chart":    chartData,
+		"Trend":    trend,
 	}
 	if err := h.Templates.Render(w, r, "training_max_history.html", data); err != nil {
 		log.Printf("handlers: training max history template: %v", err)
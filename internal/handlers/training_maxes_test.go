@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/carpenike/replog/internal/models"
@@ -150,6 +151,9 @@ func TestTrainingMaxes_History_CoachCanView(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Errorf("expected 200, got %d", rr.Code)
 	}
+	if !strings.Contains(rr.Body.String(), "5%</strong> over 4 weeks") {
+		t.Error("expected TM trend summary in history page")
+	}
 }
 
 func TestTrainingMaxes_History_NonCoachOwnAthlete(t *testing.T) {
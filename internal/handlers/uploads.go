@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// allowedImageTypes maps MIME types to file extensions for allowed image
+// uploads (avatars and note attachments).
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// errUnsupportedImage is returned by saveImageUpload when the file is not
+// one of allowedImageTypes.
+var errUnsupportedImage = errors.New("unsupported image type")
+
+// saveImageUpload sniffs the content type from the first 512 bytes of file
+// (never trusting the client's header or filename) and, if it is an allowed
+// image type, writes it into dir under prefix plus a random name. Returns the
+// stored filename and detected content type.
+func saveImageUpload(file io.ReadSeeker, dir, prefix string) (filename, contentType string, err error) {
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", "", fmt.Errorf("read for detection: %w", err)
+	}
+	contentType = http.DetectContentType(buf[:n])
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return "", "", errUnsupportedImage
+	}
+
+	// Seek back to beginning for the copy.
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", "", fmt.Errorf("seek: %w", err)
+	}
+
+	filename, err = randomUploadName(prefix, ext)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("create dir: %w", err)
+	}
+
+	destPath := filepath.Join(dir, filename)
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return "", "", fmt.Errorf("create file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(destPath)
+		return "", "", fmt.Errorf("write file: %w", err)
+	}
+	return filename, contentType, nil
+}
+
+// randomUploadName returns prefix plus a random name with extension ext,
+// for storing an upload without trusting the client's filename.
+func randomUploadName(prefix, ext string) (string, error) {
+	randBytes := make([]byte, 16)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", fmt.Errorf("generate filename: %w", err)
+	}
+	return prefix + hex.EncodeToString(randBytes) + ext, nil
+}
+
+// uploadPath returns the path of filename inside dir, or "" if the name
+// could escape dir.
+func uploadPath(dir, filename string) string {
+	// Sanitize filename — prevent directory traversal.
+	filename = filepath.Base(filename)
+	if filename == "." || filename == "/" || strings.Contains(filename, "..") {
+		return ""
+	}
+	return filepath.Join(dir, filename)
+}
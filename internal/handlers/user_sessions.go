@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alexedwards/scs/v2"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// UserSessions handles self-service review and revocation of login sessions.
+type UserSessions struct {
+	DB        *sql.DB
+	Sessions  *scs.SessionManager
+	Templates TemplateCache
+}
+
+// List renders the current user's active sessions.
+// GET /preferences/sessions
+func (h *UserSessions) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	sessions, err := models.ListUserSessions(h.DB, user.ID, h.Sessions.Token(r.Context()))
+	if err != nil {
+		log.Printf("handlers: list sessions for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"UserSessions": sessions,
+		"Revoked":      r.URL.Query().Get("revoked"),
+	}
+	if err := h.Templates.Render(w, r, "preferences_sessions.html", data); err != nil {
+		log.Printf("handlers: render sessions: %v", err)
+	}
+}
+
+// Revoke logs out one of the current user's sessions. Revoking the current
+// session is the same as logging out.
+// POST /preferences/sessions/{sessionID}/revoke
+func (h *UserSessions) Revoke(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	sessionID, err := strconv.ParseInt(r.PathValue("sessionID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := models.ListUserSessions(h.DB, user.ID, h.Sessions.Token(r.Context()))
+	if err != nil {
+		log.Printf("handlers: list sessions for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	for _, s := range sessions {
+		if s.ID == sessionID && s.Current {
+			if err := h.Sessions.Destroy(r.Context()); err != nil {
+				log.Printf("handlers: session destroy error: %v", err)
+			}
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+	}
+
+	if err := models.RevokeUserSession(h.DB, user.ID, sessionID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			h.Templates.NotFound(w, r)
+			return
+		}
+		log.Printf("handlers: revoke session %d for user %d: %v", sessionID, user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/preferences/sessions?revoked=1", http.StatusSeeOther)
+}
+
+// RevokeOthers logs out every session of the current user except this one.
+// POST /preferences/sessions/revoke-others
+func (h *UserSessions) RevokeOthers(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	n, err := models.RevokeUserSessions(h.DB, user.ID, h.Sessions.Token(r.Context()))
+	if err != nil {
+		log.Printf("handlers: revoke other sessions for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/preferences/sessions?revoked="+strconv.FormatInt(n, 10), http.StatusSeeOther)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexedwards/scs/sqlite3store"
+
+	"github.com/carpenike/replog/internal/middleware"
+	"github.com/carpenike/replog/internal/models"
+)
+
+func TestUserSessions_ListAndRevokeOthers(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	sm.Store = sqlite3store.NewWithCleanupInterval(db, 0)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	// Sign in from two devices.
+	login := func(ua string) *http.Cookie {
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sm.Put(r.Context(), "userID", coach.ID)
+		})).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		cookie := rr.Result().Cookies()[0]
+		if err := models.CreateUserSession(db, cookie.Value, coach.ID, "192.0.2.1", ua); err != nil {
+			t.Fatalf("record session: %v", err)
+		}
+		return cookie
+	}
+	laptop := login("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) Firefox/121.0")
+	phone := login("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Version/17.0 Mobile/15E148 Safari/604.1")
+
+	h := &UserSessions{DB: db, Sessions: sm, Templates: tc}
+	serve := func(handler http.HandlerFunc, method, target string) *httptest.ResponseRecorder {
+		req := requestWithUser(method, target, nil, coach)
+		req.AddCookie(laptop)
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(handler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(h.List, "GET", "/preferences/sessions")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Firefox on Mac (this device)") || !strings.Contains(body, "Safari on iPhone") {
+		t.Errorf("list should show both devices and mark the current one, got:\n%s", body)
+	}
+
+	rr = serve(h.RevokeOthers, "POST", "/preferences/sessions/revoke-others")
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("revoke others: expected 303, got %d", rr.Code)
+	}
+
+	// The phone's session is gone; the laptop's still works.
+	authed := func(c *http.Cookie) bool {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(c)
+		rr := httptest.NewRecorder()
+		middleware.RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+		return rr.Code == http.StatusOK
+	}
+	if authed(phone) {
+		t.Error("phone session should be logged out")
+	}
+	if !authed(laptop) {
+		t.Error("current session should stay logged in")
+	}
+}
+
+func TestUserSessions_RevokeOtherUsersSession(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	db.Exec(`INSERT INTO sessions (token, data, expiry) VALUES ('kid-token', x'00', julianday('now', '+1 day'))`)
+	models.CreateUserSession(db, "kid-token", kid.ID, "", "")
+	sessions, _ := models.ListUserSessions(db, kid.ID, "")
+
+	h := &UserSessions{DB: db, Sessions: sm, Templates: tc}
+	req := requestWithUser("POST", "/preferences/sessions/"+itoa(sessions[0].ID)+"/revoke", nil, coach)
+	req.SetPathValue("sessionID", itoa(sessions[0].ID))
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.Revoke)).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+	if sessions, _ := models.ListUserSessions(db, kid.ID, ""); len(sessions) != 1 {
+		t.Error("another user's session should not be revoked")
+	}
+}
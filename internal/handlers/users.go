@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/carpenike/replog/internal/middleware"
@@ -71,7 +72,8 @@ func (h *Users) NewForm(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]any{
-		"Athletes": athletes,
+		"Athletes":          athletes,
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
 	}
 	if err := h.Templates.Render(w, r, "user_form.html", data); err != nil {
 		log.Printf("handlers: render new user form: %v", err)
@@ -97,9 +99,11 @@ func (h *Users) Create(w http.ResponseWriter, r *http.Request) {
 		h.renderFormError(w, r, "Username is required.", nil)
 		return
 	}
-	if password != "" && len(password) < 6 {
-		h.renderFormError(w, r, "Password must be at least 6 characters.", nil)
-		return
+	if password != "" {
+		if err := models.ValidatePasswordStrength(h.DB, username, password); err != nil {
+			h.renderPasswordError(w, r, err, nil)
+			return
+		}
 	}
 
 	var athleteID sql.NullInt64
@@ -149,6 +153,8 @@ func (h *Users) Create(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			log.Printf("handlers: auto-create token for user %d: %v", newUser.ID, err)
 		} else {
+			recordAudit(h.DB, middleware.UserFromContext(r.Context()).ID, models.AuditLoginTokenCreate,
+				models.AuditTarget("user", newUser.ID, newUser.Username))
 			var loginURL string
 			if h.BaseURL != "" {
 				loginURL = fmt.Sprintf("%s/auth/token/%s", h.BaseURL, lt.Token)
@@ -208,9 +214,11 @@ func (h *Users) EditForm(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]any{
-		"EditUser": u,
-		"Athletes": athletes,
-		"Tokens":   tokens,
+		"EditUser":          u,
+		"Athletes":          athletes,
+		"Tokens":            tokens,
+		"PasskeyCount":      h.passkeyCount(u.ID),
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
 	}
 	if err := h.Templates.Render(w, r, "user_form.html", data); err != nil {
 		log.Printf("handlers: render edit user form: %v", err)
@@ -261,8 +269,22 @@ func (h *Users) Update(w http.ResponseWriter, r *http.Request) {
 			h.renderFormError(w, r, "This account uses passwordless login. Password cannot be set.", u)
 			return
 		}
-		if len(newPassword) < 6 {
-			h.renderFormError(w, r, "Password must be at least 6 characters.", u)
+		if err := models.ValidatePasswordStrength(h.DB, username, newPassword); err != nil {
+			h.renderPasswordError(w, r, err, u)
+			return
+		}
+	}
+
+	// Passkey only removes the password; the account must already have a
+	// passkey so it isn't locked out.
+	passkeyOnly := r.FormValue("passkey_only") == "1" && u.HasPassword()
+	if passkeyOnly {
+		if newPassword != "" {
+			h.renderFormError(w, r, "Choose either a new password or passkey only, not both.", u)
+			return
+		}
+		if h.passkeyCount(u.ID) == 0 {
+			h.renderFormError(w, r, "Register a passkey for this account before making it passkey only.", u)
 			return
 		}
 	}
@@ -291,6 +313,17 @@ func (h *Users) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if passkeyOnly {
+		if err := models.RemovePassword(h.DB, id); errors.Is(err, models.ErrNoPasskeys) {
+			h.renderFormError(w, r, "Register a passkey for this account before making it passkey only.", u)
+			return
+		} else if err != nil {
+			log.Printf("handlers: remove password for user %d: %v", id, err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Update password if provided (already validated above).
 	if newPassword != "" {
 		if err := models.UpdatePassword(h.DB, id, newPassword); err != nil {
@@ -329,17 +362,230 @@ func (h *Users) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	target := auditUserTarget(h.DB, id)
 	if err := models.DeleteUser(h.DB, id); err != nil {
 		log.Printf("handlers: delete user %d: %v", id, err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
+	recordAudit(h.DB, authUser.ID, models.AuditUserDelete, target)
 
 	http.Redirect(w, r, "/users", http.StatusSeeOther)
 }
 
+// ChangePasswordForm renders the self-service password change form.
+// GET /preferences/password
+func (h *Users) ChangePasswordForm(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	data := map[string]any{
+		"HasPassword":       user.HasPassword(),
+		"Changed":           r.URL.Query().Get("changed") == "1",
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
+	}
+	if err := h.Templates.Render(w, r, "preferences_password.html", data); err != nil {
+		log.Printf("handlers: render change password form: %v", err)
+	}
+}
+
+// ChangePassword lets the current user change their own password after
+// confirming the current one. Every other session is logged out and any
+// outstanding login links are revoked.
+// POST /preferences/password
+func (h *Users) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+
+	if !user.HasPassword() {
+		h.renderChangePasswordError(w, r, "This account uses passwordless login. Password cannot be set.", nil)
+		return
+	}
+
+	current := r.FormValue("current_password")
+	newPassword := r.FormValue("new_password")
+	confirm := r.FormValue("confirm_password")
+
+	// Wrong current passwords count toward the same lockout as sign-ins, so
+	// a stolen session can't be used to guess the password.
+	locked, until, err := models.IsAccountLocked(h.DB, user.Username)
+	if err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	if locked {
+		log.Printf("handlers: password change for %q refused: locked until %s", user.Username, until.Format(time.RFC3339))
+		h.renderChangePasswordError(w, r, lockoutMessage(until), nil)
+		return
+	}
+	if _, err := models.Authenticate(h.DB, user.Username, current); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("handlers: verify current password for user %d: %v", user.ID, err)
+		}
+		msg := "Please correct the errors below."
+		until, err := models.RecordFailedLogin(h.DB, user.Username)
+		if err != nil {
+			log.Printf("handlers: %v", err)
+		} else if !until.IsZero() {
+			log.Printf("handlers: password change for %q locked until %s", user.Username, until.Format(time.RFC3339))
+			msg = lockoutMessage(until)
+		}
+		h.renderChangePasswordError(w, r, msg, map[string]string{
+			"current_password": "Current password is incorrect.",
+		})
+		return
+	}
+	if err := models.ResetFailedLogins(h.DB, user.Username); err != nil {
+		log.Printf("handlers: %v", err)
+	}
+	if err := models.ValidatePasswordStrength(h.DB, user.Username, newPassword); err != nil {
+		msg := "Password does not meet the requirements."
+		var pe *models.PasswordPolicyError
+		if errors.As(err, &pe) {
+			msg = pe.Reason
+		}
+		h.renderChangePasswordError(w, r, "Please correct the errors below.", map[string]string{"new_password": msg})
+		return
+	}
+	if newPassword != confirm {
+		h.renderChangePasswordError(w, r, "Please correct the errors below.", map[string]string{
+			"confirm_password": "Passwords don't match.",
+		})
+		return
+	}
+
+	if err := models.UpdatePassword(h.DB, user.ID, newPassword); err != nil {
+		log.Printf("handlers: change password for user %d: %v", user.ID, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if err := models.DeleteLoginTokensByUser(h.DB, user.ID); err != nil {
+		log.Printf("handlers: revoke tokens after password change for user %d: %v", user.ID, err)
+		// Non-fatal — continue.
+	}
+	if _, err := models.RevokeUserSessions(h.DB, user.ID, h.Sessions.Token(r.Context())); err != nil {
+		log.Printf("handlers: revoke sessions after password change for user %d: %v", user.ID, err)
+		// Non-fatal — continue.
+	}
+
+	http.Redirect(w, r, "/preferences/password?changed=1", http.StatusSeeOther)
+}
+
+// Impersonate lets a coach or admin preview the app as another user sees it.
+// It doesn't log in as that user: the real login is kept and a session flag
+// makes RequireAuth render pages as the target, read-only, until the preview
+// is ended. Admins can view as any non-admin; coaches only as athletes they
+// coach.
+// POST /users/{id}/impersonate
+func (h *Users) Impersonate(w http.ResponseWriter, r *http.Request) {
+	authUser := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	target, err := models.GetUserByID(h.DB, id)
+	if errors.Is(err, models.ErrNotFound) {
+		h.Templates.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get user %d for impersonation: %v", id, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if !middleware.CanImpersonate(h.DB, authUser, target) {
+		h.Templates.Forbidden(w, r)
+		return
+	}
+
+	h.Sessions.Put(r.Context(), middleware.ImpersonateSessionKey, target.ID)
+	recordAudit(h.DB, authUser.ID, models.AuditImpersonateStart, models.AuditTarget("user", target.ID, target.Username))
+	log.Printf("handlers: user %d started viewing as user %d", authUser.ID, target.ID)
+
+	if target.AthleteID.Valid {
+		http.Redirect(w, r, "/athletes/"+strconv.FormatInt(target.AthleteID.Int64, 10), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// StopImpersonating ends a "view as" preview and returns to the real user.
+// It runs outside RequireAuth so it isn't blocked as a write while
+// impersonating.
+// POST /impersonate/stop
+func (h *Users) StopImpersonating(w http.ResponseWriter, r *http.Request) {
+	targetID := endImpersonation(h.DB, h.Sessions, r)
+	if targetID != 0 {
+		if target, err := models.GetUserByID(h.DB, targetID); err == nil && target.AthleteID.Valid {
+			http.Redirect(w, r, "/athletes/"+strconv.FormatInt(target.AthleteID.Int64, 10), http.StatusSeeOther)
+			return
+		}
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// endImpersonation clears any "view as" preview from the session and records
+// its end in the audit log. Returns the ID of the user that was being viewed,
+// or 0 if there was no preview.
+func endImpersonation(db *sql.DB, sm *scs.SessionManager, r *http.Request) int64 {
+	targetID := sm.GetInt64(r.Context(), middleware.ImpersonateSessionKey)
+	if targetID == 0 {
+		return 0
+	}
+	sm.Remove(r.Context(), middleware.ImpersonateSessionKey)
+	userID := sm.GetInt64(r.Context(), "userID")
+	recordAudit(db, userID, models.AuditImpersonateStop, auditUserTarget(db, targetID))
+	log.Printf("handlers: user %d stopped viewing as user %d", userID, targetID)
+	return targetID
+}
+
+// passkeyCount returns how many passkeys a user has registered, or 0 if
+// they can't be listed.
+func (h *Users) passkeyCount(userID int64) int {
+	creds, err := models.ListWebAuthnCredentialsByUser(h.DB, userID)
+	if err != nil {
+		log.Printf("handlers: list passkeys for user %d: %v", userID, err)
+		return 0
+	}
+	return len(creds)
+}
+
+// renderChangePasswordError re-renders the password change form with a
+// summary message and optional per-field messages keyed by input name.
+func (h *Users) renderChangePasswordError(w http.ResponseWriter, r *http.Request, msg string, fieldErrors map[string]string) {
+	user := middleware.UserFromContext(r.Context())
+	data := map[string]any{
+		"Error":             msg,
+		"FieldErrors":       fieldErrors,
+		"HasPassword":       user.HasPassword(),
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
+	}
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := h.Templates.Render(w, r, "preferences_password.html", data); err != nil {
+		log.Printf("handlers: render change password form: %v", err)
+	}
+}
+
 // renderFormError re-renders the user form with an error message.
 func (h *Users) renderFormError(w http.ResponseWriter, r *http.Request, msg string, u *models.User) {
+	h.renderFormErrors(w, r, msg, nil, u)
+}
+
+// renderPasswordError re-renders the user form with a password policy
+// violation shown on the password field.
+func (h *Users) renderPasswordError(w http.ResponseWriter, r *http.Request, err error, u *models.User) {
+	msg := "Password does not meet the requirements."
+	var pe *models.PasswordPolicyError
+	if errors.As(err, &pe) {
+		msg = pe.Reason
+	}
+	h.renderFormErrors(w, r, "Please correct the password.", map[string]string{"password": msg}, u)
+}
+
+// renderFormErrors re-renders the user form with a summary message and
+// optional per-field messages keyed by input name.
+func (h *Users) renderFormErrors(w http.ResponseWriter, r *http.Request, msg string, fieldErrors map[string]string, u *models.User) {
 	var exceptAthleteID int64
 	if u != nil && u.AthleteID.Valid {
 		exceptAthleteID = u.AthleteID.Int64
@@ -349,9 +595,14 @@ func (h *Users) renderFormError(w http.ResponseWriter, r *http.Request, msg stri
 		log.Printf("handlers: list available athletes: %v", err)
 	}
 	data := map[string]any{
-		"Error":    msg,
-		"EditUser": u,
-		"Athletes": athletes,
+		"Error":             msg,
+		"FieldErrors":       fieldErrors,
+		"EditUser":          u,
+		"Athletes":          athletes,
+		"PasswordMinLength": models.GetPasswordMinLength(h.DB),
+	}
+	if u != nil {
+		data["PasskeyCount"] = h.passkeyCount(u.ID)
 	}
 	w.WriteHeader(http.StatusUnprocessableEntity)
 	if err := h.Templates.Render(w, r, "user_form.html", data); err != nil {
@@ -9,6 +9,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/alexedwards/scs/sqlite3store"
+	"github.com/go-webauthn/webauthn/webauthn"
+
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
 )
@@ -123,6 +126,33 @@ func TestUsers_Create_ShortPassword(t *testing.T) {
 	}
 }
 
+func TestUsers_Create_PasswordPolicy(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	models.SetSetting(db, "security.password_complexity", "mixed")
+
+	h := &Users{DB: db, Templates: tc}
+
+	form := url.Values{
+		"username": {"newuser"},
+		"password": {"alllowercase"},
+	}
+	req := requestWithUser("POST", "/users", form, coach)
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `id="password-error"`) {
+		t.Error("expected the policy error on the password field")
+	}
+	if _, err := models.GetUserByUsername(db, "newuser"); err == nil {
+		t.Error("user should not be created with a weak password")
+	}
+}
+
 func TestUsers_Create_DuplicateUsername(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -592,6 +622,43 @@ func TestUsers_Update_BlockPasswordForPasswordless(t *testing.T) {
 	}
 }
 
+func TestUsers_Update_PasskeyOnly(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+
+	target, err := models.CreateUser(db, "goingpasskey", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	h := &Users{DB: db, Templates: tc}
+	update := func() *httptest.ResponseRecorder {
+		form := url.Values{"username": {"goingpasskey"}, "passkey_only": {"1"}}
+		req := requestWithUser("POST", "/users/"+itoa(target.ID), form, coach)
+		req.SetPathValue("id", itoa(target.ID))
+		rr := httptest.NewRecorder()
+		h.Update(rr, req)
+		return rr
+	}
+
+	// Without a passkey the account would be locked out.
+	if rr := update(); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("without passkey: expected 422, got %d", rr.Code)
+	}
+	if u, _ := models.GetUserByID(db, target.ID); !u.HasPassword() {
+		t.Fatal("password should be kept when the account has no passkey")
+	}
+
+	models.CreateWebAuthnCredential(db, target.ID, &webauthn.Credential{ID: []byte("po-1"), PublicKey: []byte("k"), AttestationType: "none"}, "phone")
+	if rr := update(); rr.Code != http.StatusSeeOther {
+		t.Errorf("with passkey: expected 303, got %d", rr.Code)
+	}
+	if u, _ := models.GetUserByID(db, target.ID); u.HasPassword() {
+		t.Error("password should be removed")
+	}
+}
+
 func TestUsers_Create_InlineAthleteCreation(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -669,3 +736,230 @@ func TestUsers_Create_InlineAthleteDefaultsToUsername(t *testing.T) {
 		t.Errorf("athlete name = %q, want Kidnoname", athlete.Name)
 	}
 }
+
+func TestUsers_ChangePassword(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	sm.Store = sqlite3store.NewWithCleanupInterval(db, 0)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	// Sign in from two devices.
+	login := func() *http.Cookie {
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sm.Put(r.Context(), "userID", kid.ID)
+		})).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		cookie := rr.Result().Cookies()[0]
+		if err := models.CreateUserSession(db, cookie.Value, kid.ID, "", ""); err != nil {
+			t.Fatalf("record session: %v", err)
+		}
+		return cookie
+	}
+	current := login()
+	other := login()
+	if _, err := models.CreateLoginToken(db, kid.ID, "iPad", nil); err != nil {
+		t.Fatalf("create login token: %v", err)
+	}
+
+	h := &Users{DB: db, Sessions: sm, Templates: tc}
+	change := func(form url.Values) *httptest.ResponseRecorder {
+		req := requestWithUser("POST", "/preferences/password", form, kid)
+		req.AddCookie(current)
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.ChangePassword)).ServeHTTP(rr, req)
+		return rr
+	}
+
+	tests := []struct {
+		name  string
+		form  url.Values
+		field string
+	}{
+		{"wrong current password", url.Values{"current_password": {"nope"}, "new_password": {"new-password-1"}, "confirm_password": {"new-password-1"}}, "current_password"},
+		{"too short", url.Values{"current_password": {"password123"}, "new_password": {"short"}, "confirm_password": {"short"}}, "new_password"},
+		{"mismatch", url.Values{"current_password": {"password123"}, "new_password": {"new-password-1"}, "confirm_password": {"new-password-2"}}, "confirm_password"},
+	}
+	for _, tt := range tests {
+		rr := change(tt.form)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d", tt.name, rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), `id="`+tt.field+`-error"`) {
+			t.Errorf("%s: expected error on %s", tt.name, tt.field)
+		}
+	}
+	if _, err := models.Authenticate(db, "kid", "password123"); err != nil {
+		t.Fatalf("password should be unchanged after rejected attempts: %v", err)
+	}
+
+	rr := change(url.Values{"current_password": {"password123"}, "new_password": {"new-password-1"}, "confirm_password": {"new-password-1"}})
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/preferences/password?changed=1" {
+		t.Fatalf("change: got %d %q, want 303 /preferences/password?changed=1", rr.Code, loc)
+	}
+	if _, err := models.Authenticate(db, "kid", "new-password-1"); err != nil {
+		t.Errorf("new password should work: %v", err)
+	}
+	if tokens, _ := models.ListLoginTokensByUser(db, kid.ID); len(tokens) != 0 {
+		t.Errorf("login tokens after password change = %d, want 0", len(tokens))
+	}
+
+	authed := func(c *http.Cookie) bool {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(c)
+		rr := httptest.NewRecorder()
+		middleware.RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+		return rr.Code == http.StatusOK
+	}
+	if authed(other) {
+		t.Error("other session should be logged out")
+	}
+	if !authed(current) {
+		t.Error("current session should stay logged in")
+	}
+}
+
+func TestUsers_ChangePassword_Lockout(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	h := &Users{DB: db, Sessions: sm, Templates: tc}
+	change := func(current string) *httptest.ResponseRecorder {
+		form := url.Values{"current_password": {current}, "new_password": {"new-password-1"}, "confirm_password": {"new-password-1"}}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.ChangePassword)).ServeHTTP(rr, requestWithUser("POST", "/preferences/password", form, kid))
+		return rr
+	}
+
+	var rr *httptest.ResponseRecorder
+	for i := 0; i < models.LoginLockoutThreshold; i++ {
+		rr = change("wrong-password")
+	}
+	if !strings.Contains(rr.Body.String(), "Too many failed sign-in attempts") {
+		t.Error("expected lockout message after repeated wrong passwords")
+	}
+	if locked, _, _ := models.IsAccountLocked(db, kid.Username); !locked {
+		t.Fatal("account should be locked")
+	}
+
+	// The right password is refused while locked.
+	if rr := change("password123"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("while locked: expected 422, got %d", rr.Code)
+	}
+	if _, err := models.Authenticate(db, "kid", "new-password-1"); err == nil {
+		t.Error("password should not change while the account is locked")
+	}
+}
+
+func TestUsers_ChangePassword_Passwordless(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	tc := testTemplateCache(t)
+
+	user, err := models.CreateUser(db, "nopass", "", "", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	h := &Users{DB: db, Sessions: sm, Templates: tc}
+	form := url.Values{"current_password": {""}, "new_password": {"new-password-1"}, "confirm_password": {"new-password-1"}}
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.ChangePassword)).ServeHTTP(rr, requestWithUser("POST", "/preferences/password", form, user))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rr.Code)
+	}
+	if u, _ := models.GetUserByID(db, user.ID); u.HasPassword() {
+		t.Error("passwordless account should not gain a password")
+	}
+}
+
+func TestUsers_Impersonate(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+	sm.Store = sqlite3store.NewWithCleanupInterval(db, 0)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Kid", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	rr := httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "userID", coach.ID)
+	})).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	cookie := rr.Result().Cookies()[0]
+
+	h := &Users{DB: db, Sessions: sm, Templates: tc}
+	impersonate := func(user *models.User, targetID int64) *httptest.ResponseRecorder {
+		req := requestWithUser("POST", "/users/"+itoa(targetID)+"/impersonate", nil, user)
+		req.SetPathValue("id", itoa(targetID))
+		req.AddCookie(cookie)
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(http.HandlerFunc(h.Impersonate)).ServeHTTP(rr, req)
+		return rr
+	}
+	impersonating := func() int64 {
+		var id int64
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(cookie)
+		sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = sm.GetInt64(r.Context(), middleware.ImpersonateSessionKey)
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		return id
+	}
+	auditCount := func(action string) int {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE user_id = ? AND action = ?`, coach.ID, action).Scan(&n); err != nil {
+			t.Fatalf("count audit log: %v", err)
+		}
+		return n
+	}
+
+	if rr := impersonate(kid, coach.ID); rr.Code != http.StatusForbidden {
+		t.Errorf("non-coach: expected 403, got %d", rr.Code)
+	}
+	if rr := impersonate(coach, coach.ID); rr.Code != http.StatusForbidden {
+		t.Errorf("self: expected 403, got %d", rr.Code)
+	}
+	if rr := impersonate(coach, 9999); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown user: expected 404, got %d", rr.Code)
+	}
+
+	rr = impersonate(coach, kid.ID)
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/athletes/"+itoa(athlete.ID) {
+		t.Fatalf("impersonate: got %d %q, want 303 to the athlete", rr.Code, loc)
+	}
+	if got := impersonating(); got != kid.ID {
+		t.Errorf("session impersonating %d, want %d", got, kid.ID)
+	}
+	if n := auditCount(models.AuditImpersonateStart); n != 1 {
+		t.Errorf("expected 1 start audit entry, got %d", n)
+	}
+
+	req := httptest.NewRequest("POST", "/impersonate/stop", nil)
+	req.AddCookie(cookie)
+	rr = httptest.NewRecorder()
+	sm.LoadAndSave(http.HandlerFunc(h.StopImpersonating)).ServeHTTP(rr, req)
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || loc != "/athletes/"+itoa(athlete.ID) {
+		t.Fatalf("stop: got %d %q, want 303 to the athlete", rr.Code, loc)
+	}
+	if got := impersonating(); got != 0 {
+		t.Errorf("expected impersonation cleared, got %d", got)
+	}
+	if n := auditCount(models.AuditImpersonateStop); n != 1 {
+		t.Errorf("expected 1 stop audit entry, got %d", n)
+	}
+
+	// Logging out ends the preview too.
+	impersonate(coach, kid.ID)
+	auth := &Auth{DB: db, Sessions: sm, Templates: tc}
+	req = httptest.NewRequest("POST", "/logout", nil)
+	req.AddCookie(cookie)
+	sm.LoadAndSave(http.HandlerFunc(auth.Logout)).ServeHTTP(httptest.NewRecorder(), req)
+	if n := auditCount(models.AuditImpersonateStop); n != 2 {
+		t.Errorf("expected logout to record a stop audit entry, got %d", n)
+	}
+}
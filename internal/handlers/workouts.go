@@ -2,27 +2,41 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/alexedwards/scs/v2"
 	"github.com/carpenike/replog/internal/middleware"
 	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
 )
 
+func init() {
+	gob.Register(&models.ActiveTimer{})
+}
+
 // Workouts holds dependencies for workout handlers.
 type Workouts struct {
 	DB        *sql.DB
 	Templates TemplateCache
+	// Sessions persists the active rest timer across reloads. Optional;
+	// when nil, the timer only runs client-side.
+	Sessions *scs.SessionManager
 }
 
-// checkAthleteAccess verifies the user can access the given athlete.
-// Returns the parsed athlete ID and true on success, or 0 and false after
-// writing an error/forbidden response.
+// checkAthleteAccess verifies the user can access the given athlete. GET and
+// HEAD requests need read access; any other method needs write access, so
+// read-only viewers can look but not change anything. Returns the parsed
+// athlete ID and true on success, or 0 and false after writing an
+// error/forbidden response.
 func checkAthleteAccess(db *sql.DB, tc TemplateCache, w http.ResponseWriter, r *http.Request) (int64, bool) {
 	user := middleware.UserFromContext(r.Context())
 	athleteIDStr := r.PathValue("id")
@@ -31,7 +45,11 @@ func checkAthleteAccess(db *sql.DB, tc TemplateCache, w http.ResponseWriter, r *
 		http.Error(w, "Invalid athlete ID", http.StatusBadRequest)
 		return 0, false
 	}
-	if !middleware.CanAccessAthlete(db, user, athleteID) {
+	allowed := middleware.CanAccessAthlete
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		allowed = middleware.CanWriteAthlete
+	}
+	if !allowed(db, user, athleteID) {
 		tc.Forbidden(w, r)
 		return 0, false
 	}
@@ -61,7 +79,7 @@ func (h *Workouts) List(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	page, err := models.ListWorkouts(h.DB, athleteID, offset)
+	page, err := models.ListWorkouts(h.DB, athleteID, offset, models.WorkoutPageSize)
 	if err != nil {
 		log.Printf("handlers: list workouts for athlete %d: %v", athleteID, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -142,7 +160,18 @@ func (h *Workouts) Create(w http.ResponseWriter, r *http.Request) {
 		assignmentID, _ = strconv.ParseInt(aidStr, 10, 64)
 	}
 
-	workout, err := models.CreateWorkout(h.DB, athleteID, date, notes, assignmentID)
+	// Optional body weight logged alongside the workout.
+	var bodyWeight float64
+	if bwStr := r.FormValue("body_weight"); bwStr != "" {
+		v, err := strconv.ParseFloat(bwStr, 64)
+		if err != nil || v <= 0 {
+			http.Error(w, "Body weight must be a positive number", http.StatusBadRequest)
+			return
+		}
+		bodyWeight = weightInputToLbs(r, v)
+	}
+
+	workout, err := models.CreateWorkoutWithBodyWeight(h.DB, athleteID, date, notes, assignmentID, bodyWeight)
 	if errors.Is(err, models.ErrWorkoutExists) {
 		// Redirect to the existing workout for that date.
 		existing, getErr := models.GetWorkoutByAthleteDate(h.DB, athleteID, date)
@@ -232,6 +261,20 @@ func (h *Workouts) Show(w http.ResponseWriter, r *http.Request) {
 	if errMsg := r.URL.Query().Get("error"); errMsg != "" {
 		data["SetError"] = errMsg
 	}
+	if msg := r.URL.Query().Get("success"); msg != "" {
+		data["Success"] = msg
+	}
+	// Resume a rest timer still running for this workout.
+	if h.Sessions != nil {
+		if timer := models.GetActiveTimer(r.Context(), h.Sessions); timer != nil && timer.WorkoutID == workoutID {
+			if remaining := timer.Remaining(time.Now()); remaining > 0 {
+				data["RestTimer"] = timer
+				data["RestRemaining"] = remaining
+			} else {
+				models.ClearActiveTimer(r.Context(), h.Sessions)
+			}
+		}
+	}
 	// Sticky exercise: if redirected from AddSet, pre-select the exercise.
 	if eidStr := r.URL.Query().Get("exercise_id"); eidStr != "" {
 		data["SelectedExerciseID"], _ = strconv.ParseInt(eidStr, 10, 64)
@@ -243,6 +286,50 @@ func (h *Workouts) Show(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ShowJSON returns a single workout, its sets, and its review status as JSON
+// using the same shape as the athlete JSON export.
+func (h *Workouts) ShowJSON(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify the workout belongs to the specified athlete.
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	ew, err := models.BuildExportWorkout(h.DB, workout)
+	if err != nil {
+		log.Printf("handlers: build workout json %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ew); err != nil {
+		log.Printf("handlers: encode workout JSON %d: %v", workoutID, err)
+	}
+}
+
 // loadWorkoutShowData fetches all data needed for the workout detail page.
 // Fatal queries return errors; non-fatal queries log and continue with nil/zero values.
 func (h *Workouts) loadWorkoutShowData(user *models.User, athlete *models.Athlete, workout *models.Workout) (map[string]any, error) {
@@ -260,7 +347,7 @@ func (h *Workouts) loadWorkoutShowData(user *models.User, athlete *models.Athlet
 		return nil, fmt.Errorf("list assignments: %w", err)
 	}
 
-	allExercises, err := models.ListExercises(h.DB, "")
+	allExercises, err := models.ListExercises(h.DB, models.ExerciseFilter{})
 	if err != nil {
 		return nil, fmt.Errorf("list exercises: %w", err)
 	}
@@ -339,14 +426,17 @@ func (h *Workouts) loadWorkoutShowData(user *models.User, athlete *models.Athlet
 		loggedSetCounts[g.ExerciseID] = len(g.Sets)
 	}
 
-	// Load accessory plans for the current program day (if known).
+	// Accessory plans for the current program day come with the prescription.
 	var accessoryPlans []*models.AccessoryPlan
-	if prescription != nil && prescription.CurrentDay > 0 {
-		accessoryPlans, err = models.ListAccessoryPlansForDay(h.DB, athleteID, prescription.CurrentDay)
-		if err != nil {
-			log.Printf("handlers: list accessory plans for athlete %d day %d: %v", athleteID, prescription.CurrentDay, err)
-			// Non-fatal — continue without accessories.
-		}
+	if prescription != nil {
+		accessoryPlans = prescription.Accessories
+	}
+
+	// Badge sets that are personal records.
+	prSetIDs, err := models.ListWorkoutPRSetIDs(h.DB, workoutID)
+	if err != nil {
+		log.Printf("handlers: list PR sets for workout %d: %v", workoutID, err)
+		// Non-fatal — continue without PR badges.
 	}
 
 	// Load review for this workout (if any).
@@ -359,6 +449,17 @@ func (h *Workouts) loadWorkoutShowData(user *models.User, athlete *models.Athlet
 		// Non-fatal — continue without review data.
 	}
 
+	// Coaches can drop canned feedback into the review notes.
+	canManage := middleware.CanManageAthlete(user, athlete)
+	var reviewTemplates []*models.ReviewTemplate
+	if canManage {
+		reviewTemplates, err = models.ListReviewTemplates(h.DB, user.ID)
+		if err != nil {
+			log.Printf("handlers: list review templates for user %d: %v", user.ID, err)
+			// Non-fatal — the review form works without templates.
+		}
+	}
+
 	return map[string]any{
 		"Athlete":         athlete,
 		"Workout":         workout,
@@ -371,8 +472,10 @@ func (h *Workouts) loadWorkoutShowData(user *models.User, athlete *models.Athlet
 		"LoggedSetCounts": loggedSetCounts,
 		"AccessoryPlans":  accessoryPlans,
 		"LastSession":     lastSession,
+		"PRSetIDs":        prSetIDs,
 		"Review":          review,
-		"CanManage":       middleware.CanManageAthlete(user, athlete),
+		"ReviewTemplates": reviewTemplates,
+		"CanManage":       canManage,
 		"IsOwnProfile":    user.AthleteID.Valid && user.AthleteID.Int64 == int64(athlete.ID),
 	}, nil
 }
@@ -420,6 +523,245 @@ func (h *Workouts) UpdateNotes(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
 }
 
+// StartSession starts the session timer for a workout. Any duration already
+// recorded is replaced when the timer is stopped.
+func (h *Workouts) StartSession(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	if err := models.StartWorkoutSession(h.DB, workoutID); err != nil {
+		log.Printf("handlers: start workout %d session: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// StopSession stops a workout's running session timer and records the
+// elapsed time as its duration.
+func (h *Workouts) StopSession(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	_, err = models.StopWorkoutSession(h.DB, workoutID)
+	if errors.Is(err, models.ErrInvalidInput) {
+		workoutRedirectWithError(w, r, athleteID, workoutID, "The session timer isn't running.")
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: stop workout %d session: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// UpdateDuration records a workout's session length entered by hand. An
+// empty or zero value clears it.
+func (h *Workouts) UpdateDuration(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	minutes := 0
+	if v := strings.TrimSpace(r.FormValue("duration_minutes")); v != "" {
+		minutes, err = strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			workoutRedirectWithError(w, r, athleteID, workoutID, "Duration must be a whole number of minutes.")
+			return
+		}
+	}
+
+	if err := models.SetWorkoutDuration(h.DB, workoutID, minutes); err != nil {
+		log.Printf("handlers: set workout %d duration: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// MarkComplete marks a workout complete, placing it in the coach's
+// pending-review queue. When an athlete completes their own workout, their
+// coach is notified that it is ready for review.
+func (h *Workouts) MarkComplete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	// Verify the workout belongs to the specified athlete.
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	if err := models.SetWorkoutPendingReview(h.DB, workoutID); err != nil {
+		log.Printf("handlers: mark workout %d complete: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Coaches' own workouts are auto-approved, so only an athlete completing
+	// a workout for the first time needs to ask for a review.
+	if !workout.CompletedAt.Valid && !user.IsCoach && !user.IsAdmin {
+		h.notifyWorkoutCompleted(workout)
+		h.notifyNewStalls(athleteID)
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// notifyWorkoutCompleted tells the athlete's coach that a workout is ready
+// for review. Athletes without a coach are skipped.
+func (h *Workouts) notifyWorkoutCompleted(workout *models.Workout) {
+	athlete, err := models.GetAthleteByID(h.DB, workout.AthleteID)
+	if err != nil {
+		log.Printf("handlers: get athlete %d for workout completion: %v", workout.AthleteID, err)
+		return
+	}
+	if !athlete.CoachID.Valid {
+		return
+	}
+	notify.Send(h.DB, notify.Request{
+		UserID:    athlete.CoachID.Int64,
+		Type:      models.NotifyWorkoutLogged,
+		Title:     "Workout ready for review",
+		Message:   fmt.Sprintf("%s completed their workout on %s.", athlete.Name, workout.Date),
+		Link:      fmt.Sprintf("/athletes/%d/workouts/%d", athlete.ID, workout.ID),
+		AthleteID: sql.NullInt64{Int64: athlete.ID, Valid: true},
+	})
+}
+
+// notifyNewStalls tells the athlete's coach about lifts that have stalled
+// (see models.DetectStalls). Each stall is reported once.
+func (h *Workouts) notifyNewStalls(athleteID int64) {
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: get athlete %d for stall check: %v", athleteID, err)
+		return
+	}
+	if !athlete.CoachID.Valid {
+		return
+	}
+	stalls, err := models.DetectStalls(h.DB, athleteID)
+	if err != nil {
+		log.Printf("handlers: detect stalls for athlete %d: %v", athleteID, err)
+		return
+	}
+
+	for _, stall := range stalls {
+		isNew, err := models.RecordStallAlert(h.DB, athleteID, stall.ExerciseID, stall.Since)
+		if err != nil {
+			log.Printf("handlers: %v", err)
+			continue
+		}
+		if !isNew {
+			continue
+		}
+		notify.Send(h.DB, notify.Request{
+			UserID:    athlete.CoachID.Int64,
+			Type:      models.NotifyStallDetected,
+			Title:     fmt.Sprintf("%s's %s has stalled since %s", athlete.Name, stall.ExerciseName, stall.Since),
+			Message:   stall.Reason,
+			Link:      fmt.Sprintf("/athletes/%d", athleteID),
+			AthleteID: sql.NullInt64{Int64: athleteID, Valid: true},
+		})
+	}
+}
+
 // Delete removes a workout and all its sets. Coach only.
 func (h *Workouts) Delete(w http.ResponseWriter, r *http.Request) {
 	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
@@ -500,6 +842,7 @@ func (h *Workouts) AddSet(w http.ResponseWriter, r *http.Request) {
 			workoutRedirectWithError(w, r, athleteID, workoutID, "Invalid weight")
 			return
 		}
+		weight = weightInputToLbs(r, weight)
 	}
 
 	// Verify the workout belongs to the specified athlete.
@@ -556,6 +899,17 @@ func (h *Workouts) AddSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redirectURL := h.afterSetsLogged(r, athleteID, workoutID, exerciseID)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// afterSetsLogged runs the bookkeeping shared by every set-logging path:
+// PR detection, auto-approval for coach-logged sets and starting the rest
+// timer. It returns
+// the workout URL to redirect to, carrying the timer and sticky exercise.
+func (h *Workouts) afterSetsLogged(r *http.Request, athleteID, workoutID, exerciseID int64) string {
+	h.detectPRs(athleteID, workoutID)
+
 	// Auto-approve when a coach/admin logs sets for an athlete.
 	user := middleware.UserFromContext(r.Context())
 	if user.IsCoach || user.IsAdmin {
@@ -564,18 +918,48 @@ func (h *Workouts) AddSet(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Look up exercise rest time for the timer.
+	// Look up exercise rest time for the timer. A set logged from the
+	// prescription carries its prescribed rest, which takes precedence.
 	restSeconds := models.GetDefaultRestSeconds(h.DB)
+	exerciseName := ""
 	if ex, exErr := models.GetExerciseByID(h.DB, exerciseID); exErr == nil {
+		exerciseName = ex.Name
 		if ex.RestSeconds.Valid {
 			restSeconds = int(ex.RestSeconds.Int64)
 		}
 	}
+	if v, err := strconv.Atoi(r.FormValue("rest_seconds")); err == nil && v >= 0 {
+		restSeconds = v
+	}
+
+	// Persist the rest timer so it resumes after a reload. A new set always
+	// replaces any timer still running from the previous one.
+	if h.Sessions != nil {
+		if restSeconds > 0 {
+			models.SetActiveTimer(r.Context(), h.Sessions, &models.ActiveTimer{
+				WorkoutID:    workoutID,
+				ExerciseID:   exerciseID,
+				ExerciseName: exerciseName,
+				StartedAt:    time.Now(),
+				Seconds:      restSeconds,
+			})
+		} else {
+			models.ClearActiveTimer(r.Context(), h.Sessions)
+		}
+	}
 
 	// Include exercise_id in redirect for sticky exercise selection.
-	redirectURL := "/athletes/" + strconv.FormatInt(athleteID, 10) + "/workouts/" + strconv.FormatInt(workoutID, 10) +
+	return "/athletes/" + strconv.FormatInt(athleteID, 10) + "/workouts/" + strconv.FormatInt(workoutID, 10) +
 		"?timer=" + strconv.Itoa(restSeconds) + "&exercise_id=" + strconv.FormatInt(exerciseID, 10)
-	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// detectPRs refreshes the personal records for a workout after its sets
+// change. Failures are logged rather than surfaced since the set change
+// itself succeeded.
+func (h *Workouts) detectPRs(athleteID, workoutID int64) {
+	if _, err := models.DetectPRs(h.DB, athleteID, workoutID); err != nil {
+		log.Printf("handlers: detect PRs for workout %d: %v", workoutID, err)
+	}
 }
 
 // EditSetForm renders the edit set form.
@@ -690,6 +1074,7 @@ func (h *Workouts) UpdateSet(w http.ResponseWriter, r *http.Request) {
 			workoutRedirectWithError(w, r, athleteID, workoutID, "Invalid weight")
 			return
 		}
+		weight = weightInputToLbs(r, weight)
 	}
 
 	// Verify the workout belongs to the specified athlete.
@@ -745,6 +1130,7 @@ func (h *Workouts) UpdateSet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	h.detectPRs(athleteID, workoutID)
 
 	// Auto-approve when a coach/admin edits a set.
 	user := middleware.UserFromContext(r.Context())
@@ -818,10 +1204,399 @@ func (h *Workouts) DeleteSet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	h.detectPRs(athleteID, workoutID)
 
 	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
 }
 
+// DeleteExerciseGroup removes all sets for one exercise within a workout.
+func (h *Workouts) DeleteExerciseGroup(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	exerciseID, err := strconv.ParseInt(r.PathValue("exerciseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	// Verify the workout belongs to the specified athlete.
+	workoutCheck, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workoutCheck.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	_, err = models.DeleteSetsByWorkoutExercise(h.DB, workoutID, exerciseID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "No sets for this exercise", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: delete sets for workout %d exercise %d: %v", workoutID, exerciseID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.detectPRs(athleteID, workoutID)
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// ReorderExercises saves the display order of exercise groups within a
+// workout. The form posts exercise_id values in their new order.
+func (h *Workouts) ReorderExercises(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	// Verify the workout belongs to the specified athlete.
+	workoutCheck, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workoutCheck.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	var exerciseIDs []int64
+	for _, v := range r.Form["exercise_id"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+			return
+		}
+		exerciseIDs = append(exerciseIDs, id)
+	}
+
+	if err := models.ReorderWorkoutExercises(h.DB, workoutID, exerciseIDs); err != nil {
+		log.Printf("handlers: reorder exercises for workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10), http.StatusSeeOther)
+}
+
+// CopyFromPrevious clones the set structure of the athlete's most recent
+// prior workout into this one. When the workout already has sets, the source
+// must share at least one of its exercises; those exercises are not copied
+// again. RPE and notes are left blank for the athlete to fill in.
+func (h *Workouts) CopyFromPrevious(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	sets, err := models.ListSetsByWorkout(h.DB, workoutID)
+	if err != nil {
+		log.Printf("handlers: list sets for workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	var exerciseIDs []int64
+	for _, g := range sets {
+		exerciseIDs = append(exerciseIDs, g.ExerciseID)
+	}
+
+	prev, err := models.MostRecentWorkoutBefore(h.DB, athleteID, workout.Date, exerciseIDs)
+	if errors.Is(err, models.ErrNotFound) {
+		workoutRedirectWithError(w, r, athleteID, workoutID, "No previous workout to copy")
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: find previous workout for %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	copied, err := models.CopyWorkoutSets(h.DB, prev.ID, workoutID)
+	if err != nil {
+		log.Printf("handlers: copy sets from workout %d to %d: %v", prev.ID, workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if copied == 0 {
+		workoutRedirectWithError(w, r, athleteID, workoutID, "All exercises from the previous workout are already logged")
+		return
+	}
+	h.detectPRs(athleteID, workoutID)
+
+	msg := fmt.Sprintf("Copied %d sets from %s", copied, prev.Date)
+	http.Redirect(w, r, "/athletes/"+strconv.FormatInt(athleteID, 10)+"/workouts/"+strconv.FormatInt(workoutID, 10)+"?success="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+// WarmupSuggestion renders an htmx fragment listing suggested warm-up sets
+// for the weight query parameter, using the athlete's barbell weight.
+func (h *Workouts) WarmupSuggestion(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for warmups: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	barWeight := athlete.BarWeightOrDefault()
+	var warmups []models.WarmupSet
+	if weight, err := strconv.ParseFloat(r.URL.Query().Get("weight"), 64); err == nil {
+		warmups = models.SuggestWarmups(weightInputToLbs(r, weight), barWeight)
+	}
+
+	data := map[string]any{
+		"Warmups":   warmups,
+		"BarWeight": barWeight,
+		"Prefs":     middleware.PrefsFromContext(r.Context()),
+	}
+
+	ts, ok := h.Templates["_warmup_suggestion"]
+	if !ok {
+		log.Printf("handlers: warmup suggestion template not found in cache")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := ts.ExecuteTemplate(w, "warmup-suggestion", data); err != nil {
+		log.Printf("handlers: warmup suggestion template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// PlateMath renders an htmx fragment with the per-side plate breakdown for
+// the weight query parameter, using the athlete's bar and plate set.
+func (h *Workouts) PlateMath(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	athlete, err := models.GetAthleteByID(h.DB, athleteID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Athlete not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get athlete %d for plate math: %v", athleteID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	barWeight := athlete.BarWeightOrDefault()
+	data := map[string]any{
+		"BarWeight": barWeight,
+		"Prefs":     middleware.PrefsFromContext(r.Context()),
+	}
+	if weight, err := strconv.ParseFloat(r.URL.Query().Get("weight"), 64); err == nil && weight > 0 {
+		plates, remainder := models.PlateBreakdown(weightInputToLbs(r, weight), barWeight, athlete.AvailablePlates())
+		if remainder < 0 {
+			data["Error"] = "Weight is below the bar (" + models.FormatWeight(barWeight, preferredWeightUnit(r)) + ")"
+		} else {
+			data["Plates"] = plates
+			data["Remainder"] = remainder
+		}
+	}
+
+	ts, ok := h.Templates["_plate_math"]
+	if !ok {
+		log.Printf("handlers: plate math template not found in cache")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := ts.ExecuteTemplate(w, "plate-math", data); err != nil {
+		log.Printf("handlers: plate math template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// DismissTimer clears the session's active rest timer. Called via htmx when
+// the athlete dismisses the timer.
+func (h *Workouts) DismissTimer(w http.ResponseWriter, r *http.Request) {
+	if _, ok := checkAthleteAccess(h.DB, h.Templates, w, r); !ok {
+		return
+	}
+	if h.Sessions != nil {
+		models.ClearActiveTimer(r.Context(), h.Sessions)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// QuickLog adds sets from a single free-text line such as "squat 5x5 225".
+// htmx requests get an inline error fragment on failure and an HX-Redirect
+// on success; plain form posts fall back to the usual redirects.
+func (h *Workouts) QuickLog(w http.ResponseWriter, r *http.Request) {
+	athleteID, ok := checkAthleteAccess(h.DB, h.Templates, w, r)
+	if !ok {
+		return
+	}
+
+	workoutID, err := strconv.ParseInt(r.PathValue("workoutID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid workout ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := models.GetWorkoutByID(h.DB, workoutID)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("handlers: get workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if workout.AthleteID != athleteID {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	entry := strings.TrimSpace(r.FormValue("entry"))
+	qs, err := models.ParseQuickSet(entry)
+	if err != nil {
+		h.quickLogError(w, r, athleteID, workoutID,
+			`Couldn't read "`+entry+`". Try a format like "squat 5x5 225", "bench 3x8@185" or "plank 2x30s".`)
+		return
+	}
+	if qs.Sets > 20 {
+		h.quickLogError(w, r, athleteID, workoutID, "Cannot log more than 20 sets at once")
+		return
+	}
+
+	// Without a name, fall back to the exercise currently selected in the
+	// log-set form.
+	var exerciseID int64
+	if qs.ExerciseName == "" {
+		exerciseID, _ = strconv.ParseInt(r.FormValue("exercise_id"), 10, 64)
+		if exerciseID == 0 {
+			h.quickLogError(w, r, athleteID, workoutID, `Include an exercise name, e.g. "squat 5x5 225".`)
+			return
+		}
+	} else {
+		ex, err := models.ResolveExerciseName(h.DB, athleteID, qs.ExerciseName)
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			h.quickLogError(w, r, athleteID, workoutID,
+				`No exercise matches "`+qs.ExerciseName+`". Check the spelling or use the name from the exercise list.`)
+			return
+		case errors.Is(err, models.ErrAmbiguousExercise):
+			h.quickLogError(w, r, athleteID, workoutID,
+				`"`+qs.ExerciseName+`" matches more than one exercise. Use more of the name.`)
+			return
+		case err != nil:
+			log.Printf("handlers: resolve quick log exercise %q: %v", qs.ExerciseName, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		exerciseID = ex.ID
+	}
+
+	// An explicit unit suffix ("100kg") wins over the user's preference.
+	weight := weightInputToLbs(r, qs.Weight)
+	if qs.WeightUnit != "" {
+		weight = models.ConvertWeight(qs.Weight, qs.WeightUnit, "lbs")
+	}
+	if qs.Sets > 1 {
+		_, err = models.AddMultipleSets(h.DB, workoutID, exerciseID, qs.Sets, qs.Reps, weight, 0, qs.RepType, "", "")
+	} else {
+		_, err = models.AddSet(h.DB, workoutID, exerciseID, qs.Reps, weight, 0, qs.RepType, "", "")
+	}
+	if err != nil {
+		log.Printf("handlers: quick log set(s) to workout %d: %v", workoutID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	redirectURL := h.afterSetsLogged(r, athleteID, workoutID, exerciseID)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", redirectURL)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// quickLogError reports a quick-log failure. htmx requests get an error
+// fragment with a 200 status so htmx swaps it into the form's result slot;
+// plain form posts redirect back to the workout with the message.
+func (h *Workouts) quickLogError(w http.ResponseWriter, r *http.Request, athleteID, workoutID int64, msg string) {
+	if r.Header.Get("HX-Request") != "true" {
+		workoutRedirectWithError(w, r, athleteID, workoutID, msg)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.Templates.RenderErrorFragment(w, msg); err != nil {
+		log.Printf("handlers: quick log error template: %v", err)
+	}
+}
+
 // workoutRedirectWithError redirects back to the workout detail page with an
 // error message shown to the user. Used for form validation errors that should
 // surface inline instead of as plain-text HTTP error responses.
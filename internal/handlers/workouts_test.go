@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -84,6 +87,32 @@ func TestWorkouts_Create_Success(t *testing.T) {
 	}
 }
 
+func TestWorkouts_Create_WithBodyWeight(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	form := url.Values{"date": {"2026-02-10"}, "body_weight": {"142.4"}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts", form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	bw, err := models.LatestBodyWeight(db, athlete.ID)
+	if err != nil {
+		t.Fatalf("latest body weight: %v", err)
+	}
+	if bw.Weight != 142.4 {
+		t.Errorf("body weight = %v, want 142.4", bw.Weight)
+	}
+}
+
 func TestWorkouts_Create_DuplicateDate(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -134,6 +163,39 @@ func TestWorkouts_Show_Success(t *testing.T) {
 	}
 }
 
+func TestWorkouts_Show_ReviewTemplates(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+	if _, err := models.CreateReviewTemplate(db, coach.ID, "Depth", "Great bar speed, watch depth"); err != nil {
+		t.Fatalf("create review template: %v", err)
+	}
+
+	h := &Workouts{DB: db, Templates: tc}
+	show := func(user *models.User) string {
+		t.Helper()
+		req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID), nil, user)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		h.Show(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	if body := show(coach); !contains(body, "Great bar speed, watch depth") {
+		t.Error("expected the coach's review templates in the review form")
+	}
+	if body := show(kid); contains(body, "data-insert-into") {
+		t.Error("expected no review templates for the athlete")
+	}
+}
+
 func TestWorkouts_UpdateNotes(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -163,6 +225,193 @@ func TestWorkouts_UpdateNotes(t *testing.T) {
 	}
 }
 
+func TestWorkouts_SessionDuration(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	kid := seedNonCoach(t, db, athlete.ID)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+	post := func(path string, form url.Values, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+path, form, kid)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	if rr := post("/session/start", nil, h.StartSession); rr.Code != http.StatusSeeOther {
+		t.Fatalf("start: expected 303, got %d", rr.Code)
+	}
+	started, _ := models.GetWorkoutByID(db, workout.ID)
+	if !started.StartedAt.Valid {
+		t.Fatal("expected session timer running after start")
+	}
+
+	if rr := post("/session/stop", nil, h.StopSession); rr.Code != http.StatusSeeOther {
+		t.Fatalf("stop: expected 303, got %d", rr.Code)
+	}
+	stopped, _ := models.GetWorkoutByID(db, workout.ID)
+	if stopped.StartedAt.Valid || !stopped.DurationMinutes.Valid {
+		t.Fatalf("after stop: started_at = %v, duration = %v", stopped.StartedAt, stopped.DurationMinutes)
+	}
+
+	if rr := post("/duration", url.Values{"duration_minutes": {"55"}}, h.UpdateDuration); rr.Code != http.StatusSeeOther {
+		t.Fatalf("update duration: expected 303, got %d", rr.Code)
+	}
+	updated, _ := models.GetWorkoutByID(db, workout.ID)
+	if updated.DurationMinutes.Int64 != 55 {
+		t.Errorf("duration = %d, want 55", updated.DurationMinutes.Int64)
+	}
+
+	rr := post("/duration", url.Values{"duration_minutes": {"soon"}}, h.UpdateDuration)
+	if loc := rr.Header().Get("Location"); !strings.Contains(loc, "error=") {
+		t.Errorf("invalid duration: expected redirect with error, got %q", loc)
+	}
+
+	// The workout page shows the recorded duration.
+	req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID), nil, kid)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	show := httptest.NewRecorder()
+	h.Show(show, req)
+	if !strings.Contains(show.Body.String(), "55m") {
+		t.Error("expected workout page to show the 55m duration")
+	}
+}
+
+func TestWorkouts_MarkComplete_NotifiesCoach(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete, _ := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	kid := seedNonCoach(t, db, athlete.ID)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+	complete := func() {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/complete", nil, kid)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		h.MarkComplete(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+	}
+
+	// In-progress workouts stay out of the review queue.
+	if pending, _ := models.ListUnreviewedWorkouts(db); len(pending) != 0 {
+		t.Fatalf("pending before complete = %d, want 0", len(pending))
+	}
+
+	complete()
+	pending, _ := models.ListUnreviewedWorkouts(db)
+	if len(pending) != 1 || pending[0].WorkoutID != workout.ID {
+		t.Fatalf("pending after complete = %v, want the workout", pending)
+	}
+	notifications, _ := models.ListNotifications(db, coach.ID, 10, 0)
+	if len(notifications) != 1 || notifications[0].Type != models.NotifyWorkoutLogged {
+		t.Fatalf("coach notifications = %v, want one workout logged", notifications)
+	}
+
+	// Completing again doesn't notify twice.
+	complete()
+	if n, _ := models.GetUnreadCount(db, coach.ID); n != 1 {
+		t.Errorf("coach unread = %d after second complete, want 1", n)
+	}
+}
+
+func TestWorkouts_MarkComplete_NotifiesStall(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete, _ := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	kid := seedNonCoach(t, db, athlete.ID)
+
+	// Squat: flat training max and AMRAP reps below the progression rule.
+	squat, _ := models.CreateExercise(db, "Squat", "", "", "", "", 0)
+	pct85 := 85.0
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Stall Program", "", 1, 1, true, "", 0, "")
+	models.CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, nil, nil, &pct85, nil, nil, nil, 0, "", "")
+	models.SetProgressionRule(db, tmpl.ID, squat.ID, 10, models.ConditionAMRAPMinReps, 5)
+	ap, _ := models.AssignProgram(db, athlete.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
+	for _, d := range []string{"2026-01-01", "2026-02-01", "2026-03-01"} {
+		models.SetTrainingMax(db, athlete.ID, squat.ID, 300, d, "")
+	}
+	w1, _ := models.CreateWorkout(db, athlete.ID, "2026-01-15", "", ap.ID)
+	models.AddSet(db, w1.ID, squat.ID, 4, 255, 0, "reps", "", "")
+	w2, _ := models.CreateWorkout(db, athlete.ID, "2026-02-15", "", ap.ID)
+	models.AddSet(db, w2.ID, squat.ID, 3, 255, 0, "reps", "", "")
+
+	h := &Workouts{DB: db, Templates: tc}
+	complete := func(workout *models.Workout) {
+		t.Helper()
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/complete", nil, kid)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		rr := httptest.NewRecorder()
+		h.MarkComplete(rr, req)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+	}
+	countStalls := func() int {
+		t.Helper()
+		var stalls int
+		notifications, _ := models.ListNotifications(db, coach.ID, 10, 0)
+		for _, n := range notifications {
+			if n.Type == models.NotifyStallDetected {
+				stalls++
+				if !contains(n.Title, "Squat") {
+					t.Errorf("title = %q, want the exercise", n.Title)
+				}
+			}
+		}
+		return stalls
+	}
+
+	complete(w1)
+	if n := countStalls(); n != 1 {
+		t.Errorf("stall notifications = %d, want 1", n)
+	}
+
+	// The stall is reported once even though both completions detect it,
+	// and clearing the in-app notification doesn't bring it back.
+	db.Exec(`DELETE FROM notifications WHERE user_id = ?`, coach.ID)
+	complete(w2)
+	if n := countStalls(); n != 0 {
+		t.Errorf("stall notifications after clearing = %d, want 0", n)
+	}
+}
+
+func TestWorkouts_MarkComplete_OtherAthleteForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	kid := seedNonCoach(t, db, bob.ID)
+	workout, _ := models.CreateWorkout(db, alice.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+	req := requestWithUser("POST", "/athletes/"+itoa(alice.ID)+"/workouts/"+itoa(workout.ID)+"/complete", nil, kid)
+	req.SetPathValue("id", itoa(alice.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	rr := httptest.NewRecorder()
+	h.MarkComplete(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+	if got, _ := models.GetWorkoutByID(db, workout.ID); got.CompletedAt.Valid {
+		t.Error("workout should not be marked complete")
+	}
+}
+
 func TestWorkouts_Delete_CoachOnly(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -263,6 +512,53 @@ func TestWorkouts_AddSet_Success(t *testing.T) {
 	}
 }
 
+func TestWorkouts_AddSet_DetectsPR(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	athlete := seedAthlete(t, db, "Alice", "")
+	ex := seedExercise(t, db, "Squat", "")
+	prev, _ := models.CreateWorkout(db, athlete.ID, "2026-02-03", "", 0)
+	models.AddSet(db, prev.ID, ex.ID, 5, 200, 0, "", "", "")
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	form := url.Values{
+		"exercise_id": {itoa(ex.ID)},
+		"reps":        {"5"},
+		"weight":      {"210"},
+	}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/sets", form, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	rr := httptest.NewRecorder()
+	h.AddSet(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+
+	ids, err := models.ListWorkoutPRSetIDs(db, workout.ID)
+	if err != nil {
+		t.Fatalf("list PR set ids: %v", err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("expected 1 PR set, got %d", len(ids))
+	}
+
+	req = requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID), nil, coach)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	rr = httptest.NewRecorder()
+	h.Show(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "PR!") {
+		t.Error("expected PR badge on the workout page")
+	}
+}
+
 func TestWorkouts_AddSet_InvalidReps(t *testing.T) {
 	db := testDB(t)
 	tc := testTemplateCache(t)
@@ -450,6 +746,443 @@ func TestWorkouts_DeleteSet_Success(t *testing.T) {
 	}
 }
 
+func TestWorkouts_DeleteExerciseGroup(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	ex := seedExercise(t, db, "Squat", "")
+	other := seedExercise(t, db, "Bench Press", "")
+	workout, _ := models.CreateWorkout(db, alice.ID, "2026-02-10", "", 0)
+	models.AddMultipleSets(db, workout.ID, ex.ID, 3, 5, 225, 0, "", "", "")
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	tests := []struct {
+		name       string
+		athleteID  int64
+		exerciseID int64
+		wantCode   int
+	}{
+		{"wrong athlete", bob.ID, ex.ID, http.StatusNotFound},
+		{"no sets for exercise", alice.ID, other.ID, http.StatusNotFound},
+		{"success", alice.ID, ex.ID, http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("POST", "/athletes/"+itoa(tt.athleteID)+"/workouts/"+itoa(workout.ID)+"/exercises/"+itoa(tt.exerciseID)+"/delete", nil, coach)
+			req.SetPathValue("id", itoa(tt.athleteID))
+			req.SetPathValue("workoutID", itoa(workout.ID))
+			req.SetPathValue("exerciseID", itoa(tt.exerciseID))
+			rr := httptest.NewRecorder()
+			h.DeleteExerciseGroup(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+		})
+	}
+
+	groups, _ := models.ListSetsByWorkout(db, workout.ID)
+	if len(groups) != 0 {
+		t.Errorf("groups = %d, want 0 after delete", len(groups))
+	}
+}
+
+func TestWorkouts_ReorderExercises(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Squat", "")
+	bench := seedExercise(t, db, "Bench Press", "")
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+	models.AddSet(db, workout.ID, squat.ID, 5, 225, 0, "", "", "")
+	models.AddSet(db, workout.ID, bench.ID, 5, 135, 0, "", "", "")
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	form := url.Values{"exercise_id": {itoa(bench.ID), itoa(squat.ID)}}
+	req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/exercises/reorder", form, owner)
+	req.SetPathValue("id", itoa(athlete.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	rr := httptest.NewRecorder()
+	h.ReorderExercises(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	groups, _ := models.ListSetsByWorkout(db, workout.ID)
+	if len(groups) != 2 || groups[0].ExerciseID != bench.ID {
+		t.Errorf("first group = %v, want Bench Press", groups[0].ExerciseName)
+	}
+}
+
+func TestWorkouts_ReorderExercises_OtherAthleteForbidden(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	kid := seedNonCoach(t, db, bob.ID)
+	workout, _ := models.CreateWorkout(db, alice.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	req := requestWithUser("POST", "/athletes/"+itoa(alice.ID)+"/workouts/"+itoa(workout.ID)+"/exercises/reorder", url.Values{}, kid)
+	req.SetPathValue("id", itoa(alice.ID))
+	req.SetPathValue("workoutID", itoa(workout.ID))
+	rr := httptest.NewRecorder()
+	h.ReorderExercises(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestWorkouts_ShowJSON(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	coach := seedCoach(t, db)
+	alice := seedAthlete(t, db, "Alice", "")
+	bob := seedAthlete(t, db, "Bob", "")
+	squat := seedExercise(t, db, "Squat", "")
+	workout, _ := models.CreateWorkout(db, alice.ID, "2026-02-10", "Leg day", 0)
+	models.AddMultipleSets(db, workout.ID, squat.ID, 2, 5, 225, 0, "", "", "")
+	models.CreateWorkoutReview(db, workout.ID, coach.ID, "approved", "")
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	tests := []struct {
+		name      string
+		athleteID int64
+		wantCode  int
+	}{
+		{"wrong athlete", bob.ID, http.StatusNotFound},
+		{"success", alice.ID, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("GET", "/athletes/"+itoa(tt.athleteID)+"/workouts/"+itoa(workout.ID)+".json", nil, coach)
+			req.SetPathValue("id", itoa(tt.athleteID))
+			req.SetPathValue("workoutID", itoa(workout.ID))
+			rr := httptest.NewRecorder()
+			h.ShowJSON(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("content type = %q", ct)
+			}
+			var got models.ExportWorkout
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !strings.HasPrefix(got.Date, "2026-02-10") || len(got.Sets) != 2 || got.Sets[0].Exercise != "Squat" {
+				t.Errorf("workout = %+v", got)
+			}
+			if got.Review == nil || got.Review.Status != "approved" {
+				t.Errorf("review = %+v, want approved", got.Review)
+			}
+		})
+	}
+}
+
+func TestWorkouts_WarmupSuggestion(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	models.SetAthleteBarWeight(db, athlete.ID, 35)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	tests := []struct {
+		name     string
+		query    string
+		contains []string
+		excludes []string
+	}{
+		{"ramp from athlete bar", "?weight=135", []string{"35 × 10", "55 × 5", "80 × 3", "110 × 2"}, []string{"135 ×"}},
+		{"blank weight", "?weight=", nil, []string{"×"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/warmups"+tt.query, nil, owner)
+			req.SetPathValue("id", itoa(athlete.ID))
+			rr := httptest.NewRecorder()
+			h.WarmupSuggestion(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			body := rr.Body.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(body, want) {
+					t.Errorf("body missing %q", want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("body unexpectedly contains %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkouts_PlateMath(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	tests := []struct {
+		name     string
+		query    string
+		contains string
+	}{
+		{"breakdown", "?weight=275", "2×45, 1×25"},
+		{"remainder", "?weight=232", "2 lbs can't be loaded"},
+		{"below bar", "?weight=35", "below the bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := requestWithUser("GET", "/athletes/"+itoa(athlete.ID)+"/plates"+tt.query, nil, owner)
+			req.SetPathValue("id", itoa(athlete.ID))
+			rr := httptest.NewRecorder()
+			h.PlateMath(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			if body := rr.Body.String(); !strings.Contains(body, tt.contains) {
+				t.Errorf("body missing %q: %s", tt.contains, body)
+			}
+		})
+	}
+}
+
+func TestWorkouts_RestTimerPersists(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Squat", "")
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc, Sessions: sm}
+	base := "/athletes/" + itoa(athlete.ID) + "/workouts/" + itoa(workout.ID)
+
+	var cookies []*http.Cookie
+	serve := func(handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(handler).ServeHTTP(rr, req)
+		if c := rr.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return rr
+	}
+
+	form := url.Values{"exercise_id": {itoa(squat.ID)}, "reps": {"5"}, "weight": {"225"}}
+	if rr := serve(h.AddSet, requestWithUser("POST", base+"/sets", form, owner)); rr.Code != http.StatusSeeOther {
+		t.Fatalf("add set: expected 303, got %d", rr.Code)
+	}
+
+	rr := serve(h.Show, requestWithUser("GET", base, nil, owner))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("show: expected 200, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "Resting from Squat") || !strings.Contains(body, "data-timer-remaining") {
+		t.Errorf("expected resumed rest timer in body")
+	}
+
+	if rr := serve(h.DismissTimer, requestWithUser("POST", base+"/timer/dismiss", url.Values{}, owner)); rr.Code != http.StatusNoContent {
+		t.Fatalf("dismiss: expected 204, got %d", rr.Code)
+	}
+	rr = serve(h.Show, requestWithUser("GET", base, nil, owner))
+	if strings.Contains(rr.Body.String(), "Resting from") {
+		t.Errorf("expected no rest timer after dismiss")
+	}
+}
+
+func TestWorkouts_CopyFromPrevious(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Squat", "")
+	bench := seedExercise(t, db, "Bench Press", "")
+	prev, _ := models.CreateWorkout(db, athlete.ID, "2026-02-08", "", 0)
+	models.AddMultipleSets(db, prev.ID, squat.ID, 3, 5, 225, 8, "", "", "heavy")
+	models.AddSet(db, prev.ID, bench.ID, 8, 135, 0, "", "", "")
+	first, _ := models.CreateWorkout(db, athlete.ID, "2026-02-01", "", 0)
+	current, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	post := func(workoutID int64) *httptest.ResponseRecorder {
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workoutID)+"/copy-previous", url.Values{}, owner)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workoutID))
+		rr := httptest.NewRecorder()
+		h.CopyFromPrevious(rr, req)
+		return rr
+	}
+
+	t.Run("no previous workout", func(t *testing.T) {
+		rr := post(first.ID)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "error=") {
+			t.Errorf("location = %q, want error message", loc)
+		}
+	})
+
+	t.Run("copies set structure", func(t *testing.T) {
+		rr := post(current.ID)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "success=Copied+4+sets") {
+			t.Errorf("location = %q, want success with 4 sets", loc)
+		}
+		groups, _ := models.ListSetsByWorkout(db, current.ID)
+		if len(groups) != 2 || len(groups[0].Sets) != 3 {
+			t.Fatalf("groups = %d, want squat x3 and bench x1", len(groups))
+		}
+		s := groups[0].Sets[0]
+		if s.Reps != 5 || s.Weight.Float64 != 225 || s.RPE.Valid || s.Notes.Valid {
+			t.Errorf("copied set = %+v, want reps/weight only", s)
+		}
+	})
+
+	t.Run("skips exercises already logged", func(t *testing.T) {
+		rr := post(current.ID)
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "error=") {
+			t.Errorf("location = %q, want error when nothing to copy", loc)
+		}
+	})
+}
+
+func TestWorkouts_QuickLog(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Back Squat", "")
+	seedExercise(t, db, "Front Squat", "")
+	plank := seedExercise(t, db, "Plank", "")
+	models.AssignExercise(db, athlete.ID, squat.ID, 0)
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc}
+
+	post := func(form url.Values, htmx bool) *httptest.ResponseRecorder {
+		req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/quick", form, owner)
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rr := httptest.NewRecorder()
+		h.QuickLog(rr, req)
+		return rr
+	}
+
+	t.Run("prefers assigned exercise", func(t *testing.T) {
+		rr := post(url.Values{"entry": {"squat 5x5 225"}}, false)
+		if rr.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "exercise_id="+itoa(squat.ID)) {
+			t.Errorf("location = %q, want back squat selected", loc)
+		}
+		groups, _ := models.ListSetsByWorkout(db, workout.ID)
+		if len(groups) != 1 || len(groups[0].Sets) != 5 || groups[0].Sets[0].Weight.Float64 != 225 {
+			t.Fatalf("expected 5 sets of back squat at 225, got %+v", groups)
+		}
+	})
+
+	t.Run("kg weights converted", func(t *testing.T) {
+		for _, tt := range []struct {
+			entry, unit, wantLbs string
+		}{
+			{"front squat 1x5 100", "kg", "220.46"},
+			{"front squat 1x5 100kg", "lbs", "220.46"},
+			{"front squat 1x5 225lbs", "kg", "225.00"},
+		} {
+			req := requestWithUser("POST", "/athletes/"+itoa(athlete.ID)+"/workouts/"+itoa(workout.ID)+"/quick", url.Values{"entry": {tt.entry}}, owner)
+			req = withWeightUnit(req, tt.unit)
+			req.SetPathValue("id", itoa(athlete.ID))
+			req.SetPathValue("workoutID", itoa(workout.ID))
+			rr := httptest.NewRecorder()
+			h.QuickLog(rr, req)
+			if rr.Code != http.StatusSeeOther {
+				t.Fatalf("%q: expected 303, got %d", tt.entry, rr.Code)
+			}
+
+			var stored float64
+			groups, _ := models.ListSetsByWorkout(db, workout.ID)
+			for _, g := range groups {
+				if g.ExerciseName == "Front Squat" {
+					stored = g.Sets[len(g.Sets)-1].Weight.Float64
+				}
+			}
+			if got := fmt.Sprintf("%.2f", stored); got != tt.wantLbs {
+				t.Errorf("%q as %s: stored %s lbs, want %s", tt.entry, tt.unit, got, tt.wantLbs)
+			}
+		}
+	})
+
+	t.Run("htmx success redirects", func(t *testing.T) {
+		rr := post(url.Values{"entry": {"plank 2x30s"}}, true)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if loc := rr.Header().Get("HX-Redirect"); !strings.Contains(loc, "exercise_id="+itoa(plank.ID)) {
+			t.Errorf("HX-Redirect = %q, want plank selected", loc)
+		}
+	})
+
+	t.Run("unknown exercise returns fragment", func(t *testing.T) {
+		rr := post(url.Values{"entry": {"curl 3x10 30"}}, true)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if body := rr.Body.String(); !strings.Contains(body, "alert-error") || !strings.Contains(body, "curl") {
+			t.Errorf("body = %q, want error fragment naming the exercise", body)
+		}
+	})
+
+	t.Run("unparseable entry", func(t *testing.T) {
+		rr := post(url.Values{"entry": {"squat heavy"}}, false)
+		if loc := rr.Header().Get("Location"); !strings.Contains(loc, "error=") {
+			t.Errorf("location = %q, want error redirect", loc)
+		}
+	})
+
+	t.Run("no name uses selected exercise", func(t *testing.T) {
+		rr := post(url.Values{"entry": {"3x8@185"}, "exercise_id": {itoa(squat.ID)}}, false)
+		if rr.Code != http.StatusSeeOther || strings.Contains(rr.Header().Get("Location"), "error=") {
+			t.Fatalf("expected success redirect, got %d %q", rr.Code, rr.Header().Get("Location"))
+		}
+	})
+}
+
 // Tests for workout-to-athlete ownership verification.
 // These ensure that accessing a workout via a different athlete's URL returns 404.
 
@@ -815,13 +1548,13 @@ func TestWorkouts_Show_WithPrescription(t *testing.T) {
 	ex := seedExercise(t, db, "Bench Press", "")
 
 	// Set up a program template with a prescribed set.
-	tmpl, err := models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "")
+	tmpl, err := models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create program template: %v", err)
 	}
 	reps := 5
 	pct := 75.0
-	_, err = models.CreatePrescribedSet(db, tmpl.ID, ex.ID, 1, 1, 1, &reps, &pct, nil, 0, "", "")
+	_, err = models.CreatePrescribedSet(db, tmpl.ID, ex.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "")
 	if err != nil {
 		t.Fatalf("create prescribed set: %v", err)
 	}
@@ -957,3 +1690,41 @@ func TestWorkouts_AddSet_CoachAutoApproves(t *testing.T) {
 		t.Errorf("review status = %q, want %q", rev.Status, models.ReviewStatusApproved)
 	}
 }
+
+func TestWorkouts_AddSet_PrescribedRestStartsTimer(t *testing.T) {
+	db := testDB(t)
+	tc := testTemplateCache(t)
+	sm := testSessionManager()
+	athlete := seedAthlete(t, db, "Alice", "")
+	owner := seedNonCoach(t, db, athlete.ID)
+	squat := seedExercise(t, db, "Squat", "")
+	workout, _ := models.CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
+
+	h := &Workouts{DB: db, Templates: tc, Sessions: sm}
+	base := "/athletes/" + itoa(athlete.ID) + "/workouts/" + itoa(workout.ID)
+
+	var cookies []*http.Cookie
+	serve := func(handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+		req.SetPathValue("id", itoa(athlete.ID))
+		req.SetPathValue("workoutID", itoa(workout.ID))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		sm.LoadAndSave(handler).ServeHTTP(rr, req)
+		if c := rr.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return rr
+	}
+
+	form := url.Values{"exercise_id": {itoa(squat.ID)}, "reps": {"3"}, "weight": {"315"}, "rest_seconds": {"240"}}
+	if rr := serve(h.AddSet, requestWithUser("POST", base+"/sets", form, owner)); rr.Code != http.StatusSeeOther {
+		t.Fatalf("add set: expected 303, got %d", rr.Code)
+	}
+
+	rr := serve(h.Show, requestWithUser("GET", base, nil, owner))
+	if body := rr.Body.String(); !strings.Contains(body, `data-timer-total="240"`) {
+		t.Errorf("expected rest timer to use the prescribed 240s rest")
+	}
+}
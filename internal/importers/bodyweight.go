@@ -0,0 +1,99 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseBodyWeightCSV parses weigh-ins from a smart scale CSV export. The file
+// needs a date column and a weight column; header names are matched loosely
+// ("Date", "Time", "Weight", "Weight (lb)", "weight_kg", ...) and any other
+// columns (body fat, BMI, ...) are ignored. When the weight header names a
+// unit, it is returned in ParsedFile.WeightUnit. Rows that repeat a date keep
+// the first weigh-in for that day.
+func ParseBodyWeightCSV(r io.Reader) (*ParsedFile, error) {
+	cr := csv.NewReader(r)
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("importers: read body weight csv: %w", err)
+	}
+
+	if len(records) < 2 {
+		return nil, fmt.Errorf("importers: body weight csv has no data rows")
+	}
+
+	dateCol, weightCol := -1, -1
+	pf := &ParsedFile{Format: FormatBodyWeightCSV}
+	for i, col := range records[0] {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		switch {
+		case dateCol < 0 && (strings.Contains(name, "date") || strings.Contains(name, "time")):
+			dateCol = i
+		case weightCol < 0 && strings.HasPrefix(name, "weight"):
+			weightCol = i
+			pf.WeightUnit = bodyWeightHeaderUnit(name)
+		}
+	}
+	if dateCol < 0 {
+		return nil, fmt.Errorf("importers: body weight csv missing a date column")
+	}
+	if weightCol < 0 {
+		return nil, fmt.Errorf("importers: body weight csv missing a weight column")
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range records[1:] {
+		if dateCol >= len(row) || weightCol >= len(row) {
+			continue
+		}
+		dateStr := strings.TrimSpace(row[dateCol])
+		if dateStr == "" {
+			continue
+		}
+		date := parseStrongDate(dateStr)
+
+		// Scales sometimes append the unit to the value ("185.2 lb").
+		weightStr := strings.TrimSpace(strings.TrimRightFunc(strings.TrimSpace(row[weightCol]), unicode.IsLetter))
+		weight, err := strconv.ParseFloat(weightStr, 64)
+		if err != nil || weight <= 0 {
+			continue
+		}
+
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+		pf.BodyWeights = append(pf.BodyWeights, ParsedBodyWeight{Date: date, Weight: weight})
+	}
+
+	return pf, nil
+}
+
+// isBodyWeightHeader reports whether a CSV header line looks like a scale
+// export: a date column and a weight column, with no exercise columns.
+func isBodyWeightHeader(line string) bool {
+	lower := strings.ToLower(line)
+	if strings.Contains(lower, "exercise") || strings.Contains(lower, "reps") {
+		return false
+	}
+	return containsAll(lower, "weight") && (containsAll(lower, "date") || containsAll(lower, "time"))
+}
+
+// bodyWeightHeaderUnit extracts the unit from a weight column header such as
+// "weight (kg)" or "weight_lbs". Returns empty string if none is named.
+func bodyWeightHeaderUnit(header string) string {
+	switch {
+	case strings.Contains(header, "kg"):
+		return "kg"
+	case strings.Contains(header, "lb"):
+		return "lbs"
+	}
+	return ""
+}
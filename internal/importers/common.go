@@ -6,10 +6,11 @@ package importers
 type Format string
 
 const (
-	FormatRepLogJSON   Format = "replog_json"
-	FormatCatalogJSON  Format = "catalog_json"
-	FormatStrongCSV    Format = "strong_csv"
-	FormatHevyCSV      Format = "hevy_csv"
+	FormatRepLogJSON    Format = "replog_json"
+	FormatCatalogJSON   Format = "catalog_json"
+	FormatStrongCSV     Format = "strong_csv"
+	FormatHevyCSV       Format = "hevy_csv"
+	FormatBodyWeightCSV Format = "body_weight_csv"
 )
 
 // ParsedFile is the unified output from any parser. It contains all entities
@@ -17,7 +18,7 @@ const (
 type ParsedFile struct {
 	Format Format
 
-	// Common fields (all formats).
+	// Common fields (all formats). Body weight CSV only populates BodyWeights.
 	Exercises     []ParsedExercise
 	Workouts      []ParsedWorkout
 	BodyWeights   []ParsedBodyWeight
@@ -30,6 +31,7 @@ type ParsedFile struct {
 	AthleteEquipment []string // equipment names the athlete has
 	Assignments      []ParsedAssignment
 	Programs         []ParsedProgram
+	AccessoryPlans   []ParsedAccessoryPlan
 }
 
 // ParsedAthlete is an athlete profile from a RepLog JSON export.
@@ -46,18 +48,22 @@ type ParsedAthlete struct {
 
 // ParsedEquipment is a piece of equipment from a RepLog JSON export.
 type ParsedEquipment struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Quantity    *int     `json:"quantity"`
+	UnitWeight  *float64 `json:"unit_weight"`
 }
 
 // ParsedExercise is an exercise from a RepLog JSON export.
 type ParsedExercise struct {
 	Name        string                    `json:"name"`
 	Tier        *string                   `json:"tier"`
+	MuscleGroup *string                   `json:"muscle_group"`
 	FormNotes   *string                   `json:"form_notes"`
 	DemoURL     *string                   `json:"demo_url"`
 	RestSeconds *int                      `json:"rest_seconds"`
 	Featured    bool                      `json:"featured"`
+	Unilateral  bool                      `json:"unilateral"`
 	Equipment   []ParsedExerciseEquipment `json:"equipment"`
 }
 
@@ -84,19 +90,34 @@ type ParsedTrainingMax struct {
 	Notes         *string `json:"notes"`
 }
 
-// ParsedBodyWeight is a body weight entry (RepLog JSON).
+// ParsedBodyWeight is a body weight entry (RepLog JSON, body weight CSV).
 type ParsedBodyWeight struct {
 	Date   string  `json:"date"`
 	Weight float64 `json:"weight"`
 	Notes  *string `json:"notes"`
 }
 
+// ParsedAccessoryPlan is a planned accessory exercise from a RepLog JSON export.
+type ParsedAccessoryPlan struct {
+	Day          int      `json:"day"`
+	Exercise     string   `json:"exercise"`
+	TargetSets   *int     `json:"target_sets"`
+	TargetRepMin *int     `json:"target_rep_min"`
+	TargetRepMax *int     `json:"target_rep_max"`
+	TargetWeight *float64 `json:"target_weight"`
+	Notes        *string  `json:"notes"`
+	SortOrder    int      `json:"sort_order"`
+	Superset     bool     `json:"superset"`
+	Active       *bool    `json:"active"`
+}
+
 // ParsedWorkout is a workout with its sets.
 type ParsedWorkout struct {
-	Date   string             `json:"date"`
-	Notes  *string            `json:"notes"`
-	Review *ParsedReview      `json:"review"`
-	Sets   []ParsedWorkoutSet `json:"sets"`
+	Date            string             `json:"date"`
+	Notes           *string            `json:"notes"`
+	DurationMinutes *int               `json:"duration_minutes"`
+	Review          *ParsedReview      `json:"review"`
+	Sets            []ParsedWorkoutSet `json:"sets"`
 }
 
 // ParsedReview is a workout review from a RepLog JSON export.
@@ -146,10 +167,13 @@ type ParsedPrescribedSet struct {
 	Week           int      `json:"week"`
 	Day            int      `json:"day"`
 	SetNumber      int      `json:"set_number"`
-	Reps           *int     `json:"reps"` // nil = AMRAP
+	Reps           *int     `json:"reps"`              // nil = AMRAP
+	RepMax         *int     `json:"rep_max,omitempty"` // upper bound of a rep range
 	RepType        string   `json:"rep_type"`
 	Percentage     *float64 `json:"percentage"`
 	AbsoluteWeight *float64 `json:"absolute_weight"`
+	TargetRPE      *float64 `json:"target_rpe,omitempty"`
+	RestSeconds    *int     `json:"rest_seconds,omitempty"` // nil = exercise's rest time
 	SortOrder      int      `json:"sort_order"`
 	Notes          *string  `json:"notes"`
 }
@@ -158,11 +182,13 @@ type ParsedPrescribedSet struct {
 type ParsedProgressionRule struct {
 	Exercise  string  `json:"exercise"`
 	Increment float64 `json:"increment"`
+	Condition *string `json:"condition,omitempty"` // e.g. "amrap_min_reps"; nil = unconditional
+	Threshold *int    `json:"threshold,omitempty"`
 }
 
 // DetectFormat guesses the import format from file content.
 // It returns FormatRepLogJSON for JSON, and attempts to identify
-// Strong vs Hevy CSV from headers. Returns empty string if unknown.
+// Strong vs Hevy vs body weight CSV from headers. Returns empty string if unknown.
 func DetectFormat(data []byte) Format {
 	trimmed := data
 
@@ -190,6 +216,9 @@ func DetectFormat(data []byte) Format {
 	if containsAll(firstLine, "exercise_title", "set_index", "weight_lbs", "reps") {
 		return FormatHevyCSV
 	}
+	if isBodyWeightHeader(firstLine) {
+		return FormatBodyWeightCSV
+	}
 
 	return ""
 }
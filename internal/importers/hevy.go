@@ -58,6 +58,7 @@ func ParseHevyCSV(r io.Reader) (*ParsedFile, error) {
 	workoutMap := make(map[string]*ParsedWorkout)
 	workoutOrder := []string{}
 	workoutNotesMap := make(map[string]string)
+	workoutTitleMap := make(map[string]string)
 
 	for _, row := range records[1:] {
 		startTime := colVal(row, idx, hevyColStartTime)
@@ -94,6 +95,13 @@ func ParseHevyCSV(r io.Reader) (*ParsedFile, error) {
 			}
 		}
 
+		// Routine title, used as notes when the workout has no description.
+		if title := colVal(row, idx, hevyColTitle); title != "" {
+			if _, ok := workoutTitleMap[date]; !ok {
+				workoutTitleMap[date] = title
+			}
+		}
+
 		// Parse set data.
 		set := ParsedWorkoutSet{
 			Exercise: exerciseName,
@@ -158,7 +166,13 @@ func ParseHevyCSV(r io.Reader) (*ParsedFile, error) {
 
 	// Preserve workout order.
 	for _, date := range workoutOrder {
-		pf.Workouts = append(pf.Workouts, *workoutMap[date])
+		pw := workoutMap[date]
+		if pw.Notes == nil {
+			if title, ok := workoutTitleMap[date]; ok {
+				pw.Notes = &title
+			}
+		}
+		pf.Workouts = append(pf.Workouts, *pw)
 	}
 
 	return pf, nil
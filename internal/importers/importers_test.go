@@ -1,6 +1,7 @@
 package importers
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -39,6 +40,18 @@ func TestDetectFormat_HevyCSV(t *testing.T) {
 	}
 }
 
+func TestDetectFormat_BodyWeightCSV(t *testing.T) {
+	for _, header := range []string{
+		"Date,Weight\n",
+		"Date,Weight (lb),Fat mass (lb),Bone mass (lb),Muscle mass (lb),Hydration (lb),Comments\n",
+		"time,weight_kg,bmi\n",
+	} {
+		if got := DetectFormat([]byte(header)); got != FormatBodyWeightCSV {
+			t.Errorf("DetectFormat(%q) = %q, want %q", header, got, FormatBodyWeightCSV)
+		}
+	}
+}
+
 func TestDetectFormat_Unknown(t *testing.T) {
 	data := []byte("some random text\n")
 	got := DetectFormat(data)
@@ -47,6 +60,53 @@ func TestDetectFormat_Unknown(t *testing.T) {
 	}
 }
 
+// --- Body weight CSV parser tests ---
+
+func TestParseBodyWeightCSV(t *testing.T) {
+	csv := `Date,Weight (kg),Body Fat %
+2026-02-01 07:12:00,84.2,18.1
+2026-02-01 21:40:00,85.0,18.3
+2026-02-03,83.9 kg,
+2026-02-04,,
+"Feb 5, 2026",83.5,17.9
+`
+	pf, err := ParseBodyWeightCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseBodyWeightCSV: %v", err)
+	}
+	if pf.Format != FormatBodyWeightCSV {
+		t.Errorf("format = %q, want %q", pf.Format, FormatBodyWeightCSV)
+	}
+	if pf.WeightUnit != "kg" {
+		t.Errorf("weight unit = %q, want kg", pf.WeightUnit)
+	}
+	if len(pf.Workouts) != 0 || len(pf.Exercises) != 0 {
+		t.Errorf("expected no workouts or exercises, got %d/%d", len(pf.Workouts), len(pf.Exercises))
+	}
+
+	want := []ParsedBodyWeight{
+		{Date: "2026-02-01", Weight: 84.2}, // first weigh-in of the day wins
+		{Date: "2026-02-03", Weight: 83.9},
+		{Date: "2026-02-05", Weight: 83.5},
+	}
+	if len(pf.BodyWeights) != len(want) {
+		t.Fatalf("body weights = %d, want %d", len(pf.BodyWeights), len(want))
+	}
+	for i, w := range want {
+		got := pf.BodyWeights[i]
+		if got.Date != w.Date || got.Weight != w.Weight {
+			t.Errorf("body weight %d = %s %.1f, want %s %.1f", i, got.Date, got.Weight, w.Date, w.Weight)
+		}
+	}
+}
+
+func TestParseBodyWeightCSV_MissingColumn(t *testing.T) {
+	_, err := ParseBodyWeightCSV(strings.NewReader("Date,BMI\n2026-02-01,24.1\n"))
+	if err == nil {
+		t.Error("expected error for missing weight column")
+	}
+}
+
 // --- Strong CSV parser tests ---
 
 func TestParseStrongCSV_Basic(t *testing.T) {
@@ -94,6 +154,63 @@ func TestParseStrongCSV_Basic(t *testing.T) {
 	if sqSet.RPE == nil || *sqSet.RPE != 8 {
 		t.Errorf("squat RPE = %v, want 8", sqSet.RPE)
 	}
+	if wo.DurationMinutes == nil || *wo.DurationMinutes != 30 {
+		t.Errorf("duration = %v, want 30", wo.DurationMinutes)
+	}
+}
+
+func TestParseStrongDuration(t *testing.T) {
+	for in, want := range map[string]int{"30m": 30, "1h 5m": 65, "2h": 120, " 1h 5m 40s ": 66} {
+		if got := parseStrongDuration(in); got == nil || *got != want {
+			t.Errorf("parseStrongDuration(%q) = %v, want %d", in, got, want)
+		}
+	}
+	for _, in := range []string{"", "abc", "10s", "-5m"} {
+		if got := parseStrongDuration(in); got != nil {
+			t.Errorf("parseStrongDuration(%q) = %d, want nil", in, *got)
+		}
+	}
+}
+
+func TestParseStrongCSV_RepTypes(t *testing.T) {
+	csv := `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2024-01-15 08:00:00,Morning,30m,Plank,1,,,,60,,,
+2024-01-15 08:00:00,Morning,30m,Farmer Carry,1,70,,36.58,,,,
+2024-01-15 08:00:00,Morning,30m,Split Squat,1,25,8,,,slow [each side],,
+2024-01-15 08:00:00,Morning,30m,Side Plank,1,,8,,,[each side],,
+`
+	pf, err := ParseStrongCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseStrongCSV: %v", err)
+	}
+
+	sets := pf.Workouts[0].Sets
+	tests := []struct {
+		name      string
+		repType   string
+		reps      int
+		wantNotes string
+	}{
+		{"seconds", "seconds", 60, ""},
+		{"distance in meters", "distance", 40, ""},
+		{"each side with note", "each_side", 8, "slow"},
+		{"each side only", "each_side", 8, ""},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sets[i]
+			if s.RepType != tt.repType || s.Reps != tt.reps {
+				t.Errorf("set = %s x%d, want %s x%d", s.RepType, s.Reps, tt.repType, tt.reps)
+			}
+			notes := ""
+			if s.Notes != nil {
+				notes = *s.Notes
+			}
+			if notes != tt.wantNotes {
+				t.Errorf("notes = %q, want %q", notes, tt.wantNotes)
+			}
+		})
+	}
 }
 
 func TestParseStrongCSV_EmptyFile(t *testing.T) {
@@ -190,6 +307,52 @@ Morning Workout,2024-01-15 08:00:00,,Leg day,Deadlift,,,0,warmup,135,5,,,
 	}
 }
 
+func TestParseHevyCSV_ExportFixture(t *testing.T) {
+	f, err := os.Open("testdata/hevy_workouts.csv")
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+
+	pf, err := ParseHevyCSV(f)
+	if err != nil {
+		t.Fatalf("ParseHevyCSV: %v", err)
+	}
+	if len(pf.Workouts) != 2 {
+		t.Fatalf("got %d workouts, want 2", len(pf.Workouts))
+	}
+
+	push := pf.Workouts[0]
+	if push.Date != "2024-01-15" {
+		t.Errorf("date = %q, want 2024-01-15", push.Date)
+	}
+	// No description, so the routine title becomes the notes.
+	if push.Notes == nil || *push.Notes != "Push Day A" {
+		t.Errorf("notes = %v, want routine title Push Day A", push.Notes)
+	}
+	if len(push.Sets) != 4 {
+		t.Fatalf("got %d sets, want 4", len(push.Sets))
+	}
+	if push.Sets[0].RPE != nil {
+		t.Errorf("warmup RPE = %v, want nil", *push.Sets[0].RPE)
+	}
+	if rpe := push.Sets[2].RPE; rpe == nil || *rpe != 8.5 {
+		t.Errorf("set 3 RPE = %v, want 8.5", rpe)
+	}
+	if plank := push.Sets[3]; plank.RepType != "seconds" || plank.Reps != 60 {
+		t.Errorf("plank = %+v, want 60 seconds", plank)
+	}
+
+	legs := pf.Workouts[1]
+	// A description takes precedence over the routine title.
+	if legs.Notes == nil || *legs.Notes != "Knee felt tight" {
+		t.Errorf("notes = %v, want description", legs.Notes)
+	}
+	if rpe := legs.Sets[0].RPE; rpe == nil || *rpe != 9 {
+		t.Errorf("squat RPE = %v, want 9", rpe)
+	}
+}
+
 func TestParseHevyCSV_EmptyFile(t *testing.T) {
 	csv := `title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_lbs,reps,rpe,duration_seconds,distance_km
 `
@@ -212,6 +375,7 @@ func TestParseRepLogJSON_Basic(t *testing.T) {
 		"workouts": [
 			{
 				"date": "2024-01-15",
+				"duration_minutes": 45,
 				"sets": [
 					{"exercise": "Bench Press", "set_number": 1, "reps": 5, "weight": 135}
 				]
@@ -247,6 +411,9 @@ func TestParseRepLogJSON_Basic(t *testing.T) {
 	if pf.Workouts[0].Sets[0].RepType != "reps" {
 		t.Errorf("rep_type = %q, want reps", pf.Workouts[0].Sets[0].RepType)
 	}
+	if d := pf.Workouts[0].DurationMinutes; d == nil || *d != 45 {
+		t.Errorf("duration_minutes = %v, want 45", d)
+	}
 }
 
 func TestParseRepLogJSON_MissingVersion(t *testing.T) {
@@ -356,6 +523,30 @@ func TestBuildExerciseMappings_CaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestBuildExerciseMappings_Aliases(t *testing.T) {
+	parsed := []ParsedExercise{
+		{Name: "Barbell Squat"},
+		{Name: "Front Squat"},
+		{Name: "Zercher Squat"},
+	}
+	existing := []ExistingEntity{
+		{ID: 1, Name: "Back Squat", Aliases: []string{"barbell squat", "Front Squat"}},
+		{ID: 2, Name: "Front Squat"},
+	}
+
+	mappings := BuildExerciseMappings(parsed, existing)
+
+	if mappings[0].MappedID != 1 || mappings[0].MappedName != "Back Squat" || mappings[0].Create {
+		t.Errorf("alias match = %+v, want Back Squat", mappings[0])
+	}
+	if mappings[1].MappedID != 2 {
+		t.Errorf("name should win over alias: ID=%d, want 2", mappings[1].MappedID)
+	}
+	if !mappings[2].Create {
+		t.Errorf("unmatched name should be marked for creation: %+v", mappings[2])
+	}
+}
+
 func TestBuildEquipmentMappings(t *testing.T) {
 	parsed := []ParsedEquipment{
 		{Name: "Barbell"},
@@ -400,6 +591,50 @@ func TestBuildProgramMappings(t *testing.T) {
 	}
 }
 
+func TestProgramTemplateHash(t *testing.T) {
+	five, three := 5, 3
+	p70, p80 := 0.70, 0.80
+	a := ParsedProgramTemplate{Name: "5/3/1 BBB", NumWeeks: 4, NumDays: 4, PrescribedSets: []ParsedPrescribedSet{
+		{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, Percentage: &p70},
+		{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 2, Reps: &three, Percentage: &p80},
+	}}
+	// Same content with reordered sets, different name casing, and sort order.
+	b := ParsedProgramTemplate{Name: " 5/3/1  bbb", NumWeeks: 4, NumDays: 4, PrescribedSets: []ParsedPrescribedSet{
+		{Exercise: "squat", Week: 1, Day: 1, SetNumber: 2, Reps: &three, Percentage: &p80, RepType: "reps", SortOrder: 9},
+		{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, Percentage: &p70},
+	}}
+	if ProgramTemplateHash(a) != ProgramTemplateHash(b) {
+		t.Error("equivalent templates should hash equally")
+	}
+
+	rest := 180
+	b.PrescribedSets[0].RestSeconds = &rest
+	if ProgramTemplateHash(a) == ProgramTemplateHash(b) {
+		t.Error("changed rest time should change the hash")
+	}
+
+	b.PrescribedSets[0].RestSeconds = nil
+	b.PrescribedSets[0].Percentage = &p70
+	if ProgramTemplateHash(a) == ProgramTemplateHash(b) {
+		t.Error("changed percentage should change the hash")
+	}
+}
+
+func TestBuildProgramMappings_HashMatch(t *testing.T) {
+	pt := ParsedProgramTemplate{Name: "Strength Block", NumWeeks: 1, NumDays: 3}
+	parsed := []ParsedProgram{{Template: pt}}
+	existing := []ExistingEntity{
+		{ID: 5, Name: "Strength Block"},
+		{ID: 9, Name: "Strength Block (Caydan)", Hash: ProgramTemplateHash(pt)},
+	}
+
+	mappings := BuildProgramMappings(parsed, existing)
+
+	if mappings[0].MappedID != 9 || mappings[0].Create {
+		t.Errorf("mapping = %+v, want hash match on ID 9", mappings[0])
+	}
+}
+
 func TestMappingState_ResolveExerciseID(t *testing.T) {
 	ms := &MappingState{
 		Exercises: []EntityMapping{
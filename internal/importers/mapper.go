@@ -1,6 +1,10 @@
 package importers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -29,12 +33,15 @@ type MappingState struct {
 
 // ExistingEntity is a name+ID pair for populating mapping dropdowns.
 type ExistingEntity struct {
-	ID   int64
-	Name string
+	ID      int64
+	Name    string
+	Aliases []string // alternate names that also match this entity
+	Hash    string   // content hash (program templates only); "" = unknown
 }
 
 // BuildExerciseMappings creates initial exercise mappings by performing
-// case-insensitive exact matching against existing exercises.
+// case-insensitive exact matching against existing exercise names, then
+// their aliases.
 func BuildExerciseMappings(parsed []ParsedExercise, existing []ExistingEntity) []EntityMapping {
 	return buildMappings(parsedExerciseNames(parsed), existing)
 }
@@ -44,13 +51,83 @@ func BuildEquipmentMappings(parsed []ParsedEquipment, existing []ExistingEntity)
 	return buildMappings(parsedEquipmentNames(parsed), existing)
 }
 
-// BuildProgramMappings creates initial program template mappings.
+// BuildProgramMappings creates initial program template mappings. An incoming
+// program whose ProgramTemplateHash equals an existing template's hash maps
+// onto that template; the rest fall back to name matching.
 func BuildProgramMappings(parsed []ParsedProgram, existing []ExistingEntity) []EntityMapping {
 	names := make([]string, len(parsed))
 	for i, p := range parsed {
 		names[i] = p.Template.Name
 	}
-	return buildMappings(names, existing)
+	mappings := buildMappings(names, existing)
+
+	byHash := make(map[string]ExistingEntity)
+	for _, e := range existing {
+		if e.Hash != "" {
+			byHash[e.Hash] = e
+		}
+	}
+	if len(byHash) == 0 {
+		return mappings
+	}
+	for i, p := range parsed {
+		if match, ok := byHash[ProgramTemplateHash(p.Template)]; ok {
+			mappings[i] = EntityMapping{ImportName: p.Template.Name, MappedID: match.ID, MappedName: match.Name}
+		}
+	}
+	return mappings
+}
+
+// ProgramTemplateHash returns a stable fingerprint of a program template's
+// name, shape, and prescribed sets. Names are compared case- and
+// whitespace-insensitively and sets are ordered by week, day, exercise, and
+// set number, so the hash does not depend on file ordering or sort_order.
+func ProgramTemplateHash(pt ParsedProgramTemplate) string {
+	lines := make([]string, 0, len(pt.PrescribedSets))
+	for _, ps := range pt.PrescribedSets {
+		repType := ps.RepType
+		if repType == "" {
+			repType = "reps"
+		}
+		var repMax *int
+		if ps.Reps != nil {
+			repMax = ps.RepMax
+		}
+		notes := ""
+		if ps.Notes != nil {
+			notes = strings.TrimSpace(*ps.Notes)
+		}
+		lines = append(lines, fmt.Sprintf("%03d|%03d|%s|%03d|%s|%s|%s|%s|%s|%s|%s|%q",
+			ps.Week, ps.Day, normalizeHashName(ps.Exercise), ps.SetNumber,
+			hashInt(ps.Reps), hashInt(repMax), repType,
+			hashFloat(ps.Percentage), hashFloat(ps.AbsoluteWeight), hashFloat(ps.TargetRPE), hashInt(ps.RestSeconds), notes))
+	}
+	sort.Strings(lines)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%d|%d|%t\n", normalizeHashName(pt.Name), pt.NumWeeks, pt.NumDays, pt.IsLoop)
+	for _, l := range lines {
+		fmt.Fprintln(h, l)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func normalizeHashName(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func hashInt(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
+
+func hashFloat(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%g", *v)
 }
 
 func buildMappings(importNames []string, existing []ExistingEntity) []EntityMapping {
@@ -59,6 +136,15 @@ func buildMappings(importNames []string, existing []ExistingEntity) []EntityMapp
 	for _, e := range existing {
 		lookup[strings.ToLower(e.Name)] = e
 	}
+	// Aliases never shadow a real name.
+	for _, e := range existing {
+		for _, alias := range e.Aliases {
+			key := strings.ToLower(alias)
+			if _, taken := lookup[key]; !taken {
+				lookup[key] = e
+			}
+		}
+	}
 
 	mappings := make([]EntityMapping, len(importNames))
 	for i, name := range importNames {
@@ -164,3 +250,14 @@ func (ms *MappingState) ResolveProgramID(importName string) int64 {
 	}
 	return 0
 }
+
+// CheckTargetRPE returns ps's target RPE if it is within 1–10, the range the
+// database accepts. An out-of-range value is dropped rather than failing the
+// whole import; the returned warning describes the affected set.
+func CheckTargetRPE(ps ParsedPrescribedSet) (*float64, string) {
+	if ps.TargetRPE == nil || (*ps.TargetRPE >= 1 && *ps.TargetRPE <= 10) {
+		return ps.TargetRPE, ""
+	}
+	return nil, fmt.Sprintf("target RPE %g for %s (week %d, day %d, set %d) is outside 1–10 and was not imported",
+		*ps.TargetRPE, ps.Exercise, ps.Week, ps.Day, ps.SetNumber)
+}
@@ -20,14 +20,16 @@ type replogJSON struct {
 	BodyWeights      []ParsedBodyWeight `json:"body_weights"`
 	Workouts         []replogWorkoutJSON `json:"workouts"`
 	Programs         []ParsedProgram    `json:"programs"`
+	AccessoryPlans   []ParsedAccessoryPlan `json:"accessory_plans"`
 }
 
 // replogWorkoutJSON matches the JSON workout structure (review is inline).
 type replogWorkoutJSON struct {
-	Date   string             `json:"date"`
-	Notes  *string            `json:"notes"`
-	Review *ParsedReview      `json:"review"`
-	Sets   []ParsedWorkoutSet `json:"sets"`
+	Date            string             `json:"date"`
+	Notes           *string            `json:"notes"`
+	DurationMinutes *int               `json:"duration_minutes"`
+	Review          *ParsedReview      `json:"review"`
+	Sets            []ParsedWorkoutSet `json:"sets"`
 }
 
 // ParseRepLogJSON parses a RepLog Native JSON export.
@@ -52,6 +54,7 @@ func ParseRepLogJSON(r io.Reader) (*ParsedFile, error) {
 		TrainingMaxes:    rj.TrainingMaxes,
 		BodyWeights:      rj.BodyWeights,
 		Programs:         rj.Programs,
+		AccessoryPlans:   rj.AccessoryPlans,
 	}
 
 	// Convert workouts.
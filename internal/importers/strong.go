@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -14,16 +15,26 @@ import (
 const (
 	strongColDate         = "Date"
 	strongColWorkoutName  = "Workout Name"
+	strongColDuration     = "Duration"
 	strongColExerciseName = "Exercise Name"
 	strongColSetOrder     = "Set Order"
 	strongColWeight       = "Weight"
 	strongColReps         = "Reps"
+	strongColDistance     = "Distance"
 	strongColSeconds      = "Seconds"
 	strongColNotes        = "Notes"
 	strongColWorkoutNotes = "Workout Notes"
 	strongColRPE          = "RPE"
 )
 
+// StrongEachSideMarker is appended to a set's Notes in Strong CSV exports to
+// preserve the each_side rep type, which Strong has no column for.
+const StrongEachSideMarker = "[each side]"
+
+// MetersPerYard converts RepLog distance sets (yards) to and from the meters
+// used in the Strong CSV Distance column.
+const MetersPerYard = 0.9144
+
 // ParseStrongCSV parses workout data from a Strong app CSV export.
 func ParseStrongCSV(r io.Reader) (*ParsedFile, error) {
 	cr := csv.NewReader(r)
@@ -87,6 +98,10 @@ func ParseStrongCSV(r io.Reader) (*ParsedFile, error) {
 			workoutOrder = append(workoutOrder, date)
 		}
 
+		if pw.DurationMinutes == nil {
+			pw.DurationMinutes = parseStrongDuration(colVal(row, idx, strongColDuration))
+		}
+
 		// Workout notes (first non-empty value per workout wins).
 		if wn := colVal(row, idx, strongColWorkoutNotes); wn != "" {
 			if _, noted := workoutNotesMap[date]; !noted {
@@ -130,6 +145,17 @@ func ParseStrongCSV(r io.Reader) (*ParsedFile, error) {
 			}
 		}
 
+		// Distance-based exercises: meters in the Distance column, stored as yards.
+		if set.Reps == 0 {
+			if v := colVal(row, idx, strongColDistance); v != "" {
+				meters, _ := strconv.ParseFloat(v, 64)
+				if yards := int(math.Round(meters / MetersPerYard)); yards > 0 {
+					set.Reps = yards
+					set.RepType = "distance"
+				}
+			}
+		}
+
 		if v := colVal(row, idx, strongColRPE); v != "" {
 			rpe, err := strconv.ParseFloat(v, 64)
 			if err == nil && rpe >= 1 && rpe <= 10 {
@@ -138,7 +164,13 @@ func ParseStrongCSV(r io.Reader) (*ParsedFile, error) {
 		}
 
 		if v := colVal(row, idx, strongColNotes); v != "" {
-			set.Notes = &v
+			if trimmed, ok := strings.CutSuffix(v, StrongEachSideMarker); ok {
+				set.RepType = "each_side"
+				v = strings.TrimSpace(trimmed)
+			}
+			if v != "" {
+				set.Notes = &v
+			}
 		}
 
 		pw.Sets = append(pw.Sets, set)
@@ -152,6 +184,21 @@ func ParseStrongCSV(r io.Reader) (*ParsedFile, error) {
 	return pf, nil
 }
 
+// parseStrongDuration parses a workout duration such as "1h 5m" or "45m"
+// (the format Strong and RepLog's CSV export use) into whole minutes. Returns
+// nil for blank or unparseable values and durations under a minute.
+func parseStrongDuration(s string) *int {
+	d, err := time.ParseDuration(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	if err != nil {
+		return nil
+	}
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		return nil
+	}
+	return &minutes
+}
+
 // parseStrongDate parses the date formats commonly seen in Strong exports.
 // Strong uses formats like "2026-02-15 14:30:00" or "2026 Feb 15".
 func parseStrongDate(s string) string {
@@ -164,6 +211,7 @@ func parseStrongDate(s string) string {
 		"2006 Jan 02",
 		"2006 Jan 2",
 		"Jan 2, 2006",
+		"2 Jan 2006, 15:04", // Hevy
 		"01/02/2006",
 		time.RFC3339,
 	}
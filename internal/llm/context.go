@@ -9,6 +9,8 @@ package llm
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/carpenike/replog/internal/models"
@@ -26,8 +28,10 @@ type AthleteContext struct {
 	CoachNotes        []NoteEntry        `json:"coach_notes"`
 	Goals             GoalContext        `json:"goals"`
 	ExerciseCatalog   []ExerciseEntry    `json:"exercise_catalog"`
+	Substitutions     []SubstitutionEntry `json:"exercise_substitutions,omitempty"`
 	RecentWorkouts    []WorkoutSummary   `json:"recent_workouts"`
 	ReferencePrograms []ReferenceProgramSummary `json:"reference_programs"`
+	ReferenceSelection ReferenceSelection `json:"reference_selection"`
 	PriorTemplates    []TemplateSummary  `json:"prior_templates"`
 }
 
@@ -112,8 +116,16 @@ type NoteEntry struct {
 
 // GoalContext holds the athlete's current goal and history.
 type GoalContext struct {
-	Current string   `json:"current"`
-	History []string `json:"history,omitempty"`
+	Current string             `json:"current"`
+	History []GoalHistoryEntry `json:"history,omitempty"` // oldest first
+}
+
+// GoalHistoryEntry is one change to the athlete's long-term goal.
+type GoalHistoryEntry struct {
+	Date  string  `json:"date"`
+	Goal  string  `json:"goal"`
+	SetBy string  `json:"set_by,omitempty"`
+	Notes *string `json:"notes,omitempty"`
 }
 
 // ExerciseEntry describes an available exercise for the LLM.
@@ -123,9 +135,17 @@ type ExerciseEntry struct {
 	Tier        *string `json:"tier"`
 	FormNotes   *string `json:"form_notes,omitempty"`
 	RestSeconds int     `json:"rest_seconds,omitempty"`
+	Unilateral  bool    `json:"unilateral,omitempty"` // prescribe with rep_type "each_side"
 	Compatible  bool    `json:"compatible"`
 }
 
+// SubstitutionEntry is a standing swap: the athlete does Use whenever
+// Replace would be programmed.
+type SubstitutionEntry struct {
+	Replace string `json:"replace"`
+	Use     string `json:"use"`
+}
+
 // WorkoutSummary describes a recent workout with its sets.
 type WorkoutSummary struct {
 	Date  string       `json:"date"`
@@ -154,6 +174,7 @@ type TemplateSummary struct {
 // ReferenceProgramSummary is a global seed/reference program with full prescribed sets.
 // Included so the LLM can see concrete structural examples for the athlete's audience.
 type ReferenceProgramSummary struct {
+	ID             int64                    `json:"id"`
 	Name           string                   `json:"name"`
 	Description    string                   `json:"description,omitempty"`
 	NumWeeks       int                      `json:"num_weeks"`
@@ -163,6 +184,17 @@ type ReferenceProgramSummary struct {
 	PrescribedSets []PrescribedSetSummary   `json:"prescribed_sets"`
 }
 
+// ReferenceSelection records how ReferencePrograms were chosen, so a coach
+// reading the context can tell why a generated program does or doesn't
+// resemble a given style.
+type ReferenceSelection struct {
+	Mode           string  `json:"mode"`                   // "selected" (coach picked IDs) or "audience"
+	TemplateIDs    []int64 `json:"template_ids,omitempty"` // coach-selected template IDs
+	Audience       string  `json:"audience"`               // "youth" or "adult", inferred from tier
+	AudienceReason string  `json:"audience_reason"`
+	Note           string  `json:"note,omitempty"`
+}
+
 // PrescribedSetSummary is a single prescribed set within a reference program.
 type PrescribedSetSummary struct {
 	Exercise       string   `json:"exercise"`
@@ -170,9 +202,11 @@ type PrescribedSetSummary struct {
 	Day            int      `json:"day"`
 	SetNumber      int      `json:"set_number"`
 	Reps           *int     `json:"reps,omitempty"`
+	RepMax         *int     `json:"rep_max,omitempty"`
 	RepType        string   `json:"rep_type"`
 	Percentage     *float64 `json:"percentage,omitempty"`
 	AbsoluteWeight *float64 `json:"absolute_weight,omitempty"`
+	TargetRPE      *float64 `json:"target_rpe,omitempty"`
 	SortOrder      int      `json:"sort_order"`
 	Notes          string   `json:"notes,omitempty"`
 }
@@ -184,7 +218,27 @@ type PrescribedSetSummary struct {
 // If referenceTemplateIDs is non-empty, only those specific templates are
 // included as reference programs. Otherwise all audience-matching global
 // templates are included (audience inferred from the athlete's tier).
+//
+// Private coach notes are left out; coach-initiated builds opt into them with
+// ContextOptions.IncludePrivateNotes.
 func BuildAthleteContext(db *sql.DB, athleteID int64, now time.Time, referenceTemplateIDs ...int64) (*AthleteContext, error) {
+	return BuildAthleteContextWithOptions(db, athleteID, now, ContextOptions{}, referenceTemplateIDs...)
+}
+
+// ContextOptions lets a coach keep sections of the athlete context from being
+// sent to the LLM. The zero value includes everything except private coach
+// notes, which only coach-initiated builds opt into.
+type ContextOptions struct {
+	ExcludeCoachNotes     bool // athlete notes and journal entries
+	ExcludeRecentWorkouts bool // per-session sets and notes; aggregate trends are kept
+	ExcludeBodyWeights    bool // body weight log and latest body weight
+
+	IncludePrivateNotes bool // private (coach-only) notes; set only when a coach builds the context
+}
+
+// BuildAthleteContextWithOptions is BuildAthleteContext with excluded
+// sections left empty.
+func BuildAthleteContextWithOptions(db *sql.DB, athleteID int64, now time.Time, opts ContextOptions, referenceTemplateIDs ...int64) (*AthleteContext, error) {
 	ctx := &AthleteContext{}
 
 	// Athlete profile.
@@ -223,18 +277,24 @@ func BuildAthleteContext(db *sql.DB, athleteID int64, now time.Time, referenceTe
 	ctx.Performance.TrainingMaxes = tms
 
 	// Body weights.
-	bws, err := buildBodyWeights(db, athleteID)
-	if err != nil {
-		return nil, fmt.Errorf("llm: build body weights: %w", err)
+	if opts.ExcludeBodyWeights {
+		ctx.Athlete.LatestBW = nil
+	} else {
+		bws, err := buildBodyWeights(db, athleteID)
+		if err != nil {
+			return nil, fmt.Errorf("llm: build body weights: %w", err)
+		}
+		ctx.Performance.BodyWeights = bws
 	}
-	ctx.Performance.BodyWeights = bws
 
 	// Coach notes (from athlete_notes + journal entries).
-	notes, err := buildCoachNotes(db, athleteID)
-	if err != nil {
-		return nil, fmt.Errorf("llm: build coach notes: %w", err)
+	if !opts.ExcludeCoachNotes {
+		notes, err := buildCoachNotes(db, athleteID, opts.IncludePrivateNotes)
+		if err != nil {
+			return nil, fmt.Errorf("llm: build coach notes: %w", err)
+		}
+		ctx.CoachNotes = notes
 	}
-	ctx.CoachNotes = notes
 
 	// Goals (with history from goal_history table).
 	ctx.Goals = buildGoals(db, profile, athleteID)
@@ -246,12 +306,26 @@ func BuildAthleteContext(db *sql.DB, athleteID int64, now time.Time, referenceTe
 	}
 	ctx.ExerciseCatalog = exercises
 
+	// Standing exercise substitutions.
+	subs, err := models.ListSubstitutions(db, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("llm: build substitutions: %w", err)
+	}
+	for _, sub := range subs {
+		ctx.Substitutions = append(ctx.Substitutions, SubstitutionEntry{
+			Replace: sub.FromExerciseName,
+			Use:     sub.ToExerciseName,
+		})
+	}
+
 	// Recent workouts with sets.
 	workouts, err := buildRecentWorkouts(db, athleteID)
 	if err != nil {
 		return nil, fmt.Errorf("llm: build recent workouts: %w", err)
 	}
-	ctx.RecentWorkouts = workouts
+	if !opts.ExcludeRecentWorkouts {
+		ctx.RecentWorkouts = workouts
+	}
 
 	// Exercise performance trends (computed from recent workouts).
 	ctx.Performance.Trends = buildPerformanceTrends(workouts)
@@ -265,20 +339,26 @@ func BuildAthleteContext(db *sql.DB, athleteID int64, now time.Time, referenceTe
 
 	// Reference programs: either coach-selected specific templates, or all
 	// global seed templates filtered by audience (youth vs adult).
+	sel := ReferenceSelection{Audience: "adult", AudienceReason: "athlete has no tier, so adult programs apply"}
+	if profile.Tier != nil {
+		sel.Audience = "youth"
+		sel.AudienceReason = fmt.Sprintf("athlete is on the %s tier, so youth programs apply", *profile.Tier)
+	}
 	var refProgs []ReferenceProgramSummary
 	if len(referenceTemplateIDs) > 0 {
+		sel.Mode = "selected"
+		sel.TemplateIDs = referenceTemplateIDs
 		refProgs, err = buildReferenceProgramsByIDs(db, referenceTemplateIDs)
 	} else {
-		audience := "adult"
-		if profile.Tier != nil {
-			audience = "youth"
-		}
-		refProgs, err = buildReferencePrograms(db, audience)
+		sel.Mode = "audience"
+		refProgs, err = buildReferencePrograms(db, sel.Audience)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("llm: build reference programs: %w", err)
 	}
+	sel.Note = referenceSelectionNote(sel, refProgs)
 	ctx.ReferencePrograms = refProgs
+	ctx.ReferenceSelection = sel
 
 	return ctx, nil
 }
@@ -302,11 +382,8 @@ func buildProfile(db *sql.DB, athleteID int64, now time.Time) (*AthleteProfile,
 	if athlete.Notes.Valid {
 		profile.Notes = &athlete.Notes.String
 	}
-	if athlete.DateOfBirth.Valid {
-		if dob, err := parseDate(athlete.DateOfBirth.String); err == nil {
-			age := int(now.Sub(dob).Hours() / 24 / 365)
-			profile.Age = &age
-		}
+	if age, ok := models.AthleteAge(athlete.DateOfBirth.String, now); ok {
+		profile.Age = &age
 	}
 	if athlete.Grade.Valid {
 		profile.Grade = &athlete.Grade.String
@@ -465,10 +542,12 @@ func buildPerformanceTrends(workouts []WorkoutSummary) []ExercisePerformance {
 	return trends
 }
 
-// buildCoachNotes returns a combined view of coach notes and relevant journal entries.
-func buildCoachNotes(db *sql.DB, athleteID int64) ([]NoteEntry, error) {
+// buildCoachNotes returns a combined view of coach notes and relevant journal
+// entries. Private notes are included only when includePrivate is set, so an
+// athlete-scoped context never carries coach-only notes.
+func buildCoachNotes(db *sql.DB, athleteID int64, includePrivate bool) ([]NoteEntry, error) {
 	// Athlete notes (coach observations, pinned items).
-	notes, err := models.ListAthleteNotes(db, athleteID, true)
+	notes, err := models.ListAthleteNotes(db, athleteID, includePrivate)
 	if err != nil {
 		return nil, fmt.Errorf("list athlete notes: %w", err)
 	}
@@ -485,7 +564,7 @@ func buildCoachNotes(db *sql.DB, athleteID int64) ([]NoteEntry, error) {
 	}
 
 	// Journal entries (workout reviews, goal changes, etc.) — limit to 50 most recent.
-	journal, err := models.ListJournalEntries(db, athleteID, true, 50)
+	journal, err := models.ListJournalEntries(db, athleteID, includePrivate, 50)
 	if err != nil {
 		return nil, fmt.Errorf("list journal entries: %w", err)
 	}
@@ -513,11 +592,21 @@ func buildGoals(db *sql.DB, profile *AthleteProfile, athleteID int64) GoalContex
 		gc.Current = *profile.Goal
 	}
 
-	// Populate goal history from the goal_history table.
+	// Populate goal history from the goal_history table, oldest first so the
+	// model reads how the athlete's focus evolved.
 	history, err := models.ListGoalHistory(db, athleteID)
-	if err == nil && len(history) > 0 {
-		for _, h := range history {
-			gc.History = append(gc.History, h.Goal)
+	if err == nil {
+		for i := len(history) - 1; i >= 0; i-- {
+			h := history[i]
+			entry := GoalHistoryEntry{
+				Date:  normalizeDate(h.EffectiveDate),
+				Goal:  h.Goal,
+				SetBy: h.SetByName,
+			}
+			if h.Notes.Valid {
+				entry.Notes = &h.Notes.String
+			}
+			gc.History = append(gc.History, entry)
 		}
 	}
 
@@ -526,7 +615,7 @@ func buildGoals(db *sql.DB, profile *AthleteProfile, athleteID int64) GoalContex
 
 // buildExerciseCatalog returns all exercises annotated with equipment compatibility.
 func buildExerciseCatalog(db *sql.DB, athleteID int64) ([]ExerciseEntry, error) {
-	exercises, err := models.ListExercises(db, "")
+	exercises, err := models.ListExercises(db, models.ExerciseFilter{})
 	if err != nil {
 		return nil, err
 	}
@@ -543,6 +632,7 @@ func buildExerciseCatalog(db *sql.DB, athleteID int64) ([]ExerciseEntry, error)
 			ID:          ex.ID,
 			Name:        ex.Name,
 			RestSeconds: ex.EffectiveRestSeconds(),
+			Unilateral:  ex.Unilateral,
 		}
 		if ex.Tier.Valid {
 			entry.Tier = &ex.Tier.String
@@ -561,7 +651,7 @@ func buildExerciseCatalog(db *sql.DB, athleteID int64) ([]ExerciseEntry, error)
 // buildRecentWorkouts returns the athlete's most recent workouts with their sets.
 // Returns up to 20 workouts.
 func buildRecentWorkouts(db *sql.DB, athleteID int64) ([]WorkoutSummary, error) {
-	page, err := models.ListWorkouts(db, athleteID, 0)
+	page, err := models.ListWorkouts(db, athleteID, 0, models.WorkoutPageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -671,6 +761,43 @@ func buildPriorTemplates(db *sql.DB, athleteID int64) ([]TemplateSummary, error)
 	return summaries, nil
 }
 
+// referenceSelectionNote flags selections likely to surprise the coach: no
+// reference programs at all, selected IDs that no longer exist, or selected
+// programs written for the other audience.
+func referenceSelectionNote(sel ReferenceSelection, programs []ReferenceProgramSummary) string {
+	if len(programs) == 0 {
+		if sel.Mode == "selected" {
+			return "None of the selected reference programs exist; the program will not be modeled on a reference style."
+		}
+		return fmt.Sprintf("No reference programs are marked for the %s audience; the program will not be modeled on a reference style.", sel.Audience)
+	}
+
+	var notes []string
+	if sel.Mode == "selected" && len(programs) < len(sel.TemplateIDs) {
+		found := make(map[int64]bool, len(programs))
+		for _, p := range programs {
+			found[p.ID] = true
+		}
+		var missing []string
+		for _, id := range sel.TemplateIDs {
+			if !found[id] {
+				missing = append(missing, strconv.FormatInt(id, 10))
+			}
+		}
+		notes = append(notes, fmt.Sprintf("Selected template IDs not found: %s.", strings.Join(missing, ", ")))
+	}
+	var otherAudience []string
+	for _, p := range programs {
+		if p.Audience != "" && p.Audience != sel.Audience {
+			otherAudience = append(otherAudience, p.Name)
+		}
+	}
+	if len(otherAudience) > 0 {
+		notes = append(notes, fmt.Sprintf("Written for a different audience than this %s athlete: %s.", sel.Audience, strings.Join(otherAudience, ", ")))
+	}
+	return strings.Join(notes, " ")
+}
+
 // buildReferencePrograms returns global seed/reference programs filtered by audience
 // ("youth" or "adult") with their full prescribed sets. This gives the LLM concrete
 // structural examples of correctly-built programs for the athlete's audience.
@@ -699,6 +826,7 @@ func templatesToReferenceSummaries(db *sql.DB, templates []*models.ProgramTempla
 	var programs []ReferenceProgramSummary
 	for _, t := range templates {
 		rp := ReferenceProgramSummary{
+			ID:       t.ID,
 			Name:     t.Name,
 			NumWeeks: t.NumWeeks,
 			NumDays:  t.NumDays,
@@ -728,6 +856,10 @@ func templatesToReferenceSummaries(db *sql.DB, templates []*models.ProgramTempla
 				r := int(ps.Reps.Int64)
 				pss.Reps = &r
 			}
+			if ps.RepMax.Valid {
+				m := int(ps.RepMax.Int64)
+				pss.RepMax = &m
+			}
 			if ps.Percentage.Valid {
 				p := ps.Percentage.Float64
 				pss.Percentage = &p
@@ -736,6 +868,10 @@ func templatesToReferenceSummaries(db *sql.DB, templates []*models.ProgramTempla
 				w := ps.AbsoluteWeight.Float64
 				pss.AbsoluteWeight = &w
 			}
+			if ps.TargetRPE.Valid {
+				rpe := ps.TargetRPE.Float64
+				pss.TargetRPE = &rpe
+			}
 			if ps.Notes.Valid {
 				pss.Notes = ps.Notes.String
 			}
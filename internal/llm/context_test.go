@@ -2,6 +2,7 @@ package llm
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,7 +35,7 @@ func seedAthlete(t testing.TB, db *sql.DB, name, tier, goal string) int64 {
 
 func seedExercise(t testing.TB, db *sql.DB, name, tier string) int64 {
 	t.Helper()
-	ex, err := models.CreateExercise(db, name, tier, "", "", 0)
+	ex, err := models.CreateExercise(db, name, tier, "", "", "", 0)
 	if err != nil {
 		t.Fatalf("seed exercise: %v", err)
 	}
@@ -81,6 +82,58 @@ func TestBuildAthleteContext_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildAthleteContext_Age(t *testing.T) {
+	db := testDB(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	withDOB, _ := models.CreateAthlete(db, "Youth", "", "", "", "2013-06-02", "7th", "", sql.NullInt64{}, true)
+	ctx, err := BuildAthleteContext(db, withDOB.ID, now)
+	if err != nil {
+		t.Fatalf("BuildAthleteContext: %v", err)
+	}
+	if ctx.Athlete.Age == nil || *ctx.Athlete.Age != 12 {
+		t.Errorf("age = %v, want 12", ctx.Athlete.Age)
+	}
+	if ctx.Athlete.Grade == nil || *ctx.Athlete.Grade != "7th" {
+		t.Errorf("grade = %v, want 7th", ctx.Athlete.Grade)
+	}
+
+	noDOB := seedAthlete(t, db, "Adult", "", "")
+	ctx, err = BuildAthleteContext(db, noDOB, now)
+	if err != nil {
+		t.Fatalf("BuildAthleteContext: %v", err)
+	}
+	if ctx.Athlete.Age != nil {
+		t.Errorf("age = %d, want omitted", *ctx.Athlete.Age)
+	}
+}
+
+func TestBuildAthleteContext_GoalHistory(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "Focused", "", "hit a 225 squat")
+	coach, err := models.CreateUser(db, "coach", "Coach Kim", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	models.RecordGoalChange(db, athleteID, "build general strength", "", coach.ID, "2026-01-05", "")
+	models.RecordGoalChange(db, athleteID, "hit a 225 squat", "build general strength", coach.ID, "2026-04-01", "Squat is moving well")
+
+	ctx, err := BuildAthleteContext(db, athleteID, time.Now())
+	if err != nil {
+		t.Fatalf("BuildAthleteContext: %v", err)
+	}
+	history := ctx.Goals.History
+	if len(history) != 2 {
+		t.Fatalf("goal history = %d entries, want 2", len(history))
+	}
+	if history[0].Goal != "build general strength" || history[0].Date != "2026-01-05" {
+		t.Errorf("first entry = %+v, want the oldest goal first", history[0])
+	}
+	if history[1].SetBy != "Coach Kim" || history[1].Notes == nil || *history[1].Notes != "Squat is moving well" {
+		t.Errorf("second entry = %+v, want set by Coach Kim with notes", history[1])
+	}
+}
+
 func TestBuildAthleteContext_WithWorkouts(t *testing.T) {
 	db := testDB(t)
 	athleteID := seedAthlete(t, db, "Alice", "sport_performance", "volleyball")
@@ -172,6 +225,96 @@ func TestBuildAthleteContext_WithBodyWeights(t *testing.T) {
 	}
 }
 
+func TestBuildAthleteContextWithOptions_Exclusions(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "Dana", "", "")
+
+	coach, err := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	if _, err := models.CreateAthleteNote(db, athleteID, coach.ID, "2026-01-10", "Tweaked shoulder", false, false); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	if _, err := models.CreateBodyWeight(db, athleteID, "2026-01-10", 150.0, ""); err != nil {
+		t.Fatalf("create body weight: %v", err)
+	}
+	seedWorkout(t, db, athleteID, time.Now().AddDate(0, 0, -2).Format("2006-01-02"))
+
+	full, err := BuildAthleteContextWithOptions(db, athleteID, time.Now(), ContextOptions{})
+	if err != nil {
+		t.Fatalf("BuildAthleteContextWithOptions: %v", err)
+	}
+	if len(full.CoachNotes) == 0 || len(full.RecentWorkouts) == 0 || len(full.Performance.BodyWeights) == 0 || full.Athlete.LatestBW == nil {
+		t.Fatal("default options should include every section")
+	}
+
+	trimmed, err := BuildAthleteContextWithOptions(db, athleteID, time.Now(), ContextOptions{
+		ExcludeCoachNotes:     true,
+		ExcludeRecentWorkouts: true,
+		ExcludeBodyWeights:    true,
+	})
+	if err != nil {
+		t.Fatalf("BuildAthleteContextWithOptions: %v", err)
+	}
+	if len(trimmed.CoachNotes) != 0 {
+		t.Errorf("coach notes = %d, want 0", len(trimmed.CoachNotes))
+	}
+	if len(trimmed.RecentWorkouts) != 0 {
+		t.Errorf("recent workouts = %d, want 0", len(trimmed.RecentWorkouts))
+	}
+	if len(trimmed.Performance.BodyWeights) != 0 {
+		t.Errorf("body weights = %d, want 0", len(trimmed.Performance.BodyWeights))
+	}
+	if trimmed.Athlete.LatestBW != nil {
+		t.Error("latest body weight should be omitted")
+	}
+}
+
+func TestBuildAthleteContext_PrivateNotes(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "Dana", "", "")
+
+	coach, err := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	if _, err := models.CreateAthleteNote(db, athleteID, coach.ID, "2026-01-10", "Keep knees out", false, false); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	if _, err := models.CreateAthleteNote(db, athleteID, coach.ID, "2026-01-11", "Parents worried about motivation", true, false); err != nil {
+		t.Fatalf("create private note: %v", err)
+	}
+
+	hasPrivate := func(ctx *AthleteContext) bool {
+		for _, n := range ctx.CoachNotes {
+			if strings.Contains(n.Content, "Parents worried") {
+				return true
+			}
+		}
+		return false
+	}
+
+	athleteScoped, err := BuildAthleteContext(db, athleteID, time.Now())
+	if err != nil {
+		t.Fatalf("BuildAthleteContext: %v", err)
+	}
+	if hasPrivate(athleteScoped) {
+		t.Error("athlete-scoped context should not include private notes")
+	}
+	if len(athleteScoped.CoachNotes) == 0 {
+		t.Error("athlete-scoped context should still include public notes")
+	}
+
+	coachScoped, err := BuildAthleteContextWithOptions(db, athleteID, time.Now(), ContextOptions{IncludePrivateNotes: true})
+	if err != nil {
+		t.Fatalf("BuildAthleteContextWithOptions: %v", err)
+	}
+	if !hasPrivate(coachScoped) {
+		t.Error("coach context should include private notes")
+	}
+}
+
 func TestBuildAthleteContext_ExerciseCatalog(t *testing.T) {
 	db := testDB(t)
 	athleteID := seedAthlete(t, db, "Dave", "", "")
@@ -202,7 +345,7 @@ func TestBuildAthleteContext_ExerciseCatalog_EquipmentFiltering(t *testing.T) {
 	bbExID := seedExercise(t, db, "Barbell Squat", "sport_performance")
 
 	// Create equipment and link it as required for the barbell exercise.
-	barbell, err := models.CreateEquipment(db, "Barbell", "Standard barbell")
+	barbell, err := models.CreateEquipment(db, "Barbell", "Standard barbell", 0, 0)
 	if err != nil {
 		t.Fatalf("create equipment: %v", err)
 	}
@@ -234,10 +377,10 @@ func TestBuildAthleteContext_PriorTemplates(t *testing.T) {
 	db := testDB(t)
 
 	// Create global templates with audience tags.
-	if _, err := models.CreateProgramTemplate(db, nil, "Foundations 1×20", "Youth foundations", 1, 2, true, "youth"); err != nil {
+	if _, err := models.CreateProgramTemplate(db, nil, "Foundations 1×20", "Youth foundations", 1, 2, true, "youth", 0, ""); err != nil {
 		t.Fatalf("create youth template: %v", err)
 	}
-	if _, err := models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Adult program", 4, 4, true, "adult"); err != nil {
+	if _, err := models.CreateProgramTemplate(db, nil, "5/3/1 BBB", "Adult program", 4, 4, true, "adult", 0, ""); err != nil {
 		t.Fatalf("create adult template: %v", err)
 	}
 
@@ -245,7 +388,7 @@ func TestBuildAthleteContext_PriorTemplates(t *testing.T) {
 	youthID := seedAthlete(t, db, "Eve", "foundational", "general fitness")
 
 	// Create an athlete-scoped template for the youth athlete.
-	if _, err := models.CreateProgramTemplate(db, &youthID, "Eve Custom", "", 3, 3, false, ""); err != nil {
+	if _, err := models.CreateProgramTemplate(db, &youthID, "Eve Custom", "", 3, 3, false, "", 0, ""); err != nil {
 		t.Fatalf("create athlete-scoped template: %v", err)
 	}
 
@@ -295,13 +438,13 @@ func TestBuildAthleteContext_ReferencePrograms_WithSets(t *testing.T) {
 	db := testDB(t)
 
 	// Create a global youth template with prescribed sets.
-	tmpl, err := models.CreateProgramTemplate(db, nil, "Youth Test Program", "A youth reference", 1, 2, true, "youth")
+	tmpl, err := models.CreateProgramTemplate(db, nil, "Youth Test Program", "A youth reference", 1, 2, true, "youth", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
 	exID := seedExercise(t, db, "Push-up", "foundational")
 	reps := 20
-	if _, err := models.CreatePrescribedSet(db, tmpl.ID, exID, 1, 1, 1, &reps, nil, nil, 1, "reps", "Form: full ROM"); err != nil {
+	if _, err := models.CreatePrescribedSet(db, tmpl.ID, exID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 1, "reps", "Form: full ROM"); err != nil {
 		t.Fatalf("create prescribed set: %v", err)
 	}
 
@@ -342,15 +485,15 @@ func TestBuildAthleteContext_ExplicitReferenceTemplateIDs(t *testing.T) {
 	db := testDB(t)
 
 	// Create two youth templates and one adult template.
-	youthA, err := models.CreateProgramTemplate(db, nil, "Youth A", "first", 1, 2, true, "youth")
+	youthA, err := models.CreateProgramTemplate(db, nil, "Youth A", "first", 1, 2, true, "youth", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
-	_, err = models.CreateProgramTemplate(db, nil, "Youth B", "second", 1, 3, true, "youth")
+	_, err = models.CreateProgramTemplate(db, nil, "Youth B", "second", 1, 3, true, "youth", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
-	adultC, err := models.CreateProgramTemplate(db, nil, "Adult C", "adult ref", 4, 4, false, "adult")
+	adultC, err := models.CreateProgramTemplate(db, nil, "Adult C", "adult ref", 4, 4, false, "adult", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
@@ -358,7 +501,7 @@ func TestBuildAthleteContext_ExplicitReferenceTemplateIDs(t *testing.T) {
 	// Add a prescribed set to youthA so we can verify it loads.
 	exID := seedExercise(t, db, "Squat", "foundational")
 	reps := 20
-	if _, err := models.CreatePrescribedSet(db, youthA.ID, exID, 1, 1, 1, &reps, nil, nil, 1, "reps", ""); err != nil {
+	if _, err := models.CreatePrescribedSet(db, youthA.ID, exID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 1, "reps", ""); err != nil {
 		t.Fatalf("create prescribed set: %v", err)
 	}
 
@@ -418,15 +561,15 @@ func TestBuildAthleteContext_ExplicitReferenceTemplateIDs(t *testing.T) {
 func TestListProgramTemplatesByIDs(t *testing.T) {
 	db := testDB(t)
 
-	a, err := models.CreateProgramTemplate(db, nil, "Prog A", "desc a", 4, 3, false, "adult")
+	a, err := models.CreateProgramTemplate(db, nil, "Prog A", "desc a", 4, 3, false, "adult", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
-	b, err := models.CreateProgramTemplate(db, nil, "Prog B", "desc b", 1, 4, true, "youth")
+	b, err := models.CreateProgramTemplate(db, nil, "Prog B", "desc b", 1, 4, true, "youth", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = models.CreateProgramTemplate(db, nil, "Prog C", "", 2, 2, false, "")
+	_, err = models.CreateProgramTemplate(db, nil, "Prog C", "", 2, 2, false, "", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -467,11 +610,11 @@ func TestBuildAthleteContext_ProgramHistory(t *testing.T) {
 	athleteID := seedAthlete(t, db, "Max", "sport_performance", "")
 
 	// Create two templates and assign them sequentially.
-	pt1, err := models.CreateProgramTemplate(db, nil, "Month 1", "first cycle", 4, 3, false, "youth")
+	pt1, err := models.CreateProgramTemplate(db, nil, "Month 1", "first cycle", 4, 3, false, "youth", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
-	pt2, err := models.CreateProgramTemplate(db, nil, "Month 2", "second cycle", 4, 4, false, "youth")
+	pt2, err := models.CreateProgramTemplate(db, nil, "Month 2", "second cycle", 4, 4, false, "youth", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -539,7 +682,7 @@ func TestListAthletePrograms(t *testing.T) {
 		}
 	})
 
-	pt, err := models.CreateProgramTemplate(db, nil, "Test Prog", "", 4, 3, false, "")
+	pt, err := models.CreateProgramTemplate(db, nil, "Test Prog", "", 4, 3, false, "", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -576,7 +719,7 @@ func TestListAthletePrograms(t *testing.T) {
 	if err := models.DeactivateProgram(db, ap.ID); err != nil {
 		t.Fatal(err)
 	}
-	pt2, err := models.CreateProgramTemplate(db, nil, "Test Prog 2", "", 1, 4, true, "")
+	pt2, err := models.CreateProgramTemplate(db, nil, "Test Prog 2", "", 1, 4, true, "", 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -600,3 +743,59 @@ func TestListAthletePrograms(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildAthleteContext_ReferenceSelection(t *testing.T) {
+	db := testDB(t)
+
+	adultID := seedAthlete(t, db, "Adam", "", "")
+	youthID := seedAthlete(t, db, "Zoe", "foundational", "")
+
+	t.Run("no audience match", func(t *testing.T) {
+		ctx, err := BuildAthleteContext(db, adultID, time.Now())
+		if err != nil {
+			t.Fatalf("BuildAthleteContext: %v", err)
+		}
+		sel := ctx.ReferenceSelection
+		if sel.Mode != "audience" || sel.Audience != "adult" || !strings.Contains(sel.AudienceReason, "no tier") {
+			t.Errorf("selection = %+v, want audience mode for adult", sel)
+		}
+		if !strings.Contains(sel.Note, "No reference programs are marked for the adult audience") {
+			t.Errorf("note = %q", sel.Note)
+		}
+	})
+
+	adultRef, err := models.CreateProgramTemplate(db, nil, "Adult Ref", "", 4, 4, false, "adult", 0, "")
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	t.Run("selected IDs with a missing ID and other audience", func(t *testing.T) {
+		ctx, err := BuildAthleteContext(db, youthID, time.Now(), adultRef.ID, 9999)
+		if err != nil {
+			t.Fatalf("BuildAthleteContext: %v", err)
+		}
+		sel := ctx.ReferenceSelection
+		if sel.Mode != "selected" || len(sel.TemplateIDs) != 2 || sel.Audience != "youth" {
+			t.Errorf("selection = %+v, want selected mode for youth", sel)
+		}
+		if !strings.Contains(sel.AudienceReason, "foundational tier") {
+			t.Errorf("audience reason = %q", sel.AudienceReason)
+		}
+		if !strings.Contains(sel.Note, "not found: 9999") || !strings.Contains(sel.Note, "Adult Ref") {
+			t.Errorf("note = %q, want missing ID and audience mismatch", sel.Note)
+		}
+		if ctx.ReferencePrograms[0].ID != adultRef.ID {
+			t.Errorf("reference program ID = %d, want %d", ctx.ReferencePrograms[0].ID, adultRef.ID)
+		}
+	})
+
+	t.Run("audience match has no note", func(t *testing.T) {
+		ctx, err := BuildAthleteContext(db, adultID, time.Now())
+		if err != nil {
+			t.Fatalf("BuildAthleteContext: %v", err)
+		}
+		if ctx.ReferenceSelection.Note != "" {
+			t.Errorf("note = %q, want none", ctx.ReferenceSelection.Note)
+		}
+	})
+}
@@ -1,12 +1,18 @@
 package llm
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
+
+	"github.com/carpenike/replog/internal/importers"
+	"github.com/carpenike/replog/internal/models"
 )
 
 // Generate orchestrates the full generation pipeline:
@@ -15,19 +21,34 @@ import (
 // 3. Call the LLM provider
 // 4. Extract CatalogJSON from the response
 func Generate(ctx context.Context, db *sql.DB, provider Provider, req GenerationRequest) (*GenerationResult, error) {
+	return GenerateStreaming(ctx, db, provider, req, nil)
+}
+
+// GenerateStreaming is Generate with onDelta called for each chunk of output
+// as it arrives. Providers that implement StreamingProvider stream; others
+// fall back to a blocking call and onDelta is never called. Either way the
+// returned GenerationResult is the same.
+func GenerateStreaming(ctx context.Context, db *sql.DB, provider Provider, req GenerationRequest, onDelta func(text string)) (*GenerationResult, error) {
 	now := time.Now()
 
 	// Step 1: Assemble athlete context.
-	athleteCtx, err := BuildAthleteContext(db, req.AthleteID, now, req.ReferenceTemplateIDs...)
+	athleteCtx, err := BuildAthleteContextWithOptions(db, req.AthleteID, now, req.ContextOptions, req.ReferenceTemplateIDs...)
 	if err != nil {
 		return nil, fmt.Errorf("llm: build context: %w", err)
 	}
 
+	// Long histories can crowd out the output; drop the oldest entries first.
+	trim := TrimContext(athleteCtx, ContextBudgetFromSettings(db))
+
+	contextJSON, err := json.Marshal(athleteCtx)
+	if err != nil {
+		return nil, fmt.Errorf("llm: marshal context: %w", err)
+	}
+
 	// Step 2: Construct prompts.
-	systemPrompt := buildSystemPrompt(athleteCtx)
-	// Allow admin override of the system prompt via settings.
-	if override := SystemPromptOverrideFromSettings(db); override != "" {
-		systemPrompt = override
+	systemPrompt, err := systemPromptFor(db, athleteCtx)
+	if err != nil {
+		return nil, fmt.Errorf("llm: build system prompt: %w", err)
 	}
 	userPrompt, err := buildUserPrompt(athleteCtx, req)
 	if err != nil {
@@ -39,26 +60,146 @@ func Generate(ctx context.Context, db *sql.DB, provider Provider, req Generation
 		Temperature: TemperatureFromSettings(db),
 		MaxTokens:   MaxTokensFromSettings(db),
 	}
-	resp, err := provider.Generate(ctx, systemPrompt, userPrompt, opts)
+	resp, err := callProvider(ctx, provider, systemPrompt, userPrompt, opts, onDelta)
 	if err != nil {
 		return nil, fmt.Errorf("llm: provider generate: %w", err)
 	}
 
 	// Step 4: Extract CatalogJSON and reasoning from response.
-	catalogJSON, reasoning := extractResponse(resp.Content)
+	content := resp.Content
+	catalogJSON, reasoning := extractResponse(content)
+	tokensUsed, duration := resp.TokensUsed, resp.Duration
+
+	// Step 5: If the model ran out of tokens mid-JSON, ask it to pick up
+	// where it stopped rather than discarding the whole generation. A failed
+	// continuation keeps what was already generated so the caller can still
+	// record usage and report the truncation.
+	continuations := 0
+	for catalogJSON == nil && isTruncated(resp.StopReason) && continuations < maxContinuations {
+		next, err := callProvider(ctx, provider, systemPrompt, buildContinuationPrompt(userPrompt, content), opts, onDelta)
+		if err != nil {
+			log.Printf("llm: provider continuation %d: %v", continuations+1, err)
+			break
+		}
+		continuations++
+		resp = next
+		content += trimContinuation(resp.Content)
+		tokensUsed += resp.TokensUsed
+		duration += resp.Duration
+		catalogJSON, reasoning = extractResponse(content)
+	}
+
+	// Step 6: Check the catalog against the request so the preview can flag
+	// sets the import would mangle. Unparseable JSON is left to the caller.
+	var issues []CatalogIssue
+	if catalogJSON != nil {
+		if parsed, err := importers.ParseCatalogJSON(bytes.NewReader(catalogJSON)); err == nil {
+			issues = ValidateGeneratedCatalog(parsed, req, athleteCtx.ExerciseCatalog)
+		}
+	}
 
 	return &GenerationResult{
-		CatalogJSON: catalogJSON,
-		Reasoning:   reasoning,
-		RawResponse: resp.Content,
-		TokensUsed:  resp.TokensUsed,
-		Duration:    resp.Duration,
-		Model:       resp.Model,
-		StopReason:  resp.StopReason,
+		Request:       req,
+		Issues:        issues,
+		CatalogJSON:   catalogJSON,
+		Reasoning:     reasoning,
+		RawResponse:   content,
+		TokensUsed:    tokensUsed,
+		Duration:      duration,
+		Model:         resp.Model,
+		StopReason:    resp.StopReason,
+		Continuations: continuations,
+		ContextTrim:   trim,
+		ContextJSON:   contextJSON,
 	}, nil
 }
 
+// maxContinuations caps how many times a truncated response is extended.
+const maxContinuations = 2
+
+// isTruncated reports whether a provider stop reason means the output hit
+// the token limit.
+func isTruncated(stopReason string) bool {
+	return stopReason == "max_tokens" || stopReason == "length"
+}
+
+// callProvider runs one LLM call, streaming through onDelta when both the
+// provider and caller support it.
+func callProvider(ctx context.Context, provider Provider, systemPrompt, userPrompt string, opts Options, onDelta func(string)) (*Response, error) {
+	if sp, ok := provider.(StreamingProvider); ok && onDelta != nil {
+		ch, err := sp.GenerateStream(ctx, systemPrompt, userPrompt, opts)
+		if err != nil {
+			return nil, err
+		}
+		return collectStream(ch, onDelta)
+	}
+	return provider.Generate(ctx, systemPrompt, userPrompt, opts)
+}
+
+// buildContinuationPrompt asks the model to resume a response that was cut
+// off. Providers are single-turn, so the partial output is replayed in the
+// user prompt.
+func buildContinuationPrompt(userPrompt, partial string) string {
+	var b strings.Builder
+	b.WriteString(userPrompt)
+	b.WriteString("\n\nYOUR PREVIOUS RESPONSE WAS CUT OFF AT THE OUTPUT TOKEN LIMIT. Here it is, verbatim:\n")
+	b.WriteString("<partial_response>\n")
+	b.WriteString(partial)
+	b.WriteString("\n</partial_response>\n\n")
+	b.WriteString("Continue EXACTLY where the partial response stops, mid-token if necessary, so that ")
+	b.WriteString("appending your output completes the CatalogJSON. Do NOT repeat any of it, do NOT restart ")
+	b.WriteString("the JSON, and do NOT add reasoning, markdown fences, or commentary.")
+	return b.String()
+}
+
+// trimContinuation strips a markdown fence a model may open a continuation
+// with despite instructions, which would otherwise corrupt the joined JSON.
+func trimContinuation(s string) string {
+	trimmed := strings.TrimLeft(s, " \t\r\n")
+	for _, fence := range []string{"```json", "```"} {
+		if strings.HasPrefix(trimmed, fence) {
+			return strings.TrimLeft(trimmed[len(fence):], " \t\r\n")
+		}
+	}
+	return s
+}
+
+// systemPromptFor returns the system prompt for a generation. An admin
+// override in settings replaces the whole prompt; otherwise the audience
+// rules come from the most specific coach-defined prompt template for the
+// athlete's tier, falling back to the built-in rules.
+func systemPromptFor(db *sql.DB, ctx *AthleteContext) (string, error) {
+	if override := SystemPromptOverrideFromSettings(db); override != "" {
+		return override, nil
+	}
+	tier := athleteTier(ctx)
+	tmpl, err := models.ResolvePromptTemplate(db, tier)
+	if errors.Is(err, models.ErrNotFound) {
+		return assembleSystemPrompt(DefaultAudienceRules(tier)), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return assembleSystemPrompt(tmpl.Instructions + "\n\n"), nil
+}
+
+// buildSystemPrompt returns the system prompt with the built-in audience
+// rules for the athlete's tier.
 func buildSystemPrompt(ctx *AthleteContext) string {
+	return assembleSystemPrompt(DefaultAudienceRules(athleteTier(ctx)))
+}
+
+// athleteTier returns the athlete's tier, or "" for an adult athlete.
+func athleteTier(ctx *AthleteContext) string {
+	if ctx.Athlete.Tier != nil {
+		return *ctx.Athlete.Tier
+	}
+	return ""
+}
+
+// assembleSystemPrompt wraps audienceRules with the output format, general
+// rules, and CatalogJSON schema shared by every athlete.
+func assembleSystemPrompt(audienceRules string) string {
 	var b strings.Builder
 
 	b.WriteString(`You are an expert strength and conditioning coach specializing in
@@ -96,7 +237,10 @@ GENERAL RULES (ALL ATHLETES)
    Exercises marked "compatible": false require equipment the athlete does not have.
    If the athlete has no equipment, only bodyweight exercises will be compatible.
    Never substitute or assume equipment availability — trust the compatibility flags.
+   If "exercise_substitutions" lists a swap, program the "use" exercise and never the
+   "replace" exercise for this athlete.
 3. Respect rep_type values: "reps", "each_side", "seconds", "distance".
+   Exercises marked "unilateral": true use rep_type "each_side" with reps per side.
 4. Include sort_order for exercise sequencing within each day (lower = earlier).
    Structure each day: main compound lifts first, then accessories, then conditioning.
 5. Include progression_rules with appropriate increments for compound lifts.
@@ -118,12 +262,70 @@ GENERAL RULES (ALL ATHLETES)
     and sort_order conventions. Do NOT copy them verbatim — adapt for the specific athlete.
 
 `)
+	b.WriteString(audienceRules)
 
-	// Add tier-specific rules based on the athlete's tier.
-	tier := ""
-	if ctx.Athlete.Tier != nil {
-		tier = *ctx.Athlete.Tier
-	}
+	b.WriteString(`═══════════════════════════════════════════════════════════════
+CATALOGJSON SCHEMA
+═══════════════════════════════════════════════════════════════
+
+{
+  "version": "1.0",
+  "type": "catalog",
+  "exercises": [],
+  "programs": [
+    {
+      "name": "Program Name",
+      "description": "Brief program description including periodization approach",
+      "num_weeks": 4,
+      "num_days": 4,
+      "is_loop": false,
+      "prescribed_sets": [
+        {
+          "exercise": "Exercise Name",
+          "week": 1,
+          "day": 1,
+          "set_number": 1,
+          "reps": 5,
+          "rep_type": "reps",
+          "percentage": 0.75,
+          "sort_order": 1,
+          "notes": "Optional set notes (RPE targets, form cues, tempo, etc.)"
+        }
+      ],
+      "progression_rules": [
+        {"exercise": "Exercise Name", "increment": 5.0}
+      ]
+    }
+  ]
+}
+
+FIELD DETAILS:
+- "reps": null means AMRAP (as many reps as possible).
+- "rep_max": optional upper bound for a rep range — "reps": 8, "rep_max": 12
+  prescribes 8–12 reps. Omit for a single rep target.
+- "percentage": fraction of training max (0.65 = 65%). Only use when athlete has TMs.
+- "absolute_weight": use instead of percentage for bodyweight, fixed-weight, or
+  exercises without a training max. Value is in the athlete's preferred unit (lbs/kg).
+- "target_rpe": optional RPE target (1–10, e.g. 8 or 8.5). May accompany a
+  load or stand alone when the athlete should self-select the weight.
+- "sort_order": controls exercise display order within a day (lower = earlier).
+  Main lifts get 1–3, accessories get 4–6, conditioning/finishers get 7+.
+- Each set is ONE row — 3×5 means three entries with set_number 1, 2, 3.
+- "exercises" array: ONLY include genuinely new exercises. For existing catalog
+  exercises, reference them by exact name in prescribed_sets.
+- "progression_rules": define weight increment (lbs) when the athlete completes
+  all prescribed reps. Use smaller increments for upper body (2.5–5) and
+  isolation exercises, larger for lower body compounds (5–10).
+`)
+
+	return b.String()
+}
+
+// DefaultAudienceRules returns the built-in youth or adult rules for an
+// athlete with the given tier ("" for adults). Prompt templates replace this
+// section of the system prompt.
+func DefaultAudienceRules(tier string) string {
+	var b strings.Builder
 
 	if tier != "" {
 		// Youth athlete — tier-based rules.
@@ -299,57 +501,6 @@ in a circuit/EMOM format. When generating workouts based on these references:
 
 `)
 	}
-
-	b.WriteString(`═══════════════════════════════════════════════════════════════
-CATALOGJSON SCHEMA
-═══════════════════════════════════════════════════════════════
-
-{
-  "version": "1.0",
-  "type": "catalog",
-  "exercises": [],
-  "programs": [
-    {
-      "name": "Program Name",
-      "description": "Brief program description including periodization approach",
-      "num_weeks": 4,
-      "num_days": 4,
-      "is_loop": false,
-      "prescribed_sets": [
-        {
-          "exercise": "Exercise Name",
-          "week": 1,
-          "day": 1,
-          "set_number": 1,
-          "reps": 5,
-          "rep_type": "reps",
-          "percentage": 0.75,
-          "sort_order": 1,
-          "notes": "Optional set notes (RPE targets, form cues, tempo, etc.)"
-        }
-      ],
-      "progression_rules": [
-        {"exercise": "Exercise Name", "increment": 5.0}
-      ]
-    }
-  ]
-}
-
-FIELD DETAILS:
-- "reps": null means AMRAP (as many reps as possible).
-- "percentage": fraction of training max (0.65 = 65%). Only use when athlete has TMs.
-- "absolute_weight": use instead of percentage for bodyweight, fixed-weight, or
-  exercises without a training max. Value is in the athlete's preferred unit (lbs/kg).
-- "sort_order": controls exercise display order within a day (lower = earlier).
-  Main lifts get 1–3, accessories get 4–6, conditioning/finishers get 7+.
-- Each set is ONE row — 3×5 means three entries with set_number 1, 2, 3.
-- "exercises" array: ONLY include genuinely new exercises. For existing catalog
-  exercises, reference them by exact name in prescribed_sets.
-- "progression_rules": define weight increment (lbs) when the athlete completes
-  all prescribed reps. Use smaller increments for upper body (2.5–5) and
-  isolation exercises, larger for lower body compounds (5–10).
-`)
-
 	return b.String()
 }
 
@@ -377,14 +528,20 @@ func buildUserPrompt(athleteCtx *AthleteContext, req GenerationRequest) (string,
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString(fmt.Sprintf("REQUEST:\nGenerate \"%s\" — a %d-day/week",
-		req.ProgramName, req.NumDays))
-	if req.IsLoop {
-		b.WriteString(", looping")
+	if req.RegenerateScope != nil {
+		if err := writeRegenerateRequest(&b, req); err != nil {
+			return "", err
+		}
 	} else {
-		b.WriteString(fmt.Sprintf(", %d-week", req.NumWeeks))
+		b.WriteString(fmt.Sprintf("REQUEST:\nGenerate \"%s\" — a %d-day/week",
+			req.ProgramName, req.NumDays))
+		if req.IsLoop {
+			b.WriteString(", looping")
+		} else {
+			b.WriteString(fmt.Sprintf(", %d-week", req.NumWeeks))
+		}
+		b.WriteString(fmt.Sprintf(" program for %s.\n", athleteCtx.Athlete.Name))
 	}
-	b.WriteString(fmt.Sprintf(" program for %s.\n", athleteCtx.Athlete.Name))
 
 	// Add tier-aware instructions.
 	if athleteCtx.Athlete.Tier != nil {
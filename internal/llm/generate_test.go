@@ -3,8 +3,11 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/carpenike/replog/internal/models"
 )
 
 func TestGenerate_MockProvider(t *testing.T) {
@@ -39,6 +42,37 @@ func TestGenerate_MockProvider(t *testing.T) {
 	}
 }
 
+func TestGenerateStreaming_MatchesGenerate(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "TestStream", "foundational", "get strong")
+
+	mockJSON := `{"version": "1.0", "type": "catalog", "exercises": [], "programs": []}`
+	provider := &MockProvider{
+		FixedContent: "<reasoning>Kept it simple.</reasoning>\n```json\n" + mockJSON + "\n```",
+	}
+	req := GenerationRequest{AthleteID: athleteID, ProgramName: "Test", NumWeeks: 1, NumDays: 3, IsLoop: true}
+
+	var streamed strings.Builder
+	result, err := GenerateStreaming(context.Background(), db, provider, req, func(text string) {
+		streamed.WriteString(text)
+	})
+	if err != nil {
+		t.Fatalf("GenerateStreaming: %v", err)
+	}
+	if streamed.String() != provider.FixedContent {
+		t.Errorf("streamed = %q, want full response", streamed.String())
+	}
+
+	blocking, err := Generate(context.Background(), db, provider, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if result.RawResponse != blocking.RawResponse || result.Reasoning != blocking.Reasoning ||
+		string(result.CatalogJSON) != string(blocking.CatalogJSON) || result.TokensUsed != blocking.TokensUsed {
+		t.Errorf("streaming result differs from blocking result:\n%+v\n%+v", result, blocking)
+	}
+}
+
 func TestGenerate_ProviderError(t *testing.T) {
 	db := testDB(t)
 	athleteID := seedAthlete(t, db, "TestErr", "", "")
@@ -64,6 +98,110 @@ func TestGenerate_ProviderError(t *testing.T) {
 	}
 }
 
+// scriptedProvider returns its responses in order and records each user
+// prompt. Once the responses run out it returns err.
+type scriptedProvider struct {
+	responses     []*Response
+	prompts       []string
+	systemPrompts []string
+	err           error
+}
+
+func (p *scriptedProvider) Name() string                 { return "Scripted" }
+func (p *scriptedProvider) Ping(_ context.Context) error { return nil }
+
+func (p *scriptedProvider) Generate(_ context.Context, systemPrompt, userPrompt string, _ Options) (*Response, error) {
+	p.systemPrompts = append(p.systemPrompts, systemPrompt)
+	p.prompts = append(p.prompts, userPrompt)
+	if len(p.responses) == 0 {
+		return nil, p.err
+	}
+	resp := p.responses[0]
+	p.responses = p.responses[1:]
+	return resp, nil
+}
+
+func TestGenerate_ContinuesTruncatedResponse(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "TestCont", "", "")
+
+	provider := &scriptedProvider{responses: []*Response{
+		{Content: "<reasoning>Upper/lower split.</reasoning>\n{\"version\": \"1.0\", \"type\": \"cat", TokensUsed: 100, StopReason: "max_tokens", Model: "m"},
+		{Content: "```json\nalog\", \"exercises\": [], \"programs\": []}", TokensUsed: 40, StopReason: "end_turn", Model: "m"},
+	}}
+	req := GenerationRequest{AthleteID: athleteID, ProgramName: "Test", NumWeeks: 1, NumDays: 3, IsLoop: true}
+
+	result, err := Generate(context.Background(), db, provider, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !json.Valid(result.CatalogJSON) || !strings.Contains(string(result.CatalogJSON), `"type": "catalog"`) {
+		t.Fatalf("CatalogJSON = %s, want joined catalog", result.CatalogJSON)
+	}
+	if result.Continuations != 1 {
+		t.Errorf("continuations = %d, want 1", result.Continuations)
+	}
+	if result.TokensUsed != 140 {
+		t.Errorf("tokens = %d, want 140", result.TokensUsed)
+	}
+	if result.Reasoning != "Upper/lower split." {
+		t.Errorf("reasoning = %q", result.Reasoning)
+	}
+	if len(provider.prompts) != 2 || !strings.Contains(provider.prompts[1], `"type": "cat`) {
+		t.Errorf("continuation prompt should replay the partial output")
+	}
+}
+
+func TestGenerate_ContinuationCapped(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "TestCap", "", "")
+
+	truncated := func(s string) *Response { return &Response{Content: s, StopReason: "length", TokensUsed: 10} }
+	provider := &scriptedProvider{responses: []*Response{
+		truncated(`{"version": "1.0",`), truncated(` "programs": [`), truncated(` {"name": "X",`),
+	}}
+	req := GenerationRequest{AthleteID: athleteID, ProgramName: "Test", NumWeeks: 1, NumDays: 3, IsLoop: true}
+
+	result, err := Generate(context.Background(), db, provider, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if result.CatalogJSON != nil {
+		t.Errorf("expected no CatalogJSON, got %s", result.CatalogJSON)
+	}
+	if result.Continuations != maxContinuations || len(provider.prompts) != 1+maxContinuations {
+		t.Errorf("continuations = %d, calls = %d, want %d retries", result.Continuations, len(provider.prompts), maxContinuations)
+	}
+	if !isTruncated(result.StopReason) {
+		t.Errorf("stop reason = %q, want truncated so the caller reports it", result.StopReason)
+	}
+}
+
+func TestGenerate_ContinuationErrorKeepsPartial(t *testing.T) {
+	db := testDB(t)
+	athleteID := seedAthlete(t, db, "TestContErr", "", "")
+
+	provider := &scriptedProvider{
+		responses: []*Response{{Content: `{"version": "1.0",`, StopReason: "max_tokens", TokensUsed: 120, Model: "m"}},
+		err:       errors.New("connection reset"),
+	}
+	req := GenerationRequest{AthleteID: athleteID, ProgramName: "Test", NumWeeks: 1, NumDays: 3, IsLoop: true}
+
+	result, err := Generate(context.Background(), db, provider, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if result.Continuations != 0 || len(provider.prompts) != 2 {
+		t.Errorf("continuations = %d, calls = %d, want 0 and 2", result.Continuations, len(provider.prompts))
+	}
+	if result.TokensUsed != 120 || result.Model != "m" {
+		t.Errorf("tokens = %d, model = %q, want usage from the first response", result.TokensUsed, result.Model)
+	}
+	if !isTruncated(result.StopReason) || result.RawResponse != `{"version": "1.0",` {
+		t.Errorf("stop reason = %q, raw = %q, want the truncated partial", result.StopReason, result.RawResponse)
+	}
+}
+
 func TestExtractResponse_WithReasoning(t *testing.T) {
 	content := "<reasoning>I chose compound lifts.</reasoning>\n```json\n{\"version\": \"1.0\", \"programs\": []}\n```"
 	catalogJSON, reasoning := extractResponse(content)
@@ -232,6 +370,62 @@ func TestBuildSystemPrompt(t *testing.T) {
 	})
 }
 
+func TestGenerate_PromptTemplateByTier(t *testing.T) {
+	db := testDB(t)
+	youthID := seedAthlete(t, db, "Youth", "foundational", "")
+	adultID := seedAthlete(t, db, "Adult", "", "")
+
+	if _, err := models.SetPromptTemplate(db, "youth", "", "COACH YOUTH GUARDRAILS"); err != nil {
+		t.Fatalf("set youth template: %v", err)
+	}
+	if _, err := models.SetPromptTemplate(db, "adult", "", "COACH ADULT GUARDRAILS"); err != nil {
+		t.Fatalf("set adult template: %v", err)
+	}
+
+	systemPromptFor := func(athleteID int64) string {
+		t.Helper()
+		provider := &scriptedProvider{responses: []*Response{
+			{Content: `{"version": "1.0", "type": "catalog", "exercises": [], "programs": []}`, StopReason: "end_turn"},
+		}}
+		req := GenerationRequest{AthleteID: athleteID, ProgramName: "Test", NumWeeks: 1, NumDays: 3, IsLoop: true}
+		if _, err := Generate(context.Background(), db, provider, req); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		return provider.systemPrompts[0]
+	}
+
+	youth := systemPromptFor(youthID)
+	if !strings.Contains(youth, "COACH YOUTH GUARDRAILS") {
+		t.Error("tiered athlete should get the youth template")
+	}
+	if strings.Contains(youth, "COACH ADULT GUARDRAILS") || strings.Contains(youth, "YOUTH ATHLETE SAFETY RULES") {
+		t.Error("youth template should replace the built-in rules, not join the adult template")
+	}
+	if !strings.Contains(youth, "CATALOGJSON SCHEMA") {
+		t.Error("templated prompt should keep the CatalogJSON schema")
+	}
+
+	adult := systemPromptFor(adultID)
+	if !strings.Contains(adult, "COACH ADULT GUARDRAILS") || strings.Contains(adult, "COACH YOUTH GUARDRAILS") {
+		t.Error("athlete without a tier should get the adult template")
+	}
+
+	// A tier-specific template beats the audience-wide one.
+	if _, err := models.SetPromptTemplate(db, "youth", "foundational", "COACH FOUNDATIONAL GUARDRAILS"); err != nil {
+		t.Fatalf("set foundational template: %v", err)
+	}
+	if got := systemPromptFor(youthID); !strings.Contains(got, "COACH FOUNDATIONAL GUARDRAILS") || strings.Contains(got, "COACH YOUTH GUARDRAILS") {
+		t.Error("foundational athlete should get the foundational template")
+	}
+
+	// Without templates the built-in rules apply.
+	_ = models.DeletePromptTemplate(db, "youth", "foundational")
+	_ = models.DeletePromptTemplate(db, "youth", "")
+	if got := systemPromptFor(youthID); !strings.Contains(got, "YOUTH ATHLETE SAFETY RULES") {
+		t.Error("without templates the built-in youth rules should apply")
+	}
+}
+
 func TestBuildUserPrompt(t *testing.T) {
 	tier := "sport_performance"
 	athleteCtx := &AthleteContext{
@@ -101,6 +101,11 @@ type GenerationRequest struct {
 	FocusAreas           []string // e.g. ["power", "conditioning"]
 	CoachDirections      string   // free-text instructions for the LLM
 	ReferenceTemplateIDs []int64  // coach-selected reference program IDs (empty = none)
+	ContextOptions                // sections the coach chose not to send
+
+	// RegenerateScope, when set, asks for one day of an existing program
+	// instead of a whole new one.
+	RegenerateScope *RegenerateScope
 }
 
 // GenerationResult holds the complete output from a generation.
@@ -112,6 +117,23 @@ type GenerationResult struct {
 	Duration    time.Duration
 	Model       string
 	StopReason  string // "end_turn"/"stop" = complete, "max_tokens"/"length" = truncated
+
+	// Request is what was asked for; Issues are the problems
+	// ValidateGeneratedCatalog found in CatalogJSON against it.
+	Request GenerationRequest
+	Issues  []CatalogIssue
+
+	// Continuations is how many follow-up calls were made to finish output
+	// truncated at the token limit (0 when the first response was complete).
+	Continuations int
+
+	// ContextTrim reports what was dropped from the context to fit the
+	// configured budget.
+	ContextTrim TrimResult
+
+	// ContextJSON is the AthleteContext sent to the LLM. It is persisted on
+	// the generation run rather than alongside the result.
+	ContextJSON []byte `json:"-"`
 }
 
 // NewProviderFromSettings creates a Provider using the current app_settings
@@ -133,6 +155,8 @@ func NewProviderFromSettings(db *sql.DB) (Provider, error) {
 		return NewAnthropicProvider(apiKey, model), nil
 	case "ollama":
 		return NewOllamaProvider(baseURL, model), nil
+	case "openai_compatible":
+		return NewOpenAICompatibleProvider(baseURL, model, apiKey), nil
 	default:
 		return nil, fmt.Errorf("llm: unknown provider %q", provider)
 	}
@@ -158,6 +182,17 @@ func MaxTokensFromSettings(db *sql.DB) int {
 	return tokens
 }
 
+// ContextBudgetFromSettings reads the context_budget setting: the estimated
+// token limit TrimContext applies to the athlete context. 0 disables trimming.
+func ContextBudgetFromSettings(db *sql.DB) int {
+	v := models.GetSetting(db, "llm.context_budget")
+	var tokens int
+	if _, err := fmt.Sscanf(v, "%d", &tokens); err != nil || tokens < 0 {
+		return 30000 // fallback default
+	}
+	return tokens
+}
+
 // SystemPromptOverrideFromSettings reads the system_prompt_override setting.
 // Returns empty string if not set, in which case the default prompt is used.
 func SystemPromptOverrideFromSettings(db *sql.DB) string {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -39,34 +40,11 @@ func (p *AnthropicProvider) Ping(ctx context.Context) error {
 }
 
 func (p *AnthropicProvider) Generate(ctx context.Context, systemPrompt, userPrompt string, opts Options) (*Response, error) {
-	maxTokens := opts.MaxTokens
-	if maxTokens <= 0 {
-		maxTokens = 4096
-	}
-
-	body := map[string]any{
-		"model":      p.model,
-		"max_tokens": maxTokens,
-		"system":     systemPrompt,
-		"messages": []map[string]string{
-			{"role": "user", "content": userPrompt},
-		},
-		"temperature": opts.Temperature,
-	}
-
-	jsonBody, err := json.Marshal(body)
+	req, err := p.newMessagesRequest(ctx, systemPrompt, userPrompt, opts, false)
 	if err != nil {
-		return nil, fmt.Errorf("llm/anthropic: marshal request: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("llm/anthropic: create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", p.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
 	start := time.Now()
 	resp, err := p.client.Do(req)
 	if err != nil {
@@ -81,23 +59,7 @@ func (p *AnthropicProvider) Generate(ctx context.Context, systemPrompt, userProm
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		apiErr := &APIError{
-			Provider:   "Anthropic",
-			StatusCode: resp.StatusCode,
-		}
-		var errResp struct {
-			Error struct {
-				Type    string `json:"type"`
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
-			apiErr.Code = errResp.Error.Type
-			apiErr.Message = errResp.Error.Message
-		} else {
-			apiErr.Message = string(respBody)
-		}
-		return nil, apiErr
+		return nil, anthropicAPIError(resp.StatusCode, respBody)
 	}
 
 	var result struct {
@@ -136,3 +98,147 @@ func (p *AnthropicProvider) Generate(ctx context.Context, systemPrompt, userProm
 		StopReason: result.StopReason,
 	}, nil
 }
+
+// GenerateStream streams a Messages API response. Input tokens arrive with
+// message_start and output tokens with message_delta; their sum matches
+// TokensUsed from the blocking call.
+func (p *AnthropicProvider) GenerateStream(ctx context.Context, systemPrompt, userPrompt string, opts Options) (<-chan StreamDelta, error) {
+	req, err := p.newMessagesRequest(ctx, systemPrompt, userPrompt, opts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("llm/anthropic: request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, anthropicAPIError(resp.StatusCode, respBody)
+	}
+
+	ch := make(chan StreamDelta, 16)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		out := &Response{}
+		var content strings.Builder
+		var inputTokens, outputTokens int
+		var streamErr error
+		err := readSSE(resp.Body, func(event, data string) bool {
+			var ev struct {
+				Message struct {
+					Model string `json:"model"`
+					Usage struct {
+						InputTokens int `json:"input_tokens"`
+					} `json:"usage"`
+				} `json:"message"`
+				Delta struct {
+					Type       string `json:"type"`
+					Text       string `json:"text"`
+					StopReason string `json:"stop_reason"`
+				} `json:"delta"`
+				Usage struct {
+					OutputTokens int `json:"output_tokens"`
+				} `json:"usage"`
+				Error struct {
+					Type    string `json:"type"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if streamErr = json.Unmarshal([]byte(data), &ev); streamErr != nil {
+				return false
+			}
+			switch event {
+			case "message_start":
+				out.Model = ev.Message.Model
+				inputTokens = ev.Message.Usage.InputTokens
+			case "content_block_delta":
+				if ev.Delta.Type == "text_delta" && ev.Delta.Text != "" {
+					content.WriteString(ev.Delta.Text)
+					ch <- StreamDelta{Text: ev.Delta.Text}
+				}
+			case "message_delta":
+				out.StopReason = ev.Delta.StopReason
+				outputTokens = ev.Usage.OutputTokens
+			case "message_stop":
+				return false
+			case "error":
+				streamErr = fmt.Errorf("%s: %s", ev.Error.Type, ev.Error.Message)
+				return false
+			}
+			return true
+		})
+		if err == nil {
+			err = streamErr
+		}
+		if err != nil {
+			ch <- StreamDelta{Err: fmt.Errorf("llm/anthropic: read stream: %w", err)}
+			return
+		}
+		out.Content = content.String()
+		out.TokensUsed = inputTokens + outputTokens
+		out.Duration = time.Since(start)
+		ch <- StreamDelta{Response: out}
+	}()
+	return ch, nil
+}
+
+// newMessagesRequest builds a Messages API request.
+func (p *AnthropicProvider) newMessagesRequest(ctx context.Context, systemPrompt, userPrompt string, opts Options, stream bool) (*http.Request, error) {
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 4096
+	}
+
+	body := map[string]any{
+		"model":      p.model,
+		"max_tokens": maxTokens,
+		"system":     systemPrompt,
+		"messages": []map[string]string{
+			{"role": "user", "content": userPrompt},
+		},
+		"temperature": opts.Temperature,
+	}
+	if stream {
+		body["stream"] = true
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("llm/anthropic: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("llm/anthropic: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return req, nil
+}
+
+// anthropicAPIError converts a non-200 response into an APIError.
+func anthropicAPIError(statusCode int, respBody []byte) *APIError {
+	apiErr := &APIError{
+		Provider:   "Anthropic",
+		StatusCode: statusCode,
+	}
+	var errResp struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Type
+		apiErr.Message = errResp.Error.Message
+	} else {
+		apiErr.Message = string(respBody)
+	}
+	return apiErr
+}
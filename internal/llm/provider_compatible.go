@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAICompatibleProvider implements Provider for self-hosted servers that
+// expose an OpenAI-compatible /v1/chat/completions endpoint, such as Ollama
+// (http://localhost:11434/v1), LM Studio (http://localhost:1234/v1), or vLLM.
+// Requests, token usage, and finish_reason handling are shared with
+// OpenAIProvider; only the defaults and the connectivity check differ.
+type OpenAICompatibleProvider struct {
+	*OpenAIProvider
+}
+
+// NewOpenAICompatibleProvider creates a provider for a local OpenAI-compatible
+// server. baseURL should include the /v1 prefix and defaults to Ollama's
+// http://localhost:11434/v1. The API key is optional; most local servers
+// ignore it.
+func NewOpenAICompatibleProvider(baseURL, model, apiKey string) *OpenAICompatibleProvider {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434/v1"
+	}
+	if model == "" {
+		model = "llama3"
+	}
+	p := NewOpenAIProvider(apiKey, strings.TrimSpace(model), strings.TrimRight(baseURL, "/"))
+	p.name = "OpenAI-compatible"
+	// Local models on modest hardware can be much slower than hosted APIs.
+	p.client.Timeout = 10 * time.Minute
+	return &OpenAICompatibleProvider{OpenAIProvider: p}
+}
+
+// Ping lists the server's models rather than running a completion, so testing
+// the connection doesn't force a large model to load into memory.
+func (p *OpenAICompatibleProvider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("llm/openai-compatible: create request: %w", err)
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("llm/openai-compatible: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{
+			Provider:   p.name,
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -34,3 +35,23 @@ func (p *MockProvider) Generate(_ context.Context, _, _ string, _ Options) (*Res
 		Duration:   time.Millisecond,
 	}, nil
 }
+
+// GenerateStream emits FixedContent line by line, then the same Response as
+// Generate.
+func (p *MockProvider) GenerateStream(ctx context.Context, systemPrompt, userPrompt string, opts Options) (<-chan StreamDelta, error) {
+	resp, err := p.Generate(ctx, systemPrompt, userPrompt, opts)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan StreamDelta, 16)
+	go func() {
+		defer close(ch)
+		for _, line := range strings.SplitAfter(resp.Content, "\n") {
+			if line != "" {
+				ch <- StreamDelta{Text: line}
+			}
+		}
+		ch <- StreamDelta{Response: resp}
+	}()
+	return ch, nil
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // OpenAIProvider implements Provider for OpenAI and OpenAI-compatible APIs.
 type OpenAIProvider struct {
+	name    string
 	apiKey  string
 	model   string
 	baseURL string
@@ -28,6 +30,7 @@ func NewOpenAIProvider(apiKey, model, baseURL string) *OpenAIProvider {
 		model = "gpt-4o"
 	}
 	return &OpenAIProvider{
+		name:    "OpenAI",
 		apiKey:  apiKey,
 		model:   model,
 		baseURL: baseURL,
@@ -37,7 +40,7 @@ func NewOpenAIProvider(apiKey, model, baseURL string) *OpenAIProvider {
 	}
 }
 
-func (p *OpenAIProvider) Name() string { return "OpenAI" }
+func (p *OpenAIProvider) Name() string { return p.name }
 
 func (p *OpenAIProvider) Ping(ctx context.Context) error {
 	_, err := p.Generate(ctx, "Respond with OK.", "ping", Options{Temperature: 0, MaxTokens: 10})
@@ -45,30 +48,9 @@ func (p *OpenAIProvider) Ping(ctx context.Context) error {
 }
 
 func (p *OpenAIProvider) Generate(ctx context.Context, systemPrompt, userPrompt string, opts Options) (*Response, error) {
-	body := map[string]any{
-		"model": p.model,
-		"messages": []map[string]string{
-			{"role": "system", "content": systemPrompt},
-			{"role": "user", "content": userPrompt},
-		},
-		"temperature": opts.Temperature,
-	}
-	if opts.MaxTokens > 0 {
-		body["max_tokens"] = opts.MaxTokens
-	}
-
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("llm/openai: marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
+	req, err := p.newChatRequest(ctx, systemPrompt, userPrompt, opts, false)
 	if err != nil {
-		return nil, fmt.Errorf("llm/openai: create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		return nil, err
 	}
 
 	start := time.Now()
@@ -85,27 +67,7 @@ func (p *OpenAIProvider) Generate(ctx context.Context, systemPrompt, userPrompt
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		apiErr := &APIError{
-			Provider:   "OpenAI",
-			StatusCode: resp.StatusCode,
-		}
-		var errResp struct {
-			Error struct {
-				Type    string `json:"type"`
-				Message string `json:"message"`
-				Code    string `json:"code"`
-			} `json:"error"`
-		}
-		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
-			apiErr.Code = errResp.Error.Code
-			if apiErr.Code == "" {
-				apiErr.Code = errResp.Error.Type
-			}
-			apiErr.Message = errResp.Error.Message
-		} else {
-			apiErr.Message = string(respBody)
-		}
-		return nil, apiErr
+		return nil, p.apiError(resp.StatusCode, respBody)
 	}
 
 	var result struct {
@@ -136,3 +98,139 @@ func (p *OpenAIProvider) Generate(ctx context.Context, systemPrompt, userPrompt
 		StopReason: result.Choices[0].FinishReason,
 	}, nil
 }
+
+// GenerateStream streams a chat completion. Usage is requested via
+// stream_options so TokensUsed matches the blocking call.
+func (p *OpenAIProvider) GenerateStream(ctx context.Context, systemPrompt, userPrompt string, opts Options) (<-chan StreamDelta, error) {
+	req, err := p.newChatRequest(ctx, systemPrompt, userPrompt, opts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("llm/openai: request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, p.apiError(resp.StatusCode, respBody)
+	}
+
+	ch := make(chan StreamDelta, 16)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		out := &Response{}
+		var content strings.Builder
+		var parseErr error
+		err := readSSE(resp.Body, func(_, data string) bool {
+			if data == "[DONE]" {
+				return false
+			}
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+					FinishReason string `json:"finish_reason"`
+				} `json:"choices"`
+				Model string `json:"model"`
+				Usage *struct {
+					TotalTokens int `json:"total_tokens"`
+				} `json:"usage"`
+			}
+			if parseErr = json.Unmarshal([]byte(data), &chunk); parseErr != nil {
+				return false
+			}
+			if chunk.Model != "" {
+				out.Model = chunk.Model
+			}
+			if chunk.Usage != nil {
+				out.TokensUsed = chunk.Usage.TotalTokens
+			}
+			if len(chunk.Choices) > 0 {
+				if chunk.Choices[0].FinishReason != "" {
+					out.StopReason = chunk.Choices[0].FinishReason
+				}
+				if text := chunk.Choices[0].Delta.Content; text != "" {
+					content.WriteString(text)
+					ch <- StreamDelta{Text: text}
+				}
+			}
+			return true
+		})
+		if err == nil {
+			err = parseErr
+		}
+		if err != nil {
+			ch <- StreamDelta{Err: fmt.Errorf("llm/openai: read stream: %w", err)}
+			return
+		}
+		out.Content = content.String()
+		out.Duration = time.Since(start)
+		ch <- StreamDelta{Response: out}
+	}()
+	return ch, nil
+}
+
+// newChatRequest builds a chat completions request.
+func (p *OpenAIProvider) newChatRequest(ctx context.Context, systemPrompt, userPrompt string, opts Options, stream bool) (*http.Request, error) {
+	body := map[string]any{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": userPrompt},
+		},
+		"temperature": opts.Temperature,
+	}
+	if opts.MaxTokens > 0 {
+		body["max_tokens"] = opts.MaxTokens
+	}
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]any{"include_usage": true}
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("llm/openai: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("llm/openai: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return req, nil
+}
+
+// apiError converts a non-200 response into an APIError.
+func (p *OpenAIProvider) apiError(statusCode int, respBody []byte) *APIError {
+	apiErr := &APIError{
+		Provider:   p.name,
+		StatusCode: statusCode,
+	}
+	var errResp struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Code
+		if apiErr.Code == "" {
+			apiErr.Code = errResp.Error.Type
+		}
+		apiErr.Message = errResp.Error.Message
+	} else {
+		apiErr.Message = string(respBody)
+	}
+	return apiErr
+}
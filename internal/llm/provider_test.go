@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -63,6 +64,23 @@ func TestNewProviderFromSettings_Ollama(t *testing.T) {
 	}
 }
 
+func TestNewProviderFromSettings_OpenAICompatible(t *testing.T) {
+	db := testDB(t)
+	models.SetSetting(db, "llm.provider", "openai_compatible")
+	models.SetSetting(db, "llm.base_url", "http://localhost:1234/v1/")
+
+	p, err := NewProviderFromSettings(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name() != "OpenAI-compatible" {
+		t.Errorf("name = %q, want OpenAI-compatible", p.Name())
+	}
+	if cp := p.(*OpenAICompatibleProvider); cp.baseURL != "http://localhost:1234/v1" {
+		t.Errorf("baseURL = %q, want trailing slash trimmed", cp.baseURL)
+	}
+}
+
 func TestNewProviderFromSettings_InvalidProvider(t *testing.T) {
 	db := testDB(t)
 	models.SetSetting(db, "llm.provider", "invalid")
@@ -328,6 +346,156 @@ func TestOllamaProvider_Ping(t *testing.T) {
 	}
 }
 
+func TestOpenAICompatibleProvider_Generate(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"content": "Hello from LM Studio"}, "finish_reason": "length"},
+			},
+			"model": "qwen2.5-7b-instruct",
+			"usage": map[string]int{"total_tokens": 4096},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	p := NewOpenAICompatibleProvider(srv.URL+"/v1", "qwen2.5-7b-instruct", "")
+	result, err := p.Generate(context.Background(), "system", "user", Options{Temperature: 0.5, MaxTokens: 2048})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if gotPath != "/v1/chat/completions" {
+		t.Errorf("path = %q, want /v1/chat/completions", gotPath)
+	}
+	if gotAuth != "" {
+		t.Errorf("expected no Authorization header without an API key, got %q", gotAuth)
+	}
+	if gotBody["max_tokens"] != float64(2048) {
+		t.Errorf("max_tokens = %v, want 2048", gotBody["max_tokens"])
+	}
+	if result.Content != "Hello from LM Studio" {
+		t.Errorf("content = %q", result.Content)
+	}
+	if result.StopReason != "length" {
+		t.Errorf("stop_reason = %q, want length", result.StopReason)
+	}
+	if result.TokensUsed != 4096 {
+		t.Errorf("tokens = %d, want 4096", result.TokensUsed)
+	}
+}
+
+func TestOpenAICompatibleProvider_Ping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"llama3"}]}`)
+	}))
+	defer srv.Close()
+
+	if err := NewOpenAICompatibleProvider(srv.URL+"/v1", "llama3", "").Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	// A base URL without /v1 hits the wrong path; the error should point at Settings.
+	err := NewOpenAICompatibleProvider(srv.URL, "llama3", "").Ping(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+}
+
+func TestOpenAIProvider_GenerateStream(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"model\":\"gpt-4o\",\"choices\":[{\"delta\":{\"content\":\"Hello \"}}]}\n\n")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"model\":\"gpt-4o\",\"choices\":[{\"delta\":{\"content\":\"world\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"model\":\"gpt-4o\",\"choices\":[],\"usage\":{\"total_tokens\":42}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider("test-key", "gpt-4o", srv.URL)
+	ch, err := p.GenerateStream(context.Background(), "system", "user", Options{Temperature: 0.5})
+	if err != nil {
+		t.Fatalf("GenerateStream: %v", err)
+	}
+	if gotBody["stream"] != true {
+		t.Errorf("stream = %v, want true", gotBody["stream"])
+	}
+
+	var deltas []string
+	result, err := collectStream(ch, func(text string) { deltas = append(deltas, text) })
+	if err != nil {
+		t.Fatalf("collectStream: %v", err)
+	}
+	if len(deltas) != 2 || result.Content != "Hello world" {
+		t.Errorf("deltas = %q content = %q", deltas, result.Content)
+	}
+	if result.Model != "gpt-4o" || result.TokensUsed != 42 || result.StopReason != "stop" {
+		t.Errorf("unexpected response metadata: %+v", result)
+	}
+}
+
+func TestOpenAIProvider_GenerateStream_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"type":"invalid_request_error","message":"bad key"}}`)
+	}))
+	defer srv.Close()
+
+	_, err := NewOpenAIProvider("bad", "gpt-4o", srv.URL).GenerateStream(context.Background(), "s", "u", Options{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 APIError, got %v", err)
+	}
+}
+
+func TestAnthropicProvider_GenerateStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"model\":\"claude-sonnet-4-20250514\",\"usage\":{\"input_tokens\":10}}}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello \"}}\n\n")
+		fmt.Fprint(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Claude\"}}\n\n")
+		fmt.Fprint(w, "event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"max_tokens\"},\"usage\":{\"output_tokens\":20}}\n\n")
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider("test-key", "claude-sonnet-4-20250514")
+	p.client.Transport = &rewriteTransport{
+		base:    http.DefaultTransport,
+		fromURL: "https://api.anthropic.com",
+		toURL:   srv.URL,
+	}
+
+	ch, err := p.GenerateStream(context.Background(), "system", "user", Options{MaxTokens: 100})
+	if err != nil {
+		t.Fatalf("GenerateStream: %v", err)
+	}
+	result, err := collectStream(ch, nil)
+	if err != nil {
+		t.Fatalf("collectStream: %v", err)
+	}
+	if result.Content != "Hello Claude" {
+		t.Errorf("content = %q", result.Content)
+	}
+	if result.TokensUsed != 30 || result.StopReason != "max_tokens" || result.Model != "claude-sonnet-4-20250514" {
+		t.Errorf("unexpected response metadata: %+v", result)
+	}
+}
+
 // rewriteTransport intercepts requests to fromURL and rewrites them to toURL.
 // Used to test the Anthropic provider which hardcodes the API URL.
 type rewriteTransport struct {
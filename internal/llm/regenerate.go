@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carpenike/replog/internal/importers"
+)
+
+// RegenerateScope narrows a generation to one day of an existing program.
+// The rest of Program is sent as-is so the model keeps the new day
+// consistent with it, and MergeRegenerated splices the result back.
+type RegenerateScope struct {
+	Program    importers.ParsedProgramTemplate
+	Day        int    // training day to replace (1-based)
+	Week       int    // week to replace; 0 replaces the day in every week
+	Directions string // why the coach wants it redone
+}
+
+// Includes reports whether a prescribed set falls inside the scope.
+func (s *RegenerateScope) Includes(week, day int) bool {
+	return day == s.Day && (s.Week == 0 || week == s.Week)
+}
+
+// String describes the scope for prompts and messages, e.g. "week 2, day 3",
+// "day 3 of every week", or just "day 3" for single-week programs.
+func (s *RegenerateScope) String() string {
+	switch {
+	case s.Program.NumWeeks <= 1:
+		return fmt.Sprintf("day %d", s.Day)
+	case s.Week == 0:
+		return fmt.Sprintf("day %d of every week", s.Day)
+	default:
+		return fmt.Sprintf("week %d, day %d", s.Week, s.Day)
+	}
+}
+
+// writeRegenerateRequest writes the REQUEST section for a scoped
+// regeneration in place of the whole-program request.
+func writeRegenerateRequest(b *strings.Builder, req GenerationRequest) error {
+	scope := req.RegenerateScope
+	programJSON, err := json.MarshalIndent(scope.Program, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal current program: %w", err)
+	}
+
+	b.WriteString("CURRENT PROGRAM (already reviewed and edited by the coach):\n")
+	b.Write(programJSON)
+	b.WriteString("\n\n")
+
+	if scope.Directions != "" {
+		b.WriteString("REGENERATION DIRECTIONS:\n")
+		b.WriteString(scope.Directions)
+		b.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(b, "REQUEST:\nReplace ONLY %s of \"%s\". ", scope.String(), scope.Program.Name)
+	b.WriteString("Every other day and week is final and must not change. ")
+	b.WriteString("Keep the replacement consistent with the rest of the program: balance movement patterns ")
+	b.WriteString("across the week, respect the progression between weeks, and avoid duplicating the other days.\n")
+	b.WriteString("Output a CatalogJSON with this one program (same name, num_weeks, and num_days) whose ")
+	fmt.Fprintf(b, "prescribed_sets contain ONLY the sets for %s. ", scope.String())
+	b.WriteString("Include progression_rules only for exercises that are new to the program.\n")
+	return nil
+}
+
+// MergeRegenerated replaces the sets in program that fall inside scope with
+// the in-scope sets from regenerated, leaving everything else untouched.
+// Progression rules for exercises the program didn't already have are
+// added. It returns the number of sets taken, or an error if the
+// regenerated output has none in scope, in which case program is unchanged.
+func MergeRegenerated(program *importers.ParsedProgramTemplate, regenerated *importers.ParsedFile, scope *RegenerateScope) (int, error) {
+	var replacement []importers.ParsedPrescribedSet
+	var rules []importers.ParsedProgressionRule
+	if regenerated != nil {
+		for _, p := range regenerated.Programs {
+			for _, s := range p.Template.PrescribedSets {
+				if scope.Includes(s.Week, s.Day) {
+					replacement = append(replacement, s)
+				}
+			}
+			rules = append(rules, p.Template.ProgressionRules...)
+		}
+	}
+	if len(replacement) == 0 {
+		return 0, fmt.Errorf("llm: regenerated output has no sets for %s", scope.String())
+	}
+
+	kept := make([]importers.ParsedPrescribedSet, 0, len(program.PrescribedSets))
+	for _, s := range program.PrescribedSets {
+		if !scope.Includes(s.Week, s.Day) {
+			kept = append(kept, s)
+		}
+	}
+	merged := append(kept, replacement...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Week != merged[j].Week {
+			return merged[i].Week < merged[j].Week
+		}
+		return merged[i].Day < merged[j].Day
+	})
+	program.PrescribedSets = merged
+
+	hasRule := make(map[string]bool, len(program.ProgressionRules))
+	for _, r := range program.ProgressionRules {
+		hasRule[strings.ToLower(r.Exercise)] = true
+	}
+	for _, r := range rules {
+		if !hasRule[strings.ToLower(r.Exercise)] {
+			hasRule[strings.ToLower(r.Exercise)] = true
+			program.ProgressionRules = append(program.ProgressionRules, r)
+		}
+	}
+	return len(replacement), nil
+}
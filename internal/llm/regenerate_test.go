@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carpenike/replog/internal/importers"
+)
+
+func regenTestProgram() importers.ParsedProgramTemplate {
+	five := 5
+	return importers.ParsedProgramTemplate{
+		Name: "Block", NumWeeks: 2, NumDays: 2,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Back Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+			{Exercise: "Lunge", Week: 1, Day: 2, SetNumber: 1, Reps: &five},
+			{Exercise: "Back Squat", Week: 2, Day: 1, SetNumber: 1, Reps: &five},
+			{Exercise: "Lunge", Week: 2, Day: 2, SetNumber: 1, Reps: &five},
+		},
+		ProgressionRules: []importers.ParsedProgressionRule{{Exercise: "Back Squat", Increment: 5}},
+	}
+}
+
+func TestMergeRegenerated_SingleWeek(t *testing.T) {
+	program := regenTestProgram()
+	eight := 8
+	regenerated := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Romanian Deadlift", Week: 1, Day: 2, SetNumber: 1, Reps: &eight},
+			{Exercise: "Romanian Deadlift", Week: 1, Day: 2, SetNumber: 2, Reps: &eight},
+			{Exercise: "Bench Press", Week: 1, Day: 1, SetNumber: 1, Reps: &eight}, // out of scope
+		},
+		ProgressionRules: []importers.ParsedProgressionRule{
+			{Exercise: "Romanian Deadlift", Increment: 10},
+			{Exercise: "back squat", Increment: 99},
+		},
+	}}}}
+
+	n, err := MergeRegenerated(&program, regenerated, &RegenerateScope{Program: program, Week: 1, Day: 2})
+	if err != nil {
+		t.Fatalf("MergeRegenerated: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("merged %d sets, want 2", n)
+	}
+
+	var got []string
+	for _, s := range program.PrescribedSets {
+		got = append(got, s.Exercise)
+	}
+	want := "Back Squat,Romanian Deadlift,Romanian Deadlift,Back Squat,Lunge"
+	if strings.Join(got, ",") != want {
+		t.Errorf("sets = %v, want %s", got, want)
+	}
+	if len(program.ProgressionRules) != 2 || program.ProgressionRules[0].Increment != 5 {
+		t.Errorf("rules = %+v, want existing squat rule kept and RDL rule added", program.ProgressionRules)
+	}
+}
+
+func TestMergeRegenerated_AllWeeksAndEmpty(t *testing.T) {
+	program := regenTestProgram()
+	eight := 8
+	regenerated := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Front Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &eight},
+			{Exercise: "Front Squat", Week: 2, Day: 1, SetNumber: 1, Reps: &eight},
+		},
+	}}}}
+	scope := &RegenerateScope{Program: program, Day: 1}
+	if scope.String() != "day 1 of every week" {
+		t.Errorf("scope = %q", scope.String())
+	}
+
+	if _, err := MergeRegenerated(&program, regenerated, scope); err != nil {
+		t.Fatalf("MergeRegenerated: %v", err)
+	}
+	for _, s := range program.PrescribedSets {
+		if s.Day == 1 && s.Exercise != "Front Squat" {
+			t.Errorf("week %d day 1 still has %s", s.Week, s.Exercise)
+		}
+	}
+
+	before := len(program.PrescribedSets)
+	if _, err := MergeRegenerated(&program, &importers.ParsedFile{}, &RegenerateScope{Program: program, Week: 2, Day: 2}); err == nil {
+		t.Error("expected error when nothing was regenerated")
+	}
+	if len(program.PrescribedSets) != before {
+		t.Error("program should be unchanged after a failed merge")
+	}
+}
+
+func TestBuildUserPrompt_RegenerateScope(t *testing.T) {
+	athleteCtx := &AthleteContext{Athlete: AthleteProfile{Name: "TestAthlete"}}
+	req := GenerationRequest{
+		ProgramName: "Block", NumWeeks: 2, NumDays: 2,
+		RegenerateScope: &RegenerateScope{Program: regenTestProgram(), Week: 2, Day: 1, Directions: "no barbell"},
+	}
+
+	prompt, err := buildUserPrompt(athleteCtx, req)
+	if err != nil {
+		t.Fatalf("buildUserPrompt: %v", err)
+	}
+	for _, want := range []string{"CURRENT PROGRAM", `"Lunge"`, "Replace ONLY week 2, day 1", "no barbell"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if strings.Contains(prompt, "Generate \"Block\"") {
+		t.Error("scoped prompt should not ask for a whole program")
+	}
+}
@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+)
+
+// StreamingProvider is implemented by providers that can stream output as it
+// is generated. Callers type-assert for it and fall back to the blocking
+// Provider.Generate when a provider doesn't support streaming.
+type StreamingProvider interface {
+	Provider
+
+	// GenerateStream starts a generation and returns a channel of deltas.
+	// HTTP and API errors before the stream starts are returned directly.
+	// The channel is closed after a final delta carrying either the
+	// completed Response or an error.
+	GenerateStream(ctx context.Context, systemPrompt, userPrompt string, opts Options) (<-chan StreamDelta, error)
+}
+
+// StreamDelta is one chunk of a streamed response.
+type StreamDelta struct {
+	Text     string    // newly generated text
+	Response *Response // set on the final delta: accumulated content and metadata
+	Err      error     // set on the final delta if the stream failed
+}
+
+// collectStream drains a delta channel, calling onDelta for each text chunk,
+// and returns the final Response.
+func collectStream(ch <-chan StreamDelta, onDelta func(string)) (*Response, error) {
+	for d := range ch {
+		if d.Err != nil {
+			return nil, d.Err
+		}
+		if d.Text != "" && onDelta != nil {
+			onDelta(d.Text)
+		}
+		if d.Response != nil {
+			return d.Response, nil
+		}
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+// readSSE reads a server-sent event stream, calling fn with each event's
+// type (empty if unnamed) and data. Reading stops when fn returns false.
+func readSSE(r io.Reader, fn func(event, data string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if !fn(event, strings.Join(data, "\n")) {
+					return nil
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// Comment / keep-alive.
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		fn(event, strings.Join(data, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// charsPerToken is a rough average for JSON-heavy English text across the
+// supported providers. It is only used to keep prompts comfortably inside the
+// model's context window, so precision isn't needed.
+const charsPerToken = 4
+
+// EstimateTokens approximates how many tokens the context costs in the prompt,
+// based on the length of its JSON encoding.
+func EstimateTokens(ctx *AthleteContext) int {
+	return estimateJSONTokens(ctx)
+}
+
+func estimateJSONTokens(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return (len(b) + charsPerToken - 1) / charsPerToken
+}
+
+// TrimResult reports what TrimContext removed to fit the budget.
+type TrimResult struct {
+	Budget                   int
+	TokensBefore             int
+	TokensAfter              int
+	DroppedWorkouts          int
+	DroppedNotes             int
+	DroppedReferencePrograms int
+}
+
+// Trimmed reports whether anything was dropped.
+func (t TrimResult) Trimmed() bool {
+	return t.DroppedWorkouts+t.DroppedNotes+t.DroppedReferencePrograms > 0
+}
+
+func (t TrimResult) String() string {
+	if !t.Trimmed() {
+		return fmt.Sprintf("~%d tokens, within budget of %d", t.TokensBefore, t.Budget)
+	}
+	var dropped []string
+	if t.DroppedWorkouts > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d recent workouts", t.DroppedWorkouts))
+	}
+	if t.DroppedNotes > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d coach notes", t.DroppedNotes))
+	}
+	if t.DroppedReferencePrograms > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d reference programs", t.DroppedReferencePrograms))
+	}
+	return fmt.Sprintf("~%d → ~%d tokens (budget %d), dropped %s",
+		t.TokensBefore, t.TokensAfter, t.Budget, strings.Join(dropped, ", "))
+}
+
+// TrimContext shrinks the context to fit within budget estimated tokens by
+// removing entries from RecentWorkouts, CoachNotes, and ReferencePrograms —
+// the sections that grow with an athlete's history. Each step drops one entry
+// from whichever of those sections is currently largest: the oldest workout,
+// the oldest unpinned note (pinned notes go last), or the last reference
+// program. Everything else is left intact, so the result may still exceed the
+// budget. A budget <= 0 disables trimming.
+func TrimContext(ctx *AthleteContext, budget int) TrimResult {
+	res := TrimResult{Budget: budget, TokensBefore: EstimateTokens(ctx)}
+	res.TokensAfter = res.TokensBefore
+	if budget <= 0 || res.TokensBefore <= budget {
+		return res
+	}
+
+	// Per-entry costs, kept parallel to the slices as entries are removed.
+	workoutCosts := entryCosts(ctx.RecentWorkouts)
+	noteCosts := entryCosts(ctx.CoachNotes)
+	refCosts := entryCosts(ctx.ReferencePrograms)
+
+	for res.TokensAfter > budget {
+		w, n, r := sum(workoutCosts), sum(noteCosts), sum(refCosts)
+		switch {
+		case w == 0 && n == 0 && r == 0:
+			return res
+		case w >= n && w >= r:
+			// RecentWorkouts is newest first.
+			last := len(ctx.RecentWorkouts) - 1
+			res.TokensAfter -= workoutCosts[last]
+			ctx.RecentWorkouts = ctx.RecentWorkouts[:last]
+			workoutCosts = workoutCosts[:last]
+			res.DroppedWorkouts++
+		case n >= r:
+			i := oldestNote(ctx.CoachNotes)
+			res.TokensAfter -= noteCosts[i]
+			ctx.CoachNotes = append(ctx.CoachNotes[:i], ctx.CoachNotes[i+1:]...)
+			noteCosts = append(noteCosts[:i], noteCosts[i+1:]...)
+			res.DroppedNotes++
+		default:
+			last := len(ctx.ReferencePrograms) - 1
+			res.TokensAfter -= refCosts[last]
+			ctx.ReferencePrograms = ctx.ReferencePrograms[:last]
+			refCosts = refCosts[:last]
+			res.DroppedReferencePrograms++
+		}
+	}
+	res.TokensAfter = EstimateTokens(ctx)
+	return res
+}
+
+// oldestNote returns the index of the oldest unpinned note, or the oldest
+// note if all are pinned.
+func oldestNote(notes []NoteEntry) int {
+	oldest := -1
+	for _, pinnedPass := range []bool{false, true} {
+		for i, n := range notes {
+			if n.Pinned != pinnedPass {
+				continue
+			}
+			if oldest == -1 || n.Date < notes[oldest].Date {
+				oldest = i
+			}
+		}
+		if oldest != -1 {
+			return oldest
+		}
+	}
+	return oldest
+}
+
+func entryCosts[T any](entries []T) []int {
+	costs := make([]int, len(entries))
+	for i, e := range entries {
+		costs[i] = estimateJSONTokens(e)
+	}
+	return costs
+}
+
+func sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
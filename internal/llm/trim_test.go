@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func trimTestContext() *AthleteContext {
+	long := strings.Repeat("x", 400) // ~100 tokens per entry
+	ctx := &AthleteContext{Athlete: AthleteProfile{Name: "Tommy"}}
+	for _, date := range []string{"2026-03-05", "2026-03-04", "2026-03-03", "2026-03-02"} {
+		ctx.RecentWorkouts = append(ctx.RecentWorkouts, WorkoutSummary{Date: date, Notes: &long})
+	}
+	ctx.CoachNotes = []NoteEntry{
+		{Date: "2025-01-01", Content: long, Pinned: true},
+		{Date: "2026-02-01", Content: long},
+		{Date: "2025-06-01", Content: long},
+	}
+	ctx.ReferencePrograms = []ReferenceProgramSummary{
+		{Name: "Ref A", Description: long},
+		{Name: "Ref B", Description: long},
+	}
+	return ctx
+}
+
+func TestEstimateTokens(t *testing.T) {
+	small := &AthleteContext{}
+	big := trimTestContext()
+	if EstimateTokens(small) <= 0 {
+		t.Error("expected a positive estimate for an empty context")
+	}
+	if got := EstimateTokens(big); got < 900 || got > 1200 {
+		t.Errorf("estimate = %d, want roughly 9 entries × 100 tokens", got)
+	}
+}
+
+func TestTrimContext_WithinBudget(t *testing.T) {
+	ctx := trimTestContext()
+	res := TrimContext(ctx, 100000)
+	if res.Trimmed() || len(ctx.RecentWorkouts) != 4 || len(ctx.CoachNotes) != 3 {
+		t.Errorf("expected nothing trimmed, got %s", res)
+	}
+
+	if res := TrimContext(ctx, 0); res.Trimmed() {
+		t.Errorf("budget 0 should disable trimming, got %s", res)
+	}
+}
+
+func TestTrimContext_DropsOldestFirst(t *testing.T) {
+	ctx := trimTestContext()
+	budget := EstimateTokens(ctx) - 350 // forces four ~100-token entries out
+	res := TrimContext(ctx, budget)
+
+	if !res.Trimmed() || res.TokensAfter > budget {
+		t.Fatalf("expected context trimmed under %d, got %s", budget, res)
+	}
+	if got := res.DroppedWorkouts + res.DroppedNotes + res.DroppedReferencePrograms; got != 4 {
+		t.Errorf("dropped %d entries, want 4 (%s)", got, res)
+	}
+
+	// Workouts are newest first; the survivors must be the newest ones.
+	for i, w := range ctx.RecentWorkouts {
+		if want := []string{"2026-03-05", "2026-03-04", "2026-03-03"}[i]; w.Date != want {
+			t.Errorf("workout %d = %s, want %s", i, w.Date, want)
+		}
+	}
+	// The oldest unpinned note goes before the newer one; pinned notes stay.
+	for _, n := range ctx.CoachNotes {
+		if n.Date == "2025-06-01" {
+			t.Error("expected oldest unpinned note to be dropped")
+		}
+	}
+	if len(ctx.CoachNotes) == 0 || !ctx.CoachNotes[0].Pinned {
+		t.Error("expected pinned note to survive")
+	}
+	// Reference programs drop from the end.
+	if len(ctx.ReferencePrograms) > 0 && ctx.ReferencePrograms[0].Name != "Ref A" {
+		t.Errorf("expected first reference program kept, got %s", ctx.ReferencePrograms[0].Name)
+	}
+	if !strings.Contains(res.String(), "dropped") {
+		t.Errorf("summary = %q", res.String())
+	}
+}
+
+func TestTrimContext_EverythingDroppable(t *testing.T) {
+	ctx := trimTestContext()
+	res := TrimContext(ctx, 1)
+	if len(ctx.RecentWorkouts)+len(ctx.CoachNotes)+len(ctx.ReferencePrograms) != 0 {
+		t.Errorf("expected all trimmable sections emptied, got %s", res)
+	}
+	if ctx.Athlete.Name != "Tommy" {
+		t.Error("profile should never be trimmed")
+	}
+}
@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/carpenike/replog/internal/importers"
+)
+
+// CatalogIssue is a problem found in a generated catalog. Fatal issues would
+// import a broken program and block approval; the rest are warnings the
+// coach may accept.
+type CatalogIssue struct {
+	Program string
+	Message string
+	Fatal   bool
+}
+
+// ValidateGeneratedCatalog checks a parsed generation against the request and
+// the exercise catalog that was sent to the LLM. Sets outside the program's
+// weeks or days, percentages outside 0–1 and rep ranges whose rep_max isn't
+// above reps are fatal; exercises missing from the catalog or needing
+// unavailable equipment, and programs whose shape differs from the request,
+// are warnings. Repeated problems (e.g. every set of one exercise on a
+// nonexistent day) are reported once.
+func ValidateGeneratedCatalog(parsed *importers.ParsedFile, req GenerationRequest, catalog []ExerciseEntry) []CatalogIssue {
+	if parsed == nil || len(parsed.Programs) == 0 {
+		return []CatalogIssue{{Message: "The response contains no programs.", Fatal: true}}
+	}
+
+	exercises := make(map[string]ExerciseEntry, len(catalog))
+	for _, e := range catalog {
+		exercises[strings.ToLower(e.Name)] = e
+	}
+
+	wantWeeks := req.NumWeeks
+	if req.IsLoop {
+		wantWeeks = 1
+	}
+
+	var issues []CatalogIssue
+	seen := make(map[string]bool)
+	add := func(program, msg string, fatal bool) {
+		key := program + "\x00" + msg
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		issues = append(issues, CatalogIssue{Program: program, Message: msg, Fatal: fatal})
+	}
+
+	for _, p := range parsed.Programs {
+		t := p.Template
+		numWeeks, numDays := t.NumWeeks, t.NumDays
+		if numWeeks < 1 {
+			numWeeks = wantWeeks
+		}
+		if numDays < 1 {
+			numDays = req.NumDays
+		}
+
+		if req.NumDays > 0 && t.NumDays != req.NumDays {
+			add(t.Name, fmt.Sprintf("Program has %d days per week; %d were requested.", t.NumDays, req.NumDays), false)
+		}
+		if wantWeeks > 0 && t.NumWeeks != wantWeeks {
+			add(t.Name, fmt.Sprintf("Program has %d weeks; %d were requested.", t.NumWeeks, wantWeeks), false)
+		}
+		if len(t.PrescribedSets) == 0 {
+			add(t.Name, "Program has no prescribed sets.", true)
+		}
+
+		for _, s := range t.PrescribedSets {
+			if numWeeks > 0 && (s.Week < 1 || s.Week > numWeeks) {
+				add(t.Name, fmt.Sprintf("%s is scheduled in week %d of a %d-week program.", s.Exercise, s.Week, numWeeks), true)
+			}
+			if numDays > 0 && (s.Day < 1 || s.Day > numDays) {
+				add(t.Name, fmt.Sprintf("%s is scheduled on day %d of a %d-day program.", s.Exercise, s.Day, numDays), true)
+			}
+			if s.Percentage != nil && (*s.Percentage <= 0 || *s.Percentage > 1) {
+				add(t.Name, fmt.Sprintf("%s uses percentage %g; percentages must be a fraction of the training max between 0 and 1.", s.Exercise, *s.Percentage), true)
+			}
+			if s.Reps != nil && s.RepMax != nil && *s.RepMax <= *s.Reps {
+				add(t.Name, fmt.Sprintf("%s has rep range %d-%d; rep_max must be greater than reps.", s.Exercise, *s.Reps, *s.RepMax), true)
+			}
+
+			e, ok := exercises[strings.ToLower(s.Exercise)]
+			switch {
+			case !ok:
+				add(t.Name, fmt.Sprintf("%s is not in the exercise catalog.", s.Exercise), false)
+			case !e.Compatible:
+				add(t.Name, fmt.Sprintf("%s needs equipment the athlete doesn't have.", s.Exercise), false)
+			}
+		}
+	}
+	return issues
+}
+
+// HasFatalIssues reports whether any issue should block approval.
+func HasFatalIssues(issues []CatalogIssue) bool {
+	for _, i := range issues {
+		if i.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// ExerciseCatalog returns the exercise catalog as sent in an athlete's
+// context, for re-validating a generation after the coach edits it.
+func ExerciseCatalog(db *sql.DB, athleteID int64) ([]ExerciseEntry, error) {
+	return buildExerciseCatalog(db, athleteID)
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carpenike/replog/internal/importers"
+)
+
+func TestValidateGeneratedCatalog(t *testing.T) {
+	five, three := 5, 3
+	pct := func(v float64) *float64 { return &v }
+	parsed := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		Name: "Block", NumWeeks: 4, NumDays: 3,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Back Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, Percentage: pct(0.75)},
+			{Exercise: "Back Squat", Week: 5, Day: 1, SetNumber: 1, Reps: &five},
+			{Exercise: "Bench Press", Week: 1, Day: 4, SetNumber: 1, Reps: &five},
+			{Exercise: "Bench Press", Week: 1, Day: 4, SetNumber: 2, Reps: &five},
+			{Exercise: "Deadlift", Week: 1, Day: 2, SetNumber: 1, Reps: &five, Percentage: pct(80)},
+			{Exercise: "Deadlift", Week: 1, Day: 2, SetNumber: 2, Reps: &five, RepMax: &three},
+			{Exercise: "Sled Push", Week: 1, Day: 3, SetNumber: 1, Reps: &five},
+			{Exercise: "Zercher Carry", Week: 1, Day: 3, SetNumber: 1, Reps: &five},
+		},
+	}}}}
+	catalog := []ExerciseEntry{
+		{Name: "Back Squat", Compatible: true},
+		{Name: "bench press", Compatible: true},
+		{Name: "Deadlift", Compatible: true},
+		{Name: "Sled Push", Compatible: false},
+	}
+	req := GenerationRequest{NumWeeks: 4, NumDays: 4}
+
+	issues := ValidateGeneratedCatalog(parsed, req, catalog)
+
+	want := []struct {
+		substr string
+		fatal  bool
+	}{
+		{"3 days per week; 4 were requested", false},
+		{"Back Squat is scheduled in week 5 of a 4-week program", true},
+		{"Bench Press is scheduled on day 4 of a 3-day program", true},
+		{"Deadlift uses percentage 80", true},
+		{"Deadlift has rep range 5-3", true},
+		{"Sled Push needs equipment", false},
+		{"Zercher Carry is not in the exercise catalog", false},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		if !strings.Contains(issues[i].Message, w.substr) || issues[i].Fatal != w.fatal {
+			t.Errorf("issue %d = %+v, want %q fatal=%t", i, issues[i], w.substr, w.fatal)
+		}
+	}
+	if !HasFatalIssues(issues) {
+		t.Error("expected fatal issues")
+	}
+}
+
+func TestValidateGeneratedCatalog_Clean(t *testing.T) {
+	five := 5
+	parsed := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		Name: "Loop", NumWeeks: 1, NumDays: 2,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Push-up", Week: 1, Day: 2, SetNumber: 1, Reps: &five},
+		},
+	}}}}
+	req := GenerationRequest{NumWeeks: 4, NumDays: 2, IsLoop: true}
+
+	if issues := ValidateGeneratedCatalog(parsed, req, []ExerciseEntry{{Name: "Push-up", Compatible: true}}); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+	if issues := ValidateGeneratedCatalog(&importers.ParsedFile{}, req, nil); !HasFatalIssues(issues) {
+		t.Error("expected a fatal issue for an empty catalog")
+	}
+}
@@ -3,8 +3,11 @@ package middleware
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/carpenike/replog/internal/models"
@@ -22,6 +25,14 @@ const PrefsContextKey contextKey = "prefs"
 // UnreadCountContextKey stores the user's unread notification count in request context.
 const UnreadCountContextKey contextKey = "unreadCount"
 
+// ImpersonatorContextKey stores the real coach or admin while they view the
+// app as another user. UserContextKey holds the user being viewed.
+const ImpersonatorContextKey contextKey = "impersonator"
+
+// ImpersonateSessionKey is the session key holding the ID of the user a
+// coach or admin is viewing the app as.
+const ImpersonateSessionKey = "impersonate_user_id"
+
 // RequireAuth redirects unauthenticated users to the login page.
 func RequireAuth(sm *scs.SessionManager, db *sql.DB, next http.Handler) http.Handler {
 	return sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +50,49 @@ func RequireAuth(sm *scs.SessionManager, db *sql.DB, next http.Handler) http.Han
 			return
 		}
 
+		// Every login is recorded in the user's session list, so a session
+		// missing from it has been revoked.
+		err = models.TouchUserSession(db, sm.Token(r.Context()), user.ID, ClientIP(r), r.UserAgent())
+		if errors.Is(err, models.ErrNotFound) {
+			sm.Destroy(r.Context())
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Printf("middleware: failed to record session for user %d: %v", userID, err)
+			// Non-fatal — the session list is only missing a last-seen time.
+		}
+
+		// Sessions outliving the lifetime now configured for the user's role
+		// are cut short, so lowering the setting or promoting a user to coach
+		// applies without waiting for the next login.
+		if limit := time.Now().Add(models.SessionLifetimeFor(db, user)); sm.Deadline(r.Context()).After(limit) {
+			sm.SetDeadline(r.Context(), limit)
+		}
+
+		// Users required to use two-factor are held on the enrollment pages
+		// until they finish setting it up.
+		if sm.GetBool(r.Context(), "totp_setup_required") && !strings.HasPrefix(r.URL.Path, "/preferences/totp") {
+			http.Redirect(w, r, "/preferences/totp/begin", http.StatusSeeOther)
+			return
+		}
+
+		// A coach or admin previewing the app as another user sees it as that
+		// user. The preview ends if the user may no longer be impersonated.
+		var impersonator *models.User
+		if targetID := sm.GetInt64(r.Context(), ImpersonateSessionKey); targetID != 0 {
+			target, err := models.GetUserByID(db, targetID)
+			if err == nil && CanImpersonate(db, user, target) {
+				impersonator, user = user, target
+			} else {
+				sm.Remove(r.Context(), ImpersonateSessionKey)
+			}
+		}
+
 		ctx := context.WithValue(r.Context(), UserContextKey, user)
+		if impersonator != nil {
+			ctx = context.WithValue(ctx, ImpersonatorContextKey, impersonator)
+		}
 
 		// Load user preferences (defaults returned if no row exists).
 		prefs, err := models.GetUserPreferences(db, user.ID)
@@ -74,6 +127,13 @@ func UserFromContext(ctx context.Context) *models.User {
 	return u
 }
 
+// ImpersonatorFromContext retrieves the real user behind an impersonated
+// request. Returns nil if the request isn't impersonated.
+func ImpersonatorFromContext(ctx context.Context) *models.User {
+	u, _ := ctx.Value(ImpersonatorContextKey).(*models.User)
+	return u
+}
+
 // PrefsFromContext retrieves the user's preferences from the request context.
 // Returns nil if no preferences are set.
 func PrefsFromContext(ctx context.Context) *models.UserPreferences {
@@ -88,11 +148,27 @@ func UnreadCountFromContext(ctx context.Context) int {
 	return count
 }
 
-// CanAccessAthlete checks whether the authenticated user is allowed to access
-// the given athlete. Admins can access any athlete; coaches can access athletes
-// assigned to them; non-coaches can only access their own linked athlete.
-// Loads the athlete from the database to verify coach ownership.
+// CanAccessAthlete checks whether the authenticated user is allowed to view
+// the given athlete. Users who can write to the athlete (see CanWriteAthlete)
+// can view it, as can viewers granted read-only access.
 func CanAccessAthlete(db *sql.DB, user *models.User, athleteID int64) bool {
+	if CanWriteAthlete(db, user, athleteID) {
+		return true
+	}
+	ok, err := models.IsAthleteViewer(db, athleteID, user.ID)
+	if err != nil {
+		log.Printf("middleware: check viewer access to athlete %d for user %d: %v", athleteID, user.ID, err)
+		return false
+	}
+	return ok
+}
+
+// CanWriteAthlete checks whether the authenticated user is allowed to log
+// training and make changes for the given athlete. Admins can write to any
+// athlete; coaches can write to athletes assigned to them; non-coaches can
+// only write to their own linked athlete. Loads the athlete from the database
+// to verify coach ownership.
+func CanWriteAthlete(db *sql.DB, user *models.User, athleteID int64) bool {
 	if user.IsAdmin {
 		return true
 	}
@@ -123,6 +199,26 @@ func CanManageAthlete(user *models.User, athlete *models.Athlete) bool {
 	return false
 }
 
+// CanImpersonate checks whether actor may view the app as target. Admins can
+// impersonate anyone except other admins; coaches can impersonate the
+// non-coach accounts linked to athletes they coach.
+func CanImpersonate(db *sql.DB, actor, target *models.User) bool {
+	if actor.ID == target.ID || target.IsAdmin {
+		return false
+	}
+	if actor.IsAdmin {
+		return true
+	}
+	if !actor.IsCoach || target.IsCoach || !target.AthleteID.Valid {
+		return false
+	}
+	athlete, err := models.GetAthleteByID(db, target.AthleteID.Int64)
+	if err != nil {
+		return false
+	}
+	return CanManageAthlete(actor, athlete)
+}
+
 // ErrorRenderer is a function that renders a styled error page. Middleware
 // accepts this as a parameter to avoid importing the handlers package.
 type ErrorRenderer func(w http.ResponseWriter, r *http.Request, status int, title, message string)
@@ -161,6 +257,44 @@ func RequireAdmin(onError ErrorRenderer, next http.Handler) http.Handler {
 	})
 }
 
+// BlockImpersonatedWrites returns 403 for any request other than GET or HEAD
+// while a coach or admin is viewing the app as another user, so a preview
+// can't change the user's data. Account security pages (password, two-factor,
+// sessions, passkeys) are refused for every method: they can show or create
+// secrets that belong to the user alone. If onError is nil, falls back to
+// plain text http.Error.
+func BlockImpersonatedWrites(onError ErrorRenderer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ImpersonatorFromContext(r.Context()) == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if isAccountSecurityPath(r.URL.Path) {
+			if onError != nil {
+				onError(w, r, http.StatusForbidden, "Preview Only", "Account security settings aren't available while viewing as another user. Exit the preview to manage your own.")
+			} else {
+				http.Error(w, "Forbidden — account security settings are unavailable while viewing as another user", http.StatusForbidden)
+			}
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if onError != nil {
+				onError(w, r, http.StatusForbidden, "Preview Only", "You're viewing the app as another user, so changes are disabled. Exit the preview to make changes.")
+			} else {
+				http.Error(w, "Forbidden — changes are disabled while viewing as another user", http.StatusForbidden)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isAccountSecurityPath reports whether path is one of the self-service
+// account security pages under /preferences/ or /passkeys/.
+func isAccountSecurityPath(path string) bool {
+	return strings.HasPrefix(path, "/preferences/") || strings.HasPrefix(path, "/passkeys/")
+}
+
 // CoachAthleteFilter returns the coach ID to use for filtering athlete lists.
 // Admins get sql.NullInt64{} (invalid = no filter, see all athletes).
 // Coaches get their own user ID as the filter.
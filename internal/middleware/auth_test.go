@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -33,6 +34,17 @@ func testSessionManager() *scs.SessionManager {
 	return sm
 }
 
+// recordLogin adds the session in cookies to userID's session list, as the
+// login handlers do.
+func recordLogin(t *testing.T, db *sql.DB, cookies []*http.Cookie, userID int64) {
+	t.Helper()
+	for _, c := range cookies {
+		if err := models.CreateUserSession(db, c.Value, userID, "", ""); err != nil {
+			t.Fatalf("record session: %v", err)
+		}
+	}
+}
+
 func TestRequireAuth_RedirectsWhenNotAuthenticated(t *testing.T) {
 	db := testDB(t)
 	sm := testSessionManager()
@@ -82,6 +94,7 @@ func TestRequireAuth_SetsUserInContext(t *testing.T) {
 
 	// Step 2: Use the session cookie
 	cookies := setupRR.Result().Cookies()
+	recordLogin(t, db, cookies, user.ID)
 	req := httptest.NewRequest("GET", "/", nil)
 	for _, c := range cookies {
 		req.AddCookie(c)
@@ -100,6 +113,228 @@ func TestRequireAuth_SetsUserInContext(t *testing.T) {
 	}
 }
 
+func TestRequireAuth_RejectsUnrecordedSession(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+
+	user, err := models.CreateUser(db, "testcoach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	handler := RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	login := func() []*http.Cookie {
+		setupHandler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sm.Put(r.Context(), "userID", user.ID)
+			w.WriteHeader(http.StatusOK)
+		}))
+		rr := httptest.NewRecorder()
+		setupHandler.ServeHTTP(rr, httptest.NewRequest("GET", "/setup", nil))
+		return rr.Result().Cookies()
+	}
+	serve := func(cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// A session that never made it into the session list can't be revoked,
+	// so it isn't accepted.
+	if rr := serve(login()); rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/login" {
+		t.Errorf("unrecorded session: got %d %q, want redirect to /login", rr.Code, rr.Header().Get("Location"))
+	}
+
+	// A session revoked before its first request is rejected too.
+	cookies := login()
+	recordLogin(t, db, cookies, user.ID)
+	if _, err := models.RevokeUserSessions(db, user.ID, ""); err != nil {
+		t.Fatalf("revoke sessions: %v", err)
+	}
+	if rr := serve(cookies); rr.Code != http.StatusSeeOther {
+		t.Errorf("revoked session: status = %d, want %d", rr.Code, http.StatusSeeOther)
+	}
+}
+
+func TestRequireAuth_Impersonation(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+
+	coach, err := models.CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	athlete, err := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	if err != nil {
+		t.Fatalf("create athlete: %v", err)
+	}
+	kid, err := models.CreateUser(db, "kid", "", "password123", "", false, false, sql.NullInt64{Int64: athlete.ID, Valid: true})
+	if err != nil {
+		t.Fatalf("create kid: %v", err)
+	}
+
+	var gotUser, gotImpersonator *models.User
+	handler := RequireAuth(sm, db, BlockImpersonatedWrites(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = UserFromContext(r.Context())
+		gotImpersonator = ImpersonatorFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	var servePath func(method, path string, cookies []*http.Cookie) *httptest.ResponseRecorder
+	session := func(targetID int64) []*http.Cookie {
+		setupHandler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sm.Put(r.Context(), "userID", coach.ID)
+			sm.Put(r.Context(), ImpersonateSessionKey, targetID)
+			w.WriteHeader(http.StatusOK)
+		}))
+		rr := httptest.NewRecorder()
+		setupHandler.ServeHTTP(rr, httptest.NewRequest("GET", "/setup", nil))
+		recordLogin(t, db, rr.Result().Cookies(), coach.ID)
+		return rr.Result().Cookies()
+	}
+	serve := func(method string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		return servePath(method, "/athletes/"+strconv.FormatInt(athlete.ID, 10), cookies)
+	}
+	servePath = func(method, path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	cookies := session(kid.ID)
+	if rr := serve("GET", cookies); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotUser == nil || gotUser.ID != kid.ID {
+		t.Errorf("expected request to run as kid, got %+v", gotUser)
+	}
+	if gotImpersonator == nil || gotImpersonator.ID != coach.ID {
+		t.Errorf("expected impersonator to be coach, got %+v", gotImpersonator)
+	}
+
+	if rr := serve("POST", cookies); rr.Code != http.StatusForbidden {
+		t.Errorf("POST while impersonating: expected 403, got %d", rr.Code)
+	}
+	// Account security pages are off limits even to read.
+	for _, path := range []string{"/preferences/totp/begin", "/preferences/sessions", "/preferences/password", "/passkeys/register/begin"} {
+		if rr := servePath("GET", path, cookies); rr.Code != http.StatusForbidden {
+			t.Errorf("GET %s while impersonating: expected 403, got %d", path, rr.Code)
+		}
+	}
+
+	// A coach can't view as a user they don't coach; the preview is dropped.
+	other, err := models.CreateUser(db, "other", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create other: %v", err)
+	}
+	gotUser, gotImpersonator = nil, nil
+	if rr := serve("GET", session(other.ID)); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotUser == nil || gotUser.ID != coach.ID || gotImpersonator != nil {
+		t.Errorf("expected request to run as coach without impersonation, got user %+v impersonator %+v", gotUser, gotImpersonator)
+	}
+}
+
+func TestRequireAuth_HoldsUserOnTOTPSetup(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+
+	user, err := models.CreateUser(db, "testcoach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	handler := RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	setupHandler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "userID", user.ID)
+		sm.Put(r.Context(), "totp_setup_required", true)
+		w.WriteHeader(http.StatusOK)
+	}))
+	setupRR := httptest.NewRecorder()
+	setupHandler.ServeHTTP(setupRR, httptest.NewRequest("GET", "/setup", nil))
+	cookies := setupRR.Result().Cookies()
+	recordLogin(t, db, cookies, user.ID)
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{"/athletes", http.StatusSeeOther},
+		{"/preferences/totp/begin", http.StatusOK},
+		{"/preferences/totp/finish", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: status = %d, want %d", tt.path, rr.Code, tt.wantCode)
+		}
+		if tt.wantCode == http.StatusSeeOther {
+			if loc := rr.Header().Get("Location"); loc != "/preferences/totp/begin" {
+				t.Errorf("%s: redirect = %q, want /preferences/totp/begin", tt.path, loc)
+			}
+		}
+	}
+}
+
+func TestRequireAuth_ShortensCoachSession(t *testing.T) {
+	db := testDB(t)
+	sm := testSessionManager()
+
+	coach, err := models.CreateUser(db, "testcoach", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	models.SetSetting(db, "security.coach_session_lifetime_hours", "8")
+
+	var deadline time.Time
+	handler := RequireAuth(sm, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline = sm.Deadline(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// The session was issued with the 30-day default.
+	setupHandler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.Put(r.Context(), "userID", coach.ID)
+		w.WriteHeader(http.StatusOK)
+	}))
+	setupRR := httptest.NewRecorder()
+	setupHandler.ServeHTTP(setupRR, httptest.NewRequest("GET", "/setup", nil))
+	recordLogin(t, db, setupRR.Result().Cookies(), coach.ID)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range setupRR.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if limit := time.Now().Add(8 * time.Hour); deadline.After(limit) {
+		t.Errorf("deadline = %v, want at most %v", deadline, limit)
+	}
+}
+
 func TestRequireAuth_InvalidSessionRedirects(t *testing.T) {
 	db := testDB(t)
 	sm := testSessionManager()
@@ -231,6 +466,90 @@ func TestCanAccessAthlete(t *testing.T) {
 	}
 }
 
+func TestCanAccessAthlete_Viewer(t *testing.T) {
+	db := testDB(t)
+
+	athlete, err := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	if err != nil {
+		t.Fatalf("create athlete: %v", err)
+	}
+	other, err := models.CreateAthlete(db, "Other", "", "", "", "", "", "", sql.NullInt64{}, true)
+	if err != nil {
+		t.Fatalf("create other athlete: %v", err)
+	}
+	parent, err := models.CreateUser(db, "parent", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	if err := models.GrantAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+		t.Fatalf("grant viewer: %v", err)
+	}
+
+	if !CanAccessAthlete(db, parent, athlete.ID) {
+		t.Error("viewer should be able to access athlete")
+	}
+	if CanWriteAthlete(db, parent, athlete.ID) {
+		t.Error("viewer should not be able to write to athlete")
+	}
+	if CanAccessAthlete(db, parent, other.ID) {
+		t.Error("viewer should not be able to access other athlete")
+	}
+}
+
+func TestCanImpersonate(t *testing.T) {
+	db := testDB(t)
+
+	coach, err := models.CreateUser(db, "coach1", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	otherCoach, err := models.CreateUser(db, "coach2", "", "password123", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create other coach: %v", err)
+	}
+	admin, err := models.CreateUser(db, "admin1", "", "password123", "", false, true, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create admin: %v", err)
+	}
+	owned, err := models.CreateAthlete(db, "OwnedKid", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	if err != nil {
+		t.Fatalf("create owned athlete: %v", err)
+	}
+	kid, err := models.CreateUser(db, "kid1", "", "password123", "", false, false, sql.NullInt64{Int64: owned.ID, Valid: true})
+	if err != nil {
+		t.Fatalf("create kid: %v", err)
+	}
+	parent, err := models.CreateUser(db, "parent1", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		actor  *models.User
+		target *models.User
+		want   bool
+	}{
+		{"admin can view as athlete", admin, kid, true},
+		{"admin can view as coach", admin, coach, true},
+		{"admin can view as unlinked user", admin, parent, true},
+		{"coach can view as own athlete", coach, kid, true},
+		{"coach cannot view as other coach's athlete", otherCoach, kid, false},
+		{"coach cannot view as unlinked user", coach, parent, false},
+		{"coach cannot view as coach", coach, otherCoach, false},
+		{"nobody can view as admin", coach, admin, false},
+		{"cannot view as self", admin, admin, false},
+		{"non-coach cannot view as anyone", kid, parent, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanImpersonate(db, tt.actor, tt.target); got != tt.want {
+				t.Errorf("CanImpersonate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRequireCoach_ForbidsNonCoach(t *testing.T) {
 	nonCoach := &models.User{IsCoach: false}
 
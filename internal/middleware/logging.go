@@ -30,7 +30,15 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
-// RequestLogger logs each HTTP request with method, path, status code, and duration.
+// Unwrap exposes the underlying writer so http.ResponseController can reach
+// Flush for streaming responses.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RequestLogger logs each HTTP request with client IP, method, path, status
+// code, and duration. Install RealIP before it so the IP is the real client's
+// rather than the reverse proxy's.
 func RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -38,6 +46,6 @@ func RequestLogger(next http.Handler) http.Handler {
 
 		next.ServeHTTP(sw, r)
 
-		log.Printf("%s %s %d %s", r.Method, r.URL.Path, sw.status, time.Since(start).Round(time.Microsecond))
+		log.Printf("%s %s %s %d %s", ClientIP(r), r.Method, r.URL.Path, sw.status, time.Since(start).Round(time.Microsecond))
 	})
 }
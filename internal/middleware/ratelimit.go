@@ -1,9 +1,7 @@
 package middleware
 
 import (
-	"net"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 )
@@ -16,7 +14,6 @@ type RateLimiter struct {
 	visitors     map[string]*visitor
 	rate         int           // max attempts per window
 	window       time.Duration // time window
-	trustedNets  []*net.IPNet  // trusted proxy CIDRs
 	stopCleanup  chan struct{} // signal to stop the cleanup goroutine
 }
 
@@ -27,34 +24,13 @@ type visitor struct {
 
 // NewRateLimiter creates a rate limiter that allows `rate` requests per `window`
 // per IP address. For example, NewRateLimiter(10, time.Minute) allows 10
-// requests per minute per IP.
-//
-// trustedProxies is an optional list of CIDR strings (e.g., "127.0.0.1/32",
-// "10.0.0.0/8") identifying reverse proxies whose X-Forwarded-For headers
-// should be trusted. If empty, only RemoteAddr is used (safe default).
-func NewRateLimiter(rate int, window time.Duration, trustedProxies ...string) *RateLimiter {
-	var nets []*net.IPNet
-	for _, cidr := range trustedProxies {
-		// Allow bare IPs (e.g., "127.0.0.1") by appending /32 or /128.
-		if !strings.Contains(cidr, "/") {
-			if strings.Contains(cidr, ":") {
-				cidr += "/128"
-			} else {
-				cidr += "/32"
-			}
-		}
-		_, n, err := net.ParseCIDR(cidr)
-		if err != nil {
-			continue // skip malformed entries
-		}
-		nets = append(nets, n)
-	}
-
+// requests per minute per IP. Clients are identified by ClientIP, so
+// install RealIP first when running behind a reverse proxy.
+func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
 	rl := &RateLimiter{
 		visitors:    make(map[string]*visitor),
 		rate:        rate,
 		window:      window,
-		trustedNets: nets,
 		stopCleanup: make(chan struct{}),
 	}
 	// Background cleanup of stale entries every 5 minutes.
@@ -70,7 +46,7 @@ func (rl *RateLimiter) Stop() {
 // Limit wraps a handler and rejects requests that exceed the rate limit.
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := rl.extractIP(r)
+		ip := ClientIP(r)
 
 		if !rl.allow(ip) {
 			w.Header().Set("Retry-After", "60")
@@ -120,51 +96,3 @@ func (rl *RateLimiter) cleanup() {
 		}
 	}
 }
-
-// isTrustedProxy reports whether the given IP belongs to a trusted proxy CIDR.
-func (rl *RateLimiter) isTrustedProxy(ipStr string) bool {
-	ip := net.ParseIP(strings.TrimSpace(ipStr))
-	if ip == nil {
-		return false
-	}
-	for _, n := range rl.trustedNets {
-		if n.Contains(ip) {
-			return true
-		}
-	}
-	return false
-}
-
-// extractIP returns the real client IP address. It only trusts
-// X-Forwarded-For / X-Real-IP when the direct connection (RemoteAddr)
-// comes from a configured trusted proxy. This prevents IP spoofing attacks
-// where a client forges these headers to bypass rate limiting.
-func (rl *RateLimiter) extractIP(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-
-	// Only trust proxy headers when the request comes from a known proxy.
-	if len(rl.trustedNets) == 0 || !rl.isTrustedProxy(remoteIP) {
-		return remoteIP
-	}
-
-	// Trust X-Forwarded-For: take the rightmost entry that is NOT a trusted proxy.
-	// This is the last hop before the proxy chain, i.e., the real client.
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		parts := strings.Split(xff, ",")
-		for i := len(parts) - 1; i >= 0; i-- {
-			candidate := strings.TrimSpace(parts[i])
-			if candidate != "" && !rl.isTrustedProxy(candidate) {
-				return candidate
-			}
-		}
-	}
-
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return strings.TrimSpace(xri)
-	}
-
-	return remoteIP
-}
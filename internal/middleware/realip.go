@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIPContextKey stores the resolved client IP in request context.
+const ClientIPContextKey contextKey = "clientIP"
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs or bare IPs
+// (e.g. "127.0.0.1,10.0.0.0/8") identifying reverse proxies whose
+// X-Forwarded-For headers can be trusted. An empty list trusts no one.
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, cidr := range strings.Split(list, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		// Allow bare IPs (e.g., "127.0.0.1") by appending /32 or /128.
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("middleware: invalid trusted proxy %q: %w", cidr, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// RealIP resolves the real client IP for each request and stores it for
+// ClientIP. X-Forwarded-For and X-Real-IP are only believed when the direct
+// connection comes from one of the trusted proxies; otherwise a client could
+// forge them to dodge rate limits or falsify logs. With no trusted proxies
+// the connection's address is always used.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ClientIPContextKey, ip)))
+		})
+	}
+}
+
+// ClientIP returns the client IP resolved by RealIP, or the host part of
+// the connection's address if RealIP didn't run.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ClientIPContextKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// resolveClientIP returns the real client IP for r given the trusted
+// proxies.
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer := remoteIP(r)
+	if !isTrustedProxy(peer, trusted) {
+		return peer
+	}
+
+	// Take the rightmost X-Forwarded-For entry that is NOT a trusted proxy.
+	// This is the last hop before the proxy chain, i.e., the real client.
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		parts := strings.Split(xff, ",")
+		for i := len(parts) - 1; i >= 0; i-- {
+			candidate := strings.TrimSpace(parts[i])
+			if candidate != "" && !isTrustedProxy(candidate, trusted) {
+				return candidate
+			}
+		}
+	}
+
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
+		return xri
+	}
+
+	return peer
+}
+
+// isTrustedProxy reports whether the given IP belongs to a trusted proxy CIDR.
+func isTrustedProxy(ipStr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(strings.TrimSpace(ipStr))
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the host part of the request's remote address.
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
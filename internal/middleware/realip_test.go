@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := ParseTrustedProxies(" 127.0.0.1, 10.0.0.0/8,,::1 ")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(nets) != 3 {
+		t.Fatalf("expected 3 networks, got %d", len(nets))
+	}
+	if got := nets[0].String(); got != "127.0.0.1/32" {
+		t.Errorf("bare IPv4 parsed as %s, want 127.0.0.1/32", got)
+	}
+	if got := nets[2].String(); got != "::1/128" {
+		t.Errorf("bare IPv6 parsed as %s, want ::1/128", got)
+	}
+
+	if nets, err := ParseTrustedProxies(""); err != nil || len(nets) != 0 {
+		t.Errorf("empty list: got %v, %v", nets, err)
+	}
+	if _, err := ParseTrustedProxies("10.0.0.0/33"); err == nil {
+		t.Error("expected error for malformed CIDR")
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	trusted, _ := ParseTrustedProxies("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		trusted    bool
+		remoteAddr string
+		xff        string
+		xri        string
+		want       string
+	}{
+		{"no proxies configured ignores headers", false, "10.0.0.5:1234", "203.0.113.9", "", "10.0.0.5"},
+		{"untrusted peer can't spoof", true, "198.51.100.7:1234", "203.0.113.9", "203.0.113.10", "198.51.100.7"},
+		{"trusted peer uses forwarded client", true, "10.0.0.5:1234", "203.0.113.9", "", "203.0.113.9"},
+		{"rightmost untrusted hop wins", true, "10.0.0.5:1234", "1.2.3.4, 203.0.113.9, 10.0.0.2", "", "203.0.113.9"},
+		{"falls back to X-Real-IP", true, "10.0.0.5:1234", "", "203.0.113.10", "203.0.113.10"},
+		{"trusted peer without headers", true, "10.0.0.5:1234", "", "", "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nets := trusted
+			if !tt.trusted {
+				nets = nil
+			}
+			var got string
+			handler := RealIP(nets)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.xri != "" {
+				req.Header.Set("X-Real-IP", tt.xri)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP_WithoutRealIP(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "192.0.2.1:5555"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	if got := ClientIP(req); got != "192.0.2.1" {
+		t.Errorf("ClientIP = %q, want connection address", got)
+	}
+}
+
+func TestRateLimiter_KeysOnClientIP(t *testing.T) {
+	trusted, _ := ParseTrustedProxies("10.0.0.1")
+	rl := NewRateLimiter(1, time.Minute)
+	defer rl.Stop()
+	handler := RealIP(trusted)(rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	request := func(client string) int {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "10.0.0.1:443"
+		req.Header.Set("X-Forwarded-For", client)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := request("203.0.113.1"); code != http.StatusOK {
+		t.Fatalf("first request: got %d", code)
+	}
+	if code := request("203.0.113.1"); code != http.StatusTooManyRequests {
+		t.Errorf("second request from same client: got %d, want 429", code)
+	}
+	// Another client behind the same proxy has its own budget.
+	if code := request("203.0.113.2"); code != http.StatusOK {
+		t.Errorf("request from other client: got %d, want 200", code)
+	}
+}
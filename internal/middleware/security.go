@@ -7,7 +7,9 @@ import "net/http"
 //   - X-Frame-Options: DENY prevents clickjacking
 //   - X-Content-Type-Options: nosniff prevents MIME-type sniffing
 //   - Referrer-Policy: same-origin limits referrer leakage
-//   - Content-Security-Policy: restricts resource loading origins
+//   - Content-Security-Policy: restricts resource loading origins; frames
+//     are limited to the YouTube and Vimeo players used for exercise demos,
+//     and images from elsewhere to Gravatar (when enabled in settings)
 //
 // Note: script-src includes 'unsafe-inline' because the base layout has a
 // small inline <script> block for theme persistence and htmx configuration.
@@ -21,7 +23,8 @@ func SecurityHeaders(next http.Handler) http.Handler {
 				"style-src 'self' https://fonts.googleapis.com; "+
 				"font-src https://fonts.gstatic.com; "+
 				"script-src 'self' 'unsafe-inline'; "+
-				"img-src 'self' data:; "+
+				"img-src 'self' data: https://www.gravatar.com; "+
+				"frame-src https://www.youtube-nocookie.com https://player.vimeo.com; "+
 				"connect-src 'self'")
 		next.ServeHTTP(w, r)
 	})
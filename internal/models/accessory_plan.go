@@ -21,6 +21,7 @@ type AccessoryPlan struct {
 	TargetWeight sql.NullFloat64
 	Notes        sql.NullString
 	SortOrder    int
+	Superset     bool // performed back-to-back with the preceding accessory
 	Active       bool
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -54,7 +55,7 @@ func (ap *AccessoryPlan) RepRangeLabel() string {
 }
 
 // CreateAccessoryPlan inserts a new accessory plan entry.
-func CreateAccessoryPlan(db *sql.DB, athleteID int64, day int, exerciseID int64, targetSets, targetRepMin, targetRepMax int, targetWeight float64, notes string, sortOrder int) (*AccessoryPlan, error) {
+func CreateAccessoryPlan(db *sql.DB, athleteID int64, day int, exerciseID int64, targetSets, targetRepMin, targetRepMax int, targetWeight float64, notes string, sortOrder int, superset bool) (*AccessoryPlan, error) {
 	var tsVal sql.NullInt64
 	if targetSets > 0 {
 		tsVal = sql.NullInt64{Int64: int64(targetSets), Valid: true}
@@ -78,10 +79,10 @@ func CreateAccessoryPlan(db *sql.DB, athleteID int64, day int, exerciseID int64,
 
 	var id int64
 	err := db.QueryRow(
-		`INSERT INTO accessory_plans (athlete_id, day, exercise_id, target_sets, target_rep_min, target_rep_max, target_weight, notes, sort_order)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`INSERT INTO accessory_plans (athlete_id, day, exercise_id, target_sets, target_rep_min, target_rep_max, target_weight, notes, sort_order, superset)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 RETURNING id`,
-		athleteID, day, exerciseID, tsVal, minVal, maxVal, wVal, nVal, sortOrder,
+		athleteID, day, exerciseID, tsVal, minVal, maxVal, wVal, nVal, sortOrder, superset,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -97,13 +98,13 @@ func GetAccessoryPlanByID(db *sql.DB, id int64) (*AccessoryPlan, error) {
 	ap := &AccessoryPlan{}
 	err := db.QueryRow(
 		`SELECT ap.id, ap.athlete_id, ap.day, ap.exercise_id, ap.target_sets, ap.target_rep_min, ap.target_rep_max,
-		        ap.target_weight, ap.notes, ap.sort_order, ap.active, ap.created_at, ap.updated_at,
+		        ap.target_weight, ap.notes, ap.sort_order, ap.superset, ap.active, ap.created_at, ap.updated_at,
 		        e.name
 		 FROM accessory_plans ap
 		 JOIN exercises e ON e.id = ap.exercise_id
 		 WHERE ap.id = ?`, id,
 	).Scan(&ap.ID, &ap.AthleteID, &ap.Day, &ap.ExerciseID, &ap.TargetSets, &ap.TargetRepMin, &ap.TargetRepMax,
-		&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
+		&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Superset, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
 		&ap.ExerciseName)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -119,7 +120,7 @@ func GetAccessoryPlanByID(db *sql.DB, id int64) (*AccessoryPlan, error) {
 func ListAccessoryPlansForDay(db *sql.DB, athleteID int64, day int) ([]*AccessoryPlan, error) {
 	rows, err := db.Query(`
 		SELECT ap.id, ap.athlete_id, ap.day, ap.exercise_id, ap.target_sets, ap.target_rep_min, ap.target_rep_max,
-		       ap.target_weight, ap.notes, ap.sort_order, ap.active, ap.created_at, ap.updated_at,
+		       ap.target_weight, ap.notes, ap.sort_order, ap.superset, ap.active, ap.created_at, ap.updated_at,
 		       e.name
 		FROM accessory_plans ap
 		JOIN exercises e ON e.id = ap.exercise_id
@@ -134,7 +135,7 @@ func ListAccessoryPlansForDay(db *sql.DB, athleteID int64, day int) ([]*Accessor
 	for rows.Next() {
 		ap := &AccessoryPlan{}
 		if err := rows.Scan(&ap.ID, &ap.AthleteID, &ap.Day, &ap.ExerciseID, &ap.TargetSets, &ap.TargetRepMin, &ap.TargetRepMax,
-			&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
+			&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Superset, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
 			&ap.ExerciseName); err != nil {
 			return nil, fmt.Errorf("models: scan accessory plan: %w", err)
 		}
@@ -148,7 +149,7 @@ func ListAccessoryPlansForDay(db *sql.DB, athleteID int64, day int) ([]*Accessor
 func ListAllAccessoryPlans(db *sql.DB, athleteID int64) ([]*AccessoryPlan, error) {
 	rows, err := db.Query(`
 		SELECT ap.id, ap.athlete_id, ap.day, ap.exercise_id, ap.target_sets, ap.target_rep_min, ap.target_rep_max,
-		       ap.target_weight, ap.notes, ap.sort_order, ap.active, ap.created_at, ap.updated_at,
+		       ap.target_weight, ap.notes, ap.sort_order, ap.superset, ap.active, ap.created_at, ap.updated_at,
 		       e.name
 		FROM accessory_plans ap
 		JOIN exercises e ON e.id = ap.exercise_id
@@ -163,7 +164,7 @@ func ListAllAccessoryPlans(db *sql.DB, athleteID int64) ([]*AccessoryPlan, error
 	for rows.Next() {
 		ap := &AccessoryPlan{}
 		if err := rows.Scan(&ap.ID, &ap.AthleteID, &ap.Day, &ap.ExerciseID, &ap.TargetSets, &ap.TargetRepMin, &ap.TargetRepMax,
-			&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
+			&ap.TargetWeight, &ap.Notes, &ap.SortOrder, &ap.Superset, &ap.Active, &ap.CreatedAt, &ap.UpdatedAt,
 			&ap.ExerciseName); err != nil {
 			return nil, fmt.Errorf("models: scan accessory plan: %w", err)
 		}
@@ -173,7 +174,7 @@ func ListAllAccessoryPlans(db *sql.DB, athleteID int64) ([]*AccessoryPlan, error
 }
 
 // UpdateAccessoryPlan updates an existing accessory plan entry.
-func UpdateAccessoryPlan(db *sql.DB, id int64, targetSets, targetRepMin, targetRepMax int, targetWeight float64, notes string, sortOrder int) error {
+func UpdateAccessoryPlan(db *sql.DB, id int64, targetSets, targetRepMin, targetRepMax int, targetWeight float64, notes string, sortOrder int, superset bool) error {
 	var tsVal sql.NullInt64
 	if targetSets > 0 {
 		tsVal = sql.NullInt64{Int64: int64(targetSets), Valid: true}
@@ -196,8 +197,8 @@ func UpdateAccessoryPlan(db *sql.DB, id int64, targetSets, targetRepMin, targetR
 	}
 
 	result, err := db.Exec(
-		`UPDATE accessory_plans SET target_sets = ?, target_rep_min = ?, target_rep_max = ?, target_weight = ?, notes = ?, sort_order = ? WHERE id = ?`,
-		tsVal, minVal, maxVal, wVal, nVal, sortOrder, id,
+		`UPDATE accessory_plans SET target_sets = ?, target_rep_min = ?, target_rep_max = ?, target_weight = ?, notes = ?, sort_order = ?, superset = ? WHERE id = ?`,
+		tsVal, minVal, maxVal, wVal, nVal, sortOrder, superset, id,
 	)
 	if err != nil {
 		return fmt.Errorf("models: update accessory plan %d: %w", id, err)
@@ -8,10 +8,10 @@ import (
 func TestCreateAccessoryPlan(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex, _ := CreateExercise(db, "Bicep Curls", "", "", "", 0, false)
+	ex, _ := CreateExercise(db, "Bicep Curls", "", "", "", "", 0, false)
 
 	t.Run("basic create", func(t *testing.T) {
-		ap, err := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 25.0, "superset with pushdowns", 0)
+		ap, err := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 25.0, "superset with pushdowns", 0, false)
 		if err != nil {
 			t.Fatalf("create accessory plan: %v", err)
 		}
@@ -47,16 +47,34 @@ func TestCreateAccessoryPlan(t *testing.T) {
 		}
 	})
 
+	t.Run("superset flag", func(t *testing.T) {
+		ex3, _ := CreateExercise(db, "Hammer Curls", "", "", "", "", 0, false)
+		ap, err := CreateAccessoryPlan(db, a.ID, 1, ex3.ID, 3, 12, 12, 0, "", 2, true)
+		if err != nil {
+			t.Fatalf("create accessory plan: %v", err)
+		}
+		if !ap.Superset {
+			t.Error("expected superset = true")
+		}
+		if err := UpdateAccessoryPlan(db, ap.ID, 3, 12, 12, 0, "", 2, false); err != nil {
+			t.Fatalf("update accessory plan: %v", err)
+		}
+		got, _ := GetAccessoryPlanByID(db, ap.ID)
+		if got.Superset {
+			t.Error("expected superset = false after update")
+		}
+	})
+
 	t.Run("duplicate athlete-day-exercise", func(t *testing.T) {
-		_, err := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 4, 8, 12, 30.0, "", 0)
+		_, err := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 4, 8, 12, 30.0, "", 0, false)
 		if err == nil {
 			t.Fatal("expected error for duplicate, got nil")
 		}
 	})
 
 	t.Run("nullable fields omitted", func(t *testing.T) {
-		ex2, _ := CreateExercise(db, "Tricep Pushdowns", "", "", "", 0, false)
-		ap, err := CreateAccessoryPlan(db, a.ID, 1, ex2.ID, 0, 0, 0, 0, "", 1)
+		ex2, _ := CreateExercise(db, "Tricep Pushdowns", "", "", "", "", 0, false)
+		ap, err := CreateAccessoryPlan(db, a.ID, 1, ex2.ID, 0, 0, 0, 0, "", 1, false)
 		if err != nil {
 			t.Fatalf("create accessory plan: %v", err)
 		}
@@ -75,12 +93,12 @@ func TestCreateAccessoryPlan(t *testing.T) {
 func TestListAccessoryPlansForDay(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex1, _ := CreateExercise(db, "Curls", "", "", "", 0, false)
-	ex2, _ := CreateExercise(db, "Lateral Raises", "", "", "", 0, false)
+	ex1, _ := CreateExercise(db, "Curls", "", "", "", "", 0, false)
+	ex2, _ := CreateExercise(db, "Lateral Raises", "", "", "", "", 0, false)
 
-	CreateAccessoryPlan(db, a.ID, 1, ex1.ID, 3, 10, 15, 0, "", 1)
-	CreateAccessoryPlan(db, a.ID, 1, ex2.ID, 3, 12, 15, 0, "", 0)
-	CreateAccessoryPlan(db, a.ID, 2, ex1.ID, 4, 8, 12, 0, "", 0)
+	CreateAccessoryPlan(db, a.ID, 1, ex1.ID, 3, 10, 15, 0, "", 1, false)
+	CreateAccessoryPlan(db, a.ID, 1, ex2.ID, 3, 12, 15, 0, "", 0, false)
+	CreateAccessoryPlan(db, a.ID, 2, ex1.ID, 4, 8, 12, 0, "", 0, false)
 
 	plans, err := ListAccessoryPlansForDay(db, a.ID, 1)
 	if err != nil {
@@ -116,10 +134,10 @@ func TestListAccessoryPlansForDay(t *testing.T) {
 func TestUpdateAccessoryPlan(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex, _ := CreateExercise(db, "Curls", "", "", "", 0, false)
-	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 25.0, "original", 0)
+	ex, _ := CreateExercise(db, "Curls", "", "", "", "", 0, false)
+	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 25.0, "original", 0, false)
 
-	err := UpdateAccessoryPlan(db, ap.ID, 4, 8, 12, 30.0, "updated", 1)
+	err := UpdateAccessoryPlan(db, ap.ID, 4, 8, 12, 30.0, "updated", 1, false)
 	if err != nil {
 		t.Fatalf("update: %v", err)
 	}
@@ -139,7 +157,7 @@ func TestUpdateAccessoryPlan(t *testing.T) {
 	}
 
 	t.Run("not found", func(t *testing.T) {
-		err := UpdateAccessoryPlan(db, 99999, 0, 0, 0, 0, "", 0)
+		err := UpdateAccessoryPlan(db, 99999, 0, 0, 0, 0, "", 0, false)
 		if err != ErrNotFound {
 			t.Errorf("err = %v, want ErrNotFound", err)
 		}
@@ -149,8 +167,8 @@ func TestUpdateAccessoryPlan(t *testing.T) {
 func TestDeactivateAccessoryPlan(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex, _ := CreateExercise(db, "Curls", "", "", "", 0, false)
-	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 0, "", 0)
+	ex, _ := CreateExercise(db, "Curls", "", "", "", "", 0, false)
+	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 0, "", 0, false)
 
 	err := DeactivateAccessoryPlan(db, ap.ID)
 	if err != nil {
@@ -187,8 +205,8 @@ func TestDeactivateAccessoryPlan(t *testing.T) {
 func TestDeleteAccessoryPlan(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex, _ := CreateExercise(db, "Curls", "", "", "", 0, false)
-	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 0, "", 0)
+	ex, _ := CreateExercise(db, "Curls", "", "", "", "", 0, false)
+	ap, _ := CreateAccessoryPlan(db, a.ID, 1, ex.ID, 3, 10, 15, 0, "", 0, false)
 
 	err := DeleteAccessoryPlan(db, ap.ID)
 	if err != nil {
@@ -221,8 +239,8 @@ func TestMaxAccessoryDay(t *testing.T) {
 		t.Errorf("max day = %d, want 0", maxDay)
 	}
 
-	ex, _ := CreateExercise(db, "Curls", "", "", "", 0, false)
-	CreateAccessoryPlan(db, a.ID, 3, ex.ID, 0, 0, 0, 0, "", 0)
+	ex, _ := CreateExercise(db, "Curls", "", "", "", "", 0, false)
+	CreateAccessoryPlan(db, a.ID, 3, ex.ID, 0, 0, 0, 0, "", 0, false)
 
 	maxDay, err = MaxAccessoryDay(db, a.ID)
 	if err != nil {
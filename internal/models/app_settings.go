@@ -9,9 +9,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/hkdf"
 )
@@ -39,7 +42,7 @@ type SettingValue struct {
 }
 
 // CategoryOrder defines the display order for setting categories in the admin UI.
-var CategoryOrder = []string{"General", "Defaults", "Notifications", "AI Coach", "Maintenance"}
+var CategoryOrder = []string{"General", "Defaults", "Notifications", "AI Coach", "Maintenance", "Security"}
 
 // SettingsRegistry defines all known application settings.
 var SettingsRegistry = []SettingDefinition{
@@ -49,6 +52,17 @@ var SettingsRegistry = []SettingDefinition{
 		Label: "Application Name", Description: "Custom name shown in page titles and navigation",
 		FieldType: "text", Category: "General",
 	},
+	{
+		Key: "app.base_url", EnvVar: "REPLOG_BASE_URL", Default: "",
+		Label: "Base URL", Description: "External URL of this server (e.g. https://replog.example.com), used to build links in emails. Password reset emails are off until this is set",
+		FieldType: "text", Category: "General",
+	},
+	{
+		Key: "avatars.gravatar", EnvVar: "REPLOG_GRAVATAR", Default: "false",
+		Label: "Gravatar", Description: "Show the Gravatar for users without an uploaded avatar. Browsers send a hash of the user's email to gravatar.com; when off, a generated identicon is shown instead",
+		FieldType: "select", Options: []string{"false", "true"},
+		Category: "General",
+	},
 	// --- Defaults ---
 	{
 		Key: "defaults.weight_unit", EnvVar: "", Default: "lbs",
@@ -72,6 +86,11 @@ var SettingsRegistry = []SettingDefinition{
 		Label: "Default Rest Timer", Description: "Default rest time in seconds when an exercise doesn't specify one (e.g. 60, 90, 120)",
 		FieldType: "number", Category: "Defaults",
 	},
+	{
+		Key: "defaults.stall_entries", EnvVar: "", Default: "3",
+		Label: "Stall Detection Window", Description: "Flag a lift as stalled after this many consecutive training max entries without an increase, combined with missed AMRAP targets (2–12)",
+		FieldType: "number", Category: "Defaults",
+	},
 	// --- Notifications ---
 	{
 		Key: "smtp.host", EnvVar: "REPLOG_SMTP_HOST", Default: "",
@@ -107,7 +126,7 @@ var SettingsRegistry = []SettingDefinition{
 	{
 		Key: "llm.provider", EnvVar: "REPLOG_LLM_PROVIDER", Default: "",
 		Label: "Provider", Description: "AI provider for program generation",
-		FieldType: "select", Options: []string{"", "openai", "anthropic", "ollama"},
+		FieldType: "select", Options: []string{"", "openai", "anthropic", "ollama", "openai_compatible"},
 		Category: "AI Coach",
 	},
 	{
@@ -117,12 +136,12 @@ var SettingsRegistry = []SettingDefinition{
 	},
 	{
 		Key: "llm.api_key", EnvVar: "REPLOG_LLM_API_KEY", Default: "",
-		Label: "API Key", Description: "Provider API key (not needed for Ollama)",
+		Label: "API Key", Description: "Provider API key (not needed for Ollama; optional for OpenAI-compatible servers)",
 		FieldType: "password", Category: "AI Coach", Sensitive: true,
 	},
 	{
 		Key: "llm.base_url", EnvVar: "REPLOG_LLM_BASE_URL", Default: "",
-		Label: "Base URL", Description: "Custom API endpoint (required for Ollama, optional for others)",
+		Label: "Base URL", Description: "Custom API endpoint (required for Ollama; for OpenAI-compatible servers include /v1, e.g. http://localhost:1234/v1)",
 		FieldType: "text", Category: "AI Coach",
 	},
 	{
@@ -132,9 +151,24 @@ var SettingsRegistry = []SettingDefinition{
 	},
 	{
 		Key: "llm.max_tokens", EnvVar: "REPLOG_LLM_MAX_TOKENS", Default: "32768",
-		Label: "Max Tokens", Description: "Maximum output tokens for generation (4096–65536)",
+		Label: "Max Tokens", Description: "Maximum output tokens for generation (4096–65536). Local models need this below their context window.",
+		FieldType: "number", Category: "AI Coach",
+	},
+	{
+		Key: "llm.context_budget", EnvVar: "REPLOG_LLM_CONTEXT_BUDGET", Default: "30000",
+		Label: "Context Budget", Description: "Approximate token limit for the athlete context. Older workouts, notes, and reference programs are dropped to fit (0 = no limit).",
+		FieldType: "number", Category: "AI Coach",
+	},
+	{
+		Key: "llm.monthly_token_cap", EnvVar: "REPLOG_LLM_MONTHLY_TOKEN_CAP", Default: "0",
+		Label: "Monthly Token Cap", Description: "Block new generations once this many tokens have been used in the current calendar month (0 = no cap)",
 		FieldType: "number", Category: "AI Coach",
 	},
+	{
+		Key: "llm.model_prices", EnvVar: "REPLOG_LLM_MODEL_PRICES", Default: "",
+		Label: "Model Prices", Description: "USD per million tokens, one model per line (e.g. gpt-4o=5.00). Used to estimate monthly spend.",
+		FieldType: "textarea", Category: "AI Coach",
+	},
 	{
 		Key: "llm.system_prompt_override", EnvVar: "", Default: "",
 		Label: "System Prompt Override", Description: "Replace the default system prompt (leave empty to use built-in prompt)",
@@ -151,6 +185,52 @@ var SettingsRegistry = []SettingDefinition{
 		Label: "Notification Retention (days)", Description: "Read notifications older than this are pruned (1–365 days)",
 		FieldType: "number", Category: "Maintenance",
 	},
+	// --- Security ---
+	{
+		Key: "security.require_totp", EnvVar: "REPLOG_REQUIRE_TOTP", Default: "false",
+		Label: "Require Two-Factor for Coaches", Description: "Coaches and admins who sign in with a password must set up an authenticator app",
+		FieldType: "select", Options: []string{"false", "true"},
+		Category: "Security",
+	},
+	{
+		Key: "security.password_min_length", EnvVar: "REPLOG_PASSWORD_MIN_LENGTH", Default: "8",
+		Label: "Minimum Password Length", Description: "Shortest password accepted when creating users or changing passwords (6–128)",
+		FieldType: "number", Category: "Security",
+	},
+	{
+		Key: "security.password_complexity", EnvVar: "REPLOG_PASSWORD_COMPLEXITY", Default: "none",
+		Label: "Password Complexity", Description: "none: length only. mixed: at least three of lowercase, uppercase, digits, and symbols",
+		FieldType: "select", Options: []string{"none", "mixed"},
+		Category: "Security",
+	},
+	{
+		Key: "security.password_breach_check", EnvVar: "REPLOG_PASSWORD_BREACH_CHECK", Default: "false",
+		Label: "Check Breached Passwords", Description: "Reject passwords found in the HaveIBeenPwned breach list. Only a 5-character hash prefix is sent; if the service is unreachable the password is allowed",
+		FieldType: "select", Options: []string{"false", "true"},
+		Category: "Security",
+	},
+	{
+		Key: "security.session_lifetime_days", EnvVar: "REPLOG_SESSION_LIFETIME_DAYS", Default: "30",
+		Label: "Session Lifetime (days)", Description: "How long a login lasts before signing in again (1–365). Lowering it also shortens existing sessions",
+		FieldType: "number", Category: "Security",
+	},
+	{
+		Key: "security.coach_session_lifetime_hours", EnvVar: "REPLOG_COACH_SESSION_LIFETIME_HOURS", Default: "0",
+		Label: "Coach Session Lifetime (hours)", Description: "Shorter login lifetime for coaches and admins (1–8760 hours; 0 = same as everyone)",
+		FieldType: "number", Category: "Security",
+	},
+	{
+		Key: "security.cookie_samesite", EnvVar: "REPLOG_COOKIE_SAMESITE", Default: "lax",
+		Label: "Session Cookie SameSite", Description: "lax: sent when following links from other sites. strict: never sent cross-site, so those links open signed out. Applies after restart",
+		FieldType: "select", Options: []string{"lax", "strict"},
+		Category: "Security",
+	},
+	{
+		Key: "security.secure_cookies", EnvVar: "REPLOG_SECURE_COOKIES", Default: "auto",
+		Label: "Secure Session Cookie", Description: "Only send the session cookie over HTTPS. auto: on when REPLOG_BASE_URL is https. Applies after restart",
+		FieldType: "select", Options: []string{"auto", "true", "false"},
+		Category: "Security",
+	},
 }
 
 // GetSetting returns a configuration value using the resolution chain:
@@ -263,6 +343,51 @@ func IsAICoachConfigured(db *sql.DB) bool {
 	return GetSetting(db, "llm.provider") != ""
 }
 
+// GetBaseURL returns the configured external base URL without a trailing
+// slash, or "" if none is set.
+func GetBaseURL(db *sql.DB) string {
+	return strings.TrimRight(GetSetting(db, "app.base_url"), "/")
+}
+
+// IsEmailLinksConfigured reports whether links that prove control of an
+// email address (password reset, email verification) can be sent. Both SMTP
+// and the base URL are required; links are never built from request headers.
+func IsEmailLinksConfigured(db *sql.DB) bool {
+	return GetSetting(db, "smtp.host") != "" && GetBaseURL(db) != ""
+}
+
+// GetMonthlyTokenCap returns the monthly AI Coach token cap, or 0 when
+// generations are uncapped.
+func GetMonthlyTokenCap(db *sql.DB) int64 {
+	if v := GetSetting(db, "llm.monthly_token_cap"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// GetModelPrice returns the configured USD price per million tokens for a
+// model from the "llm.model_prices" setting, or 0 if no price is set.
+func GetModelPrice(db *sql.DB, model string) float64 {
+	for _, line := range strings.Split(GetSetting(db, "llm.model_prices"), "\n") {
+		name, price, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(name) != model {
+			continue
+		}
+		if p, err := strconv.ParseFloat(strings.TrimSpace(price), 64); err == nil && p >= 0 {
+			return p
+		}
+	}
+	return 0
+}
+
+// GenerationCostUSD estimates the cost of a generation from its token count
+// and the model's configured price.
+func GenerationCostUSD(db *sql.DB, model string, tokensUsed int) float64 {
+	return float64(tokensUsed) / 1_000_000 * GetModelPrice(db, model)
+}
+
 // GetDefaultWeightUnit returns the configured default weight unit from app settings,
 // falling back to the hardcoded constant.
 func GetDefaultWeightUnit(db *sql.DB) string {
@@ -298,6 +423,17 @@ func GetDefaultRestSeconds(db *sql.DB) int {
 	return 90
 }
 
+// GetStallEntries returns how many consecutive flat training max entries
+// DetectStalls requires before flagging a lift.
+func GetStallEntries(db *sql.DB) int {
+	if v := GetSetting(db, "defaults.stall_entries"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 2 && n <= 12 {
+			return n
+		}
+	}
+	return 3
+}
+
 // GetAppName returns the configured application name from app settings.
 func GetAppName(db *sql.DB) string {
 	if v := GetSetting(db, "app.name"); v != "" {
@@ -534,3 +670,103 @@ func decryptValue(encoded string) (string, error) {
 
 	return string(plaintext), nil
 }
+
+// GetSessionLifetime returns how long a login lasts.
+func GetSessionLifetime(db *sql.DB) time.Duration {
+	if v := GetSetting(db, "security.session_lifetime_days"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= 365 {
+			return time.Duration(n) * 24 * time.Hour
+		}
+	}
+	return 30 * 24 * time.Hour
+}
+
+// GetCoachSessionLifetime returns the login lifetime for coaches and admins,
+// or 0 if they use the standard lifetime.
+func GetCoachSessionLifetime(db *sql.DB) time.Duration {
+	if v := GetSetting(db, "security.coach_session_lifetime_hours"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= 8760 {
+			return time.Duration(n) * time.Hour
+		}
+	}
+	return 0
+}
+
+// SessionLifetimeFor returns how long a login by u lasts: the coach lifetime
+// for coaches and admins when it is set and shorter, otherwise the standard
+// lifetime.
+func SessionLifetimeFor(db *sql.DB, u *User) time.Duration {
+	lifetime := GetSessionLifetime(db)
+	if u.IsCoach || u.IsAdmin {
+		if coach := GetCoachSessionLifetime(db); coach > 0 && coach < lifetime {
+			return coach
+		}
+	}
+	return lifetime
+}
+
+// GetCookieSameSite returns the session cookie's SameSite policy, "lax" or
+// "strict".
+func GetCookieSameSite(db *sql.DB) string {
+	if v := GetSetting(db, "security.cookie_samesite"); v == "strict" {
+		return v
+	}
+	return "lax"
+}
+
+// GetSecureCookies returns whether the session cookie is Secure: "true",
+// "false", or "auto" to follow the base URL's scheme. Any value other than
+// "true" or "auto" means false, as REPLOG_SECURE_COOKIES always has.
+func GetSecureCookies(db *sql.DB) string {
+	switch v := GetSetting(db, "security.secure_cookies"); v {
+	case "true", "auto":
+		return v
+	}
+	return "false"
+}
+
+// ValidateSetting checks a new value for settings with restricted values,
+// returning an error worded to follow the setting's label. Settings without
+// rules accept any value.
+func ValidateSetting(key, value string) error {
+	def := findDefinition(key)
+	if def == nil {
+		return fmt.Errorf("models: unknown setting key %q", key)
+	}
+	if def.FieldType == "select" && value != "" && !slices.Contains(def.Options, value) {
+		return fmt.Errorf("must be one of %s", strings.Join(def.Options, ", "))
+	}
+	switch key {
+	case "app.base_url":
+		return validateBaseURL(value)
+	case "security.session_lifetime_days":
+		return validateIntRange(value, 1, 365)
+	case "security.coach_session_lifetime_hours":
+		return validateIntRange(value, 0, 8760)
+	}
+	return nil
+}
+
+// validateBaseURL checks that value is empty or an absolute http(s) URL.
+func validateBaseURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http:// or https:// URL")
+	}
+	return nil
+}
+
+// validateIntRange checks that value is empty (the default) or a whole
+// number between lo and hi.
+func validateIntRange(value string, lo, hi int) error {
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < lo || n > hi {
+		return fmt.Errorf("must be a whole number from %d to %d", lo, hi)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestGetSetting_EnvOverride(t *testing.T) {
@@ -403,4 +404,85 @@ func TestGetMaintenanceRetentionDays(t *testing.T) {
 	if got := GetMaintenanceRetentionDays(db); got != 90 {
 		t.Errorf("invalid retention fallback = %d, want 90", got)
 	}
-}
\ No newline at end of file
+}
+func TestSessionLifetimeFor(t *testing.T) {
+	db := testDB(t)
+	athlete := &User{}
+	coach := &User{IsCoach: true}
+	admin := &User{IsAdmin: true}
+
+	// Defaults: 30 days for everyone.
+	for _, u := range []*User{athlete, coach, admin} {
+		if got := SessionLifetimeFor(db, u); got != 30*24*time.Hour {
+			t.Errorf("default lifetime = %v, want 720h", got)
+		}
+	}
+
+	SetSetting(db, "security.session_lifetime_days", "14")
+	SetSetting(db, "security.coach_session_lifetime_hours", "12")
+	if got := SessionLifetimeFor(db, athlete); got != 14*24*time.Hour {
+		t.Errorf("athlete lifetime = %v, want 336h", got)
+	}
+	if got := SessionLifetimeFor(db, coach); got != 12*time.Hour {
+		t.Errorf("coach lifetime = %v, want 12h", got)
+	}
+	if got := SessionLifetimeFor(db, admin); got != 12*time.Hour {
+		t.Errorf("admin lifetime = %v, want 12h", got)
+	}
+
+	// A coach lifetime longer than the standard one doesn't extend it.
+	SetSetting(db, "security.coach_session_lifetime_hours", "720")
+	if got := SessionLifetimeFor(db, coach); got != 14*24*time.Hour {
+		t.Errorf("coach lifetime = %v, want 336h", got)
+	}
+
+	// Env overrides the stored value.
+	t.Setenv("REPLOG_SESSION_LIFETIME_DAYS", "1")
+	if got := SessionLifetimeFor(db, athlete); got != 24*time.Hour {
+		t.Errorf("env lifetime = %v, want 24h", got)
+	}
+}
+
+func TestGetSecureCookies(t *testing.T) {
+	db := testDB(t)
+
+	if got := GetSecureCookies(db); got != "auto" {
+		t.Errorf("default = %q, want auto", got)
+	}
+	SetSetting(db, "security.secure_cookies", "true")
+	if got := GetSecureCookies(db); got != "true" {
+		t.Errorf("stored = %q, want true", got)
+	}
+	// Env wins, and anything but true/auto means off as before.
+	t.Setenv("REPLOG_SECURE_COOKIES", "0")
+	if got := GetSecureCookies(db); got != "false" {
+		t.Errorf("env = %q, want false", got)
+	}
+}
+
+func TestValidateSetting(t *testing.T) {
+	tests := []struct {
+		key, value string
+		ok         bool
+	}{
+		{"security.session_lifetime_days", "30", true},
+		{"security.session_lifetime_days", "0", false},
+		{"security.session_lifetime_days", "366", false},
+		{"security.session_lifetime_days", "1.5", false},
+		{"security.coach_session_lifetime_hours", "0", true},
+		{"security.coach_session_lifetime_hours", "8761", false},
+		{"security.cookie_samesite", "strict", true},
+		{"security.cookie_samesite", "none", false},
+		{"security.secure_cookies", "auto", true},
+		{"app.name", "anything", true},
+		{"app.base_url", "https://replog.example.com", true},
+		{"app.base_url", "replog.example.com", false},
+		{"app.base_url", "javascript:alert(1)", false},
+	}
+	for _, tt := range tests {
+		err := ValidateSetting(tt.key, tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateSetting(%q, %q) = %v, want ok=%v", tt.key, tt.value, err, tt.ok)
+		}
+	}
+}
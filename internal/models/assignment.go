@@ -120,12 +120,14 @@ func ListActiveAssignments(db *sql.DB, athleteID int64) ([]*AthleteExercise, err
 	return assignments, nil
 }
 
-// ListUnassignedExercises returns exercises not actively assigned to an athlete.
+// ListUnassignedExercises returns non-archived exercises not actively
+// assigned to an athlete.
 func ListUnassignedExercises(db *sql.DB, athleteID int64) ([]*Exercise, error) {
 	rows, err := db.Query(`
 		SELECT e.id, e.name, e.tier, e.form_notes, e.demo_url, e.rest_seconds, e.featured, e.created_at, e.updated_at
 		FROM exercises e
-		WHERE e.id NOT IN (
+		WHERE e.archived = 0
+		  AND e.id NOT IN (
 			SELECT exercise_id FROM athlete_exercises
 			WHERE athlete_id = ? AND active = 1
 		)
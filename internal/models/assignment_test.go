@@ -9,7 +9,7 @@ func TestAssignmentLifecycle(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Assign Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Assign Exercise", "foundational", "", "", 0)
+	e, _ := CreateExercise(db, "Assign Exercise", "foundational", "", "", "", 0)
 
 	t.Run("assign exercise", func(t *testing.T) {
 		ae, err := AssignExercise(db, a.ID, e.ID, 0)
@@ -73,8 +73,8 @@ func TestListDeactivatedAssignments(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Deact Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e1, _ := CreateExercise(db, "Deact Ex 1", "", "", "", 0)
-	e2, _ := CreateExercise(db, "Deact Ex 2", "", "", "", 0)
+	e1, _ := CreateExercise(db, "Deact Ex 1", "", "", "", "", 0)
+	e2, _ := CreateExercise(db, "Deact Ex 2", "", "", "", "", 0)
 
 	// Assign both, deactivate e1 only.
 	ae1, _ := AssignExercise(db, a.ID, e1.ID, 0)
@@ -97,8 +97,8 @@ func TestListUnassignedExercises(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Unassigned Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e1, _ := CreateExercise(db, "Assigned Ex", "", "", "", 0)
-	CreateExercise(db, "Free Ex", "", "", "", 0)
+	e1, _ := CreateExercise(db, "Assigned Ex", "", "", "", "", 0)
+	CreateExercise(db, "Free Ex", "", "", "", "", 0)
 
 	AssignExercise(db, a.ID, e1.ID, 0)
 
@@ -119,7 +119,7 @@ func TestListAssignedAthletes(t *testing.T) {
 
 	a1, _ := CreateAthlete(db, "Alice", "", "", "", "", "", "", sql.NullInt64{}, true)
 	a2, _ := CreateAthlete(db, "Bob", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Shared Exercise", "", "", "", 0)
+	e, _ := CreateExercise(db, "Shared Exercise", "", "", "", "", 0)
 
 	AssignExercise(db, a1.ID, e.ID, 0)
 	AssignExercise(db, a2.ID, e.ID, 0)
@@ -151,7 +151,7 @@ func TestListAssignedAthletes(t *testing.T) {
 	})
 
 	t.Run("empty for unassigned exercise", func(t *testing.T) {
-		e2, _ := CreateExercise(db, "Lonely Exercise", "", "", "", 0)
+		e2, _ := CreateExercise(db, "Lonely Exercise", "", "", "", "", 0)
 		athletes, err := ListAssignedAthletes(db, e2.ID)
 		if err != nil {
 			t.Fatalf("list assigned: %v", err)
@@ -166,17 +166,17 @@ func TestAssignProgramExercises(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Program Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	ex1, _ := CreateExercise(db, "Squat", "", "", "", 0)
-	ex2, _ := CreateExercise(db, "Bench", "", "", "", 0)
-	ex3, _ := CreateExercise(db, "Deadlift", "", "", "", 0)
+	ex1, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	ex2, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	ex3, _ := CreateExercise(db, "Deadlift", "", "", "", "", 0)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 3, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 3, false, "", 0, "")
 	reps5 := 5
 	pct75 := 75.0
-	CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 1, &reps5, &pct75, nil, 0, "reps", "")
-	CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 2, &reps5, &pct75, nil, 0, "reps", "") // duplicate exercise
-	CreatePrescribedSet(db, tmpl.ID, ex2.ID, 1, 2, 1, &reps5, &pct75, nil, 0, "reps", "")
-	CreatePrescribedSet(db, tmpl.ID, ex3.ID, 1, 3, 1, &reps5, &pct75, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 1, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, ex1.ID, 1, 1, 2, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "") // duplicate exercise
+	CreatePrescribedSet(db, tmpl.ID, ex2.ID, 1, 2, 1, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, ex3.ID, 1, 3, 1, &reps5, nil, &pct75, nil, nil, nil, 0, "reps", "")
 
 	t.Run("assigns all program exercises", func(t *testing.T) {
 		n, err := AssignProgramExercises(db, athlete.ID, tmpl.ID)
@@ -222,7 +222,7 @@ func TestAssignProgramExercises(t *testing.T) {
 	})
 
 	t.Run("empty program template", func(t *testing.T) {
-		emptyTmpl, _ := CreateProgramTemplate(db, nil, "Empty Program", "", 1, 1, false, "")
+		emptyTmpl, _ := CreateProgramTemplate(db, nil, "Empty Program", "", 1, 1, false, "", 0, "")
 		n, err := AssignProgramExercises(db, athlete.ID, emptyTmpl.ID)
 		if err != nil {
 			t.Fatalf("auto-assign empty: %v", err)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,10 @@ type Athlete struct {
 	Gender            sql.NullString // "male" or "female"
 	CoachID           sql.NullInt64
 	TrackBodyWeight   bool
+	BarWeight         sql.NullFloat64 // NULL = DefaultBarWeight
+	Plates            sql.NullString  // comma-separated; NULL = DefaultPlates
+	GoalWeight        sql.NullFloat64 // target body weight; NULL = none
+	Archived          bool            // hidden from lists; history is kept
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	ActiveAssignments int // populated by list queries
@@ -35,6 +40,7 @@ type AthleteCardInfo struct {
 	WeekStreak        int            // consecutive weeks with a workout
 	BWTrend           string         // "up", "down", "flat", or "" if insufficient data
 	TrackBodyWeight   bool           // whether body weight tracking is enabled
+	Archived          bool
 }
 
 // CreateAthlete inserts a new athlete. coachID links the athlete to a coach.
@@ -82,18 +88,51 @@ func CreateAthlete(db *sql.DB, name, tier, notes, goal, dateOfBirth, grade, gend
 	return GetAthleteByID(db, id)
 }
 
+// DefaultBarWeight is the barbell weight assumed when an athlete has none set.
+const DefaultBarWeight = 45.0
+
+// BarWeightOrDefault returns the athlete's barbell weight, falling back to
+// DefaultBarWeight.
+func (a *Athlete) BarWeightOrDefault() float64 {
+	if a.BarWeight.Valid && a.BarWeight.Float64 > 0 {
+		return a.BarWeight.Float64
+	}
+	return DefaultBarWeight
+}
+
+// AthleteAge returns the age in whole years on now for a YYYY-MM-DD (or
+// RFC 3339) date of birth. ok is false when dob is empty, invalid, or in the
+// future, so callers can omit the age.
+func AthleteAge(dob string, now time.Time) (age int, ok bool) {
+	if dob == "" {
+		return 0, false
+	}
+	born, err := time.Parse("2006-01-02", normalizeDate(dob))
+	if err != nil {
+		return 0, false
+	}
+	age = now.Year() - born.Year()
+	if now.Month() < born.Month() || (now.Month() == born.Month() && now.Day() < born.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0, false
+	}
+	return age, true
+}
+
 // GetAthleteByID retrieves an athlete by primary key.
 func GetAthleteByID(db *sql.DB, id int64) (*Athlete, error) {
 	a := &Athlete{}
 	err := db.QueryRow(
 		`SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
-		        a.coach_id, a.track_body_weight,
+		        a.coach_id, a.track_body_weight, a.bar_weight, a.plates, a.goal_weight, a.archived,
 		        a.created_at, a.updated_at,
 		        COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
 		                  WHERE ae.athlete_id = a.id AND ae.active = 1), 0)
 		 FROM athletes a WHERE a.id = ?`, id,
 	).Scan(&a.ID, &a.Name, &a.Tier, &a.Notes, &a.Goal, &a.DateOfBirth, &a.Grade, &a.Gender,
-		&a.CoachID, &a.TrackBodyWeight,
+		&a.CoachID, &a.TrackBodyWeight, &a.BarWeight, &a.Plates, &a.GoalWeight, &a.Archived,
 		&a.CreatedAt, &a.UpdatedAt, &a.ActiveAssignments)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -180,6 +219,20 @@ func DeleteAthlete(db *sql.DB, id int64) error {
 	return nil
 }
 
+// SetAthleteArchived archives or restores an athlete. Archiving hides the
+// athlete from lists without touching their history.
+func SetAthleteArchived(db *sql.DB, id int64, archived bool) error {
+	result, err := db.Exec(`UPDATE athletes SET archived = ? WHERE id = ?`, archived, id)
+	if err != nil {
+		return fmt.Errorf("models: set athlete %d archived: %w", id, err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // NextTier returns the tier that follows the given tier value.
 // Returns ("", false) if the athlete is already at the highest tier or has no tier.
 func NextTier(current string) (string, bool) {
@@ -218,34 +271,71 @@ func PromoteAthlete(db *sql.DB, id int64) (*Athlete, error) {
 	return GetAthleteByID(db, id)
 }
 
+// SetAthleteBarWeight updates the barbell weight used for warm-up
+// suggestions. Pass 0 to clear it and use DefaultBarWeight.
+func SetAthleteBarWeight(db *sql.DB, id int64, barWeight float64) error {
+	var val sql.NullFloat64
+	if barWeight > 0 {
+		val = sql.NullFloat64{Float64: barWeight, Valid: true}
+	}
+	result, err := db.Exec(`UPDATE athletes SET bar_weight = ? WHERE id = ?`, val, id)
+	if err != nil {
+		return fmt.Errorf("models: set bar weight for athlete %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetAthleteGoalWeight updates the athlete's target body weight. Pass 0 to
+// clear it.
+func SetAthleteGoalWeight(db *sql.DB, id int64, goalWeight float64) error {
+	var val sql.NullFloat64
+	if goalWeight > 0 {
+		val = sql.NullFloat64{Float64: goalWeight, Valid: true}
+	}
+	result, err := db.Exec(`UPDATE athletes SET goal_weight = ? WHERE id = ?`, val, id)
+	if err != nil {
+		return fmt.Errorf("models: set goal weight for athlete %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // ListAthletes returns athletes with their active assignment count.
 // If coachID is valid, only returns athletes belonging to that coach.
 // Pass sql.NullInt64{} (invalid) to return all athletes (admin view).
-func ListAthletes(db *sql.DB, coachID sql.NullInt64) ([]*Athlete, error) {
-	var rows *sql.Rows
-	var err error
+// Archived athletes are left out unless includeArchived is true.
+func ListAthletes(db *sql.DB, coachID sql.NullInt64, includeArchived bool) ([]*Athlete, error) {
+	var conds []string
+	var args []any
 	if coachID.Valid {
-		rows, err = db.Query(`
-			SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
-			       a.coach_id, a.track_body_weight,
-			       a.created_at, a.updated_at,
-			       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
-			                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments
-			FROM athletes a
-			WHERE a.coach_id = ?
-			ORDER BY a.name COLLATE NOCASE
-			LIMIT 100`, coachID.Int64)
-	} else {
-		rows, err = db.Query(`
-			SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
-			       a.coach_id, a.track_body_weight,
-			       a.created_at, a.updated_at,
-			       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
-			                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments
-			FROM athletes a
-			ORDER BY a.name COLLATE NOCASE
-			LIMIT 100`)
+		conds = append(conds, "a.coach_id = ?")
+		args = append(args, coachID.Int64)
+	}
+	if !includeArchived {
+		conds = append(conds, "a.archived = 0")
 	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	rows, err := db.Query(`
+		SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
+		       a.coach_id, a.track_body_weight, a.bar_weight, a.plates, a.goal_weight, a.archived,
+		       a.created_at, a.updated_at,
+		       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
+		                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments
+		FROM athletes a
+		`+where+`
+		ORDER BY a.name COLLATE NOCASE
+		LIMIT 100`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("models: list athletes: %w", err)
 	}
@@ -255,7 +345,7 @@ func ListAthletes(db *sql.DB, coachID sql.NullInt64) ([]*Athlete, error) {
 	for rows.Next() {
 		a := &Athlete{}
 		if err := rows.Scan(&a.ID, &a.Name, &a.Tier, &a.Notes, &a.Goal, &a.DateOfBirth, &a.Grade, &a.Gender,
-			&a.CoachID, &a.TrackBodyWeight,
+			&a.CoachID, &a.TrackBodyWeight, &a.BarWeight, &a.Plates, &a.GoalWeight, &a.Archived,
 			&a.CreatedAt, &a.UpdatedAt, &a.ActiveAssignments); err != nil {
 			return nil, fmt.Errorf("models: scan athlete: %w", err)
 		}
@@ -273,7 +363,7 @@ func ListAthletes(db *sql.DB, coachID sql.NullInt64) ([]*Athlete, error) {
 func ListAvailableAthletes(db *sql.DB, exceptAthleteID int64) ([]*Athlete, error) {
 	rows, err := db.Query(`
 		SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
-		       a.coach_id, a.track_body_weight,
+		       a.coach_id, a.track_body_weight, a.bar_weight, a.plates, a.goal_weight, a.archived,
 		       a.created_at, a.updated_at,
 		       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
 		                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments
@@ -291,7 +381,7 @@ func ListAvailableAthletes(db *sql.DB, exceptAthleteID int64) ([]*Athlete, error
 	for rows.Next() {
 		a := &Athlete{}
 		if err := rows.Scan(&a.ID, &a.Name, &a.Tier, &a.Notes, &a.Goal, &a.DateOfBirth, &a.Grade, &a.Gender,
-			&a.CoachID, &a.TrackBodyWeight,
+			&a.CoachID, &a.TrackBodyWeight, &a.BarWeight, &a.Plates, &a.GoalWeight, &a.Archived,
 			&a.CreatedAt, &a.UpdatedAt, &a.ActiveAssignments); err != nil {
 			return nil, fmt.Errorf("models: scan available athlete: %w", err)
 		}
@@ -307,31 +397,32 @@ func ListAvailableAthletes(db *sql.DB, exceptAthleteID int64) ([]*Athlete, error
 // Includes last workout date, week streak, and body weight trend.
 // If coachID is valid, only returns athletes belonging to that coach.
 // Pass sql.NullInt64{} (invalid) to return all athletes (admin view).
-func ListAthleteCards(db *sql.DB, coachID sql.NullInt64) ([]*AthleteCardInfo, error) {
-	var rows *sql.Rows
-	var err error
+// Archived athletes are left out unless includeArchived is true.
+func ListAthleteCards(db *sql.DB, coachID sql.NullInt64, includeArchived bool) ([]*AthleteCardInfo, error) {
+	var conds []string
+	var args []any
 	if coachID.Valid {
-		rows, err = db.Query(`
-			SELECT a.id, a.name, a.tier,
-			       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
-			                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments,
-			       (SELECT date(w.date) FROM workouts w WHERE w.athlete_id = a.id ORDER BY w.date DESC LIMIT 1) AS last_workout,
-			       a.track_body_weight
-			FROM athletes a
-			WHERE a.coach_id = ?
-			ORDER BY a.name COLLATE NOCASE
-			LIMIT 100`, coachID.Int64)
-	} else {
-		rows, err = db.Query(`
-			SELECT a.id, a.name, a.tier,
-			       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
-			                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments,
-			       (SELECT date(w.date) FROM workouts w WHERE w.athlete_id = a.id ORDER BY w.date DESC LIMIT 1) AS last_workout,
-			       a.track_body_weight
-			FROM athletes a
-			ORDER BY a.name COLLATE NOCASE
-			LIMIT 100`)
+		conds = append(conds, "a.coach_id = ?")
+		args = append(args, coachID.Int64)
+	}
+	if !includeArchived {
+		conds = append(conds, "a.archived = 0")
 	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	rows, err := db.Query(`
+		SELECT a.id, a.name, a.tier,
+		       COALESCE((SELECT COUNT(*) FROM athlete_exercises ae
+		                 WHERE ae.athlete_id = a.id AND ae.active = 1), 0) AS active_assignments,
+		       (SELECT date(w.date) FROM workouts w WHERE w.athlete_id = a.id ORDER BY w.date DESC LIMIT 1) AS last_workout,
+		       a.track_body_weight, a.archived
+		FROM athletes a
+		`+where+`
+		ORDER BY a.name COLLATE NOCASE
+		LIMIT 100`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("models: list athlete cards: %w", err)
 	}
@@ -340,7 +431,7 @@ func ListAthleteCards(db *sql.DB, coachID sql.NullInt64) ([]*AthleteCardInfo, er
 	var cards []*AthleteCardInfo
 	for rows.Next() {
 		c := &AthleteCardInfo{}
-		if err := rows.Scan(&c.ID, &c.Name, &c.Tier, &c.ActiveAssignments, &c.LastWorkoutDate, &c.TrackBodyWeight); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Tier, &c.ActiveAssignments, &c.LastWorkoutDate, &c.TrackBodyWeight, &c.Archived); err != nil {
 			return nil, fmt.Errorf("models: scan athlete card: %w", err)
 		}
 		cards = append(cards, c)
@@ -19,6 +19,9 @@ type AthleteNote struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
+	// AcknowledgedAt is when the athlete confirmed reading the note.
+	AcknowledgedAt sql.NullTime
+
 	// Joined field populated by list queries.
 	AuthorName string
 }
@@ -60,13 +63,13 @@ func GetAthleteNoteByID(db *sql.DB, id int64) (*AthleteNote, error) {
 	var privInt, pinnedInt int
 	err := db.QueryRow(
 		`SELECT n.id, n.athlete_id, n.author_id, n.date, n.content,
-		        n.is_private, n.pinned, n.created_at, n.updated_at,
+		        n.is_private, n.pinned, n.created_at, n.updated_at, n.acknowledged_at,
 		        COALESCE(u.name, u.username, '') AS author_name
 		 FROM athlete_notes n
 		 LEFT JOIN users u ON u.id = n.author_id
 		 WHERE n.id = ?`, id,
 	).Scan(&n.ID, &n.AthleteID, &n.AuthorID, &n.Date, &n.Content,
-		&privInt, &pinnedInt, &n.CreatedAt, &n.UpdatedAt, &n.AuthorName)
+		&privInt, &pinnedInt, &n.CreatedAt, &n.UpdatedAt, &n.AcknowledgedAt, &n.AuthorName)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -79,6 +82,8 @@ func GetAthleteNoteByID(db *sql.DB, id int64) (*AthleteNote, error) {
 }
 
 // UpdateAthleteNote updates the content, visibility, and pinned status of a note.
+// Changing the content clears the athlete's acknowledgement so they confirm
+// the new wording.
 func UpdateAthleteNote(db *sql.DB, id int64, content string, isPrivate, pinned bool) (*AthleteNote, error) {
 	if content == "" {
 		return nil, fmt.Errorf("models: update athlete note: %w: content is required", ErrInvalidInput)
@@ -94,8 +99,11 @@ func UpdateAthleteNote(db *sql.DB, id int64, content string, isPrivate, pinned b
 	}
 
 	result, err := db.Exec(
-		`UPDATE athlete_notes SET content = ?, is_private = ?, pinned = ? WHERE id = ?`,
-		content, privInt, pinnedInt, id,
+		`UPDATE athlete_notes SET
+		     acknowledged_at = CASE WHEN content = ? THEN acknowledged_at END,
+		     content = ?, is_private = ?, pinned = ?
+		 WHERE id = ?`,
+		content, content, privInt, pinnedInt, id,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("models: update athlete note %d: %w", id, err)
@@ -108,6 +116,21 @@ func UpdateAthleteNote(db *sql.DB, id int64, content string, isPrivate, pinned b
 	return GetAthleteNoteByID(db, id)
 }
 
+// AcknowledgeAthleteNote records that the athlete has read a note. An
+// already acknowledged note keeps its original time.
+func AcknowledgeAthleteNote(db *sql.DB, id int64) (*AthleteNote, error) {
+	result, err := db.Exec(
+		`UPDATE athlete_notes SET acknowledged_at = COALESCE(acknowledged_at, CURRENT_TIMESTAMP) WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("models: acknowledge athlete note %d: %w", id, err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return nil, ErrNotFound
+	}
+	return GetAthleteNoteByID(db, id)
+}
+
 // DeleteAthleteNote removes a note by ID.
 func DeleteAthleteNote(db *sql.DB, id int64) error {
 	result, err := db.Exec(`DELETE FROM athlete_notes WHERE id = ?`, id)
@@ -125,7 +148,7 @@ func DeleteAthleteNote(db *sql.DB, id int64) error {
 // If includePrivate is false, only public notes are returned (for non-coach view).
 func ListAthleteNotes(db *sql.DB, athleteID int64, includePrivate bool) ([]*AthleteNote, error) {
 	query := `SELECT n.id, n.athlete_id, n.author_id, n.date, n.content,
-	                 n.is_private, n.pinned, n.created_at, n.updated_at,
+	                 n.is_private, n.pinned, n.created_at, n.updated_at, n.acknowledged_at,
 	                 COALESCE(u.name, u.username, '') AS author_name
 	          FROM athlete_notes n
 	          LEFT JOIN users u ON u.id = n.author_id
@@ -146,7 +169,7 @@ func ListAthleteNotes(db *sql.DB, athleteID int64, includePrivate bool) ([]*Athl
 		n := &AthleteNote{}
 		var privInt, pinnedInt int
 		if err := rows.Scan(&n.ID, &n.AthleteID, &n.AuthorID, &n.Date, &n.Content,
-			&privInt, &pinnedInt, &n.CreatedAt, &n.UpdatedAt, &n.AuthorName); err != nil {
+			&privInt, &pinnedInt, &n.CreatedAt, &n.UpdatedAt, &n.AcknowledgedAt, &n.AuthorName); err != nil {
 			return nil, fmt.Errorf("models: scan athlete note: %w", err)
 		}
 		n.IsPrivate = privInt == 1
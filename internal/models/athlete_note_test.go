@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -81,6 +82,46 @@ func TestUpdateAthleteNote(t *testing.T) {
 	})
 }
 
+func TestAcknowledgeAthleteNote(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	note, _ := CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Brace before every rep", false, true)
+
+	if note.AcknowledgedAt.Valid {
+		t.Fatal("new note should not be acknowledged")
+	}
+
+	acked, err := AcknowledgeAthleteNote(db, note.ID)
+	if err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	if !acked.AcknowledgedAt.Valid {
+		t.Fatal("expected acknowledged_at to be set")
+	}
+
+	// Acknowledging again keeps the first time.
+	db.Exec(`UPDATE athlete_notes SET acknowledged_at = '2026-03-02 08:00:00' WHERE id = ?`, note.ID)
+	again, _ := AcknowledgeAthleteNote(db, note.ID)
+	if got := again.AcknowledgedAt.Time.Format("2006-01-02"); got != "2026-03-02" {
+		t.Errorf("re-acknowledge moved acknowledged_at to %s", got)
+	}
+
+	// Changing flags keeps the acknowledgement; changing the wording clears it.
+	updated, _ := UpdateAthleteNote(db, note.ID, "Brace before every rep", false, false)
+	if !updated.AcknowledgedAt.Valid {
+		t.Error("unpinning should keep the acknowledgement")
+	}
+	updated, _ = UpdateAthleteNote(db, note.ID, "Brace and breathe before every rep", false, true)
+	if updated.AcknowledgedAt.Valid {
+		t.Error("editing the content should clear the acknowledgement")
+	}
+
+	if _, err := AcknowledgeAthleteNote(db, 9999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing note err = %v, want ErrNotFound", err)
+	}
+}
+
 func TestDeleteAthleteNote(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -28,11 +29,18 @@ type AthleteProgram struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 
+	// TrainingDays is a weekday bitmask (bit 0 = Mon … bit 6 = Sun) of the
+	// days the athlete is scheduled to train. NULL = any day.
+	TrainingDays sql.NullInt64
+
 	// Joined fields.
 	TemplateName string
 	NumWeeks     int
 	NumDays      int
 	IsLoop       bool
+
+	RoundingIncrement float64
+	RoundingMode      string
 }
 
 // ScheduleDays parses the Schedule JSON into a slice of weekday numbers (1=Mon..7=Sun).
@@ -67,6 +75,89 @@ func (ap *AthleteProgram) ScheduleLabel() string {
 	return label
 }
 
+// weekdayNames maps ISO weekday numbers (1=Mon..7=Sun) to short labels.
+var weekdayNames = map[int]string{1: "Mon", 2: "Tue", 3: "Wed", 4: "Thu", 5: "Fri", 6: "Sat", 7: "Sun"}
+
+// isoWeekday returns the ISO weekday number (1=Mon..7=Sun) of t.
+func isoWeekday(t time.Time) int {
+	d := int(t.Weekday())
+	if d == 0 {
+		return 7
+	}
+	return d
+}
+
+// TrainingDaysMask builds a training_days bitmask from ISO weekday numbers
+// (1=Mon..7=Sun). Out-of-range values are ignored.
+func TrainingDaysMask(days []int) int64 {
+	var mask int64
+	for _, d := range days {
+		if d >= 1 && d <= 7 {
+			mask |= 1 << (d - 1)
+		}
+	}
+	return mask
+}
+
+// TrainingDayList returns the ISO weekday numbers set in TrainingDays, or nil
+// when the assignment has no training-day schedule.
+func (ap *AthleteProgram) TrainingDayList() []int {
+	if !ap.TrainingDays.Valid {
+		return nil
+	}
+	var days []int
+	for d := 1; d <= 7; d++ {
+		if ap.TrainingDays.Int64&(1<<(d-1)) != 0 {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// TrainsOnWeekday reports whether ISO weekday d is a scheduled training day.
+func (ap *AthleteProgram) TrainsOnWeekday(d int) bool {
+	if !ap.TrainingDays.Valid {
+		return true
+	}
+	return ap.TrainingDays.Int64&(1<<(d-1)) != 0
+}
+
+// TrainsOn reports whether date is a scheduled training day. Always true
+// when no training days are set.
+func (ap *AthleteProgram) TrainsOn(date time.Time) bool {
+	return ap.TrainsOnWeekday(isoWeekday(date))
+}
+
+// TrainingDaysLabel returns the training days as "Mon, Wed, Fri", or "" when
+// no training-day schedule is set.
+func (ap *AthleteProgram) TrainingDaysLabel() string {
+	var names []string
+	for _, d := range ap.TrainingDayList() {
+		names = append(names, weekdayNames[d])
+	}
+	return strings.Join(names, ", ")
+}
+
+// SetTrainingDays updates the weekday bitmask for an assignment. A zero
+// mask clears the schedule (any day).
+func SetTrainingDays(db *sql.DB, athleteProgramID, mask int64) error {
+	if mask < 0 || mask > 127 {
+		return fmt.Errorf("models: training days mask %d out of range: %w", mask, ErrInvalidInput)
+	}
+	var val sql.NullInt64
+	if mask != 0 {
+		val = sql.NullInt64{Int64: mask, Valid: true}
+	}
+	result, err := db.Exec(`UPDATE athlete_programs SET training_days = ? WHERE id = ?`, val, athleteProgramID)
+	if err != nil {
+		return fmt.Errorf("models: set training days for athlete program %d: %w", athleteProgramID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // AssignProgram assigns a program template to an athlete.
 // role must be "primary" or "supplemental". schedule is a JSON weekday array (e.g. "[2,4]") or empty.
 // Only one active primary is allowed. Supplemental schedules are validated against existing assignments.
@@ -109,6 +200,64 @@ func AssignProgram(db *sql.DB, athleteID, templateID int64, startDate, notes, go
 	return GetAthleteProgramByID(db, id)
 }
 
+// ReassignProgram restarts a previous assignment on a fresh copy of its
+// template named "<template> (<startDate>)". The clone, the deactivation of
+// prev (if still active), and the new assignment happen in one transaction,
+// so a failure leaves no orphaned template or program-less athlete. The new
+// assignment keeps prev's role, schedule, and training days.
+func ReassignProgram(db *sql.DB, prev *AthleteProgram, startDate string) (*AthleteProgram, error) {
+	role := prev.Role
+	if role == "" {
+		role = "primary"
+	}
+	var scheduleVal sql.NullString
+	if prev.Schedule.Valid && prev.Schedule.String != "" {
+		scheduleVal = prev.Schedule
+	}
+
+	// Checked before the transaction starts: the pool has a single
+	// connection. prev is excluded since it is deactivated below.
+	if role == "supplemental" && scheduleVal.Valid {
+		if err := validateScheduleConflict(db, prev.AthleteID, scheduleVal.String, prev.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: reassign program begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	templateID, err := cloneProgramTemplateTx(tx, prev.TemplateID, fmt.Sprintf("%s (%s)", prev.TemplateName, startDate))
+	if err != nil {
+		return nil, err
+	}
+
+	if prev.Active {
+		if _, err := tx.Exec(`UPDATE athlete_programs SET active = 0 WHERE id = ?`, prev.ID); err != nil {
+			return nil, fmt.Errorf("models: deactivate athlete program %d: %w", prev.ID, err)
+		}
+	}
+
+	var id int64
+	err = tx.QueryRow(
+		`INSERT INTO athlete_programs (athlete_id, template_id, start_date, role, schedule, training_days) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
+		prev.AthleteID, templateID, startDate, role, scheduleVal, prev.TrainingDays,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrProgramAlreadyActive
+		}
+		return nil, fmt.Errorf("models: reassign program to athlete %d: %w", prev.AthleteID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: reassign program commit: %w", err)
+	}
+	return GetAthleteProgramByID(db, id)
+}
+
 // validateScheduleConflict checks that the proposed schedule doesn't overlap with any
 // existing active assignment. excludeID is an assignment ID to skip (0 to skip none).
 func validateScheduleConflict(db *sql.DB, athleteID int64, schedule string, excludeID int64) error {
@@ -142,15 +291,17 @@ func validateScheduleConflict(db *sql.DB, athleteID int64, schedule string, excl
 func scanAthleteProgram(scanner interface{ Scan(...any) error }) (*AthleteProgram, error) {
 	ap := &AthleteProgram{}
 	err := scanner.Scan(&ap.ID, &ap.AthleteID, &ap.TemplateID, &ap.StartDate, &ap.Active,
-		&ap.Role, &ap.Schedule, &ap.Notes, &ap.Goal,
-		&ap.CreatedAt, &ap.UpdatedAt, &ap.TemplateName, &ap.NumWeeks, &ap.NumDays, &ap.IsLoop)
+		&ap.Role, &ap.Schedule, &ap.TrainingDays, &ap.Notes, &ap.Goal,
+		&ap.CreatedAt, &ap.UpdatedAt, &ap.TemplateName, &ap.NumWeeks, &ap.NumDays, &ap.IsLoop,
+		&ap.RoundingIncrement, &ap.RoundingMode)
 	return ap, err
 }
 
 // athleteProgramColumns is the shared SELECT list for athlete_programs queries.
 const athleteProgramColumns = `ap.id, ap.athlete_id, ap.template_id, ap.start_date, ap.active,
-		        ap.role, ap.schedule, ap.notes, ap.goal,
-		        ap.created_at, ap.updated_at, pt.name, pt.num_weeks, pt.num_days, pt.is_loop`
+		        ap.role, ap.schedule, ap.training_days, ap.notes, ap.goal,
+		        ap.created_at, ap.updated_at, pt.name, pt.num_weeks, pt.num_days, pt.is_loop,
+		        pt.rounding_increment, pt.rounding_mode`
 
 // GetAthleteProgramByID retrieves an athlete program by primary key.
 func GetAthleteProgramByID(db *sql.DB, id int64) (*AthleteProgram, error) {
@@ -251,6 +402,52 @@ func ListAthletePrograms(db *sql.DB, athleteID int64) ([]*AthleteProgram, error)
 	return programs, nil
 }
 
+// ProgramHistoryEntry is one program assignment in an athlete's history.
+type ProgramHistoryEntry struct {
+	*AthleteProgram
+	EndDate        sql.NullString // YYYY-MM-DD the assignment was deactivated; NULL while active
+	WorkoutsLogged int
+}
+
+// ListAthleteProgramHistory returns every program assignment for an athlete,
+// most recent first, with its end date and the number of workouts logged
+// against it.
+func ListAthleteProgramHistory(db *sql.DB, athleteID int64) ([]*ProgramHistoryEntry, error) {
+	rows, err := db.Query(
+		`SELECT `+athleteProgramColumns+`,
+		        CASE WHEN ap.active = 0 THEN date(ap.updated_at) END,
+		        (SELECT COUNT(*) FROM workouts w WHERE w.assignment_id = ap.id)
+		 FROM athlete_programs ap
+		 JOIN program_templates pt ON pt.id = ap.template_id
+		 WHERE ap.athlete_id = ?
+		 ORDER BY ap.start_date DESC, ap.created_at DESC`,
+		athleteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list program history for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var history []*ProgramHistoryEntry
+	for rows.Next() {
+		ap := &AthleteProgram{}
+		e := &ProgramHistoryEntry{AthleteProgram: ap}
+		if err := rows.Scan(&ap.ID, &ap.AthleteID, &ap.TemplateID, &ap.StartDate, &ap.Active,
+			&ap.Role, &ap.Schedule, &ap.TrainingDays, &ap.Notes, &ap.Goal,
+			&ap.CreatedAt, &ap.UpdatedAt, &ap.TemplateName, &ap.NumWeeks, &ap.NumDays, &ap.IsLoop,
+			&ap.RoundingIncrement, &ap.RoundingMode,
+			&e.EndDate, &e.WorkoutsLogged); err != nil {
+			return nil, fmt.Errorf("models: scan program history: %w", err)
+		}
+		ap.StartDate = normalizeDate(ap.StartDate)
+		history = append(history, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate program history: %w", err)
+	}
+	return history, nil
+}
+
 // DeactivateProgram deactivates an athlete's program.
 func DeactivateProgram(db *sql.DB, athleteProgramID int64) error {
 	_, err := db.Exec(
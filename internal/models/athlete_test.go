@@ -2,7 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestCreateAthlete(t *testing.T) {
@@ -172,11 +174,55 @@ func TestDeleteAthlete(t *testing.T) {
 	})
 }
 
+func TestSetAthleteArchived(t *testing.T) {
+	db := testDB(t)
+
+	archived, _ := CreateAthlete(db, "Archived", "", "", "", "", "", "", sql.NullInt64{}, true)
+	CreateAthlete(db, "Active", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	if err := SetAthleteArchived(db, archived.ID, true); err != nil {
+		t.Fatalf("archive athlete: %v", err)
+	}
+	got, err := GetAthleteByID(db, archived.ID)
+	if err != nil {
+		t.Fatalf("get athlete: %v", err)
+	}
+	if !got.Archived {
+		t.Error("expected athlete to be archived")
+	}
+
+	athletes, _ := ListAthletes(db, sql.NullInt64{}, false)
+	if len(athletes) != 1 || athletes[0].Name != "Active" {
+		t.Errorf("expected only the active athlete, got %d athletes", len(athletes))
+	}
+	athletes, _ = ListAthletes(db, sql.NullInt64{}, true)
+	if len(athletes) != 2 {
+		t.Errorf("expected 2 athletes with includeArchived, got %d", len(athletes))
+	}
+
+	cards, _ := ListAthleteCards(db, sql.NullInt64{}, false)
+	if len(cards) != 1 {
+		t.Errorf("expected 1 athlete card, got %d", len(cards))
+	}
+
+	if err := SetAthleteArchived(db, archived.ID, false); err != nil {
+		t.Fatalf("unarchive athlete: %v", err)
+	}
+	athletes, _ = ListAthletes(db, sql.NullInt64{}, false)
+	if len(athletes) != 2 {
+		t.Errorf("expected 2 athletes after unarchive, got %d", len(athletes))
+	}
+
+	if err := SetAthleteArchived(db, 99999, true); err != ErrNotFound {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
 func TestListAthletes(t *testing.T) {
 	db := testDB(t)
 
 	// Start with empty list.
-	athletes, err := ListAthletes(db, sql.NullInt64{})
+	athletes, err := ListAthletes(db, sql.NullInt64{}, false)
 	if err != nil {
 		t.Fatalf("list athletes: %v", err)
 	}
@@ -187,7 +233,7 @@ func TestListAthletes(t *testing.T) {
 	CreateAthlete(db, "Zara", "", "", "", "", "", "", sql.NullInt64{}, true)
 	CreateAthlete(db, "Aaron", "", "", "", "", "", "", sql.NullInt64{}, true)
 
-	athletes, err = ListAthletes(db, sql.NullInt64{})
+	athletes, err = ListAthletes(db, sql.NullInt64{}, false)
 	if err != nil {
 		t.Fatalf("list athletes: %v", err)
 	}
@@ -200,6 +246,29 @@ func TestListAthletes(t *testing.T) {
 	}
 }
 
+func TestAthleteAge(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		dob    string
+		want   int
+		wantOK bool
+	}{
+		{"2012-03-15", 14, true},           // birthday today
+		{"2012-03-16", 13, true},           // birthday tomorrow
+		{"2012-02-29", 14, true},           // leap-day birthday already passed
+		{"2012-03-15T00:00:00Z", 14, true}, // RFC 3339 from SQLite
+		{"", 0, false},
+		{"not-a-date", 0, false},
+		{"2027-01-01", 0, false}, // in the future
+	}
+	for _, tt := range tests {
+		got, ok := AthleteAge(tt.dob, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("AthleteAge(%q) = (%d, %v), want (%d, %v)", tt.dob, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
 func TestNextTier(t *testing.T) {
 	tests := []struct {
 		current  string
@@ -329,3 +398,32 @@ func TestListAvailableAthletes(t *testing.T) {
 	_ = a2
 	_ = a3
 }
+
+func TestSetAthleteBarWeight(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Barbell", "", "", "", "", "", "", sql.NullInt64{}, true)
+	if got := a.BarWeightOrDefault(); got != DefaultBarWeight {
+		t.Errorf("default bar weight = %v, want %v", got, DefaultBarWeight)
+	}
+
+	if err := SetAthleteBarWeight(db, a.ID, 35); err != nil {
+		t.Fatalf("set bar weight: %v", err)
+	}
+	got, _ := GetAthleteByID(db, a.ID)
+	if !got.BarWeight.Valid || got.BarWeight.Float64 != 35 {
+		t.Errorf("bar weight = %+v, want 35", got.BarWeight)
+	}
+
+	if err := SetAthleteBarWeight(db, a.ID, 0); err != nil {
+		t.Fatalf("clear bar weight: %v", err)
+	}
+	got, _ = GetAthleteByID(db, a.ID)
+	if got.BarWeight.Valid {
+		t.Errorf("bar weight = %+v, want NULL after clear", got.BarWeight)
+	}
+
+	if err := SetAthleteBarWeight(db, 99999, 45); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
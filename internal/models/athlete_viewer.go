@@ -0,0 +1,128 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// userColumns is the column list scanned by scanUsers, for queries that
+// alias the users table as u.
+const userColumns = `u.id, u.username, u.name, u.email, COALESCE(u.password_hash, ''), u.athlete_id, u.is_coach, u.is_admin, u.avatar_path, u.created_at, u.updated_at`
+
+// GrantAthleteViewer gives a user read-only access to an athlete. Granting
+// access the user already has is a no-op.
+func GrantAthleteViewer(db *sql.DB, athleteID, userID int64) error {
+	_, err := db.Exec(
+		`INSERT INTO athlete_viewers (athlete_id, user_id) VALUES (?, ?)
+		 ON CONFLICT(athlete_id, user_id) DO NOTHING`,
+		athleteID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("models: grant viewer %d on athlete %d: %w", userID, athleteID, err)
+	}
+	return nil
+}
+
+// RevokeAthleteViewer removes a user's read-only access to an athlete.
+// Returns ErrNotFound if the user wasn't a viewer.
+func RevokeAthleteViewer(db *sql.DB, athleteID, userID int64) error {
+	res, err := db.Exec(`DELETE FROM athlete_viewers WHERE athlete_id = ? AND user_id = ?`, athleteID, userID)
+	if err != nil {
+		return fmt.Errorf("models: revoke viewer %d on athlete %d: %w", userID, athleteID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// IsAthleteViewer reports whether the user has been granted read-only
+// access to the athlete.
+func IsAthleteViewer(db *sql.DB, athleteID, userID int64) (bool, error) {
+	var exists bool
+	err := db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM athlete_viewers WHERE athlete_id = ? AND user_id = ?)`,
+		athleteID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("models: check viewer %d on athlete %d: %w", userID, athleteID, err)
+	}
+	return exists, nil
+}
+
+// ListAthleteViewers returns the users with read-only access to an athlete,
+// ordered by username.
+func ListAthleteViewers(db *sql.DB, athleteID int64) ([]*User, error) {
+	rows, err := db.Query(`
+		SELECT `+userColumns+`
+		FROM athlete_viewers av
+		JOIN users u ON u.id = av.user_id
+		WHERE av.athlete_id = ?
+		ORDER BY u.username COLLATE NOCASE`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list viewers for athlete %d: %w", athleteID, err)
+	}
+	return scanUsers(rows)
+}
+
+// ListViewerCandidates returns the accounts that could be granted read-only
+// access to an athlete: users who aren't coaches or admins, aren't linked to
+// the athlete and aren't already viewers.
+func ListViewerCandidates(db *sql.DB, athleteID int64) ([]*User, error) {
+	rows, err := db.Query(`
+		SELECT `+userColumns+`
+		FROM users u
+		WHERE u.is_coach = 0 AND u.is_admin = 0
+		  AND (u.athlete_id IS NULL OR u.athlete_id != ?)
+		  AND NOT EXISTS (SELECT 1 FROM athlete_viewers av WHERE av.athlete_id = ? AND av.user_id = u.id)
+		ORDER BY u.username COLLATE NOCASE
+		LIMIT 100`, athleteID, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list viewer candidates for athlete %d: %w", athleteID, err)
+	}
+	return scanUsers(rows)
+}
+
+// ListViewedAthletes returns the unarchived athletes a user has been granted
+// read-only access to, ordered by name.
+func ListViewedAthletes(db *sql.DB, userID int64) ([]*Athlete, error) {
+	rows, err := db.Query(`
+		SELECT a.id, a.name, a.tier, a.notes, a.goal, a.date_of_birth, a.grade, a.gender,
+		       a.coach_id, a.track_body_weight, a.bar_weight, a.plates, a.goal_weight, a.archived,
+		       a.created_at, a.updated_at
+		FROM athlete_viewers av
+		JOIN athletes a ON a.id = av.athlete_id
+		WHERE av.user_id = ? AND a.archived = 0
+		ORDER BY a.name COLLATE NOCASE`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list viewed athletes for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var athletes []*Athlete
+	for rows.Next() {
+		a := &Athlete{}
+		if err := rows.Scan(&a.ID, &a.Name, &a.Tier, &a.Notes, &a.Goal, &a.DateOfBirth, &a.Grade, &a.Gender,
+			&a.CoachID, &a.TrackBodyWeight, &a.BarWeight, &a.Plates, &a.GoalWeight, &a.Archived,
+			&a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan viewed athlete: %w", err)
+		}
+		athletes = append(athletes, a)
+	}
+	return athletes, rows.Err()
+}
+
+// scanUsers reads users selected with userColumns and closes rows.
+func scanUsers(rows *sql.Rows) ([]*User, error) {
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		u := &User{}
+		if err := rows.Scan(&u.ID, &u.Username, &u.Name, &u.Email, &u.PasswordHash, &u.AthleteID, &u.IsCoach, &u.IsAdmin, &u.AvatarPath, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
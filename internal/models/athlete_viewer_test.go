@@ -0,0 +1,109 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestAthleteViewers(t *testing.T) {
+	db := testDB(t)
+
+	athlete, err := CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	if err != nil {
+		t.Fatalf("create athlete: %v", err)
+	}
+	parent, err := CreateUser(db, "parent", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	kid, err := CreateUser(db, "kid", "", "password123", "", false, false, sql.NullInt64{Int64: athlete.ID, Valid: true})
+	if err != nil {
+		t.Fatalf("create kid: %v", err)
+	}
+	if _, err := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{}); err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+
+	t.Run("candidates exclude coaches and linked users", func(t *testing.T) {
+		candidates, err := ListViewerCandidates(db, athlete.ID)
+		if err != nil {
+			t.Fatalf("list candidates: %v", err)
+		}
+		if len(candidates) != 1 || candidates[0].ID != parent.ID {
+			t.Errorf("candidates = %v, want only parent", candidates)
+		}
+	})
+
+	t.Run("grant", func(t *testing.T) {
+		if err := GrantAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+			t.Fatalf("grant: %v", err)
+		}
+		// Granting twice is a no-op.
+		if err := GrantAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+			t.Fatalf("grant again: %v", err)
+		}
+
+		ok, err := IsAthleteViewer(db, athlete.ID, parent.ID)
+		if err != nil || !ok {
+			t.Errorf("IsAthleteViewer(parent) = %v, %v; want true", ok, err)
+		}
+		ok, err = IsAthleteViewer(db, athlete.ID, kid.ID)
+		if err != nil || ok {
+			t.Errorf("IsAthleteViewer(kid) = %v, %v; want false", ok, err)
+		}
+
+		viewers, err := ListAthleteViewers(db, athlete.ID)
+		if err != nil {
+			t.Fatalf("list viewers: %v", err)
+		}
+		if len(viewers) != 1 || viewers[0].Username != "parent" {
+			t.Errorf("viewers = %v, want [parent]", viewers)
+		}
+
+		viewed, err := ListViewedAthletes(db, parent.ID)
+		if err != nil {
+			t.Fatalf("list viewed athletes: %v", err)
+		}
+		if len(viewed) != 1 || viewed[0].ID != athlete.ID {
+			t.Errorf("viewed athletes = %v, want [Kid]", viewed)
+		}
+
+		candidates, err := ListViewerCandidates(db, athlete.ID)
+		if err != nil {
+			t.Fatalf("list candidates: %v", err)
+		}
+		if len(candidates) != 0 {
+			t.Errorf("expected no candidates after grant, got %d", len(candidates))
+		}
+	})
+
+	t.Run("archived athletes are hidden from viewers", func(t *testing.T) {
+		if err := SetAthleteArchived(db, athlete.ID, true); err != nil {
+			t.Fatalf("archive: %v", err)
+		}
+		viewed, err := ListViewedAthletes(db, parent.ID)
+		if err != nil {
+			t.Fatalf("list viewed athletes: %v", err)
+		}
+		if len(viewed) != 0 {
+			t.Errorf("expected archived athlete hidden, got %d", len(viewed))
+		}
+		if err := SetAthleteArchived(db, athlete.ID, false); err != nil {
+			t.Fatalf("unarchive: %v", err)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		if err := RevokeAthleteViewer(db, athlete.ID, parent.ID); err != nil {
+			t.Fatalf("revoke: %v", err)
+		}
+		if err := RevokeAthleteViewer(db, athlete.ID, parent.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("revoke again: err = %v, want ErrNotFound", err)
+		}
+		ok, err := IsAthleteViewer(db, athlete.ID, parent.ID)
+		if err != nil || ok {
+			t.Errorf("IsAthleteViewer after revoke = %v, %v; want false", ok, err)
+		}
+	})
+}
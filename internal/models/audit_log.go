@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Audit actions recorded by RecordAudit.
+const (
+	AuditImpersonateStart = "impersonate.start"
+	AuditImpersonateStop  = "impersonate.stop"
+	AuditUserDelete       = "user.delete"
+	AuditAthleteDelete    = "athlete.delete"
+	AuditProgramDelete    = "program.delete"
+	AuditSettingsUpdate   = "settings.update"
+	AuditLoginTokenCreate = "login_token.create"
+)
+
+// AuditPageSize is the number of audit log entries shown per page.
+const AuditPageSize = 50
+
+// AuditEntry is one row of the audit log.
+type AuditEntry struct {
+	ID        int64
+	UserID    sql.NullInt64
+	Username  sql.NullString // NULL once the acting user is deleted
+	Action    string
+	Target    string
+	CreatedAt time.Time
+}
+
+// AuditFilter narrows ListAuditLog. Zero values match everything.
+type AuditFilter struct {
+	UserID int64
+	Action string
+}
+
+// IsZero reports whether the filter matches every entry.
+func (f AuditFilter) IsZero() bool {
+	return f.UserID == 0 && f.Action == ""
+}
+
+// AuditPage holds a page of audit log entries and whether more exist.
+type AuditPage struct {
+	Entries []*AuditEntry
+	HasMore bool
+}
+
+// RecordAudit adds an entry to the audit log saying userID performed action
+// on target, a short human-readable description built with AuditTarget.
+func RecordAudit(db *sql.DB, userID int64, action, target string) error {
+	_, err := db.Exec(
+		`INSERT INTO audit_log (user_id, action, target) VALUES (?, ?, ?)`,
+		userID, action, target,
+	)
+	if err != nil {
+		return fmt.Errorf("models: record audit %s by user %d: %w", action, userID, err)
+	}
+	return nil
+}
+
+// AuditTarget describes a record as an audit log target, e.g.
+// "athlete 5 (Kid)". The name keeps entries readable after the record is
+// deleted.
+func AuditTarget(kind string, id int64, name string) string {
+	if name == "" {
+		return fmt.Sprintf("%s %d", kind, id)
+	}
+	return fmt.Sprintf("%s %d (%s)", kind, id, name)
+}
+
+// ListAuditLog returns audit log entries matching filter, newest first.
+// Uses offset-based pagination.
+func ListAuditLog(db *sql.DB, filter AuditFilter, offset int) (*AuditPage, error) {
+	var conds []string
+	var args []any
+	if filter.UserID != 0 {
+		conds = append(conds, "al.user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Action != "" {
+		conds = append(conds, "al.action = ?")
+		args = append(args, filter.Action)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, AuditPageSize+1, offset)
+
+	rows, err := db.Query(`
+		SELECT al.id, al.user_id, u.username, al.action, al.target, al.created_at
+		FROM audit_log al
+		LEFT JOIN users u ON u.id = al.user_id
+		`+where+`
+		ORDER BY al.created_at DESC, al.id DESC
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("models: list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		e := &AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Username, &e.Action, &e.Target, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate audit log: %w", err)
+	}
+
+	hasMore := len(entries) > AuditPageSize
+	if hasMore {
+		entries = entries[:AuditPageSize]
+	}
+	return &AuditPage{Entries: entries, HasMore: hasMore}, nil
+}
+
+// ListAuditActions returns the distinct actions present in the audit log,
+// for the filter dropdown.
+func ListAuditActions(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT action FROM audit_log ORDER BY action`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list audit actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []string
+	for rows.Next() {
+		var a string
+		if err := rows.Scan(&a); err != nil {
+			return nil, fmt.Errorf("models: scan audit action: %w", err)
+		}
+		actions = append(actions, a)
+	}
+	return actions, rows.Err()
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestListAuditLog(t *testing.T) {
+	db := testDB(t)
+
+	admin, _ := CreateUser(db, "admin", "", "password123", "", true, true, sql.NullInt64{})
+	coach, _ := CreateUser(db, "coach2", "", "password123", "", true, false, sql.NullInt64{})
+
+	if err := RecordAudit(db, admin.ID, AuditSettingsUpdate, "app.name"); err != nil {
+		t.Fatalf("record audit: %v", err)
+	}
+	RecordAudit(db, coach.ID, AuditAthleteDelete, AuditTarget("athlete", 3, "Kid"))
+	RecordAudit(db, admin.ID, AuditUserDelete, AuditTarget("user", 9, ""))
+
+	page, err := ListAuditLog(db, AuditFilter{}, 0)
+	if err != nil {
+		t.Fatalf("list audit log: %v", err)
+	}
+	if len(page.Entries) != 3 || page.HasMore {
+		t.Fatalf("entries = %d (more %v), want 3", len(page.Entries), page.HasMore)
+	}
+	if got := page.Entries[0].Target; got != "user 9" {
+		t.Errorf("newest target = %q, want %q", got, "user 9")
+	}
+	if got := page.Entries[1].Target; got != "athlete 3 (Kid)" {
+		t.Errorf("target = %q, want %q", got, "athlete 3 (Kid)")
+	}
+
+	page, _ = ListAuditLog(db, AuditFilter{UserID: admin.ID}, 0)
+	if len(page.Entries) != 2 {
+		t.Errorf("admin entries = %d, want 2", len(page.Entries))
+	}
+	page, _ = ListAuditLog(db, AuditFilter{UserID: admin.ID, Action: AuditUserDelete}, 0)
+	if len(page.Entries) != 1 || page.Entries[0].Username.String != "admin" {
+		t.Errorf("filtered entries = %+v, want one user.delete by admin", page.Entries)
+	}
+
+	actions, err := ListAuditActions(db)
+	if err != nil {
+		t.Fatalf("list audit actions: %v", err)
+	}
+	if len(actions) != 3 || actions[0] != AuditAthleteDelete {
+		t.Errorf("actions = %v", actions)
+	}
+
+	// Entries outlive the user who made them.
+	if err := DeleteUser(db, coach.ID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	page, _ = ListAuditLog(db, AuditFilter{Action: AuditAthleteDelete}, 0)
+	if len(page.Entries) != 1 || page.Entries[0].Username.Valid {
+		t.Errorf("entries after actor deleted = %+v, want one without username", page.Entries)
+	}
+}
+
+func TestListAuditLog_Pagination(t *testing.T) {
+	db := testDB(t)
+
+	admin, _ := CreateUser(db, "admin", "", "password123", "", true, true, sql.NullInt64{})
+	for i := 0; i < AuditPageSize+5; i++ {
+		RecordAudit(db, admin.ID, AuditLoginTokenCreate, AuditTarget("user", int64(i), ""))
+	}
+
+	page, err := ListAuditLog(db, AuditFilter{}, 0)
+	if err != nil {
+		t.Fatalf("list audit log: %v", err)
+	}
+	if len(page.Entries) != AuditPageSize || !page.HasMore {
+		t.Errorf("first page = %d (more %v), want %d with more", len(page.Entries), page.HasMore, AuditPageSize)
+	}
+	page, _ = ListAuditLog(db, AuditFilter{}, AuditPageSize)
+	if len(page.Entries) != 5 || page.HasMore {
+		t.Errorf("second page = %d (more %v), want 5", len(page.Entries), page.HasMore)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -12,17 +13,35 @@ import (
 var ErrDuplicateBodyWeight = errors.New("body weight entry already exists for this date")
 
 // BodyWeight represents a single body weight entry for an athlete.
+// Weight is always stored in lbs; Unit is the unit it was logged in.
 type BodyWeight struct {
 	ID        int64
 	AthleteID int64
 	Date      string
 	Weight    float64
+	Unit      string
 	Notes     sql.NullString
 	CreatedAt time.Time
 }
 
-// CreateBodyWeight inserts a new body weight record.
+// WeightIn returns the entry's weight converted to the given unit.
+func (bw *BodyWeight) WeightIn(unit string) float64 {
+	return ConvertWeight(bw.Weight, "lbs", unit)
+}
+
+// CreateBodyWeight inserts a new body weight record with the weight in lbs.
 func CreateBodyWeight(db *sql.DB, athleteID int64, date string, weight float64, notes string) (*BodyWeight, error) {
+	return CreateBodyWeightInUnit(db, athleteID, date, weight, "lbs", notes)
+}
+
+// CreateBodyWeightInUnit inserts a new body weight record logged in the given
+// unit ("lbs" or "kg"). The weight is converted to lbs for storage and the
+// original unit is recorded on the entry.
+func CreateBodyWeightInUnit(db *sql.DB, athleteID int64, date string, weight float64, unit, notes string) (*BodyWeight, error) {
+	if !isValidWeightUnit(unit) {
+		return nil, fmt.Errorf("models: invalid body weight unit %q: %w", unit, ErrInvalidInput)
+	}
+
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
@@ -30,8 +49,8 @@ func CreateBodyWeight(db *sql.DB, athleteID int64, date string, weight float64,
 
 	var id int64
 	err := db.QueryRow(
-		`INSERT INTO body_weights (athlete_id, date, weight, notes) VALUES (?, ?, ?, ?) RETURNING id`,
-		athleteID, date, weight, notesVal,
+		`INSERT INTO body_weights (athlete_id, date, weight, unit, notes) VALUES (?, ?, ?, ?, ?) RETURNING id`,
+		athleteID, date, ConvertWeight(weight, unit, "lbs"), unit, notesVal,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -47,8 +66,8 @@ func CreateBodyWeight(db *sql.DB, athleteID int64, date string, weight float64,
 func GetBodyWeightByID(db *sql.DB, id int64) (*BodyWeight, error) {
 	bw := &BodyWeight{}
 	err := db.QueryRow(
-		`SELECT id, athlete_id, date, weight, notes, created_at FROM body_weights WHERE id = ?`, id,
-	).Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Notes, &bw.CreatedAt)
+		`SELECT id, athlete_id, date, weight, unit, notes, created_at FROM body_weights WHERE id = ?`, id,
+	).Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Unit, &bw.Notes, &bw.CreatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -84,7 +103,7 @@ type BodyWeightPage struct {
 // descending. Uses offset-based pagination.
 func ListBodyWeights(db *sql.DB, athleteID int64, offset int) (*BodyWeightPage, error) {
 	rows, err := db.Query(`
-		SELECT id, athlete_id, date, weight, notes, created_at
+		SELECT id, athlete_id, date, weight, unit, notes, created_at
 		FROM body_weights
 		WHERE athlete_id = ?
 		ORDER BY date DESC
@@ -97,7 +116,7 @@ func ListBodyWeights(db *sql.DB, athleteID int64, offset int) (*BodyWeightPage,
 	var entries []*BodyWeight
 	for rows.Next() {
 		bw := &BodyWeight{}
-		if err := rows.Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Notes, &bw.CreatedAt); err != nil {
+		if err := rows.Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Unit, &bw.Notes, &bw.CreatedAt); err != nil {
 			return nil, fmt.Errorf("models: scan body weight: %w", err)
 		}
 		entries = append(entries, bw)
@@ -118,12 +137,12 @@ func ListBodyWeights(db *sql.DB, athleteID int64, offset int) (*BodyWeightPage,
 func LatestBodyWeight(db *sql.DB, athleteID int64) (*BodyWeight, error) {
 	bw := &BodyWeight{}
 	err := db.QueryRow(`
-		SELECT id, athlete_id, date, weight, notes, created_at
+		SELECT id, athlete_id, date, weight, unit, notes, created_at
 		FROM body_weights
 		WHERE athlete_id = ?
 		ORDER BY date DESC
 		LIMIT 1`, athleteID,
-	).Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Notes, &bw.CreatedAt)
+	).Scan(&bw.ID, &bw.AthleteID, &bw.Date, &bw.Weight, &bw.Unit, &bw.Notes, &bw.CreatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -132,3 +151,173 @@ func LatestBodyWeight(db *sql.DB, athleteID int64) (*BodyWeight, error) {
 	}
 	return bw, nil
 }
+
+// bodyWeightDates returns the set of dates on which the athlete already has a
+// body weight entry.
+func bodyWeightDates(db *sql.DB, athleteID int64) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT date FROM body_weights WHERE athlete_id = ?`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list body weight dates for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	dates := make(map[string]bool)
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, fmt.Errorf("models: scan body weight date: %w", err)
+		}
+		dates[normalizeDate(d)] = true
+	}
+	return dates, rows.Err()
+}
+
+// BodyWeightSummary summarizes an athlete's recent body weight trend.
+// Averages are taken over whatever entries fall in each window, so sparse
+// logging still yields a value as long as one entry is in range. Windows
+// end at the latest entry, not today.
+type BodyWeightSummary struct {
+	Latest  *BodyWeight
+	Avg7    float64 // mean of entries in the 7 days ending at Latest
+	Count7  int
+	Avg30   float64 // mean of entries in the 30 days ending at Latest
+	Count30 int
+
+	// WeeklyRate is the least-squares slope of the 30-day window in weight
+	// per week. Only meaningful when HasRate is true (two or more entries
+	// on different dates).
+	WeeklyRate float64
+	HasRate    bool
+
+	GoalWeight sql.NullFloat64 // from the athlete; NULL = no goal
+}
+
+// ToGoal returns the remaining change from the 7-day average to the goal
+// (negative when the athlete needs to lose weight).
+func (s *BodyWeightSummary) ToGoal() float64 {
+	return s.GoalWeight.Float64 - s.Avg7
+}
+
+// HasProjection reports whether WeeksToGoal is meaningful: there is a goal,
+// a rate, and the trend is moving toward the goal.
+func (s *BodyWeightSummary) HasProjection() bool {
+	if !s.GoalWeight.Valid || !s.HasRate || s.WeeklyRate == 0 {
+		return false
+	}
+	return s.ToGoal()/s.WeeklyRate >= 0
+}
+
+// WeeksToGoal projects how many weeks remain at the current weekly rate,
+// rounded up. Returns 0 when HasProjection is false.
+func (s *BodyWeightSummary) WeeksToGoal() int {
+	if !s.HasProjection() {
+		return 0
+	}
+	return int(math.Ceil(s.ToGoal() / s.WeeklyRate))
+}
+
+// InUnit returns a copy of the summary with all weights converted from lbs to
+// the given unit.
+func (s *BodyWeightSummary) InUnit(unit string) *BodyWeightSummary {
+	c := *s
+	latest := *s.Latest
+	latest.Weight = latest.WeightIn(unit)
+	c.Latest = &latest
+	c.Avg7 = ConvertWeight(s.Avg7, "lbs", unit)
+	c.Avg30 = ConvertWeight(s.Avg30, "lbs", unit)
+	c.WeeklyRate = ConvertWeight(s.WeeklyRate, "lbs", unit)
+	if c.GoalWeight.Valid {
+		c.GoalWeight.Float64 = ConvertWeight(s.GoalWeight.Float64, "lbs", unit)
+	}
+	return &c
+}
+
+// BodyWeightStats computes the latest entry, 7- and 30-day averages, the
+// weekly rate of change, and the athlete's goal weight. Returns nil when the
+// athlete has no body weight entries.
+func BodyWeightStats(db *sql.DB, athleteID int64) (*BodyWeightSummary, error) {
+	latest, err := LatestBodyWeight(db, athleteID)
+	if err != nil || latest == nil {
+		return nil, err
+	}
+
+	stats := &BodyWeightSummary{Latest: latest}
+	err = db.QueryRow(`SELECT goal_weight FROM athletes WHERE id = ?`, athleteID).Scan(&stats.GoalWeight)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("models: get goal weight for athlete %d: %w", athleteID, err)
+	}
+
+	end, err := time.Parse("2006-01-02", normalizeDate(latest.Date))
+	if err != nil {
+		return nil, fmt.Errorf("models: parse body weight date %q: %w", latest.Date, err)
+	}
+
+	rows, err := db.Query(`
+		SELECT date, weight FROM body_weights
+		WHERE athlete_id = ? AND date(date) >= date(?) AND date(date) <= date(?)
+		ORDER BY date`,
+		athleteID, end.AddDate(0, 0, -29).Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("models: body weight stats for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var sum7, sum30 float64
+	var xs, ys []float64 // days since window start, weight
+	for rows.Next() {
+		var d string
+		var w float64
+		if err := rows.Scan(&d, &w); err != nil {
+			return nil, fmt.Errorf("models: scan body weight stats: %w", err)
+		}
+		t, err := time.Parse("2006-01-02", normalizeDate(d))
+		if err != nil {
+			continue
+		}
+		daysBack := end.Sub(t).Hours() / 24
+		sum30 += w
+		stats.Count30++
+		if daysBack < 7 {
+			sum7 += w
+			stats.Count7++
+		}
+		xs = append(xs, 29-daysBack)
+		ys = append(ys, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.Count7 > 0 {
+		stats.Avg7 = sum7 / float64(stats.Count7)
+	}
+	if stats.Count30 > 0 {
+		stats.Avg30 = sum30 / float64(stats.Count30)
+	}
+	if slope, ok := leastSquaresSlope(xs, ys); ok {
+		stats.WeeklyRate = slope * 7
+		stats.HasRate = true
+	}
+	return stats, nil
+}
+
+// leastSquaresSlope returns the slope of the best-fit line through (xs, ys).
+// Returns false when fewer than two distinct x values are present.
+func leastSquaresSlope(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, false
+	}
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	denom := n*sxx - sx*sx
+	if denom == 0 {
+		return 0, false
+	}
+	return (n*sxy - sx*sy) / denom, true
+}
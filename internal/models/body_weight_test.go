@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+	"math"
 	"strings"
 	"testing"
 )
@@ -205,3 +207,100 @@ func TestLatestBodyWeight(t *testing.T) {
 		}
 	})
 }
+
+func TestBodyWeightStats(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Stats Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	stats, err := BodyWeightStats(db, a.ID)
+	if err != nil || stats != nil {
+		t.Fatalf("no entries: stats=%v err=%v, want nil", stats, err)
+	}
+
+	// A single entry: averages but no rate.
+	CreateBodyWeight(db, a.ID, "2026-03-01", 200, "")
+	stats, _ = BodyWeightStats(db, a.ID)
+	if stats.Avg7 != 200 || stats.Avg30 != 200 || stats.HasRate || stats.HasProjection() {
+		t.Errorf("single entry: %+v", stats)
+	}
+
+	// Sparse logging, losing 1 lb per week. The 2026-01-01 entry falls
+	// outside the 30-day window.
+	CreateBodyWeight(db, a.ID, "2026-01-01", 220, "")
+	CreateBodyWeight(db, a.ID, "2026-03-08", 199, "")
+	CreateBodyWeight(db, a.ID, "2026-03-15", 198, "")
+	CreateBodyWeight(db, a.ID, "2026-03-22", 197, "")
+
+	stats, _ = BodyWeightStats(db, a.ID)
+	if stats.Latest.Weight != 197 {
+		t.Errorf("latest = %v, want 197", stats.Latest.Weight)
+	}
+	if stats.Count7 != 1 || stats.Avg7 != 197 {
+		t.Errorf("7-day = %v over %d, want 197 over 1", stats.Avg7, stats.Count7)
+	}
+	if stats.Count30 != 4 || stats.Avg30 != 198.5 {
+		t.Errorf("30-day = %v over %d, want 198.5 over 4", stats.Avg30, stats.Count30)
+	}
+	if !stats.HasRate || math.Abs(stats.WeeklyRate+1) > 1e-9 {
+		t.Errorf("weekly rate = %v, want -1", stats.WeeklyRate)
+	}
+
+	// Goal below current weight: projected at 1 lb/week.
+	SetAthleteGoalWeight(db, a.ID, 190)
+	stats, _ = BodyWeightStats(db, a.ID)
+	if !stats.HasProjection() || stats.WeeksToGoal() != 7 {
+		t.Errorf("weeks to goal = %d (projection %v), want 7", stats.WeeksToGoal(), stats.HasProjection())
+	}
+
+	// Goal above current weight while losing: no projection.
+	SetAthleteGoalWeight(db, a.ID, 210)
+	stats, _ = BodyWeightStats(db, a.ID)
+	if stats.HasProjection() {
+		t.Error("expected no projection when trending away from goal")
+	}
+}
+
+func TestConvertWeight_RoundTrip(t *testing.T) {
+	lbs := ConvertWeight(100, "kg", "lbs")
+	if math.Abs(lbs-220.46) > 0.005 {
+		t.Errorf("100 kg = %.4f lbs, want 220.46", lbs)
+	}
+	if kg := ConvertWeight(220.46, "lbs", "kg"); math.Abs(kg-100) > 0.005 {
+		t.Errorf("220.46 lbs = %.4f kg, want 100", kg)
+	}
+	if kg := ConvertWeight(lbs, "lbs", "kg"); math.Abs(kg-100) > 1e-9 {
+		t.Errorf("round trip = %v kg, want 100", kg)
+	}
+	if got := ConvertWeight(185, "lbs", "lbs"); got != 185 {
+		t.Errorf("same unit = %v, want 185", got)
+	}
+}
+
+func TestCreateBodyWeightInUnit(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Metric Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	bw, err := CreateBodyWeightInUnit(db, a.ID, "2026-02-01", 100, "kg", "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if bw.Unit != "kg" {
+		t.Errorf("unit = %q, want kg", bw.Unit)
+	}
+	if math.Abs(bw.Weight-220.46) > 0.005 {
+		t.Errorf("stored weight = %.4f, want 220.46 lbs", bw.Weight)
+	}
+	if math.Abs(bw.WeightIn("kg")-100) > 1e-9 {
+		t.Errorf("WeightIn(kg) = %v, want 100", bw.WeightIn("kg"))
+	}
+
+	plain, _ := CreateBodyWeight(db, a.ID, "2026-02-02", 185, "")
+	if plain.Unit != "lbs" || plain.Weight != 185 {
+		t.Errorf("CreateBodyWeight = %.1f %s, want 185 lbs", plain.Weight, plain.Unit)
+	}
+
+	_, err = CreateBodyWeightInUnit(db, a.ID, "2026-02-03", 14, "stone", "")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("invalid unit: err = %v, want ErrInvalidInput", err)
+	}
+}
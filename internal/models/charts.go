@@ -8,6 +8,10 @@ import (
 	"time"
 )
 
+// setVolumeSQL is the volume of one workout_sets row (alias ws). Each-side
+// reps are logged per side, so they count twice.
+const setVolumeSQL = `ws.reps * COALESCE(ws.weight, 0) * CASE ws.rep_type WHEN 'each_side' THEN 2 ELSE 1 END`
+
 // ChartPoint represents a single data point for SVG line/area charts.
 type ChartPoint struct {
 	X     float64 // SVG x coordinate
@@ -180,7 +184,8 @@ func formatChartValue(v float64) string {
 }
 
 // BodyWeightChartData returns chart data for an athlete's body weight history.
-// Returns the last `limit` entries in chronological order.
+// Returns the last `limit` entries in chronological order, converted from the
+// stored lbs to unit.
 func BodyWeightChartData(db *sql.DB, athleteID int64, limit int, unit string) (*ChartData, error) {
 	if limit <= 0 {
 		limit = 30
@@ -205,7 +210,7 @@ func BodyWeightChartData(db *sql.DB, athleteID int64, limit int, unit string) (*
 			return nil, fmt.Errorf("models: scan body weight chart: %w", err)
 		}
 		dates = append(dates, normalizeDate(d))
-		values = append(values, w)
+		values = append(values, ConvertWeight(w, "lbs", unit))
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -221,7 +226,8 @@ func BodyWeightChartData(db *sql.DB, athleteID int64, limit int, unit string) (*
 }
 
 // TrainingMaxChartData returns chart data for a training max progression.
-// Returns all TM records in chronological order.
+// Returns all TM records in chronological order, converted from the stored lbs
+// to unit.
 func TrainingMaxChartData(db *sql.DB, athleteID, exerciseID int64, unit string) (*ChartData, error) {
 	rows, err := db.Query(`
 		SELECT effective_date, weight FROM training_maxes
@@ -242,7 +248,7 @@ func TrainingMaxChartData(db *sql.DB, athleteID, exerciseID int64, unit string)
 			return nil, fmt.Errorf("models: scan TM chart: %w", err)
 		}
 		dates = append(dates, normalizeDate(d))
-		values = append(values, w)
+		values = append(values, ConvertWeight(w, "lbs", unit))
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -275,7 +281,7 @@ func ExerciseVolumeChart(db *sql.DB, athleteID, exerciseID int64, limit int) (*E
 	}
 
 	rows, err := db.Query(`
-		SELECT w.date, SUM(ws.reps * COALESCE(ws.weight, 0)) as volume
+		SELECT w.date, SUM(`+setVolumeSQL+`) as volume
 		FROM workout_sets ws
 		JOIN workouts w ON w.id = ws.workout_id
 		WHERE w.athlete_id = ? AND ws.exercise_id = ?
@@ -352,6 +358,215 @@ func ExerciseVolumeChart(db *sql.DB, athleteID, exerciseID int64, limit int) (*E
 	}, nil
 }
 
+// VolumePoint is one ISO-week bucket of exercise volume.
+type VolumePoint struct {
+	Week      string  `json:"week"`       // ISO week, e.g. "2026-W07"
+	WeekStart string  `json:"week_start"` // Monday of the ISO week (YYYY-MM-DD)
+	Sets      int     `json:"sets"`
+	Reps      int     `json:"reps"`    // rep-based sets, including bodyweight; each-side reps count twice
+	Tonnage   float64 `json:"tonnage"` // reps × weight for weighted rep and each-side sets
+}
+
+// WeeklyVolume returns per-ISO-week volume for an exercise over the last
+// weeks weeks (including the current week), oldest first. Weeks without
+// training are included with zero totals. Tonnage counts weighted sets with
+// rep_type 'reps' or 'each_side'; the rep count also includes bodyweight
+// sets so unloaded work still shows. Each-side sets count both sides. An
+// exerciseID of 0 totals every exercise.
+func WeeklyVolume(db *sql.DB, athleteID, exerciseID int64, weeks int) ([]VolumePoint, error) {
+	if weeks <= 0 {
+		weeks = 12
+	}
+
+	now := time.Now()
+	currentMonday := now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	startMonday := currentMonday.AddDate(0, 0, -7*(weeks-1))
+
+	points := make([]VolumePoint, weeks)
+	index := make(map[string]int, weeks)
+	for i := range points {
+		monday := startMonday.AddDate(0, 0, 7*i)
+		year, week := monday.ISOWeek()
+		key := fmt.Sprintf("%d-W%02d", year, week)
+		points[i] = VolumePoint{Week: key, WeekStart: monday.Format("2006-01-02")}
+		index[key] = i
+	}
+
+	rows, err := db.Query(`
+		SELECT w.date, ws.reps, ws.weight, ws.rep_type
+		FROM workout_sets ws
+		JOIN workouts w ON w.id = ws.workout_id
+		WHERE w.athlete_id = ? AND (? = 0 OR ws.exercise_id = ?) AND date(w.date) >= date(?)`,
+		athleteID, exerciseID, exerciseID, startMonday.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("models: weekly volume: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var date, repType string
+		var reps int
+		var weight sql.NullFloat64
+		if err := rows.Scan(&date, &reps, &weight, &repType); err != nil {
+			return nil, fmt.Errorf("models: scan weekly volume: %w", err)
+		}
+		d, err := time.Parse("2006-01-02", normalizeDate(date))
+		if err != nil {
+			continue
+		}
+		year, week := d.ISOWeek()
+		i, ok := index[fmt.Sprintf("%d-W%02d", year, week)]
+		if !ok {
+			continue
+		}
+		points[i].Sets++
+		if repType == "each_side" {
+			reps *= 2
+		}
+		if repType == "reps" || repType == "each_side" {
+			points[i].Reps += reps
+			if weight.Valid {
+				points[i].Tonnage += float64(reps) * weight.Float64
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate weekly volume: %w", err)
+	}
+	return points, nil
+}
+
+// WorkloadSpikeThreshold is the week-over-week increase in sets or tonnage
+// that WeeklyWorkload flags as a possible overtraining risk.
+const WorkloadSpikeThreshold = 0.30
+
+// WorkloadPoint is one ISO week of an athlete's total training workload
+// across all exercises.
+type WorkloadPoint struct {
+	VolumePoint
+	SetsChange    *float64 `json:"sets_change"`    // fractional change from the previous week; nil if it had no sets
+	TonnageChange *float64 `json:"tonnage_change"` // fractional change from the previous week; nil if it had no tonnage
+	Spike         bool     `json:"spike"`          // sets or tonnage rose by more than WorkloadSpikeThreshold
+}
+
+// WeeklyWorkload returns an athlete's total sets, reps and tonnage per ISO
+// week across all exercises over the last weeks weeks (including the current
+// week), oldest first, counted as in WeeklyVolume. Each week is compared with
+// the one before it and flagged as a spike when sets or tonnage jump by more
+// than WorkloadSpikeThreshold. All logged sets count; there is no warmup flag
+// to exclude.
+func WeeklyWorkload(db *sql.DB, athleteID int64, weeks int) ([]WorkloadPoint, error) {
+	volume, err := WeeklyVolume(db, athleteID, 0, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("models: weekly workload for athlete %d: %w", athleteID, err)
+	}
+
+	points := make([]WorkloadPoint, len(volume))
+	for i, v := range volume {
+		points[i].VolumePoint = v
+		if i == 0 {
+			continue
+		}
+		prev := volume[i-1]
+		points[i].SetsChange = fractionalChange(float64(prev.Sets), float64(v.Sets))
+		points[i].TonnageChange = fractionalChange(prev.Tonnage, v.Tonnage)
+		for _, c := range []*float64{points[i].SetsChange, points[i].TonnageChange} {
+			if c != nil && *c > WorkloadSpikeThreshold {
+				points[i].Spike = true
+			}
+		}
+	}
+	return points, nil
+}
+
+// SpikeLabel describes the largest week-over-week jump, e.g. "+45% tonnage",
+// or "" when the week isn't a spike.
+func (p WorkloadPoint) SpikeLabel() string {
+	if !p.Spike {
+		return ""
+	}
+	label, best := "", 0.0
+	if p.SetsChange != nil && *p.SetsChange > best {
+		label, best = "sets", *p.SetsChange
+	}
+	if p.TonnageChange != nil && *p.TonnageChange > best {
+		label, best = "tonnage", *p.TonnageChange
+	}
+	return fmt.Sprintf("+%.0f%% %s", best*100, label)
+}
+
+// fractionalChange returns (cur-prev)/prev, or nil when prev is zero.
+func fractionalChange(prev, cur float64) *float64 {
+	if prev == 0 {
+		return nil
+	}
+	c := (cur - prev) / prev
+	return &c
+}
+
+// WorkloadBar is one week's bar in the workload chart.
+type WorkloadBar struct {
+	X, Y, Width, Height float64
+	Point               WorkloadPoint
+}
+
+// WorkloadChartData holds bar chart data for weekly workload, scaled by
+// tonnage (or sets when no weighted work was logged).
+type WorkloadChartData struct {
+	Bars    []WorkloadBar
+	Spikes  []WorkloadPoint // weeks flagged as spikes, oldest first
+	HasData bool
+}
+
+// WeeklyWorkloadChart lays out workload points as SVG bars.
+func WeeklyWorkloadChart(points []WorkloadPoint) *WorkloadChartData {
+	data := &WorkloadChartData{}
+	if len(points) == 0 {
+		return data
+	}
+
+	maxTonnage, maxSets := 0.0, 0.0
+	for _, p := range points {
+		maxTonnage = math.Max(maxTonnage, p.Tonnage)
+		maxSets = math.Max(maxSets, float64(p.Sets))
+		if p.Spike {
+			data.Spikes = append(data.Spikes, p)
+		}
+	}
+	if maxSets == 0 {
+		return data
+	}
+	data.HasData = true
+
+	value := func(p WorkloadPoint) float64 {
+		if maxTonnage > 0 {
+			return p.Tonnage / maxTonnage
+		}
+		return float64(p.Sets) / maxSets
+	}
+
+	plotW := chartWidth - chartPadLeft - chartPadRight
+	plotH := chartHeight - chartPadTop - chartPadBot
+	barGap := 4.0
+	barW := (plotW - barGap*float64(len(points)-1)) / float64(len(points))
+	if barW > 40 {
+		barW = 40
+	}
+
+	data.Bars = make([]WorkloadBar, len(points))
+	for i, p := range points {
+		barH := value(p) * plotH
+		data.Bars[i] = WorkloadBar{
+			X:      chartPadLeft + float64(i)*(barW+barGap),
+			Y:      chartPadTop + plotH - barH,
+			Width:  barW,
+			Height: barH,
+			Point:  p,
+		}
+	}
+	return data
+}
+
 // HeatmapCell represents one day in a workout frequency heatmap.
 type HeatmapCell struct {
 	X      float64
@@ -391,7 +606,7 @@ func WorkoutHeatmap(db *sql.DB, athleteID int64) (*HeatmapData, error) {
 
 	// Query workout volumes per day.
 	rows, err := db.Query(`
-		SELECT w.date, SUM(ws.reps * COALESCE(ws.weight, 0)) as volume
+		SELECT w.date, SUM(`+setVolumeSQL+`) as volume
 		FROM workouts w
 		LEFT JOIN workout_sets ws ON ws.workout_id = w.id
 		WHERE w.athlete_id = ?
@@ -518,7 +733,7 @@ func GetDashboardStats(db *sql.DB) (*DashboardStats, error) {
 
 	// Total volume this week.
 	err = db.QueryRow(`
-		SELECT COALESCE(SUM(ws.reps * COALESCE(ws.weight, 0)), 0)
+		SELECT COALESCE(SUM(`+setVolumeSQL+`), 0)
 		FROM workout_sets ws
 		JOIN workouts w ON w.id = ws.workout_id
 		WHERE date(w.date) >= date(?)`, mondayStr).Scan(&stats.WeekVolume)
@@ -526,8 +741,8 @@ func GetDashboardStats(db *sql.DB) (*DashboardStats, error) {
 		return nil, fmt.Errorf("models: dashboard week volume: %w", err)
 	}
 
-	// Total athletes.
-	err = db.QueryRow(`SELECT COUNT(*) FROM athletes`).Scan(&stats.TotalAthletes)
+	// Total active (unarchived) athletes.
+	err = db.QueryRow(`SELECT COUNT(*) FROM athletes WHERE archived = 0`).Scan(&stats.TotalAthletes)
 	if err != nil {
 		return nil, fmt.Errorf("models: dashboard total athletes: %w", err)
 	}
@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"testing"
+	"time"
 )
 
 func TestComputeChartPoints_Empty(t *testing.T) {
@@ -187,7 +188,7 @@ func TestTrainingMaxChartData(t *testing.T) {
 		t.Fatalf("create athlete: %v", err)
 	}
 
-	exercise, err := CreateExercise(db, "Squat", "foundational", "", "", 0)
+	exercise, err := CreateExercise(db, "Squat", "foundational", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
@@ -227,7 +228,7 @@ func TestExerciseVolumeChart(t *testing.T) {
 		t.Fatalf("create athlete: %v", err)
 	}
 
-	exercise, err := CreateExercise(db, "Bench Press", "", "", "", 0)
+	exercise, err := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
@@ -263,6 +264,26 @@ func TestExerciseVolumeChart(t *testing.T) {
 	}
 }
 
+func TestExerciseVolumeChart_EachSideCountsBothSides(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Lunge Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	exercise, _ := CreateExercise(db, "Dumbbell Lunge", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, athlete.ID, "2025-01-01", "", 0)
+	if _, err := AddSet(db, w.ID, exercise.ID, 10, 30, 0, "each_side", "", ""); err != nil {
+		t.Fatalf("add set: %v", err)
+	}
+
+	chart, err := ExerciseVolumeChart(db, athlete.ID, exercise.ID, 20)
+	if err != nil {
+		t.Fatalf("ExerciseVolumeChart: %v", err)
+	}
+	// 10 reps per side × 2 sides × 30 = 600.
+	if len(chart.Bars) != 1 || chart.Bars[0].Volume != 600 {
+		t.Errorf("bars = %+v, want one bar with volume 600", chart.Bars)
+	}
+}
+
 func TestExerciseVolumeChart_Empty(t *testing.T) {
 	db := testDB(t)
 
@@ -284,11 +305,11 @@ func TestWorkoutHeatmap(t *testing.T) {
 	}
 
 	// Create a workout today so heatmap has data.
-	exercise, err := CreateExercise(db, "Deadlift", "", "", "", 0)
+	exercise, err := CreateExercise(db, "Deadlift", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
-	w, err := CreateWorkout(db, athlete.ID, "2025-06-01", "", 0)
+	w, err := CreateWorkout(db, athlete.ID, time.Now().Format("2006-01-02"), "", 0)
 	if err != nil {
 		t.Fatalf("create workout: %v", err)
 	}
@@ -351,3 +372,101 @@ func TestGetDashboardStats(t *testing.T) {
 		t.Errorf("expected 0 week streak, got %d", stats.ConsecutiveWeeks)
 	}
 }
+
+func TestWeeklyVolume(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Volume Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	today := time.Now().Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	tooOld := time.Now().AddDate(0, 0, -70).Format("2006-01-02")
+
+	w1, _ := CreateWorkout(db, athlete.ID, today, "", 0)
+	AddMultipleSets(db, w1.ID, squat.ID, 3, 5, 200, 0, "", "", "") // 3000 tonnage, 15 reps
+	AddSet(db, w1.ID, squat.ID, 10, 0, 0, "", "", "")              // bodyweight: reps only
+	AddSet(db, w1.ID, squat.ID, 30, 45, 0, "seconds", "", "")      // timed: no tonnage or reps
+	w2, _ := CreateWorkout(db, athlete.ID, lastWeek, "", 0)
+	AddSet(db, w2.ID, squat.ID, 8, 100, 0, "each_side", "", "")
+	w3, _ := CreateWorkout(db, athlete.ID, tooOld, "", 0)
+	AddSet(db, w3.ID, squat.ID, 5, 500, 0, "", "", "")
+
+	points, err := WeeklyVolume(db, athlete.ID, squat.ID, 4)
+	if err != nil {
+		t.Fatalf("WeeklyVolume: %v", err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("points = %d, want 4", len(points))
+	}
+
+	cur := points[3]
+	if cur.Sets != 5 || cur.Reps != 25 || cur.Tonnage != 3000 {
+		t.Errorf("current week = %+v, want 5 sets, 25 reps, 3000 tonnage", cur)
+	}
+	prev := points[2]
+	// Each-side reps count both sides: 8 per side → 16 reps, 1600 tonnage.
+	if prev.Sets != 1 || prev.Reps != 16 || prev.Tonnage != 1600 {
+		t.Errorf("previous week = %+v, want 1 set, 16 reps, 1600 tonnage", prev)
+	}
+	if points[0].Sets != 0 || points[0].Week == "" {
+		t.Errorf("oldest week = %+v, want empty bucket", points[0])
+	}
+}
+
+func TestWeeklyWorkload(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Workload Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+
+	twoWeeksAgo := time.Now().AddDate(0, 0, -14).Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	today := time.Now().Format("2006-01-02")
+
+	w1, _ := CreateWorkout(db, athlete.ID, twoWeeksAgo, "", 0)
+	AddMultipleSets(db, w1.ID, squat.ID, 4, 5, 200, 0, "", "", "") // 4000
+	w2, _ := CreateWorkout(db, athlete.ID, lastWeek, "", 0)
+	AddMultipleSets(db, w2.ID, squat.ID, 4, 5, 200, 0, "", "", "") // 4000
+	AddSet(db, w2.ID, bench.ID, 5, 100, 0, "", "", "")             // +500 = 4500, +25% sets
+	w3, _ := CreateWorkout(db, athlete.ID, today, "", 0)
+	AddMultipleSets(db, w3.ID, squat.ID, 5, 5, 200, 0, "", "", "") // 5000
+	AddMultipleSets(db, w3.ID, bench.ID, 2, 5, 100, 0, "", "", "") // +1000 = 6000, +40% sets
+
+	points, err := WeeklyWorkload(db, athlete.ID, 4)
+	if err != nil {
+		t.Fatalf("WeeklyWorkload: %v", err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("points = %d, want 4", len(points))
+	}
+
+	if points[0].Sets != 0 || points[0].SetsChange != nil || points[0].Spike {
+		t.Errorf("oldest week = %+v, want empty and unflagged", points[0])
+	}
+	if p := points[1]; p.Sets != 4 || p.Tonnage != 4000 || p.Spike {
+		t.Errorf("two weeks ago = %+v, want 4 sets, 4000 tonnage, no spike after an empty week", p)
+	}
+	if p := points[2]; p.Sets != 5 || p.Tonnage != 4500 || p.Spike {
+		t.Errorf("last week = %+v, want 5 sets, 4500 tonnage, no spike", p)
+	}
+	cur := points[3]
+	if cur.Sets != 7 || cur.Tonnage != 6000 {
+		t.Errorf("current week = %+v, want 7 sets, 6000 tonnage", cur)
+	}
+	if !cur.Spike {
+		t.Error("expected a 40% jump in sets to be flagged as a spike")
+	}
+	if got := cur.SpikeLabel(); got != "+40% sets" {
+		t.Errorf("SpikeLabel = %q, want +40%% sets", got)
+	}
+
+	chart := WeeklyWorkloadChart(points)
+	if !chart.HasData || len(chart.Bars) != 4 {
+		t.Fatalf("chart = %+v, want 4 bars", chart)
+	}
+	if len(chart.Spikes) != 1 || chart.Spikes[0].Week != cur.Week {
+		t.Errorf("chart spikes = %+v, want the current week", chart.Spikes)
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CheckIn records that an athlete trained on a day without logging a
+// workout, such as a mobility or recovery session.
+type CheckIn struct {
+	ID        int64
+	AthleteID int64
+	Date      string // YYYY-MM-DD
+	Notes     sql.NullString
+	CreatedBy sql.NullInt64
+	CreatedAt time.Time
+}
+
+// CheckInAthlete records attendance for an athlete on date, defaulting to
+// today. Checking in again on the same day keeps the single check-in and
+// replaces its note when a new one is given. Returns ErrInvalidInput for a
+// malformed date.
+func CheckInAthlete(db *sql.DB, athleteID int64, date, notes string, createdBy int64) (*CheckIn, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("models: check in date %q: %w", date, ErrInvalidInput)
+	}
+
+	var notesVal sql.NullString
+	if notes != "" {
+		notesVal = sql.NullString{String: notes, Valid: true}
+	}
+	var createdByVal sql.NullInt64
+	if createdBy != 0 {
+		createdByVal = sql.NullInt64{Int64: createdBy, Valid: true}
+	}
+
+	var id int64
+	err := db.QueryRow(
+		`INSERT INTO check_ins (athlete_id, date, notes, created_by)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(athlete_id, date) DO UPDATE SET notes = COALESCE(excluded.notes, check_ins.notes)
+		 RETURNING id`,
+		athleteID, date, notesVal, createdByVal,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("models: check in athlete %d on %s: %w", athleteID, date, err)
+	}
+
+	return GetCheckInByID(db, id)
+}
+
+// GetCheckInByID retrieves a single check-in by primary key.
+func GetCheckInByID(db *sql.DB, id int64) (*CheckIn, error) {
+	c := &CheckIn{}
+	err := db.QueryRow(
+		`SELECT id, athlete_id, date, notes, created_by, created_at
+		 FROM check_ins WHERE id = ?`, id,
+	).Scan(&c.ID, &c.AthleteID, &c.Date, &c.Notes, &c.CreatedBy, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get check-in %d: %w", id, err)
+	}
+	c.Date = normalizeDate(c.Date)
+	return c, nil
+}
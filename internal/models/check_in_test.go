@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckInAthlete(t *testing.T) {
+	db := testDB(t)
+	coach := seedCoachUser(t, db)
+	a, _ := CreateAthlete(db, "Recovering", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+
+	first, err := CheckInAthlete(db, a.ID, "2026-02-02", "Mobility circuit", coach.ID)
+	if err != nil {
+		t.Fatalf("check in: %v", err)
+	}
+	if first.Date != "2026-02-02" || first.Notes.String != "Mobility circuit" || first.CreatedBy.Int64 != coach.ID {
+		t.Errorf("check-in = %+v, want 2026-02-02 with note by coach", first)
+	}
+
+	t.Run("same day keeps one check-in", func(t *testing.T) {
+		again, err := CheckInAthlete(db, a.ID, "2026-02-02", "", coach.ID)
+		if err != nil {
+			t.Fatalf("check in again: %v", err)
+		}
+		if again.ID != first.ID || again.Notes.String != "Mobility circuit" {
+			t.Errorf("repeat check-in = %+v, want id %d with the original note", again, first.ID)
+		}
+		updated, err := CheckInAthlete(db, a.ID, "2026-02-02", "Foam rolling", coach.ID)
+		if err != nil {
+			t.Fatalf("check in with new note: %v", err)
+		}
+		if updated.ID != first.ID || updated.Notes.String != "Foam rolling" {
+			t.Errorf("updated check-in = %+v, want id %d with the new note", updated, first.ID)
+		}
+	})
+
+	t.Run("defaults to today", func(t *testing.T) {
+		c, err := CheckInAthlete(db, a.ID, "", "", 0)
+		if err != nil {
+			t.Fatalf("check in: %v", err)
+		}
+		if today := time.Now().Format("2006-01-02"); c.Date != today || c.CreatedBy.Valid {
+			t.Errorf("check-in = %+v, want today (%s) with no creator", c, today)
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		if _, err := CheckInAthlete(db, a.ID, "02/02/2026", "", 0); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("shown in the journal", func(t *testing.T) {
+		page, err := ListJournalPage(db, a.ID, true, JournalFilter{Types: []string{"check-in"}}, 0)
+		if err != nil {
+			t.Fatalf("journal page: %v", err)
+		}
+		if len(page.Entries) != 2 {
+			t.Fatalf("got %d check-in entries, want 2", len(page.Entries))
+		}
+		e := page.Entries[len(page.Entries)-1]
+		if e.Type != "check_in" || e.Detail != "Foam rolling" || e.Author == "" {
+			t.Errorf("journal entry = %+v, want check_in with note and author", e)
+		}
+	})
+}
+
+func TestRosterSummary_CheckInsCountTowardAdherence(t *testing.T) {
+	db := testDB(t)
+	coach := seedCoachUser(t, db)
+	coachID := sql.NullInt64{Int64: coach.ID, Valid: true}
+	tmpl, _ := CreateProgramTemplate(db, nil, "Three Day", "", 4, 3, false, "", 0, "")
+
+	a, _ := CreateAthlete(db, "Mixed", "", "", "", "", "", "", coachID, true)
+	AssignProgram(db, a.ID, tmpl.ID, "2020-01-01", "", "", "primary", "")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	CreateWorkout(db, a.ID, yesterday, "", 0)
+	CheckInAthlete(db, a.ID, yesterday, "", 0) // same day counts once
+	CheckInAthlete(db, a.ID, time.Now().AddDate(0, 0, -3).Format("2006-01-02"), "recovery", 0)
+
+	roster, err := RosterSummary(db, coachID)
+	if err != nil {
+		t.Fatalf("roster summary: %v", err)
+	}
+	if len(roster) != 1 {
+		t.Fatalf("got %d roster entries, want 1", len(roster))
+	}
+	if e := roster[0]; e.LoggedSessions != 2 || e.AdherencePercent() != 67 {
+		t.Errorf("adherence = %d/%d (%d%%), want 2/3 (67%%)", e.LoggedSessions, e.ExpectedSessions, e.AdherencePercent())
+	}
+}
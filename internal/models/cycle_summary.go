@@ -14,6 +14,11 @@ type TMSuggestion struct {
 	Increment    float64 // from progression rule
 	SuggestedTM  float64 // current + increment
 	AMRAPResults []AMRAPResult
+
+	// Recommended is false when the rule's condition was not met and the
+	// TM should be held. Reason explains the decision for the review UI.
+	Recommended bool
+	Reason      string
 }
 
 // IncrementLabel returns a formatted increment (e.g. "10", "5", "2.5").
@@ -216,6 +221,7 @@ func GetCycleSummary(db *sql.DB, program *AthleteProgram, today time.Time) (*Cyc
 			continue // no TM set — skip suggestion
 		}
 
+		recommended, reason := evaluateProgressionRule(rule, amrapByExercise[rule.ExerciseID])
 		suggestions = append(suggestions, &TMSuggestion{
 			ExerciseID:   rule.ExerciseID,
 			ExerciseName: rule.ExerciseName,
@@ -223,6 +229,8 @@ func GetCycleSummary(db *sql.DB, program *AthleteProgram, today time.Time) (*Cyc
 			Increment:    rule.Increment,
 			SuggestedTM:  currentTM + rule.Increment,
 			AMRAPResults: amrapByExercise[rule.ExerciseID],
+			Recommended:  recommended,
+			Reason:       reason,
 		})
 	}
 
@@ -235,3 +243,21 @@ func GetCycleSummary(db *sql.DB, program *AthleteProgram, today time.Time) (*Cyc
 		CycleEnd:    cycleEnd,
 	}, nil
 }
+
+// evaluateProgressionRule decides whether a rule's bump should be suggested
+// given the exercise's AMRAP results for the cycle (ordered by date).
+// Unconditional rules always recommend the bump.
+func evaluateProgressionRule(rule *ProgressionRule, amraps []AMRAPResult) (bool, string) {
+	if rule.Condition.String != ConditionAMRAPMinReps || !rule.Threshold.Valid {
+		return true, "Bump every cycle"
+	}
+	threshold := int(rule.Threshold.Int64)
+	if len(amraps) == 0 {
+		return false, fmt.Sprintf("Hold: no AMRAP logged (needs %d+ reps)", threshold)
+	}
+	last := amraps[len(amraps)-1]
+	if last.Reps >= threshold {
+		return true, fmt.Sprintf("Last AMRAP %d reps ≥ %d", last.Reps, threshold)
+	}
+	return false, fmt.Sprintf("Hold: last AMRAP %d reps < %d", last.Reps, threshold)
+}
@@ -24,7 +24,7 @@ func TestGetCycleSummary_NoCycleCompletedReturnsNil(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Test", "", "", "", "", "", "", sql.NullInt64{}, true)
-	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 3, 4, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 3, 4, false, "", 0, "")
 	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
 
 	// No workouts logged — still in cycle 1.
@@ -41,24 +41,24 @@ func TestGetCycleSummary_AfterFirstCycle(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Test", "", "", "", "", "", "", sql.NullInt64{}, true)
-	squat, _ := CreateExercise(db, "Squat", "", "", "", 0)
-	bench, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 
 	// Create a 3-week × 2-day program (6 workouts per cycle).
-	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 3, 2, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 3, 2, false, "", 0, "")
 	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
 
 	// Add AMRAP prescribed sets (reps=NULL) on week 3 day 1.
-	CreatePrescribedSet(db, tmpl.ID, squat.ID, 3, 1, 1, nil, ptrFloat(95), nil, 0, "", "")
-	CreatePrescribedSet(db, tmpl.ID, bench.ID, 3, 1, 2, nil, ptrFloat(95), nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 3, 1, 1, nil, nil, ptrFloat(95), nil, nil, nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, bench.ID, 3, 1, 2, nil, nil, ptrFloat(95), nil, nil, nil, 0, "", "")
 
 	// Add some non-AMRAP sets.
 	five := 5
-	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &five, ptrFloat(65), nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &five, nil, ptrFloat(65), nil, nil, nil, 0, "", "")
 
 	// Add progression rules.
-	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0)
-	SetProgressionRule(db, tmpl.ID, bench.ID, 5.0)
+	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0, "", 0)
+	SetProgressionRule(db, tmpl.ID, bench.ID, 5.0, "", 0)
 
 	// Set training maxes.
 	SetTrainingMax(db, a.ID, squat.ID, 300, "2026-01-01", "")
@@ -135,13 +135,13 @@ func TestGetCycleSummary_NoTMSkipsExercise(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Test", "", "", "", "", "", "", sql.NullInt64{}, true)
-	squat, _ := CreateExercise(db, "Squat", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 1, 2, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 1, 2, false, "", 0, "")
 	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
 
 	// Progression rule but no TM set.
-	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0)
+	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0, "", 0)
 
 	// Complete one cycle (2 workouts).
 	CreateWorkout(db, a.ID, "2026-01-02", "", ap.ID)
@@ -160,6 +160,61 @@ func TestGetCycleSummary_NoTMSkipsExercise(t *testing.T) {
 	}
 }
 
+func TestGetCycleSummary_AMRAPCondition(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Test", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	press, _ := CreateExercise(db, "Press", "", "", "", "", 0)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "531", "", 1, 2, false, "", 0, "")
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
+
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, nil, nil, ptrFloat(85), nil, nil, nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 2, nil, nil, ptrFloat(85), nil, nil, nil, 0, "", "")
+
+	// Squat hits 8 vs threshold 8; bench hits 5 vs threshold 8; press has
+	// no AMRAP logged at all.
+	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0, ConditionAMRAPMinReps, 8)
+	SetProgressionRule(db, tmpl.ID, bench.ID, 5.0, ConditionAMRAPMinReps, 8)
+	SetProgressionRule(db, tmpl.ID, press.ID, 5.0, ConditionAMRAPMinReps, 6)
+
+	SetTrainingMax(db, a.ID, squat.ID, 300, "2026-01-01", "")
+	SetTrainingMax(db, a.ID, bench.ID, 200, "2026-01-01", "")
+	SetTrainingMax(db, a.ID, press.ID, 120, "2026-01-01", "")
+
+	w, _ := CreateWorkout(db, a.ID, "2026-01-02", "", ap.ID)
+	AddSet(db, w.ID, squat.ID, 8, 255, 0, "reps", "", "")
+	AddSet(db, w.ID, bench.ID, 5, 170, 0, "reps", "", "")
+	CreateWorkout(db, a.ID, "2026-01-03", "", ap.ID)
+
+	summary, err := GetCycleSummary(db, ap, mustParseDate("2026-01-10"))
+	if err != nil {
+		t.Fatalf("get cycle summary: %v", err)
+	}
+	if summary == nil || len(summary.Suggestions) != 3 {
+		t.Fatalf("expected 3 suggestions, got %+v", summary)
+	}
+
+	want := map[string]bool{"Bench Press": false, "Press": false, "Squat": true}
+	for _, s := range summary.Suggestions {
+		if s.Recommended != want[s.ExerciseName] {
+			t.Errorf("%s recommended = %v, want %v (reason %q)", s.ExerciseName, s.Recommended, want[s.ExerciseName], s.Reason)
+		}
+		if s.Reason == "" {
+			t.Errorf("%s has no reason", s.ExerciseName)
+		}
+	}
+}
+
+func TestEvaluateProgressionRule_Unconditional(t *testing.T) {
+	ok, reason := evaluateProgressionRule(&ProgressionRule{Increment: 5}, nil)
+	if !ok || reason == "" {
+		t.Errorf("unconditional rule = %v %q, want recommended with a reason", ok, reason)
+	}
+}
+
 func TestTMSuggestion_IncrementLabel(t *testing.T) {
 	tests := []struct {
 		increment float64
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// DashboardPRWindowDays is how recent an all-time best must be to appear in
+// an athlete dashboard's recent PRs.
+const DashboardPRWindowDays = 30
+
+// DashboardPR is an exercise whose all-time best estimated 1RM was set
+// recently.
+type DashboardPR struct {
+	ExerciseID   int64
+	ExerciseName string
+	Best         *OneRepMaxEstimate
+}
+
+// AthleteDashboard is an at-a-glance summary of an athlete's training.
+type AthleteDashboard struct {
+	Athlete        *Athlete
+	Program        *AthleteProgram // active primary program; nil if none
+	Prescription   *Prescription   // today's or the next scheduled session; nil without a program
+	LastWorkout    *Workout        // most recent workout; nil if none
+	RecentPRs      []*DashboardPR  // newest first
+	PendingReviews int             // completed workouts awaiting coach review
+}
+
+// BuildAthleteDashboard gathers an athlete's next prescribed session, last
+// workout, program progress, recent PRs and pending review count from the
+// existing program, workout, 1RM and review queries. Returns ErrNotFound if
+// the athlete does not exist.
+func BuildAthleteDashboard(db *sql.DB, athleteID int64) (*AthleteDashboard, error) {
+	athlete, err := GetAthleteByID(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	d := &AthleteDashboard{Athlete: athlete}
+
+	now := time.Now()
+	d.Program, err = GetActiveProgram(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Program != nil {
+		d.Prescription, err = GetPrescription(db, d.Program, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := ListWorkouts(db, athleteID, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Workouts) > 0 {
+		d.LastWorkout = page.Workouts[0]
+	}
+
+	d.RecentPRs, err = recentPRs(db, athleteID, now.AddDate(0, 0, -DashboardPRWindowDays))
+	if err != nil {
+		return nil, err
+	}
+
+	d.PendingReviews, err = CountPendingReviews(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// recentPRs returns the athlete's actively assigned exercises whose all-time
+// best estimated 1RM was set on or after since, newest first.
+func recentPRs(db *sql.DB, athleteID int64, since time.Time) ([]*DashboardPR, error) {
+	assignments, err := ListActiveAssignments(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := since.Format("2006-01-02")
+	var prs []*DashboardPR
+	for _, a := range assignments {
+		summary, err := BestEstimated1RM(db, athleteID, a.ExerciseID)
+		if err != nil {
+			return nil, err
+		}
+		if summary.AllTime == nil || summary.AllTime.Date < cutoff {
+			continue
+		}
+		prs = append(prs, &DashboardPR{
+			ExerciseID:   a.ExerciseID,
+			ExerciseName: a.ExerciseName,
+			Best:         summary.AllTime,
+		})
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prs[i].Best.Date > prs[j].Best.Date
+	})
+	return prs, nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestBuildAthleteDashboard(t *testing.T) {
+	db := testDB(t)
+
+	t.Run("empty athlete", func(t *testing.T) {
+		a, _ := CreateAthlete(db, "New", "", "", "", "", "", "", sql.NullInt64{}, true)
+		d, err := BuildAthleteDashboard(db, a.ID)
+		if err != nil {
+			t.Fatalf("build dashboard: %v", err)
+		}
+		if d.Program != nil || d.Prescription != nil || d.LastWorkout != nil {
+			t.Errorf("expected no program, prescription or workout, got %+v", d)
+		}
+		if len(d.RecentPRs) != 0 || d.PendingReviews != 0 {
+			t.Errorf("expected no PRs or pending reviews, got %d and %d", len(d.RecentPRs), d.PendingReviews)
+		}
+	})
+
+	t.Run("missing athlete", func(t *testing.T) {
+		if _, err := BuildAthleteDashboard(db, 9999); err == nil {
+			t.Error("expected error for missing athlete")
+		}
+	})
+
+	t.Run("active athlete", func(t *testing.T) {
+		a, _ := CreateAthlete(db, "Active", "", "", "", "", "", "", sql.NullInt64{}, true)
+		squat, _ := CreateExercise(db, "Dashboard Squat", "", "", "", "", 0)
+		bench, _ := CreateExercise(db, "Dashboard Bench", "", "", "", "", 0)
+		AssignExercise(db, a.ID, squat.ID, 0)
+		AssignExercise(db, a.ID, bench.ID, 0)
+
+		tmpl, _ := CreateProgramTemplate(db, nil, "Dashboard Program", "", 4, 3, false, "", 0, "")
+		AssignProgram(db, a.ID, tmpl.ID, "2020-01-01", "", "", "primary", "")
+
+		recent := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+		old := time.Now().AddDate(0, 0, -90).Format("2006-01-02")
+
+		// Squat's best is recent; bench's best is old.
+		oldWorkout, _ := CreateWorkout(db, a.ID, old, "", 0)
+		AddSet(db, oldWorkout.ID, squat.ID, 5, 200, 0, "", "", "")
+		AddSet(db, oldWorkout.ID, bench.ID, 5, 150, 0, "", "", "")
+		recentWorkout, _ := CreateWorkout(db, a.ID, recent, "", 0)
+		AddSet(db, recentWorkout.ID, squat.ID, 5, 225, 0, "", "", "")
+		AddSet(db, recentWorkout.ID, bench.ID, 5, 135, 0, "", "", "")
+		SetWorkoutPendingReview(db, recentWorkout.ID)
+
+		d, err := BuildAthleteDashboard(db, a.ID)
+		if err != nil {
+			t.Fatalf("build dashboard: %v", err)
+		}
+		if d.Program == nil || d.Program.TemplateName != "Dashboard Program" {
+			t.Errorf("expected active program, got %+v", d.Program)
+		}
+		if d.Prescription == nil || d.Prescription.CurrentWeek != 1 {
+			t.Errorf("expected prescription at week 1, got %+v", d.Prescription)
+		}
+		if d.LastWorkout == nil || d.LastWorkout.ID != recentWorkout.ID {
+			t.Errorf("expected last workout %d, got %+v", recentWorkout.ID, d.LastWorkout)
+		}
+		if len(d.RecentPRs) != 1 || d.RecentPRs[0].ExerciseID != squat.ID {
+			t.Fatalf("expected one recent squat PR, got %+v", d.RecentPRs)
+		}
+		if d.RecentPRs[0].Best.Weight != 225 {
+			t.Errorf("PR weight = %v, want 225", d.RecentPRs[0].Best.Weight)
+		}
+		if d.PendingReviews != 1 {
+			t.Errorf("PendingReviews = %d, want 1", d.PendingReviews)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidDemoURL is returned when a demo URL is not an absolute http(s)
+// link.
+var ErrInvalidDemoURL = errors.New("demo URL must be an http or https link")
+
+var (
+	youTubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoIDPattern   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// ValidateDemoURL reports whether raw is an absolute http or https URL.
+// An empty string is valid and means no demo.
+func ValidateDemoURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidDemoURL
+	}
+	return nil
+}
+
+// NormalizeDemoURL converts a YouTube or Vimeo watch link into an embeddable
+// player URL. Returns "" for other hosts, which callers render as a plain
+// link instead.
+func NormalizeDemoURL(raw string) string {
+	if id := YouTubeVideoID(raw); id != "" {
+		return "https://www.youtube-nocookie.com/embed/" + id
+	}
+	if ValidateDemoURL(raw) != nil || raw == "" {
+		return ""
+	}
+	u, _ := url.Parse(raw)
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch host {
+	case "vimeo.com":
+		if len(segments) >= 1 && vimeoIDPattern.MatchString(segments[0]) {
+			return "https://player.vimeo.com/video/" + segments[0]
+		}
+	case "player.vimeo.com":
+		if len(segments) == 2 && segments[0] == "video" && vimeoIDPattern.MatchString(segments[1]) {
+			return "https://player.vimeo.com/video/" + segments[1]
+		}
+	}
+	return ""
+}
+
+// YouTubeVideoID returns the video ID of a YouTube watch, share, embed or
+// Shorts link, or "" if raw isn't one.
+func YouTubeVideoID(raw string) string {
+	if ValidateDemoURL(raw) != nil || raw == "" {
+		return ""
+	}
+	u, _ := url.Parse(raw)
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch host {
+	case "youtube.com", "youtube-nocookie.com":
+		switch {
+		case u.Path == "/watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live"):
+			id = segments[1]
+		}
+	case "youtu.be":
+		if len(segments) == 1 {
+			id = segments[0]
+		}
+	}
+	if !youTubeIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// DemoEmbedURL returns the embeddable player URL for the exercise's demo
+// video, or "" when there is no demo or its host is not recognized.
+func (e *Exercise) DemoEmbedURL() string {
+	if !e.DemoURL.Valid {
+		return ""
+	}
+	return NormalizeDemoURL(e.DemoURL.String)
+}
+
+// DemoThumbURL returns the URL of the cached thumbnail for the exercise's
+// demo video, or "" when the demo isn't on YouTube.
+func (e *Exercise) DemoThumbURL() string {
+	if !e.DemoURL.Valid || YouTubeVideoID(e.DemoURL.String) == "" {
+		return ""
+	}
+	return fmt.Sprintf("/exercise-thumbs/%d", e.ID)
+}
+
+// ListDemoVideoIDs returns the distinct YouTube video IDs used by exercise
+// demos, including archived exercises.
+func ListDemoVideoIDs(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT demo_url FROM exercises WHERE demo_url IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list demo urls: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var ids []string
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("models: scan demo url: %w", err)
+		}
+		if id := YouTubeVideoID(raw); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate demo urls: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeDemoURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&t=30s", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"},
+		{"https://youtube.com/shorts/dQw4w9WgXcQ", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"},
+		{"https://vimeo.com/76979871", "https://player.vimeo.com/video/76979871"},
+		{"https://player.vimeo.com/video/76979871", "https://player.vimeo.com/video/76979871"},
+		{"https://www.youtube.com/watch?v=bad", ""},
+		{"https://example.com/bench.mp4", ""},
+		{"javascript:alert(1)", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := NormalizeDemoURL(tt.raw); got != tt.want {
+				t.Errorf("NormalizeDemoURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateExercise_RejectsNonHTTPDemoURL(t *testing.T) {
+	db := testDB(t)
+
+	for _, raw := range []string{"javascript:alert(1)", "ftp://example.com/video", "/relative/path"} {
+		if _, err := CreateExercise(db, "Bad "+raw, "", "", "", raw, 0); !errors.Is(err, ErrInvalidDemoURL) {
+			t.Errorf("CreateExercise(demo %q) err = %v, want ErrInvalidDemoURL", raw, err)
+		}
+	}
+
+	e, err := CreateExercise(db, "Squat", "", "", "", "https://youtu.be/dQw4w9WgXcQ", 0)
+	if err != nil {
+		t.Fatalf("create with valid demo: %v", err)
+	}
+	if _, err := UpdateExercise(db, e.ID, "Squat", "", "", "", "javascript:void(0)", 0); !errors.Is(err, ErrInvalidDemoURL) {
+		t.Errorf("UpdateExercise err = %v, want ErrInvalidDemoURL", err)
+	}
+	if got := e.DemoEmbedURL(); got != "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ" {
+		t.Errorf("DemoEmbedURL = %q", got)
+	}
+}
+
+func TestYouTubeVideoID(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://vimeo.com/76979871", ""},
+		{"https://www.youtube.com/watch?v=../../x", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := YouTubeVideoID(tt.raw); got != tt.want {
+			t.Errorf("YouTubeVideoID(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestListDemoVideoIDs(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateExercise(db, "Squat", "", "", "", "https://youtu.be/dQw4w9WgXcQ", 0)
+	CreateExercise(db, "Front Squat", "", "", "", "https://www.youtube.com/watch?v=dQw4w9WgXcQ", 0)
+	CreateExercise(db, "Bench", "", "", "", "https://vimeo.com/76979871", 0)
+	CreateExercise(db, "Row", "", "", "", "", 0)
+
+	ids, err := ListDemoVideoIDs(db)
+	if err != nil {
+		t.Fatalf("ListDemoVideoIDs: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "dQw4w9WgXcQ" {
+		t.Errorf("ids = %v, want [dQw4w9WgXcQ]", ids)
+	}
+	if got := a.DemoThumbURL(); got != "/exercise-thumbs/"+fmt.Sprint(a.ID) {
+		t.Errorf("DemoThumbURL = %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EmailVerificationTokenLifetime is how long an emailed verification link
+// stays valid.
+const EmailVerificationTokenLifetime = 24 * time.Hour
+
+// CreateEmailVerificationToken issues a single-use token confirming that the
+// user receives mail at email, and returns it. Any earlier unused tokens for
+// the user stop working.
+func CreateEmailVerificationToken(db *sql.DB, userID int64, email string) (string, error) {
+	token, err := generateToken(32) // 256-bit token
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("models: begin verification token tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM email_verification_tokens WHERE user_id = ? AND used_at IS NULL`, userID); err != nil {
+		return "", fmt.Errorf("models: replace verification tokens for user %d: %w", userID, err)
+	}
+	_, err = tx.Exec(
+		`INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at) VALUES (?, ?, ?, ?)`,
+		userID, email, hashResetToken(token), time.Now().Add(EmailVerificationTokenLifetime),
+	)
+	if err != nil {
+		return "", fmt.Errorf("models: create verification token for user %d: %w", userID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("models: commit verification token: %w", err)
+	}
+	return token, nil
+}
+
+// VerifyEmail uses a verification token to mark the user's address as
+// verified. The token is consumed, so it works at most once. Returns
+// ErrNotFound if the token is unknown, used or expired, or if the user's
+// address has changed since it was issued.
+func VerifyEmail(db *sql.DB, token string) (*User, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin verify email tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	var email string
+	err = tx.QueryRow(
+		`UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP
+		 WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		 RETURNING user_id, email`,
+		hashResetToken(token), time.Now(),
+	).Scan(&userID, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: consume verification token: %w", err)
+	}
+
+	result, err := tx.Exec(
+		`UPDATE users SET email_verified_at = CURRENT_TIMESTAMP WHERE id = ? AND email = ?`,
+		userID, email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: verify email for user %d: %w", userID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit verify email: %w", err)
+	}
+	return GetUserByID(db, userID)
+}
+
+// IsEmailVerified reports whether the user's current email address has been
+// verified.
+func IsEmailVerified(db *sql.DB, userID int64) bool {
+	var verified bool
+	err := db.QueryRow(
+		`SELECT email IS NOT NULL AND email_verified_at IS NOT NULL FROM users WHERE id = ?`, userID,
+	).Scan(&verified)
+	return err == nil && verified
+}
+
+// DeleteExpiredEmailVerificationTokens removes verification tokens that have
+// expired or been used. Returns the number of tokens deleted.
+func DeleteExpiredEmailVerificationTokens(db *sql.DB) (int64, error) {
+	result, err := db.Exec(
+		`DELETE FROM email_verification_tokens WHERE used_at IS NOT NULL OR expires_at < ?`,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete expired verification tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
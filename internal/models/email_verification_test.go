@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "verifyme", "", "password123", "verifyme@example.com", false, false, sql.NullInt64{})
+	if IsEmailVerified(db, u.ID) {
+		t.Fatal("new address should not be verified")
+	}
+
+	token, err := CreateEmailVerificationToken(db, u.ID, u.Email.String)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	got, err := VerifyEmail(db, token)
+	if err != nil {
+		t.Fatalf("verify email: %v", err)
+	}
+	if got.ID != u.ID {
+		t.Errorf("user = %d, want %d", got.ID, u.ID)
+	}
+	if !IsEmailVerified(db, u.ID) {
+		t.Error("address should be verified")
+	}
+	if _, err := VerifyEmail(db, token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("reused token: err = %v, want ErrNotFound", err)
+	}
+
+	// Changing the address clears verification.
+	if _, err := UpdateUser(db, u.ID, "verifyme", "", "new@example.com", sql.NullInt64{}, false, false); err != nil {
+		t.Fatalf("update user: %v", err)
+	}
+	if IsEmailVerified(db, u.ID) {
+		t.Error("changed address should not be verified")
+	}
+}
+
+func TestVerifyEmail_AddressChanged(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "mover", "", "password123", "old@example.com", false, false, sql.NullInt64{})
+	token, err := CreateEmailVerificationToken(db, u.ID, u.Email.String)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if _, err := UpdateUser(db, u.ID, "mover", "", "new@example.com", sql.NullInt64{}, false, false); err != nil {
+		t.Fatalf("update user: %v", err)
+	}
+
+	if _, err := VerifyEmail(db, token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("token for old address: err = %v, want ErrNotFound", err)
+	}
+	if IsEmailVerified(db, u.ID) {
+		t.Error("new address should not be verified by a link sent to the old one")
+	}
+}
@@ -19,10 +19,21 @@ type Equipment struct {
 	ID          int64
 	Name        string
 	Description sql.NullString
+	Quantity    int             // number of identical items owned (default 1)
+	UnitWeight  sql.NullFloat64 // weight of a single item; NULL = unspecified
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+// TotalWeight returns the combined weight of all items (quantity × unit
+// weight). The boolean is false when the unit weight is unspecified.
+func (e *Equipment) TotalWeight() (float64, bool) {
+	if !e.UnitWeight.Valid {
+		return 0, false
+	}
+	return float64(e.Quantity) * e.UnitWeight.Float64, true
+}
+
 // ExerciseEquipment represents a required or optional equipment link for an exercise.
 type ExerciseEquipment struct {
 	ID            int64
@@ -48,6 +59,27 @@ type EquipmentCompatibility struct {
 	Missing      []ExerciseEquipment // required equipment the athlete lacks
 	Available    []ExerciseEquipment // required equipment the athlete has
 	Optional     []ExerciseEquipment // optional equipment (for display)
+
+	// MissingOptional lists optional equipment the athlete lacks. It is a
+	// warning only and never blocks readiness.
+	MissingOptional []ExerciseEquipment
+
+	// Load check — populated by CheckProgramCompatibility only.
+	RequiredLoad     float64 // heaviest absolute-weight set prescribed (0 = none)
+	AvailableLoad    float64 // total weight of athlete's linked equipment
+	InsufficientLoad bool    // true if AvailableLoad is known and below RequiredLoad
+}
+
+// Blocked reports whether the exercise cannot be performed as prescribed:
+// required equipment is missing or the owned equipment is too light.
+func (c EquipmentCompatibility) Blocked() bool {
+	return !c.HasRequired || c.InsufficientLoad
+}
+
+// HasWarning reports whether the exercise is performable but the athlete
+// lacks some optional equipment.
+func (c EquipmentCompatibility) HasWarning() bool {
+	return !c.Blocked() && len(c.MissingOptional) > 0
 }
 
 // ProgramCompatibility summarizes equipment readiness for an entire program template.
@@ -58,19 +90,24 @@ type ProgramCompatibility struct {
 	Exercises    []EquipmentCompatibility  // per-exercise breakdown
 	ReadyCount   int                      // exercises with all required equipment
 	TotalCount   int                      // total unique exercises in program
+
+	BlockingCount int // exercises missing required equipment or load
+	WarningCount  int // ready exercises missing only optional equipment
 }
 
-// CreateEquipment inserts a new equipment item.
-func CreateEquipment(db *sql.DB, name, description string) (*Equipment, error) {
+// CreateEquipment inserts a new equipment item. A quantity below 1 is stored
+// as 1; a unitWeight of 0 or less leaves the weight unspecified.
+func CreateEquipment(db *sql.DB, name, description string, quantity int, unitWeight float64) (*Equipment, error) {
 	var descVal sql.NullString
 	if description != "" {
 		descVal = sql.NullString{String: description, Valid: true}
 	}
+	qty, weightVal := equipmentLoadValues(quantity, unitWeight)
 
 	var id int64
 	err := db.QueryRow(
-		`INSERT INTO equipment (name, description) VALUES (?, ?) RETURNING id`,
-		name, descVal,
+		`INSERT INTO equipment (name, description, quantity, unit_weight) VALUES (?, ?, ?, ?) RETURNING id`,
+		name, descVal, qty, weightVal,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -82,13 +119,25 @@ func CreateEquipment(db *sql.DB, name, description string) (*Equipment, error) {
 	return GetEquipmentByID(db, id)
 }
 
+// equipmentLoadValues normalizes quantity and unit weight for storage.
+func equipmentLoadValues(quantity int, unitWeight float64) (int, sql.NullFloat64) {
+	if quantity < 1 {
+		quantity = 1
+	}
+	var weightVal sql.NullFloat64
+	if unitWeight > 0 {
+		weightVal = sql.NullFloat64{Float64: unitWeight, Valid: true}
+	}
+	return quantity, weightVal
+}
+
 // GetEquipmentByID retrieves an equipment item by primary key.
 func GetEquipmentByID(db *sql.DB, id int64) (*Equipment, error) {
 	e := &Equipment{}
 	err := db.QueryRow(
-		`SELECT id, name, description, created_at, updated_at
+		`SELECT id, name, description, quantity, unit_weight, created_at, updated_at
 		 FROM equipment WHERE id = ?`, id,
-	).Scan(&e.ID, &e.Name, &e.Description, &e.CreatedAt, &e.UpdatedAt)
+	).Scan(&e.ID, &e.Name, &e.Description, &e.Quantity, &e.UnitWeight, &e.CreatedAt, &e.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -98,16 +147,18 @@ func GetEquipmentByID(db *sql.DB, id int64) (*Equipment, error) {
 	return e, nil
 }
 
-// UpdateEquipment modifies an existing equipment item.
-func UpdateEquipment(db *sql.DB, id int64, name, description string) (*Equipment, error) {
+// UpdateEquipment modifies an existing equipment item. Quantity and
+// unitWeight follow the same rules as CreateEquipment.
+func UpdateEquipment(db *sql.DB, id int64, name, description string, quantity int, unitWeight float64) (*Equipment, error) {
 	var descVal sql.NullString
 	if description != "" {
 		descVal = sql.NullString{String: description, Valid: true}
 	}
+	qty, weightVal := equipmentLoadValues(quantity, unitWeight)
 
 	result, err := db.Exec(
-		`UPDATE equipment SET name = ?, description = ? WHERE id = ?`,
-		name, descVal, id,
+		`UPDATE equipment SET name = ?, description = ?, quantity = ?, unit_weight = ? WHERE id = ?`,
+		name, descVal, qty, weightVal, id,
 	)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -140,7 +191,7 @@ func DeleteEquipment(db *sql.DB, id int64) error {
 // ListEquipment returns all equipment items ordered by name.
 func ListEquipment(db *sql.DB) ([]*Equipment, error) {
 	rows, err := db.Query(
-		`SELECT id, name, description, created_at, updated_at
+		`SELECT id, name, description, quantity, unit_weight, created_at, updated_at
 		 FROM equipment ORDER BY name COLLATE NOCASE LIMIT 200`,
 	)
 	if err != nil {
@@ -151,7 +202,7 @@ func ListEquipment(db *sql.DB) ([]*Equipment, error) {
 	var items []*Equipment
 	for rows.Next() {
 		e := &Equipment{}
-		if err := rows.Scan(&e.ID, &e.Name, &e.Description, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.Name, &e.Description, &e.Quantity, &e.UnitWeight, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("models: scan equipment: %w", err)
 		}
 		items = append(items, e)
@@ -277,6 +328,46 @@ func AddAthleteEquipment(db *sql.DB, athleteID, equipmentID int64) error {
 	return nil
 }
 
+// AddAthleteEquipmentBatch adds several equipment items to an athlete's
+// inventory in one transaction. Items the athlete already has are ignored.
+// Returns the number of items actually added, or ErrNotFound if any ID is not
+// an equipment item, in which case nothing is added.
+func AddAthleteEquipmentBatch(db *sql.DB, athleteID int64, equipmentIDs []int64) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("models: begin add athlete equipment batch (athlete=%d): %w", athleteID, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO athlete_equipment (athlete_id, equipment_id) VALUES (?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("models: prepare add athlete equipment batch: %w", err)
+	}
+	defer stmt.Close()
+
+	added := 0
+	for _, equipmentID := range equipmentIDs {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM equipment WHERE id = ?)`, equipmentID).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("models: check equipment %d: %w", equipmentID, err)
+		}
+		if !exists {
+			return 0, fmt.Errorf("models: add athlete equipment (equipment=%d): %w", equipmentID, ErrNotFound)
+		}
+		result, err := stmt.Exec(athleteID, equipmentID)
+		if err != nil {
+			return 0, fmt.Errorf("models: add athlete equipment (athlete=%d, equipment=%d): %w", athleteID, equipmentID, err)
+		}
+		n, _ := result.RowsAffected()
+		added += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("models: commit add athlete equipment batch (athlete=%d): %w", athleteID, err)
+	}
+	return added, nil
+}
+
 // RemoveAthleteEquipment removes an equipment item from an athlete's inventory.
 func RemoveAthleteEquipment(db *sql.DB, athleteID, equipmentID int64) error {
 	result, err := db.Exec(
@@ -379,6 +470,9 @@ func CheckExerciseCompatibility(db *sql.DB, athleteID, exerciseID int64) (*Equip
 	for _, eq := range eqList {
 		if eq.Optional {
 			result.Optional = append(result.Optional, eq)
+			if !athleteIDs[eq.EquipmentID] {
+				result.MissingOptional = append(result.MissingOptional, eq)
+			}
 			continue
 		}
 		if athleteIDs[eq.EquipmentID] {
@@ -448,6 +542,9 @@ func CheckAthleteExerciseCompatibility(db *sql.DB, athleteID int64) ([]Equipment
 		for _, eq := range eqList {
 			if eq.Optional {
 				compat.Optional = append(compat.Optional, eq)
+				if !athleteIDs[eq.EquipmentID] {
+					compat.MissingOptional = append(compat.MissingOptional, eq)
+				}
 				continue
 			}
 			if athleteIDs[eq.EquipmentID] {
@@ -467,6 +564,11 @@ func CheckAthleteExerciseCompatibility(db *sql.DB, athleteID int64) ([]Equipment
 // CheckProgramCompatibility checks whether an athlete has the required equipment
 // for every exercise in a program template. It examines all unique exercises
 // referenced by the template's prescribed sets.
+//
+// When an exercise has absolute-weight sets and every piece of linked equipment
+// the athlete owns records a unit weight, it also verifies that the combined
+// weight covers the heaviest prescribed set. Equipment with an unspecified
+// weight is assumed to provide enough load.
 func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*ProgramCompatibility, error) {
 	// Get program template info.
 	tmpl, err := GetProgramTemplateByID(db, templateID)
@@ -476,10 +578,11 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 
 	// Get all unique exercises referenced by this template's prescribed sets.
 	rows, err := db.Query(
-		`SELECT DISTINCT ps.exercise_id, e.name
+		`SELECT ps.exercise_id, e.name, MAX(ps.absolute_weight)
 		 FROM prescribed_sets ps
 		 JOIN exercises e ON e.id = ps.exercise_id
 		 WHERE ps.template_id = ?
+		 GROUP BY ps.exercise_id, e.name
 		 ORDER BY e.name COLLATE NOCASE`,
 		templateID,
 	)
@@ -489,13 +592,14 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 	defer rows.Close()
 
 	type exerciseRef struct {
-		id   int64
-		name string
+		id        int64
+		name      string
+		maxWeight sql.NullFloat64
 	}
 	var exercises []exerciseRef
 	for rows.Next() {
 		var ref exerciseRef
-		if err := rows.Scan(&ref.id, &ref.name); err != nil {
+		if err := rows.Scan(&ref.id, &ref.name, &ref.maxWeight); err != nil {
 			return nil, fmt.Errorf("models: scan exercise ref: %w", err)
 		}
 		exercises = append(exercises, ref)
@@ -504,11 +608,19 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 		return nil, err
 	}
 
-	// Get athlete's equipment set once.
+	// Get athlete's equipment set and the equipment catalog once.
 	athleteIDs, err := AthleteEquipmentIDs(db, athleteID)
 	if err != nil {
 		return nil, err
 	}
+	allEquipment, err := ListEquipment(db)
+	if err != nil {
+		return nil, err
+	}
+	eqByID := make(map[int64]*Equipment, len(allEquipment))
+	for _, eq := range allEquipment {
+		eqByID[eq.ID] = eq
+	}
 
 	result := &ProgramCompatibility{
 		TemplateID:   templateID,
@@ -532,6 +644,9 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 		for _, eq := range eqList {
 			if eq.Optional {
 				compat.Optional = append(compat.Optional, eq)
+				if !athleteIDs[eq.EquipmentID] {
+					compat.MissingOptional = append(compat.MissingOptional, eq)
+				}
 				continue
 			}
 			if athleteIDs[eq.EquipmentID] {
@@ -542,10 +657,20 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 			}
 		}
 
-		if compat.HasRequired {
-			result.ReadyCount++
-		} else {
+		if ex.maxWeight.Valid && ex.maxWeight.Float64 > 0 {
+			compat.RequiredLoad = ex.maxWeight.Float64
+			checkEquipmentLoad(&compat, eqList, athleteIDs, eqByID)
+		}
+
+		switch {
+		case compat.Blocked():
+			result.BlockingCount++
 			result.Ready = false
+		case compat.HasWarning():
+			result.WarningCount++
+			result.ReadyCount++
+		default:
+			result.ReadyCount++
 		}
 
 		result.Exercises = append(result.Exercises, compat)
@@ -554,6 +679,35 @@ func CheckProgramCompatibility(db *sql.DB, athleteID, templateID int64) (*Progra
 	return result, nil
 }
 
+// checkEquipmentLoad totals the weight of the linked equipment the athlete
+// owns and flags the exercise when it falls short of RequiredLoad. The check
+// is skipped when the athlete owns none of the linked equipment or any owned
+// item has an unspecified unit weight.
+func checkEquipmentLoad(compat *EquipmentCompatibility, eqList []ExerciseEquipment, athleteIDs map[int64]bool, eqByID map[int64]*Equipment) {
+	var total float64
+	owned := 0
+	for _, link := range eqList {
+		if !athleteIDs[link.EquipmentID] {
+			continue
+		}
+		eq, ok := eqByID[link.EquipmentID]
+		if !ok {
+			return
+		}
+		weight, known := eq.TotalWeight()
+		if !known {
+			return
+		}
+		total += weight
+		owned++
+	}
+	if owned == 0 {
+		return
+	}
+	compat.AvailableLoad = total
+	compat.InsufficientLoad = total < compat.RequiredLoad
+}
+
 // BatchCheckExerciseCompatibility checks equipment compatibility for ALL exercises
 // against an athlete's equipment inventory in a single query. Returns a map from
 // exercise ID to whether the athlete has all required equipment.
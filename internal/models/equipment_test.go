@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -9,7 +10,7 @@ func TestCreateEquipment(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("basic create", func(t *testing.T) {
-		e, err := CreateEquipment(db, "Barbell", "Standard 45lb barbell")
+		e, err := CreateEquipment(db, "Barbell", "Standard 45lb barbell", 0, 0)
 		if err != nil {
 			t.Fatalf("create equipment: %v", err)
 		}
@@ -22,7 +23,7 @@ func TestCreateEquipment(t *testing.T) {
 	})
 
 	t.Run("no description", func(t *testing.T) {
-		e, err := CreateEquipment(db, "Dumbbells", "")
+		e, err := CreateEquipment(db, "Dumbbells", "", 0, 0)
 		if err != nil {
 			t.Fatalf("create equipment: %v", err)
 		}
@@ -31,15 +32,47 @@ func TestCreateEquipment(t *testing.T) {
 		}
 	})
 
+	t.Run("quantity and unit weight", func(t *testing.T) {
+		e, err := CreateEquipment(db, "50lb Dumbbells", "", 2, 50)
+		if err != nil {
+			t.Fatalf("create equipment: %v", err)
+		}
+		if e.Quantity != 2 {
+			t.Errorf("quantity = %d, want 2", e.Quantity)
+		}
+		if !e.UnitWeight.Valid || e.UnitWeight.Float64 != 50 {
+			t.Errorf("unit weight = %v, want 50", e.UnitWeight)
+		}
+		if total, ok := e.TotalWeight(); !ok || total != 100 {
+			t.Errorf("total weight = %v (%v), want 100", total, ok)
+		}
+	})
+
+	t.Run("defaults leave weight unspecified", func(t *testing.T) {
+		e, err := CreateEquipment(db, "Kettlebell", "", 0, 0)
+		if err != nil {
+			t.Fatalf("create equipment: %v", err)
+		}
+		if e.Quantity != 1 {
+			t.Errorf("quantity = %d, want 1", e.Quantity)
+		}
+		if e.UnitWeight.Valid {
+			t.Errorf("unit weight should be null, got %v", e.UnitWeight.Float64)
+		}
+		if _, ok := e.TotalWeight(); ok {
+			t.Error("total weight should be unknown")
+		}
+	})
+
 	t.Run("duplicate name", func(t *testing.T) {
-		_, err := CreateEquipment(db, "Barbell", "")
+		_, err := CreateEquipment(db, "Barbell", "", 0, 0)
 		if err != ErrDuplicateEquipmentName {
 			t.Errorf("err = %v, want ErrDuplicateEquipmentName", err)
 		}
 	})
 
 	t.Run("case insensitive duplicate", func(t *testing.T) {
-		_, err := CreateEquipment(db, "barbell", "")
+		_, err := CreateEquipment(db, "barbell", "", 0, 0)
 		if err != ErrDuplicateEquipmentName {
 			t.Errorf("err = %v, want ErrDuplicateEquipmentName", err)
 		}
@@ -49,7 +82,7 @@ func TestCreateEquipment(t *testing.T) {
 func TestGetEquipmentByID(t *testing.T) {
 	db := testDB(t)
 
-	e, _ := CreateEquipment(db, "Squat Rack", "Full rack with safeties")
+	e, _ := CreateEquipment(db, "Squat Rack", "Full rack with safeties", 0, 0)
 
 	t.Run("found", func(t *testing.T) {
 		got, err := GetEquipmentByID(db, e.ID)
@@ -72,10 +105,10 @@ func TestGetEquipmentByID(t *testing.T) {
 func TestUpdateEquipment(t *testing.T) {
 	db := testDB(t)
 
-	e, _ := CreateEquipment(db, "Bench", "Flat bench")
+	e, _ := CreateEquipment(db, "Bench", "Flat bench", 0, 0)
 
 	t.Run("basic update", func(t *testing.T) {
-		updated, err := UpdateEquipment(db, e.ID, "Flat Bench", "Adjustable flat bench")
+		updated, err := UpdateEquipment(db, e.ID, "Flat Bench", "Adjustable flat bench", 0, 0)
 		if err != nil {
 			t.Fatalf("update equipment: %v", err)
 		}
@@ -88,15 +121,15 @@ func TestUpdateEquipment(t *testing.T) {
 	})
 
 	t.Run("duplicate name", func(t *testing.T) {
-		CreateEquipment(db, "Pull-up Bar", "")
-		_, err := UpdateEquipment(db, e.ID, "Pull-up Bar", "")
+		CreateEquipment(db, "Pull-up Bar", "", 0, 0)
+		_, err := UpdateEquipment(db, e.ID, "Pull-up Bar", "", 0, 0)
 		if err != ErrDuplicateEquipmentName {
 			t.Errorf("err = %v, want ErrDuplicateEquipmentName", err)
 		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		_, err := UpdateEquipment(db, 99999, "Whatever", "")
+		_, err := UpdateEquipment(db, 99999, "Whatever", "", 0, 0)
 		if err != ErrNotFound {
 			t.Errorf("err = %v, want ErrNotFound", err)
 		}
@@ -107,7 +140,7 @@ func TestDeleteEquipment(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("delete existing", func(t *testing.T) {
-		e, _ := CreateEquipment(db, "Kettlebell", "")
+		e, _ := CreateEquipment(db, "Kettlebell", "", 0, 0)
 		if err := DeleteEquipment(db, e.ID); err != nil {
 			t.Fatalf("delete equipment: %v", err)
 		}
@@ -128,9 +161,9 @@ func TestDeleteEquipment(t *testing.T) {
 func TestListEquipment(t *testing.T) {
 	db := testDB(t)
 
-	CreateEquipment(db, "Barbell", "")
-	CreateEquipment(db, "Dumbbells", "")
-	CreateEquipment(db, "Squat Rack", "")
+	CreateEquipment(db, "Barbell", "", 0, 0)
+	CreateEquipment(db, "Dumbbells", "", 0, 0)
+	CreateEquipment(db, "Squat Rack", "", 0, 0)
 
 	items, err := ListEquipment(db)
 	if err != nil {
@@ -148,9 +181,9 @@ func TestListEquipment(t *testing.T) {
 func TestExerciseEquipment(t *testing.T) {
 	db := testDB(t)
 
-	exercise, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	barbell, _ := CreateEquipment(db, "Barbell", "")
-	bench, _ := CreateEquipment(db, "Flat Bench", "")
+	exercise, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	bench, _ := CreateEquipment(db, "Flat Bench", "", 0, 0)
 
 	t.Run("add required", func(t *testing.T) {
 		if err := AddExerciseEquipment(db, exercise.ID, barbell.ID, false); err != nil {
@@ -216,8 +249,8 @@ func TestAthleteEquipment(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	barbell, _ := CreateEquipment(db, "Barbell", "")
-	rack, _ := CreateEquipment(db, "Squat Rack", "")
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	rack, _ := CreateEquipment(db, "Squat Rack", "", 0, 0)
 
 	t.Run("add equipment", func(t *testing.T) {
 		if err := AddAthleteEquipment(db, athlete.ID, barbell.ID); err != nil {
@@ -276,14 +309,54 @@ func TestAthleteEquipment(t *testing.T) {
 	})
 }
 
+func TestAddAthleteEquipmentBatch(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	rack, _ := CreateEquipment(db, "Squat Rack", "", 0, 0)
+	bench, _ := CreateEquipment(db, "Flat Bench", "", 0, 0)
+	AddAthleteEquipment(db, athlete.ID, barbell.ID)
+
+	added, err := AddAthleteEquipmentBatch(db, athlete.ID, []int64{barbell.ID, rack.ID, bench.ID, rack.ID})
+	if err != nil {
+		t.Fatalf("add batch: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("added = %d, want 2 (barbell owned, rack duplicated)", added)
+	}
+	items, _ := ListAthleteEquipment(db, athlete.ID)
+	if len(items) != 3 {
+		t.Errorf("inventory = %d, want 3", len(items))
+	}
+
+	t.Run("empty list", func(t *testing.T) {
+		added, err := AddAthleteEquipmentBatch(db, athlete.ID, nil)
+		if err != nil || added != 0 {
+			t.Errorf("added = %d, err = %v; want 0, nil", added, err)
+		}
+	})
+
+	t.Run("unknown equipment rolls back", func(t *testing.T) {
+		other, _ := CreateAthlete(db, "Other", "", "", "", "", "", "", sql.NullInt64{}, true)
+		if _, err := AddAthleteEquipmentBatch(db, other.ID, []int64{barbell.ID, 99999}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+		items, _ := ListAthleteEquipment(db, other.ID)
+		if len(items) != 0 {
+			t.Errorf("inventory = %d, want 0 after rollback", len(items))
+		}
+	})
+}
+
 func TestCheckExerciseCompatibility(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	barbell, _ := CreateEquipment(db, "Barbell", "")
-	bench, _ := CreateEquipment(db, "Flat Bench", "")
-	bands, _ := CreateEquipment(db, "Resistance Bands", "")
+	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	bench, _ := CreateEquipment(db, "Flat Bench", "", 0, 0)
+	bands, _ := CreateEquipment(db, "Resistance Bands", "", 0, 0)
 
 	// Set up exercise requirements: barbell required, bench required, bands optional.
 	AddExerciseEquipment(db, benchPress.ID, barbell.ID, false)
@@ -338,7 +411,7 @@ func TestCheckExerciseCompatibility(t *testing.T) {
 	})
 
 	t.Run("exercise with no requirements", func(t *testing.T) {
-		pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", 0)
+		pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", "", 0)
 		compat, err := CheckExerciseCompatibility(db, athlete.ID, pushUps.ID)
 		if err != nil {
 			t.Fatalf("check compatibility: %v", err)
@@ -353,11 +426,11 @@ func TestCheckAthleteExerciseCompatibility(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", 0)
+	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", "", 0)
 
-	barbell, _ := CreateEquipment(db, "Barbell", "")
-	bench, _ := CreateEquipment(db, "Flat Bench", "")
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	bench, _ := CreateEquipment(db, "Flat Bench", "", 0, 0)
 
 	// Assign both exercises.
 	AssignExercise(db, athlete.ID, benchPress.ID, 0)
@@ -424,7 +497,7 @@ func TestEquipmentCascadeOnAthleteDelete(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	eq, _ := CreateEquipment(db, "Barbell", "")
+	eq, _ := CreateEquipment(db, "Barbell", "", 0, 0)
 	AddAthleteEquipment(db, athlete.ID, eq.ID)
 
 	// Delete athlete — cascade should remove athlete_equipment.
@@ -443,26 +516,26 @@ func TestCheckProgramCompatibility(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	barbell, _ := CreateEquipment(db, "Barbell", "")
-	rack, _ := CreateEquipment(db, "Squat Rack", "")
-	bench, _ := CreateEquipment(db, "Flat Bench", "")
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	rack, _ := CreateEquipment(db, "Squat Rack", "", 0, 0)
+	bench, _ := CreateEquipment(db, "Flat Bench", "", 0, 0)
 
-	squat, _ := CreateExercise(db, "Squat", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
 	AddExerciseEquipment(db, squat.ID, barbell.ID, false)
 	AddExerciseEquipment(db, squat.ID, rack.ID, false)
 
-	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
+	benchPress, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 	AddExerciseEquipment(db, benchPress.ID, barbell.ID, false)
 	AddExerciseEquipment(db, benchPress.ID, bench.ID, false)
 
-	pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", 0)
+	pushUps, _ := CreateExercise(db, "Push-ups", "", "", "", "", 0)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 3, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 3, false, "", 0, "")
 	reps := 5
 	pct := 80.0
-	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, &pct, nil, 0, "reps", "")
-	CreatePrescribedSet(db, tmpl.ID, benchPress.ID, 1, 2, 1, &reps, &pct, nil, 0, "reps", "")
-	CreatePrescribedSet(db, tmpl.ID, pushUps.ID, 1, 3, 1, &reps, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, benchPress.ID, 1, 2, 1, &reps, nil, &pct, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, pushUps.ID, 1, 3, 1, &reps, nil, nil, nil, nil, nil, 0, "reps", "")
 
 	t.Run("no equipment — partial readiness", func(t *testing.T) {
 		result, err := CheckProgramCompatibility(db, athlete.ID, tmpl.ID)
@@ -514,7 +587,7 @@ func TestCheckProgramCompatibility(t *testing.T) {
 	})
 
 	t.Run("empty program — ready by default", func(t *testing.T) {
-		emptyTmpl, _ := CreateProgramTemplate(db, nil, "Empty", "", 1, 1, false, "")
+		emptyTmpl, _ := CreateProgramTemplate(db, nil, "Empty", "", 1, 1, false, "", 0, "")
 		result, err := CheckProgramCompatibility(db, athlete.ID, emptyTmpl.ID)
 		if err != nil {
 			t.Fatalf("check: %v", err)
@@ -528,11 +601,121 @@ func TestCheckProgramCompatibility(t *testing.T) {
 	})
 }
 
+func TestCheckProgramCompatibility_BlockingAndWarnings(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	barbell, _ := CreateEquipment(db, "Barbell", "", 0, 0)
+	rack, _ := CreateEquipment(db, "Squat Rack", "", 0, 0)
+	belt, _ := CreateEquipment(db, "Belt", "", 0, 0)
+	AddAthleteEquipment(db, athlete.ID, barbell.ID)
+
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	AddExerciseEquipment(db, squat.ID, barbell.ID, false)
+	AddExerciseEquipment(db, squat.ID, rack.ID, false)
+	deadlift, _ := CreateExercise(db, "Deadlift", "", "", "", "", 0)
+	AddExerciseEquipment(db, deadlift.ID, barbell.ID, false)
+	AddExerciseEquipment(db, deadlift.ID, belt.ID, true)
+	row, _ := CreateExercise(db, "Row", "", "", "", "", 0)
+	AddExerciseEquipment(db, row.ID, barbell.ID, false)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Program", "", 1, 1, false, "", 0, "")
+	reps := 5
+	for i, id := range []int64{squat.ID, deadlift.ID, row.ID} {
+		CreatePrescribedSet(db, tmpl.ID, id, 1, 1, i+1, &reps, nil, nil, nil, nil, nil, 0, "reps", "")
+	}
+
+	result, err := CheckProgramCompatibility(db, athlete.ID, tmpl.ID)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if result.Ready {
+		t.Error("should not be ready — squat missing rack")
+	}
+	if result.BlockingCount != 1 || result.WarningCount != 1 || result.ReadyCount != 2 {
+		t.Errorf("blocking/warning/ready = %d/%d/%d, want 1/1/2",
+			result.BlockingCount, result.WarningCount, result.ReadyCount)
+	}
+
+	byName := make(map[string]EquipmentCompatibility)
+	for _, ex := range result.Exercises {
+		byName[ex.ExerciseName] = ex
+	}
+	if sq := byName["Squat"]; !sq.Blocked() || len(sq.Missing) != 1 || sq.Missing[0].EquipmentName != "Squat Rack" {
+		t.Errorf("squat = blocked %v, missing %v; want blocked on Squat Rack", sq.Blocked(), sq.Missing)
+	}
+	if dl := byName["Deadlift"]; dl.Blocked() || !dl.HasWarning() || len(dl.MissingOptional) != 1 {
+		t.Errorf("deadlift = blocked %v, warning %v; want warning on Belt", dl.Blocked(), dl.HasWarning())
+	}
+	if r := byName["Row"]; r.Blocked() || r.HasWarning() {
+		t.Errorf("row = blocked %v, warning %v; want ready", r.Blocked(), r.HasWarning())
+	}
+}
+
+func TestCheckProgramCompatibility_Load(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	dumbbells, _ := CreateEquipment(db, "Dumbbells", "", 2, 25)
+	bands, _ := CreateEquipment(db, "Bands", "", 0, 0)
+	AddAthleteEquipment(db, athlete.ID, dumbbells.ID)
+	AddAthleteEquipment(db, athlete.ID, bands.ID)
+
+	press, _ := CreateExercise(db, "DB Press", "", "", "", "", 0)
+	AddExerciseEquipment(db, press.ID, dumbbells.ID, false)
+	curl, _ := CreateExercise(db, "Band Curl", "", "", "", "", 0)
+	AddExerciseEquipment(db, curl.ID, bands.ID, false)
+
+	reps := 8
+	tmpl, _ := CreateProgramTemplate(db, nil, "Load Program", "", 1, 1, false, "", 0, "")
+
+	tests := []struct {
+		name         string
+		exerciseID   int64
+		weight       float64
+		wantReady    bool
+		wantShort    bool
+		wantRequired float64
+	}{
+		{"within owned load", press.ID, 40, true, false, 40},
+		{"exceeds owned load", press.ID, 60, false, true, 60},
+		{"unspecified weight assumed available", curl.ID, 200, true, false, 200},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.Exec(`DELETE FROM prescribed_sets WHERE template_id = ?`, tmpl.ID)
+			w := tt.weight
+			if _, err := CreatePrescribedSet(db, tmpl.ID, tt.exerciseID, 1, 1, i+1, &reps, nil, nil, &w, nil, nil, 0, "reps", ""); err != nil {
+				t.Fatalf("create prescribed set: %v", err)
+			}
+
+			result, err := CheckProgramCompatibility(db, athlete.ID, tmpl.ID)
+			if err != nil {
+				t.Fatalf("check: %v", err)
+			}
+			if result.Ready != tt.wantReady {
+				t.Errorf("ready = %v, want %v", result.Ready, tt.wantReady)
+			}
+			if len(result.Exercises) != 1 {
+				t.Fatalf("exercises = %d, want 1", len(result.Exercises))
+			}
+			ex := result.Exercises[0]
+			if ex.InsufficientLoad != tt.wantShort {
+				t.Errorf("insufficient load = %v, want %v", ex.InsufficientLoad, tt.wantShort)
+			}
+			if ex.RequiredLoad != tt.wantRequired {
+				t.Errorf("required load = %v, want %v", ex.RequiredLoad, tt.wantRequired)
+			}
+		})
+	}
+}
+
 func TestEquipmentCascadeOnExerciseDelete(t *testing.T) {
 	db := testDB(t)
 
-	exercise, _ := CreateExercise(db, "Test Exercise", "", "", "", 0)
-	eq, _ := CreateEquipment(db, "Barbell", "")
+	exercise, _ := CreateExercise(db, "Test Exercise", "", "", "", "", 0)
+	eq, _ := CreateEquipment(db, "Barbell", "", 0, 0)
 	AddExerciseEquipment(db, exercise.ID, eq.ID, false)
 
 	// Delete exercise — cascade should remove exercise_equipment.
@@ -551,8 +734,8 @@ func TestEquipmentCascadeOnEquipmentDelete(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	exercise, _ := CreateExercise(db, "Test Exercise", "", "", "", 0)
-	eq, _ := CreateEquipment(db, "Barbell", "")
+	exercise, _ := CreateExercise(db, "Test Exercise", "", "", "", "", 0)
+	eq, _ := CreateEquipment(db, "Barbell", "", 0, 0)
 
 	AddAthleteEquipment(db, athlete.ID, eq.ID)
 	AddExerciseEquipment(db, exercise.ID, eq.ID, false)
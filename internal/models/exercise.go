@@ -8,25 +8,51 @@ import (
 )
 
 // ErrExerciseInUse is returned when attempting to delete an exercise that has
-// been logged in workout sets (ON DELETE RESTRICT).
-var ErrExerciseInUse = errors.New("exercise is referenced by workout sets")
+// history: logged sets, training maxes, assignments, or program references.
+var ErrExerciseInUse = errors.New("exercise is referenced by workout history or programs")
 
-// ErrDuplicateExerciseName is returned when an exercise name is already taken.
+// ErrDuplicateExerciseName is returned when an exercise name is already taken
+// by another exercise or by an alias.
 var ErrDuplicateExerciseName = errors.New("duplicate exercise name")
 
 // DefaultRestSeconds is the global fallback rest time (in seconds) when an
 // exercise does not specify its own.
 const DefaultRestSeconds = 90
 
+// MuscleGroups lists the valid exercise muscle groups in display order.
+var MuscleGroups = []string{"push", "pull", "legs", "core", "full_body", "conditioning"}
+
+// IsValidMuscleGroup reports whether g is one of MuscleGroups.
+func IsValidMuscleGroup(g string) bool {
+	for _, mg := range MuscleGroups {
+		if g == mg {
+			return true
+		}
+	}
+	return false
+}
+
+// ExerciseFilter narrows ListExercises. Zero values match everything except
+// archived exercises; a value of "none" matches exercises with that field
+// unset.
+type ExerciseFilter struct {
+	Tier            string
+	MuscleGroup     string
+	IncludeArchived bool
+}
+
 // Exercise represents a movement tracked in the system.
 type Exercise struct {
 	ID          int64
 	Name        string
 	Tier        sql.NullString
+	MuscleGroup sql.NullString
 	FormNotes   sql.NullString
 	DemoURL     sql.NullString
 	RestSeconds sql.NullInt64
 	Featured    bool
+	Unilateral  bool // single-leg/arm; sets default to each_side
+	Archived    bool // hidden from pickers; history is kept
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -39,8 +65,21 @@ func (e *Exercise) EffectiveRestSeconds() int {
 	return DefaultRestSeconds
 }
 
-// CreateExercise inserts a new exercise.
-func CreateExercise(db *sql.DB, name, tier string, formNotes, demoURL string, restSeconds int, featured ...bool) (*Exercise, error) {
+// DefaultRepType returns the rep type new sets of this exercise use when
+// none is given: "each_side" for unilateral exercises, otherwise "reps".
+func (e *Exercise) DefaultRepType() string {
+	if e.Unilateral {
+		return "each_side"
+	}
+	return "reps"
+}
+
+// CreateExercise inserts a new exercise. Returns ErrInvalidDemoURL if
+// demoURL is set but not an http(s) link.
+func CreateExercise(db *sql.DB, name, tier, muscleGroup string, formNotes, demoURL string, restSeconds int, featured ...bool) (*Exercise, error) {
+	if err := ValidateDemoURL(demoURL); err != nil {
+		return nil, err
+	}
 	feat := false
 	if len(featured) > 0 {
 		feat = featured[0]
@@ -49,6 +88,10 @@ func CreateExercise(db *sql.DB, name, tier string, formNotes, demoURL string, re
 	if tier != "" {
 		tierVal = sql.NullString{String: tier, Valid: true}
 	}
+	var groupVal sql.NullString
+	if muscleGroup != "" {
+		groupVal = sql.NullString{String: muscleGroup, Valid: true}
+	}
 	var notesVal sql.NullString
 	if formNotes != "" {
 		notesVal = sql.NullString{String: formNotes, Valid: true}
@@ -61,11 +104,14 @@ func CreateExercise(db *sql.DB, name, tier string, formNotes, demoURL string, re
 	if restSeconds > 0 {
 		restVal = sql.NullInt64{Int64: int64(restSeconds), Valid: true}
 	}
+	if err := checkNameNotAlias(db, name, 0); err != nil {
+		return nil, err
+	}
 
 	var id int64
 	err := db.QueryRow(
-		`INSERT INTO exercises (name, tier, form_notes, demo_url, rest_seconds, featured) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
-		name, tierVal, notesVal, demoVal, restVal, feat,
+		`INSERT INTO exercises (name, tier, muscle_group, form_notes, demo_url, rest_seconds, featured) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		name, tierVal, groupVal, notesVal, demoVal, restVal, feat,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -81,9 +127,9 @@ func CreateExercise(db *sql.DB, name, tier string, formNotes, demoURL string, re
 func GetExerciseByID(db *sql.DB, id int64) (*Exercise, error) {
 	e := &Exercise{}
 	err := db.QueryRow(
-		`SELECT id, name, tier, form_notes, demo_url, rest_seconds, featured, created_at, updated_at
+		`SELECT id, name, tier, muscle_group, form_notes, demo_url, rest_seconds, featured, unilateral, archived, created_at, updated_at
 		 FROM exercises WHERE id = ?`, id,
-	).Scan(&e.ID, &e.Name, &e.Tier, &e.FormNotes, &e.DemoURL, &e.RestSeconds, &e.Featured, &e.CreatedAt, &e.UpdatedAt)
+	).Scan(&e.ID, &e.Name, &e.Tier, &e.MuscleGroup, &e.FormNotes, &e.DemoURL, &e.RestSeconds, &e.Featured, &e.Unilateral, &e.Archived, &e.CreatedAt, &e.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	}
@@ -94,7 +140,10 @@ func GetExerciseByID(db *sql.DB, id int64) (*Exercise, error) {
 }
 
 // UpdateExercise modifies an existing exercise's fields.
-func UpdateExercise(db *sql.DB, id int64, name, tier string, formNotes, demoURL string, restSeconds int, featured ...bool) (*Exercise, error) {
+func UpdateExercise(db *sql.DB, id int64, name, tier, muscleGroup string, formNotes, demoURL string, restSeconds int, featured ...bool) (*Exercise, error) {
+	if err := ValidateDemoURL(demoURL); err != nil {
+		return nil, err
+	}
 	feat := false
 	if len(featured) > 0 {
 		feat = featured[0]
@@ -103,6 +152,10 @@ func UpdateExercise(db *sql.DB, id int64, name, tier string, formNotes, demoURL
 	if tier != "" {
 		tierVal = sql.NullString{String: tier, Valid: true}
 	}
+	var groupVal sql.NullString
+	if muscleGroup != "" {
+		groupVal = sql.NullString{String: muscleGroup, Valid: true}
+	}
 	var notesVal sql.NullString
 	if formNotes != "" {
 		notesVal = sql.NullString{String: formNotes, Valid: true}
@@ -115,10 +168,13 @@ func UpdateExercise(db *sql.DB, id int64, name, tier string, formNotes, demoURL
 	if restSeconds > 0 {
 		restVal = sql.NullInt64{Int64: int64(restSeconds), Valid: true}
 	}
+	if err := checkNameNotAlias(db, name, id); err != nil {
+		return nil, err
+	}
 
 	result, err := db.Exec(
-		`UPDATE exercises SET name = ?, tier = ?, form_notes = ?, demo_url = ?, rest_seconds = ?, featured = ? WHERE id = ?`,
-		name, tierVal, notesVal, demoVal, restVal, feat, id,
+		`UPDATE exercises SET name = ?, tier = ?, muscle_group = ?, form_notes = ?, demo_url = ?, rest_seconds = ?, featured = ? WHERE id = ?`,
+		name, tierVal, groupVal, notesVal, demoVal, restVal, feat, id,
 	)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -135,9 +191,54 @@ func UpdateExercise(db *sql.DB, id int64, name, tier string, formNotes, demoURL
 	return GetExerciseByID(db, id)
 }
 
+// SetExerciseUnilateral marks whether an exercise is worked one side at a
+// time.
+func SetExerciseUnilateral(db *sql.DB, id int64, unilateral bool) error {
+	result, err := db.Exec(`UPDATE exercises SET unilateral = ? WHERE id = ?`, unilateral, id)
+	if err != nil {
+		return fmt.Errorf("models: set unilateral for exercise %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetExerciseArchived archives or restores an exercise. Archived exercises
+// are hidden from ListExercises by default but keep all their history.
+func SetExerciseArchived(db *sql.DB, id int64, archived bool) error {
+	result, err := db.Exec(`UPDATE exercises SET archived = ? WHERE id = ?`, archived, id)
+	if err != nil {
+		return fmt.Errorf("models: set archived for exercise %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // DeleteExercise removes an exercise by ID. Returns ErrExerciseInUse if the
-// exercise has been logged in any workout sets (RESTRICT).
+// exercise has any history that a delete would restrict or cascade away:
+// logged sets, training maxes, assignments, prescribed sets, or accessory
+// plans. Callers archive such exercises instead.
 func DeleteExercise(db *sql.DB, id int64) error {
+	var refs int
+	err := db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM workout_sets WHERE exercise_id = ?)
+		     + (SELECT COUNT(*) FROM training_maxes WHERE exercise_id = ?)
+		     + (SELECT COUNT(*) FROM athlete_exercises WHERE exercise_id = ?)
+		     + (SELECT COUNT(*) FROM prescribed_sets WHERE exercise_id = ?)
+		     + (SELECT COUNT(*) FROM accessory_plans WHERE exercise_id = ?)`,
+		id, id, id, id, id).Scan(&refs)
+	if err != nil {
+		return fmt.Errorf("models: count references to exercise %d: %w", id, err)
+	}
+	if refs > 0 {
+		return ErrExerciseInUse
+	}
+
 	result, err := db.Exec(`DELETE FROM exercises WHERE id = ?`, id)
 	if err != nil {
 		if errContains(err, "FOREIGN KEY constraint failed") {
@@ -152,23 +253,33 @@ func DeleteExercise(db *sql.DB, id int64) error {
 	return nil
 }
 
-// ListExercises returns all exercises, optionally filtered by tier.
-// Pass empty string for tier to list all.
-func ListExercises(db *sql.DB, tierFilter string) ([]*Exercise, error) {
-	var query string
+// ListExercises returns all exercises matching filter, ordered by name.
+// Pass a zero ExerciseFilter to list all active exercises.
+func ListExercises(db *sql.DB, filter ExerciseFilter) ([]*Exercise, error) {
+	query := `SELECT id, name, tier, muscle_group, form_notes, demo_url, rest_seconds, featured, unilateral, archived, created_at, updated_at
+	          FROM exercises WHERE 1=1`
 	var args []any
 
-	if tierFilter == "none" {
-		query = `SELECT id, name, tier, form_notes, demo_url, rest_seconds, featured, created_at, updated_at
-		         FROM exercises WHERE tier IS NULL ORDER BY name COLLATE NOCASE LIMIT 200`
-	} else if tierFilter != "" {
-		query = `SELECT id, name, tier, form_notes, demo_url, rest_seconds, featured, created_at, updated_at
-		         FROM exercises WHERE tier = ? ORDER BY name COLLATE NOCASE LIMIT 200`
-		args = append(args, tierFilter)
-	} else {
-		query = `SELECT id, name, tier, form_notes, demo_url, rest_seconds, featured, created_at, updated_at
-		         FROM exercises ORDER BY name COLLATE NOCASE LIMIT 200`
+	switch filter.Tier {
+	case "":
+	case "none":
+		query += ` AND tier IS NULL`
+	default:
+		query += ` AND tier = ?`
+		args = append(args, filter.Tier)
+	}
+	switch filter.MuscleGroup {
+	case "":
+	case "none":
+		query += ` AND muscle_group IS NULL`
+	default:
+		query += ` AND muscle_group = ?`
+		args = append(args, filter.MuscleGroup)
 	}
+	if !filter.IncludeArchived {
+		query += ` AND archived = 0`
+	}
+	query += ` ORDER BY name COLLATE NOCASE LIMIT 200`
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -179,7 +290,7 @@ func ListExercises(db *sql.DB, tierFilter string) ([]*Exercise, error) {
 	var exercises []*Exercise
 	for rows.Next() {
 		e := &Exercise{}
-		if err := rows.Scan(&e.ID, &e.Name, &e.Tier, &e.FormNotes, &e.DemoURL, &e.RestSeconds, &e.Featured, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.Name, &e.Tier, &e.MuscleGroup, &e.FormNotes, &e.DemoURL, &e.RestSeconds, &e.Featured, &e.Unilateral, &e.Archived, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("models: scan exercise: %w", err)
 		}
 		exercises = append(exercises, e)
@@ -190,6 +301,28 @@ func ListExercises(db *sql.DB, tierFilter string) ([]*Exercise, error) {
 	return exercises, nil
 }
 
+// ListArchivedExercises returns archived exercises ordered by name.
+func ListArchivedExercises(db *sql.DB) ([]*Exercise, error) {
+	rows, err := db.Query(`
+		SELECT id, name, tier, muscle_group, form_notes, demo_url, rest_seconds, featured, unilateral, archived, created_at, updated_at
+		FROM exercises WHERE archived = 1
+		ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list archived exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var exercises []*Exercise
+	for rows.Next() {
+		e := &Exercise{}
+		if err := rows.Scan(&e.ID, &e.Name, &e.Tier, &e.MuscleGroup, &e.FormNotes, &e.DemoURL, &e.RestSeconds, &e.Featured, &e.Unilateral, &e.Archived, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan archived exercise: %w", err)
+		}
+		exercises = append(exercises, e)
+	}
+	return exercises, rows.Err()
+}
+
 // FeaturedLift holds summary data for one featured exercise for an athlete.
 type FeaturedLift struct {
 	ExerciseID   int64
@@ -285,13 +418,8 @@ func ListFeaturedLifts(db *sql.DB, athleteID int64) ([]*FeaturedLift, error) {
 			}
 		}
 
-		// Estimated 1RM via Epley formula: weight × (1 + reps/30).
-		if lift.BestWeight.Valid && lift.BestWeight.Float64 > 0 && lift.BestReps > 0 {
-			if lift.BestReps == 1 {
-				lift.Estimated1RM = lift.BestWeight.Float64
-			} else {
-				lift.Estimated1RM = lift.BestWeight.Float64 * (1 + float64(lift.BestReps)/30.0)
-			}
+		if lift.BestWeight.Valid {
+			lift.Estimated1RM = EstimateOneRepMax(lift.BestReps, lift.BestWeight.Float64)
 		}
 
 		lifts = append(lifts, lift)
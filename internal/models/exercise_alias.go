@@ -0,0 +1,156 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateAlias is returned when an alias is already used by another
+// alias or matches an existing exercise name.
+var ErrDuplicateAlias = errors.New("alias already in use")
+
+// ExerciseAlias is an alternate name for an exercise, such as the name a
+// third-party app uses for it.
+type ExerciseAlias struct {
+	ID         int64
+	ExerciseID int64
+	Alias      string
+	CreatedAt  time.Time
+}
+
+// AddExerciseAlias adds an alternate name for an exercise. Aliases are unique
+// case-insensitively and may not shadow an existing exercise name; either
+// case returns ErrDuplicateAlias.
+func AddExerciseAlias(db *sql.DB, exerciseID int64, alias string) (*ExerciseAlias, error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return nil, fmt.Errorf("models: alias is required: %w", ErrInvalidInput)
+	}
+
+	var clash int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM exercises WHERE name = ? COLLATE NOCASE`, alias).Scan(&clash); err != nil {
+		return nil, fmt.Errorf("models: check alias %q against exercise names: %w", alias, err)
+	}
+	if clash > 0 {
+		return nil, ErrDuplicateAlias
+	}
+
+	var id int64
+	err := db.QueryRow(
+		`INSERT INTO exercise_aliases (exercise_id, alias) VALUES (?, ?) RETURNING id`,
+		exerciseID, alias,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateAlias
+		}
+		return nil, fmt.Errorf("models: add alias %q to exercise %d: %w", alias, exerciseID, err)
+	}
+
+	a := &ExerciseAlias{}
+	err = db.QueryRow(
+		`SELECT id, exercise_id, alias, created_at FROM exercise_aliases WHERE id = ?`, id,
+	).Scan(&a.ID, &a.ExerciseID, &a.Alias, &a.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("models: get alias %d: %w", id, err)
+	}
+	return a, nil
+}
+
+// checkNameNotAlias returns ErrDuplicateExerciseName if name matches an alias
+// of any exercise other than exerciseID, so names and aliases never shadow
+// each other.
+func checkNameNotAlias(db *sql.DB, name string, exerciseID int64) error {
+	var clash int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM exercise_aliases WHERE alias = ? COLLATE NOCASE AND exercise_id != ?`,
+		strings.TrimSpace(name), exerciseID,
+	).Scan(&clash)
+	if err != nil {
+		return fmt.Errorf("models: check exercise name %q against aliases: %w", name, err)
+	}
+	if clash > 0 {
+		return ErrDuplicateExerciseName
+	}
+	return nil
+}
+
+// DeleteExerciseAlias removes an alias from an exercise.
+func DeleteExerciseAlias(db *sql.DB, exerciseID, aliasID int64) error {
+	result, err := db.Exec(`DELETE FROM exercise_aliases WHERE id = ? AND exercise_id = ?`, aliasID, exerciseID)
+	if err != nil {
+		return fmt.Errorf("models: delete alias %d: %w", aliasID, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListExerciseAliases returns the aliases for an exercise, ordered by name.
+func ListExerciseAliases(db *sql.DB, exerciseID int64) ([]*ExerciseAlias, error) {
+	rows, err := db.Query(`
+		SELECT id, exercise_id, alias, created_at
+		FROM exercise_aliases
+		WHERE exercise_id = ?
+		ORDER BY alias COLLATE NOCASE`, exerciseID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list aliases for exercise %d: %w", exerciseID, err)
+	}
+	defer rows.Close()
+
+	var aliases []*ExerciseAlias
+	for rows.Next() {
+		a := &ExerciseAlias{}
+		if err := rows.Scan(&a.ID, &a.ExerciseID, &a.Alias, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// ListAliasesByExercise returns every alias keyed by exercise ID. Used to
+// seed import mappings in one query.
+func ListAliasesByExercise(db *sql.DB) (map[int64][]string, error) {
+	rows, err := db.Query(`SELECT exercise_id, alias FROM exercise_aliases ORDER BY alias COLLATE NOCASE`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list all aliases: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64][]string)
+	for rows.Next() {
+		var exerciseID int64
+		var alias string
+		if err := rows.Scan(&exerciseID, &alias); err != nil {
+			return nil, fmt.Errorf("models: scan alias: %w", err)
+		}
+		result[exerciseID] = append(result[exerciseID], alias)
+	}
+	return result, rows.Err()
+}
+
+// ResolveExerciseByNameOrAlias finds the exercise whose name or one of whose
+// aliases matches name case-insensitively. Returns ErrNotFound if neither
+// matches.
+func ResolveExerciseByNameOrAlias(db *sql.DB, name string) (*Exercise, error) {
+	name = strings.TrimSpace(name)
+	var id int64
+	err := db.QueryRow(`
+		SELECT id FROM exercises WHERE name = ? COLLATE NOCASE
+		UNION ALL
+		SELECT exercise_id FROM exercise_aliases WHERE alias = ? COLLATE NOCASE
+		LIMIT 1`, name, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: resolve exercise %q: %w", name, err)
+	}
+	return GetExerciseByID(db, id)
+}
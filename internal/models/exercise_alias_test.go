@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExerciseAliases(t *testing.T) {
+	db := testDB(t)
+
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+
+	alias, err := AddExerciseAlias(db, squat.ID, "  Barbell Squat ")
+	if err != nil {
+		t.Fatalf("add alias: %v", err)
+	}
+	if alias.Alias != "Barbell Squat" {
+		t.Errorf("alias = %q, want trimmed", alias.Alias)
+	}
+
+	t.Run("duplicate alias", func(t *testing.T) {
+		if _, err := AddExerciseAlias(db, bench.ID, "barbell squat"); !errors.Is(err, ErrDuplicateAlias) {
+			t.Errorf("err = %v, want ErrDuplicateAlias", err)
+		}
+	})
+
+	t.Run("alias shadowing exercise name", func(t *testing.T) {
+		if _, err := AddExerciseAlias(db, squat.ID, "bench press"); !errors.Is(err, ErrDuplicateAlias) {
+			t.Errorf("err = %v, want ErrDuplicateAlias", err)
+		}
+	})
+
+	t.Run("exercise name shadowing alias", func(t *testing.T) {
+		if _, err := CreateExercise(db, "barbell squat", "", "", "", "", 0); !errors.Is(err, ErrDuplicateExerciseName) {
+			t.Errorf("create: err = %v, want ErrDuplicateExerciseName", err)
+		}
+		if _, err := UpdateExercise(db, bench.ID, "Barbell Squat", "", "", "", "", 0); !errors.Is(err, ErrDuplicateExerciseName) {
+			t.Errorf("rename: err = %v, want ErrDuplicateExerciseName", err)
+		}
+		if _, err := UpdateExercise(db, squat.ID, "Back Squat", "", "", "", "", 0); err != nil {
+			t.Errorf("update with own alias present: %v", err)
+		}
+	})
+
+	t.Run("blank alias", func(t *testing.T) {
+		if _, err := AddExerciseAlias(db, squat.ID, "  "); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		AddExerciseAlias(db, squat.ID, "Squat (Barbell)")
+		aliases, err := ListExerciseAliases(db, squat.ID)
+		if err != nil {
+			t.Fatalf("list aliases: %v", err)
+		}
+		if len(aliases) != 2 {
+			t.Fatalf("count = %d, want 2", len(aliases))
+		}
+		all, err := ListAliasesByExercise(db)
+		if err != nil {
+			t.Fatalf("list all aliases: %v", err)
+		}
+		if len(all[squat.ID]) != 2 || len(all[bench.ID]) != 0 {
+			t.Errorf("aliases by exercise = %v", all)
+		}
+	})
+
+	t.Run("resolve by name or alias", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			wantID int64
+		}{
+			{"back squat", squat.ID},
+			{"BARBELL SQUAT", squat.ID},
+			{"Bench Press", bench.ID},
+		}
+		for _, tt := range tests {
+			ex, err := ResolveExerciseByNameOrAlias(db, tt.input)
+			if err != nil {
+				t.Fatalf("resolve %q: %v", tt.input, err)
+			}
+			if ex.ID != tt.wantID {
+				t.Errorf("resolve %q = %d, want %d", tt.input, ex.ID, tt.wantID)
+			}
+		}
+		if _, err := ResolveExerciseByNameOrAlias(db, "Deadlift"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := DeleteExerciseAlias(db, bench.ID, alias.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("delete via wrong exercise: err = %v, want ErrNotFound", err)
+		}
+		if err := DeleteExerciseAlias(db, squat.ID, alias.ID); err != nil {
+			t.Fatalf("delete alias: %v", err)
+		}
+		if _, err := ResolveExerciseByNameOrAlias(db, "Barbell Squat"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound after delete", err)
+		}
+	})
+}
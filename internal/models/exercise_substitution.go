@@ -0,0 +1,152 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExerciseSubstitution is a standing swap of one exercise for another for a
+// single athlete. Prescriptions show the substitute in place of the original.
+type ExerciseSubstitution struct {
+	ID             int64
+	AthleteID      int64
+	FromExerciseID int64
+	ToExerciseID   int64
+	CreatedAt      time.Time
+
+	// Joined fields.
+	FromExerciseName string
+	ToExerciseName   string
+}
+
+// SetSubstitution records that athleteID does toExerciseID whenever
+// fromExerciseID is prescribed, replacing any existing substitution for
+// fromExerciseID. Returns ErrInvalidInput if the two exercises are the same.
+func SetSubstitution(db *sql.DB, athleteID, fromExerciseID, toExerciseID int64) (*ExerciseSubstitution, error) {
+	if fromExerciseID == toExerciseID {
+		return nil, fmt.Errorf("models: exercise cannot substitute for itself: %w", ErrInvalidInput)
+	}
+
+	var id int64
+	err := db.QueryRow(`
+		INSERT INTO exercise_substitutions (athlete_id, from_exercise_id, to_exercise_id)
+		VALUES (?, ?, ?)
+		ON CONFLICT(athlete_id, from_exercise_id) DO UPDATE SET
+			to_exercise_id = excluded.to_exercise_id,
+			created_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		athleteID, fromExerciseID, toExerciseID,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("models: set substitution for athlete %d: %w", athleteID, err)
+	}
+
+	sub := &ExerciseSubstitution{}
+	err = db.QueryRow(`
+		SELECT s.id, s.athlete_id, s.from_exercise_id, s.to_exercise_id, s.created_at,
+		       fe.name, te.name
+		FROM exercise_substitutions s
+		JOIN exercises fe ON fe.id = s.from_exercise_id
+		JOIN exercises te ON te.id = s.to_exercise_id
+		WHERE s.id = ?`, id,
+	).Scan(&sub.ID, &sub.AthleteID, &sub.FromExerciseID, &sub.ToExerciseID, &sub.CreatedAt,
+		&sub.FromExerciseName, &sub.ToExerciseName)
+	if err != nil {
+		return nil, fmt.Errorf("models: get substitution %d: %w", id, err)
+	}
+	return sub, nil
+}
+
+// DeleteSubstitution removes a substitution belonging to athleteID.
+func DeleteSubstitution(db *sql.DB, athleteID, substitutionID int64) error {
+	result, err := db.Exec(`DELETE FROM exercise_substitutions WHERE id = ? AND athlete_id = ?`, substitutionID, athleteID)
+	if err != nil {
+		return fmt.Errorf("models: delete substitution %d: %w", substitutionID, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListSubstitutions returns an athlete's substitutions ordered by the name of
+// the exercise being replaced.
+func ListSubstitutions(db *sql.DB, athleteID int64) ([]*ExerciseSubstitution, error) {
+	rows, err := db.Query(`
+		SELECT s.id, s.athlete_id, s.from_exercise_id, s.to_exercise_id, s.created_at,
+		       fe.name, te.name
+		FROM exercise_substitutions s
+		JOIN exercises fe ON fe.id = s.from_exercise_id
+		JOIN exercises te ON te.id = s.to_exercise_id
+		WHERE s.athlete_id = ?
+		ORDER BY fe.name COLLATE NOCASE`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list substitutions for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var subs []*ExerciseSubstitution
+	for rows.Next() {
+		s := &ExerciseSubstitution{}
+		if err := rows.Scan(&s.ID, &s.AthleteID, &s.FromExerciseID, &s.ToExerciseID, &s.CreatedAt,
+			&s.FromExerciseName, &s.ToExerciseName); err != nil {
+			return nil, fmt.Errorf("models: scan substitution: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// ResolveSubstitution returns the exercise athleteID should do in place of
+// exerciseID, or exerciseID itself when no substitution exists.
+func ResolveSubstitution(db *sql.DB, athleteID, exerciseID int64) (int64, error) {
+	var toID int64
+	err := db.QueryRow(
+		`SELECT to_exercise_id FROM exercise_substitutions WHERE athlete_id = ? AND from_exercise_id = ?`,
+		athleteID, exerciseID,
+	).Scan(&toID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return exerciseID, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("models: resolve substitution for exercise %d: %w", exerciseID, err)
+	}
+	return toID, nil
+}
+
+// substitutionsByExercise returns an athlete's substitutions keyed by the
+// replaced exercise ID.
+func substitutionsByExercise(db *sql.DB, athleteID int64) (map[int64]*ExerciseSubstitution, error) {
+	subs, err := ListSubstitutions(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int64]*ExerciseSubstitution, len(subs))
+	for _, s := range subs {
+		m[s.FromExerciseID] = s
+	}
+	return m, nil
+}
+
+// applySubstitutions rewrites prescribed sets in place so substituted
+// exercises point at their replacement. Returns the original exercise name
+// for each replacement exercise ID, for "(sub for X)" labels.
+func applySubstitutions(sets []*PrescribedSet, subs map[int64]*ExerciseSubstitution) map[int64]string {
+	if len(subs) == 0 {
+		return nil
+	}
+	replaced := make(map[int64]string)
+	for _, s := range sets {
+		sub, ok := subs[s.ExerciseID]
+		if !ok {
+			continue
+		}
+		replaced[sub.ToExerciseID] = sub.FromExerciseName
+		s.ExerciseID = sub.ToExerciseID
+		s.ExerciseName = sub.ToExerciseName
+	}
+	return replaced
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestExerciseSubstitutions(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Sub Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	goblet, _ := CreateExercise(db, "Goblet Squat", "", "", "", "", 0)
+	split, _ := CreateExercise(db, "Split Squat", "", "", "", "", 0)
+
+	t.Run("self substitution rejected", func(t *testing.T) {
+		if _, err := SetSubstitution(db, a.ID, squat.ID, squat.ID); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("set and resolve", func(t *testing.T) {
+		sub, err := SetSubstitution(db, a.ID, squat.ID, goblet.ID)
+		if err != nil {
+			t.Fatalf("set substitution: %v", err)
+		}
+		if sub.FromExerciseName != "Back Squat" || sub.ToExerciseName != "Goblet Squat" {
+			t.Errorf("sub = %+v, want Back Squat → Goblet Squat", sub)
+		}
+		got, err := ResolveSubstitution(db, a.ID, squat.ID)
+		if err != nil || got != goblet.ID {
+			t.Errorf("resolve = %d, %v; want %d", got, err, goblet.ID)
+		}
+		got, _ = ResolveSubstitution(db, a.ID, goblet.ID)
+		if got != goblet.ID {
+			t.Errorf("resolve unsubstituted = %d, want %d", got, goblet.ID)
+		}
+	})
+
+	t.Run("setting again replaces", func(t *testing.T) {
+		if _, err := SetSubstitution(db, a.ID, squat.ID, split.ID); err != nil {
+			t.Fatalf("replace substitution: %v", err)
+		}
+		subs, _ := ListSubstitutions(db, a.ID)
+		if len(subs) != 1 || subs[0].ToExerciseID != split.ID {
+			t.Errorf("subs = %+v, want single swap to Split Squat", subs)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		subs, _ := ListSubstitutions(db, a.ID)
+		other, _ := CreateAthlete(db, "Other", "", "", "", "", "", "", sql.NullInt64{}, true)
+		if err := DeleteSubstitution(db, other.ID, subs[0].ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("delete other athlete's sub = %v, want ErrNotFound", err)
+		}
+		if err := DeleteSubstitution(db, a.ID, subs[0].ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if subs, _ := ListSubstitutions(db, a.ID); len(subs) != 0 {
+			t.Errorf("subs after delete = %d, want 0", len(subs))
+		}
+	})
+}
+
+func TestGetPrescription_AppliesSubstitutions(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Sub Test", "", 1, 1, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	goblet, _ := CreateExercise(db, "Goblet Squat", "", "", "", "", 0)
+
+	reps := 5
+	pct := 75.0
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "")
+
+	a, _ := CreateAthlete(db, "No Rack", "", "", "", "", "", "", sql.NullInt64{}, true)
+	SetTrainingMax(db, a.ID, goblet.ID, 100, "2026-01-01", "")
+	if _, err := SetSubstitution(db, a.ID, squat.ID, goblet.ID); err != nil {
+		t.Fatalf("set substitution: %v", err)
+	}
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+
+	rx, err := GetPrescription(db, ap, mustParseDate("2026-02-01"))
+	if err != nil {
+		t.Fatalf("get prescription: %v", err)
+	}
+	if len(rx.Lines) != 1 {
+		t.Fatalf("lines = %d, want 1", len(rx.Lines))
+	}
+	line := rx.Lines[0]
+	if line.ExerciseID != goblet.ID || line.ExerciseName != "Goblet Squat" {
+		t.Errorf("line exercise = %d %q, want Goblet Squat", line.ExerciseID, line.ExerciseName)
+	}
+	if line.SubstitutedFor != "Back Squat" {
+		t.Errorf("SubstitutedFor = %q, want Back Squat", line.SubstitutedFor)
+	}
+	// Target weight comes from the substitute's training max.
+	if line.TargetWeight == nil || *line.TargetWeight != 75 {
+		t.Errorf("target weight = %v, want 75", line.TargetWeight)
+	}
+
+	report, err := GetCycleReport(db, ap, mustParseDate("2026-02-01"))
+	if err != nil {
+		t.Fatalf("cycle report: %v", err)
+	}
+	if got := report.Days[0].Lines[0]; got.SubstitutedFor != "Back Squat" {
+		t.Errorf("cycle report SubstitutedFor = %q, want Back Squat", got.SubstitutedFor)
+	}
+}
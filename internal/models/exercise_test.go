@@ -9,7 +9,7 @@ func TestCreateExercise(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("basic create", func(t *testing.T) {
-		e, err := CreateExercise(db, "Bench Press", "intermediate", "Control the descent", "", 0)
+		e, err := CreateExercise(db, "Bench Press", "intermediate", "", "Control the descent", "", 0)
 		if err != nil {
 			t.Fatalf("create exercise: %v", err)
 		}
@@ -22,14 +22,14 @@ func TestCreateExercise(t *testing.T) {
 	})
 
 	t.Run("duplicate name", func(t *testing.T) {
-		_, err := CreateExercise(db, "Bench Press", "", "", "", 0)
+		_, err := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 		if err != ErrDuplicateExerciseName {
 			t.Errorf("err = %v, want ErrDuplicateExerciseName", err)
 		}
 	})
 
 	t.Run("case insensitive duplicate", func(t *testing.T) {
-		_, err := CreateExercise(db, "bench press", "", "", "", 0)
+		_, err := CreateExercise(db, "bench press", "", "", "", "", 0)
 		if err != ErrDuplicateExerciseName {
 			t.Errorf("err = %v, want ErrDuplicateExerciseName", err)
 		}
@@ -39,7 +39,7 @@ func TestCreateExercise(t *testing.T) {
 func TestDeleteExercise(t *testing.T) {
 	db := testDB(t)
 
-	e, _ := CreateExercise(db, "Squats", "", "", "", 0)
+	e, _ := CreateExercise(db, "Squats", "", "", "", "", 0)
 
 	t.Run("delete unreferenced", func(t *testing.T) {
 		if err := DeleteExercise(db, e.ID); err != nil {
@@ -48,7 +48,7 @@ func TestDeleteExercise(t *testing.T) {
 	})
 
 	t.Run("delete referenced (RESTRICT)", func(t *testing.T) {
-		e2, _ := CreateExercise(db, "Deadlift", "", "", "", 0)
+		e2, _ := CreateExercise(db, "Deadlift", "", "", "", "", 0)
 		a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
 		w, _ := CreateWorkout(db, a.ID, "2026-01-01", "", 0)
 		_, err := AddSet(db, w.ID, e2.ID, 5, 225, 0, "", "", "")
@@ -61,17 +61,58 @@ func TestDeleteExercise(t *testing.T) {
 			t.Errorf("err = %v, want ErrExerciseInUse", err)
 		}
 	})
+
+	t.Run("delete with training max history", func(t *testing.T) {
+		e3, _ := CreateExercise(db, "Press", "", "", "", "", 0)
+		a, _ := CreateAthlete(db, "TM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+		if _, err := SetTrainingMax(db, a.ID, e3.ID, 100, "2026-01-01", ""); err != nil {
+			t.Fatalf("set training max: %v", err)
+		}
+		if err := DeleteExercise(db, e3.ID); err != ErrExerciseInUse {
+			t.Errorf("err = %v, want ErrExerciseInUse so TM history is not cascaded away", err)
+		}
+	})
+}
+
+func TestArchivedExercises(t *testing.T) {
+	db := testDB(t)
+
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	if err := SetExerciseArchived(db, bench.ID, true); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	active, _ := ListExercises(db, ExerciseFilter{})
+	if len(active) != 1 || active[0].Name != "Back Squat" {
+		t.Errorf("default list = %v, want only Back Squat", active)
+	}
+	all, _ := ListExercises(db, ExerciseFilter{IncludeArchived: true})
+	if len(all) != 2 {
+		t.Errorf("include archived = %d, want 2", len(all))
+	}
+	archived, err := ListArchivedExercises(db)
+	if err != nil {
+		t.Fatalf("list archived: %v", err)
+	}
+	if len(archived) != 1 || !archived[0].Archived {
+		t.Errorf("archived = %v, want Bench Press", archived)
+	}
+
+	if err := SetExerciseArchived(db, 9999, true); err != ErrNotFound {
+		t.Errorf("archive missing = %v, want ErrNotFound", err)
+	}
 }
 
 func TestListExercises(t *testing.T) {
 	db := testDB(t)
 
-	CreateExercise(db, "Push-ups", "foundational", "", "", 0)
-	CreateExercise(db, "Back Squat", "", "", "", 0)
-	CreateExercise(db, "Cleans", "sport_performance", "", "", 0)
+	CreateExercise(db, "Push-ups", "foundational", "", "", "", 0)
+	CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	CreateExercise(db, "Cleans", "sport_performance", "", "", "", 0)
 
 	t.Run("all", func(t *testing.T) {
-		exercises, err := ListExercises(db, "")
+		exercises, err := ListExercises(db, ExerciseFilter{})
 		if err != nil {
 			t.Fatalf("list: %v", err)
 		}
@@ -81,7 +122,7 @@ func TestListExercises(t *testing.T) {
 	})
 
 	t.Run("filter by tier", func(t *testing.T) {
-		exercises, err := ListExercises(db, "foundational")
+		exercises, err := ListExercises(db, ExerciseFilter{Tier: "foundational"})
 		if err != nil {
 			t.Fatalf("list: %v", err)
 		}
@@ -91,7 +132,7 @@ func TestListExercises(t *testing.T) {
 	})
 
 	t.Run("filter no tier", func(t *testing.T) {
-		exercises, err := ListExercises(db, "none")
+		exercises, err := ListExercises(db, ExerciseFilter{Tier: "none"})
 		if err != nil {
 			t.Fatalf("list: %v", err)
 		}
@@ -104,6 +145,47 @@ func TestListExercises(t *testing.T) {
 	})
 }
 
+func TestListExercises_MuscleGroup(t *testing.T) {
+	db := testDB(t)
+
+	CreateExercise(db, "Push-ups", "foundational", "push", "", "", 0)
+	CreateExercise(db, "Bench Press", "", "push", "", "", 0)
+	CreateExercise(db, "Rows", "foundational", "pull", "", "", 0)
+	plank, _ := CreateExercise(db, "Plank", "", "", "", "", 0)
+
+	tests := []struct {
+		name   string
+		filter ExerciseFilter
+		want   int
+	}{
+		{"push", ExerciseFilter{MuscleGroup: "push"}, 2},
+		{"pull", ExerciseFilter{MuscleGroup: "pull"}, 1},
+		{"uncategorized", ExerciseFilter{MuscleGroup: "none"}, 1},
+		{"push and foundational", ExerciseFilter{Tier: "foundational", MuscleGroup: "push"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exercises, err := ListExercises(db, tt.filter)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(exercises) != tt.want {
+				t.Errorf("count = %d, want %d", len(exercises), tt.want)
+			}
+		})
+	}
+
+	t.Run("update sets group", func(t *testing.T) {
+		ex, err := UpdateExercise(db, plank.ID, "Plank", "", "core", "", "", 0)
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if !ex.MuscleGroup.Valid || ex.MuscleGroup.String != "core" {
+			t.Errorf("muscle group = %v, want core", ex.MuscleGroup)
+		}
+	})
+}
+
 func TestEffectiveRestSeconds(t *testing.T) {
 	t.Run("custom rest", func(t *testing.T) {
 		e := &Exercise{RestSeconds: sql.NullInt64{Int64: 120, Valid: true}}
@@ -123,10 +205,10 @@ func TestEffectiveRestSeconds(t *testing.T) {
 func TestUpdateExercise(t *testing.T) {
 	db := testDB(t)
 
-	e, _ := CreateExercise(db, "Original Name", "foundational", "old notes", "", 0)
+	e, _ := CreateExercise(db, "Original Name", "foundational", "", "old notes", "", 0)
 
 	t.Run("basic update", func(t *testing.T) {
-		updated, err := UpdateExercise(db, e.ID, "New Name", "intermediate", "new notes", "https://demo.url", 120)
+		updated, err := UpdateExercise(db, e.ID, "New Name", "intermediate", "", "new notes", "https://demo.url", 120)
 		if err != nil {
 			t.Fatalf("update exercise: %v", err)
 		}
@@ -142,15 +224,15 @@ func TestUpdateExercise(t *testing.T) {
 	})
 
 	t.Run("duplicate name", func(t *testing.T) {
-		CreateExercise(db, "Taken Name", "", "", "", 0)
-		_, err := UpdateExercise(db, e.ID, "Taken Name", "", "", "", 0)
+		CreateExercise(db, "Taken Name", "", "", "", "", 0)
+		_, err := UpdateExercise(db, e.ID, "Taken Name", "", "", "", "", 0)
 		if err != ErrDuplicateExerciseName {
 			t.Errorf("err = %v, want ErrDuplicateExerciseName", err)
 		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		_, err := UpdateExercise(db, 99999, "Whatever", "", "", "", 0)
+		_, err := UpdateExercise(db, 99999, "Whatever", "", "", "", "", 0)
 		if err != ErrNotFound {
 			t.Errorf("err = %v, want ErrNotFound", err)
 		}
@@ -161,7 +243,7 @@ func TestFeaturedExercise(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("create with featured flag", func(t *testing.T) {
-		e, err := CreateExercise(db, "Featured Squat", "", "", "", 0, true)
+		e, err := CreateExercise(db, "Featured Squat", "", "", "", "", 0, true)
 		if err != nil {
 			t.Fatalf("create: %v", err)
 		}
@@ -171,7 +253,7 @@ func TestFeaturedExercise(t *testing.T) {
 	})
 
 	t.Run("create defaults to not featured", func(t *testing.T) {
-		e, err := CreateExercise(db, "Ordinary Exercise", "", "", "", 0)
+		e, err := CreateExercise(db, "Ordinary Exercise", "", "", "", "", 0)
 		if err != nil {
 			t.Fatalf("create: %v", err)
 		}
@@ -181,8 +263,8 @@ func TestFeaturedExercise(t *testing.T) {
 	})
 
 	t.Run("update featured flag", func(t *testing.T) {
-		e, _ := CreateExercise(db, "Toggle Featured", "", "", "", 0)
-		updated, err := UpdateExercise(db, e.ID, e.Name, "", "", "", 0, true)
+		e, _ := CreateExercise(db, "Toggle Featured", "", "", "", "", 0)
+		updated, err := UpdateExercise(db, e.ID, e.Name, "", "", "", "", 0, true)
 		if err != nil {
 			t.Fatalf("update: %v", err)
 		}
@@ -190,7 +272,7 @@ func TestFeaturedExercise(t *testing.T) {
 			t.Error("expected Featured = true after update")
 		}
 
-		unfeatured, err := UpdateExercise(db, e.ID, e.Name, "", "", "", 0, false)
+		unfeatured, err := UpdateExercise(db, e.ID, e.Name, "", "", "", "", 0, false)
 		if err != nil {
 			t.Fatalf("update: %v", err)
 		}
@@ -204,9 +286,9 @@ func TestListFeaturedLifts(t *testing.T) {
 	db := testDB(t)
 
 	athlete, _ := CreateAthlete(db, "Feat Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	squat, _ := CreateExercise(db, "F Squat", "", "", "", 0, true)
-	bench, _ := CreateExercise(db, "F Bench", "", "", "", 0, true)
-	CreateExercise(db, "F Curl", "", "", "", 0) // not featured
+	squat, _ := CreateExercise(db, "F Squat", "", "", "", "", 0, true)
+	bench, _ := CreateExercise(db, "F Bench", "", "", "", "", 0, true)
+	CreateExercise(db, "F Curl", "", "", "", "", 0) // not featured
 
 	t.Run("no data returns nil", func(t *testing.T) {
 		lifts, err := ListFeaturedLifts(db, athlete.ID)
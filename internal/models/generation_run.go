@@ -0,0 +1,194 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Generation run statuses.
+const (
+	GenerationQueued   = "queued"
+	GenerationRunning  = "running"
+	GenerationComplete = "complete"
+	GenerationFailed   = "failed"
+)
+
+// GenerationRun tracks one background AI Coach program generation. The
+// request and result are opaque JSON owned by the llm package.
+type GenerationRun struct {
+	ID          int64
+	AthleteID   int64
+	UserID      sql.NullInt64
+	Status      string
+	RequestJSON string
+	ResultJSON  sql.NullString
+	Error       sql.NullString
+	ContextJSON sql.NullString // AthleteContext sent to the LLM
+	RawResponse sql.NullString // unprocessed LLM output, kept for failed runs too
+	Model       sql.NullString
+	TokensUsed  sql.NullInt64
+	CreatedAt   time.Time
+	StartedAt   sql.NullTime
+	FinishedAt  sql.NullTime
+}
+
+// Done reports whether the run has finished, successfully or not.
+func (g *GenerationRun) Done() bool {
+	return g.Status == GenerationComplete || g.Status == GenerationFailed
+}
+
+const generationRunColumns = `id, athlete_id, user_id, status, request_json, result_json, error,
+	context_json, raw_response, model, tokens_used, created_at, started_at, finished_at`
+
+func scanGenerationRun(row interface{ Scan(...any) error }) (*GenerationRun, error) {
+	g := &GenerationRun{}
+	err := row.Scan(&g.ID, &g.AthleteID, &g.UserID, &g.Status, &g.RequestJSON, &g.ResultJSON,
+		&g.Error, &g.ContextJSON, &g.RawResponse, &g.Model, &g.TokensUsed, &g.CreatedAt, &g.StartedAt, &g.FinishedAt)
+	return g, err
+}
+
+// CreateGenerationRun inserts a queued generation run for an athlete.
+func CreateGenerationRun(db *sql.DB, athleteID, userID int64, requestJSON string) (*GenerationRun, error) {
+	var uid sql.NullInt64
+	if userID != 0 {
+		uid = sql.NullInt64{Int64: userID, Valid: true}
+	}
+	g, err := scanGenerationRun(db.QueryRow(
+		`INSERT INTO generation_runs (athlete_id, user_id, status, request_json)
+		 VALUES (?, ?, ?, ?)
+		 RETURNING `+generationRunColumns,
+		athleteID, uid, GenerationQueued, requestJSON,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("models: create generation run for athlete %d: %w", athleteID, err)
+	}
+	return g, nil
+}
+
+// GetGenerationRun retrieves a generation run by ID.
+func GetGenerationRun(db *sql.DB, id int64) (*GenerationRun, error) {
+	g, err := scanGenerationRun(db.QueryRow(
+		`SELECT `+generationRunColumns+` FROM generation_runs WHERE id = ?`, id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get generation run %d: %w", id, err)
+	}
+	return g, nil
+}
+
+// MarkGenerationRunRunning moves a queued run to running.
+func MarkGenerationRunRunning(db *sql.DB, id int64) error {
+	_, err := db.Exec(
+		`UPDATE generation_runs SET status = ?, started_at = CURRENT_TIMESTAMP
+		 WHERE id = ? AND status = ?`,
+		GenerationRunning, id, GenerationQueued,
+	)
+	if err != nil {
+		return fmt.Errorf("models: mark generation run %d running: %w", id, err)
+	}
+	return nil
+}
+
+// RecordGenerationResponse stores the context sent to the LLM, its raw
+// response, and the model and token usage reported back, for audit. It is
+// recorded as soon as the provider responds, whether or not the response
+// turns out to be usable.
+func RecordGenerationResponse(db *sql.DB, id int64, contextJSON, rawResponse, model string, tokensUsed int) error {
+	_, err := db.Exec(
+		`UPDATE generation_runs SET context_json = ?, raw_response = ?, model = ?, tokens_used = ? WHERE id = ?`,
+		contextJSON, rawResponse, model, tokensUsed, id,
+	)
+	if err != nil {
+		return fmt.Errorf("models: record generation response for run %d: %w", id, err)
+	}
+	return nil
+}
+
+// CompleteGenerationRun stores the result of a successful run.
+func CompleteGenerationRun(db *sql.DB, id int64, resultJSON string) error {
+	_, err := db.Exec(
+		`UPDATE generation_runs SET status = ?, result_json = ?, error = NULL, finished_at = CURRENT_TIMESTAMP
+		 WHERE id = ?`,
+		GenerationComplete, resultJSON, id,
+	)
+	if err != nil {
+		return fmt.Errorf("models: complete generation run %d: %w", id, err)
+	}
+	return nil
+}
+
+// FailGenerationRun records a user-facing error message for a failed run.
+func FailGenerationRun(db *sql.DB, id int64, errMsg string) error {
+	_, err := db.Exec(
+		`UPDATE generation_runs SET status = ?, error = ?, finished_at = CURRENT_TIMESTAMP
+		 WHERE id = ?`,
+		GenerationFailed, errMsg, id,
+	)
+	if err != nil {
+		return fmt.Errorf("models: fail generation run %d: %w", id, err)
+	}
+	return nil
+}
+
+// ActiveGenerationRun returns the most recent queued or running run for an
+// athlete, or nil if none is in progress.
+func ActiveGenerationRun(db *sql.DB, athleteID int64) (*GenerationRun, error) {
+	g, err := scanGenerationRun(db.QueryRow(
+		`SELECT `+generationRunColumns+` FROM generation_runs
+		 WHERE athlete_id = ? AND status IN (?, ?)
+		 ORDER BY id DESC LIMIT 1`,
+		athleteID, GenerationQueued, GenerationRunning,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get active generation run for athlete %d: %w", athleteID, err)
+	}
+	return g, nil
+}
+
+// ListGenerations returns an athlete's generation runs, newest first.
+func ListGenerations(db *sql.DB, athleteID int64) ([]*GenerationRun, error) {
+	rows, err := db.Query(
+		`SELECT `+generationRunColumns+` FROM generation_runs
+		 WHERE athlete_id = ?
+		 ORDER BY created_at DESC, id DESC`,
+		athleteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list generations for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var runs []*GenerationRun
+	for rows.Next() {
+		g, err := scanGenerationRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("models: scan generation run: %w", err)
+		}
+		runs = append(runs, g)
+	}
+	return runs, rows.Err()
+}
+
+// FailOrphanedGenerationRuns marks queued and running runs as failed. It is
+// called at startup: runs execute in-process, so any still pending after a
+// restart will never finish.
+func FailOrphanedGenerationRuns(db *sql.DB) (int64, error) {
+	res, err := db.Exec(
+		`UPDATE generation_runs SET status = ?, error = ?, finished_at = CURRENT_TIMESTAMP
+		 WHERE status IN (?, ?)`,
+		GenerationFailed, "Generation was interrupted by a server restart. Please try again.",
+		GenerationQueued, GenerationRunning,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: fail orphaned generation runs: %w", err)
+	}
+	return res.RowsAffected()
+}
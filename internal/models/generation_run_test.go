@@ -0,0 +1,113 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestGenerationRunLifecycle(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+
+	run, err := CreateGenerationRun(db, a.ID, coach.ID, `{"ProgramName":"Block"}`)
+	if err != nil {
+		t.Fatalf("create generation run: %v", err)
+	}
+	if run.Status != GenerationQueued || run.Done() {
+		t.Errorf("status = %q, want queued", run.Status)
+	}
+
+	active, err := ActiveGenerationRun(db, a.ID)
+	if err != nil {
+		t.Fatalf("active generation run: %v", err)
+	}
+	if active == nil || active.ID != run.ID {
+		t.Fatalf("active run = %v, want %d", active, run.ID)
+	}
+
+	if err := MarkGenerationRunRunning(db, run.ID); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	got, _ := GetGenerationRun(db, run.ID)
+	if got.Status != GenerationRunning || !got.StartedAt.Valid {
+		t.Errorf("status = %q started = %v, want running with start time", got.Status, got.StartedAt)
+	}
+
+	if err := CompleteGenerationRun(db, run.ID, `{"Reasoning":"ok"}`); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	got, _ = GetGenerationRun(db, run.ID)
+	if got.Status != GenerationComplete || !got.Done() || got.ResultJSON.String != `{"Reasoning":"ok"}` || !got.FinishedAt.Valid {
+		t.Errorf("unexpected completed run: %+v", got)
+	}
+
+	if active, _ := ActiveGenerationRun(db, a.ID); active != nil {
+		t.Errorf("expected no active run, got %d", active.ID)
+	}
+
+	if _, err := GetGenerationRun(db, 9999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFailOrphanedGenerationRuns(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	queued, _ := CreateGenerationRun(db, a.ID, 0, `{}`)
+	running, _ := CreateGenerationRun(db, a.ID, 0, `{}`)
+	MarkGenerationRunRunning(db, running.ID)
+	done, _ := CreateGenerationRun(db, a.ID, 0, `{}`)
+	CompleteGenerationRun(db, done.ID, `{}`)
+
+	n, err := FailOrphanedGenerationRuns(db)
+	if err != nil {
+		t.Fatalf("fail orphaned runs: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("failed %d runs, want 2", n)
+	}
+
+	for _, id := range []int64{queued.ID, running.ID} {
+		got, _ := GetGenerationRun(db, id)
+		if got.Status != GenerationFailed || !got.Error.Valid {
+			t.Errorf("run %d: status = %q error = %v, want failed with message", id, got.Status, got.Error)
+		}
+	}
+	if got, _ := GetGenerationRun(db, done.ID); got.Status != GenerationComplete {
+		t.Errorf("completed run status = %q, want complete", got.Status)
+	}
+}
+
+func TestListGenerations(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	other, _ := CreateAthlete(db, "Other Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	first, _ := CreateGenerationRun(db, a.ID, 0, `{"ProgramName":"Month 1"}`)
+	second, _ := CreateGenerationRun(db, a.ID, 0, `{"ProgramName":"Month 2"}`)
+	CreateGenerationRun(db, other.ID, 0, `{}`)
+
+	if err := RecordGenerationResponse(db, second.ID, `{"athlete":{"name":"Test Athlete"}}`, "no JSON here", "gpt-4o", 1234); err != nil {
+		t.Fatalf("record generation response: %v", err)
+	}
+
+	runs, err := ListGenerations(db, a.ID)
+	if err != nil {
+		t.Fatalf("list generations: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(runs))
+	}
+	if runs[0].ID != second.ID || runs[1].ID != first.ID {
+		t.Errorf("runs not newest first: %d, %d", runs[0].ID, runs[1].ID)
+	}
+	if runs[0].Model.String != "gpt-4o" || runs[0].TokensUsed.Int64 != 1234 || !runs[0].ContextJSON.Valid || runs[0].RawResponse.String != "no JSON here" {
+		t.Errorf("unexpected audit fields: %+v", runs[0])
+	}
+	if runs[1].ContextJSON.Valid || runs[1].Model.Valid {
+		t.Errorf("expected no audit fields on unrecorded run: %+v", runs[1])
+	}
+}
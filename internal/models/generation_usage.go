@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GenerationUsage summarizes AI Coach token spend over a period.
+type GenerationUsage struct {
+	Generations int
+	TokensUsed  int64
+	CostUSD     float64
+}
+
+// RecordGenerationUsage appends a row to the usage ledger for a completed
+// LLM call. runID and userID may be 0 when not applicable.
+func RecordGenerationUsage(db *sql.DB, runID, userID int64, model string, tokensUsed int, costUSD float64) error {
+	var rid, uid sql.NullInt64
+	if runID != 0 {
+		rid = sql.NullInt64{Int64: runID, Valid: true}
+	}
+	if userID != 0 {
+		uid = sql.NullInt64{Int64: userID, Valid: true}
+	}
+	if tokensUsed < 0 {
+		tokensUsed = 0
+	}
+	_, err := db.Exec(
+		`INSERT INTO generation_usage (generation_run_id, user_id, model, tokens_used, cost_usd)
+		 VALUES (?, ?, ?, ?, ?)`,
+		rid, uid, model, tokensUsed, costUSD,
+	)
+	if err != nil {
+		return fmt.Errorf("models: record generation usage: %w", err)
+	}
+	return nil
+}
+
+// MonthlyTokenUsage returns the usage recorded in the calendar month (UTC)
+// containing now.
+func MonthlyTokenUsage(db *sql.DB, now time.Time) (*GenerationUsage, error) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	u := &GenerationUsage{}
+	err := db.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM(tokens_used), 0), COALESCE(SUM(cost_usd), 0)
+		 FROM generation_usage
+		 WHERE created_at >= ? AND created_at < ?`,
+		start.Format("2006-01-02 15:04:05"), end.Format("2006-01-02 15:04:05"),
+	).Scan(&u.Generations, &u.TokensUsed, &u.CostUSD)
+	if err != nil {
+		return nil, fmt.Errorf("models: monthly token usage: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthlyTokenUsage(t *testing.T) {
+	db := testDB(t)
+
+	if err := RecordGenerationUsage(db, 0, 0, "gpt-4o", 1200, 0.006); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := RecordGenerationUsage(db, 0, 0, "gpt-4o", 800, 0.004); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	// Last month's usage doesn't count toward this month.
+	if _, err := db.Exec(`INSERT INTO generation_usage (model, tokens_used, created_at)
+		VALUES ('gpt-4o', 5000, datetime('now', 'start of month', '-1 day'))`); err != nil {
+		t.Fatalf("insert old usage: %v", err)
+	}
+
+	usage, err := MonthlyTokenUsage(db, time.Now())
+	if err != nil {
+		t.Fatalf("monthly usage: %v", err)
+	}
+	if usage.Generations != 2 {
+		t.Errorf("generations = %d, want 2", usage.Generations)
+	}
+	if usage.TokensUsed != 2000 {
+		t.Errorf("tokens = %d, want 2000", usage.TokensUsed)
+	}
+	if usage.CostUSD < 0.0099 || usage.CostUSD > 0.0101 {
+		t.Errorf("cost = %f, want 0.01", usage.CostUSD)
+	}
+}
+
+func TestGetModelPrice(t *testing.T) {
+	db := testDB(t)
+
+	if got := GetModelPrice(db, "gpt-4o"); got != 0 {
+		t.Errorf("expected 0 with no prices set, got %f", got)
+	}
+
+	if err := SetSetting(db, "llm.model_prices", "gpt-4o = 5.00\nclaude-sonnet-4-20250514=9\nbroken"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := GetModelPrice(db, "gpt-4o"); got != 5 {
+		t.Errorf("gpt-4o price = %f, want 5", got)
+	}
+	if got := GenerationCostUSD(db, "claude-sonnet-4-20250514", 500_000); got != 4.5 {
+		t.Errorf("cost = %f, want 4.5", got)
+	}
+	if got := GetModelPrice(db, "llama3"); got != 0 {
+		t.Errorf("unpriced model = %f, want 0", got)
+	}
+}
+
+func TestGetMonthlyTokenCap(t *testing.T) {
+	db := testDB(t)
+
+	if got := GetMonthlyTokenCap(db); got != 0 {
+		t.Errorf("expected default 0, got %d", got)
+	}
+	if err := SetSetting(db, "llm.monthly_token_cap", "250000"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := GetMonthlyTokenCap(db); got != 250000 {
+		t.Errorf("expected 250000, got %d", got)
+	}
+}
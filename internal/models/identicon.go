@@ -0,0 +1,54 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// identiconGrid is the number of cells along each side of an identicon.
+const identiconGrid = 5
+
+// IdenticonFor returns a deterministic SVG identicon for a user without an
+// uploaded avatar: a horizontally symmetric 5×5 pattern in a colour, both
+// derived from a hash of the username. It needs no network access.
+func IdenticonFor(u *User) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(u.Username)))
+	hue := (int(sum[0])<<8 | int(sum[1])) % 360
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="-0.5 -0.5 %d %d" shape-rendering="crispEdges">`, identiconGrid+1, identiconGrid+1)
+	fmt.Fprintf(&b, `<rect x="-0.5" y="-0.5" width="%d" height="%d" fill="hsl(%d, 30%%, 93%%)"/>`, identiconGrid+1, identiconGrid+1, hue)
+	fmt.Fprintf(&b, `<g fill="hsl(%d, 55%%, 48%%)">`, hue)
+	// Fill the left half plus the middle column from the hash bits, then
+	// mirror onto the right half.
+	half := (identiconGrid + 1) / 2
+	bit := 0
+	for x := 0; x < half; x++ {
+		for y := 0; y < identiconGrid; y++ {
+			if sum[2+bit/8]&(1<<(bit%8)) != 0 {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1"/>`, x, y)
+				if mx := identiconGrid - 1 - x; mx != x {
+					fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1"/>`, mx, y)
+				}
+			}
+			bit++
+		}
+	}
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
+
+// GravatarURL returns the Gravatar image URL for the user's email address,
+// falling back to Gravatar's own identicon, or "" if the user has no email.
+// Only used when the avatars.gravatar setting is on, since loading it sends
+// a hash of the address to gravatar.com.
+func GravatarURL(u *User, size int) string {
+	email := strings.ToLower(strings.TrimSpace(u.Email.String))
+	if !u.Email.Valid || email == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(email))
+	return fmt.Sprintf("https://www.gravatar.com/avatar/%s?s=%d&d=identicon", hex.EncodeToString(sum[:]), size)
+}
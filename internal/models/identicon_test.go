@@ -0,0 +1,41 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestIdenticonFor(t *testing.T) {
+	alice := &User{ID: 1, Username: "alice"}
+	svg := IdenticonFor(alice)
+
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("identicon is not well-formed XML: %v", err)
+	}
+	if !strings.HasPrefix(svg, "<svg") {
+		t.Errorf("identicon doesn't start with <svg: %q", svg)
+	}
+	// Same username, same image, whatever the case or ID.
+	if IdenticonFor(&User{ID: 99, Username: "Alice"}) != svg {
+		t.Error("identicon should depend only on the username")
+	}
+	if IdenticonFor(&User{ID: 2, Username: "bob"}) == svg {
+		t.Error("different usernames should get different identicons")
+	}
+}
+
+func TestGravatarURL(t *testing.T) {
+	u := &User{Email: sql.NullString{String: " Someone@Example.com ", Valid: true}}
+	got := GravatarURL(u, 128)
+	// SHA-256 of "someone@example.com".
+	want := "https://www.gravatar.com/avatar/72497f475e4f76d0b28f57c73a084ece576d170874eba3ee2609d9afe4b71aab?s=128&d=identicon"
+	if got != want {
+		t.Errorf("GravatarURL = %q, want %q", got, want)
+	}
+
+	if got := GravatarURL(&User{}, 128); got != "" {
+		t.Errorf("GravatarURL without email = %q, want empty", got)
+	}
+}
@@ -80,6 +80,31 @@ func BuildImportPreview(db *sql.DB, athleteID int64, ms *importers.MappingState)
 	p.TrainingMaxCount = len(pf.TrainingMaxes)
 	p.BodyWeightCount = len(pf.BodyWeights)
 
+	// Body weights already logged on a date are skipped at execution.
+	if len(pf.BodyWeights) > 0 {
+		existing, err := bodyWeightDates(db, athleteID)
+		if err != nil {
+			return nil, err
+		}
+		for _, bw := range pf.BodyWeights {
+			date := normalizeDate(bw.Date)
+			if existing[date] {
+				p.BodyWeightConflictDates = append(p.BodyWeightConflictDates, date)
+			}
+			if ms.Format == importers.FormatBodyWeightCSV {
+				if minDate == "" || date < minDate {
+					minDate = date
+				}
+				if maxDate == "" || date > maxDate {
+					maxDate = date
+				}
+			}
+		}
+		if minDate != "" && maxDate != "" {
+			p.DateRange = minDate + " to " + maxDate
+		}
+	}
+
 	// Computed aggregate counts for template display.
 	p.ExerciseCount = p.ExercisesNew + p.ExercisesMapped
 	p.EquipmentCount = p.EquipmentNew + p.EquipmentMapped
@@ -146,6 +171,18 @@ func validateImportData(pf *importers.ParsedFile) []ValidationWarning {
 		}
 	}
 
+	for _, prog := range pf.Programs {
+		for _, ps := range prog.Template.PrescribedSets {
+			if _, warning := importers.CheckTargetRPE(ps); warning != "" {
+				warnings = append(warnings, ValidationWarning{
+					Entity:  "prescribed_set",
+					Field:   "target_rpe",
+					Message: fmt.Sprintf("Program %q: %s", prog.Template.Name, warning),
+				})
+			}
+		}
+	}
+
 	for _, tm := range pf.TrainingMaxes {
 		if tm.Weight < 0 {
 			warnings = append(warnings, ValidationWarning{
@@ -169,11 +206,38 @@ func validateImportData(pf *importers.ParsedFile) []ValidationWarning {
 	return warnings
 }
 
+// ConflictMode controls how ExecuteImport handles an imported workout whose
+// date already has a workout for the athlete.
+type ConflictMode string
+
+const (
+	ConflictSkip    ConflictMode = "skip"    // keep the existing workout untouched
+	ConflictMerge   ConflictMode = "merge"   // add imported sets missing from the existing workout
+	ConflictReplace ConflictMode = "replace" // delete the existing workout and re-create it
+)
+
+// ParseConflictMode returns the ConflictMode named by s, defaulting to
+// ConflictSkip for empty or unknown values.
+func ParseConflictMode(s string) ConflictMode {
+	switch ConflictMode(s) {
+	case ConflictMerge, ConflictReplace:
+		return ConflictMode(s)
+	default:
+		return ConflictSkip
+	}
+}
+
 // ExecuteImport performs the import in a single transaction. It creates new
-// entities as specified by the mapping, then imports all data.
-func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingState) (*ImportResult, error) {
-	pf := ms.Parsed
+// entities as specified by the mapping, then imports all data. Workouts on
+// dates that already have one are resolved according to mode.
+func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingState, mode ConflictMode) (*ImportResult, error) {
+	unit := ms.WeightUnit
+	if !isValidWeightUnit(unit) {
+		unit = DefaultWeightUnit
+	}
+	pf := parsedWeightsInLbs(ms.Parsed, unit)
 	result := &ImportResult{}
+	var skips importSkips
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -187,14 +251,8 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 		if m.MappedID > 0 {
 			equipmentIDMap[strings.ToLower(m.ImportName)] = m.MappedID
 		} else if m.Create {
-			desc := ""
-			for _, pe := range pf.Equipment {
-				if strings.EqualFold(pe.Name, m.ImportName) && pe.Description != nil {
-					desc = *pe.Description
-					break
-				}
-			}
-			id, err := insertEquipment(tx, m.ImportName, desc)
+			pe := findParsedEquipment(pf.Equipment, m.ImportName)
+			id, err := insertEquipment(tx, m.ImportName, pe)
 			if err != nil {
 				return nil, fmt.Errorf("models: import create equipment %q: %w", m.ImportName, err)
 			}
@@ -211,14 +269,19 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 		} else if m.Create {
 			pe := findParsedExercise(pf.Exercises, m.ImportName)
 			tier := ""
+			muscleGroup := ""
 			formNotes := ""
 			demoURL := ""
 			restSeconds := 0
 			featured := false
+			unilateral := false
 			if pe != nil {
 				if pe.Tier != nil {
 					tier = *pe.Tier
 				}
+				if pe.MuscleGroup != nil {
+					muscleGroup = *pe.MuscleGroup
+				}
 				if pe.FormNotes != nil {
 					formNotes = *pe.FormNotes
 				}
@@ -229,8 +292,9 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 					restSeconds = *pe.RestSeconds
 				}
 				featured = pe.Featured
+				unilateral = pe.Unilateral
 			}
-			id, err := insertExercise(tx, m.ImportName, tier, formNotes, demoURL, restSeconds, featured)
+			id, err := insertExercise(tx, m.ImportName, tier, muscleGroup, formNotes, demoURL, restSeconds, featured, unilateral)
 			if err != nil {
 				return nil, fmt.Errorf("models: import create exercise %q: %w", m.ImportName, err)
 			}
@@ -265,6 +329,7 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 	for _, a := range pf.Assignments {
 		exID, ok := exerciseIDMap[strings.ToLower(a.Exercise)]
 		if !ok {
+			skips.unmapped(result, "assignment", a.Exercise)
 			continue
 		}
 		targetReps := 0
@@ -286,6 +351,7 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 	for _, tm := range pf.TrainingMaxes {
 		exID, ok := exerciseIDMap[strings.ToLower(tm.Exercise)]
 		if !ok {
+			skips.unmapped(result, "training max", tm.Exercise)
 			continue
 		}
 		date := normalizeDate(tm.EffectiveDate)
@@ -304,14 +370,14 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 		result.TrainingMaxesCreated++
 	}
 
-	// Phase 6: Body weights (RepLog JSON only).
+	// Phase 6: Body weights (RepLog JSON, body weight CSV).
 	for _, bw := range pf.BodyWeights {
 		date := normalizeDate(bw.Date)
 		notes := ""
 		if bw.Notes != nil {
 			notes = *bw.Notes
 		}
-		if err := insertBodyWeight(tx, athleteID, date, bw.Weight, notes); err != nil {
+		if err := insertBodyWeight(tx, athleteID, date, bw.Weight, unit, notes); err != nil {
 			if !isUniqueViolation(err) {
 				return nil, fmt.Errorf("models: import body weight: %w", err)
 			}
@@ -325,34 +391,75 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 	for _, w := range pf.Workouts {
 		date := normalizeDate(w.Date)
 
-		// Skip if workout already exists on this date.
-		_, err := getWorkoutByAthleteDateTx(tx, athleteID, date)
-		if err == nil {
-			result.WorkoutsSkipped++
-			continue
-		}
-
 		notes := ""
 		if w.Notes != nil {
 			notes = *w.Notes
 		}
 
-		workoutID, err := insertWorkout(tx, athleteID, date, notes, 0)
-		if err != nil {
-			if isUniqueViolation(err) {
+		// Resolve a conflict with an existing workout on this date.
+		var workoutID int64
+		var existingSets map[workoutSetKey]bool
+		existingID, err := getWorkoutByAthleteDateTx(tx, athleteID, date)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("models: import check workout on %s: %w", date, err)
+		}
+		merging := false
+		if err == nil {
+			switch mode {
+			case ConflictMerge:
+				existingSets, err = workoutSetKeysTx(tx, existingID)
+				if err != nil {
+					return nil, fmt.Errorf("models: import load sets for workout on %s: %w", date, err)
+				}
+				// Keep a duration the workout already has.
+				if w.DurationMinutes != nil && *w.DurationMinutes > 0 {
+					if _, err := tx.Exec(`UPDATE workouts SET duration_minutes = ? WHERE id = ? AND duration_minutes IS NULL`,
+						*w.DurationMinutes, existingID); err != nil {
+						return nil, fmt.Errorf("models: import duration for workout on %s: %w", date, err)
+					}
+				}
+				workoutID = existingID
+				merging = true
+				result.WorkoutsMerged++
+			case ConflictReplace:
+				if _, err := tx.Exec(`DELETE FROM workouts WHERE id = ?`, existingID); err != nil {
+					return nil, fmt.Errorf("models: import replace workout on %s: %w", date, err)
+				}
+				result.WorkoutsReplaced++
+			default:
 				result.WorkoutsSkipped++
 				continue
 			}
-			return nil, fmt.Errorf("models: import workout on %s: %w", date, err)
 		}
-		result.WorkoutsCreated++
+
+		if !merging {
+			workoutID, err = insertWorkout(tx, athleteID, date, notes, 0, w.DurationMinutes)
+			if err != nil {
+				if isUniqueViolation(err) {
+					result.WorkoutsSkipped++
+					continue
+				}
+				return nil, fmt.Errorf("models: import workout on %s: %w", date, err)
+			}
+			if existingID == 0 {
+				result.WorkoutsCreated++
+			}
+		}
 
 		// Sets.
 		for _, s := range w.Sets {
 			exID, ok := exerciseIDMap[strings.ToLower(s.Exercise)]
 			if !ok {
+				skips.unmapped(result, "set", s.Exercise)
 				continue
 			}
+			if merging {
+				key := workoutSetKey{exID, s.SetNumber}
+				if existingSets[key] {
+					continue
+				}
+				existingSets[key] = true
+			}
 			weight := 0.0
 			if s.Weight != nil {
 				weight = *s.Weight
@@ -375,8 +482,8 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 			result.SetsCreated++
 		}
 
-		// Review (RepLog JSON only).
-		if w.Review != nil && coachID > 0 {
+		// Review (RepLog JSON only). A merged workout keeps its own review.
+		if w.Review != nil && coachID > 0 && !merging {
 			rNotes := ""
 			if w.Review.Notes != nil {
 				rNotes = *w.Review.Notes
@@ -412,8 +519,13 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 					for _, ps := range prog.Template.PrescribedSets {
 						exID, exOK := exerciseIDMap[strings.ToLower(ps.Exercise)]
 						if !exOK {
+							skips.unmapped(result, "prescribed set", ps.Exercise)
 							continue
 						}
+						var warning string
+						if ps.TargetRPE, warning = importers.CheckTargetRPE(ps); warning != "" {
+							skips.add(warning)
+						}
 						if err := insertPrescribedSet(tx, templateID, exID, ps); err != nil {
 							return nil, fmt.Errorf("models: import prescribed set: %w", err)
 						}
@@ -423,18 +535,24 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 					for _, pr := range prog.Template.ProgressionRules {
 						exID, exOK := exerciseIDMap[strings.ToLower(pr.Exercise)]
 						if !exOK {
+							skips.unmapped(result, "progression rule", pr.Exercise)
 							continue
 						}
-						if err := insertProgressionRule(tx, templateID, exID, pr.Increment); err != nil {
+						if err := insertProgressionRule(tx, templateID, exID, pr); err != nil {
 							return nil, fmt.Errorf("models: import progression rule: %w", err)
 						}
 					}
+
+					if err := setProgramTemplateHash(tx, templateID, prog.Template); err != nil {
+						return nil, fmt.Errorf("models: import hash program template %q: %w", prog.Template.Name, err)
+					}
 				}
 				break
 			}
 		}
 
 		if !ok || templateID == 0 {
+			skips.add(fmt.Sprintf("program skipped: %q not mapped", prog.Template.Name))
 			continue
 		}
 
@@ -465,34 +583,172 @@ func ExecuteImport(db *sql.DB, athleteID, coachID int64, ms *importers.MappingSt
 		}
 	}
 
+	// Phase 9: Accessory plans (RepLog JSON only).
+	for _, ap := range pf.AccessoryPlans {
+		exID, ok := exerciseIDMap[strings.ToLower(ap.Exercise)]
+		if !ok {
+			skips.unmapped(result, "accessory plan", ap.Exercise)
+			continue
+		}
+		if ap.Day < 1 {
+			skips.add(fmt.Sprintf("accessory plan skipped: %q has invalid day %d", ap.Exercise, ap.Day))
+			continue
+		}
+		if err := insertAccessoryPlan(tx, athleteID, exID, ap); err != nil {
+			if !isUniqueViolation(err) {
+				return nil, fmt.Errorf("models: import accessory plan: %w", err)
+			}
+			result.AccessoriesSkipped++
+			continue
+		}
+		result.AccessoriesCreated++
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("models: commit import: %w", err)
 	}
 
+	result.Warnings = skips.warnings()
 	return result, nil
 }
 
+// importSkips tallies rows ExecuteImport drops so each distinct reason is
+// reported once with a row count rather than once per row.
+type importSkips struct {
+	order  []string
+	counts map[string]int
+}
+
+func (s *importSkips) add(reason string) {
+	if s.counts == nil {
+		s.counts = make(map[string]int)
+	}
+	if s.counts[reason] == 0 {
+		s.order = append(s.order, reason)
+	}
+	s.counts[reason]++
+}
+
+// unmapped records a row dropped because its exercise has no mapping.
+func (s *importSkips) unmapped(result *ImportResult, what, exercise string) {
+	result.UnmappedSkipped++
+	s.add(fmt.Sprintf("%s skipped: exercise %q unmapped", what, exercise))
+}
+
+func (s *importSkips) warnings() []string {
+	out := make([]string, 0, len(s.order))
+	for _, reason := range s.order {
+		if n := s.counts[reason]; n > 1 {
+			reason = fmt.Sprintf("%s (%d rows)", reason, n)
+		}
+		out = append(out, reason)
+	}
+	return out
+}
+
+// parsedWeightsInLbs returns a copy of the parsed file with every weight
+// converted from unit to the canonical lbs stored in the database. The
+// original is left untouched since it lives in the user's session.
+func parsedWeightsInLbs(pf *importers.ParsedFile, unit string) *importers.ParsedFile {
+	if unit == "lbs" {
+		return pf
+	}
+	conv := func(v float64) float64 { return ConvertWeight(v, unit, "lbs") }
+	convPtr := func(p *float64) *float64 {
+		if p == nil {
+			return nil
+		}
+		v := conv(*p)
+		return &v
+	}
+
+	out := *pf
+	out.Equipment = append([]importers.ParsedEquipment(nil), pf.Equipment...)
+	for i := range out.Equipment {
+		out.Equipment[i].UnitWeight = convPtr(out.Equipment[i].UnitWeight)
+	}
+	out.TrainingMaxes = append([]importers.ParsedTrainingMax(nil), pf.TrainingMaxes...)
+	for i := range out.TrainingMaxes {
+		out.TrainingMaxes[i].Weight = conv(out.TrainingMaxes[i].Weight)
+	}
+	out.BodyWeights = append([]importers.ParsedBodyWeight(nil), pf.BodyWeights...)
+	for i := range out.BodyWeights {
+		out.BodyWeights[i].Weight = conv(out.BodyWeights[i].Weight)
+	}
+	out.Workouts = append([]importers.ParsedWorkout(nil), pf.Workouts...)
+	for i := range out.Workouts {
+		out.Workouts[i].Sets = append([]importers.ParsedWorkoutSet(nil), pf.Workouts[i].Sets...)
+		for j := range out.Workouts[i].Sets {
+			out.Workouts[i].Sets[j].Weight = convPtr(out.Workouts[i].Sets[j].Weight)
+		}
+	}
+	out.Programs = append([]importers.ParsedProgram(nil), pf.Programs...)
+	for i := range out.Programs {
+		tmpl := &out.Programs[i].Template
+		tmpl.PrescribedSets = append([]importers.ParsedPrescribedSet(nil), pf.Programs[i].Template.PrescribedSets...)
+		for j := range tmpl.PrescribedSets {
+			tmpl.PrescribedSets[j].AbsoluteWeight = convPtr(tmpl.PrescribedSets[j].AbsoluteWeight)
+		}
+		tmpl.ProgressionRules = append([]importers.ParsedProgressionRule(nil), pf.Programs[i].Template.ProgressionRules...)
+		for j := range tmpl.ProgressionRules {
+			tmpl.ProgressionRules[j].Increment = conv(tmpl.ProgressionRules[j].Increment)
+		}
+	}
+	out.AccessoryPlans = append([]importers.ParsedAccessoryPlan(nil), pf.AccessoryPlans...)
+	for i := range out.AccessoryPlans {
+		out.AccessoryPlans[i].TargetWeight = convPtr(out.AccessoryPlans[i].TargetWeight)
+	}
+	return &out
+}
+
 // --- Transaction-level insert helpers ---
 
-func insertEquipment(tx *sql.Tx, name, description string) (int64, error) {
+// findParsedEquipment returns the parsed equipment entry matching name, or nil.
+func findParsedEquipment(equipment []importers.ParsedEquipment, name string) *importers.ParsedEquipment {
+	for i := range equipment {
+		if strings.EqualFold(equipment[i].Name, name) {
+			return &equipment[i]
+		}
+	}
+	return nil
+}
+
+// insertEquipment creates an equipment row, copying description, quantity,
+// and unit weight from pe when present.
+func insertEquipment(tx *sql.Tx, name string, pe *importers.ParsedEquipment) (int64, error) {
 	var descVal sql.NullString
-	if description != "" {
-		descVal = sql.NullString{String: description, Valid: true}
+	quantity, unitWeight := 0, 0.0
+	if pe != nil {
+		if pe.Description != nil && *pe.Description != "" {
+			descVal = sql.NullString{String: *pe.Description, Valid: true}
+		}
+		if pe.Quantity != nil {
+			quantity = *pe.Quantity
+		}
+		if pe.UnitWeight != nil {
+			unitWeight = *pe.UnitWeight
+		}
 	}
+	qty, weightVal := equipmentLoadValues(quantity, unitWeight)
 	var id int64
-	err := tx.QueryRow(`INSERT INTO equipment (name, description) VALUES (?, ?) RETURNING id`, name, descVal).Scan(&id)
+	err := tx.QueryRow(`INSERT INTO equipment (name, description, quantity, unit_weight) VALUES (?, ?, ?, ?) RETURNING id`, name, descVal, qty, weightVal).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-func insertExercise(tx *sql.Tx, name, tier, formNotes, demoURL string, restSeconds int, featured bool) (int64, error) {
-	var tierVal, notesVal, demoVal sql.NullString
+func insertExercise(tx *sql.Tx, name, tier, muscleGroup, formNotes, demoURL string, restSeconds int, featured, unilateral bool) (int64, error) {
+	var tierVal, groupVal, notesVal, demoVal sql.NullString
 	var restVal sql.NullInt64
 	if tier != "" {
 		tierVal = sql.NullString{String: tier, Valid: true}
 	}
+	// Unknown groups from hand-edited files are dropped rather than failing
+	// the whole import on the CHECK constraint.
+	if IsValidMuscleGroup(muscleGroup) {
+		groupVal = sql.NullString{String: muscleGroup, Valid: true}
+	}
 	if formNotes != "" {
 		notesVal = sql.NullString{String: formNotes, Valid: true}
 	}
@@ -508,8 +764,8 @@ func insertExercise(tx *sql.Tx, name, tier, formNotes, demoURL string, restSecon
 	}
 	var id int64
 	err := tx.QueryRow(
-		`INSERT INTO exercises (name, tier, form_notes, demo_url, rest_seconds, featured) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
-		name, tierVal, notesVal, demoVal, restVal, featuredInt,
+		`INSERT INTO exercises (name, tier, muscle_group, form_notes, demo_url, rest_seconds, featured, unilateral) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		name, tierVal, groupVal, notesVal, demoVal, restVal, featuredInt, unilateral,
 	).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -557,19 +813,36 @@ func insertTrainingMax(tx *sql.Tx, athleteID, exerciseID int64, weight float64,
 	return err
 }
 
-func insertBodyWeight(tx *sql.Tx, athleteID int64, date string, weight float64, notes string) error {
+func insertBodyWeight(tx *sql.Tx, athleteID int64, date string, weight float64, unit, notes string) error {
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
 	}
 	_, err := tx.Exec(
-		`INSERT INTO body_weights (athlete_id, date, weight, notes) VALUES (?, ?, ?, ?)`,
-		athleteID, date, weight, notesVal,
+		`INSERT INTO body_weights (athlete_id, date, weight, unit, notes) VALUES (?, ?, ?, ?, ?)`,
+		athleteID, date, weight, unit, notesVal,
 	)
 	return err
 }
 
-func insertWorkout(tx *sql.Tx, athleteID int64, date, notes string, assignmentID int64) (int64, error) {
+func insertAccessoryPlan(tx *sql.Tx, athleteID, exerciseID int64, ap importers.ParsedAccessoryPlan) error {
+	var notesVal sql.NullString
+	if ap.Notes != nil && *ap.Notes != "" {
+		notesVal = sql.NullString{String: *ap.Notes, Valid: true}
+	}
+	active := true
+	if ap.Active != nil {
+		active = *ap.Active
+	}
+	_, err := tx.Exec(
+		`INSERT INTO accessory_plans (athlete_id, day, exercise_id, target_sets, target_rep_min, target_rep_max, target_weight, notes, sort_order, superset, active)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		athleteID, ap.Day, exerciseID, ap.TargetSets, ap.TargetRepMin, ap.TargetRepMax, ap.TargetWeight, notesVal, ap.SortOrder, ap.Superset, active,
+	)
+	return err
+}
+
+func insertWorkout(tx *sql.Tx, athleteID int64, date, notes string, assignmentID int64, durationMinutes *int) (int64, error) {
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
@@ -578,10 +851,14 @@ func insertWorkout(tx *sql.Tx, athleteID int64, date, notes string, assignmentID
 	if assignmentID > 0 {
 		assignVal = sql.NullInt64{Int64: assignmentID, Valid: true}
 	}
+	var durationVal sql.NullInt64
+	if durationMinutes != nil && *durationMinutes > 0 {
+		durationVal = sql.NullInt64{Int64: int64(*durationMinutes), Valid: true}
+	}
 	var id int64
 	err := tx.QueryRow(
-		`INSERT INTO workouts (athlete_id, date, assignment_id, notes) VALUES (?, ?, ?, ?) RETURNING id`,
-		athleteID, date, assignVal, notesVal,
+		`INSERT INTO workouts (athlete_id, date, assignment_id, notes, duration_minutes) VALUES (?, ?, ?, ?, ?) RETURNING id`,
+		athleteID, date, assignVal, notesVal, durationVal,
 	).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -598,6 +875,30 @@ func getWorkoutByAthleteDateTx(tx *sql.Tx, athleteID int64, date string) (int64,
 	return id, err
 }
 
+// workoutSetKey identifies a logged set by exercise and set number.
+type workoutSetKey struct {
+	exerciseID int64
+	setNumber  int
+}
+
+func workoutSetKeysTx(tx *sql.Tx, workoutID int64) (map[workoutSetKey]bool, error) {
+	rows, err := tx.Query(`SELECT exercise_id, set_number FROM workout_sets WHERE workout_id = ?`, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make(map[workoutSetKey]bool)
+	for rows.Next() {
+		var k workoutSetKey
+		if err := rows.Scan(&k.exerciseID, &k.setNumber); err != nil {
+			return nil, err
+		}
+		keys[k] = true
+	}
+	return keys, rows.Err()
+}
+
 func insertSet(tx *sql.Tx, workoutID, exerciseID int64, setNumber, reps int, weight, rpe float64, repType, category, notes string) error {
 	var weightVal sql.NullFloat64
 	if weight > 0 {
@@ -622,11 +923,15 @@ func insertSet(tx *sql.Tx, workoutID, exerciseID int64, setNumber, reps int, wei
 }
 
 func insertReview(tx *sql.Tx, workoutID, coachID int64, status, notes string) error {
+	status, err := NormalizeReviewStatus(status)
+	if err != nil {
+		return err
+	}
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
 	}
-	_, err := tx.Exec(
+	_, err = tx.Exec(
 		`INSERT INTO workout_reviews (workout_id, coach_id, status, notes) VALUES (?, ?, ?, ?)`,
 		workoutID, coachID, status, notesVal,
 	)
@@ -657,11 +962,26 @@ func insertProgramTemplate(tx *sql.Tx, pt importers.ParsedProgramTemplate, athle
 	return id, nil
 }
 
+// setProgramTemplateHash stores the content hash of a freshly imported
+// template. It must run after the template's prescribed sets are inserted,
+// since the prescribed_sets triggers clear the hash on every set change.
+func setProgramTemplateHash(tx *sql.Tx, templateID int64, pt importers.ParsedProgramTemplate) error {
+	_, err := tx.Exec(`UPDATE program_templates SET content_hash = ? WHERE id = ?`, importers.ProgramTemplateHash(pt), templateID)
+	return err
+}
+
 func insertPrescribedSet(tx *sql.Tx, templateID, exerciseID int64, ps importers.ParsedPrescribedSet) error {
+	if err := checkRepRange(ps.Reps, ps.RepMax); err != nil {
+		return fmt.Errorf("%s week %d day %d set %d: %w", ps.Exercise, ps.Week, ps.Day, ps.SetNumber, err)
+	}
 	var repsVal sql.NullInt64
 	if ps.Reps != nil {
 		repsVal = sql.NullInt64{Int64: int64(*ps.Reps), Valid: true}
 	}
+	var repMaxVal sql.NullInt64
+	if ps.Reps != nil && ps.RepMax != nil {
+		repMaxVal = sql.NullInt64{Int64: int64(*ps.RepMax), Valid: true}
+	}
 	var pctVal sql.NullFloat64
 	if ps.Percentage != nil {
 		pctVal = sql.NullFloat64{Float64: *ps.Percentage, Valid: true}
@@ -670,6 +990,14 @@ func insertPrescribedSet(tx *sql.Tx, templateID, exerciseID int64, ps importers.
 	if ps.AbsoluteWeight != nil {
 		absWeightVal = sql.NullFloat64{Float64: *ps.AbsoluteWeight, Valid: true}
 	}
+	var rpeVal sql.NullFloat64
+	if ps.TargetRPE != nil {
+		rpeVal = sql.NullFloat64{Float64: *ps.TargetRPE, Valid: true}
+	}
+	var restVal sql.NullInt64
+	if ps.RestSeconds != nil {
+		restVal = sql.NullInt64{Int64: int64(*ps.RestSeconds), Valid: true}
+	}
 	var notesVal sql.NullString
 	if ps.Notes != nil && *ps.Notes != "" {
 		notesVal = sql.NullString{String: *ps.Notes, Valid: true}
@@ -679,16 +1007,28 @@ func insertPrescribedSet(tx *sql.Tx, templateID, exerciseID int64, ps importers.
 		repType = "reps"
 	}
 	_, err := tx.Exec(
-		`INSERT INTO prescribed_sets (template_id, exercise_id, week, day, set_number, reps, percentage, absolute_weight, sort_order, rep_type, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		templateID, exerciseID, ps.Week, ps.Day, ps.SetNumber, repsVal, pctVal, absWeightVal, ps.SortOrder, repType, notesVal,
+		`INSERT INTO prescribed_sets (template_id, exercise_id, week, day, set_number, reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		templateID, exerciseID, ps.Week, ps.Day, ps.SetNumber, repsVal, repMaxVal, pctVal, absWeightVal, rpeVal, restVal, ps.SortOrder, repType, notesVal,
 	)
 	return err
 }
 
-func insertProgressionRule(tx *sql.Tx, templateID, exerciseID int64, increment float64) error {
-	_, err := tx.Exec(
-		`INSERT OR REPLACE INTO progression_rules (template_id, exercise_id, increment) VALUES (?, ?, ?)`,
-		templateID, exerciseID, increment,
+func insertProgressionRule(tx *sql.Tx, templateID, exerciseID int64, pr importers.ParsedProgressionRule) error {
+	var condition string
+	if pr.Condition != nil {
+		condition = *pr.Condition
+	}
+	var threshold int
+	if pr.Threshold != nil {
+		threshold = *pr.Threshold
+	}
+	condVal, thresholdVal, err := progressionConditionValues(condition, threshold)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT OR REPLACE INTO progression_rules (template_id, exercise_id, increment, condition, threshold) VALUES (?, ?, ?, ?, ?)`,
+		templateID, exerciseID, pr.Increment, condVal, thresholdVal,
 	)
 	return err
 }
@@ -735,6 +1075,78 @@ type CatalogImportPreview struct {
 	EquipmentMapped int
 	ProgramsNew     int
 	ProgramsMapped  int
+
+	// ProgramDiffs compares incoming prescribed sets against the existing
+	// sets of each program mapped to an existing template.
+	ProgramDiffs []CatalogProgramDiff
+
+	// Unresolved lists exercise references that block the import.
+	Unresolved []UnresolvedProgramExercises
+}
+
+// CatalogProgramDiff counts how an incoming program's prescribed sets differ
+// from those of the existing template it is mapped to. Sets are matched by
+// week, day, exercise, and set number.
+type CatalogProgramDiff struct {
+	ImportName string
+	MappedID   int64
+	MappedName string
+	Added      int
+	Removed    int
+	Changed    int
+	Unchanged  int
+}
+
+// HasChanges reports whether any prescribed set would differ.
+func (d CatalogProgramDiff) HasChanges() bool {
+	return d.Added > 0 || d.Removed > 0 || d.Changed > 0
+}
+
+// UnresolvedProgramExercises lists the exercises a program's prescribed sets
+// or progression rules reference that a catalog import cannot resolve.
+type UnresolvedProgramExercises struct {
+	Program   string
+	Exercises []string
+}
+
+// ValidateCatalogReferences checks every program the catalog import will
+// create for exercise references that resolve to neither an existing exercise
+// nor one declared in the import's exercises array. ExecuteCatalogImport
+// would otherwise drop those sets and rules silently.
+func ValidateCatalogReferences(ms *importers.MappingState) []UnresolvedProgramExercises {
+	if ms.Parsed == nil {
+		return nil
+	}
+
+	resolved := make(map[string]bool)
+	for _, m := range ms.Exercises {
+		if m.MappedID > 0 || (m.Create && findParsedExercise(ms.Parsed.Exercises, m.ImportName) != nil) {
+			resolved[strings.ToLower(m.ImportName)] = true
+		}
+	}
+
+	var unresolved []UnresolvedProgramExercises
+	for _, m := range ms.Programs {
+		if m.MappedID > 0 || !m.Create {
+			continue
+		}
+		for _, prog := range ms.Parsed.Programs {
+			if !strings.EqualFold(prog.Template.Name, m.ImportName) {
+				continue
+			}
+			var missing []string
+			for _, name := range importers.CollectProgramExerciseNames([]importers.ParsedProgram{prog}) {
+				if !resolved[strings.ToLower(name)] {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				unresolved = append(unresolved, UnresolvedProgramExercises{Program: prog.Template.Name, Exercises: missing})
+			}
+			break
+		}
+	}
+	return unresolved
 }
 
 // CatalogImportResult summarizes what was imported.
@@ -747,10 +1159,14 @@ type CatalogImportResult struct {
 	ProgressionRules    int
 	ExerciseEquipLinks  int
 	CreatedTemplateIDs  []int64 // template IDs created, for post-import exercise auto-assignment
+
+	// Warnings describes values the import dropped, one line per row.
+	Warnings []string
 }
 
-// BuildCatalogImportPreview generates a preview of a catalog import.
-func BuildCatalogImportPreview(ms *importers.MappingState) *CatalogImportPreview {
+// BuildCatalogImportPreview generates a preview of a catalog import,
+// including a prescribed-set diff for programs mapped to existing templates.
+func BuildCatalogImportPreview(db *sql.DB, ms *importers.MappingState) (*CatalogImportPreview, error) {
 	p := &CatalogImportPreview{}
 
 	for _, m := range ms.Exercises {
@@ -775,13 +1191,140 @@ func BuildCatalogImportPreview(ms *importers.MappingState) *CatalogImportPreview
 		}
 	}
 
-	return p
+	// Diff prescribed sets for programs mapped onto existing templates.
+	exerciseIDs := make(map[string]int64)
+	for _, m := range ms.Exercises {
+		if m.MappedID > 0 {
+			exerciseIDs[strings.ToLower(m.ImportName)] = m.MappedID
+		}
+	}
+	for _, m := range ms.Programs {
+		if m.MappedID == 0 || ms.Parsed == nil {
+			continue
+		}
+		var pt *importers.ParsedProgramTemplate
+		for i := range ms.Parsed.Programs {
+			if strings.EqualFold(ms.Parsed.Programs[i].Template.Name, m.ImportName) {
+				pt = &ms.Parsed.Programs[i].Template
+				break
+			}
+		}
+		if pt == nil {
+			continue
+		}
+		existing, err := ListPrescribedSets(db, m.MappedID)
+		if err != nil {
+			return nil, fmt.Errorf("models: catalog preview diff for %q: %w", m.ImportName, err)
+		}
+		diff := diffPrescribedSets(existing, pt.PrescribedSets, exerciseIDs)
+		diff.ImportName = m.ImportName
+		diff.MappedID = m.MappedID
+		diff.MappedName = m.MappedName
+		p.ProgramDiffs = append(p.ProgramDiffs, diff)
+	}
+
+	p.Unresolved = ValidateCatalogReferences(ms)
+
+	return p, nil
+}
+
+// prescribedSetKey identifies a prescribed set slot within a template.
+type prescribedSetKey struct {
+	week, day, setNumber int
+	exerciseID           int64
+}
+
+// diffPrescribedSets counts added, removed, changed, and unchanged sets
+// between an existing template and incoming parsed sets. Incoming sets whose
+// exercise is not mapped to an existing exercise always count as added.
+func diffPrescribedSets(existing []*PrescribedSet, incoming []importers.ParsedPrescribedSet, exerciseIDs map[string]int64) CatalogProgramDiff {
+	var d CatalogProgramDiff
+
+	current := make(map[prescribedSetKey]*PrescribedSet, len(existing))
+	for _, ps := range existing {
+		current[prescribedSetKey{ps.Week, ps.Day, ps.SetNumber, ps.ExerciseID}] = ps
+	}
+
+	seen := make(map[prescribedSetKey]bool, len(incoming))
+	for _, in := range incoming {
+		exID, ok := exerciseIDs[strings.ToLower(in.Exercise)]
+		if !ok {
+			d.Added++
+			continue
+		}
+		key := prescribedSetKey{in.Week, in.Day, in.SetNumber, exID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ps, ok := current[key]
+		switch {
+		case !ok:
+			d.Added++
+		case prescribedSetEqual(ps, in):
+			d.Unchanged++
+		default:
+			d.Changed++
+		}
+	}
+
+	for key := range current {
+		if !seen[key] {
+			d.Removed++
+		}
+	}
+
+	return d
+}
+
+// prescribedSetEqual reports whether an existing prescribed set matches an
+// incoming parsed set on every programmed field.
+func prescribedSetEqual(ps *PrescribedSet, in importers.ParsedPrescribedSet) bool {
+	repType := in.RepType
+	if repType == "" {
+		repType = "reps"
+	}
+	var repMax *int
+	if in.Reps != nil {
+		repMax = in.RepMax
+	}
+	var notes string
+	if in.Notes != nil {
+		notes = *in.Notes
+	}
+	return ps.RepType == repType &&
+		nullIntEqual(ps.Reps, in.Reps) &&
+		nullIntEqual(ps.RepMax, repMax) &&
+		nullFloatEqual(ps.Percentage, in.Percentage) &&
+		nullFloatEqual(ps.AbsoluteWeight, in.AbsoluteWeight) &&
+		nullFloatEqual(ps.TargetRPE, in.TargetRPE) &&
+		nullIntEqual(ps.RestSeconds, in.RestSeconds) &&
+		ps.Notes.String == notes
+}
+
+func nullIntEqual(n sql.NullInt64, v *int) bool {
+	if v == nil {
+		return !n.Valid
+	}
+	return n.Valid && n.Int64 == int64(*v)
+}
+
+func nullFloatEqual(n sql.NullFloat64, v *float64) bool {
+	if v == nil {
+		return !n.Valid
+	}
+	return n.Valid && n.Float64 == *v
 }
 
 // ExecuteCatalogImport creates equipment, exercises, and program templates
 // from a parsed catalog file. athleteID scopes new program templates: nil =
 // global, non-nil = athlete-specific (e.g. AI-generated).
 func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int64) (*CatalogImportResult, error) {
+	if unresolved := ValidateCatalogReferences(ms); len(unresolved) > 0 {
+		return nil, fmt.Errorf("models: program %q references unknown exercises %s: %w",
+			unresolved[0].Program, strings.Join(unresolved[0].Exercises, ", "), ErrInvalidInput)
+	}
+
 	pf := ms.Parsed
 	result := &CatalogImportResult{}
 
@@ -801,16 +1344,8 @@ func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int
 		if !m.Create {
 			continue
 		}
-		desc := ""
-		for _, pe := range pf.Equipment {
-			if strings.EqualFold(pe.Name, m.ImportName) {
-				if pe.Description != nil {
-					desc = *pe.Description
-				}
-				break
-			}
-		}
-		id, err := insertEquipment(tx, m.ImportName, desc)
+		pe := findParsedEquipment(pf.Equipment, m.ImportName)
+		id, err := insertEquipment(tx, m.ImportName, pe)
 		if err != nil {
 			if isUniqueViolation(err) {
 				continue
@@ -838,12 +1373,16 @@ func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int
 		}
 
 		tier := ""
+		muscleGroup := ""
 		formNotes := ""
 		demoURL := ""
 		restSeconds := 0
 		if pe.Tier != nil {
 			tier = *pe.Tier
 		}
+		if pe.MuscleGroup != nil {
+			muscleGroup = *pe.MuscleGroup
+		}
 		if pe.FormNotes != nil {
 			formNotes = *pe.FormNotes
 		}
@@ -854,7 +1393,7 @@ func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int
 			restSeconds = *pe.RestSeconds
 		}
 
-		id, err := insertExercise(tx, pe.Name, tier, formNotes, demoURL, restSeconds, pe.Featured)
+		id, err := insertExercise(tx, pe.Name, tier, muscleGroup, formNotes, demoURL, restSeconds, pe.Featured, pe.Unilateral)
 		if err != nil {
 			if isUniqueViolation(err) {
 				continue
@@ -923,6 +1462,10 @@ func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int
 			if !ok {
 				continue
 			}
+			var warning string
+			if ps.TargetRPE, warning = importers.CheckTargetRPE(ps); warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
 			if err := insertPrescribedSet(tx, templateID, exID, ps); err != nil {
 				return nil, fmt.Errorf("models: catalog import prescribed set: %w", err)
 			}
@@ -935,11 +1478,15 @@ func ExecuteCatalogImport(db *sql.DB, ms *importers.MappingState, athleteID *int
 			if !ok {
 				continue
 			}
-			if err := insertProgressionRule(tx, templateID, exID, pr.Increment); err != nil {
+			if err := insertProgressionRule(tx, templateID, exID, pr); err != nil {
 				return nil, fmt.Errorf("models: catalog import progression rule: %w", err)
 			}
 			result.ProgressionRules++
 		}
+
+		if err := setProgramTemplateHash(tx, templateID, *pt); err != nil {
+			return nil, fmt.Errorf("models: catalog import hash program template %q: %w", pt.Name, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
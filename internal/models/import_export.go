@@ -1,13 +1,18 @@
 package models
 
 import (
+	"archive/zip"
 	"database/sql"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/carpenike/replog/internal/importers"
 )
 
 // --- Export Types ---
@@ -27,6 +32,7 @@ type ExportJSON struct {
 	BodyWeights      []ExportBodyWeight     `json:"body_weights"`
 	Workouts         []ExportWorkout        `json:"workouts"`
 	Programs         []ExportProgram        `json:"programs"`
+	AccessoryPlans   []ExportAccessoryPlan  `json:"accessory_plans"`
 }
 
 // ExportAthlete is the athlete profile in a JSON export.
@@ -43,18 +49,22 @@ type ExportAthlete struct {
 
 // ExportEquipment is an equipment item in a JSON export.
 type ExportEquipment struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Quantity    int      `json:"quantity"`
+	UnitWeight  *float64 `json:"unit_weight"`
 }
 
 // ExportExercise is an exercise in a JSON export, including equipment deps.
 type ExportExercise struct {
 	Name        string                    `json:"name"`
 	Tier        *string                   `json:"tier"`
+	MuscleGroup *string                   `json:"muscle_group"`
 	FormNotes   *string                   `json:"form_notes"`
 	DemoURL     *string                   `json:"demo_url"`
 	RestSeconds *int                      `json:"rest_seconds"`
 	Featured    bool                      `json:"featured"`
+	Unilateral  bool                      `json:"unilateral"`
 	Equipment   []ExportExerciseEquipment `json:"equipment"`
 }
 
@@ -92,6 +102,7 @@ type ExportBodyWeight struct {
 type ExportWorkout struct {
 	Date   string              `json:"date"`
 	Notes  *string             `json:"notes"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
 	Review *ExportReview       `json:"review"`
 	Sets   []ExportWorkoutSet  `json:"sets"`
 }
@@ -143,21 +154,54 @@ type ExportPrescribedSet struct {
 	Day            int      `json:"day"`
 	SetNumber      int      `json:"set_number"`
 	Reps           *int     `json:"reps"`
+	RepMax         *int     `json:"rep_max,omitempty"`
 	RepType        string   `json:"rep_type"`
 	Percentage     *float64 `json:"percentage"`
 	AbsoluteWeight *float64 `json:"absolute_weight"`
+	TargetRPE      *float64 `json:"target_rpe,omitempty"`
+	RestSeconds    *int     `json:"rest_seconds,omitempty"`
 	SortOrder      int      `json:"sort_order"`
 	Notes          *string  `json:"notes"`
 }
 
+// ExportAccessoryPlan is a planned accessory exercise in a JSON export.
+type ExportAccessoryPlan struct {
+	Day          int      `json:"day"`
+	Exercise     string   `json:"exercise"`
+	TargetSets   *int     `json:"target_sets"`
+	TargetRepMin *int     `json:"target_rep_min"`
+	TargetRepMax *int     `json:"target_rep_max"`
+	TargetWeight *float64 `json:"target_weight"`
+	Notes        *string  `json:"notes"`
+	SortOrder    int      `json:"sort_order"`
+	Superset     bool     `json:"superset"`
+	Active       bool     `json:"active"`
+}
+
 // ExportProgressionRule is a progression rule in a JSON export.
 type ExportProgressionRule struct {
 	Exercise  string  `json:"exercise"`
 	Increment float64 `json:"increment"`
+	Condition *string `json:"condition,omitempty"`
+	Threshold *int    `json:"threshold,omitempty"`
 }
 
 // --- Export Functions ---
 
+// exportProgressionRule converts a progression rule to its export form.
+func exportProgressionRule(r *ProgressionRule) ExportProgressionRule {
+	epr := ExportProgressionRule{
+		Exercise:  r.ExerciseName,
+		Increment: r.Increment,
+		Condition: nullStringPtr(r.Condition),
+	}
+	if r.Threshold.Valid {
+		t := int(r.Threshold.Int64)
+		epr.Threshold = &t
+	}
+	return epr
+}
+
 // BuildExportJSON gathers all data for an athlete and returns the full export struct.
 func BuildExportJSON(db *sql.DB, athleteID int64) (*ExportJSON, error) {
 	athlete, err := GetAthleteByID(db, athleteID)
@@ -260,9 +304,62 @@ func BuildExportJSON(db *sql.DB, athleteID int64) (*ExportJSON, error) {
 		return nil, err
 	}
 
+	// Accessory plans (active + inactive).
+	export.AccessoryPlans, err = exportAccessoryPlans(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+
 	return export, nil
 }
 
+// ConvertWeights converts every weight in the export from the stored lbs to
+// unit and sets WeightUnit accordingly. Converted values are rounded to two
+// decimals so a kg export stays readable.
+func (e *ExportJSON) ConvertWeights(unit string) {
+	if unit == e.WeightUnit {
+		return
+	}
+	conv := func(v float64) float64 {
+		return math.Round(ConvertWeight(v, e.WeightUnit, unit)*100) / 100
+	}
+	convPtr := func(p *float64) *float64 {
+		if p == nil {
+			return nil
+		}
+		v := conv(*p)
+		return &v
+	}
+
+	for i := range e.Equipment {
+		e.Equipment[i].UnitWeight = convPtr(e.Equipment[i].UnitWeight)
+	}
+	for i := range e.TrainingMaxes {
+		e.TrainingMaxes[i].Weight = conv(e.TrainingMaxes[i].Weight)
+	}
+	for i := range e.BodyWeights {
+		e.BodyWeights[i].Weight = conv(e.BodyWeights[i].Weight)
+	}
+	for i := range e.Workouts {
+		for j := range e.Workouts[i].Sets {
+			e.Workouts[i].Sets[j].Weight = convPtr(e.Workouts[i].Sets[j].Weight)
+		}
+	}
+	for i := range e.Programs {
+		tmpl := &e.Programs[i].Template
+		for j := range tmpl.PrescribedSets {
+			tmpl.PrescribedSets[j].AbsoluteWeight = convPtr(tmpl.PrescribedSets[j].AbsoluteWeight)
+		}
+		for j := range tmpl.ProgressionRules {
+			tmpl.ProgressionRules[j].Increment = conv(tmpl.ProgressionRules[j].Increment)
+		}
+	}
+	for i := range e.AccessoryPlans {
+		e.AccessoryPlans[i].TargetWeight = convPtr(e.AccessoryPlans[i].TargetWeight)
+	}
+	e.WeightUnit = unit
+}
+
 // WriteExportJSON serializes the export to JSON and writes it.
 func WriteExportJSON(w io.Writer, export *ExportJSON) error {
 	enc := json.NewEncoder(w)
@@ -270,8 +367,9 @@ func WriteExportJSON(w io.Writer, export *ExportJSON) error {
 	return enc.Encode(export)
 }
 
-// WriteExportStrongCSV writes workouts as a Strong-compatible CSV.
-func WriteExportStrongCSV(w io.Writer, db *sql.DB, athleteID int64) error {
+// WriteExportStrongCSV writes workouts as a Strong-compatible CSV with weights
+// converted from the stored lbs to unit (Strong CSV has no unit column).
+func WriteExportStrongCSV(w io.Writer, db *sql.DB, athleteID int64, unit string) error {
 	athlete, err := GetAthleteByID(db, athleteID)
 	if err != nil {
 		return fmt.Errorf("models: export csv athlete %d: %w", athleteID, err)
@@ -288,83 +386,219 @@ func WriteExportStrongCSV(w io.Writer, db *sql.DB, athleteID int64) error {
 		return fmt.Errorf("models: write csv header: %w", err)
 	}
 
-	// Iterate all workouts.
-	offset := 0
-	for {
-		page, err := ListWorkouts(db, athleteID, offset)
+	workouts, err := ListAllWorkouts(db, athleteID)
+	if err != nil {
+		return fmt.Errorf("models: list workouts for csv: %w", err)
+	}
+
+	for _, wo := range workouts {
+		groups, err := ListSetsByWorkout(db, wo.ID)
 		if err != nil {
-			return fmt.Errorf("models: list workouts for csv: %w", err)
+			return fmt.Errorf("models: list sets for workout %d: %w", wo.ID, err)
 		}
 
-		for _, wo := range page.Workouts {
-			groups, err := ListSetsByWorkout(db, wo.ID)
-			if err != nil {
-				return fmt.Errorf("models: list sets for workout %d: %w", wo.ID, err)
-			}
+		workoutName := athlete.Name + " — " + wo.Date
+		workoutNotes := ""
+		if wo.Notes.Valid {
+			workoutNotes = wo.Notes.String
+		}
+		duration := ""
+		if wo.DurationMinutes.Valid {
+			duration = FormatDuration(int(wo.DurationMinutes.Int64))
+		}
 
-			workoutName := athlete.Name + " — " + wo.Date
-			workoutNotes := ""
-			if wo.Notes.Valid {
-				workoutNotes = wo.Notes.String
-			}
+		for _, group := range groups {
+			for _, set := range group.Sets {
+				reps := ""
+				distance := ""
+				seconds := ""
+				switch set.RepType {
+				case "seconds":
+					seconds = strconv.Itoa(set.Reps)
+				case "distance":
+					// RepLog stores yards; Strong's Distance column is meters.
+					meters := math.Round(float64(set.Reps)*importers.MetersPerYard*100) / 100
+					distance = strconv.FormatFloat(meters, 'f', -1, 64)
+				default:
+					reps = strconv.Itoa(set.Reps)
+				}
+
+				weight := ""
+				if set.Weight.Valid {
+					weight = strconv.FormatFloat(math.Round(ConvertWeight(set.Weight.Float64, "lbs", unit)*100)/100, 'f', -1, 64)
+				}
 
-			for _, group := range groups {
-				for _, set := range group.Sets {
-					reps := ""
-					seconds := ""
-					if set.RepType == "seconds" {
-						seconds = strconv.Itoa(set.Reps)
-					} else {
-						reps = strconv.Itoa(set.Reps)
-					}
-
-					weight := ""
-					if set.Weight.Valid {
-						weight = strconv.FormatFloat(set.Weight.Float64, 'f', -1, 64)
-					}
-
-					rpe := ""
-					if set.RPE.Valid {
-						rpe = strconv.FormatFloat(set.RPE.Float64, 'f', -1, 64)
-					}
-
-					notes := ""
-					if set.Notes.Valid {
-						notes = set.Notes.String
-					}
-
-					if err := cw.Write([]string{
-						wo.Date + " 00:00:00",
-						workoutName,
-						"",
-						group.ExerciseName,
-						strconv.Itoa(set.SetNumber),
-						weight,
-						reps,
-						"",
-						seconds,
-						notes,
-						workoutNotes,
-						rpe,
-					}); err != nil {
-						return fmt.Errorf("models: write csv row: %w", err)
-					}
-
-					// Only emit workout notes on the first row.
-					workoutNotes = ""
+				rpe := ""
+				if set.RPE.Valid {
+					rpe = strconv.FormatFloat(set.RPE.Float64, 'f', -1, 64)
 				}
+
+				notes := ""
+				if set.Notes.Valid {
+					notes = set.Notes.String
+				}
+				// Strong has no each-side column; mark it in Notes so
+				// re-import can restore the rep type.
+				if set.RepType == "each_side" {
+					notes = strings.TrimSpace(notes + " " + importers.StrongEachSideMarker)
+				}
+
+				if err := cw.Write([]string{
+					wo.Date + " 00:00:00",
+					workoutName,
+					duration,
+					group.ExerciseName,
+					strconv.Itoa(set.SetNumber),
+					weight,
+					reps,
+					distance,
+					seconds,
+					notes,
+					workoutNotes,
+					rpe,
+				}); err != nil {
+					return fmt.Errorf("models: write csv row: %w", err)
+				}
+
+				// Only emit workout notes on the first row.
+				workoutNotes = ""
 			}
 		}
+	}
 
-		if !page.HasMore {
-			break
+	return nil
+}
+
+// WriteExportPerExerciseZIP writes a zip archive to w containing one CSV per
+// exercise the athlete has logged, with columns Date, Set, Reps, Weight, and
+// RPE (most recent workout first). Weights are written in unit. Each CSV is
+// streamed a page of exercise history at a time so large histories are never
+// held in memory.
+func WriteExportPerExerciseZIP(w io.Writer, db *sql.DB, athleteID int64, unit string) error {
+	if _, err := GetAthleteByID(db, athleteID); err != nil {
+		return fmt.Errorf("models: export zip athlete %d: %w", athleteID, err)
+	}
+
+	rows, err := db.Query(`
+		SELECT DISTINCT e.id, e.name
+		FROM workout_sets ws
+		JOIN workouts w ON w.id = ws.workout_id
+		JOIN exercises e ON e.id = ws.exercise_id
+		WHERE w.athlete_id = ?
+		ORDER BY e.name COLLATE NOCASE`, athleteID)
+	if err != nil {
+		return fmt.Errorf("models: list exercises for zip export: %w", err)
+	}
+	type exerciseRef struct {
+		ID   int64
+		Name string
+	}
+	var exercises []exerciseRef
+	for rows.Next() {
+		var ref exerciseRef
+		if err := rows.Scan(&ref.ID, &ref.Name); err != nil {
+			rows.Close()
+			return fmt.Errorf("models: scan exercise for zip export: %w", err)
 		}
-		offset += WorkoutPageSize
+		exercises = append(exercises, ref)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
+	zw := zip.NewWriter(w)
+	// Every entry name handed out, lowercased so names differing only in
+	// case don't clash when extracted on a case-insensitive filesystem.
+	used := make(map[string]bool)
+	for _, ex := range exercises {
+		base := SanitizeFilename(ex.Name)
+		if base == "" {
+			base = "exercise"
+		}
+		name := base + ".csv"
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d.csv", base, n)
+		}
+		used[strings.ToLower(name)] = true
+
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("models: create zip entry %q: %w", name, err)
+		}
+		if err := writeExerciseHistoryCSV(fw, db, athleteID, ex.ID, unit); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("models: close export zip: %w", err)
+	}
 	return nil
 }
 
+// writeExerciseHistoryCSV pages through an athlete's history for one exercise
+// and writes each set as a CSV row.
+func writeExerciseHistoryCSV(w io.Writer, db *sql.DB, athleteID, exerciseID int64, unit string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Date", "Set", "Reps", "Weight (" + unit + ")", "RPE"}); err != nil {
+		return fmt.Errorf("models: write exercise csv header: %w", err)
+	}
+
+	for offset := 0; ; offset += ExerciseHistoryPageSize {
+		page, err := ListExerciseHistory(db, athleteID, exerciseID, offset)
+		if err != nil {
+			return err
+		}
+		for _, day := range page.Days {
+			for _, set := range day.Sets {
+				weight := ""
+				if set.Weight.Valid {
+					weight = strconv.FormatFloat(math.Round(ConvertWeight(set.Weight.Float64, "lbs", unit)*100)/100, 'f', -1, 64)
+				}
+				rpe := ""
+				if set.RPE.Valid {
+					rpe = strconv.FormatFloat(set.RPE.Float64, 'f', -1, 64)
+				}
+				if err := cw.Write([]string{
+					normalizeDate(day.WorkoutDate),
+					strconv.Itoa(set.SetNumber),
+					strconv.Itoa(set.Reps),
+					weight,
+					rpe,
+				}); err != nil {
+					return fmt.Errorf("models: write exercise csv row: %w", err)
+				}
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("models: flush exercise csv: %w", err)
+		}
+		if !page.HasMore {
+			return nil
+		}
+	}
+}
+
+// SanitizeFilename lowercases name and reduces it to letters, digits, and
+// dashes so it is safe to use in a download or archive entry name.
+func SanitizeFilename(name string) string {
+	r := strings.NewReplacer(
+		" ", "-", "/", "-", "\\", "-",
+		".", "", ",", "", "'", "", "\"", "",
+	)
+	s := r.Replace(strings.ToLower(name))
+	// Remove any remaining non-alphanumeric chars except dash.
+	var clean []byte
+	for _, c := range []byte(s) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' {
+			clean = append(clean, c)
+		}
+	}
+	return string(clean)
+}
+
 // --- Export Helpers ---
 
 func exportEquipment(db *sql.DB, athleteID int64) (map[int64]ExportEquipment, error) {
@@ -389,10 +623,7 @@ func exportEquipment(db *sql.DB, athleteID int64) (map[int64]ExportEquipment, er
 
 	for _, ae := range aeList {
 		if eq, ok := eqByID[ae.EquipmentID]; ok {
-			result[eq.ID] = ExportEquipment{
-				Name:        eq.Name,
-				Description: nullStringPtr(eq.Description),
-			}
+			result[eq.ID] = exportEquipmentItem(eq)
 		}
 	}
 
@@ -414,10 +645,7 @@ func exportEquipment(db *sql.DB, athleteID int64) (map[int64]ExportEquipment, er
 		for _, ee := range eeList {
 			if _, exists := result[ee.EquipmentID]; !exists {
 				if eq, ok := eqByID[ee.EquipmentID]; ok {
-					result[eq.ID] = ExportEquipment{
-						Name:        eq.Name,
-						Description: nullStringPtr(eq.Description),
-					}
+					result[eq.ID] = exportEquipmentItem(eq)
 				}
 			}
 		}
@@ -449,6 +677,12 @@ func exportEquipment(db *sql.DB, athleteID int64) (map[int64]ExportEquipment, er
 	}
 	rows.Close()
 
+	accessoryExIDs, err := accessoryExerciseIDs(db, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: export equipment (accessory exercises): %w", err)
+	}
+	workoutExIDs = append(workoutExIDs, accessoryExIDs...)
+
 	for _, exID := range workoutExIDs {
 		eeList, err := ListExerciseEquipment(db, exID)
 		if err != nil {
@@ -457,10 +691,7 @@ func exportEquipment(db *sql.DB, athleteID int64) (map[int64]ExportEquipment, er
 		for _, ee := range eeList {
 			if _, exists := result[ee.EquipmentID]; !exists {
 				if eq, ok := eqByID[ee.EquipmentID]; ok {
-					result[eq.ID] = ExportEquipment{
-						Name:        eq.Name,
-						Description: nullStringPtr(eq.Description),
-					}
+					result[eq.ID] = exportEquipmentItem(eq)
 				}
 			}
 		}
@@ -511,6 +742,13 @@ func exportExercises(db *sql.DB, athleteID int64, equipmentMap map[int64]ExportE
 		exerciseIDs = append(exerciseIDs, a.ExerciseID)
 	}
 
+	// Also include exercises from accessory plans.
+	accessoryExIDs, err := accessoryExerciseIDs(db, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: export exercises (accessory plans): %w", err)
+	}
+	exerciseIDs = append(exerciseIDs, accessoryExIDs...)
+
 	// Deduplicate.
 	seen := make(map[int64]bool)
 	for _, id := range exerciseIDs {
@@ -525,11 +763,13 @@ func exportExercises(db *sql.DB, athleteID int64, equipmentMap map[int64]ExportE
 		}
 
 		ee := ExportExercise{
-			Name:      ex.Name,
-			Tier:      nullStringPtr(ex.Tier),
-			FormNotes: nullStringPtr(ex.FormNotes),
-			DemoURL:   nullStringPtr(ex.DemoURL),
-			Featured:  ex.Featured,
+			Name:        ex.Name,
+			Tier:        nullStringPtr(ex.Tier),
+			MuscleGroup: nullStringPtr(ex.MuscleGroup),
+			FormNotes:   nullStringPtr(ex.FormNotes),
+			DemoURL:     nullStringPtr(ex.DemoURL),
+			Featured:    ex.Featured,
+			Unilateral:  ex.Unilateral,
 		}
 		if ex.RestSeconds.Valid {
 			rs := int(ex.RestSeconds.Int64)
@@ -554,6 +794,56 @@ func exportExercises(db *sql.DB, athleteID int64, equipmentMap map[int64]ExportE
 	return result, nil
 }
 
+// accessoryExerciseIDs returns the distinct exercise IDs used by an athlete's
+// accessory plans.
+func accessoryExerciseIDs(db *sql.DB, athleteID int64) ([]int64, error) {
+	plans, err := ListAllAccessoryPlans(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for _, ap := range plans {
+		ids = append(ids, ap.ExerciseID)
+	}
+	return ids, nil
+}
+
+func exportAccessoryPlans(db *sql.DB, athleteID int64) ([]ExportAccessoryPlan, error) {
+	plans, err := ListAllAccessoryPlans(db, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: export accessory plans: %w", err)
+	}
+	var result []ExportAccessoryPlan
+	for _, ap := range plans {
+		ea := ExportAccessoryPlan{
+			Day:       ap.Day,
+			Exercise:  ap.ExerciseName,
+			Notes:     nullStringPtr(ap.Notes),
+			SortOrder: ap.SortOrder,
+			Superset:  ap.Superset,
+			Active:    ap.Active,
+		}
+		if ap.TargetSets.Valid {
+			v := int(ap.TargetSets.Int64)
+			ea.TargetSets = &v
+		}
+		if ap.TargetRepMin.Valid {
+			v := int(ap.TargetRepMin.Int64)
+			ea.TargetRepMin = &v
+		}
+		if ap.TargetRepMax.Valid {
+			v := int(ap.TargetRepMax.Int64)
+			ea.TargetRepMax = &v
+		}
+		if ap.TargetWeight.Valid {
+			v := ap.TargetWeight.Float64
+			ea.TargetWeight = &v
+		}
+		result = append(result, ea)
+	}
+	return result, nil
+}
+
 func exportBodyWeights(db *sql.DB, athleteID int64) ([]ExportBodyWeight, error) {
 	var result []ExportBodyWeight
 	offset := 0
@@ -578,65 +868,70 @@ func exportBodyWeights(db *sql.DB, athleteID int64) ([]ExportBodyWeight, error)
 }
 
 func exportWorkouts(db *sql.DB, athleteID int64) ([]ExportWorkout, error) {
-	var result []ExportWorkout
-	offset := 0
-	for {
-		page, err := ListWorkouts(db, athleteID, offset)
+	workouts, err := ListAllWorkouts(db, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: export workouts: %w", err)
+	}
+
+	result := make([]ExportWorkout, 0, len(workouts))
+	for _, wo := range workouts {
+		ew, err := BuildExportWorkout(db, wo)
 		if err != nil {
-			return nil, fmt.Errorf("models: export workouts: %w", err)
+			return nil, err
 		}
+		result = append(result, *ew)
+	}
+	return result, nil
+}
 
-		for _, wo := range page.Workouts {
-			ew := ExportWorkout{
-				Date:  wo.Date,
-				Notes: nullStringPtr(wo.Notes),
-			}
+// BuildExportWorkout converts a workout, its review, and its sets into the
+// export representation. Used by the full export and the per-workout JSON API.
+func BuildExportWorkout(db *sql.DB, wo *Workout) (*ExportWorkout, error) {
+	ew := &ExportWorkout{
+		Date:  wo.Date,
+		Notes: nullStringPtr(wo.Notes),
+	}
+	if wo.DurationMinutes.Valid {
+		d := int(wo.DurationMinutes.Int64)
+		ew.DurationMinutes = &d
+	}
 
-			// Review.
-			rev, err := GetWorkoutReviewByWorkoutID(db, wo.ID)
-			if err == nil && rev != nil {
-				ew.Review = &ExportReview{
-					Status: rev.Status,
-					Notes:  nullStringPtr(rev.Notes),
-				}
-			}
+	// Review.
+	rev, err := GetWorkoutReviewByWorkoutID(db, wo.ID)
+	if err == nil && rev != nil {
+		ew.Review = &ExportReview{
+			Status: rev.Status,
+			Notes:  nullStringPtr(rev.Notes),
+		}
+	}
 
-			// Sets.
-			groups, err := ListSetsByWorkout(db, wo.ID)
-			if err != nil {
-				return nil, fmt.Errorf("models: export sets for workout %d: %w", wo.ID, err)
+	// Sets.
+	groups, err := ListSetsByWorkout(db, wo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("models: export sets for workout %d: %w", wo.ID, err)
+	}
+	for _, g := range groups {
+		for _, s := range g.Sets {
+			es := ExportWorkoutSet{
+				Exercise:  g.ExerciseName,
+				SetNumber: s.SetNumber,
+				Reps:      s.Reps,
+				RepType:   s.RepType,
+				Category:  s.Category,
 			}
-			for _, g := range groups {
-				for _, s := range g.Sets {
-					es := ExportWorkoutSet{
-						Exercise:  g.ExerciseName,
-						SetNumber: s.SetNumber,
-						Reps:      s.Reps,
-						RepType:   s.RepType,
-						Category:  s.Category,
-					}
-					if s.Weight.Valid {
-						w := s.Weight.Float64
-						es.Weight = &w
-					}
-					if s.RPE.Valid {
-						r := s.RPE.Float64
-						es.RPE = &r
-					}
-					es.Notes = nullStringPtr(s.Notes)
-					ew.Sets = append(ew.Sets, es)
-				}
+			if s.Weight.Valid {
+				w := s.Weight.Float64
+				es.Weight = &w
 			}
-
-			result = append(result, ew)
-		}
-
-		if !page.HasMore {
-			break
+			if s.RPE.Valid {
+				r := s.RPE.Float64
+				es.RPE = &r
+			}
+			es.Notes = nullStringPtr(s.Notes)
+			ew.Sets = append(ew.Sets, es)
 		}
-		offset += WorkoutPageSize
 	}
-	return result, nil
+	return ew, nil
 }
 
 func exportPrograms(db *sql.DB, athleteID int64) ([]ExportProgram, error) {
@@ -725,6 +1020,10 @@ func exportPrograms(db *sql.DB, athleteID int64) ([]ExportProgram, error) {
 				r := int(ps.Reps.Int64)
 				eps.Reps = &r
 			}
+			if ps.RepMax.Valid {
+				m := int(ps.RepMax.Int64)
+				eps.RepMax = &m
+			}
 			if ps.Percentage.Valid {
 				p := ps.Percentage.Float64
 				eps.Percentage = &p
@@ -733,6 +1032,14 @@ func exportPrograms(db *sql.DB, athleteID int64) ([]ExportProgram, error) {
 				w := ps.AbsoluteWeight.Float64
 				eps.AbsoluteWeight = &w
 			}
+			if ps.TargetRPE.Valid {
+				rpe := ps.TargetRPE.Float64
+				eps.TargetRPE = &rpe
+			}
+			if ps.RestSeconds.Valid {
+				rest := int(ps.RestSeconds.Int64)
+				eps.RestSeconds = &rest
+			}
 			eps.Notes = nullStringPtr(ps.Notes)
 			ep.Template.PrescribedSets = append(ep.Template.PrescribedSets, eps)
 		}
@@ -743,10 +1050,7 @@ func exportPrograms(db *sql.DB, athleteID int64) ([]ExportProgram, error) {
 			return nil, fmt.Errorf("models: export progression rules for template %d: %w", pr.templateID, err)
 		}
 		for _, r := range rules {
-			ep.Template.ProgressionRules = append(ep.Template.ProgressionRules, ExportProgressionRule{
-				Exercise:  r.ExerciseName,
-				Increment: r.Increment,
-			})
+			ep.Template.ProgressionRules = append(ep.Template.ProgressionRules, exportProgressionRule(r))
 		}
 
 		result = append(result, ep)
@@ -772,6 +1076,19 @@ func assignmentToExport(a *AthleteExercise, active bool) ExportAssignment {
 	return ea
 }
 
+func exportEquipmentItem(eq *Equipment) ExportEquipment {
+	ee := ExportEquipment{
+		Name:        eq.Name,
+		Description: nullStringPtr(eq.Description),
+		Quantity:    eq.Quantity,
+	}
+	if eq.UnitWeight.Valid {
+		w := eq.UnitWeight.Float64
+		ee.UnitWeight = &w
+	}
+	return ee
+}
+
 func nullStringPtr(ns sql.NullString) *string {
 	if ns.Valid {
 		return &ns.String
@@ -810,24 +1127,23 @@ func BuildCatalogExportJSON(db *sql.DB) (*CatalogJSON, error) {
 		return nil, fmt.Errorf("models: catalog export equipment: %w", err)
 	}
 	for _, eq := range allEquipment {
-		catalog.Equipment = append(catalog.Equipment, ExportEquipment{
-			Name:        eq.Name,
-			Description: nullStringPtr(eq.Description),
-		})
+		catalog.Equipment = append(catalog.Equipment, exportEquipmentItem(eq))
 	}
 
-	// Exercises — all, with equipment dependencies.
-	allExercises, err := ListExercises(db, "")
+	// Exercises — all, including archived, with equipment dependencies.
+	allExercises, err := ListExercises(db, ExerciseFilter{IncludeArchived: true})
 	if err != nil {
 		return nil, fmt.Errorf("models: catalog export exercises: %w", err)
 	}
 	for _, ex := range allExercises {
 		ee := ExportExercise{
-			Name:      ex.Name,
-			Tier:      nullStringPtr(ex.Tier),
-			FormNotes: nullStringPtr(ex.FormNotes),
-			DemoURL:   nullStringPtr(ex.DemoURL),
-			Featured:  ex.Featured,
+			Name:        ex.Name,
+			Tier:        nullStringPtr(ex.Tier),
+			MuscleGroup: nullStringPtr(ex.MuscleGroup),
+			FormNotes:   nullStringPtr(ex.FormNotes),
+			DemoURL:     nullStringPtr(ex.DemoURL),
+			Featured:    ex.Featured,
+			Unilateral:  ex.Unilateral,
 		}
 		if ex.RestSeconds.Valid {
 			rs := int(ex.RestSeconds.Int64)
@@ -878,6 +1194,10 @@ func BuildCatalogExportJSON(db *sql.DB) (*CatalogJSON, error) {
 				r := int(ps.Reps.Int64)
 				eps.Reps = &r
 			}
+			if ps.RepMax.Valid {
+				m := int(ps.RepMax.Int64)
+				eps.RepMax = &m
+			}
 			if ps.Percentage.Valid {
 				p := ps.Percentage.Float64
 				eps.Percentage = &p
@@ -886,6 +1206,14 @@ func BuildCatalogExportJSON(db *sql.DB) (*CatalogJSON, error) {
 				w := ps.AbsoluteWeight.Float64
 				eps.AbsoluteWeight = &w
 			}
+			if ps.TargetRPE.Valid {
+				rpe := ps.TargetRPE.Float64
+				eps.TargetRPE = &rpe
+			}
+			if ps.RestSeconds.Valid {
+				rest := int(ps.RestSeconds.Int64)
+				eps.RestSeconds = &rest
+			}
 			eps.Notes = nullStringPtr(ps.Notes)
 			ept.PrescribedSets = append(ept.PrescribedSets, eps)
 		}
@@ -895,10 +1223,7 @@ func BuildCatalogExportJSON(db *sql.DB) (*CatalogJSON, error) {
 			return nil, fmt.Errorf("models: catalog export progression rules for template %d: %w", pt.ID, err)
 		}
 		for _, r := range rules {
-			ept.ProgressionRules = append(ept.ProgressionRules, ExportProgressionRule{
-				Exercise:  r.ExerciseName,
-				Increment: r.Increment,
-			})
+			ept.ProgressionRules = append(ept.ProgressionRules, exportProgressionRule(r))
 		}
 
 		catalog.Programs = append(catalog.Programs, ept)
@@ -936,24 +1261,25 @@ type ValidationWarning struct {
 }
 
 type ImportPreview struct {
-	WorkoutCount     int
-	SetCount         int
-	ExercisesNew     int
-	ExercisesMapped  int
-	ExerciseCount    int      // ExercisesNew + ExercisesMapped (for template)
-	EquipmentNew     int
-	EquipmentMapped  int
-	EquipmentCount   int      // EquipmentNew + EquipmentMapped (for template)
-	ProgramsNew      int
-	ProgramsMapped   int
-	ProgramCount     int      // ProgramsNew + ProgramsMapped (for template)
-	ConflictDates    []string // dates that already have workouts
-	AssignmentCount  int
-	TrainingMaxCount int
-	BodyWeightCount  int
-	ReviewCount      int
-	DateRange        string // "YYYY-MM-DD to YYYY-MM-DD"
-	Warnings         []ValidationWarning
+	WorkoutCount            int
+	SetCount                int
+	ExercisesNew            int
+	ExercisesMapped         int
+	ExerciseCount           int // ExercisesNew + ExercisesMapped (for template)
+	EquipmentNew            int
+	EquipmentMapped         int
+	EquipmentCount          int // EquipmentNew + EquipmentMapped (for template)
+	ProgramsNew             int
+	ProgramsMapped          int
+	ProgramCount            int      // ProgramsNew + ProgramsMapped (for template)
+	ConflictDates           []string // dates that already have workouts
+	AssignmentCount         int
+	TrainingMaxCount        int
+	BodyWeightCount         int
+	BodyWeightConflictDates []string // dates that already have a body weight entry
+	ReviewCount             int
+	DateRange               string // "YYYY-MM-DD to YYYY-MM-DD"
+	Warnings                []ValidationWarning
 }
 
 // ImportResult summarizes what was imported after execution.
@@ -970,8 +1296,17 @@ type ImportResult struct {
 	TrainingMaxesSkipped int
 	BodyWeightsCreated   int
 	BodyWeightsSkipped   int
+	AccessoriesCreated   int
+	AccessoriesSkipped   int
 	ReviewsCreated       int
 	ProgramsCreated      int
 	ProgramsSkipped      int
-	WorkoutsSkipped      int // existing date conflicts
+	WorkoutsSkipped      int // existing date conflicts left untouched
+	WorkoutsMerged       int // existing date conflicts that gained missing sets
+	WorkoutsReplaced     int // existing date conflicts deleted and re-created
+	UnmappedSkipped      int // rows dropped because their exercise was not mapped
+
+	// Warnings describes rows the import dropped, one line per distinct
+	// reason (e.g. `set skipped: exercise "Zercher Squat" unmapped (4 rows)`).
+	Warnings []string
 }
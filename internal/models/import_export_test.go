@@ -0,0 +1,662 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carpenike/replog/internal/importers"
+)
+
+func TestWriteExportStrongCSV_RepTypeRoundTrip(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Carrier", "", "", "", "", "", "", sql.NullInt64{}, true)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	plank, _ := CreateExercise(db, "Plank", "", "", "", "", 0)
+	carry, _ := CreateExercise(db, "Farmer Carry", "", "", "", "", 0)
+	split, _ := CreateExercise(db, "Split Squat", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+
+	AddSet(db, w.ID, bench.ID, 5, 185, 0, "reps", "", "")
+	AddSet(db, w.ID, plank.ID, 60, 0, 0, "seconds", "", "")
+	AddSet(db, w.ID, carry.ID, 40, 70, 0, "distance", "", "")
+	AddSet(db, w.ID, split.ID, 8, 25, 0, "each_side", "", "slow")
+
+	var buf bytes.Buffer
+	if err := WriteExportStrongCSV(&buf, db, a.ID, "lbs"); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",36.58,") {
+		t.Errorf("expected 40 yd exported as 36.58 m in Distance column:\n%s", buf.String())
+	}
+
+	pf, err := importers.ParseStrongCSV(&buf)
+	if err != nil {
+		t.Fatalf("parse exported csv: %v", err)
+	}
+	if len(pf.Workouts) != 1 {
+		t.Fatalf("workouts = %d, want 1", len(pf.Workouts))
+	}
+
+	got := map[string]importers.ParsedWorkoutSet{}
+	for _, s := range pf.Workouts[0].Sets {
+		got[s.Exercise] = s
+	}
+	tests := []struct {
+		exercise string
+		repType  string
+		reps     int
+	}{
+		{"Bench Press", "reps", 5},
+		{"Plank", "seconds", 60},
+		{"Farmer Carry", "distance", 40},
+		{"Split Squat", "each_side", 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.exercise, func(t *testing.T) {
+			s, ok := got[tt.exercise]
+			if !ok {
+				t.Fatalf("missing %s after round trip", tt.exercise)
+			}
+			if s.RepType != tt.repType || s.Reps != tt.reps {
+				t.Errorf("set = %s x%d, want %s x%d", s.RepType, s.Reps, tt.repType, tt.reps)
+			}
+		})
+	}
+	if n := got["Split Squat"].Notes; n == nil || *n != "slow" {
+		t.Errorf("split squat notes = %v, want slow", n)
+	}
+}
+
+func TestExportWorkoutDuration(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Timed", "", "", "", "", "", "", sql.NullInt64{}, true)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+	AddSet(db, w.ID, bench.ID, 5, 185, 0, "reps", "", "")
+	SetWorkoutDuration(db, w.ID, 65)
+
+	var buf bytes.Buffer
+	if err := WriteExportStrongCSV(&buf, db, a.ID, "lbs"); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("rows = %d, want header + 1", len(records))
+	}
+	if got := records[1][2]; got != "1h 5m" {
+		t.Errorf("Duration column = %q, want 1h 5m", got)
+	}
+
+	wo, _ := GetWorkoutByID(db, w.ID)
+	ew, err := BuildExportWorkout(db, wo)
+	if err != nil {
+		t.Fatalf("build export workout: %v", err)
+	}
+	if ew.DurationMinutes == nil || *ew.DurationMinutes != 65 {
+		t.Errorf("duration_minutes = %v, want 65", ew.DurationMinutes)
+	}
+}
+
+func TestImportWorkoutDuration(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Timed", "", "", "", "", "", "", sql.NullInt64{}, true)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+	AddSet(db, w.ID, bench.ID, 5, 185, 0, "reps", "", "")
+	SetWorkoutDuration(db, w.ID, 65)
+
+	export, err := BuildExportJSON(db, a.ID)
+	if err != nil {
+		t.Fatalf("build export: %v", err)
+	}
+	var js, csvBuf bytes.Buffer
+	if err := WriteExportJSON(&js, export); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if err := WriteExportStrongCSV(&csvBuf, db, a.ID, "lbs"); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	fromJSON, err := importers.ParseRepLogJSON(&js)
+	if err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	fromCSV, err := importers.ParseStrongCSV(&csvBuf)
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+
+	for name, pf := range map[string]*importers.ParsedFile{"json": fromJSON, "csv": fromCSV} {
+		t.Run(name, func(t *testing.T) {
+			target, _ := CreateAthlete(db, "Target "+name, "", "", "", "", "", "", sql.NullInt64{}, true)
+			ms := &importers.MappingState{
+				Format:    pf.Format,
+				Exercises: []importers.EntityMapping{{ImportName: "Bench Press", MappedID: bench.ID}},
+				Parsed:    pf,
+			}
+			if _, err := ExecuteImport(db, target.ID, 0, ms, ConflictSkip); err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			got, err := GetWorkoutByAthleteDate(db, target.ID, "2026-03-01")
+			if err != nil {
+				t.Fatalf("get workout: %v", err)
+			}
+			if !got.DurationMinutes.Valid || got.DurationMinutes.Int64 != 65 {
+				t.Errorf("duration = %v, want 65 minutes", got.DurationMinutes)
+			}
+		})
+	}
+}
+
+func TestCatalogMuscleGroupRoundTrip(t *testing.T) {
+	db := testDB(t)
+	CreateExercise(db, "Bench Press", "", "push", "", "", 0)
+
+	catalog, err := BuildCatalogExportJSON(db)
+	if err != nil {
+		t.Fatalf("build catalog: %v", err)
+	}
+	if len(catalog.Exercises) != 1 || catalog.Exercises[0].MuscleGroup == nil || *catalog.Exercises[0].MuscleGroup != "push" {
+		t.Fatalf("exported exercises = %+v, want bench with push group", catalog.Exercises)
+	}
+
+	legs, bogus := "legs", "arms"
+	parsed := &importers.ParsedFile{Exercises: []importers.ParsedExercise{
+		{Name: "Goblet Squat", MuscleGroup: &legs},
+		{Name: "Curl", MuscleGroup: &bogus},
+	}}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: importers.BuildExerciseMappings(parsed.Exercises, nil),
+		Parsed:    parsed,
+	}
+	if _, err := ExecuteCatalogImport(db, ms, nil); err != nil {
+		t.Fatalf("catalog import: %v", err)
+	}
+
+	got, _ := ListExercises(db, ExerciseFilter{MuscleGroup: "legs"})
+	if len(got) != 1 || got[0].Name != "Goblet Squat" {
+		t.Errorf("legs exercises = %v, want Goblet Squat", got)
+	}
+	got, _ = ListExercises(db, ExerciseFilter{MuscleGroup: "none"})
+	if len(got) != 1 || got[0].Name != "Curl" {
+		t.Errorf("uncategorized = %v, want Curl with unknown group dropped", got)
+	}
+}
+
+func TestCatalogUnilateralRoundTrip(t *testing.T) {
+	db := testDB(t)
+	lunge, _ := CreateExercise(db, "Walking Lunge", "", "", "", "", 0)
+	SetExerciseUnilateral(db, lunge.ID, true)
+
+	catalog, err := BuildCatalogExportJSON(db)
+	if err != nil {
+		t.Fatalf("build catalog: %v", err)
+	}
+	if len(catalog.Exercises) != 1 || !catalog.Exercises[0].Unilateral {
+		t.Fatalf("exported exercises = %+v, want unilateral lunge", catalog.Exercises)
+	}
+
+	parsed := &importers.ParsedFile{Exercises: []importers.ParsedExercise{
+		{Name: "Single-Arm Row", Unilateral: true},
+	}}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: importers.BuildExerciseMappings(parsed.Exercises, nil),
+		Parsed:    parsed,
+	}
+	if _, err := ExecuteCatalogImport(db, ms, nil); err != nil {
+		t.Fatalf("catalog import: %v", err)
+	}
+
+	row, err := ResolveExerciseByNameOrAlias(db, "Single-Arm Row")
+	if err != nil {
+		t.Fatalf("resolve imported exercise: %v", err)
+	}
+	if !row.Unilateral {
+		t.Error("imported exercise should be unilateral")
+	}
+}
+
+func TestBuildCatalogImportPreview_ProgramDiff(t *testing.T) {
+	db := testDB(t)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1 BBB", "", 4, 4, false, "", 0, "")
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	five := 5
+	p65, p75, p85 := 0.65, 0.75, 0.85
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &five, nil, &p65, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 2, &five, nil, &p75, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 3, nil, nil, &p85, nil, nil, nil, 0, "reps", "")
+
+	p80 := 0.80
+	parsed := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		Name: "5/3/1 BBB", NumWeeks: 4, NumDays: 4,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, Percentage: &p65},
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 2, Reps: &five, Percentage: &p80},
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 4, Reps: &five, Percentage: &p65},
+			{Exercise: "Lunge", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+		},
+	}}}}
+	ms := &importers.MappingState{
+		Format: importers.FormatCatalogJSON,
+		Exercises: []importers.EntityMapping{
+			{ImportName: "Squat", MappedID: squat.ID, MappedName: "Squat"},
+			{ImportName: "Lunge", Create: true},
+		},
+		Programs: []importers.EntityMapping{{ImportName: "5/3/1 BBB", MappedID: tmpl.ID, MappedName: tmpl.Name}},
+		Parsed:   parsed,
+	}
+
+	p, err := BuildCatalogImportPreview(db, ms)
+	if err != nil {
+		t.Fatalf("build preview: %v", err)
+	}
+	if len(p.ProgramDiffs) != 1 {
+		t.Fatalf("program diffs = %d, want 1", len(p.ProgramDiffs))
+	}
+	d := p.ProgramDiffs[0]
+	if d.Added != 2 || d.Removed != 1 || d.Changed != 1 || d.Unchanged != 1 {
+		t.Errorf("diff = %+v, want added=2 removed=1 changed=1 unchanged=1", d)
+	}
+	if !d.HasChanges() {
+		t.Error("HasChanges = false, want true")
+	}
+}
+
+func TestAccessoryPlanExportRoundTrip(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Source", "", "", "", "", "", "", sql.NullInt64{}, true)
+	curl, _ := CreateExercise(db, "Face Pull", "", "", "", "", 0)
+	CreateAccessoryPlan(db, a.ID, 2, curl.ID, 3, 12, 12, 0, "", 0, true)
+
+	export, err := BuildExportJSON(db, a.ID)
+	if err != nil {
+		t.Fatalf("build export: %v", err)
+	}
+	if len(export.AccessoryPlans) != 1 {
+		t.Fatalf("accessory plans = %d, want 1", len(export.AccessoryPlans))
+	}
+	ea := export.AccessoryPlans[0]
+	if ea.Exercise != "Face Pull" || ea.Day != 2 || ea.TargetSets == nil || *ea.TargetSets != 3 || !ea.Superset {
+		t.Errorf("exported plan = %+v, want Face Pull day 2, 3 sets, superset", ea)
+	}
+	found := false
+	for _, ex := range export.Exercises {
+		if ex.Name == "Face Pull" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("accessory exercise missing from exported exercises")
+	}
+
+	target, _ := CreateAthlete(db, "Target", "", "", "", "", "", "", sql.NullInt64{}, true)
+	parsed := &importers.ParsedFile{
+		Format:         importers.FormatRepLogJSON,
+		AccessoryPlans: []importers.ParsedAccessoryPlan{{Day: 2, Exercise: "Face Pull", TargetSets: ea.TargetSets, Superset: true}},
+	}
+	ms := &importers.MappingState{
+		Format:    importers.FormatRepLogJSON,
+		Exercises: []importers.EntityMapping{{ImportName: "Face Pull", MappedID: curl.ID}},
+		Parsed:    parsed,
+	}
+	result, err := ExecuteImport(db, target.ID, 0, ms, ConflictSkip)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.AccessoriesCreated != 1 {
+		t.Errorf("accessories created = %d, want 1", result.AccessoriesCreated)
+	}
+	plans, _ := ListAccessoryPlansForDay(db, target.ID, 2)
+	if len(plans) != 1 || !plans[0].Superset || !plans[0].Active {
+		t.Errorf("imported plans = %+v, want one active superset plan", plans)
+	}
+}
+
+func TestExportImport_KilogramRoundTrip(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Metric", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+	AddSet(db, w.ID, squat.ID, 5, 220.46226, 0, "reps", "", "")
+	SetTrainingMax(db, a.ID, squat.ID, 440.92452, "2026-03-01", "")
+	CreateBodyWeight(db, a.ID, "2026-03-01", 176.36981, "")
+
+	export, err := BuildExportJSON(db, a.ID)
+	if err != nil {
+		t.Fatalf("build export: %v", err)
+	}
+	export.ConvertWeights("kg")
+	if export.WeightUnit != "kg" {
+		t.Errorf("weight unit = %q, want kg", export.WeightUnit)
+	}
+	if got := *export.Workouts[0].Sets[0].Weight; got != 100 {
+		t.Errorf("set weight = %v kg, want 100", got)
+	}
+	if got := export.TrainingMaxes[0].Weight; got != 200 {
+		t.Errorf("training max = %v kg, want 200", got)
+	}
+	if got := export.BodyWeights[0].Weight; got != 80 {
+		t.Errorf("body weight = %v kg, want 80", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExportStrongCSV(&buf, db, a.ID, "kg"); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",Squat,1,100,5,") {
+		t.Errorf("expected kg weight in CSV:\n%s", buf.String())
+	}
+
+	// Re-importing the kg export converts back to canonical lbs.
+	var js bytes.Buffer
+	if err := WriteExportJSON(&js, export); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	pf, err := importers.ParseRepLogJSON(&js)
+	if err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	target, _ := CreateAthlete(db, "Target", "", "", "", "", "", "", sql.NullInt64{}, true)
+	ms := &importers.MappingState{
+		Format:     importers.FormatRepLogJSON,
+		WeightUnit: pf.WeightUnit,
+		Exercises:  []importers.EntityMapping{{ImportName: "Squat", MappedID: squat.ID}},
+		Parsed:     pf,
+	}
+	if _, err := ExecuteImport(db, target.ID, 0, ms, ConflictSkip); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	tm, _ := CurrentTrainingMax(db, target.ID, squat.ID)
+	if tm == nil || math.Abs(tm.Weight-440.92) > 0.01 {
+		t.Errorf("imported TM = %v, want 440.92 lbs", tm)
+	}
+	bw, _ := LatestBodyWeight(db, target.ID)
+	if bw == nil || math.Abs(bw.Weight-176.37) > 0.01 || bw.Unit != "kg" {
+		t.Errorf("imported body weight = %+v, want 176.37 lbs logged in kg", bw)
+	}
+	if got := pf.TrainingMaxes[0].Weight; got != 200 {
+		t.Errorf("parsed file mutated: TM = %v, want 200", got)
+	}
+}
+
+func TestExecuteImport_ConflictModes(t *testing.T) {
+	tests := []struct {
+		mode        ConflictMode
+		wantSets    int
+		wantSkipped int
+		wantMerged  int
+		wantReplace int
+		wantSet1Wt  float64
+	}{
+		{ConflictSkip, 1, 1, 0, 0, 100},
+		{ConflictMerge, 2, 0, 1, 0, 100},
+		{ConflictReplace, 2, 0, 0, 1, 135},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			db := testDB(t)
+			a, _ := CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+			squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+			w, _ := CreateWorkout(db, a.ID, "2026-01-05", "", 0)
+			AddSet(db, w.ID, squat.ID, 5, 100, 0, "reps", "", "")
+
+			wt := 135.0
+			ms := &importers.MappingState{
+				Format:    importers.FormatRepLogJSON,
+				Exercises: []importers.EntityMapping{{ImportName: "Squat", MappedID: squat.ID}},
+				Parsed: &importers.ParsedFile{Workouts: []importers.ParsedWorkout{{
+					Date: "2026-01-05",
+					Sets: []importers.ParsedWorkoutSet{
+						{Exercise: "Squat", SetNumber: 1, Reps: 5, Weight: &wt},
+						{Exercise: "Squat", SetNumber: 2, Reps: 5, Weight: &wt},
+					},
+				}}},
+			}
+			result, err := ExecuteImport(db, a.ID, 0, ms, tt.mode)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if result.WorkoutsSkipped != tt.wantSkipped || result.WorkoutsMerged != tt.wantMerged || result.WorkoutsReplaced != tt.wantReplace || result.WorkoutsCreated != 0 {
+				t.Errorf("result = %+v", result)
+			}
+
+			got, err := GetWorkoutByAthleteDate(db, a.ID, "2026-01-05")
+			if err != nil {
+				t.Fatalf("get workout: %v", err)
+			}
+			var count int
+			var set1 float64
+			db.QueryRow(`SELECT COUNT(*) FROM workout_sets WHERE workout_id = ?`, got.ID).Scan(&count)
+			db.QueryRow(`SELECT weight FROM workout_sets WHERE workout_id = ? AND set_number = 1`, got.ID).Scan(&set1)
+			if count != tt.wantSets {
+				t.Errorf("sets = %d, want %d", count, tt.wantSets)
+			}
+			if set1 != tt.wantSet1Wt {
+				t.Errorf("set 1 weight = %v, want %v", set1, tt.wantSet1Wt)
+			}
+		})
+	}
+}
+
+func TestParseConflictMode(t *testing.T) {
+	for in, want := range map[string]ConflictMode{"": ConflictSkip, "merge": ConflictMerge, "replace": ConflictReplace, "bogus": ConflictSkip} {
+		if got := ParseConflictMode(in); got != want {
+			t.Errorf("ParseConflictMode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteExportPerExerciseZIP(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	press, _ := CreateExercise(db, "Overhead Press (DB)", "", "", "", "", 0)
+
+	// More workout days than one history page to exercise paging.
+	days := ExerciseHistoryPageSize + 3
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < days; i++ {
+		w, _ := CreateWorkout(db, a.ID, start.AddDate(0, 0, i).Format("2006-01-02"), "", 0)
+		AddSet(db, w.ID, squat.ID, 5, 220.46226, 8, "reps", "", "")
+		if i == 0 {
+			AddSet(db, w.ID, press.ID, 8, 0, 0, "reps", "", "")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExportPerExerciseZIP(&buf, db, a.ID, "kg"); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read zip: %v", err)
+	}
+	files := make(map[string][][]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		records, err := csv.NewReader(rc).ReadAll()
+		rc.Close()
+		if err != nil {
+			t.Fatalf("parse %s: %v", f.Name, err)
+		}
+		files[f.Name] = records
+	}
+
+	sq, ok := files["squat.csv"]
+	if !ok {
+		t.Fatalf("zip entries = %v, want squat.csv", zr.File)
+	}
+	if len(sq) != days+1 {
+		t.Errorf("squat rows = %d, want %d", len(sq), days+1)
+	}
+	if got := strings.Join(sq[0], ","); got != "Date,Set,Reps,Weight (kg),RPE" {
+		t.Errorf("header = %q", got)
+	}
+	if got := strings.Join(sq[1], ","); got != "2026-01"+fmt.Sprintf("-%02d", days)+",1,5,100,8" {
+		t.Errorf("first row = %q, want most recent squat in kg", got)
+	}
+
+	op, ok := files["overhead-press-db.csv"]
+	if !ok || len(op) != 2 || op[1][3] != "" {
+		t.Errorf("press csv = %v, want one bodyweight row", op)
+	}
+}
+
+func TestExecuteImport_UnmappedWarnings(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	wt := 135.0
+	ms := &importers.MappingState{
+		Format: importers.FormatStrongCSV,
+		Exercises: []importers.EntityMapping{
+			{ImportName: "Squat", MappedID: squat.ID},
+			{ImportName: "Zercher Squat"}, // neither mapped nor created
+		},
+		Parsed: &importers.ParsedFile{Workouts: []importers.ParsedWorkout{{
+			Date: "2026-01-05",
+			Sets: []importers.ParsedWorkoutSet{
+				{Exercise: "Squat", SetNumber: 1, Reps: 5, Weight: &wt},
+				{Exercise: "Zercher Squat", SetNumber: 1, Reps: 5, Weight: &wt},
+				{Exercise: "Zercher Squat", SetNumber: 2, Reps: 5, Weight: &wt},
+			},
+		}}},
+	}
+	result, err := ExecuteImport(db, a.ID, 0, ms, ConflictSkip)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.SetsCreated != 1 || result.UnmappedSkipped != 2 {
+		t.Errorf("sets created = %d, unmapped skipped = %d; want 1 and 2", result.SetsCreated, result.UnmappedSkipped)
+	}
+	want := `set skipped: exercise "Zercher Squat" unmapped (2 rows)`
+	if len(result.Warnings) != 1 || result.Warnings[0] != want {
+		t.Errorf("warnings = %q, want [%q]", result.Warnings, want)
+	}
+}
+
+func TestExecuteCatalogImport_OutOfRangeRPE(t *testing.T) {
+	db := testDB(t)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	five := 5
+	rpe8, rpe12 := 8.0, 12.0
+	parsed := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		Name: "RPE Block", NumWeeks: 1, NumDays: 1,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, TargetRPE: &rpe8},
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 2, Reps: &five, TargetRPE: &rpe12},
+		},
+	}}}}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: []importers.EntityMapping{{ImportName: "Squat", MappedID: squat.ID, MappedName: "Squat"}},
+		Programs:  []importers.EntityMapping{{ImportName: "RPE Block", Create: true}},
+		Parsed:    parsed,
+	}
+
+	result, err := ExecuteCatalogImport(db, ms, nil)
+	if err != nil {
+		t.Fatalf("catalog import: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "target RPE 12") {
+		t.Errorf("warnings = %q, want one about target RPE 12", result.Warnings)
+	}
+	if len(result.CreatedTemplateIDs) != 1 {
+		t.Fatalf("created templates = %v, want 1", result.CreatedTemplateIDs)
+	}
+	sets, err := ListPrescribedSets(db, result.CreatedTemplateIDs[0])
+	if err != nil {
+		t.Fatalf("list prescribed sets: %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("prescribed sets = %d, want 2", len(sets))
+	}
+	if !sets[0].TargetRPE.Valid || sets[0].TargetRPE.Float64 != 8 {
+		t.Errorf("set 1 RPE = %v, want 8", sets[0].TargetRPE)
+	}
+	if sets[1].TargetRPE.Valid {
+		t.Errorf("set 2 RPE = %v, want NULL", sets[1].TargetRPE)
+	}
+
+	if warnings := validateImportData(parsed); len(warnings) != 1 || warnings[0].Field != "target_rpe" {
+		t.Errorf("preview warnings = %+v, want one target_rpe warning", warnings)
+	}
+}
+
+func TestExecuteCatalogImport_RejectsInvertedRepRange(t *testing.T) {
+	db := testDB(t)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	eight, five := 8, 5
+	parsed := &importers.ParsedFile{Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+		Name: "Hypertrophy", NumWeeks: 1, NumDays: 1,
+		PrescribedSets: []importers.ParsedPrescribedSet{
+			{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &eight, RepMax: &five},
+		},
+	}}}}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: []importers.EntityMapping{{ImportName: "Squat", MappedID: squat.ID, MappedName: "Squat"}},
+		Programs:  []importers.EntityMapping{{ImportName: "Hypertrophy", Create: true}},
+		Parsed:    parsed,
+	}
+
+	if _, err := ExecuteCatalogImport(db, ms, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("catalog import: err = %v, want ErrInvalidInput", err)
+	}
+	if templates, _ := ListProgramTemplates(db); len(templates) != 0 {
+		t.Errorf("templates = %d, want none after a rejected import", len(templates))
+	}
+}
+func TestWriteExportPerExerciseZIP_UniqueNames(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	w, _ := CreateWorkout(db, a.ID, "2026-01-05", "", 0)
+	// "Foo" and "Foo!" both sanitize to "foo"; the second must not take
+	// "foo-2", which "Foo 2" already has.
+	for _, name := range []string{"Foo", "Foo 2", "Foo!"} {
+		ex, _ := CreateExercise(db, name, "", "", "", "", 0)
+		AddSet(db, w.ID, ex.ID, 5, 100, 0, "reps", "", "")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExportPerExerciseZIP(&buf, db, a.ID, "lbs"); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "foo.csv,foo-2.csv,foo-3.csv" {
+		t.Errorf("zip entries = %s, want foo.csv,foo-2.csv,foo-3.csv", got)
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // JournalEntry represents a single event on an athlete's timeline.
@@ -10,17 +12,92 @@ import (
 // and determines which detail fields are populated.
 type JournalEntry struct {
 	Date    string // YYYY-MM-DD
-	Type    string // "workout", "body_weight", "training_max", "goal_change", "tier_change", "program_start", "review", "note"
+	Type    string // "workout", "check_in", "body_weight", "training_max", "goal_change", "tier_change", "program_start", "review", "note"
 	Summary string // Human-readable one-line summary
 	ID      int64  // Source row ID (for linking)
 
 	// Optional detail fields (populated per type).
-	Detail    string // Secondary text (e.g., workout notes, exercise list)
+	Detail    string // Secondary text (e.g., workout notes, exercise list); the review status for "review"
 	IsPrivate bool   // Only relevant for "note" type
 	Pinned    bool   // Only relevant for "note" type
 	SecondID  int64  // Secondary ID (e.g., workout_id for reviews)
 	Author    string // Author/coach name for notes, reviews
 	AuthorID  int64  // Author user ID (for edit permission checks on notes)
+
+	AcknowledgedAt string            // YYYY-MM-DD the athlete confirmed reading a note, or ""
+	Attachments    []*NoteAttachment // Only relevant for "note" type
+	PRs            int               // Personal records set; only relevant for "workout" type
+}
+
+// JournalPageSize is the max number of journal entries returned per page.
+const JournalPageSize = 50
+
+// journalFilterTypes maps the entry types a journal can be filtered by to
+// the JournalEntry.Type they select.
+var journalFilterTypes = map[string]string{
+	"note":           "note",
+	"workout":        "workout",
+	"check-in":       "check_in",
+	"review":         "review",
+	"program-change": "program_start",
+	"training-max":   "training_max",
+}
+
+// JournalFilter narrows the journal timeline. Zero values match everything.
+type JournalFilter struct {
+	Types []string // "note", "workout", "check-in", "review", "program-change", "training-max"
+	From  string   // YYYY-MM-DD, inclusive
+	To    string   // YYYY-MM-DD, inclusive
+}
+
+// IsZero reports whether the filter matches every entry.
+func (f JournalFilter) IsZero() bool {
+	return len(f.Types) == 0 && f.From == "" && f.To == ""
+}
+
+// where returns the SQL condition and arguments for the filter, applied to
+// the columns of the unified journal query. Returns ErrInvalidInput for an
+// unknown type or a malformed date.
+func (f JournalFilter) where() (string, []any, error) {
+	var conds []string
+	var args []any
+
+	if len(f.Types) > 0 {
+		placeholders := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			entryType, ok := journalFilterTypes[t]
+			if !ok {
+				return "", nil, fmt.Errorf("models: journal filter type %q: %w", t, ErrInvalidInput)
+			}
+			placeholders[i] = "?"
+			args = append(args, entryType)
+		}
+		conds = append(conds, "type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	for _, bound := range []struct {
+		value, op string
+	}{{f.From, ">="}, {f.To, "<="}} {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", bound.value); err != nil {
+			return "", nil, fmt.Errorf("models: journal filter date %q: %w", bound.value, ErrInvalidInput)
+		}
+		// Some tables store DATE columns as full timestamps; compare the day.
+		conds = append(conds, "date(date) "+bound.op+" ?")
+		args = append(args, bound.value)
+	}
+
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args, nil
+}
+
+// JournalPage holds a page of journal entries and whether more exist.
+type JournalPage struct {
+	Entries []*JournalEntry
+	HasMore bool
 }
 
 // ListJournalEntries returns a unified timeline of events for an athlete,
@@ -30,15 +107,41 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 	if limit <= 0 {
 		limit = 100
 	}
+	return listJournalEntries(db, athleteID, includePrivate, JournalFilter{}, limit, 0)
+}
+
+// ListJournalPage returns one page of an athlete's timeline matching filter,
+// newest first. Uses offset-based pagination. Returns ErrInvalidInput if the
+// filter is malformed.
+func ListJournalPage(db *sql.DB, athleteID int64, includePrivate bool, filter JournalFilter, offset int) (*JournalPage, error) {
+	entries, err := listJournalEntries(db, athleteID, includePrivate, filter, JournalPageSize+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(entries) > JournalPageSize
+	if hasMore {
+		entries = entries[:JournalPageSize]
+	}
+	return &JournalPage{Entries: entries, HasMore: hasMore}, nil
+}
+
+// listJournalEntries runs the unified timeline query. The filter is applied
+// to the UNION as a whole, which SQLite pushes down into each branch.
+func listJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, filter JournalFilter, limit, offset int) ([]*JournalEntry, error) {
+	where, filterArgs, err := filter.where()
+	if err != nil {
+		return nil, err
+	}
 
 	privateFilter := ""
 	if !includePrivate {
 		privateFilter = " AND n.is_private = 0"
 	}
 
-	// Each UNION branch selects: date, type, summary, id, detail, is_private, pinned, second_id, author, author_id
+	// Each UNION branch selects: date, type, summary, id, detail, is_private, pinned, second_id, author, author_id, acknowledged_at
 	query := fmt.Sprintf(`
-		SELECT date, type, summary, id, detail, is_private, pinned, second_id, author, author_id FROM (
+		SELECT date, type, summary, id, detail, is_private, pinned, second_id, author, author_id, acknowledged_at FROM (
 			-- Workouts
 			SELECT w.date AS date,
 			       'workout' AS type,
@@ -58,12 +161,31 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       0 AS second_id,
 			       '' AS author,
-			       0 AS author_id
+			       0 AS author_id,
+			       '' AS acknowledged_at
 			FROM workouts w
 			WHERE w.athlete_id = ?
 
 			UNION ALL
 
+			-- Check-ins
+			SELECT ci.date AS date,
+			       'check_in' AS type,
+			       'Checked in' AS summary,
+			       ci.id AS id,
+			       COALESCE(ci.notes, '') AS detail,
+			       0 AS is_private,
+			       0 AS pinned,
+			       0 AS second_id,
+			       COALESCE(u.name, u.username, '') AS author,
+			       COALESCE(ci.created_by, 0) AS author_id,
+			       '' AS acknowledged_at
+			FROM check_ins ci
+			LEFT JOIN users u ON u.id = ci.created_by
+			WHERE ci.athlete_id = ?
+
+			UNION ALL
+
 			-- Body Weights
 			SELECT bw.date AS date,
 			       'body_weight' AS type,
@@ -74,7 +196,8 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       0 AS second_id,
 			       '' AS author,
-			       0 AS author_id
+			       0 AS author_id,
+			       '' AS acknowledged_at
 			FROM body_weights bw
 			WHERE bw.athlete_id = ?
 
@@ -90,7 +213,8 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       tm.exercise_id AS second_id,
 			       '' AS author,
-			       0 AS author_id
+			       0 AS author_id,
+			       '' AS acknowledged_at
 			FROM training_maxes tm
 			JOIN exercises e ON e.id = tm.exercise_id
 			WHERE tm.athlete_id = ?
@@ -107,7 +231,8 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       0 AS second_id,
 			       COALESCE(u.name, u.username, '') AS author,
-			       COALESCE(gh.set_by, 0) AS author_id
+			       COALESCE(gh.set_by, 0) AS author_id,
+			       '' AS acknowledged_at
 			FROM goal_history gh
 			LEFT JOIN users u ON u.id = gh.set_by
 			WHERE gh.athlete_id = ?
@@ -127,7 +252,8 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       0 AS second_id,
 			       COALESCE(u.name, u.username, '') AS author,
-			       COALESCE(th.set_by, 0) AS author_id
+			       COALESCE(th.set_by, 0) AS author_id,
+			       '' AS acknowledged_at
 			FROM tier_history th
 			LEFT JOIN users u ON u.id = th.set_by
 			WHERE th.athlete_id = ?
@@ -144,7 +270,8 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       0 AS pinned,
 			       ap.template_id AS second_id,
 			       '' AS author,
-			       0 AS author_id
+			       0 AS author_id,
+			       '' AS acknowledged_at
 			FROM athlete_programs ap
 			JOIN program_templates pt ON pt.id = ap.template_id
 			WHERE ap.athlete_id = ?
@@ -154,14 +281,20 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			-- Workout Reviews
 			SELECT date(wr.created_at) AS date,
 			       'review' AS type,
-			       wr.status || ' — ' || COALESCE(wr.notes, 'No comment') AS summary,
+			       CASE wr.status
+			           WHEN 'approved' THEN 'Approved'
+			           WHEN 'needs_changes' THEN 'Needs changes'
+			           WHEN 'flagged_injury' THEN 'Flagged: possible injury'
+			           ELSE wr.status
+			       END || ' — ' || COALESCE(wr.notes, 'No comment') AS summary,
 			       wr.id AS id,
-			       '' AS detail,
+			       wr.status AS detail,
 			       0 AS is_private,
 			       0 AS pinned,
 			       wr.workout_id AS second_id,
 			       COALESCE(u.name, u.username, '') AS author,
-			       COALESCE(wr.coach_id, 0) AS author_id
+			       COALESCE(wr.coach_id, 0) AS author_id,
+			       '' AS acknowledged_at
 			FROM workout_reviews wr
 			LEFT JOIN users u ON u.id = wr.coach_id
 			JOIN workouts w ON w.id = wr.workout_id
@@ -179,21 +312,26 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 			       n.pinned AS pinned,
 			       0 AS second_id,
 			       COALESCE(u.name, u.username, '') AS author,
-			       COALESCE(n.author_id, 0) AS author_id
+			       COALESCE(n.author_id, 0) AS author_id,
+			       COALESCE(date(n.acknowledged_at), '') AS acknowledged_at
 			FROM athlete_notes n
 			LEFT JOIN users u ON u.id = n.author_id
 			WHERE n.athlete_id = ?%s
 		)
-		ORDER BY pinned DESC, date DESC
-		LIMIT ?`,
-		privateFilter,
+		%s
+		ORDER BY pinned DESC, date DESC, type, id DESC
+		LIMIT ? OFFSET ?`,
+		privateFilter, where,
 	)
 
-	rows, err := db.Query(query,
-		athleteID, athleteID, athleteID, athleteID,
+	args := []any{
+		athleteID, athleteID, athleteID, athleteID, athleteID,
 		athleteID, athleteID, athleteID, athleteID,
-		limit,
-	)
+	}
+	args = append(args, filterArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("models: list journal entries for athlete %d: %w", athleteID, err)
 	}
@@ -204,12 +342,43 @@ func ListJournalEntries(db *sql.DB, athleteID int64, includePrivate bool, limit
 		e := &JournalEntry{}
 		var privInt, pinnedInt int
 		if err := rows.Scan(&e.Date, &e.Type, &e.Summary, &e.ID,
-			&e.Detail, &privInt, &pinnedInt, &e.SecondID, &e.Author, &e.AuthorID); err != nil {
+			&e.Detail, &privInt, &pinnedInt, &e.SecondID, &e.Author, &e.AuthorID, &e.AcknowledgedAt); err != nil {
 			return nil, fmt.Errorf("models: scan journal entry: %w", err)
 		}
 		e.IsPrivate = privInt == 1
 		e.Pinned = pinnedInt == 1
 		entries = append(entries, e)
 	}
-	return entries, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Attach images to their notes and PR counts to their workouts in one
+	// query each rather than per entry.
+	var noteIDs, workoutIDs []int64
+	for _, e := range entries {
+		switch e.Type {
+		case "note":
+			noteIDs = append(noteIDs, e.ID)
+		case "workout":
+			workoutIDs = append(workoutIDs, e.ID)
+		}
+	}
+	attachments, err := noteAttachmentsByNote(db, noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	prCounts, err := prCountsByWorkout(db, workoutIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		switch e.Type {
+		case "note":
+			e.Attachments = attachments[e.ID]
+		case "workout":
+			e.PRs = prCounts[e.ID]
+		}
+	}
+	return entries, nil
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -13,8 +14,8 @@ func TestListJournalEntries(t *testing.T) {
 
 	// Seed some data across multiple tables.
 	w, _ := CreateWorkout(db, a.ID, "2026-02-10", "Felt strong today", 0)
-	e, _ := CreateExercise(db, "Squat", "", "", "", 0)
-	e2, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
+	e, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	e2, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 	AddSet(db, w.ID, e.ID, 5, 225, 0, "reps", "", "")
 	AddSet(db, w.ID, e.ID, 5, 225, 0, "reps", "", "")
 	AddSet(db, w.ID, e2.ID, 8, 135, 0, "reps", "", "")
@@ -151,3 +152,93 @@ func TestListJournalEntries(t *testing.T) {
 		}
 	})
 }
+
+func TestListJournalPage(t *testing.T) {
+	db := testDB(t)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	a, _ := CreateAthlete(db, "Filter Athlete", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	CreateWorkout(db, a.ID, "2026-03-01", "", 0)
+	CreateWorkout(db, a.ID, "2026-03-08", "", 0)
+	SetTrainingMax(db, a.ID, squat.ID, 200, "2026-03-02", "")
+	CreateBodyWeight(db, a.ID, "2026-03-03", 180, "")
+	CreateAthleteNote(db, a.ID, coach.ID, "2026-03-04", "Early note", false, false)
+	CreateAthleteNote(db, a.ID, coach.ID, "2026-03-09", "Late note", false, false)
+
+	t.Run("filters by type", func(t *testing.T) {
+		page, err := ListJournalPage(db, a.ID, true, JournalFilter{Types: []string{"note", "training-max"}}, 0)
+		if err != nil {
+			t.Fatalf("list journal page: %v", err)
+		}
+		if len(page.Entries) != 3 {
+			t.Fatalf("len = %d, want 3", len(page.Entries))
+		}
+		for _, e := range page.Entries {
+			if e.Type != "note" && e.Type != "training_max" {
+				t.Errorf("unexpected type %q", e.Type)
+			}
+		}
+	})
+
+	t.Run("filters by date range", func(t *testing.T) {
+		page, err := ListJournalPage(db, a.ID, true, JournalFilter{From: "2026-03-02", To: "2026-03-04"}, 0)
+		if err != nil {
+			t.Fatalf("list journal page: %v", err)
+		}
+		if len(page.Entries) != 3 {
+			t.Fatalf("len = %d, want 3 (TM, body weight, early note)", len(page.Entries))
+		}
+		for _, e := range page.Entries {
+			if day := e.Date[:10]; day < "2026-03-02" || day > "2026-03-04" {
+				t.Errorf("entry %s on %s outside range", e.Type, e.Date)
+			}
+		}
+	})
+
+	t.Run("combines type and dates", func(t *testing.T) {
+		page, err := ListJournalPage(db, a.ID, true, JournalFilter{Types: []string{"workout"}, From: "2026-03-05"}, 0)
+		if err != nil {
+			t.Fatalf("list journal page: %v", err)
+		}
+		if len(page.Entries) != 1 || !strings.HasPrefix(page.Entries[0].Date, "2026-03-08") {
+			t.Errorf("entries = %+v, want only the 2026-03-08 workout", page.Entries)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		for _, f := range []JournalFilter{{Types: []string{"body_weight"}}, {From: "March 1"}} {
+			if _, err := ListJournalPage(db, a.ID, true, f, 0); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("filter %+v: err = %v, want ErrInvalidInput", f, err)
+			}
+		}
+	})
+
+	t.Run("paginates filtered results", func(t *testing.T) {
+		for i := 0; i < JournalPageSize; i++ {
+			CreateAthleteNote(db, a.ID, coach.ID, "2026-04-01", "Bulk note", false, false)
+		}
+		filter := JournalFilter{Types: []string{"note"}}
+		first, err := ListJournalPage(db, a.ID, true, filter, 0)
+		if err != nil {
+			t.Fatalf("first page: %v", err)
+		}
+		if len(first.Entries) != JournalPageSize || !first.HasMore {
+			t.Fatalf("first page len = %d, hasMore = %v; want %d, true", len(first.Entries), first.HasMore, JournalPageSize)
+		}
+		second, err := ListJournalPage(db, a.ID, true, filter, JournalPageSize)
+		if err != nil {
+			t.Fatalf("second page: %v", err)
+		}
+		if len(second.Entries) != 2 || second.HasMore {
+			t.Fatalf("second page len = %d, hasMore = %v; want 2, false", len(second.Entries), second.HasMore)
+		}
+		seen := make(map[int64]bool)
+		for _, e := range append(first.Entries, second.Entries...) {
+			if seen[e.ID] {
+				t.Errorf("note %d returned on both pages", e.ID)
+			}
+			seen[e.ID] = true
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Account lockout policy for password logins. The first LoginLockoutThreshold
+// failures are free; each failure after that locks the account for twice as
+// long as the last, starting at LoginLockoutBase and capped at
+// LoginLockoutMax. Failures older than LoginFailureWindow are forgotten.
+const (
+	LoginLockoutThreshold = 5
+	LoginLockoutBase      = time.Minute
+	LoginLockoutMax       = time.Hour
+	LoginFailureWindow    = 24 * time.Hour
+)
+
+// lockoutFor returns how long an account is locked after its nth
+// consecutive failure, or 0 if it isn't locked.
+func lockoutFor(failures int) time.Duration {
+	if failures < LoginLockoutThreshold {
+		return 0
+	}
+	d := LoginLockoutBase
+	for i := LoginLockoutThreshold; i < failures && d < LoginLockoutMax; i++ {
+		d *= 2
+	}
+	return min(d, LoginLockoutMax)
+}
+
+// RecordFailedLogin counts a failed password login for username and returns
+// the time the account is locked until, or the zero time if this failure
+// didn't lock it. Failures are counted for unknown usernames too.
+func RecordFailedLogin(db *sql.DB, username string) (time.Time, error) {
+	now := time.Now()
+	var failures int
+	err := db.QueryRow(`
+		INSERT INTO login_attempts (username, failures, last_failed_at) VALUES (?, 1, ?)
+		ON CONFLICT(username) DO UPDATE SET
+			failures = CASE WHEN last_failed_at < ? THEN 1 ELSE failures + 1 END,
+			last_failed_at = excluded.last_failed_at
+		RETURNING failures`,
+		username, now, now.Add(-LoginFailureWindow),
+	).Scan(&failures)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("models: record failed login for %q: %w", username, err)
+	}
+
+	d := lockoutFor(failures)
+	if d == 0 {
+		return time.Time{}, nil
+	}
+	until := now.Add(d)
+	if _, err := db.Exec(`UPDATE login_attempts SET locked_until = ? WHERE username = ?`, until, username); err != nil {
+		return time.Time{}, fmt.Errorf("models: lock login for %q: %w", username, err)
+	}
+	return until, nil
+}
+
+// IsAccountLocked reports whether password logins for username are
+// temporarily locked after repeated failures, and until when.
+func IsAccountLocked(db *sql.DB, username string) (bool, time.Time, error) {
+	var until time.Time
+	err := db.QueryRow(
+		`SELECT locked_until FROM login_attempts WHERE username = ? AND locked_until > ?`,
+		username, time.Now(),
+	).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("models: check login lock for %q: %w", username, err)
+	}
+	return true, until, nil
+}
+
+// ResetFailedLogins clears the failure count for username after a
+// successful login.
+func ResetFailedLogins(db *sql.DB, username string) error {
+	if _, err := db.Exec(`DELETE FROM login_attempts WHERE username = ?`, username); err != nil {
+		return fmt.Errorf("models: reset failed logins for %q: %w", username, err)
+	}
+	return nil
+}
+
+// DeleteStaleLoginAttempts removes failure counts that are no longer
+// relevant: unlocked and older than LoginFailureWindow. Returns the number
+// of rows deleted.
+func DeleteStaleLoginAttempts(db *sql.DB) (int64, error) {
+	now := time.Now()
+	result, err := db.Exec(
+		`DELETE FROM login_attempts
+		 WHERE last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)`,
+		now.Add(-LoginFailureWindow), now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete stale login attempts: %w", err)
+	}
+	return result.RowsAffected()
+}
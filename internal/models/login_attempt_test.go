@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutFor(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 0},
+		{LoginLockoutThreshold - 1, 0},
+		{LoginLockoutThreshold, LoginLockoutBase},
+		{LoginLockoutThreshold + 1, 2 * LoginLockoutBase},
+		{LoginLockoutThreshold + 3, 8 * LoginLockoutBase},
+		{LoginLockoutThreshold + 50, LoginLockoutMax},
+	}
+	for _, tt := range tests {
+		if got := lockoutFor(tt.failures); got != tt.want {
+			t.Errorf("lockoutFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestRecordFailedLogin(t *testing.T) {
+	db := testDB(t)
+
+	for i := 1; i < LoginLockoutThreshold; i++ {
+		until, err := RecordFailedLogin(db, "nobody")
+		if err != nil {
+			t.Fatalf("record failed login: %v", err)
+		}
+		if !until.IsZero() {
+			t.Fatalf("failure %d locked the account", i)
+		}
+	}
+	if locked, _, _ := IsAccountLocked(db, "nobody"); locked {
+		t.Fatal("account locked before reaching the threshold")
+	}
+
+	// Usernames match case-insensitively, like users.username.
+	until, err := RecordFailedLogin(db, "NoBody")
+	if err != nil {
+		t.Fatalf("record failed login: %v", err)
+	}
+	if d := time.Until(until); d <= 0 || d > LoginLockoutBase {
+		t.Errorf("locked for %v, want up to %v", d, LoginLockoutBase)
+	}
+	locked, lockedUntil, err := IsAccountLocked(db, "nobody")
+	if err != nil {
+		t.Fatalf("is account locked: %v", err)
+	}
+	if !locked || !lockedUntil.Equal(until) {
+		t.Errorf("IsAccountLocked = %v %v, want true %v", locked, lockedUntil, until)
+	}
+
+	// Old failures are forgotten rather than extending the lockout.
+	db.Exec(`UPDATE login_attempts SET last_failed_at = ?, locked_until = NULL`, time.Now().Add(-LoginFailureWindow-time.Minute))
+	if until, _ := RecordFailedLogin(db, "nobody"); !until.IsZero() {
+		t.Error("failure after the window should start a fresh count")
+	}
+
+	if err := ResetFailedLogins(db, "nobody"); err != nil {
+		t.Fatalf("reset failed logins: %v", err)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM login_attempts`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected no rows after reset, got %d", n)
+	}
+}
+
+func TestDeleteStaleLoginAttempts(t *testing.T) {
+	db := testDB(t)
+
+	RecordFailedLogin(db, "fresh")
+	RecordFailedLogin(db, "stale")
+	db.Exec(`UPDATE login_attempts SET last_failed_at = ? WHERE username = 'stale'`, time.Now().Add(-LoginFailureWindow-time.Hour))
+
+	deleted, err := DeleteStaleLoginAttempts(db)
+	if err != nil {
+		t.Fatalf("delete stale login attempts: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d, want 1", deleted)
+	}
+}
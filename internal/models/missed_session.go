@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MissedSession is an athlete who had a scheduled training day with no
+// logged workout or check-in.
+type MissedSession struct {
+	AthleteID   int64
+	AthleteName string
+	CoachID     int64
+	Date        string // YYYY-MM-DD
+}
+
+// AthletesWithMissedSessions returns coached, unarchived athletes whose active program
+// has date as a training day but who neither logged a workout nor checked in
+// that day. Programs without a training-day schedule never count as missed,
+// and athletes already recorded with RecordMissedSessionAlert for date are
+// excluded.
+func AthletesWithMissedSessions(db *sql.DB, date time.Time) ([]*MissedSession, error) {
+	day := date.Format("2006-01-02")
+	bit := int64(1) << (isoWeekday(date) - 1)
+
+	rows, err := db.Query(`
+		SELECT a.id, a.name, a.coach_id
+		FROM athletes a
+		WHERE a.coach_id IS NOT NULL AND a.archived = 0
+		  AND EXISTS (
+		      SELECT 1 FROM athlete_programs ap
+		      WHERE ap.athlete_id = a.id AND ap.active = 1
+		        AND ap.training_days IS NOT NULL AND (ap.training_days & ?) != 0
+		        AND ap.start_date <= ?)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM workouts w WHERE w.athlete_id = a.id AND date(w.date) = ?)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM check_ins ci WHERE ci.athlete_id = a.id AND date(ci.date) = ?)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM missed_session_alerts m WHERE m.athlete_id = a.id AND m.date = ?)
+		ORDER BY a.name COLLATE NOCASE`, bit, day, day, day, day)
+	if err != nil {
+		return nil, fmt.Errorf("models: list missed sessions for %s: %w", day, err)
+	}
+	defer rows.Close()
+
+	var missed []*MissedSession
+	for rows.Next() {
+		m := &MissedSession{Date: day}
+		if err := rows.Scan(&m.AthleteID, &m.AthleteName, &m.CoachID); err != nil {
+			return nil, fmt.Errorf("models: scan missed session: %w", err)
+		}
+		missed = append(missed, m)
+	}
+	return missed, rows.Err()
+}
+
+// RecordMissedSessionAlert marks an athlete's missed session on date as
+// reported. Returns false if it had already been recorded.
+func RecordMissedSessionAlert(db *sql.DB, athleteID int64, date string) (bool, error) {
+	result, err := db.Exec(`INSERT OR IGNORE INTO missed_session_alerts (athlete_id, date) VALUES (?, ?)`, athleteID, date)
+	if err != nil {
+		return false, fmt.Errorf("models: record missed session for athlete %d on %s: %w", athleteID, date, err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestAthletesWithMissedSessions(t *testing.T) {
+	db := testDB(t)
+	coach := seedCoachUser(t, db)
+	coachID := sql.NullInt64{Int64: coach.ID, Valid: true}
+	tmpl, _ := CreateProgramTemplate(db, nil, "Program", "", 4, 3, false, "", 0, "")
+
+	monday := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	mon := TrainingDaysMask([]int{1})
+	wed := TrainingDaysMask([]int{3})
+
+	seed := func(name string, coach sql.NullInt64, start string, mask int64) *Athlete {
+		t.Helper()
+		a, err := CreateAthlete(db, name, "", "", "", "", "", "", coach, true)
+		if err != nil {
+			t.Fatalf("create athlete %s: %v", name, err)
+		}
+		ap, err := AssignProgram(db, a.ID, tmpl.ID, start, "", "", "primary", "")
+		if err != nil {
+			t.Fatalf("assign program to %s: %v", name, err)
+		}
+		if err := SetTrainingDays(db, ap.ID, mask); err != nil {
+			t.Fatalf("set training days for %s: %v", name, err)
+		}
+		return a
+	}
+
+	missed := seed("Missed", coachID, "2026-01-01", mon)
+	logged := seed("Logged", coachID, "2026-01-01", mon)
+	checkedIn := seed("Checked In", coachID, "2026-01-01", mon)
+	seed("Rest Day", coachID, "2026-01-01", wed)
+	seed("Any Day", coachID, "2026-01-01", 0)
+	seed("Uncoached", sql.NullInt64{}, "2026-01-01", mon)
+	seed("Not Started", coachID, "2026-01-06", mon)
+	CreateWorkout(db, logged.ID, "2026-01-05", "", 0)
+	CheckInAthlete(db, checkedIn.ID, "2026-01-05", "mobility", 0)
+
+	got, err := AthletesWithMissedSessions(db, monday)
+	if err != nil {
+		t.Fatalf("missed sessions: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d missed sessions, want 1", len(got))
+	}
+	if got[0].AthleteID != missed.ID || got[0].CoachID != coach.ID || got[0].Date != "2026-01-05" {
+		t.Errorf("missed session = %+v, want athlete %d, coach %d on 2026-01-05", got[0], missed.ID, coach.ID)
+	}
+
+	t.Run("recorded alerts are excluded", func(t *testing.T) {
+		recorded, err := RecordMissedSessionAlert(db, missed.ID, "2026-01-05")
+		if err != nil || !recorded {
+			t.Fatalf("record alert = %v, %v; want true, nil", recorded, err)
+		}
+		again, err := RecordMissedSessionAlert(db, missed.ID, "2026-01-05")
+		if err != nil || again {
+			t.Errorf("record duplicate alert = %v, %v; want false, nil", again, err)
+		}
+
+		got, err := AthletesWithMissedSessions(db, monday)
+		if err != nil {
+			t.Fatalf("missed sessions: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("got %d missed sessions after recording, want 0", len(got))
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NoteAttachment is an image file attached to an athlete note.
+type NoteAttachment struct {
+	ID          int64
+	NoteID      int64
+	Filename    string // random on-disk name, unique
+	ContentType string
+	SizeBytes   int64
+	CreatedAt   time.Time
+
+	// Joined fields from the note, for access checks when serving.
+	AthleteID int64
+	IsPrivate bool
+}
+
+// CreateNoteAttachment records an image already written to disk as filename.
+func CreateNoteAttachment(db *sql.DB, noteID int64, filename, contentType string, sizeBytes int64) (*NoteAttachment, error) {
+	var id int64
+	err := db.QueryRow(
+		`INSERT INTO note_attachments (note_id, filename, content_type, size_bytes)
+		 VALUES (?, ?, ?, ?) RETURNING id`,
+		noteID, filename, contentType, sizeBytes,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("models: create attachment for note %d: %w", noteID, err)
+	}
+	return getNoteAttachment(db, "a.id = ?", id)
+}
+
+// GetNoteAttachmentByFilename returns the attachment stored as filename,
+// with the owning note's athlete and visibility.
+func GetNoteAttachmentByFilename(db *sql.DB, filename string) (*NoteAttachment, error) {
+	return getNoteAttachment(db, "a.filename = ?", filename)
+}
+
+func getNoteAttachment(db *sql.DB, where string, arg any) (*NoteAttachment, error) {
+	a := &NoteAttachment{}
+	var privInt int
+	err := db.QueryRow(`
+		SELECT a.id, a.note_id, a.filename, a.content_type, a.size_bytes, a.created_at,
+		       n.athlete_id, n.is_private
+		FROM note_attachments a
+		JOIN athlete_notes n ON n.id = a.note_id
+		WHERE `+where, arg,
+	).Scan(&a.ID, &a.NoteID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.CreatedAt,
+		&a.AthleteID, &privInt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get note attachment: %w", err)
+	}
+	a.IsPrivate = privInt == 1
+	return a, nil
+}
+
+// ListNoteAttachments returns a note's attachments in upload order.
+func ListNoteAttachments(db *sql.DB, noteID int64) ([]*NoteAttachment, error) {
+	byNote, err := noteAttachmentsByNote(db, []int64{noteID})
+	if err != nil {
+		return nil, err
+	}
+	return byNote[noteID], nil
+}
+
+// noteAttachmentsByNote returns the attachments of the given notes keyed by
+// note ID, in upload order.
+func noteAttachmentsByNote(db *sql.DB, noteIDs []int64) (map[int64][]*NoteAttachment, error) {
+	byNote := make(map[int64][]*NoteAttachment)
+	if len(noteIDs) == 0 {
+		return byNote, nil
+	}
+
+	placeholders := make([]string, len(noteIDs))
+	args := make([]any, len(noteIDs))
+	for i, id := range noteIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	rows, err := db.Query(`
+		SELECT a.id, a.note_id, a.filename, a.content_type, a.size_bytes, a.created_at,
+		       n.athlete_id, n.is_private
+		FROM note_attachments a
+		JOIN athlete_notes n ON n.id = a.note_id
+		WHERE a.note_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY a.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("models: list note attachments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := &NoteAttachment{}
+		var privInt int
+		if err := rows.Scan(&a.ID, &a.NoteID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.CreatedAt,
+			&a.AthleteID, &privInt); err != nil {
+			return nil, fmt.Errorf("models: scan note attachment: %w", err)
+		}
+		a.IsPrivate = privInt == 1
+		byNote[a.NoteID] = append(byNote[a.NoteID], a)
+	}
+	return byNote, rows.Err()
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestNoteAttachments(t *testing.T) {
+	db := testDB(t)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	a, _ := CreateAthlete(db, "Photo Athlete", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	note, _ := CreateAthleteNote(db, a.ID, coach.ID, "2026-03-01", "Form check", true, false)
+	CreateAthleteNote(db, a.ID, coach.ID, "2026-03-02", "No photo", false, false)
+
+	att, err := CreateNoteAttachment(db, note.ID, "1_abc.png", "image/png", 1234)
+	if err != nil {
+		t.Fatalf("create attachment: %v", err)
+	}
+	if att.AthleteID != a.ID || !att.IsPrivate || att.SizeBytes != 1234 {
+		t.Errorf("attachment = %+v, want joined athlete and private flag", att)
+	}
+
+	t.Run("get by filename", func(t *testing.T) {
+		got, err := GetNoteAttachmentByFilename(db, "1_abc.png")
+		if err != nil || got.NoteID != note.ID {
+			t.Errorf("get = %+v, %v; want note %d", got, err, note.ID)
+		}
+		if _, err := GetNoteAttachmentByFilename(db, "missing.png"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("missing err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("journal entries carry attachments", func(t *testing.T) {
+		entries, err := ListJournalEntries(db, a.ID, true, 100)
+		if err != nil {
+			t.Fatalf("list journal entries: %v", err)
+		}
+		for _, e := range entries {
+			want := 0
+			if e.ID == note.ID {
+				want = 1
+			}
+			if len(e.Attachments) != want {
+				t.Errorf("note %q has %d attachments, want %d", e.Summary, len(e.Attachments), want)
+			}
+		}
+	})
+
+	t.Run("cascade on note delete", func(t *testing.T) {
+		if err := DeleteAthleteNote(db, note.ID); err != nil {
+			t.Fatalf("delete note: %v", err)
+		}
+		if _, err := GetNoteAttachmentByFilename(db, "1_abc.png"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound after cascade", err)
+		}
+	})
+}
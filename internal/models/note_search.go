@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// NoteSearchLimit caps the number of search results returned.
+const NoteSearchLimit = 50
+
+// NoteSearchResult is a note or workout whose notes match a search query.
+type NoteSearchResult struct {
+	Type    string // "note" or "workout", matching JournalEntry.Type
+	ID      int64  // athlete_notes.id or workouts.id
+	Date    string // YYYY-MM-DD
+	Snippet string // Excerpt around the match
+}
+
+// SearchNotes finds an athlete's notes and workout notes matching query,
+// best match first. Each whitespace-separated word must appear, and the last
+// word matches as a prefix so partial input still finds results. If
+// includePrivate is false, private notes are excluded. Returns
+// ErrInvalidInput for an empty query.
+func SearchNotes(db *sql.DB, athleteID int64, includePrivate bool, query string) ([]*NoteSearchResult, error) {
+	match := ftsMatchQuery(query)
+	if match == "" {
+		return nil, fmt.Errorf("models: search notes: empty query: %w", ErrInvalidInput)
+	}
+
+	privateFilter := ""
+	if !includePrivate {
+		privateFilter = " AND n.is_private = 0"
+	}
+
+	rows, err := db.Query(`
+		SELECT f.kind, f.source_id,
+		       date(COALESCE(n.date, w.date)),
+		       snippet(notes_fts, 0, '', '', '…', 16)
+		FROM notes_fts f
+		LEFT JOIN athlete_notes n ON f.kind = 'note' AND n.id = f.source_id
+		LEFT JOIN workouts w ON f.kind = 'workout' AND w.id = f.source_id
+		WHERE notes_fts MATCH ? AND f.athlete_id = ?
+		  AND (f.kind = 'workout' OR (n.id IS NOT NULL`+privateFilter+`))
+		ORDER BY rank
+		LIMIT ?`, match, athleteID, NoteSearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("models: search notes for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var results []*NoteSearchResult
+	for rows.Next() {
+		r := &NoteSearchResult{}
+		if err := rows.Scan(&r.Type, &r.ID, &r.Date, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("models: scan note search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// ftsMatchQuery turns free-form user input into an FTS5 MATCH expression.
+// Every word is quoted so punctuation and FTS operators in the input are
+// treated as text rather than query syntax.
+func ftsMatchQuery(query string) string {
+	words := strings.Fields(query)
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.ReplaceAll(w, `"`, "")
+		if w == "" {
+			continue
+		}
+		terms = append(terms, `"`+w+`"`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
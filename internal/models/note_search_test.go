@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestSearchNotes(t *testing.T) {
+	db := testDB(t)
+	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	other, _ := CreateAthlete(db, "Other Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+
+	note, err := CreateAthleteNote(db, a.ID, coach.ID, "2026-01-10", "Remember the bracing cue before each rep", false, false)
+	if err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	if _, err := CreateAthleteNote(db, a.ID, coach.ID, "2026-01-11", "Bracing looks weak, watch closely", true, false); err != nil {
+		t.Fatalf("create private note: %v", err)
+	}
+	if _, err := CreateAthleteNote(db, other.ID, coach.ID, "2026-01-12", "Bracing for another athlete", false, false); err != nil {
+		t.Fatalf("create other note: %v", err)
+	}
+	w, err := CreateWorkout(db, a.ID, "2026-01-15", "Felt strong on squats", 0)
+	if err != nil {
+		t.Fatalf("create workout: %v", err)
+	}
+
+	t.Run("matches notes and excludes private", func(t *testing.T) {
+		results, err := SearchNotes(db, a.ID, false, "bracing")
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("got %d results, want 1", len(results))
+		}
+		r := results[0]
+		if r.Type != "note" || r.ID != note.ID || r.Date != "2026-01-10" {
+			t.Errorf("result = %+v, want note %d on 2026-01-10", r, note.ID)
+		}
+		if r.Snippet == "" {
+			t.Error("expected snippet")
+		}
+	})
+
+	t.Run("coach sees private notes", func(t *testing.T) {
+		results, err := SearchNotes(db, a.ID, true, "bracing")
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("got %d results, want 2", len(results))
+		}
+	})
+
+	t.Run("matches workout notes by prefix", func(t *testing.T) {
+		results, err := SearchNotes(db, a.ID, false, "squat")
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		if len(results) != 1 || results[0].Type != "workout" || results[0].ID != w.ID {
+			t.Fatalf("results = %+v, want workout %d", results, w.ID)
+		}
+	})
+
+	t.Run("index follows edits and deletes", func(t *testing.T) {
+		if err := UpdateWorkoutNotes(db, w.ID, "Easy day"); err != nil {
+			t.Fatalf("update workout notes: %v", err)
+		}
+		if results, _ := SearchNotes(db, a.ID, false, "squats"); len(results) != 0 {
+			t.Errorf("got %d results for old workout notes, want 0", len(results))
+		}
+		if _, err := UpdateAthleteNote(db, note.ID, "Keep the hips tight", false, false); err != nil {
+			t.Fatalf("update note: %v", err)
+		}
+		if results, _ := SearchNotes(db, a.ID, false, "hips"); len(results) != 1 {
+			t.Errorf("got %d results for edited note, want 1", len(results))
+		}
+		if err := DeleteAthleteNote(db, note.ID); err != nil {
+			t.Fatalf("delete note: %v", err)
+		}
+		if results, _ := SearchNotes(db, a.ID, false, "hips"); len(results) != 0 {
+			t.Errorf("got %d results after delete, want 0", len(results))
+		}
+	})
+
+	t.Run("query syntax is treated as text", func(t *testing.T) {
+		if _, err := SearchNotes(db, a.ID, true, `"bracing AND (`); err != nil {
+			t.Errorf("search with punctuation: %v", err)
+		}
+	})
+
+	t.Run("empty query rejected", func(t *testing.T) {
+		_, err := SearchNotes(db, a.ID, true, `  " `)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
@@ -14,6 +14,12 @@ const (
 	NotifyNoteAdded       = "note_added"
 	NotifyWorkoutLogged   = "workout_logged"
 	NotifyMagicLinkSent   = "magic_link_sent"
+	NotifyInjuryFlagged   = "injury_flagged"
+	NotifyStallDetected   = "stall_detected"
+	NotifyMissedSession   = "missed_session"
+
+	NotifyGenerationSucceeded = "generation_succeeded"
+	NotifyGenerationFailed    = "generation_failed"
 )
 
 // AllNotificationTypes lists all known notification types for preference UI.
@@ -22,8 +28,13 @@ var AllNotificationTypes = []NotificationType{
 	{Type: NotifyProgramAssigned, Label: "Program Assigned", Description: "When a new program is assigned to you"},
 	{Type: NotifyTMUpdated, Label: "Training Max Updated", Description: "When a training max is updated"},
 	{Type: NotifyNoteAdded, Label: "Coach Note Added", Description: "When a coach adds a public note"},
-	{Type: NotifyWorkoutLogged, Label: "Workout Logged", Description: "When an athlete logs a workout"},
+	{Type: NotifyWorkoutLogged, Label: "Workout Logged", Description: "When an athlete marks a workout complete"},
 	{Type: NotifyMagicLinkSent, Label: "Login Link Sent", Description: "When a login link is generated for you"},
+	{Type: NotifyInjuryFlagged, Label: "Injury Flagged", Description: "When a workout review flags a possible injury"},
+	{Type: NotifyStallDetected, Label: "Lift Stalled", Description: "When an athlete's lift stops progressing"},
+	{Type: NotifyMissedSession, Label: "Missed Session", Description: "When an athlete skips a scheduled training day"},
+	{Type: NotifyGenerationSucceeded, Label: "Program Generated", Description: "When an AI Coach program is ready to review"},
+	{Type: NotifyGenerationFailed, Label: "Program Generation Failed", Description: "When an AI Coach program generation fails"},
 }
 
 // NotificationType describes a notification type for preference UI.
@@ -93,6 +104,21 @@ func GetUnreadCount(db *sql.DB, userID int64) (int, error) {
 	return count, nil
 }
 
+// GetUnreadState returns the highest notification ID and the unread count
+// for a user in one query. Together they change whenever a notification is
+// created, read or deleted in a way that affects the unread badge, so
+// callers use them as a cheap version for conditional requests.
+func GetUnreadState(db *sql.DB, userID int64) (maxID int64, unread int, err error) {
+	err = db.QueryRow(
+		`SELECT COALESCE(MAX(id), 0), COALESCE(SUM(read = 0), 0) FROM notifications WHERE user_id = ?`,
+		userID,
+	).Scan(&maxID, &unread)
+	if err != nil {
+		return 0, 0, fmt.Errorf("models: get unread state for user %d: %w", userID, err)
+	}
+	return maxID, unread, nil
+}
+
 // GetUnreadNotifications returns up to `limit` unread notifications for a user,
 // ordered newest first. Used for toast polling.
 func GetUnreadNotifications(db *sql.DB, userID int64, limit int) ([]*Notification, error) {
@@ -189,6 +215,34 @@ func GetNotificationsSince(db *sql.DB, userID int64, since time.Time) ([]*Notifi
 	return scanNotifications(rows)
 }
 
+// DigestMaxItems caps the number of notifications bundled into one digest.
+const DigestMaxItems = 50
+
+// PendingDigest returns the notifications due in a user's next digest:
+// unread notifications after the last one digested whose type the user
+// receives externally, oldest first and at most DigestMaxItems.
+func PendingDigest(db *sql.DB, userID int64) ([]*Notification, error) {
+	rows, err := db.Query(
+		`SELECT n.id, n.user_id, n.type, n.title, n.message, n.link, n.read, n.athlete_id, n.created_at
+		 FROM notifications n
+		 JOIN notification_preferences np
+		   ON np.user_id = n.user_id AND np.type = n.type AND np.external = 1
+		 LEFT JOIN user_preferences up ON up.user_id = n.user_id
+		 WHERE n.user_id = ? AND n.read = 0
+		   AND (up.last_digest_at IS NULL OR n.created_at > up.last_digest_at
+		        OR (n.created_at = up.last_digest_at AND n.id > COALESCE(up.last_digest_id, 0)))
+		 ORDER BY n.created_at, n.id
+		 LIMIT ?`,
+		userID, DigestMaxItems,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: pending digest for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
 // --- Notification Preferences ---
 
 // GetNotificationPreference returns the preference for a user+type, or defaults.
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrPrivateWebhookAddress is returned when a webhook URL points at a
+// loopback, private, or link-local address.
+var ErrPrivateWebhookAddress = errors.New("webhook URL points to a private or local address")
+
+// NotificationWebhook is a user's outbound webhook for external
+// notifications (Discord, Slack, Home Assistant, etc.).
+type NotificationWebhook struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Secret    string // Decrypted HMAC signing secret; empty when unset
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ValidateWebhookURL checks that raw is an absolute http or https URL whose
+// host is not a disallowed address (see WebhookAddrAllowed) or localhost.
+// Host names are only checked again when a webhook is delivered, since they
+// may resolve differently by then.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("models: webhook URL must be an http or https URL: %w", ErrInvalidInput)
+	}
+	host := strings.ToLower(u.Hostname())
+	if addr, err := netip.ParseAddr(host); err == nil && !WebhookAddrAllowed(addr) {
+		return ErrPrivateWebhookAddress
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateWebhookAddress
+	}
+	return nil
+}
+
+// WebhookAddrAllowed reports whether webhooks may be delivered to addr.
+// Loopback, private (RFC 1918 and fc00::/7), link-local — including the
+// 169.254.169.254 cloud metadata endpoint — multicast, and unspecified
+// addresses are refused so a webhook can't reach the server's own network.
+func WebhookAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() && !addr.IsMulticast() && !addr.IsUnspecified()
+}
+
+// GetNotificationWebhook returns a user's webhook, or ErrNotFound if none is
+// configured.
+func GetNotificationWebhook(db *sql.DB, userID int64) (*NotificationWebhook, error) {
+	h := &NotificationWebhook{}
+	var secret sql.NullString
+	err := db.QueryRow(
+		`SELECT id, user_id, url, secret, created_at, updated_at
+		 FROM notification_webhooks WHERE user_id = ?`, userID,
+	).Scan(&h.ID, &h.UserID, &h.URL, &secret, &h.CreatedAt, &h.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get notification webhook for user %d: %w", userID, err)
+	}
+
+	if strings.HasPrefix(secret.String, "enc:") {
+		h.Secret, err = decryptValue(secret.String[4:])
+		if err != nil {
+			return nil, fmt.Errorf("models: decrypt webhook secret for user %d: %w", userID, err)
+		}
+	}
+	return h, nil
+}
+
+// SetNotificationWebhook creates or replaces a user's webhook. The URL must
+// pass ValidateWebhookURL. A non-empty secret is encrypted the same way as
+// sensitive app settings, so REPLOG_SECRET_KEY must be available.
+func SetNotificationWebhook(db *sql.DB, userID int64, rawURL, secret string) error {
+	rawURL = strings.TrimSpace(rawURL)
+	if err := ValidateWebhookURL(rawURL); err != nil {
+		return err
+	}
+
+	var storeSecret sql.NullString
+	if secret != "" {
+		encrypted, err := encryptValue(secret)
+		if err != nil {
+			return fmt.Errorf("models: encrypt webhook secret for user %d: %w", userID, err)
+		}
+		storeSecret = sql.NullString{String: "enc:" + encrypted, Valid: true}
+	}
+
+	_, err := db.Exec(
+		`INSERT INTO notification_webhooks (user_id, url, secret) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET url = excluded.url, secret = excluded.secret`,
+		userID, rawURL, storeSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("models: set notification webhook for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteNotificationWebhook removes a user's webhook. Deleting a webhook
+// that doesn't exist is not an error.
+func DeleteNotificationWebhook(db *sql.DB, userID int64) error {
+	if _, err := db.Exec(`DELETE FROM notification_webhooks WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("models: delete notification webhook for user %d: %w", userID, err)
+	}
+	return nil
+}
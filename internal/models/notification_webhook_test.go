@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, raw := range []string{"https://discord.com/api/webhooks/1/abc", "http://homeassistant.local:8123/api/webhook/replog"} {
+		if err := ValidateWebhookURL(raw); err != nil {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want nil", raw, err)
+		}
+	}
+	for _, raw := range []string{"", "discord.com/webhook", "ftp://example.com/hook", "javascript:alert(1)", "https://"} {
+		if err := ValidateWebhookURL(raw); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ErrInvalidInput", raw, err)
+		}
+	}
+}
+
+func TestValidateWebhookURL_PrivateAddresses(t *testing.T) {
+	for _, raw := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://api.localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://172.16.3.4/hook",
+		"http://192.168.1.10:8123/api/webhook/replog",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if err := ValidateWebhookURL(raw); !errors.Is(err, ErrPrivateWebhookAddress) {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ErrPrivateWebhookAddress", raw, err)
+		}
+	}
+	if err := ValidateWebhookURL("https://93.184.216.34/hook"); err != nil {
+		t.Errorf("public address rejected: %v", err)
+	}
+}
+
+func TestNotificationWebhookCRUD(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "hookuser", "", "password123", "", true, false, sql.NullInt64{})
+
+	if _, err := GetNotificationWebhook(db, u.ID); err != ErrNotFound {
+		t.Fatalf("get before set: err = %v, want ErrNotFound", err)
+	}
+
+	if err := SetNotificationWebhook(db, u.ID, "https://example.com/hook", "s3cret"); err != nil {
+		t.Fatalf("set webhook: %v", err)
+	}
+	hook, err := GetNotificationWebhook(db, u.ID)
+	if err != nil {
+		t.Fatalf("get webhook: %v", err)
+	}
+	if hook.URL != "https://example.com/hook" || hook.Secret != "s3cret" {
+		t.Errorf("webhook = %+v, want URL and decrypted secret", hook)
+	}
+
+	// The secret is encrypted at rest.
+	var stored string
+	db.QueryRow(`SELECT secret FROM notification_webhooks WHERE user_id = ?`, u.ID).Scan(&stored)
+	if !strings.HasPrefix(stored, "enc:") || strings.Contains(stored, "s3cret") {
+		t.Errorf("stored secret = %q, want encrypted", stored)
+	}
+
+	// Replacing without a secret clears it.
+	if err := SetNotificationWebhook(db, u.ID, "https://example.com/other", ""); err != nil {
+		t.Fatalf("replace webhook: %v", err)
+	}
+	hook, _ = GetNotificationWebhook(db, u.ID)
+	if hook.URL != "https://example.com/other" || hook.Secret != "" {
+		t.Errorf("webhook = %+v, want new URL and no secret", hook)
+	}
+
+	if err := SetNotificationWebhook(db, u.ID, "ftp://example.com", ""); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("invalid URL: err = %v, want ErrInvalidInput", err)
+	}
+
+	if err := DeleteNotificationWebhook(db, u.ID); err != nil {
+		t.Fatalf("delete webhook: %v", err)
+	}
+	if _, err := GetNotificationWebhook(db, u.ID); err != ErrNotFound {
+		t.Errorf("get after delete: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EstimateOneRepMax returns an estimated one-rep max using the Epley formula:
+// weight × (1 + reps/30). A single rep returns the weight unchanged. Returns 0
+// for non-positive reps or weight.
+func EstimateOneRepMax(reps int, weight float64) float64 {
+	if reps <= 0 || weight <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+	return weight * (1 + float64(reps)/30.0)
+}
+
+// OneRepMaxEstimate is the set that produced a best estimated 1RM.
+type OneRepMaxEstimate struct {
+	Value  float64
+	Weight float64
+	Reps   int
+	Date   string // YYYY-MM-DD
+}
+
+// OneRepMaxSummary holds the all-time and recent best estimated 1RM for an
+// exercise. Either field is nil when no qualifying sets exist.
+type OneRepMaxSummary struct {
+	AllTime    *OneRepMaxEstimate
+	Last30Days *OneRepMaxEstimate
+}
+
+// BestEstimated1RM scans an athlete's logged sets for an exercise and returns
+// the best all-time and last-30-day estimated 1RM. Bodyweight and zero-weight
+// sets are skipped, as are timed (seconds) and distance sets. Each-side reps
+// are per side, which is what a single-limb 1RM should use, so they are not
+// doubled here even though volume counts both sides.
+func BestEstimated1RM(db *sql.DB, athleteID, exerciseID int64) (*OneRepMaxSummary, error) {
+	rows, err := db.Query(`
+		SELECT ws.reps, ws.weight, w.date
+		FROM workout_sets ws
+		JOIN workouts w ON w.id = ws.workout_id
+		WHERE w.athlete_id = ? AND ws.exercise_id = ?
+		  AND ws.weight IS NOT NULL AND ws.weight > 0 AND ws.reps > 0
+		  AND ws.rep_type IN ('reps', 'each_side')`, athleteID, exerciseID)
+	if err != nil {
+		return nil, fmt.Errorf("models: best e1rm for athlete %d exercise %d: %w", athleteID, exerciseID, err)
+	}
+	defer rows.Close()
+
+	cutoff := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	summary := &OneRepMaxSummary{}
+	for rows.Next() {
+		var reps int
+		var weight float64
+		var date string
+		if err := rows.Scan(&reps, &weight, &date); err != nil {
+			return nil, fmt.Errorf("models: scan e1rm set: %w", err)
+		}
+		date = normalizeDate(date)
+		est := &OneRepMaxEstimate{
+			Value:  EstimateOneRepMax(reps, weight),
+			Weight: weight,
+			Reps:   reps,
+			Date:   date,
+		}
+		if summary.AllTime == nil || est.Value > summary.AllTime.Value {
+			summary.AllTime = est
+		}
+		if date >= cutoff && (summary.Last30Days == nil || est.Value > summary.Last30Days.Value) {
+			summary.Last30Days = est
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate e1rm sets: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		name   string
+		reps   int
+		weight float64
+		want   float64
+	}{
+		{"single", 1, 315, 315},
+		{"five reps", 5, 225, 262.5},
+		{"ten reps", 10, 150, 200},
+		{"zero weight", 5, 0, 0},
+		{"zero reps", 0, 225, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateOneRepMax(tt.reps, tt.weight); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("EstimateOneRepMax(%d, %v) = %v, want %v", tt.reps, tt.weight, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestEstimated1RM(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "E1RM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	plank, _ := CreateExercise(db, "Plank", "", "", "", "", 0)
+
+	recent := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	old := time.Now().AddDate(0, 0, -90).Format("2006-01-02")
+
+	w1, _ := CreateWorkout(db, a.ID, old, "", 0)
+	AddSet(db, w1.ID, squat.ID, 3, 300, 0, "", "", "") // 330
+	AddSet(db, w1.ID, squat.ID, 20, 0, 0, "", "", "")  // bodyweight, skipped
+	w2, _ := CreateWorkout(db, a.ID, recent, "", 0)
+	AddSet(db, w2.ID, squat.ID, 5, 240, 0, "", "", "")        // 280
+	AddSet(db, w2.ID, squat.ID, 60, 45, 0, "seconds", "", "") // timed, skipped
+	AddSet(db, w2.ID, plank.ID, 60, 25, 0, "seconds", "", "")
+
+	t.Run("all-time and recent", func(t *testing.T) {
+		got, err := BestEstimated1RM(db, a.ID, squat.ID)
+		if err != nil {
+			t.Fatalf("best e1rm: %v", err)
+		}
+		if got.AllTime == nil || got.AllTime.Value != 330 || got.AllTime.Date != old {
+			t.Errorf("all-time = %+v, want 330 on %s", got.AllTime, old)
+		}
+		if got.Last30Days == nil || got.Last30Days.Value != 280 || got.Last30Days.Reps != 5 {
+			t.Errorf("last 30 days = %+v, want 280 from 240x5", got.Last30Days)
+		}
+	})
+
+	t.Run("timed exercise has no estimate", func(t *testing.T) {
+		got, err := BestEstimated1RM(db, a.ID, plank.ID)
+		if err != nil {
+			t.Fatalf("best e1rm: %v", err)
+		}
+		if got.AllTime != nil || got.Last30Days != nil {
+			t.Errorf("summary = %+v, want empty", got)
+		}
+	})
+}
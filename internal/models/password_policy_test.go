@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidatePasswordStrength(t *testing.T) {
+	db := testDB(t)
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  bool
+	}{
+		{"long enough", "coach", "correct horse", false},
+		{"too short", "coach", "short1", true},
+		{"same as username", "liftinglarry", "LiftingLarry", true},
+		{"over bcrypt limit", "coach", strings.Repeat("a", 73), true},
+		{"multibyte counts characters", "coach", "ééééééé", true},
+	}
+	for _, tt := range tests {
+		err := ValidatePasswordStrength(db, tt.username, tt.password)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrWeakPassword) {
+			t.Errorf("%s: err = %v, want ErrWeakPassword", tt.name, err)
+		}
+	}
+
+	SetSetting(db, "security.password_min_length", "12")
+	err := ValidatePasswordStrength(db, "coach", "elevenchars")
+	var pe *PasswordPolicyError
+	if !errors.As(err, &pe) || pe.Reason != "Password must be at least 12 characters." {
+		t.Errorf("min length 12: err = %v", err)
+	}
+
+	SetSetting(db, "security.password_complexity", "mixed")
+	if err := ValidatePasswordStrength(db, "coach", "onlylowercaseletters"); err == nil {
+		t.Error("mixed complexity should reject a single character class")
+	}
+	if err := ValidatePasswordStrength(db, "coach", "Lower and UPPER 123"); err != nil {
+		t.Errorf("mixed complexity: %v", err)
+	}
+}
+
+func TestGetPasswordMinLength(t *testing.T) {
+	db := testDB(t)
+
+	if got := GetPasswordMinLength(db); got != 8 {
+		t.Errorf("default = %d, want 8", got)
+	}
+	for _, v := range []string{"4", "500", "abc"} {
+		SetSetting(db, "security.password_min_length", v)
+		if got := GetPasswordMinLength(db); got != 8 {
+			t.Errorf("invalid %q: got %d, want fallback 8", v, got)
+		}
+	}
+}
+
+func TestValidatePasswordStrength_BreachCheck(t *testing.T) {
+	db := testDB(t)
+	SetSetting(db, "security.password_breach_check", "true")
+
+	// SHA-1 of "password123" is CBFDAC6008F9CAB4083784CBD1874F76618D2A97.
+	var gotPath, gotPadding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotPadding = r.URL.Path, r.Header.Get("Add-Padding")
+		fmt.Fprint(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\nC6008F9CAB4083784CBD1874F76618D2A97:250000\r\n")
+	}))
+	defer srv.Close()
+
+	orig := pwnedRangeURL
+	pwnedRangeURL = srv.URL + "/range/"
+	defer func() { pwnedRangeURL = orig }()
+
+	if err := ValidatePasswordStrength(db, "coach", "password123"); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("breached password: err = %v, want ErrWeakPassword", err)
+	}
+	if gotPath != "/range/CBFDA" {
+		t.Errorf("range path = %q, want only the 5-character prefix", gotPath)
+	}
+	if gotPadding != "true" {
+		t.Error("range query should request padding")
+	}
+	if err := ValidatePasswordStrength(db, "coach", "a much less common passphrase"); err != nil {
+		t.Errorf("unbreached password: %v", err)
+	}
+
+	// Unreachable service fails open.
+	srv.Close()
+	if err := ValidatePasswordStrength(db, "coach", "password123"); err != nil {
+		t.Errorf("service down: err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PasswordResetTokenLifetime is how long an emailed reset link stays valid.
+const PasswordResetTokenLifetime = 30 * time.Minute
+
+// CreatePasswordResetToken issues a single-use password reset token for the
+// user and returns it. Any earlier unused tokens for the user stop working,
+// so only the most recent email's link can be used.
+func CreatePasswordResetToken(db *sql.DB, userID int64) (string, error) {
+	token, err := generateToken(32) // 256-bit token
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("models: begin reset token tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM password_reset_tokens WHERE user_id = ? AND used_at IS NULL`, userID); err != nil {
+		return "", fmt.Errorf("models: replace reset tokens for user %d: %w", userID, err)
+	}
+	_, err = tx.Exec(
+		`INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)`,
+		userID, hashResetToken(token), time.Now().Add(PasswordResetTokenLifetime),
+	)
+	if err != nil {
+		return "", fmt.Errorf("models: create reset token for user %d: %w", userID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("models: commit reset token: %w", err)
+	}
+	return token, nil
+}
+
+// ValidatePasswordResetToken returns the user a reset token belongs to.
+// Returns ErrNotFound if the token is unknown, used or expired.
+func ValidatePasswordResetToken(db *sql.DB, token string) (*User, error) {
+	var userID int64
+	err := db.QueryRow(
+		`SELECT user_id FROM password_reset_tokens
+		 WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?`,
+		hashResetToken(token), time.Now(),
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: validate reset token: %w", err)
+	}
+	return GetUserByID(db, userID)
+}
+
+// ResetPassword uses a reset token to set the user's new password. The token
+// is consumed in the same transaction, so it works at most once. Returns
+// ErrNotFound if the token is unknown, used or expired.
+func ResetPassword(db *sql.DB, token, newPassword string) (*User, error) {
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin reset password tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRow(
+		`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+		 WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		 RETURNING user_id`,
+		hashResetToken(token), time.Now(),
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: consume reset token: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE users SET password_hash = ? WHERE id = ?`, hash, userID); err != nil {
+		return nil, fmt.Errorf("models: reset password for user %d: %w", userID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit reset password: %w", err)
+	}
+	return GetUserByID(db, userID)
+}
+
+// DeleteExpiredPasswordResetTokens removes reset tokens that have expired or
+// been used. Returns the number of tokens deleted.
+func DeleteExpiredPasswordResetTokens(db *sql.DB) (int64, error) {
+	result, err := db.Exec(
+		`DELETE FROM password_reset_tokens WHERE used_at IS NOT NULL OR expires_at < ?`,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete expired reset tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// hashResetToken returns the stored form of a reset token.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
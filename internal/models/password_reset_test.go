@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPasswordResetToken(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "resetme", "", "password123", "resetme@example.com", false, false, sql.NullInt64{})
+
+	first, err := CreatePasswordResetToken(db, u.ID)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	token, err := CreatePasswordResetToken(db, u.ID)
+	if err != nil {
+		t.Fatalf("create second token: %v", err)
+	}
+
+	var stored int
+	db.QueryRow(`SELECT COUNT(*) FROM password_reset_tokens WHERE token_hash = ?`, token).Scan(&stored)
+	if stored != 0 {
+		t.Error("token should not be stored in plain text")
+	}
+
+	if _, err := ValidatePasswordResetToken(db, first); !errors.Is(err, ErrNotFound) {
+		t.Errorf("superseded token: err = %v, want ErrNotFound", err)
+	}
+	got, err := ValidatePasswordResetToken(db, token)
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+	if got.ID != u.ID {
+		t.Errorf("token user = %d, want %d", got.ID, u.ID)
+	}
+
+	if _, err := ResetPassword(db, token, "new-password-1"); err != nil {
+		t.Fatalf("reset password: %v", err)
+	}
+	if _, err := Authenticate(db, "resetme", "new-password-1"); err != nil {
+		t.Errorf("new password should work: %v", err)
+	}
+	if _, err := ResetPassword(db, token, "another-password"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("reused token: err = %v, want ErrNotFound", err)
+	}
+
+	if n, err := DeleteExpiredPasswordResetTokens(db); err != nil || n != 1 {
+		t.Errorf("delete used tokens = %d, %v; want 1", n, err)
+	}
+}
+
+func TestPasswordResetToken_Expired(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "slow", "", "password123", "slow@example.com", false, false, sql.NullInt64{})
+	token, _ := CreatePasswordResetToken(db, u.ID)
+	db.Exec(`UPDATE password_reset_tokens SET expires_at = ?`, time.Now().Add(-time.Minute))
+
+	if _, err := ValidatePasswordResetToken(db, token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("validate expired token: err = %v, want ErrNotFound", err)
+	}
+	if _, err := ResetPassword(db, token, "new-password-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("reset with expired token: err = %v, want ErrNotFound", err)
+	}
+	if _, err := Authenticate(db, "slow", "password123"); err != nil {
+		t.Errorf("old password should still work: %v", err)
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "mailer", "", "password123", "Mailer@Example.com", false, false, sql.NullInt64{})
+
+	got, err := GetUserByEmail(db, "mailer@example.com")
+	if err != nil {
+		t.Fatalf("get user by email: %v", err)
+	}
+	if got.ID != u.ID {
+		t.Errorf("user = %d, want %d", got.ID, u.ID)
+	}
+	if _, err := GetUserByEmail(db, "nobody@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown email: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PersonalRecord is a logged set that beat the athlete's prior best for its
+// exercise.
+type PersonalRecord struct {
+	ID           int64
+	AthleteID    int64
+	ExerciseID   int64
+	ExerciseName string
+	WorkoutID    int64
+	SetID        int64
+	Reps         int
+	Weight       float64
+	Estimated1RM float64
+	PreviousBest sql.NullFloat64 // prior best weight for this many reps; null for an e1RM-only PR
+	Date         string          // YYYY-MM-DD of the workout
+}
+
+// prSet is a qualifying set considered by DetectPRs.
+type prSet struct {
+	id, workoutID, exerciseID int64
+	reps                      int
+	weight                    float64
+	date                      string
+}
+
+// prHistory tracks an athlete's bests for one exercise as workouts are
+// replayed in order.
+type prHistory struct {
+	byReps   map[int]float64 // heaviest weight lifted for exactly this many reps
+	bestE1RM float64
+}
+
+// bestFor returns the heaviest prior weight lifted for at least reps reps. A
+// set is also a best at every lower rep count, so 8 × 200 counts as a 5-rep
+// best of 200.
+func (h *prHistory) bestFor(reps int) (float64, bool) {
+	best, found := 0.0, false
+	for r, w := range h.byReps {
+		if r >= reps && (!found || w > best) {
+			best, found = w, true
+		}
+	}
+	return best, found
+}
+
+// DetectPRs recomputes the personal records for a workout and every later
+// workout of the athlete, replacing any previously recorded. A set is a PR
+// when its weight beats the prior best for that exercise at equal-or-fewer
+// reps, or its estimated 1RM beats the prior best estimate. The first session
+// of an exercise sets a baseline and is never a PR. Within a workout only the
+// top set counts: a set matched or beaten on both weight and reps by another
+// set in the same workout is skipped. Only weighted rep sets qualify, as in
+// BestEstimated1RM. Returns the workout's records and ErrNotFound if the
+// workout does not belong to the athlete.
+func DetectPRs(db *sql.DB, athleteID, workoutID int64) ([]*PersonalRecord, error) {
+	var date string
+	err := db.QueryRow(`SELECT date(date) FROM workouts WHERE id = ? AND athlete_id = ?`,
+		workoutID, athleteID).Scan(&date)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get workout %d for PR detection: %w", workoutID, err)
+	}
+
+	rows, err := db.Query(`
+		SELECT ws.id, ws.workout_id, ws.exercise_id, ws.reps, ws.weight, date(w.date)
+		FROM workout_sets ws
+		JOIN workouts w ON w.id = ws.workout_id
+		WHERE w.athlete_id = ?
+		  AND ws.weight IS NOT NULL AND ws.weight > 0 AND ws.reps > 0
+		  AND ws.rep_type IN ('reps', 'each_side')
+		ORDER BY date(w.date), w.id, ws.id`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list sets for PR detection athlete %d: %w", athleteID, err)
+	}
+	var sets []prSet
+	for rows.Next() {
+		var s prSet
+		if err := rows.Scan(&s.id, &s.workoutID, &s.exerciseID, &s.reps, &s.weight, &s.date); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("models: scan PR set: %w", err)
+		}
+		sets = append(sets, s)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("models: iterate PR sets: %w", err)
+	}
+	rows.Close()
+
+	// Replay workouts in order, checking each against the bests built up by
+	// the workouts before it.
+	history := make(map[int64]*prHistory)
+	var records []*PersonalRecord
+	for start := 0; start < len(sets); {
+		end := start
+		for end < len(sets) && sets[end].workoutID == sets[start].workoutID {
+			end++
+		}
+		workout := sets[start:end]
+		if w := workout[0]; w.date > date || (w.date == date && w.workoutID >= workoutID) {
+			records = append(records, workoutPRs(athleteID, workout, history)...)
+		}
+		for _, s := range workout {
+			h := history[s.exerciseID]
+			if h == nil {
+				h = &prHistory{byReps: make(map[int]float64)}
+				history[s.exerciseID] = h
+			}
+			if s.weight > h.byReps[s.reps] {
+				h.byReps[s.reps] = s.weight
+			}
+			if e := EstimateOneRepMax(s.reps, s.weight); e > h.bestE1RM {
+				h.bestE1RM = e
+			}
+		}
+		start = end
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin PR detection tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM personal_records
+		WHERE workout_id IN (
+		    SELECT id FROM workouts
+		    WHERE athlete_id = ? AND (date(date) > ? OR (date(date) = ? AND id >= ?))
+		)`, athleteID, date, date, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("models: clear PRs from workout %d: %w", workoutID, err)
+	}
+
+	var current []*PersonalRecord
+	for _, pr := range records {
+		res, err := tx.Exec(`
+			INSERT INTO personal_records (athlete_id, exercise_id, workout_id, set_id, reps, weight, estimated_1rm, previous_best)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			pr.AthleteID, pr.ExerciseID, pr.WorkoutID, pr.SetID, pr.Reps, pr.Weight, pr.Estimated1RM, pr.PreviousBest)
+		if err != nil {
+			return nil, fmt.Errorf("models: record PR for set %d: %w", pr.SetID, err)
+		}
+		pr.ID, _ = res.LastInsertId()
+		if pr.WorkoutID == workoutID {
+			current = append(current, pr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit PR detection: %w", err)
+	}
+	return current, nil
+}
+
+// workoutPRs returns the sets in one workout that beat history.
+func workoutPRs(athleteID int64, workout []prSet, history map[int64]*prHistory) []*PersonalRecord {
+	var records []*PersonalRecord
+	for i, s := range workout {
+		h := history[s.exerciseID]
+		if h == nil || dominatedInWorkout(workout, i) {
+			continue
+		}
+		e1rm := EstimateOneRepMax(s.reps, s.weight)
+		prev, hasPrev := h.bestFor(s.reps)
+		if !(hasPrev && s.weight > prev) && e1rm <= h.bestE1RM {
+			continue
+		}
+		pr := &PersonalRecord{
+			AthleteID:    athleteID,
+			ExerciseID:   s.exerciseID,
+			WorkoutID:    s.workoutID,
+			SetID:        s.id,
+			Reps:         s.reps,
+			Weight:       s.weight,
+			Estimated1RM: e1rm,
+			Date:         s.date,
+		}
+		if hasPrev {
+			pr.PreviousBest = sql.NullFloat64{Float64: prev, Valid: true}
+		}
+		records = append(records, pr)
+	}
+	return records
+}
+
+// dominatedInWorkout reports whether another set of the same exercise in the
+// workout matched or beat workout[i] on both weight and reps. Of identical
+// sets, the first logged is kept.
+func dominatedInWorkout(workout []prSet, i int) bool {
+	s := workout[i]
+	for j, o := range workout {
+		if j == i || o.exerciseID != s.exerciseID || o.weight < s.weight || o.reps < s.reps {
+			continue
+		}
+		if o.weight > s.weight || o.reps > s.reps || j < i {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPersonalRecords returns an athlete's personal records, newest first.
+func ListPersonalRecords(db *sql.DB, athleteID int64) ([]*PersonalRecord, error) {
+	rows, err := db.Query(`
+		SELECT pr.id, pr.athlete_id, pr.exercise_id, e.name, pr.workout_id, pr.set_id,
+		       pr.reps, pr.weight, pr.estimated_1rm, pr.previous_best, w.date
+		FROM personal_records pr
+		JOIN exercises e ON e.id = pr.exercise_id
+		JOIN workouts w ON w.id = pr.workout_id
+		WHERE pr.athlete_id = ?
+		ORDER BY date(w.date) DESC, pr.id DESC`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list PRs for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var records []*PersonalRecord
+	for rows.Next() {
+		pr := &PersonalRecord{}
+		if err := rows.Scan(&pr.ID, &pr.AthleteID, &pr.ExerciseID, &pr.ExerciseName, &pr.WorkoutID, &pr.SetID,
+			&pr.Reps, &pr.Weight, &pr.Estimated1RM, &pr.PreviousBest, &pr.Date); err != nil {
+			return nil, fmt.Errorf("models: scan PR: %w", err)
+		}
+		pr.Date = normalizeDate(pr.Date)
+		records = append(records, pr)
+	}
+	return records, rows.Err()
+}
+
+// ListWorkoutPRSetIDs returns the IDs of a workout's sets that are personal
+// records, for badging them on the workout page.
+func ListWorkoutPRSetIDs(db *sql.DB, workoutID int64) (map[int64]bool, error) {
+	rows, err := db.Query(`SELECT set_id FROM personal_records WHERE workout_id = ?`, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list PR sets for workout %d: %w", workoutID, err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("models: scan PR set id: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// prCountsByWorkout returns the number of personal records set in each of
+// the given workouts. Workouts without PRs are absent from the map.
+func prCountsByWorkout(db *sql.DB, workoutIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	if len(workoutIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := make([]string, len(workoutIDs))
+	args := make([]any, len(workoutIDs))
+	for i, id := range workoutIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	rows, err := db.Query(`
+		SELECT workout_id, COUNT(*)
+		FROM personal_records
+		WHERE workout_id IN (`+strings.Join(placeholders, ", ")+`)
+		GROUP BY workout_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("models: count PRs by workout: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("models: scan PR count: %w", err)
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+)
+
+func TestDetectPRs(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "PR Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+
+	w1, _ := CreateWorkout(db, a.ID, "2026-03-02", "", 0)
+	AddSet(db, w1.ID, squat.ID, 5, 200, 0, "", "", "")
+	AddSet(db, w1.ID, squat.ID, 8, 180, 0, "", "", "")
+
+	t.Run("first session is a baseline", func(t *testing.T) {
+		prs, err := DetectPRs(db, a.ID, w1.ID)
+		if err != nil {
+			t.Fatalf("detect PRs: %v", err)
+		}
+		if len(prs) != 0 {
+			t.Errorf("got %d PRs, want 0 for the first session", len(prs))
+		}
+	})
+
+	w2, _ := CreateWorkout(db, a.ID, "2026-03-05", "", 0)
+	warmup, _ := AddSet(db, w2.ID, squat.ID, 5, 205, 0, "", "", "")
+	top, _ := AddSet(db, w2.ID, squat.ID, 5, 210, 0, "", "", "")
+	AddSet(db, w2.ID, squat.ID, 10, 150, 0, "", "", "")        // no 10-rep best, e1RM below 250
+	AddSet(db, w2.ID, squat.ID, 60, 300, 0, "seconds", "", "") // timed, skipped
+	AddSet(db, w2.ID, bench.ID, 5, 135, 0, "", "", "")         // first bench session
+
+	t.Run("beats prior best at equal-or-fewer reps", func(t *testing.T) {
+		prs, err := DetectPRs(db, a.ID, w2.ID)
+		if err != nil {
+			t.Fatalf("detect PRs: %v", err)
+		}
+		if len(prs) != 1 {
+			t.Fatalf("got %d PRs, want 1: %+v", len(prs), prs)
+		}
+		pr := prs[0]
+		if pr.SetID != top.ID || pr.Weight != 210 || pr.Reps != 5 {
+			t.Errorf("PR = %+v, want set %d (5 × 210)", pr, top.ID)
+		}
+		if !pr.PreviousBest.Valid || pr.PreviousBest.Float64 != 200 {
+			t.Errorf("previous best = %+v, want 200", pr.PreviousBest)
+		}
+		if math.Abs(pr.Estimated1RM-245) > 0.001 {
+			t.Errorf("e1RM = %v, want 245", pr.Estimated1RM)
+		}
+
+		ids, err := ListWorkoutPRSetIDs(db, w2.ID)
+		if err != nil {
+			t.Fatalf("list PR set ids: %v", err)
+		}
+		if !ids[top.ID] || ids[warmup.ID] {
+			t.Errorf("PR set ids = %v, want only %d", ids, top.ID)
+		}
+	})
+
+	t.Run("more reps count toward fewer", func(t *testing.T) {
+		// The 8 × 180 set makes 180 the 6-rep best, and the e1RM of 6 × 180
+		// (216) does not beat 5 × 210 (245).
+		w3, _ := CreateWorkout(db, a.ID, "2026-03-09", "", 0)
+		AddSet(db, w3.ID, squat.ID, 6, 180, 0, "", "", "")
+		prs, err := DetectPRs(db, a.ID, w3.ID)
+		if err != nil {
+			t.Fatalf("detect PRs: %v", err)
+		}
+		if len(prs) != 0 {
+			t.Errorf("got %d PRs, want 0: %+v", len(prs), prs)
+		}
+	})
+
+	t.Run("e1RM PR without a rep best", func(t *testing.T) {
+		w4, _ := CreateWorkout(db, a.ID, "2026-03-12", "", 0)
+		set, _ := AddSet(db, w4.ID, squat.ID, 12, 190, 0, "", "", "") // e1RM 266
+		prs, err := DetectPRs(db, a.ID, w4.ID)
+		if err != nil {
+			t.Fatalf("detect PRs: %v", err)
+		}
+		if len(prs) != 1 || prs[0].SetID != set.ID {
+			t.Fatalf("PRs = %+v, want set %d", prs, set.ID)
+		}
+		if prs[0].PreviousBest.Valid {
+			t.Errorf("previous best = %+v, want null for an e1RM-only PR", prs[0].PreviousBest)
+		}
+	})
+
+	t.Run("editing an earlier workout rechecks later ones", func(t *testing.T) {
+		// Raising the baseline above 210 means week two no longer set a PR.
+		sets, _ := ListSetsByWorkout(db, w1.ID)
+		UpdateSet(db, sets[0].Sets[0].ID, 5, 215, 0, "")
+		if _, err := DetectPRs(db, a.ID, w1.ID); err != nil {
+			t.Fatalf("detect PRs: %v", err)
+		}
+		ids, err := ListWorkoutPRSetIDs(db, w2.ID)
+		if err != nil {
+			t.Fatalf("list PR set ids: %v", err)
+		}
+		if len(ids) != 0 {
+			t.Errorf("week two PR set ids = %v, want none", ids)
+		}
+	})
+
+	t.Run("history newest first", func(t *testing.T) {
+		records, err := ListPersonalRecords(db, a.ID)
+		if err != nil {
+			t.Fatalf("list PRs: %v", err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("got %d records, want 1: %+v", len(records), records)
+		}
+		if records[0].ExerciseName != "Squat" || records[0].Date != "2026-03-12" {
+			t.Errorf("record = %+v, want Squat on 2026-03-12", records[0])
+		}
+	})
+
+	t.Run("journal counts workout PRs", func(t *testing.T) {
+		entries, err := ListJournalEntries(db, a.ID, true, 0)
+		if err != nil {
+			t.Fatalf("list journal: %v", err)
+		}
+		for _, e := range entries {
+			if e.Type != "workout" {
+				continue
+			}
+			want := 0
+			if normalizeDate(e.Date) == "2026-03-12" {
+				want = 1
+			}
+			if e.PRs != want {
+				t.Errorf("journal workout %s PRs = %d, want %d", e.Date, e.PRs, want)
+			}
+		}
+	})
+
+	t.Run("workout of another athlete", func(t *testing.T) {
+		other, _ := CreateAthlete(db, "Other", "", "", "", "", "", "", sql.NullInt64{}, true)
+		if _, err := DetectPRs(db, other.ID, w1.ID); err != ErrNotFound {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+}
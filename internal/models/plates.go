@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultPlates is the plate set assumed when an athlete has none configured.
+var DefaultPlates = []float64{45, 35, 25, 10, 5, 2.5}
+
+// PlateCount is the number of one plate size to load on each side of the bar.
+type PlateCount struct {
+	Weight float64
+	Count  int
+}
+
+// PlateBreakdown computes the plates to load per side to reach total on a
+// bar of the given weight, using the available plate sizes largest-first.
+// The second return value is the weight (across both sides) that could not
+// be loaded with the available plates. If total is below the bar, no plates
+// are returned and the remainder is negative.
+func PlateBreakdown(total, bar float64, available []float64) ([]PlateCount, float64) {
+	if total < bar {
+		return nil, total - bar
+	}
+
+	plates := slices.Clone(available)
+	slices.Sort(plates)
+	slices.Reverse(plates)
+
+	perSide := (total - bar) / 2
+	var result []PlateCount
+	for _, p := range plates {
+		if p <= 0 {
+			continue
+		}
+		// Small epsilon guards against float drift on fractional plates.
+		n := int(math.Floor(perSide/p + 1e-9))
+		if n == 0 {
+			continue
+		}
+		result = append(result, PlateCount{Weight: p, Count: n})
+		perSide -= float64(n) * p
+	}
+	remainder := math.Round(perSide*2*100) / 100
+	return result, remainder
+}
+
+// ParsePlates parses a comma-separated list of plate weights. Blank entries
+// are ignored; non-numeric or non-positive entries return ErrInvalidInput.
+func ParsePlates(s string) ([]float64, error) {
+	var plates []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("models: invalid plate weight %q: %w", part, ErrInvalidInput)
+		}
+		plates = append(plates, v)
+	}
+	return plates, nil
+}
+
+// FormatPlates renders a plate list in the comma-separated form stored on
+// the athlete.
+func FormatPlates(plates []float64) string {
+	parts := make([]string, len(plates))
+	for i, p := range plates {
+		parts[i] = strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatPlatesIn renders a plate list (stored in lbs) converted to unit,
+// rounded to 0.01 so fractional kg plates such as 1.25 survive a round trip
+// through the athlete form.
+func FormatPlatesIn(plates []float64, unit string) string {
+	parts := make([]string, len(plates))
+	for i, p := range plates {
+		v := math.Round(ConvertWeight(p, "lbs", unit)*100) / 100
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AvailablePlates returns the athlete's configured plate set, falling back
+// to DefaultPlates.
+func (a *Athlete) AvailablePlates() []float64 {
+	if a.Plates.Valid {
+		if plates, err := ParsePlates(a.Plates.String); err == nil && len(plates) > 0 {
+			return plates
+		}
+	}
+	return DefaultPlates
+}
+
+// SetAthletePlates updates the plate set available to an athlete. Pass an
+// empty slice to clear it and use DefaultPlates.
+func SetAthletePlates(db *sql.DB, id int64, plates []float64) error {
+	var val sql.NullString
+	if len(plates) > 0 {
+		val = sql.NullString{String: FormatPlates(plates), Valid: true}
+	}
+	result, err := db.Exec(`UPDATE athletes SET plates = ? WHERE id = ?`, val, id)
+	if err != nil {
+		return fmt.Errorf("models: set plates for athlete %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPlateBreakdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         float64
+		bar           float64
+		available     []float64
+		want          []PlateCount
+		wantRemainder float64
+	}{
+		{"two plates", 225, 45, DefaultPlates, []PlateCount{{45, 2}}, 0},
+		{"mixed plates", 275, 45, DefaultPlates, []PlateCount{{45, 2}, {25, 1}}, 0},
+		{"fractional plate", 140, 45, DefaultPlates, []PlateCount{{45, 1}, {2.5, 1}}, 0},
+		{"empty bar", 45, 45, DefaultPlates, nil, 0},
+		{"unloadable remainder", 232, 45, DefaultPlates, []PlateCount{{45, 2}, {2.5, 1}}, 2},
+		{"unsorted available", 135, 45, []float64{10, 45}, []PlateCount{{45, 1}}, 0},
+		{"below bar", 35, 45, DefaultPlates, nil, -10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, remainder := PlateBreakdown(tt.total, tt.bar, tt.available)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("plates = %v, want %v", got, tt.want)
+			}
+			if remainder != tt.wantRemainder {
+				t.Errorf("remainder = %v, want %v", remainder, tt.wantRemainder)
+			}
+		})
+	}
+}
+
+func TestParsePlates(t *testing.T) {
+	got, err := ParsePlates(" 45, 25 ,, 2.5")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !slices.Equal(got, []float64{45, 25, 2.5}) {
+		t.Errorf("plates = %v", got)
+	}
+	if _, err := ParsePlates("45, heavy"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := ParsePlates("45, -5"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSetAthletePlates(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Plates", "", "", "", "", "", "", sql.NullInt64{}, true)
+	if got := a.AvailablePlates(); !slices.Equal(got, DefaultPlates) {
+		t.Errorf("default plates = %v", got)
+	}
+
+	if err := SetAthletePlates(db, a.ID, []float64{20, 10, 1.25}); err != nil {
+		t.Fatalf("set plates: %v", err)
+	}
+	got, _ := GetAthleteByID(db, a.ID)
+	if got.Plates.String != "20, 10, 1.25" || !slices.Equal(got.AvailablePlates(), []float64{20, 10, 1.25}) {
+		t.Errorf("plates = %q", got.Plates.String)
+	}
+
+	if err := SetAthletePlates(db, a.ID, nil); err != nil {
+		t.Fatalf("clear plates: %v", err)
+	}
+	got, _ = GetAthleteByID(db, a.ID)
+	if got.Plates.Valid {
+		t.Errorf("plates = %q, want NULL after clear", got.Plates.String)
+	}
+}
@@ -2,7 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 )
 
 // PrescribedSet represents one prescribed set within a program template.
@@ -14,8 +16,11 @@ type PrescribedSet struct {
 	Day            int
 	SetNumber      int
 	Reps           sql.NullInt64   // NULL = AMRAP
+	RepMax         sql.NullInt64   // upper bound of a rep range (e.g. 8-12), NULL for a single target
 	Percentage     sql.NullFloat64 // of training max, NULL for bodyweight/accessories
 	AbsoluteWeight sql.NullFloat64 // fixed weight (lbs/kg), NULL when using percentage
+	TargetRPE      sql.NullFloat64 // target RPE (1-10), NULL when not programmed by RPE
+	RestSeconds    sql.NullInt64   // rest after this set, NULL to use the exercise's rest time
 	SortOrder      int             // display order within a day (lower = first)
 	RepType        string          // "reps", "each_side", "seconds", or "distance"
 	Notes          sql.NullString
@@ -26,6 +31,15 @@ type PrescribedSet struct {
 	// TargetWeight is the per-set target weight computed from percentage × training max
 	// or from absolute_weight. Populated by GetPrescription; not stored in the database.
 	TargetWeight *float64
+
+	// Rest is the rest time in seconds after this set: RestSeconds, else the
+	// exercise's rest time, else the app default. Populated by
+	// GetPrescription; not stored in the database.
+	Rest int
+
+	// Completed reports whether a matching set was logged for this week/day.
+	// Populated by GetCycleReport; not stored in the database.
+	Completed bool
 }
 
 // TargetWeightLabel returns the formatted target weight for this set, or "" if none.
@@ -72,29 +86,79 @@ func (ps *PrescribedSet) PercentageLabel() string {
 	return fmt.Sprintf("%.1f%%", pct)
 }
 
-// RepsLabel returns a display string for reps (e.g. "5", "5/ea", "30s", "30yd", or "AMRAP").
+// TargetRPELabel returns a formatted target RPE (e.g. "8", "8.5"), or "" if none.
+func (ps *PrescribedSet) TargetRPELabel() string {
+	if !ps.TargetRPE.Valid {
+		return ""
+	}
+	rpe := ps.TargetRPE.Float64
+	if rpe == float64(int(rpe)) {
+		return fmt.Sprintf("%.0f", rpe)
+	}
+	return fmt.Sprintf("%.1f", rpe)
+}
+
+// RestLabel returns the set's rest time when it overrides the exercise's
+// rest, or "".
+func (ps *PrescribedSet) RestLabel() string {
+	if !ps.RestSeconds.Valid {
+		return ""
+	}
+	return restLabel(int(ps.RestSeconds.Int64))
+}
+
+// RepsLabel returns a display string for reps (e.g. "5", "8-12", "5/ea", "30s", "30yd", or "AMRAP").
 func (ps *PrescribedSet) RepsLabel() string {
 	if !ps.Reps.Valid {
 		return "AMRAP"
 	}
+	reps := fmt.Sprintf("%d", ps.Reps.Int64)
+	if ps.IsRepRange() {
+		reps = fmt.Sprintf("%d-%d", ps.Reps.Int64, ps.RepMax.Int64)
+	}
 	switch ps.RepType {
 	case "each_side":
-		return fmt.Sprintf("%d/ea", ps.Reps.Int64)
+		return reps + "/ea"
 	case "seconds":
-		return fmt.Sprintf("%ds", ps.Reps.Int64)
+		return reps + "s"
 	case "distance":
-		return fmt.Sprintf("%dyd", ps.Reps.Int64)
+		return reps + "yd"
 	default:
-		return fmt.Sprintf("%d", ps.Reps.Int64)
+		return reps
 	}
 }
 
+// IsRepRange reports whether the set prescribes a rep range rather than a
+// single rep target.
+func (ps *PrescribedSet) IsRepRange() bool {
+	return ps.Reps.Valid && ps.RepMax.Valid && ps.RepMax.Int64 > ps.Reps.Int64
+}
+
+// checkRepRange returns ErrInvalidInput if repMax doesn't lie above reps.
+// repMax is ignored for AMRAP sets (reps == nil).
+func checkRepRange(reps, repMax *int) error {
+	if reps != nil && repMax != nil && *repMax <= *reps {
+		return fmt.Errorf("models: rep range %d-%d must end above its start: %w", *reps, *repMax, ErrInvalidInput)
+	}
+	return nil
+}
+
 // CreatePrescribedSet inserts a new prescribed set into a program template.
-func CreatePrescribedSet(db *sql.DB, templateID, exerciseID int64, week, day, setNumber int, reps *int, percentage *float64, absoluteWeight *float64, sortOrder int, repType, notes string) (*PrescribedSet, error) {
+// A non-nil repMax turns reps into the lower bound of a rep range; it is
+// ignored for AMRAP sets (reps == nil). Returns ErrInvalidInput if repMax
+// isn't above reps.
+func CreatePrescribedSet(db *sql.DB, templateID, exerciseID int64, week, day, setNumber int, reps, repMax *int, percentage, absoluteWeight, targetRPE *float64, restSeconds *int, sortOrder int, repType, notes string) (*PrescribedSet, error) {
+	if err := checkRepRange(reps, repMax); err != nil {
+		return nil, err
+	}
 	var repsVal sql.NullInt64
 	if reps != nil {
 		repsVal = sql.NullInt64{Int64: int64(*reps), Valid: true}
 	}
+	var repMaxVal sql.NullInt64
+	if reps != nil && repMax != nil {
+		repMaxVal = sql.NullInt64{Int64: int64(*repMax), Valid: true}
+	}
 	var pctVal sql.NullFloat64
 	if percentage != nil {
 		pctVal = sql.NullFloat64{Float64: *percentage, Valid: true}
@@ -103,6 +167,14 @@ func CreatePrescribedSet(db *sql.DB, templateID, exerciseID int64, week, day, se
 	if absoluteWeight != nil {
 		absWeightVal = sql.NullFloat64{Float64: *absoluteWeight, Valid: true}
 	}
+	var rpeVal sql.NullFloat64
+	if targetRPE != nil {
+		rpeVal = sql.NullFloat64{Float64: *targetRPE, Valid: true}
+	}
+	var restVal sql.NullInt64
+	if restSeconds != nil {
+		restVal = sql.NullInt64{Int64: int64(*restSeconds), Valid: true}
+	}
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
@@ -113,9 +185,9 @@ func CreatePrescribedSet(db *sql.DB, templateID, exerciseID int64, week, day, se
 
 	var id int64
 	err := db.QueryRow(
-		`INSERT INTO prescribed_sets (template_id, exercise_id, week, day, set_number, reps, percentage, absolute_weight, sort_order, rep_type, notes)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
-		templateID, exerciseID, week, day, setNumber, repsVal, pctVal, absWeightVal, sortOrder, repType, notesVal,
+		`INSERT INTO prescribed_sets (template_id, exercise_id, week, day, set_number, reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		templateID, exerciseID, week, day, setNumber, repsVal, repMaxVal, pctVal, absWeightVal, rpeVal, restVal, sortOrder, repType, notesVal,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -132,13 +204,13 @@ func GetPrescribedSetByID(db *sql.DB, id int64) (*PrescribedSet, error) {
 	ps := &PrescribedSet{}
 	err := db.QueryRow(
 		`SELECT ps.id, ps.template_id, ps.exercise_id, ps.week, ps.day, ps.set_number,
-		        ps.reps, ps.percentage, ps.absolute_weight, ps.sort_order, ps.rep_type, ps.notes, e.name
+		        ps.reps, ps.rep_max, ps.percentage, ps.absolute_weight, ps.target_rpe, ps.rest_seconds, ps.sort_order, ps.rep_type, ps.notes, e.name
 		 FROM prescribed_sets ps
 		 JOIN exercises e ON e.id = ps.exercise_id
 		 WHERE ps.id = ?`,
 		id,
 	).Scan(&ps.ID, &ps.TemplateID, &ps.ExerciseID, &ps.Week, &ps.Day, &ps.SetNumber,
-		&ps.Reps, &ps.Percentage, &ps.AbsoluteWeight, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName)
+		&ps.Reps, &ps.RepMax, &ps.Percentage, &ps.AbsoluteWeight, &ps.TargetRPE, &ps.RestSeconds, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName)
 	if err != nil {
 		return nil, fmt.Errorf("models: get prescribed set %d: %w", id, err)
 	}
@@ -149,7 +221,7 @@ func GetPrescribedSetByID(db *sql.DB, id int64) (*PrescribedSet, error) {
 func ListPrescribedSets(db *sql.DB, templateID int64) ([]*PrescribedSet, error) {
 	rows, err := db.Query(
 		`SELECT ps.id, ps.template_id, ps.exercise_id, ps.week, ps.day, ps.set_number,
-		        ps.reps, ps.percentage, ps.absolute_weight, ps.sort_order, ps.rep_type, ps.notes, e.name
+		        ps.reps, ps.rep_max, ps.percentage, ps.absolute_weight, ps.target_rpe, ps.rest_seconds, ps.sort_order, ps.rep_type, ps.notes, e.name
 		 FROM prescribed_sets ps
 		 JOIN exercises e ON e.id = ps.exercise_id
 		 WHERE ps.template_id = ?
@@ -165,7 +237,7 @@ func ListPrescribedSets(db *sql.DB, templateID int64) ([]*PrescribedSet, error)
 	for rows.Next() {
 		ps := &PrescribedSet{}
 		if err := rows.Scan(&ps.ID, &ps.TemplateID, &ps.ExerciseID, &ps.Week, &ps.Day, &ps.SetNumber,
-			&ps.Reps, &ps.Percentage, &ps.AbsoluteWeight, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName); err != nil {
+			&ps.Reps, &ps.RepMax, &ps.Percentage, &ps.AbsoluteWeight, &ps.TargetRPE, &ps.RestSeconds, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName); err != nil {
 			return nil, fmt.Errorf("models: scan prescribed set: %w", err)
 		}
 		sets = append(sets, ps)
@@ -180,7 +252,7 @@ func ListPrescribedSets(db *sql.DB, templateID int64) ([]*PrescribedSet, error)
 func ListPrescribedSetsForDay(db *sql.DB, templateID int64, week, day int) ([]*PrescribedSet, error) {
 	rows, err := db.Query(
 		`SELECT ps.id, ps.template_id, ps.exercise_id, ps.week, ps.day, ps.set_number,
-		        ps.reps, ps.percentage, ps.absolute_weight, ps.sort_order, ps.rep_type, ps.notes, e.name
+		        ps.reps, ps.rep_max, ps.percentage, ps.absolute_weight, ps.target_rpe, ps.rest_seconds, ps.sort_order, ps.rep_type, ps.notes, e.name
 		 FROM prescribed_sets ps
 		 JOIN exercises e ON e.id = ps.exercise_id
 		 WHERE ps.template_id = ? AND ps.week = ? AND ps.day = ?
@@ -196,7 +268,7 @@ func ListPrescribedSetsForDay(db *sql.DB, templateID int64, week, day int) ([]*P
 	for rows.Next() {
 		ps := &PrescribedSet{}
 		if err := rows.Scan(&ps.ID, &ps.TemplateID, &ps.ExerciseID, &ps.Week, &ps.Day, &ps.SetNumber,
-			&ps.Reps, &ps.Percentage, &ps.AbsoluteWeight, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName); err != nil {
+			&ps.Reps, &ps.RepMax, &ps.Percentage, &ps.AbsoluteWeight, &ps.TargetRPE, &ps.RestSeconds, &ps.SortOrder, &ps.RepType, &ps.Notes, &ps.ExerciseName); err != nil {
 			return nil, fmt.Errorf("models: scan prescribed set: %w", err)
 		}
 		sets = append(sets, ps)
@@ -220,12 +292,20 @@ func DeletePrescribedSet(db *sql.DB, id int64) error {
 	return nil
 }
 
-// UpdatePrescribedSet updates an existing prescribed set's fields.
-func UpdatePrescribedSet(db *sql.DB, id int64, exerciseID int64, setNumber int, reps *int, percentage *float64, absoluteWeight *float64, sortOrder int, repType, notes string) (*PrescribedSet, error) {
+// UpdatePrescribedSet updates an existing prescribed set's fields. Returns
+// ErrInvalidInput if repMax isn't above reps.
+func UpdatePrescribedSet(db *sql.DB, id int64, exerciseID int64, setNumber int, reps, repMax *int, percentage, absoluteWeight, targetRPE *float64, restSeconds *int, sortOrder int, repType, notes string) (*PrescribedSet, error) {
+	if err := checkRepRange(reps, repMax); err != nil {
+		return nil, err
+	}
 	var repsVal sql.NullInt64
 	if reps != nil {
 		repsVal = sql.NullInt64{Int64: int64(*reps), Valid: true}
 	}
+	var repMaxVal sql.NullInt64
+	if reps != nil && repMax != nil {
+		repMaxVal = sql.NullInt64{Int64: int64(*repMax), Valid: true}
+	}
 	var pctVal sql.NullFloat64
 	if percentage != nil {
 		pctVal = sql.NullFloat64{Float64: *percentage, Valid: true}
@@ -234,6 +314,14 @@ func UpdatePrescribedSet(db *sql.DB, id int64, exerciseID int64, setNumber int,
 	if absoluteWeight != nil {
 		absWeightVal = sql.NullFloat64{Float64: *absoluteWeight, Valid: true}
 	}
+	var rpeVal sql.NullFloat64
+	if targetRPE != nil {
+		rpeVal = sql.NullFloat64{Float64: *targetRPE, Valid: true}
+	}
+	var restVal sql.NullInt64
+	if restSeconds != nil {
+		restVal = sql.NullInt64{Int64: int64(*restSeconds), Valid: true}
+	}
 	var notesVal sql.NullString
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
@@ -244,9 +332,9 @@ func UpdatePrescribedSet(db *sql.DB, id int64, exerciseID int64, setNumber int,
 
 	_, err := db.Exec(
 		`UPDATE prescribed_sets
-		 SET exercise_id = ?, set_number = ?, reps = ?, percentage = ?, absolute_weight = ?, sort_order = ?, rep_type = ?, notes = ?
+		 SET exercise_id = ?, set_number = ?, reps = ?, rep_max = ?, percentage = ?, absolute_weight = ?, target_rpe = ?, rest_seconds = ?, sort_order = ?, rep_type = ?, notes = ?
 		 WHERE id = ?`,
-		exerciseID, setNumber, repsVal, pctVal, absWeightVal, sortOrder, repType, notesVal, id,
+		exerciseID, setNumber, repsVal, repMaxVal, pctVal, absWeightVal, rpeVal, restVal, sortOrder, repType, notesVal, id,
 	)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -258,35 +346,95 @@ func UpdatePrescribedSet(db *sql.DB, id int64, exerciseID int64, setNumber int,
 	return GetPrescribedSetByID(db, id)
 }
 
+// ReorderPrescribedSets rewrites sort_order for the given sets so they
+// display in the order supplied. All IDs must belong to templateID and to a
+// single week+day; otherwise ErrInvalidInput is returned and nothing changes.
+func ReorderPrescribedSets(db *sql.DB, templateID int64, setIDs []int64) error {
+	if len(setIDs) == 0 {
+		return fmt.Errorf("models: reorder prescribed sets: no set IDs: %w", ErrInvalidInput)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("models: begin tx for reorder template %d: %w", templateID, err)
+	}
+	defer tx.Rollback()
+
+	type slot struct{ week, day int }
+	var first slot
+	seen := make(map[int64]bool, len(setIDs))
+	for i, id := range setIDs {
+		if seen[id] {
+			return fmt.Errorf("models: reorder prescribed sets: duplicate set %d: %w", id, ErrInvalidInput)
+		}
+		seen[id] = true
+
+		var cur slot
+		err := tx.QueryRow(
+			`SELECT week, day FROM prescribed_sets WHERE id = ? AND template_id = ?`,
+			id, templateID,
+		).Scan(&cur.week, &cur.day)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("models: prescribed set %d not in template %d: %w", id, templateID, ErrInvalidInput)
+		}
+		if err != nil {
+			return fmt.Errorf("models: look up prescribed set %d: %w", id, err)
+		}
+		if i == 0 {
+			first = cur
+		} else if cur != first {
+			return fmt.Errorf("models: reorder prescribed sets: set %d is not in week %d day %d: %w", id, first.week, first.day, ErrInvalidInput)
+		}
+
+		if _, err := tx.Exec(`UPDATE prescribed_sets SET sort_order = ? WHERE id = ?`, i+1, id); err != nil {
+			return fmt.Errorf("models: set order for prescribed set %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("models: commit reorder template %d: %w", templateID, err)
+	}
+	return nil
+}
+
 // CopyWeek replaces all prescribed sets in targetWeek with copies from
 // sourceWeek within the same program template. Any existing sets in the
 // target week are deleted first. Returns the number of sets inserted.
 func CopyWeek(db *sql.DB, templateID int64, sourceWeek, targetWeek int) (int, error) {
-	tx, err := db.Begin()
+	counts, err := CopyPrescribedWeek(db, templateID, sourceWeek, []int{targetWeek})
 	if err != nil {
-		return 0, fmt.Errorf("models: copy week begin tx: %w", err)
+		return 0, err
 	}
-	defer tx.Rollback()
+	return counts[targetWeek], nil
+}
 
-	// Delete existing sets in target week.
-	_, err = tx.Exec(
-		`DELETE FROM prescribed_sets WHERE template_id = ? AND week = ?`,
-		templateID, targetWeek,
-	)
+// CopyPrescribedWeek replaces all prescribed sets in each target week with
+// copies from sourceWeek, in a single transaction. Existing sets in every
+// target week are deleted first. Returns the number of sets inserted per
+// target week. Returns ErrInvalidInput if a target equals the source week.
+func CopyPrescribedWeek(db *sql.DB, templateID int64, sourceWeek int, targetWeeks []int) (map[int]int, error) {
+	for _, tw := range targetWeeks {
+		if tw == sourceWeek {
+			return nil, fmt.Errorf("models: copy week %d onto itself: %w", sourceWeek, ErrInvalidInput)
+		}
+	}
+
+	tx, err := db.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("models: copy week delete target: %w", err)
+		return nil, fmt.Errorf("models: copy week begin tx: %w", err)
 	}
+	defer tx.Rollback()
 
 	rows, err := tx.Query(
-		`SELECT day, exercise_id, set_number, reps, percentage,
-		        absolute_weight, sort_order, rep_type, notes
+		`SELECT day, exercise_id, set_number, reps, rep_max, percentage,
+		        absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes
 		   FROM prescribed_sets
 		  WHERE template_id = ? AND week = ?
 		  ORDER BY day, sort_order`,
 		templateID, sourceWeek,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("models: copy week query source: %w", err)
+		return nil, fmt.Errorf("models: copy week query source: %w", err)
 	}
 	defer rows.Close()
 
@@ -295,8 +443,11 @@ func CopyWeek(db *sql.DB, templateID int64, sourceWeek, targetWeek int) (int, er
 		exerciseID     int64
 		setNumber      int
 		reps           sql.NullInt64
+		repMax         sql.NullInt64
 		percentage     sql.NullFloat64
 		absoluteWeight sql.NullFloat64
+		targetRPE      sql.NullFloat64
+		restSeconds    sql.NullInt64
 		sortOrder      int
 		repType        string
 		notes          sql.NullString
@@ -305,34 +456,169 @@ func CopyWeek(db *sql.DB, templateID int64, sourceWeek, targetWeek int) (int, er
 	for rows.Next() {
 		var s setRow
 		if err := rows.Scan(&s.day, &s.exerciseID, &s.setNumber,
-			&s.reps, &s.percentage, &s.absoluteWeight,
+			&s.reps, &s.repMax, &s.percentage, &s.absoluteWeight, &s.targetRPE, &s.restSeconds,
 			&s.sortOrder, &s.repType, &s.notes); err != nil {
-			return 0, fmt.Errorf("models: copy week scan: %w", err)
+			return nil, fmt.Errorf("models: copy week scan: %w", err)
 		}
 		sets = append(sets, s)
 	}
 	if err := rows.Err(); err != nil {
-		return 0, fmt.Errorf("models: copy week rows: %w", err)
+		return nil, fmt.Errorf("models: copy week rows: %w", err)
 	}
+	rows.Close()
 
-	inserted := 0
-	for _, s := range sets {
+	counts := make(map[int]int, len(targetWeeks))
+	for _, targetWeek := range targetWeeks {
+		// Delete existing sets in target week.
+		_, err = tx.Exec(
+			`DELETE FROM prescribed_sets WHERE template_id = ? AND week = ?`,
+			templateID, targetWeek,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("models: copy week delete target %d: %w", targetWeek, err)
+		}
+
+		inserted := 0
+		for _, s := range sets {
+			_, err := tx.Exec(
+				`INSERT INTO prescribed_sets
+				   (template_id, week, day, exercise_id, set_number,
+				    reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				templateID, targetWeek, s.day, s.exerciseID, s.setNumber,
+				s.reps, s.repMax, s.percentage, s.absoluteWeight, s.targetRPE, s.restSeconds, s.sortOrder, s.repType, s.notes,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("models: copy week insert into week %d: %w", targetWeek, err)
+			}
+			inserted++
+		}
+		counts[targetWeek] = inserted
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: copy week commit: %w", err)
+	}
+	return counts, nil
+}
+
+// GenerateDeloadWeek appends a deload week to a program template. The new
+// week mirrors the exercise/day structure of sourceWeek with percentages and
+// absolute weights scaled by loadFactor (e.g. 0.6) and reps reduced by the
+// same factor. Bodyweight sets (no percentage or absolute weight) are copied
+// unchanged. The template's num_weeks is incremented. Returns the number of
+// the new week.
+func GenerateDeloadWeek(db *sql.DB, templateID int64, sourceWeek int, loadFactor float64) (int, error) {
+	if loadFactor <= 0 || loadFactor >= 1 {
+		return 0, fmt.Errorf("models: deload load factor %.2f out of range: %w", loadFactor, ErrInvalidInput)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("models: deload week begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var numWeeks int
+	err = tx.QueryRow(`SELECT num_weeks FROM program_templates WHERE id = ?`, templateID).Scan(&numWeeks)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("models: deload week get template %d: %w", templateID, err)
+	}
+	if sourceWeek < 1 || sourceWeek > numWeeks {
+		return 0, fmt.Errorf("models: deload source week %d outside 1-%d: %w", sourceWeek, numWeeks, ErrInvalidInput)
+	}
+	newWeek := numWeeks + 1
+
+	res, err := tx.Exec(
+		`INSERT INTO prescribed_sets
+		   (template_id, week, day, exercise_id, set_number,
+		    reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes)
+		 SELECT template_id, ?, day, exercise_id, set_number,
+		        reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes
+		   FROM prescribed_sets
+		  WHERE template_id = ? AND week = ?`,
+		newWeek, templateID, sourceWeek,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: deload week copy sets: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, fmt.Errorf("models: deload source week %d has no sets: %w", sourceWeek, ErrInvalidInput)
+	}
+
+	// Scale the loaded sets in the new week. Bodyweight sets are untouched.
+	rows, err := tx.Query(
+		`SELECT id, reps, rep_max, percentage, absolute_weight
+		   FROM prescribed_sets
+		  WHERE template_id = ? AND week = ?
+		    AND (percentage IS NOT NULL OR absolute_weight IS NOT NULL)`,
+		templateID, newWeek,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: deload week query sets: %w", err)
+	}
+	defer rows.Close()
+
+	type loadedSet struct {
+		id             int64
+		reps           sql.NullInt64
+		repMax         sql.NullInt64
+		percentage     sql.NullFloat64
+		absoluteWeight sql.NullFloat64
+	}
+	var loaded []loadedSet
+	for rows.Next() {
+		var ls loadedSet
+		if err := rows.Scan(&ls.id, &ls.reps, &ls.repMax, &ls.percentage, &ls.absoluteWeight); err != nil {
+			return 0, fmt.Errorf("models: deload week scan: %w", err)
+		}
+		loaded = append(loaded, ls)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("models: deload week rows: %w", err)
+	}
+	rows.Close()
+
+	for _, ls := range loaded {
+		if ls.percentage.Valid {
+			ls.percentage.Float64 = math.Round(ls.percentage.Float64*loadFactor*10) / 10
+		}
+		if ls.absoluteWeight.Valid {
+			ls.absoluteWeight.Float64 = math.Round(ls.absoluteWeight.Float64*loadFactor*10) / 10
+		}
+		if ls.reps.Valid {
+			ls.reps.Int64 = deloadReps(ls.reps.Int64, loadFactor)
+		}
+		if ls.repMax.Valid {
+			ls.repMax.Int64 = deloadReps(ls.repMax.Int64, loadFactor)
+		}
 		_, err := tx.Exec(
-			`INSERT INTO prescribed_sets
-			   (template_id, week, day, exercise_id, set_number,
-			    reps, percentage, absolute_weight, sort_order, rep_type, notes)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			templateID, targetWeek, s.day, s.exerciseID, s.setNumber,
-			s.reps, s.percentage, s.absoluteWeight, s.sortOrder, s.repType, s.notes,
+			`UPDATE prescribed_sets SET reps = ?, rep_max = ?, percentage = ?, absolute_weight = ? WHERE id = ?`,
+			ls.reps, ls.repMax, ls.percentage, ls.absoluteWeight, ls.id,
 		)
 		if err != nil {
-			return 0, fmt.Errorf("models: copy week insert: %w", err)
+			return 0, fmt.Errorf("models: deload week scale set %d: %w", ls.id, err)
 		}
-		inserted++
+	}
+
+	if _, err := tx.Exec(`UPDATE program_templates SET num_weeks = ? WHERE id = ?`, newWeek, templateID); err != nil {
+		return 0, fmt.Errorf("models: deload week bump num_weeks: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("models: copy week commit: %w", err)
+		return 0, fmt.Errorf("models: deload week commit: %w", err)
+	}
+	return newWeek, nil
+}
+
+// deloadReps scales a rep target by loadFactor, keeping at least one rep.
+func deloadReps(reps int64, loadFactor float64) int64 {
+	scaled := int64(math.Round(float64(reps) * loadFactor))
+	if scaled < 1 {
+		scaled = 1
 	}
-	return inserted, nil
+	return scaled
 }
@@ -2,8 +2,10 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"time"
 )
 
@@ -64,6 +66,11 @@ type PrescriptionLine struct {
 	TrainingMax  *float64 // nil if no TM set
 	TargetWeight *float64 // calculated from percentage * TM
 	Percentage   *float64 // from the prescribed set
+	RestSeconds  int      // rest after each set, from the first set's Rest
+
+	// SubstitutedFor is the name of the programmed exercise when the athlete
+	// has a standing substitution for it, or "".
+	SubstitutedFor string
 }
 
 // PercentageLabel returns a formatted string like "75%" or empty if nil.
@@ -86,6 +93,21 @@ func (pl *PrescriptionLine) TargetWeightLabel() string {
 	return fmt.Sprintf("%.1f", *pl.TargetWeight)
 }
 
+// RestLabel returns the line's rest time, like "90s" or "3 min". Sets that
+// override the rest are labelled individually by PrescribedSet.RestLabel.
+func (pl *PrescriptionLine) RestLabel() string {
+	return restLabel(pl.RestSeconds)
+}
+
+// restLabel formats a rest time in seconds, using minutes for whole minutes
+// past the first.
+func restLabel(seconds int) string {
+	if seconds > 60 && seconds%60 == 0 {
+		return fmt.Sprintf("%d min", seconds/60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
 // SetsSummary returns a compact summary like "3×5" or "5/3/1+".
 func (pl *PrescriptionLine) SetsSummary() string {
 	if len(pl.Sets) == 0 {
@@ -94,9 +116,9 @@ func (pl *PrescriptionLine) SetsSummary() string {
 
 	// Check if all sets have the same reps.
 	allSame := true
-	firstReps := pl.Sets[0].Reps
+	firstReps, firstMax := pl.Sets[0].Reps, pl.Sets[0].RepMax
 	for _, s := range pl.Sets[1:] {
-		if s.Reps != firstReps {
+		if s.Reps != firstReps || s.RepMax != firstMax {
 			allSame = false
 			break
 		}
@@ -125,8 +147,9 @@ type Prescription struct {
 	CurrentDay  int
 	CycleNumber int
 	Lines       []*PrescriptionLine
-	HasWorkout  bool   // true if athlete already has a workout logged today
-	TodayDate   string // YYYY-MM-DD
+	Accessories []*AccessoryPlan // active accessory plans for CurrentDay
+	HasWorkout  bool             // true if athlete already has a workout logged today
+	TodayDate   string           // YYYY-MM-DD
 
 	// Progress tracking within the current cycle.
 	CompletedInCycle int     // workouts completed in the current cycle
@@ -136,11 +159,21 @@ type Prescription struct {
 	// CycleComplete is true when a non-loop program has finished all workouts
 	// in its cycle and is awaiting coach review before advancing.
 	CycleComplete bool
+
+	// RestDay is true when the assignment has a training-day schedule and
+	// today is not one of its days. CurrentWeek/CurrentDay then describe
+	// the next scheduled session, which falls on NextTrainingDate.
+	RestDay          bool
+	NextTrainingDate string // YYYY-MM-DD; empty unless RestDay
 }
 
 // GetPrescription calculates training prescription for an athlete using a specific assignment.
 // Position in the program is determined by counting completed workouts with the same assignment_id.
-// The cycle repeats automatically when all weeks×days are exhausted.
+// The cycle repeats automatically when all weeks×days are exhausted. The
+// athlete's standing exercise substitutions replace programmed exercises, and
+// accessory plans for the current day are listed after the main lifts.
+// When the assignment has training days set and today is not one of them,
+// the prescription is flagged as a rest day.
 // If program is nil, returns nil (no prescription).
 func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Prescription, error) {
 	if program == nil {
@@ -188,11 +221,17 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 	currentWeek := (position / program.NumDays) + 1
 	currentDay := (position % program.NumDays) + 1
 
-	// Get prescribed sets for this week/day.
+	// Get prescribed sets for this week/day, with the athlete's standing
+	// substitutions applied.
 	sets, err := ListPrescribedSetsForDay(db, program.TemplateID, currentWeek, currentDay)
 	if err != nil {
 		return nil, err
 	}
+	subs, err := substitutionsByExercise(db, program.AthleteID)
+	if err != nil {
+		return nil, err
+	}
+	substitutedFor := applySubstitutions(sets, subs)
 
 	// Get current training maxes for the athlete.
 	tms, err := ListCurrentTrainingMaxes(db, program.AthleteID)
@@ -206,15 +245,37 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 		tmMap[tm.ExerciseID] = tm.Weight
 	}
 
+	// Rest after each set: the set's own override, else the exercise's rest
+	// time, else the app default — the same fallback the rest timer uses.
+	defaultRest := GetDefaultRestSeconds(db)
+	exerciseRest := make(map[int64]int)
+
 	// Group sets by exercise and calculate target weights.
 	lineMap := make(map[int64]*PrescriptionLine)
 	var lineOrder []int64
 	for _, s := range sets {
+		rest, ok := exerciseRest[s.ExerciseID]
+		if !ok {
+			rest = defaultRest
+			ex, err := GetExerciseByID(db, s.ExerciseID)
+			if err != nil {
+				return nil, err
+			}
+			if ex.RestSeconds.Valid {
+				rest = int(ex.RestSeconds.Int64)
+			}
+			exerciseRest[s.ExerciseID] = rest
+		}
+		s.Rest = rest
+		if s.RestSeconds.Valid {
+			s.Rest = int(s.RestSeconds.Int64)
+		}
+
 		// Compute per-set target weight from percentage × training max,
 		// or use absolute_weight for fixed-weight prescriptions.
 		if s.Percentage.Valid {
 			if tm, ok := tmMap[s.ExerciseID]; ok {
-				target := roundToNearest(s.Percentage.Float64/100*tm, 2.5)
+				target := program.roundWeight(s.Percentage.Float64 / 100 * tm)
 				s.TargetWeight = &target
 			}
 		} else if s.AbsoluteWeight.Valid {
@@ -225,8 +286,10 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 		line, exists := lineMap[s.ExerciseID]
 		if !exists {
 			line = &PrescriptionLine{
-				ExerciseName: s.ExerciseName,
-				ExerciseID:   s.ExerciseID,
+				ExerciseName:   s.ExerciseName,
+				ExerciseID:     s.ExerciseID,
+				SubstitutedFor: substitutedFor[s.ExerciseID],
+				RestSeconds:    s.Rest,
 			}
 			lineMap[s.ExerciseID] = line
 			lineOrder = append(lineOrder, s.ExerciseID)
@@ -239,7 +302,7 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 			line.Percentage = &pct
 			if tm, ok := tmMap[s.ExerciseID]; ok {
 				line.TrainingMax = &tm
-				target := roundToNearest(pct/100*tm, 2.5)
+				target := program.roundWeight(pct / 100 * tm)
 				line.TargetWeight = &target
 			}
 		} else if s.AbsoluteWeight.Valid && line.TargetWeight == nil {
@@ -254,6 +317,24 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 		lines = append(lines, lineMap[eid])
 	}
 
+	accessories, err := ListAccessoryPlansForDay(db, program.AthleteID, currentDay)
+	if err != nil {
+		return nil, err
+	}
+
+	// Training-day schedule: flag rest days and find the next session date.
+	restDay := !program.TrainsOn(today)
+	var nextTrainingDate string
+	if restDay {
+		for i := 1; i <= 7; i++ {
+			d := today.AddDate(0, 0, i)
+			if program.TrainsOn(d) {
+				nextTrainingDate = d.Format("2006-01-02")
+				break
+			}
+		}
+	}
+
 	// Calculate progress within the current cycle.
 	completedInCycle := position // position is 0-based index within cycle
 	progressPct := 0.0
@@ -267,12 +348,15 @@ func GetPrescription(db *sql.DB, program *AthleteProgram, today time.Time) (*Pre
 		CurrentDay:       currentDay,
 		CycleNumber:      cycleNumber,
 		Lines:            lines,
+		Accessories:      accessories,
 		HasWorkout:       hasWorkout,
 		TodayDate:        todayStr,
 		CompletedInCycle: completedInCycle,
 		TotalInCycle:     cycleLength,
 		ProgressPercent:  progressPct,
 		CycleComplete:    cycleComplete,
+		RestDay:          restDay,
+		NextTrainingDate: nextTrainingDate,
 	}, nil
 }
 
@@ -281,11 +365,59 @@ func roundToNearest(v, increment float64) float64 {
 	return math.Round(v/increment) * increment
 }
 
+// roundWeight rounds a computed target weight using the program template's
+// rounding increment and mode. Falls back to the nearest 2.5 when the
+// program was not loaded with rounding settings.
+func (ap *AthleteProgram) roundWeight(v float64) float64 {
+	return roundToIncrement(v, ap.RoundingIncrement, ap.RoundingMode)
+}
+
+// roundToIncrement rounds v to a multiple of increment using the given
+// rounding mode. Falls back to the nearest 2.5 when increment is unset.
+func roundToIncrement(v, increment float64, mode string) float64 {
+	if increment <= 0 {
+		return roundToNearest(v, 2.5)
+	}
+	// The epsilon keeps float noise (e.g. 0.7*200 = 140.00000000000003)
+	// from pushing an exact multiple into the next increment.
+	const epsilon = 1e-9
+	switch mode {
+	case RoundDown:
+		return math.Floor(v/increment+epsilon) * increment
+	case RoundUp:
+		return math.Ceil(v/increment-epsilon) * increment
+	default:
+		return roundToNearest(v, increment)
+	}
+}
+
+// RoundTrainingMax rounds a training max to a loadable value using the
+// program's rounding settings. When rounding changes the weight, the
+// adjustment is appended to notes so the TM history shows where the stored
+// value came from.
+func (ap *AthleteProgram) RoundTrainingMax(weight float64, notes string) (float64, string) {
+	rounded := ap.roundWeight(weight)
+	if math.Abs(rounded-weight) < 1e-9 {
+		return weight, notes
+	}
+	adj := fmt.Sprintf("rounded from %s to %s", formatTMWeight(weight), formatTMWeight(rounded))
+	if notes == "" {
+		return rounded, adj
+	}
+	return rounded, notes + " (" + adj + ")"
+}
+
+// formatTMWeight formats a weight without trailing zeros (e.g. 202.3, 200).
+func formatTMWeight(w float64) string {
+	return strconv.FormatFloat(math.Round(w*100)/100, 'f', -1, 64)
+}
+
 // CycleReportDay holds the prescription lines for one day in a cycle.
 type CycleReportDay struct {
-	Week  int
-	Day   int
-	Lines []*PrescriptionLine
+	Week   int
+	Day    int
+	Lines  []*PrescriptionLine
+	Logged bool // a workout has been logged for this day in the cycle
 }
 
 // CycleReport holds a complete cycle's worth of prescriptions for printing.
@@ -293,6 +425,77 @@ type CycleReport struct {
 	Program     *AthleteProgram
 	CycleNumber int
 	Days        []*CycleReportDay
+
+	// Adherence across the cycle: prescribed sets with a matching logged set.
+	CompletedSets int
+	TotalSets     int
+}
+
+// AdherencePercent returns completed sets as a percentage of prescribed sets.
+func (r *CycleReport) AdherencePercent() int {
+	if r.TotalSets == 0 {
+		return 0
+	}
+	return int(math.Round(float64(r.CompletedSets) / float64(r.TotalSets) * 100))
+}
+
+// programDay identifies a week/day slot within a program cycle.
+type programDay struct {
+	week, day int
+}
+
+// loggedSetsByProgramDay maps each workout logged against an assignment in
+// the given cycle (0-based) to its program week/day and returns the number of
+// sets logged per exercise on that day. Workouts are placed by the same
+// date-ordered counting GetPrescription uses to advance position.
+func loggedSetsByProgramDay(db *sql.DB, assignmentID int64, numDays, cycleLength, cycleIdx int) (map[programDay]map[int64]int, error) {
+	rows, err := db.Query(
+		`SELECT w.pos, ws.exercise_id, COUNT(ws.id)
+		 FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY date(date), id) - 1 AS pos
+		       FROM workouts WHERE assignment_id = ?) w
+		 LEFT JOIN workout_sets ws ON ws.workout_id = w.id
+		 WHERE w.pos >= ? AND w.pos < ?
+		 GROUP BY w.pos, ws.exercise_id`,
+		assignmentID, cycleIdx*cycleLength, (cycleIdx+1)*cycleLength,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: logged sets for assignment %d: %w", assignmentID, err)
+	}
+	defer rows.Close()
+
+	byDay := make(map[programDay]map[int64]int)
+	for rows.Next() {
+		var pos, n int
+		var exerciseID sql.NullInt64
+		if err := rows.Scan(&pos, &exerciseID, &n); err != nil {
+			return nil, fmt.Errorf("models: scan logged set count: %w", err)
+		}
+		pos -= cycleIdx * cycleLength
+		key := programDay{week: pos/numDays + 1, day: pos%numDays + 1}
+		if byDay[key] == nil {
+			byDay[key] = make(map[int64]int)
+		}
+		if exerciseID.Valid {
+			byDay[key][exerciseID.Int64] = n
+		}
+	}
+	return byDay, rows.Err()
+}
+
+// markCompletedSets flags each prescribed set as completed when at least as
+// many sets of its exercise were logged as its position among that
+// exercise's prescribed sets. Returns the number of completed sets.
+func markCompletedSets(sets []*PrescribedSet, logged map[int64]int) int {
+	seen := make(map[int64]int)
+	completed := 0
+	for _, s := range sets {
+		seen[s.ExerciseID]++
+		s.Completed = logged[s.ExerciseID] >= seen[s.ExerciseID]
+		if s.Completed {
+			completed++
+		}
+	}
+	return completed
 }
 
 // GetCycleReport generates the full prescription for every day in the current cycle.
@@ -330,14 +533,29 @@ func GetCycleReport(db *sql.DB, program *AthleteProgram, today time.Time) (*Cycl
 		tmMap[tm.ExerciseID] = tm.Weight
 	}
 
+	subs, err := substitutionsByExercise(db, program.AthleteID)
+	if err != nil {
+		return nil, err
+	}
+
+	logged, err := loggedSetsByProgramDay(db, program.ID, program.NumDays, cycleLength, cycleNumber-1)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build each day.
 	var days []*CycleReportDay
+	var completedSets, totalSets int
 	for w := 1; w <= program.NumWeeks; w++ {
 		for d := 1; d <= program.NumDays; d++ {
 			sets, err := ListPrescribedSetsForDay(db, program.TemplateID, w, d)
 			if err != nil {
 				return nil, err
 			}
+			substitutedFor := applySubstitutions(sets, subs)
+			dayLogged, hasLog := logged[programDay{week: w, day: d}]
+			completedSets += markCompletedSets(sets, dayLogged)
+			totalSets += len(sets)
 
 			lineMap := make(map[int64]*PrescriptionLine)
 			var lineOrder []int64
@@ -345,7 +563,7 @@ func GetCycleReport(db *sql.DB, program *AthleteProgram, today time.Time) (*Cycl
 				// Compute per-set target weight.
 				if s.Percentage.Valid {
 					if tm, ok := tmMap[s.ExerciseID]; ok {
-						target := roundToNearest(s.Percentage.Float64/100*tm, 2.5)
+						target := program.roundWeight(s.Percentage.Float64 / 100 * tm)
 						s.TargetWeight = &target
 					}
 				} else if s.AbsoluteWeight.Valid {
@@ -356,8 +574,9 @@ func GetCycleReport(db *sql.DB, program *AthleteProgram, today time.Time) (*Cycl
 				line, exists := lineMap[s.ExerciseID]
 				if !exists {
 					line = &PrescriptionLine{
-						ExerciseName: s.ExerciseName,
-						ExerciseID:   s.ExerciseID,
+						ExerciseName:   s.ExerciseName,
+						ExerciseID:     s.ExerciseID,
+						SubstitutedFor: substitutedFor[s.ExerciseID],
 					}
 					lineMap[s.ExerciseID] = line
 					lineOrder = append(lineOrder, s.ExerciseID)
@@ -369,7 +588,7 @@ func GetCycleReport(db *sql.DB, program *AthleteProgram, today time.Time) (*Cycl
 					line.Percentage = &pct
 					if tm, ok := tmMap[s.ExerciseID]; ok {
 						line.TrainingMax = &tm
-						target := roundToNearest(pct/100*tm, 2.5)
+						target := program.roundWeight(pct / 100 * tm)
 						line.TargetWeight = &target
 					}
 				} else if s.AbsoluteWeight.Valid && line.TargetWeight == nil {
@@ -384,16 +603,79 @@ func GetCycleReport(db *sql.DB, program *AthleteProgram, today time.Time) (*Cycl
 			}
 
 			days = append(days, &CycleReportDay{
-				Week:  w,
-				Day:   d,
-				Lines: lines,
+				Week:   w,
+				Day:    d,
+				Lines:  lines,
+				Logged: hasLog,
 			})
 		}
 	}
 
 	return &CycleReport{
-		Program:     program,
-		CycleNumber: cycleNumber,
-		Days:        days,
+		Program:       program,
+		CycleNumber:   cycleNumber,
+		Days:          days,
+		CompletedSets: completedSets,
+		TotalSets:     totalSets,
 	}, nil
 }
+
+// CompletedPrescribedSets reports, for the athlete's most recent assignment
+// of a template, which prescribed sets were performed in the latest cycle
+// with logged workouts. The map is keyed by prescribed set ID; sets in days
+// not yet logged map to false. Returns an empty map when the athlete has
+// never been assigned the template.
+func CompletedPrescribedSets(db *sql.DB, athleteID, templateID int64) (map[int64]bool, error) {
+	var assignmentID int64
+	var numWeeks, numDays, workoutCount int
+	err := db.QueryRow(
+		`SELECT ap.id, pt.num_weeks, pt.num_days,
+		        (SELECT COUNT(*) FROM workouts w WHERE w.assignment_id = ap.id)
+		 FROM athlete_programs ap
+		 JOIN program_templates pt ON pt.id = ap.template_id
+		 WHERE ap.athlete_id = ? AND ap.template_id = ?
+		 ORDER BY ap.active DESC, ap.id DESC
+		 LIMIT 1`,
+		athleteID, templateID,
+	).Scan(&assignmentID, &numWeeks, &numDays, &workoutCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return map[int64]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: find assignment of template %d for athlete %d: %w", templateID, athleteID, err)
+	}
+
+	cycleLength := numWeeks * numDays
+	if cycleLength == 0 {
+		return map[int64]bool{}, nil
+	}
+	cycleIdx := 0
+	if workoutCount > 0 {
+		cycleIdx = (workoutCount - 1) / cycleLength
+	}
+
+	logged, err := loggedSetsByProgramDay(db, assignmentID, numDays, cycleLength, cycleIdx)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := substitutionsByExercise(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[int64]bool)
+	for w := 1; w <= numWeeks; w++ {
+		for d := 1; d <= numDays; d++ {
+			sets, err := ListPrescribedSetsForDay(db, templateID, w, d)
+			if err != nil {
+				return nil, err
+			}
+			applySubstitutions(sets, subs)
+			markCompletedSets(sets, logged[programDay{week: w, day: d}])
+			for _, s := range sets {
+				completed[s.ID] = s.Completed
+			}
+		}
+	}
+	return completed, nil
+}
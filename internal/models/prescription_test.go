@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -120,11 +121,75 @@ func TestRoundToNearest(t *testing.T) {
 	}
 }
 
+func TestGetPrescription_Rounding(t *testing.T) {
+	db := testDB(t)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	press, _ := CreateExercise(db, "Press", "", "", "", "", 0)
+
+	tests := []struct {
+		name      string
+		increment float64
+		mode      string
+		pct       float64
+		want      float64
+	}{
+		{"default nearest 5", 0, "", 75, 185}, // 245 × 75% = 183.75
+		{"down to 5", 5, RoundDown, 75, 180},  // 183.75 → 180
+		{"up to 5", 5, RoundUp, 71, 175},      // 245 × 71% = 173.95 → 175
+		{"up keeps exact multiple", 5, RoundUp, 100, 245},
+		{"nearest 2.5", 2.5, RoundNearest, 71, 175},
+		{"nearest 10", 10, RoundNearest, 75, 180},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := CreateProgramTemplate(db, nil, fmt.Sprintf("Rounding %d", i), "", 1, 1, false, "", tt.increment, tt.mode)
+			if err != nil {
+				t.Fatalf("create template: %v", err)
+			}
+			reps := 5
+			pct := tt.pct
+			abs := 183.75
+			CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "")
+			CreatePrescribedSet(db, tmpl.ID, press.ID, 1, 1, 1, &reps, nil, nil, &abs, nil, nil, 1, "", "")
+
+			a, _ := CreateAthlete(db, fmt.Sprintf("Rounding Athlete %d", i), "", "", "", "", "", "", sql.NullInt64{}, true)
+			SetTrainingMax(db, a.ID, squat.ID, 245, "2026-01-01", "")
+			ap, err := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+			if err != nil {
+				t.Fatalf("assign program: %v", err)
+			}
+
+			rx, err := GetPrescription(db, ap, mustParseDate("2026-02-01"))
+			if err != nil {
+				t.Fatalf("get prescription: %v", err)
+			}
+			if len(rx.Lines) != 2 {
+				t.Fatalf("lines = %d, want 2", len(rx.Lines))
+			}
+			if got := *rx.Lines[0].TargetWeight; got != tt.want {
+				t.Errorf("target = %.2f, want %.2f", got, tt.want)
+			}
+			if got := *rx.Lines[1].TargetWeight; got != abs {
+				t.Errorf("absolute target = %.2f, want %.2f (unrounded)", got, abs)
+			}
+		})
+	}
+}
+
+func TestCreateProgramTemplate_InvalidRoundingMode(t *testing.T) {
+	db := testDB(t)
+	_, err := CreateProgramTemplate(db, nil, "Bad Rounding", "", 1, 1, false, "", 5, "sideways")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
+
 func TestCurrentTrainingMaxes(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "TM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	bench, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	squat, _ := CreateExercise(db, "Back Squat", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
 
 	// Set multiple TMs for bench (should return latest).
 	SetTrainingMax(db, a.ID, bench.ID, 180, "2026-01-01", "")
@@ -170,15 +235,15 @@ func TestGetPrescription_CycleWraparound(t *testing.T) {
 	db := testDB(t)
 
 	// 2 weeks × 2 days = 4 total positions.
-	tmpl, _ := CreateProgramTemplate(db, nil, "Short Cycle", "", 2, 2, false, "")
-	bench, _ := CreateExercise(db, "Bench", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Short Cycle", "", 2, 2, false, "", 0, "")
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
 
 	// Add sets for each day.
 	for w := 1; w <= 2; w++ {
 		for d := 1; d <= 2; d++ {
 			reps := 5
 			pct := 65.0
-			CreatePrescribedSet(db, tmpl.ID, bench.ID, w, d, 1, &reps, &pct, nil, 0, "", "")
+			CreatePrescribedSet(db, tmpl.ID, bench.ID, w, d, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "")
 		}
 	}
 
@@ -212,12 +277,12 @@ func TestGetPrescription_CycleWraparound(t *testing.T) {
 func TestGetPrescription_NoTrainingMax(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "No TM Test", "", 1, 1, false, "")
-	bench, _ := CreateExercise(db, "Bench", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "No TM Test", "", 1, 1, false, "", 0, "")
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
 
 	reps := 5
 	pct := 75.0
-	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &reps, &pct, nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "")
 
 	a, _ := CreateAthlete(db, "No TM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
 	// Deliberately do NOT set a training max.
@@ -251,10 +316,10 @@ func TestGetPrescription_NoTrainingMax(t *testing.T) {
 func TestGetPrescription_HasWorkoutToday(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Today Test", "", 1, 1, false, "")
-	bench, _ := CreateExercise(db, "Bench", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Today Test", "", 1, 1, false, "", 0, "")
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
 	reps := 5
-	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &reps, nil, nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
 
 	a, _ := CreateAthlete(db, "Today Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
 	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
@@ -275,7 +340,232 @@ func TestGetPrescription_HasWorkoutToday(t *testing.T) {
 	}
 }
 
+func TestGetPrescription_RestDay(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Rest Day Test", "", 1, 3, false, "", 0, "")
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	reps := 5
+	CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
+
+	a, _ := CreateAthlete(db, "Rest Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+
+	// No schedule: every day is a training day.
+	tuesday := mustParseDate("2026-02-03")
+	rx, _ := GetPrescription(db, ap, tuesday)
+	if rx.RestDay {
+		t.Error("expected no rest day without training days")
+	}
+
+	// Mon/Wed/Fri.
+	if err := SetTrainingDays(db, ap.ID, TrainingDaysMask([]int{1, 3, 5})); err != nil {
+		t.Fatalf("set training days: %v", err)
+	}
+	ap, _ = GetAthleteProgramByID(db, ap.ID)
+	if got := ap.TrainingDaysLabel(); got != "Mon, Wed, Fri" {
+		t.Errorf("label = %q, want Mon, Wed, Fri", got)
+	}
+
+	rx, _ = GetPrescription(db, ap, tuesday)
+	if !rx.RestDay {
+		t.Error("expected rest day on Tuesday")
+	}
+	if rx.NextTrainingDate != "2026-02-04" {
+		t.Errorf("next training date = %q, want 2026-02-04", rx.NextTrainingDate)
+	}
+	if rx.CurrentWeek != 1 || rx.CurrentDay != 1 {
+		t.Errorf("position = W%dD%d, want W1D1", rx.CurrentWeek, rx.CurrentDay)
+	}
+
+	rx, _ = GetPrescription(db, ap, mustParseDate("2026-02-04"))
+	if rx.RestDay || rx.NextTrainingDate != "" {
+		t.Errorf("Wednesday: rest=%v next=%q, want training day", rx.RestDay, rx.NextTrainingDate)
+	}
+
+	// Clearing the schedule.
+	if err := SetTrainingDays(db, ap.ID, 0); err != nil {
+		t.Fatalf("clear training days: %v", err)
+	}
+	ap, _ = GetAthleteProgramByID(db, ap.ID)
+	if ap.TrainingDays.Valid {
+		t.Error("expected training days cleared")
+	}
+
+	if err := SetTrainingDays(db, ap.ID, 128); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("mask 128: err = %v, want ErrInvalidInput", err)
+	}
+	if err := SetTrainingDays(db, 9999, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing assignment: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetCycleReport_SetCompletion(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Report Test", "", 1, 2, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	reps := 5
+	s1, _ := CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
+	s2, _ := CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 2, &reps, nil, nil, nil, nil, nil, 0, "", "")
+	s3, _ := CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 3, &reps, nil, nil, nil, nil, nil, 0, "", "")
+	s4, _ := CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 2, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
+
+	a, _ := CreateAthlete(db, "Report Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+
+	// Day 1: two of three squat sets logged. Day 2 not yet logged.
+	w, _ := CreateWorkout(db, a.ID, "2026-02-02", "", ap.ID)
+	AddSet(db, w.ID, squat.ID, 5, 225, 0, "reps", "", "")
+	AddSet(db, w.ID, squat.ID, 5, 225, 0, "reps", "", "")
+
+	done, err := CompletedPrescribedSets(db, a.ID, tmpl.ID)
+	if err != nil {
+		t.Fatalf("completed sets: %v", err)
+	}
+	want := map[int64]bool{s1.ID: true, s2.ID: true, s3.ID: false, s4.ID: false}
+	for id, exp := range want {
+		if done[id] != exp {
+			t.Errorf("set %d completed = %v, want %v", id, done[id], exp)
+		}
+	}
+
+	report, err := GetCycleReport(db, ap, mustParseDate("2026-02-03"))
+	if err != nil {
+		t.Fatalf("cycle report: %v", err)
+	}
+	if report.CompletedSets != 2 || report.TotalSets != 4 {
+		t.Errorf("adherence = %d/%d, want 2/4", report.CompletedSets, report.TotalSets)
+	}
+	if report.AdherencePercent() != 50 {
+		t.Errorf("adherence percent = %d, want 50", report.AdherencePercent())
+	}
+	if !report.Days[0].Logged || report.Days[1].Logged {
+		t.Errorf("logged days = %v/%v, want true/false", report.Days[0].Logged, report.Days[1].Logged)
+	}
+	if sets := report.Days[0].Lines[0].Sets; !sets[0].Completed || sets[2].Completed {
+		t.Errorf("day 1 completion = %v %v %v, want true true false", sets[0].Completed, sets[1].Completed, sets[2].Completed)
+	}
+
+	// Unassigned template yields an empty map.
+	other, _ := CreateProgramTemplate(db, nil, "Other", "", 1, 1, false, "", 0, "")
+	done, err = CompletedPrescribedSets(db, a.ID, other.ID)
+	if err != nil || len(done) != 0 {
+		t.Errorf("unassigned template: %v, %v; want empty map", done, err)
+	}
+}
+
+func TestRoundTrainingMax(t *testing.T) {
+	ap := &AthleteProgram{RoundingIncrement: 5, RoundingMode: RoundNearest}
+
+	w, notes := ap.RoundTrainingMax(202.3, "Initial TM setup")
+	if w != 200 || notes != "Initial TM setup (rounded from 202.3 to 200)" {
+		t.Errorf("got %v %q", w, notes)
+	}
+	w, notes = ap.RoundTrainingMax(205, "Initial TM setup")
+	if w != 205 || notes != "Initial TM setup" {
+		t.Errorf("exact multiple: got %v %q", w, notes)
+	}
+	w, notes = ap.RoundTrainingMax(97.5, "")
+	if w != 100 || notes != "rounded from 97.5 to 100" {
+		t.Errorf("empty notes: got %v %q", w, notes)
+	}
+}
+
+func TestListProgramExerciseTMs_Rounded(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "TM Round", "", 1, 1, false, "", 5, RoundDown)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	reps := 5
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, ptrFloat(80), nil, nil, nil, 0, "", "")
+
+	a, _ := CreateAthlete(db, "TM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	SetTrainingMax(db, a.ID, squat.ID, 204.4, "2026-01-01", "")
+
+	tms, err := ListProgramExerciseTMs(db, tmpl.ID, a.ID)
+	if err != nil {
+		t.Fatalf("list program exercise TMs: %v", err)
+	}
+	if len(tms) != 1 || tms[0].CurrentTM == nil || *tms[0].CurrentTM != 200 {
+		t.Errorf("current TM = %v, want 200", tms)
+	}
+}
+
 // ptrFloat returns a pointer to a float64 value.
 func ptrFloat(v float64) *float64 {
 	return &v
 }
+
+func TestGetPrescription_IncludesAccessories(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Accessory Test", "", 1, 2, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	facePull, _ := CreateExercise(db, "Face Pull", "", "", "", "", 0)
+	curl, _ := CreateExercise(db, "Curl", "", "", "", "", 0)
+
+	reps := 5
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
+
+	a, _ := CreateAthlete(db, "Accessory Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	CreateAccessoryPlan(db, a.ID, 1, facePull.ID, 3, 12, 12, 0, "", 0, false)
+	CreateAccessoryPlan(db, a.ID, 2, curl.ID, 3, 10, 10, 0, "", 0, false)
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+
+	rx, err := GetPrescription(db, ap, mustParseDate("2026-02-01"))
+	if err != nil {
+		t.Fatalf("get prescription: %v", err)
+	}
+	if len(rx.Accessories) != 1 {
+		t.Fatalf("accessories = %d, want 1 (day 1 only)", len(rx.Accessories))
+	}
+	if got := rx.Accessories[0]; got.ExerciseName != "Face Pull" || got.RepRangeLabel() != "3×12" {
+		t.Errorf("accessory = %s %s, want Face Pull 3×12", got.ExerciseName, got.RepRangeLabel())
+	}
+}
+
+func TestGetPrescription_RestSeconds(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Rest Test", "", 1, 1, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 180)
+	curl, _ := CreateExercise(db, "Curl", "", "", "", "", 0)
+	reps := 5
+	topSetRest := 240
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &reps, nil, nil, nil, nil, &topSetRest, 1, "", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 2, &reps, nil, nil, nil, nil, nil, 1, "", "")
+	CreatePrescribedSet(db, tmpl.ID, curl.ID, 1, 1, 1, &reps, nil, nil, nil, nil, nil, 2, "", "")
+
+	a, _ := CreateAthlete(db, "Rest Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
+
+	rx, err := GetPrescription(db, ap, mustParseDate("2026-02-01"))
+	if err != nil {
+		t.Fatalf("get prescription: %v", err)
+	}
+	if len(rx.Lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(rx.Lines))
+	}
+
+	squatLine, curlLine := rx.Lines[0], rx.Lines[1]
+	if got := squatLine.Sets[0].Rest; got != 240 {
+		t.Errorf("squat top set rest = %d, want 240 (set override)", got)
+	}
+	if got := squatLine.Sets[1].Rest; got != 180 {
+		t.Errorf("squat back-off rest = %d, want 180 (exercise rest)", got)
+	}
+	if got := squatLine.Sets[0].RestLabel(); got != "4 min" {
+		t.Errorf("squat top set RestLabel = %q, want 4 min", got)
+	}
+	if got := squatLine.Sets[1].RestLabel(); got != "" {
+		t.Errorf("squat back-off RestLabel = %q, want empty without override", got)
+	}
+	if got := curlLine.RestSeconds; got != DefaultRestSeconds {
+		t.Errorf("curl rest = %d, want default %d", got, DefaultRestSeconds)
+	}
+	if got := curlLine.RestLabel(); got != "90s" {
+		t.Errorf("curl RestLabel = %q, want 90s", got)
+	}
+}
@@ -11,6 +11,37 @@ import (
 // ErrTemplateInUse is returned when deleting a template that has active athlete assignments.
 var ErrTemplateInUse = errors.New("program template is in use by one or more athletes")
 
+// ErrDuplicateTemplateName is returned when a global template name is already taken.
+var ErrDuplicateTemplateName = errors.New("duplicate program template name")
+
+// Rounding modes for percentage-based target weights.
+const (
+	RoundNearest = "nearest"
+	RoundDown    = "down"
+	RoundUp      = "up"
+)
+
+// DefaultRoundingIncrement is the plate increment used when a template does
+// not specify one.
+const DefaultRoundingIncrement = 5.0
+
+// normalizeRounding applies defaults to a rounding increment and mode.
+// A non-positive increment uses DefaultRoundingIncrement and an empty mode
+// uses RoundNearest. Unknown modes return ErrInvalidInput.
+func normalizeRounding(increment float64, mode string) (float64, string, error) {
+	if increment <= 0 {
+		increment = DefaultRoundingIncrement
+	}
+	switch mode {
+	case "":
+		mode = RoundNearest
+	case RoundNearest, RoundDown, RoundUp:
+	default:
+		return 0, "", fmt.Errorf("models: unknown rounding mode %q: %w", mode, ErrInvalidInput)
+	}
+	return increment, mode, nil
+}
+
 // ProgramTemplate represents a reusable training program structure.
 // AthleteID nil = global/shared template; non-nil = athlete-specific (e.g. AI-generated).
 type ProgramTemplate struct {
@@ -25,15 +56,48 @@ type ProgramTemplate struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 
+	// RoundingIncrement and RoundingMode control how percentage-based
+	// target weights are rounded to loadable plates.
+	RoundingIncrement float64
+	RoundingMode      string // "nearest", "down", or "up"
+
 	// Joined fields populated by detail queries.
 	AthleteCount int
 	AthleteName  string // populated by athlete-scoped listing queries
 }
 
+// ProgramTemplateHashes returns the stored content hash of every template
+// that has one, keyed by template ID. Only imported templates whose sets are
+// unchanged since import carry a hash.
+func ProgramTemplateHashes(db *sql.DB) (map[int64]string, error) {
+	rows, err := db.Query(`SELECT id, content_hash FROM program_templates WHERE content_hash IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list program template hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, fmt.Errorf("models: scan program template hash: %w", err)
+		}
+		hashes[id] = hash
+	}
+	return hashes, rows.Err()
+}
+
 // CreateProgramTemplate inserts a new program template.
 // athleteID nil = global template, non-nil = athlete-scoped.
-// audience is "youth", "adult", or "" (NULL).
-func CreateProgramTemplate(db *sql.DB, athleteID *int64, name, description string, numWeeks, numDays int, isLoop bool, audience string) (*ProgramTemplate, error) {
+// audience is "youth", "adult", or "" (NULL). A zero roundingIncrement and
+// empty roundingMode use the defaults (5, nearest).
+func CreateProgramTemplate(db *sql.DB, athleteID *int64, name, description string, numWeeks, numDays int, isLoop bool, audience string, roundingIncrement float64, roundingMode string) (*ProgramTemplate, error) {
+	roundingIncrement, roundingMode, err := normalizeRounding(roundingIncrement, roundingMode)
+	if err != nil {
+		return nil, err
+	}
+
 	var descVal sql.NullString
 	if description != "" {
 		descVal = sql.NullString{String: description, Valid: true}
@@ -50,9 +114,9 @@ func CreateProgramTemplate(db *sql.DB, athleteID *int64, name, description strin
 	}
 
 	var id int64
-	err := db.QueryRow(
-		`INSERT INTO program_templates (athlete_id, name, description, num_weeks, num_days, is_loop, audience) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id`,
-		athleteID, name, descVal, numWeeks, numDays, isLoopInt, audVal,
+	err = db.QueryRow(
+		`INSERT INTO program_templates (athlete_id, name, description, num_weeks, num_days, is_loop, audience, rounding_increment, rounding_mode) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		athleteID, name, descVal, numWeeks, numDays, isLoopInt, audVal, roundingIncrement, roundingMode,
 	).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -68,14 +132,14 @@ func CreateProgramTemplate(db *sql.DB, athleteID *int64, name, description strin
 func GetProgramTemplateByID(db *sql.DB, id int64) (*ProgramTemplate, error) {
 	t := &ProgramTemplate{}
 	err := db.QueryRow(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
 		 WHERE pt.id = ?
 		 GROUP BY pt.id`,
 		id,
-	).Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount)
+	).Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("models: program template %d not found", id)
@@ -88,7 +152,7 @@ func GetProgramTemplateByID(db *sql.DB, id int64) (*ProgramTemplate, error) {
 // ListProgramTemplates returns all program templates ordered by name.
 func ListProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	rows, err := db.Query(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -103,7 +167,7 @@ func ListProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
 			return nil, fmt.Errorf("models: scan program template: %w", err)
 		}
 		templates = append(templates, t)
@@ -119,7 +183,7 @@ func ListProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 // (e.g. AI-generated) are excluded — they are managed from the athlete page.
 func ListGlobalProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	rows, err := db.Query(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -135,7 +199,7 @@ func ListGlobalProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
 			return nil, fmt.Errorf("models: scan global program template: %w", err)
 		}
 		templates = append(templates, t)
@@ -152,7 +216,7 @@ func ListGlobalProgramTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 // section for athlete-specific programs.
 func ListAthleteScopedTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	rows, err := db.Query(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count, a.name AS athlete_name
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -169,7 +233,7 @@ func ListAthleteScopedTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount, &t.AthleteName); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount, &t.AthleteName); err != nil {
 			return nil, fmt.Errorf("models: scan athlete-scoped template: %w", err)
 		}
 		templates = append(templates, t)
@@ -186,7 +250,7 @@ func ListAthleteScopedTemplates(db *sql.DB) ([]*ProgramTemplate, error) {
 // athlete-facing views and program assignment forms.
 func ListProgramTemplatesForAthlete(db *sql.DB, athleteID int64) ([]*ProgramTemplate, error) {
 	rows, err := db.Query(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -203,7 +267,7 @@ func ListProgramTemplatesForAthlete(db *sql.DB, athleteID int64) ([]*ProgramTemp
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
 			return nil, fmt.Errorf("models: scan program template for athlete: %w", err)
 		}
 		templates = append(templates, t)
@@ -219,7 +283,7 @@ func ListProgramTemplatesForAthlete(db *sql.DB, athleteID int64) ([]*ProgramTemp
 // Returns templates ordered by name.
 func ListReferenceTemplatesByAudience(db *sql.DB, audience string) ([]*ProgramTemplate, error) {
 	rows, err := db.Query(
-		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+		`SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 		        COUNT(ap.id) AS athlete_count
 		 FROM program_templates pt
 		 LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -236,7 +300,7 @@ func ListReferenceTemplatesByAudience(db *sql.DB, audience string) ([]*ProgramTe
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
 			return nil, fmt.Errorf("models: scan reference template: %w", err)
 		}
 		templates = append(templates, t)
@@ -263,7 +327,7 @@ func ListProgramTemplatesByIDs(db *sql.DB, ids []int64) ([]*ProgramTemplate, err
 		args[i] = id
 	}
 
-	query := `SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.created_at, pt.updated_at,
+	query := `SELECT pt.id, pt.athlete_id, pt.name, pt.description, pt.num_weeks, pt.num_days, pt.is_loop, pt.audience, pt.rounding_increment, pt.rounding_mode, pt.created_at, pt.updated_at,
 	                  COUNT(ap.id) AS athlete_count
 	           FROM program_templates pt
 	           LEFT JOIN athlete_programs ap ON ap.template_id = pt.id AND ap.active = 1
@@ -280,7 +344,7 @@ func ListProgramTemplatesByIDs(db *sql.DB, ids []int64) ([]*ProgramTemplate, err
 	var templates []*ProgramTemplate
 	for rows.Next() {
 		t := &ProgramTemplate{}
-		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
+		if err := rows.Scan(&t.ID, &t.AthleteID, &t.Name, &t.Description, &t.NumWeeks, &t.NumDays, &t.IsLoop, &t.Audience, &t.RoundingIncrement, &t.RoundingMode, &t.CreatedAt, &t.UpdatedAt, &t.AthleteCount); err != nil {
 			return nil, fmt.Errorf("models: scan program template by ID: %w", err)
 		}
 		templates = append(templates, t)
@@ -292,7 +356,12 @@ func ListProgramTemplatesByIDs(db *sql.DB, ids []int64) ([]*ProgramTemplate, err
 }
 
 // UpdateProgramTemplate updates a program template's metadata.
-func UpdateProgramTemplate(db *sql.DB, id int64, name, description string, numWeeks, numDays int, isLoop bool) (*ProgramTemplate, error) {
+func UpdateProgramTemplate(db *sql.DB, id int64, name, description string, numWeeks, numDays int, isLoop bool, roundingIncrement float64, roundingMode string) (*ProgramTemplate, error) {
+	roundingIncrement, roundingMode, err := normalizeRounding(roundingIncrement, roundingMode)
+	if err != nil {
+		return nil, err
+	}
+
 	var descVal sql.NullString
 	if description != "" {
 		descVal = sql.NullString{String: description, Valid: true}
@@ -303,9 +372,9 @@ func UpdateProgramTemplate(db *sql.DB, id int64, name, description string, numWe
 		isLoopInt = 1
 	}
 
-	_, err := db.Exec(
-		`UPDATE program_templates SET name = ?, description = ?, num_weeks = ?, num_days = ?, is_loop = ? WHERE id = ?`,
-		name, descVal, numWeeks, numDays, isLoopInt, id,
+	_, err = db.Exec(
+		`UPDATE program_templates SET name = ?, description = ?, num_weeks = ?, num_days = ?, is_loop = ?, rounding_increment = ?, rounding_mode = ? WHERE id = ?`,
+		name, descVal, numWeeks, numDays, isLoopInt, roundingIncrement, roundingMode, id,
 	)
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -317,6 +386,101 @@ func UpdateProgramTemplate(db *sql.DB, id int64, name, description string, numWe
 	return GetProgramTemplateByID(db, id)
 }
 
+// CloneProgramTemplate deep-copies a program template, its prescribed sets,
+// and its progression rules in one transaction. The clone is global
+// (athlete_id NULL) and has no athlete assignments. Returns the new
+// template's ID.
+func CloneProgramTemplate(db *sql.DB, templateID int64, newName string) (int64, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return 0, fmt.Errorf("models: clone program template: name required: %w", ErrInvalidInput)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("models: clone program template begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	newID, err := cloneProgramTemplateTx(tx, templateID, newName)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("models: clone program template commit: %w", err)
+	}
+	return newID, nil
+}
+
+// cloneProgramTemplateTx does the work of CloneProgramTemplate inside tx.
+func cloneProgramTemplateTx(tx *sql.Tx, templateID int64, newName string) (int64, error) {
+	var newID int64
+	err := tx.QueryRow(
+		`INSERT INTO program_templates (athlete_id, name, description, num_weeks, num_days, is_loop, audience, rounding_increment, rounding_mode)
+		 SELECT NULL, ?, description, num_weeks, num_days, is_loop, audience, rounding_increment, rounding_mode
+		   FROM program_templates WHERE id = ?
+		 RETURNING id`,
+		newName, templateID,
+	).Scan(&newID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("models: clone program template %q: %w", newName, ErrDuplicateTemplateName)
+		}
+		return 0, fmt.Errorf("models: clone program template %d: %w", templateID, err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO prescribed_sets
+		   (template_id, week, day, exercise_id, set_number,
+		    reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes)
+		 SELECT ?, week, day, exercise_id, set_number,
+		        reps, rep_max, percentage, absolute_weight, target_rpe, rest_seconds, sort_order, rep_type, notes
+		   FROM prescribed_sets
+		  WHERE template_id = ?
+		  ORDER BY week, day, sort_order, set_number`,
+		newID, templateID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: clone prescribed sets for template %d: %w", templateID, err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO progression_rules (template_id, exercise_id, increment, condition, threshold)
+		 SELECT ?, exercise_id, increment, condition, threshold
+		   FROM progression_rules
+		  WHERE template_id = ?`,
+		newID, templateID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: clone progression rules for template %d: %w", templateID, err)
+	}
+	return newID, nil
+}
+
+// CloneName returns a default name for a copy of the template named name:
+// "<name> (Copy)", or "<name> (Copy N)" with the lowest N from 2 up that no
+// global template uses yet.
+func CloneName(db *sql.DB, name string) (string, error) {
+	candidate := name + " (Copy)"
+	for n := 2; ; n++ {
+		var taken bool
+		err := db.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM program_templates WHERE athlete_id IS NULL AND name = ?)`, candidate,
+		).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("models: check clone name %q: %w", candidate, err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (Copy %d)", name, n)
+	}
+}
+
 // DeleteProgramTemplate removes a program template. Fails if athletes are assigned to it.
 func DeleteProgramTemplate(db *sql.DB, id int64) error {
 	// Check for active athlete assignments.
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -9,7 +10,7 @@ func TestCreateProgramTemplate(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("basic create global", func(t *testing.T) {
-		tmpl, err := CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "")
+		tmpl, err := CreateProgramTemplate(db, nil, "5/3/1 BBB", "Boring But Big", 4, 4, false, "", 0, "")
 		if err != nil {
 			t.Fatalf("create program template: %v", err)
 		}
@@ -32,7 +33,7 @@ func TestCreateProgramTemplate(t *testing.T) {
 
 	t.Run("athlete-scoped create", func(t *testing.T) {
 		a, _ := CreateAthlete(db, "Scoped Test", "", "", "", "", "", "", sql.NullInt64{}, true)
-		tmpl, err := CreateProgramTemplate(db, &a.ID, "Athlete Program", "", 3, 3, false, "")
+		tmpl, err := CreateProgramTemplate(db, &a.ID, "Athlete Program", "", 3, 3, false, "", 0, "")
 		if err != nil {
 			t.Fatalf("create athlete-scoped template: %v", err)
 		}
@@ -42,7 +43,7 @@ func TestCreateProgramTemplate(t *testing.T) {
 	})
 
 	t.Run("duplicate name global", func(t *testing.T) {
-		_, err := CreateProgramTemplate(db, nil, "5/3/1 BBB", "", 1, 1, false, "")
+		_, err := CreateProgramTemplate(db, nil, "5/3/1 BBB", "", 1, 1, false, "", 0, "")
 		if err == nil {
 			t.Error("expected error for duplicate name")
 		}
@@ -52,8 +53,8 @@ func TestCreateProgramTemplate(t *testing.T) {
 func TestListProgramTemplates(t *testing.T) {
 	db := testDB(t)
 
-	CreateProgramTemplate(db, nil, "Program A", "", 4, 4, false, "")
-	CreateProgramTemplate(db, nil, "Program B", "", 3, 3, false, "")
+	CreateProgramTemplate(db, nil, "Program A", "", 4, 4, false, "", 0, "")
+	CreateProgramTemplate(db, nil, "Program B", "", 3, 3, false, "", 0, "")
 
 	templates, err := ListProgramTemplates(db)
 	if err != nil {
@@ -71,10 +72,10 @@ func TestListAthleteScopedTemplates(t *testing.T) {
 	a2, _ := CreateAthlete(db, "Bob", "", "", "", "", "", "", sql.NullInt64{}, true)
 
 	// Global template — should NOT appear.
-	CreateProgramTemplate(db, nil, "Global Program", "", 4, 4, false, "")
+	CreateProgramTemplate(db, nil, "Global Program", "", 4, 4, false, "", 0, "")
 	// Athlete-scoped templates — should appear.
-	CreateProgramTemplate(db, &a1.ID, "Alice Custom", "", 3, 3, false, "")
-	CreateProgramTemplate(db, &a2.ID, "Bob Custom", "", 2, 2, false, "")
+	CreateProgramTemplate(db, &a1.ID, "Alice Custom", "", 3, 3, false, "", 0, "")
+	CreateProgramTemplate(db, &a2.ID, "Bob Custom", "", 2, 2, false, "", 0, "")
 
 	templates, err := ListAthleteScopedTemplates(db)
 	if err != nil {
@@ -101,11 +102,11 @@ func TestListProgramTemplatesForAthlete(t *testing.T) {
 	a2, _ := CreateAthlete(db, "Athlete Two", "", "", "", "", "", "", sql.NullInt64{}, true)
 
 	// Global template.
-	CreateProgramTemplate(db, nil, "Global Program", "", 4, 4, false, "")
+	CreateProgramTemplate(db, nil, "Global Program", "", 4, 4, false, "", 0, "")
 	// Athlete-1-scoped template.
-	CreateProgramTemplate(db, &a1.ID, "A1 Program", "", 3, 3, false, "")
+	CreateProgramTemplate(db, &a1.ID, "A1 Program", "", 3, 3, false, "", 0, "")
 	// Athlete-2-scoped template.
-	CreateProgramTemplate(db, &a2.ID, "A2 Program", "", 2, 2, false, "")
+	CreateProgramTemplate(db, &a2.ID, "A2 Program", "", 2, 2, false, "", 0, "")
 
 	t.Run("athlete 1 sees global + own", func(t *testing.T) {
 		templates, err := ListProgramTemplatesForAthlete(db, a1.ID)
@@ -160,14 +161,14 @@ func TestListProgramTemplatesForAthlete(t *testing.T) {
 	})
 
 	t.Run("same name allowed for different athletes", func(t *testing.T) {
-		_, err := CreateProgramTemplate(db, &a2.ID, "A1 Program", "", 1, 1, false, "")
+		_, err := CreateProgramTemplate(db, &a2.ID, "A1 Program", "", 1, 1, false, "", 0, "")
 		if err != nil {
 			t.Errorf("should allow same name for different athlete, got: %v", err)
 		}
 	})
 
 	t.Run("duplicate name within same athlete rejected", func(t *testing.T) {
-		_, err := CreateProgramTemplate(db, &a1.ID, "A1 Program", "", 1, 1, false, "")
+		_, err := CreateProgramTemplate(db, &a1.ID, "A1 Program", "", 1, 1, false, "", 0, "")
 		if err == nil {
 			t.Error("expected unique violation for duplicate name within same athlete")
 		}
@@ -178,13 +179,13 @@ func TestListReferenceTemplatesByAudience(t *testing.T) {
 	db := testDB(t)
 
 	// Create templates with different audiences.
-	CreateProgramTemplate(db, nil, "Youth Foundations", "For kids", 1, 2, true, "youth")
-	CreateProgramTemplate(db, nil, "5/3/1 Program", "For adults", 4, 4, true, "adult")
-	CreateProgramTemplate(db, nil, "No Audience", "Unclassified", 1, 1, false, "")
+	CreateProgramTemplate(db, nil, "Youth Foundations", "For kids", 1, 2, true, "youth", 0, "")
+	CreateProgramTemplate(db, nil, "5/3/1 Program", "For adults", 4, 4, true, "adult", 0, "")
+	CreateProgramTemplate(db, nil, "No Audience", "Unclassified", 1, 1, false, "", 0, "")
 
 	// Athlete-scoped template should NOT appear.
 	a, _ := CreateAthlete(db, "Aud Test", "", "", "", "", "", "", sql.NullInt64{}, true)
-	CreateProgramTemplate(db, &a.ID, "Athlete Program", "", 3, 3, false, "youth")
+	CreateProgramTemplate(db, &a.ID, "Athlete Program", "", 3, 3, false, "youth", 0, "")
 
 	t.Run("youth audience", func(t *testing.T) {
 		templates, err := ListReferenceTemplatesByAudience(db, "youth")
@@ -220,7 +221,7 @@ func TestCreateProgramTemplateAudience(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("youth audience", func(t *testing.T) {
-		tmpl, err := CreateProgramTemplate(db, nil, "Youth Prog", "", 1, 2, true, "youth")
+		tmpl, err := CreateProgramTemplate(db, nil, "Youth Prog", "", 1, 2, true, "youth", 0, "")
 		if err != nil {
 			t.Fatalf("create: %v", err)
 		}
@@ -230,7 +231,7 @@ func TestCreateProgramTemplateAudience(t *testing.T) {
 	})
 
 	t.Run("adult audience", func(t *testing.T) {
-		tmpl, err := CreateProgramTemplate(db, nil, "Adult Prog", "", 4, 4, false, "adult")
+		tmpl, err := CreateProgramTemplate(db, nil, "Adult Prog", "", 4, 4, false, "adult", 0, "")
 		if err != nil {
 			t.Fatalf("create: %v", err)
 		}
@@ -240,7 +241,7 @@ func TestCreateProgramTemplateAudience(t *testing.T) {
 	})
 
 	t.Run("no audience", func(t *testing.T) {
-		tmpl, err := CreateProgramTemplate(db, nil, "Generic Prog", "", 1, 1, false, "")
+		tmpl, err := CreateProgramTemplate(db, nil, "Generic Prog", "", 1, 1, false, "", 0, "")
 		if err != nil {
 			t.Fatalf("create: %v", err)
 		}
@@ -253,9 +254,9 @@ func TestCreateProgramTemplateAudience(t *testing.T) {
 func TestUpdateProgramTemplate(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Old Name", "", 4, 4, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "Old Name", "", 4, 4, false, "", 0, "")
 
-	updated, err := UpdateProgramTemplate(db, tmpl.ID, "New Name", "Updated description", 3, 3, false)
+	updated, err := UpdateProgramTemplate(db, tmpl.ID, "New Name", "Updated description", 3, 3, false, 0, "")
 	if err != nil {
 		t.Fatalf("update: %v", err)
 	}
@@ -271,14 +272,14 @@ func TestDeleteProgramTemplate(t *testing.T) {
 	db := testDB(t)
 
 	t.Run("delete unused", func(t *testing.T) {
-		tmpl, _ := CreateProgramTemplate(db, nil, "To Delete", "", 1, 1, false, "")
+		tmpl, _ := CreateProgramTemplate(db, nil, "To Delete", "", 1, 1, false, "", 0, "")
 		if err := DeleteProgramTemplate(db, tmpl.ID); err != nil {
 			t.Fatalf("delete: %v", err)
 		}
 	})
 
 	t.Run("delete in use", func(t *testing.T) {
-		tmpl, _ := CreateProgramTemplate(db, nil, "In Use", "", 1, 1, false, "")
+		tmpl, _ := CreateProgramTemplate(db, nil, "In Use", "", 1, 1, false, "", 0, "")
 		a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
 		_, err := AssignProgram(db, a.ID, tmpl.ID, "2026-02-01", "", "", "primary", "")
 		if err != nil {
@@ -295,13 +296,13 @@ func TestDeleteProgramTemplate(t *testing.T) {
 func TestPrescribedSets(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 4, false, "")
-	e, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Test Program", "", 4, 4, false, "", 0, "")
+	e, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 
 	t.Run("create prescribed set", func(t *testing.T) {
 		reps := 5
 		pct := 75.0
-		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 1, 1, 1, &reps, &pct, nil, 0, "", "heavy")
+		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 1, 1, 1, &reps, nil, &pct, nil, nil, nil, 0, "", "heavy")
 		if err != nil {
 			t.Fatalf("create prescribed set: %v", err)
 		}
@@ -318,7 +319,7 @@ func TestPrescribedSets(t *testing.T) {
 
 	t.Run("create AMRAP set (nil reps)", func(t *testing.T) {
 		pct := 85.0
-		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 1, 1, 2, nil, &pct, nil, 0, "", "")
+		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 1, 1, 2, nil, nil, &pct, nil, nil, nil, 0, "", "")
 		if err != nil {
 			t.Fatalf("create AMRAP set: %v", err)
 		}
@@ -330,6 +331,86 @@ func TestPrescribedSets(t *testing.T) {
 		}
 	})
 
+	t.Run("create and update rep range", func(t *testing.T) {
+		lo, hi := 8, 12
+		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 3, 1, 1, &lo, &hi, nil, nil, nil, nil, 0, "", "")
+		if err != nil {
+			t.Fatalf("create rep range set: %v", err)
+		}
+		if !ps.RepMax.Valid || ps.RepMax.Int64 != 12 {
+			t.Errorf("rep_max = %v, want 12", ps.RepMax)
+		}
+		if ps.RepsLabel() != "8-12" {
+			t.Errorf("RepsLabel = %q, want 8-12", ps.RepsLabel())
+		}
+
+		reps := 10
+		ps, err = UpdatePrescribedSet(db, ps.ID, e.ID, 1, &reps, nil, nil, nil, nil, nil, 0, "each_side", "")
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if ps.RepMax.Valid {
+			t.Errorf("rep_max should be NULL after update, got %v", ps.RepMax)
+		}
+		if ps.RepsLabel() != "10/ea" {
+			t.Errorf("RepsLabel = %q, want 10/ea", ps.RepsLabel())
+		}
+		DeletePrescribedSet(db, ps.ID)
+	})
+
+	t.Run("rep range must increase", func(t *testing.T) {
+		reps, same, lower := 8, 8, 6
+		for _, repMax := range []*int{&same, &lower} {
+			if _, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 3, 1, 1, &reps, repMax, nil, nil, nil, nil, 0, "", ""); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("create %d-%d: err = %v, want ErrInvalidInput", reps, *repMax, err)
+			}
+		}
+
+		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 3, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if _, err := UpdatePrescribedSet(db, ps.ID, e.ID, 1, &reps, &lower, nil, nil, nil, nil, 0, "", ""); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("update %d-%d: err = %v, want ErrInvalidInput", reps, lower, err)
+		}
+		if got, _ := GetPrescribedSetByID(db, ps.ID); got.RepMax.Valid {
+			t.Errorf("rejected update changed rep_max to %v", got.RepMax)
+		}
+
+		// AMRAP sets have no lower bound to compare against.
+		amrap, err := UpdatePrescribedSet(db, ps.ID, e.ID, 1, nil, &lower, nil, nil, nil, nil, 0, "", "")
+		if err != nil {
+			t.Fatalf("update AMRAP: %v", err)
+		}
+		if amrap.RepMax.Valid {
+			t.Errorf("AMRAP rep_max = %v, want NULL", amrap.RepMax)
+		}
+		DeletePrescribedSet(db, ps.ID)
+	})
+
+	t.Run("create with target RPE", func(t *testing.T) {
+		reps := 5
+		rpe := 8.5
+		ps, err := CreatePrescribedSet(db, tmpl.ID, e.ID, 3, 2, 1, &reps, nil, nil, nil, &rpe, nil, 0, "", "")
+		if err != nil {
+			t.Fatalf("create RPE set: %v", err)
+		}
+		if ps.TargetRPELabel() != "8.5" {
+			t.Errorf("TargetRPELabel = %q, want 8.5", ps.TargetRPELabel())
+		}
+
+		n, err := CopyWeek(db, tmpl.ID, 3, 4)
+		if err != nil || n != 1 {
+			t.Fatalf("copy week = %d, %v", n, err)
+		}
+		copied, _ := ListPrescribedSetsForDay(db, tmpl.ID, 4, 2)
+		if len(copied) != 1 || !copied[0].TargetRPE.Valid || copied[0].TargetRPE.Float64 != 8.5 {
+			t.Errorf("copied set target RPE = %+v, want 8.5", copied)
+		}
+		DeletePrescribedSet(db, ps.ID)
+		DeletePrescribedSet(db, copied[0].ID)
+	})
+
 	t.Run("list for day", func(t *testing.T) {
 		sets, err := ListPrescribedSetsForDay(db, tmpl.ID, 1, 1)
 		if err != nil {
@@ -352,7 +433,7 @@ func TestPrescribedSets(t *testing.T) {
 
 	t.Run("delete", func(t *testing.T) {
 		reps := 10
-		ps, _ := CreatePrescribedSet(db, tmpl.ID, e.ID, 2, 1, 1, &reps, nil, nil, 0, "", "")
+		ps, _ := CreatePrescribedSet(db, tmpl.ID, e.ID, 2, 1, 1, &reps, nil, nil, nil, nil, nil, 0, "", "")
 		if err := DeletePrescribedSet(db, ps.ID); err != nil {
 			t.Fatalf("delete: %v", err)
 		}
@@ -362,7 +443,7 @@ func TestPrescribedSets(t *testing.T) {
 func TestAthleteProgram(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "")
+	tmpl, _ := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "", 0, "")
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
 
 	t.Run("assign program", func(t *testing.T) {
@@ -421,23 +502,23 @@ func TestGetPrescription(t *testing.T) {
 	db := testDB(t)
 
 	// Set up template: 4 weeks × 4 days, with exercises on W1D1.
-	tmpl, _ := CreateProgramTemplate(db, nil, "Test 531", "", 4, 4, false, "")
-	bench, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	squat, _ := CreateExercise(db, "Back Squat", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Test 531", "", 4, 4, false, "", 0, "")
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
 
 	// W1D1: Bench 3×5 @ 65%, Squat 3×5 @ 65%
 	for i := 1; i <= 3; i++ {
 		reps := 5
 		pct := 65.0
-		CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, i, &reps, &pct, nil, 0, "", "")
-		CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, i, &reps, &pct, nil, 0, "", "")
+		CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, i, &reps, nil, &pct, nil, nil, nil, 0, "", "")
+		CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, i, &reps, nil, &pct, nil, nil, nil, 0, "", "")
 	}
 
 	// W1D2: Bench 3×3 @ 75%
 	for i := 1; i <= 3; i++ {
 		reps := 3
 		pct := 75.0
-		CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 2, i, &reps, &pct, nil, 0, "", "")
+		CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 2, i, &reps, nil, &pct, nil, nil, nil, 0, "", "")
 	}
 
 	a, _ := CreateAthlete(db, "Test Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
@@ -520,17 +601,17 @@ func TestGetPrescription(t *testing.T) {
 func TestCopyWeek(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, _ := CreateProgramTemplate(db, nil, "Copy Test", "", 3, 3, false, "")
-	e1, _ := CreateExercise(db, "Squat", "", "", "", 0)
-	e2, _ := CreateExercise(db, "Bench", "", "", "", 0)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Copy Test", "", 3, 3, false, "", 0, "")
+	e1, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	e2, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
 
 	// Populate week 1 with sets across two days.
 	r5 := 5
 	r10 := 10
 	pct := 75.0
-	CreatePrescribedSet(db, tmpl.ID, e1.ID, 1, 1, 1, &r5, &pct, nil, 1, "", "")
-	CreatePrescribedSet(db, tmpl.ID, e1.ID, 1, 1, 2, &r5, &pct, nil, 1, "", "")
-	CreatePrescribedSet(db, tmpl.ID, e2.ID, 1, 2, 1, &r10, nil, nil, 2, "", "notes here")
+	CreatePrescribedSet(db, tmpl.ID, e1.ID, 1, 1, 1, &r5, nil, &pct, nil, nil, nil, 1, "", "")
+	CreatePrescribedSet(db, tmpl.ID, e1.ID, 1, 1, 2, &r5, nil, &pct, nil, nil, nil, 1, "", "")
+	CreatePrescribedSet(db, tmpl.ID, e2.ID, 1, 2, 1, &r10, nil, nil, nil, nil, nil, 2, "", "notes here")
 
 	t.Run("copy to empty week", func(t *testing.T) {
 		inserted, err := CopyWeek(db, tmpl.ID, 1, 2)
@@ -560,9 +641,9 @@ func TestCopyWeek(t *testing.T) {
 	t.Run("copy replaces existing sets in target week", func(t *testing.T) {
 		// Week 2 already has 3 sets from the previous subtest.
 		// Add an extra set to week 2 that doesn't exist in week 1.
-		e3, _ := CreateExercise(db, "Deadlift", "", "", "", 0)
+		e3, _ := CreateExercise(db, "Deadlift", "", "", "", "", 0)
 		r8 := 8
-		CreatePrescribedSet(db, tmpl.ID, e3.ID, 2, 3, 1, &r8, nil, nil, 0, "", "")
+		CreatePrescribedSet(db, tmpl.ID, e3.ID, 2, 3, 1, &r8, nil, nil, nil, nil, nil, 0, "", "")
 
 		// Copy week 1 → week 2 again; should replace all 4 sets with 3.
 		inserted, err := CopyWeek(db, tmpl.ID, 1, 2)
@@ -599,3 +680,317 @@ func TestCopyWeek(t *testing.T) {
 		}
 	})
 }
+
+func TestCopyPrescribedWeek(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Block", "", 4, 2, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	r5 := 5
+	pct := 70.0
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &r5, nil, &pct, nil, nil, nil, 0, "", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 2, 1, &r5, nil, &pct, nil, nil, nil, 0, "", "")
+	// Week 3 has a stale set that should be replaced.
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 3, 1, 5, &r5, nil, &pct, nil, nil, nil, 0, "", "")
+
+	t.Run("copies to every target", func(t *testing.T) {
+		counts, err := CopyPrescribedWeek(db, tmpl.ID, 1, []int{2, 3, 4})
+		if err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+		for _, w := range []int{2, 3, 4} {
+			if counts[w] != 2 {
+				t.Errorf("week %d inserted = %d, want 2", w, counts[w])
+			}
+		}
+		sets, _ := ListPrescribedSets(db, tmpl.ID)
+		perWeek := make(map[int]int)
+		for _, s := range sets {
+			perWeek[s.Week]++
+		}
+		for w := 1; w <= 4; w++ {
+			if perWeek[w] != 2 {
+				t.Errorf("week %d has %d sets, want 2", w, perWeek[w])
+			}
+		}
+	})
+
+	t.Run("rejects copying onto itself", func(t *testing.T) {
+		_, err := CopyPrescribedWeek(db, tmpl.ID, 1, []int{2, 1})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestGenerateDeloadWeek(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Linear", "", 2, 1, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	pullup, _ := CreateExercise(db, "Pull-up", "", "", "", "", 0)
+	r5, r10 := 5, 10
+	pct := 80.0
+	abs := 100.0
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 2, 1, 1, &r5, nil, &pct, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 2, 1, 2, &r10, nil, nil, &abs, nil, nil, 1, "reps", "")
+	CreatePrescribedSet(db, tmpl.ID, pullup.ID, 2, 1, 1, &r10, nil, nil, nil, nil, nil, 2, "reps", "")
+
+	t.Run("appends scaled week", func(t *testing.T) {
+		week, err := GenerateDeloadWeek(db, tmpl.ID, 2, 0.6)
+		if err != nil {
+			t.Fatalf("generate deload: %v", err)
+		}
+		if week != 3 {
+			t.Errorf("new week = %d, want 3", week)
+		}
+
+		got, _ := GetProgramTemplateByID(db, tmpl.ID)
+		if got.NumWeeks != 3 {
+			t.Errorf("num_weeks = %d, want 3", got.NumWeeks)
+		}
+
+		sets, err := ListPrescribedSetsForDay(db, tmpl.ID, 3, 1)
+		if err != nil {
+			t.Fatalf("list sets: %v", err)
+		}
+		if len(sets) != 3 {
+			t.Fatalf("deload sets = %d, want 3", len(sets))
+		}
+		if sets[0].Percentage.Float64 != 48 || sets[0].Reps.Int64 != 3 {
+			t.Errorf("percentage set = %.1f%% x %d, want 48%% x 3", sets[0].Percentage.Float64, sets[0].Reps.Int64)
+		}
+		if sets[1].AbsoluteWeight.Float64 != 60 || sets[1].Reps.Int64 != 6 {
+			t.Errorf("absolute set = %.1f x %d, want 60 x 6", sets[1].AbsoluteWeight.Float64, sets[1].Reps.Int64)
+		}
+		if sets[2].Reps.Int64 != 10 || sets[2].Percentage.Valid || sets[2].AbsoluteWeight.Valid {
+			t.Errorf("bodyweight set changed: reps=%d", sets[2].Reps.Int64)
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		if _, err := GenerateDeloadWeek(db, tmpl.ID, 2, 1.5); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("load factor 1.5: err = %v, want ErrInvalidInput", err)
+		}
+		if _, err := GenerateDeloadWeek(db, tmpl.ID, 1, 0.6); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("empty source week: err = %v, want ErrInvalidInput", err)
+		}
+		if _, err := GenerateDeloadWeek(db, 9999, 1, 0.6); !errors.Is(err, ErrNotFound) {
+			t.Errorf("missing template: err = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestCloneProgramTemplate(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Owner", "", "", "", "", "", "", sql.NullInt64{}, true)
+	src, _ := CreateProgramTemplate(db, &a.ID, "Original", "Base block", 3, 2, true, "adult", 2.5, RoundDown)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	r5 := 5
+	pct := 75.0
+	CreatePrescribedSet(db, src.ID, squat.ID, 1, 1, 1, &r5, nil, &pct, nil, nil, nil, 0, "reps", "")
+	CreatePrescribedSet(db, src.ID, squat.ID, 2, 2, 1, nil, nil, &pct, nil, nil, nil, 0, "reps", "")
+	SetProgressionRule(db, src.ID, squat.ID, 10, ConditionAMRAPMinReps, 8)
+	AssignProgram(db, a.ID, src.ID, "2026-02-01", "", "", "primary", "")
+
+	t.Run("deep copies", func(t *testing.T) {
+		newID, err := CloneProgramTemplate(db, src.ID, "Original v2")
+		if err != nil {
+			t.Fatalf("clone: %v", err)
+		}
+		clone, err := GetProgramTemplateByID(db, newID)
+		if err != nil {
+			t.Fatalf("get clone: %v", err)
+		}
+		if clone.AthleteID != nil {
+			t.Errorf("clone athlete_id = %v, want nil (global)", *clone.AthleteID)
+		}
+		if clone.AthleteCount != 0 {
+			t.Errorf("clone athlete count = %d, want 0", clone.AthleteCount)
+		}
+		if clone.NumWeeks != 3 || clone.NumDays != 2 || !clone.IsLoop || clone.RoundingIncrement != 2.5 || clone.RoundingMode != RoundDown {
+			t.Errorf("clone metadata not copied: %+v", clone)
+		}
+
+		sets, _ := ListPrescribedSets(db, newID)
+		if len(sets) != 2 {
+			t.Errorf("clone sets = %d, want 2", len(sets))
+		}
+		rules, _ := ListProgressionRules(db, newID)
+		if len(rules) != 1 || rules[0].ConditionLabel() != "AMRAP ≥ 8 reps" {
+			t.Errorf("clone rules = %+v, want one conditional rule", rules)
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		CreateProgramTemplate(db, nil, "Taken", "", 1, 1, false, "", 0, "")
+		if _, err := CloneProgramTemplate(db, src.ID, "Taken"); !errors.Is(err, ErrDuplicateTemplateName) {
+			t.Errorf("err = %v, want ErrDuplicateTemplateName", err)
+		}
+	})
+
+	t.Run("missing template", func(t *testing.T) {
+		if _, err := CloneProgramTemplate(db, 99999, "Ghost"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("name trimmed", func(t *testing.T) {
+		newID, err := CloneProgramTemplate(db, src.ID, "  Original v3  ")
+		if err != nil {
+			t.Fatalf("clone: %v", err)
+		}
+		if clone, _ := GetProgramTemplateByID(db, newID); clone.Name != "Original v3" {
+			t.Errorf("name = %q, want trimmed", clone.Name)
+		}
+		if _, err := CloneProgramTemplate(db, src.ID, "   "); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("blank name: err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestCloneName(t *testing.T) {
+	db := testDB(t)
+
+	name, err := CloneName(db, "Base")
+	if err != nil || name != "Base (Copy)" {
+		t.Fatalf("CloneName = %q, %v; want Base (Copy)", name, err)
+	}
+	CreateProgramTemplate(db, nil, "Base (Copy)", "", 1, 1, false, "", 0, "")
+	CreateProgramTemplate(db, nil, "Base (Copy 2)", "", 1, 1, false, "", 0, "")
+	if name, _ := CloneName(db, "Base"); name != "Base (Copy 3)" {
+		t.Errorf("CloneName = %q, want Base (Copy 3)", name)
+	}
+}
+
+func TestReorderPrescribedSets(t *testing.T) {
+	db := testDB(t)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Order", "", 2, 1, false, "", 0, "")
+	other, _ := CreateProgramTemplate(db, nil, "Other", "", 1, 1, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	r5 := 5
+	s1, _ := CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &r5, nil, nil, nil, nil, nil, 0, "reps", "")
+	s2, _ := CreatePrescribedSet(db, tmpl.ID, bench.ID, 1, 1, 1, &r5, nil, nil, nil, nil, nil, 1, "reps", "")
+	w2, _ := CreatePrescribedSet(db, tmpl.ID, bench.ID, 2, 1, 1, &r5, nil, nil, nil, nil, nil, 0, "reps", "")
+	foreign, _ := CreatePrescribedSet(db, other.ID, squat.ID, 1, 1, 1, &r5, nil, nil, nil, nil, nil, 0, "reps", "")
+
+	t.Run("rewrites sort order", func(t *testing.T) {
+		if err := ReorderPrescribedSets(db, tmpl.ID, []int64{s2.ID, s1.ID}); err != nil {
+			t.Fatalf("reorder: %v", err)
+		}
+		sets, _ := ListPrescribedSetsForDay(db, tmpl.ID, 1, 1)
+		if len(sets) != 2 || sets[0].ID != s2.ID || sets[1].ID != s1.ID {
+			t.Errorf("order = %d,%d, want %d,%d", sets[0].ID, sets[1].ID, s2.ID, s1.ID)
+		}
+	})
+
+	tests := []struct {
+		name string
+		ids  []int64
+	}{
+		{"foreign set", []int64{s1.ID, foreign.ID}},
+		{"different day", []int64{s1.ID, w2.ID}},
+		{"duplicate", []int64{s1.ID, s1.ID}},
+		{"empty", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ReorderPrescribedSets(db, tmpl.ID, tt.ids); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("err = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+
+	// Failed reorders must not have changed anything.
+	got, _ := GetPrescribedSetByID(db, s1.ID)
+	if got.SortOrder != 2 {
+		t.Errorf("s1 sort_order = %d, want 2 (unchanged after rejected reorders)", got.SortOrder)
+	}
+}
+
+func TestListAthleteProgramHistory(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "History", "", "", "", "", "", "", sql.NullInt64{}, true)
+	first, _ := CreateProgramTemplate(db, nil, "Block A", "", 1, 2, false, "", 0, "")
+	second, _ := CreateProgramTemplate(db, nil, "Block B", "", 1, 2, false, "", 0, "")
+
+	old, _ := AssignProgram(db, a.ID, first.ID, "2026-01-01", "", "", "primary", "")
+	CreateWorkout(db, a.ID, "2026-01-02", "", old.ID)
+	CreateWorkout(db, a.ID, "2026-01-04", "", old.ID)
+	DeactivateProgram(db, old.ID)
+	AssignProgram(db, a.ID, second.ID, "2026-02-01", "", "", "primary", "")
+
+	history, err := ListAthleteProgramHistory(db, a.ID)
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history = %d entries, want 2", len(history))
+	}
+
+	current, past := history[0], history[1]
+	if current.TemplateName != "Block B" || !current.Active || current.EndDate.Valid {
+		t.Errorf("current = %s active=%v end=%v, want active Block B with no end date", current.TemplateName, current.Active, current.EndDate)
+	}
+	if past.TemplateName != "Block A" || past.Active || !past.EndDate.Valid {
+		t.Errorf("past = %s active=%v end=%v, want inactive Block A with an end date", past.TemplateName, past.Active, past.EndDate)
+	}
+	if past.WorkoutsLogged != 2 {
+		t.Errorf("past workouts = %d, want 2", past.WorkoutsLogged)
+	}
+	if past.StartDate != "2026-01-01" {
+		t.Errorf("past start = %q, want 2026-01-01", past.StartDate)
+	}
+}
+
+func TestReassignProgram(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Lifter", "", "", "", "", "", "", sql.NullInt64{}, true)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Block", "", 3, 2, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	r5 := 5
+	CreatePrescribedSet(db, tmpl.ID, squat.ID, 1, 1, 1, &r5, nil, nil, nil, nil, nil, 0, "reps", "")
+	prev, err := AssignProgram(db, a.ID, tmpl.ID, "2026-01-05", "", "", "primary", "")
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	SetTrainingDays(db, prev.ID, 0b0010101)
+	prev, _ = GetAthleteProgramByID(db, prev.ID)
+
+	ap, err := ReassignProgram(db, prev, "2026-03-01")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if ap.TemplateName != "Block (2026-03-01)" || ap.TemplateID == tmpl.ID {
+		t.Errorf("new assignment template = %d %q, want a clone named Block (2026-03-01)", ap.TemplateID, ap.TemplateName)
+	}
+	if !ap.Active || ap.Role != "primary" || !ap.TrainingDays.Valid || ap.TrainingDays.Int64 != 0b0010101 {
+		t.Errorf("new assignment = %+v, want active primary with training days copied", ap)
+	}
+	if sets, _ := ListPrescribedSets(db, ap.TemplateID); len(sets) != 1 {
+		t.Errorf("cloned sets = %d, want 1", len(sets))
+	}
+	if old, _ := GetAthleteProgramByID(db, prev.ID); old.Active {
+		t.Error("previous assignment still active")
+	}
+
+	t.Run("failure rolls back", func(t *testing.T) {
+		before, _ := ListProgramTemplates(db)
+		// Same day again: the clone name is taken.
+		old, _ := GetAthleteProgramByID(db, prev.ID)
+		if _, err := ReassignProgram(db, old, "2026-03-01"); !errors.Is(err, ErrDuplicateTemplateName) {
+			t.Fatalf("err = %v, want ErrDuplicateTemplateName", err)
+		}
+		if cur, _ := GetAthleteProgramByID(db, ap.ID); !cur.Active {
+			t.Error("assignment deactivated despite failed reassign")
+		}
+		if after, _ := ListProgramTemplates(db); len(after) != len(before) {
+			t.Errorf("templates = %d, want %d", len(after), len(before))
+		}
+	})
+}
@@ -5,14 +5,30 @@ import (
 	"fmt"
 )
 
+// ConditionAMRAPMinReps gates a progression rule on the athlete's last AMRAP
+// set for the exercise reaching at least Threshold reps.
+const ConditionAMRAPMinReps = "amrap_min_reps"
+
 // ProgressionRule defines a per-exercise TM increment for a program template.
 // After a cycle completes, the app suggests bumping the training max by this amount.
+// A rule with a Condition only suggests the bump when the condition is met.
 type ProgressionRule struct {
 	ID           int64
 	TemplateID   int64
 	ExerciseID   int64
 	Increment    float64
-	ExerciseName string // joined field
+	Condition    sql.NullString // NULL = unconditional
+	Threshold    sql.NullInt64  // rep threshold for ConditionAMRAPMinReps
+	ExerciseName string         // joined field
+}
+
+// ConditionLabel describes the rule's condition (e.g. "AMRAP ≥ 8 reps"),
+// or returns "" for unconditional rules.
+func (pr *ProgressionRule) ConditionLabel() string {
+	if pr.Condition.String == ConditionAMRAPMinReps && pr.Threshold.Valid {
+		return fmt.Sprintf("AMRAP ≥ %d reps", pr.Threshold.Int64)
+	}
+	return ""
 }
 
 // IncrementLabel returns a formatted increment string (e.g. "5", "10", "2.5").
@@ -24,13 +40,22 @@ func (pr *ProgressionRule) IncrementLabel() string {
 }
 
 // SetProgressionRule creates or updates a progression rule for a template+exercise.
-// Uses INSERT OR REPLACE to handle upserts cleanly.
-func SetProgressionRule(db *sql.DB, templateID, exerciseID int64, increment float64) (*ProgressionRule, error) {
-	_, err := db.Exec(
-		`INSERT INTO progression_rules (template_id, exercise_id, increment)
-		 VALUES (?, ?, ?)
-		 ON CONFLICT(template_id, exercise_id) DO UPDATE SET increment = excluded.increment`,
-		templateID, exerciseID, increment,
+// Uses INSERT OR REPLACE to handle upserts cleanly. condition is "" for an
+// unconditional rule or ConditionAMRAPMinReps with a positive threshold.
+func SetProgressionRule(db *sql.DB, templateID, exerciseID int64, increment float64, condition string, threshold int) (*ProgressionRule, error) {
+	condVal, thresholdVal, err := progressionConditionValues(condition, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(
+		`INSERT INTO progression_rules (template_id, exercise_id, increment, condition, threshold)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(template_id, exercise_id) DO UPDATE SET
+		     increment = excluded.increment,
+		     condition = excluded.condition,
+		     threshold = excluded.threshold`,
+		templateID, exerciseID, increment, condVal, thresholdVal,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("models: set progression rule for template %d exercise %d: %w", templateID, exerciseID, err)
@@ -39,16 +64,32 @@ func SetProgressionRule(db *sql.DB, templateID, exerciseID int64, increment floa
 	return GetProgressionRule(db, templateID, exerciseID)
 }
 
+// progressionConditionValues validates a rule condition and converts it to
+// nullable column values. Unconditional rules store NULL for both.
+func progressionConditionValues(condition string, threshold int) (sql.NullString, sql.NullInt64, error) {
+	switch condition {
+	case "":
+		return sql.NullString{}, sql.NullInt64{}, nil
+	case ConditionAMRAPMinReps:
+		if threshold < 1 {
+			return sql.NullString{}, sql.NullInt64{}, fmt.Errorf("models: progression threshold %d must be positive: %w", threshold, ErrInvalidInput)
+		}
+		return sql.NullString{String: condition, Valid: true}, sql.NullInt64{Int64: int64(threshold), Valid: true}, nil
+	default:
+		return sql.NullString{}, sql.NullInt64{}, fmt.Errorf("models: unknown progression condition %q: %w", condition, ErrInvalidInput)
+	}
+}
+
 // GetProgressionRule retrieves a single progression rule for a template+exercise.
 func GetProgressionRule(db *sql.DB, templateID, exerciseID int64) (*ProgressionRule, error) {
 	pr := &ProgressionRule{}
 	err := db.QueryRow(
-		`SELECT pr.id, pr.template_id, pr.exercise_id, pr.increment, e.name
+		`SELECT pr.id, pr.template_id, pr.exercise_id, pr.increment, pr.condition, pr.threshold, e.name
 		 FROM progression_rules pr
 		 JOIN exercises e ON e.id = pr.exercise_id
 		 WHERE pr.template_id = ? AND pr.exercise_id = ?`,
 		templateID, exerciseID,
-	).Scan(&pr.ID, &pr.TemplateID, &pr.ExerciseID, &pr.Increment, &pr.ExerciseName)
+	).Scan(&pr.ID, &pr.TemplateID, &pr.ExerciseID, &pr.Increment, &pr.Condition, &pr.Threshold, &pr.ExerciseName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -61,7 +102,7 @@ func GetProgressionRule(db *sql.DB, templateID, exerciseID int64) (*ProgressionR
 // ListProgressionRules returns all progression rules for a program template.
 func ListProgressionRules(db *sql.DB, templateID int64) ([]*ProgressionRule, error) {
 	rows, err := db.Query(
-		`SELECT pr.id, pr.template_id, pr.exercise_id, pr.increment, e.name
+		`SELECT pr.id, pr.template_id, pr.exercise_id, pr.increment, pr.condition, pr.threshold, e.name
 		 FROM progression_rules pr
 		 JOIN exercises e ON e.id = pr.exercise_id
 		 WHERE pr.template_id = ?
@@ -76,7 +117,7 @@ func ListProgressionRules(db *sql.DB, templateID int64) ([]*ProgressionRule, err
 	var rules []*ProgressionRule
 	for rows.Next() {
 		pr := &ProgressionRule{}
-		if err := rows.Scan(&pr.ID, &pr.TemplateID, &pr.ExerciseID, &pr.Increment, &pr.ExerciseName); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.TemplateID, &pr.ExerciseID, &pr.Increment, &pr.Condition, &pr.Threshold, &pr.ExerciseName); err != nil {
 			return nil, fmt.Errorf("models: scan progression rule: %w", err)
 		}
 		rules = append(rules, pr)
@@ -9,17 +9,17 @@ func TestSetProgressionRule(t *testing.T) {
 	db := testDB(t)
 
 	// Seed exercise and template.
-	ex, err := CreateExercise(db, "Squat", "", "", "", 0)
+	ex, err := CreateExercise(db, "Squat", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
-	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "")
+	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
 
 	t.Run("create new rule", func(t *testing.T) {
-		pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 10.0)
+		pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 10.0, "", 0)
 		if err != nil {
 			t.Fatalf("set progression rule: %v", err)
 		}
@@ -32,7 +32,7 @@ func TestSetProgressionRule(t *testing.T) {
 	})
 
 	t.Run("upsert updates existing", func(t *testing.T) {
-		pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 5.0)
+		pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 5.0, "", 0)
 		if err != nil {
 			t.Fatalf("upsert progression rule: %v", err)
 		}
@@ -54,21 +54,21 @@ func TestGetProgressionRule_NotFound(t *testing.T) {
 func TestListProgressionRules(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "")
+	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
-	squat, err := CreateExercise(db, "Squat", "", "", "", 0)
+	squat, err := CreateExercise(db, "Squat", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create squat: %v", err)
 	}
-	bench, err := CreateExercise(db, "Bench Press", "", "", "", 0)
+	bench, err := CreateExercise(db, "Bench Press", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create bench: %v", err)
 	}
 
-	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0)
-	SetProgressionRule(db, tmpl.ID, bench.ID, 5.0)
+	SetProgressionRule(db, tmpl.ID, squat.ID, 10.0, "", 0)
+	SetProgressionRule(db, tmpl.ID, bench.ID, 5.0, "", 0)
 
 	rules, err := ListProgressionRules(db, tmpl.ID)
 	if err != nil {
@@ -90,16 +90,16 @@ func TestListProgressionRules(t *testing.T) {
 func TestDeleteProgressionRule(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "")
+	tmpl, err := CreateProgramTemplate(db, nil, "5/3/1", "", 4, 4, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
-	ex, err := CreateExercise(db, "Squat", "", "", "", 0)
+	ex, err := CreateExercise(db, "Squat", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
 
-	pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 10.0)
+	pr, err := SetProgressionRule(db, tmpl.ID, ex.ID, 10.0, "", 0)
 	if err != nil {
 		t.Fatalf("set rule: %v", err)
 	}
@@ -142,16 +142,16 @@ func TestProgressionRule_IncrementLabel(t *testing.T) {
 func TestProgressionRule_CascadeDeleteTemplate(t *testing.T) {
 	db := testDB(t)
 
-	tmpl, err := CreateProgramTemplate(db, nil, "Temp", "", 1, 1, false, "")
+	tmpl, err := CreateProgramTemplate(db, nil, "Temp", "", 1, 1, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
-	ex, err := CreateExercise(db, "Squat", "", "", "", 0)
+	ex, err := CreateExercise(db, "Squat", "", "", "", "", 0)
 	if err != nil {
 		t.Fatalf("create exercise: %v", err)
 	}
 
-	_, err = SetProgressionRule(db, tmpl.ID, ex.ID, 10.0)
+	_, err = SetProgressionRule(db, tmpl.ID, ex.ID, 10.0, "", 0)
 	if err != nil {
 		t.Fatalf("set rule: %v", err)
 	}
@@ -169,3 +169,33 @@ func TestProgressionRule_CascadeDeleteTemplate(t *testing.T) {
 		t.Errorf("expected 0 rules after template cascade delete, got %d", len(rules))
 	}
 }
+
+func TestSetProgressionRule_Condition(t *testing.T) {
+	db := testDB(t)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Conditional", "", 1, 1, false, "", 0, "")
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	pr, err := SetProgressionRule(db, tmpl.ID, squat.ID, 10, ConditionAMRAPMinReps, 8)
+	if err != nil {
+		t.Fatalf("set conditional rule: %v", err)
+	}
+	if pr.ConditionLabel() != "AMRAP ≥ 8 reps" {
+		t.Errorf("label = %q, want AMRAP ≥ 8 reps", pr.ConditionLabel())
+	}
+
+	// Upserting without a condition clears it.
+	pr, err = SetProgressionRule(db, tmpl.ID, squat.ID, 10, "", 0)
+	if err != nil {
+		t.Fatalf("clear condition: %v", err)
+	}
+	if pr.Condition.Valid || pr.Threshold.Valid {
+		t.Errorf("condition not cleared: %+v", pr)
+	}
+
+	if _, err := SetProgressionRule(db, tmpl.ID, squat.ID, 10, ConditionAMRAPMinReps, 0); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("zero threshold: err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := SetProgressionRule(db, tmpl.ID, squat.ID, 10, "bogus", 3); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("unknown condition: err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PromptTemplate is a coach-edited replacement for the audience-specific
+// rules in the AI Coach system prompt. Tier is empty for a template that
+// covers the whole audience.
+type PromptTemplate struct {
+	ID           int64
+	Audience     string // "youth" or "adult"
+	Tier         string // "" or a youth tier
+	Instructions string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// validPromptTemplateKey reports whether audience and tier name a scope a
+// template can be stored for. Adult athletes have no tier.
+func validPromptTemplateKey(audience, tier string) bool {
+	switch audience {
+	case "adult":
+		return tier == ""
+	case "youth":
+		switch tier {
+		case "", "foundational", "intermediate", "sport_performance":
+			return true
+		}
+	}
+	return false
+}
+
+// SetPromptTemplate creates or replaces the template for audience and tier.
+// Returns ErrInvalidInput for an unknown scope or blank instructions.
+func SetPromptTemplate(db *sql.DB, audience, tier, instructions string) (*PromptTemplate, error) {
+	if !validPromptTemplateKey(audience, tier) {
+		return nil, fmt.Errorf("models: prompt template scope %q/%q: %w", audience, tier, ErrInvalidInput)
+	}
+	instructions = strings.TrimSpace(instructions)
+	if instructions == "" {
+		return nil, fmt.Errorf("models: prompt template instructions are required: %w", ErrInvalidInput)
+	}
+
+	t := &PromptTemplate{}
+	err := db.QueryRow(`
+		INSERT INTO prompt_templates (audience, tier, instructions)
+		VALUES (?, ?, ?)
+		ON CONFLICT(audience, tier) DO UPDATE SET
+			instructions = excluded.instructions,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, audience, tier, instructions, created_at, updated_at`,
+		audience, tier, instructions,
+	).Scan(&t.ID, &t.Audience, &t.Tier, &t.Instructions, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("models: set prompt template %s/%s: %w", audience, tier, err)
+	}
+	return t, nil
+}
+
+// DeletePromptTemplate removes the template for audience and tier so the
+// built-in rules apply again.
+func DeletePromptTemplate(db *sql.DB, audience, tier string) error {
+	result, err := db.Exec(`DELETE FROM prompt_templates WHERE audience = ? AND tier = ?`, audience, tier)
+	if err != nil {
+		return fmt.Errorf("models: delete prompt template %s/%s: %w", audience, tier, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListPromptTemplates returns every stored template, youth before adult and
+// audience-wide templates before tier-specific ones.
+func ListPromptTemplates(db *sql.DB) ([]*PromptTemplate, error) {
+	rows, err := db.Query(`
+		SELECT id, audience, tier, instructions, created_at, updated_at
+		FROM prompt_templates
+		ORDER BY audience DESC, tier`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list prompt templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*PromptTemplate
+	for rows.Next() {
+		t := &PromptTemplate{}
+		if err := rows.Scan(&t.ID, &t.Audience, &t.Tier, &t.Instructions, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan prompt template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// ResolvePromptTemplate returns the most specific template for an athlete
+// with the given tier: the template for that tier, then the audience-wide
+// one. An empty tier means an adult athlete. Returns ErrNotFound when no
+// template applies and the built-in rules should be used.
+func ResolvePromptTemplate(db *sql.DB, tier string) (*PromptTemplate, error) {
+	audience := "adult"
+	if tier != "" {
+		audience = "youth"
+	}
+
+	t := &PromptTemplate{}
+	err := db.QueryRow(`
+		SELECT id, audience, tier, instructions, created_at, updated_at
+		FROM prompt_templates
+		WHERE audience = ? AND tier IN (?, '')
+		ORDER BY tier = '' ASC
+		LIMIT 1`, audience, tier,
+	).Scan(&t.ID, &t.Audience, &t.Tier, &t.Instructions, &t.CreatedAt, &t.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: resolve prompt template for tier %q: %w", tier, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPromptTemplates(t *testing.T) {
+	db := testDB(t)
+
+	t.Run("invalid scope rejected", func(t *testing.T) {
+		if _, err := SetPromptTemplate(db, "adult", "foundational", "x"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("adult tier err = %v, want ErrInvalidInput", err)
+		}
+		if _, err := SetPromptTemplate(db, "youth", "", "  "); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("blank instructions err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("no templates", func(t *testing.T) {
+		if _, err := ResolvePromptTemplate(db, "foundational"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	if _, err := SetPromptTemplate(db, "youth", "", "youth rules"); err != nil {
+		t.Fatalf("set youth template: %v", err)
+	}
+	if _, err := SetPromptTemplate(db, "youth", "foundational", "foundational rules"); err != nil {
+		t.Fatalf("set foundational template: %v", err)
+	}
+	if _, err := SetPromptTemplate(db, "adult", "", "adult rules"); err != nil {
+		t.Fatalf("set adult template: %v", err)
+	}
+
+	t.Run("most specific wins", func(t *testing.T) {
+		cases := map[string]string{
+			"foundational": "foundational rules",
+			"intermediate": "youth rules",
+			"":             "adult rules",
+		}
+		for tier, want := range cases {
+			got, err := ResolvePromptTemplate(db, tier)
+			if err != nil {
+				t.Fatalf("resolve %q: %v", tier, err)
+			}
+			if got.Instructions != want {
+				t.Errorf("resolve %q = %q, want %q", tier, got.Instructions, want)
+			}
+		}
+	})
+
+	t.Run("set replaces", func(t *testing.T) {
+		if _, err := SetPromptTemplate(db, "youth", "", "  revised youth rules\n"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		got, _ := ResolvePromptTemplate(db, "intermediate")
+		if got == nil || got.Instructions != "revised youth rules" {
+			t.Errorf("resolve = %+v, want revised youth rules", got)
+		}
+		list, err := ListPromptTemplates(db)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if len(list) != 3 {
+			t.Fatalf("list len = %d, want 3", len(list))
+		}
+		if list[0].Audience != "youth" || list[0].Tier != "" || list[2].Audience != "adult" {
+			t.Errorf("list order = %s/%s .. %s/%s", list[0].Audience, list[0].Tier, list[2].Audience, list[2].Tier)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := DeletePromptTemplate(db, "youth", "foundational"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		got, _ := ResolvePromptTemplate(db, "foundational")
+		if got == nil || got.Tier != "" {
+			t.Errorf("after delete resolve = %+v, want audience-wide youth template", got)
+		}
+		if err := DeletePromptTemplate(db, "youth", "foundational"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("second delete err = %v, want ErrNotFound", err)
+		}
+	})
+}
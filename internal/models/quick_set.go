@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrAmbiguousExercise is returned when an exercise name matches more than
+// one exercise and none of them exactly.
+var ErrAmbiguousExercise = errors.New("exercise name matches more than one exercise")
+
+// QuickSet is a set entry parsed from a free-text quick-log line such as
+// "squat 5x5 225".
+type QuickSet struct {
+	ExerciseName string // empty when the line has only the set spec
+	Sets         int
+	Reps         int
+	Weight       float64
+	WeightUnit   string // "lbs" or "kg" if the weight had a unit suffix, else empty
+	RepType      string // empty unless a unit suffix was given; AddSet picks the default
+}
+
+// quickSetPattern matches "[name] [sets x] reps[unit] [@] [weight][unit]".
+// The weight separator is "@" or whitespace; a bare "reps weight" pair
+// without "x" is treated as a single set.
+var quickSetPattern = regexp.MustCompile(`^(?:(.+?)\s+)?(?:(\d+)\s*x\s*)?(\d+)\s*(s|sec|secs|yd|yds)?(?:(?:\s*@\s*|\s+)(\d+(?:\.\d+)?)\s*(lb|lbs|kg)?)?$`)
+
+// ParseQuickSet parses a quick-log line. Supported forms include
+// "squat 5x5 225", "bench 3x8@185", "plank 2x30s", "sled push 4x20yd" and
+// "deadlift 5@315". A trailing "s" on the reps logs seconds and "yd" logs
+// distance. Returns ErrInvalidInput if the line cannot be parsed.
+func ParseQuickSet(input string) (QuickSet, error) {
+	line := strings.ToLower(strings.TrimSpace(input))
+	line = strings.ReplaceAll(line, "×", "x")
+	line = strings.Join(strings.Fields(line), " ")
+
+	m := quickSetPattern.FindStringSubmatch(line)
+	if m == nil {
+		return QuickSet{}, fmt.Errorf("models: parse quick set %q: %w", input, ErrInvalidInput)
+	}
+	// A bare number ("squat 5") is too ambiguous to log.
+	if m[2] == "" && m[5] == "" {
+		return QuickSet{}, fmt.Errorf("models: parse quick set %q: %w", input, ErrInvalidInput)
+	}
+
+	qs := QuickSet{ExerciseName: strings.TrimSpace(m[1]), Sets: 1}
+	if m[2] != "" {
+		qs.Sets, _ = strconv.Atoi(m[2])
+	}
+	qs.Reps, _ = strconv.Atoi(m[3])
+	switch m[4] {
+	case "s", "sec", "secs":
+		qs.RepType = "seconds"
+	case "yd", "yds":
+		qs.RepType = "distance"
+	}
+	if m[5] != "" {
+		qs.Weight, _ = strconv.ParseFloat(m[5], 64)
+	}
+	switch m[6] {
+	case "lb", "lbs":
+		qs.WeightUnit = "lbs"
+	case "kg":
+		qs.WeightUnit = "kg"
+	}
+
+	if qs.Sets <= 0 || qs.Reps <= 0 {
+		return QuickSet{}, fmt.Errorf("models: parse quick set %q: %w", input, ErrInvalidInput)
+	}
+	return qs, nil
+}
+
+// ResolveExerciseName finds the exercise a quick-log name refers to. An exact
+// case-insensitive match on an exercise name or alias wins outright.
+// Otherwise the athlete's active assignments are searched first, then the
+// full catalog, for a single exercise whose name contains the input. Returns
+// ErrAmbiguousExercise if several exercises contain the input, or
+// ErrNotFound if none do.
+func ResolveExerciseName(db *sql.DB, athleteID int64, name string) (*Exercise, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrNotFound
+	}
+
+	ex, err := ResolveExerciseByNameOrAlias(db, name)
+	if err == nil {
+		return ex, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	scopes := []struct {
+		label string
+		query string
+		args  []any
+	}{
+		{"assigned", `
+			SELECT e.id
+			FROM athlete_exercises ae
+			JOIN exercises e ON e.id = ae.exercise_id
+			WHERE ae.athlete_id = ? AND ae.active = 1
+			  AND instr(LOWER(e.name), LOWER(?)) > 0
+			ORDER BY e.name COLLATE NOCASE`, []any{athleteID, name}},
+		{"catalog", `
+			SELECT id
+			FROM exercises
+			WHERE archived = 0 AND instr(LOWER(name), LOWER(?)) > 0
+			ORDER BY name COLLATE NOCASE`, []any{name}},
+	}
+
+	for _, scope := range scopes {
+		ids, err := queryExerciseMatches(db, scope.query, scope.args...)
+		if err != nil {
+			return nil, fmt.Errorf("models: resolve %s exercise %q: %w", scope.label, name, err)
+		}
+		switch len(ids) {
+		case 0:
+			continue
+		case 1:
+			return GetExerciseByID(db, ids[0])
+		default:
+			return nil, ErrAmbiguousExercise
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func queryExerciseMatches(db *sql.DB, query string, args ...any) ([]int64, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
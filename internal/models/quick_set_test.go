@@ -0,0 +1,86 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestParseQuickSet(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    QuickSet
+		wantErr bool
+	}{
+		{"squat 5x5 225", QuickSet{ExerciseName: "squat", Sets: 5, Reps: 5, Weight: 225}, false},
+		{"Bench Press 3x8@185", QuickSet{ExerciseName: "bench press", Sets: 3, Reps: 8, Weight: 185}, false},
+		{"3x8 @ 185lbs", QuickSet{Sets: 3, Reps: 8, Weight: 185, WeightUnit: "lbs"}, false},
+		{"squat 5x5 100kg", QuickSet{ExerciseName: "squat", Sets: 5, Reps: 5, Weight: 100, WeightUnit: "kg"}, false},
+		{"plank 2x30s", QuickSet{ExerciseName: "plank", Sets: 2, Reps: 30, RepType: "seconds"}, false},
+		{"sled push 4×20yd 90", QuickSet{ExerciseName: "sled push", Sets: 4, Reps: 20, Weight: 90, RepType: "distance"}, false},
+		{"deadlift 5@315", QuickSet{ExerciseName: "deadlift", Sets: 1, Reps: 5, Weight: 315}, false},
+		{"pull up 3x10", QuickSet{ExerciseName: "pull up", Sets: 3, Reps: 10}, false},
+		{"squat 5", QuickSet{}, true},
+		{"squat heavy", QuickSet{}, true},
+		{"squat 0x5 225", QuickSet{}, true},
+		{"", QuickSet{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseQuickSet(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("err = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseQuickSet(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveExerciseName(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Resolver", "", "", "", "", "", "", sql.NullInt64{}, true)
+	backSquat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	CreateExercise(db, "Front Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	incline, _ := CreateExercise(db, "Incline Bench Press", "", "", "", "", 0)
+	AssignExercise(db, a.ID, backSquat.ID, 0)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantID  int64
+		wantErr error
+	}{
+		{"assigned substring wins", "squat", backSquat.ID, nil},
+		{"exact match case-insensitive", "BENCH PRESS", bench.ID, nil},
+		{"unique catalog substring", "incline", incline.ID, nil},
+		{"ambiguous catalog substring", "bench", 0, ErrAmbiguousExercise},
+		{"no match", "curl", 0, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex, err := ResolveExerciseName(db, a.ID, tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ex.ID != tt.wantID {
+				t.Errorf("resolved %q to %d (%s), want %d", tt.input, ex.ID, ex.Name, tt.wantID)
+			}
+		})
+	}
+}
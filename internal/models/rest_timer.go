@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// activeTimerKey is the session key holding the in-progress rest timer.
+const activeTimerKey = "active_rest_timer"
+
+// SessionStore is the subset of the session manager used to persist
+// per-session state. *scs.SessionManager satisfies it.
+type SessionStore interface {
+	Put(ctx context.Context, key string, val interface{})
+	Get(ctx context.Context, key string) interface{}
+	Remove(ctx context.Context, key string)
+}
+
+// ActiveTimer is a rest timer started after logging a set. It is stored in
+// the user's session so the countdown survives page reloads.
+type ActiveTimer struct {
+	WorkoutID    int64
+	ExerciseID   int64
+	ExerciseName string
+	StartedAt    time.Time
+	Seconds      int // total rest duration
+}
+
+// Remaining returns the whole seconds left on the timer at now, or 0 if it
+// has expired.
+func (t *ActiveTimer) Remaining(now time.Time) int {
+	left := t.Seconds - int(now.Sub(t.StartedAt)/time.Second)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// SetActiveTimer stores the session's in-progress rest timer, replacing any
+// existing one.
+func SetActiveTimer(ctx context.Context, store SessionStore, timer *ActiveTimer) {
+	store.Put(ctx, activeTimerKey, timer)
+}
+
+// GetActiveTimer returns the session's rest timer, or nil if none is set.
+func GetActiveTimer(ctx context.Context, store SessionStore) *ActiveTimer {
+	timer, _ := store.Get(ctx, activeTimerKey).(*ActiveTimer)
+	return timer
+}
+
+// ClearActiveTimer removes the session's rest timer.
+func ClearActiveTimer(ctx context.Context, store SessionStore) {
+	store.Remove(ctx, activeTimerKey)
+}
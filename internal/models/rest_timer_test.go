@@ -0,0 +1,42 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// mapSessionStore is an in-memory SessionStore for tests.
+type mapSessionStore map[string]interface{}
+
+func (m mapSessionStore) Put(_ context.Context, key string, val interface{}) { m[key] = val }
+func (m mapSessionStore) Get(_ context.Context, key string) interface{}      { return m[key] }
+func (m mapSessionStore) Remove(_ context.Context, key string)               { delete(m, key) }
+
+func TestActiveTimer(t *testing.T) {
+	ctx := context.Background()
+	store := mapSessionStore{}
+
+	if got := GetActiveTimer(ctx, store); got != nil {
+		t.Fatalf("timer = %+v, want nil before set", got)
+	}
+
+	started := time.Now().Add(-30 * time.Second)
+	SetActiveTimer(ctx, store, &ActiveTimer{WorkoutID: 1, ExerciseID: 2, ExerciseName: "Squat", StartedAt: started, Seconds: 90})
+
+	got := GetActiveTimer(ctx, store)
+	if got == nil || got.ExerciseName != "Squat" {
+		t.Fatalf("timer = %+v, want Squat", got)
+	}
+	if rem := got.Remaining(started.Add(30 * time.Second)); rem != 60 {
+		t.Errorf("remaining = %d, want 60", rem)
+	}
+	if rem := got.Remaining(started.Add(5 * time.Minute)); rem != 0 {
+		t.Errorf("remaining after expiry = %d, want 0", rem)
+	}
+
+	ClearActiveTimer(ctx, store)
+	if got := GetActiveTimer(ctx, store); got != nil {
+		t.Errorf("timer = %+v, want nil after clear", got)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateReviewTemplate is returned when a coach already has a review
+// template with the same name.
+var ErrDuplicateReviewTemplate = errors.New("review template name already in use")
+
+// ReviewTemplate is a coach's canned workout review feedback.
+type ReviewTemplate struct {
+	ID        int64
+	CoachID   int64
+	Name      string // short label shown in the review form dropdown
+	Content   string // text inserted into the review notes
+	CreatedAt time.Time
+}
+
+// CreateReviewTemplate saves canned review feedback for a coach. Name and
+// content are required; a name the coach already uses returns
+// ErrDuplicateReviewTemplate.
+func CreateReviewTemplate(db *sql.DB, coachID int64, name, content string) (*ReviewTemplate, error) {
+	name = strings.TrimSpace(name)
+	content = strings.TrimSpace(content)
+	if name == "" || content == "" {
+		return nil, fmt.Errorf("models: review template name and content are required: %w", ErrInvalidInput)
+	}
+
+	t := &ReviewTemplate{}
+	err := db.QueryRow(
+		`INSERT INTO review_templates (coach_id, name, content) VALUES (?, ?, ?)
+		 RETURNING id, coach_id, name, content, created_at`,
+		coachID, name, content,
+	).Scan(&t.ID, &t.CoachID, &t.Name, &t.Content, &t.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateReviewTemplate
+		}
+		return nil, fmt.Errorf("models: create review template for coach %d: %w", coachID, err)
+	}
+	return t, nil
+}
+
+// DeleteReviewTemplate removes one of a coach's review templates. Returns
+// ErrNotFound if the template doesn't exist or belongs to another coach.
+func DeleteReviewTemplate(db *sql.DB, coachID, id int64) error {
+	result, err := db.Exec(`DELETE FROM review_templates WHERE id = ? AND coach_id = ?`, id, coachID)
+	if err != nil {
+		return fmt.Errorf("models: delete review template %d: %w", id, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListReviewTemplates returns a coach's review templates, ordered by name.
+func ListReviewTemplates(db *sql.DB, coachID int64) ([]*ReviewTemplate, error) {
+	rows, err := db.Query(`
+		SELECT id, coach_id, name, content, created_at
+		FROM review_templates
+		WHERE coach_id = ?
+		ORDER BY name COLLATE NOCASE`, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list review templates for coach %d: %w", coachID, err)
+	}
+	defer rows.Close()
+
+	var templates []*ReviewTemplate
+	for rows.Next() {
+		t := &ReviewTemplate{}
+		if err := rows.Scan(&t.ID, &t.CoachID, &t.Name, &t.Content, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("models: scan review template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestReviewTemplates(t *testing.T) {
+	db := testDB(t)
+	coach, _ := CreateUser(db, "coach", "", "password123", "", true, false, sql.NullInt64{})
+	other, _ := CreateUser(db, "other", "", "password123", "", true, false, sql.NullInt64{})
+
+	depth, err := CreateReviewTemplate(db, coach.ID, " Depth ", " Great bar speed, watch depth ")
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if depth.Name != "Depth" || depth.Content != "Great bar speed, watch depth" {
+		t.Errorf("template = %+v, want trimmed name and content", depth)
+	}
+	if _, err := CreateReviewTemplate(db, coach.ID, "brace", "Brace before every rep"); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := CreateReviewTemplate(db, other.ID, "Depth", "Another coach's wording"); err != nil {
+		t.Fatalf("same name for another coach: %v", err)
+	}
+
+	t.Run("duplicate name rejected", func(t *testing.T) {
+		_, err := CreateReviewTemplate(db, coach.ID, "Depth", "Something else")
+		if !errors.Is(err, ErrDuplicateReviewTemplate) {
+			t.Errorf("err = %v, want ErrDuplicateReviewTemplate", err)
+		}
+	})
+
+	t.Run("blank fields rejected", func(t *testing.T) {
+		if _, err := CreateReviewTemplate(db, coach.ID, "  ", "text"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("blank name: err = %v, want ErrInvalidInput", err)
+		}
+		if _, err := CreateReviewTemplate(db, coach.ID, "Name", " "); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("blank content: err = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("list is per coach and sorted", func(t *testing.T) {
+		list, err := ListReviewTemplates(db, coach.ID)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if len(list) != 2 || list[0].Name != "brace" || list[1].Name != "Depth" {
+			t.Errorf("list = %v, want [brace Depth]", list)
+		}
+	})
+
+	t.Run("delete is scoped to owner", func(t *testing.T) {
+		if err := DeleteReviewTemplate(db, other.ID, depth.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("other coach delete: err = %v, want ErrNotFound", err)
+		}
+		if err := DeleteReviewTemplate(db, coach.ID, depth.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		list, _ := ListReviewTemplates(db, coach.ID)
+		if len(list) != 1 {
+			t.Errorf("got %d templates after delete, want 1", len(list))
+		}
+	})
+}
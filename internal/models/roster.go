@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+// RosterAdherenceDays is the window, ending today, that roster adherence
+// covers.
+const RosterAdherenceDays = 7
+
+// RosterEntry summarizes one athlete for the coach roster view.
+type RosterEntry struct {
+	AthleteID       int64
+	AthleteName     string
+	Tier            sql.NullString
+	LastWorkoutDate sql.NullString // YYYY-MM-DD; NULL if the athlete never trained
+	ProgramName     sql.NullString // active primary program; NULL if none
+	PendingReviews  int
+
+	// Adherence over the last RosterAdherenceDays days: distinct days with
+	// a workout or check-in against the sessions the program schedules in
+	// that window.
+	// ExpectedSessions is 0 when the athlete has no active program.
+	LoggedSessions   int
+	ExpectedSessions int
+}
+
+// AdherencePercent returns logged sessions as a percentage of expected
+// sessions, capped at 100.
+func (e *RosterEntry) AdherencePercent() int {
+	if e.ExpectedSessions == 0 {
+		return 0
+	}
+	pct := int(math.Round(float64(e.LoggedSessions) / float64(e.ExpectedSessions) * 100))
+	if pct > 100 {
+		return 100
+	}
+	return pct
+}
+
+// RosterSummary returns a roster entry for each athlete, sorted so those
+// needing attention come first: athletes who never trained, then by oldest
+// last workout, then by most pending reviews. Archived athletes are left
+// out. If coachID is valid, only that
+// coach's athletes are included; pass sql.NullInt64{} for all athletes
+// (admin view).
+//
+// Expected sessions come from the program's training days when set, and
+// otherwise from the template's days per week.
+func RosterSummary(db *sql.DB, coachID sql.NullInt64) ([]*RosterEntry, error) {
+	today := time.Now()
+	windowStart := today.AddDate(0, 0, -(RosterAdherenceDays - 1)).Format("2006-01-02")
+
+	coachFilter := ""
+	args := []any{windowStart, today.Format("2006-01-02")}
+	if coachID.Valid {
+		coachFilter = "AND a.coach_id = ?"
+		args = append(args, coachID.Int64)
+	}
+
+	rows, err := db.Query(`
+		SELECT a.id, a.name, a.tier,
+		       (SELECT date(w.date) FROM workouts w WHERE w.athlete_id = a.id ORDER BY w.date DESC LIMIT 1),
+		       pt.name, pt.num_days, ap.training_days,
+		       (SELECT COUNT(*) FROM workouts w
+		        LEFT JOIN workout_reviews wr ON wr.workout_id = w.id
+		        WHERE w.athlete_id = a.id AND wr.id IS NULL AND w.completed_at IS NOT NULL),
+		       (SELECT COUNT(*) FROM (
+		            SELECT date(w.date) AS day FROM workouts w WHERE w.athlete_id = a.id
+		            UNION
+		            SELECT date(ci.date) FROM check_ins ci WHERE ci.athlete_id = a.id
+		        ) WHERE day BETWEEN date(?) AND date(?))
+		FROM athletes a
+		LEFT JOIN athlete_programs ap ON ap.athlete_id = a.id AND ap.active = 1 AND ap.role = 'primary'
+		LEFT JOIN program_templates pt ON pt.id = ap.template_id
+		WHERE a.archived = 0 `+coachFilter+`
+		ORDER BY a.name COLLATE NOCASE`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("models: roster summary: %w", err)
+	}
+	defer rows.Close()
+
+	var roster []*RosterEntry
+	for rows.Next() {
+		e := &RosterEntry{}
+		var daysPerWeek, trainingDays sql.NullInt64
+		if err := rows.Scan(&e.AthleteID, &e.AthleteName, &e.Tier, &e.LastWorkoutDate,
+			&e.ProgramName, &daysPerWeek, &trainingDays, &e.PendingReviews, &e.LoggedSessions); err != nil {
+			return nil, fmt.Errorf("models: scan roster entry: %w", err)
+		}
+		switch {
+		case trainingDays.Valid:
+			// A 7-day window contains each weekday exactly once.
+			e.ExpectedSessions = bits.OnesCount64(uint64(trainingDays.Int64))
+		case daysPerWeek.Valid:
+			e.ExpectedSessions = int(daysPerWeek.Int64)
+		}
+		roster = append(roster, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate roster: %w", err)
+	}
+
+	sort.SliceStable(roster, func(i, j int) bool {
+		a, b := roster[i], roster[j]
+		if a.LastWorkoutDate.Valid != b.LastWorkoutDate.Valid {
+			return !a.LastWorkoutDate.Valid
+		}
+		if a.LastWorkoutDate.String != b.LastWorkoutDate.String {
+			return a.LastWorkoutDate.String < b.LastWorkoutDate.String
+		}
+		return a.PendingReviews > b.PendingReviews
+	})
+	return roster, nil
+}
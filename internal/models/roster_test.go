@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestRosterSummary(t *testing.T) {
+	db := testDB(t)
+	coach := seedCoachUser(t, db)
+	coachID := sql.NullInt64{Int64: coach.ID, Valid: true}
+
+	day := func(offset int) string {
+		return time.Now().AddDate(0, 0, offset).Format("2006-01-02")
+	}
+
+	// Trained yesterday on a 3-day template: 1 of 3 sessions.
+	recent, _ := CreateAthlete(db, "Recent", "", "", "", "", "", "", coachID, true)
+	tmpl, _ := CreateProgramTemplate(db, nil, "Three Day", "", 4, 3, false, "", 0, "")
+	AssignProgram(db, recent.ID, tmpl.ID, "2020-01-01", "", "", "primary", "")
+	CreateWorkout(db, recent.ID, day(-1), "", 0)
+
+	// Trained long ago with two workouts awaiting review; every-day schedule.
+	stale, _ := CreateAthlete(db, "Stale", "", "", "", "", "", "", coachID, true)
+	ap, _ := AssignProgram(db, stale.ID, tmpl.ID, "2020-01-01", "", "", "primary", "")
+	SetTrainingDays(db, ap.ID, 127)
+	for _, d := range []int{-30, -31} {
+		w, _ := CreateWorkout(db, stale.ID, day(d), "", 0)
+		SetWorkoutPendingReview(db, w.ID)
+	}
+
+	// Never trained, no program.
+	CreateAthlete(db, "Never", "", "", "", "", "", "", coachID, true)
+
+	// Another coach's athlete.
+	CreateAthlete(db, "Elsewhere", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	roster, err := RosterSummary(db, coachID)
+	if err != nil {
+		t.Fatalf("roster summary: %v", err)
+	}
+	var names []string
+	for _, e := range roster {
+		names = append(names, e.AthleteName)
+	}
+	want := []string{"Never", "Stale", "Recent"}
+	if len(names) != len(want) {
+		t.Fatalf("roster = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("roster = %v, want %v", names, want)
+		}
+	}
+
+	never, staleEntry, recentEntry := roster[0], roster[1], roster[2]
+	if never.ExpectedSessions != 0 || never.ProgramName.Valid {
+		t.Errorf("Never: expected no program, got %+v", never)
+	}
+	if staleEntry.PendingReviews != 2 {
+		t.Errorf("Stale pending reviews = %d, want 2", staleEntry.PendingReviews)
+	}
+	if staleEntry.ExpectedSessions != 7 || staleEntry.AdherencePercent() != 0 {
+		t.Errorf("Stale adherence = %d/%d, want 0/7", staleEntry.LoggedSessions, staleEntry.ExpectedSessions)
+	}
+	if recentEntry.ProgramName.String != "Three Day" {
+		t.Errorf("Recent program = %q, want Three Day", recentEntry.ProgramName.String)
+	}
+	if recentEntry.LoggedSessions != 1 || recentEntry.ExpectedSessions != 3 || recentEntry.AdherencePercent() != 33 {
+		t.Errorf("Recent adherence = %d/%d (%d%%), want 1/3 (33%%)",
+			recentEntry.LoggedSessions, recentEntry.ExpectedSessions, recentEntry.AdherencePercent())
+	}
+
+	t.Run("all athletes without coach filter", func(t *testing.T) {
+		all, err := RosterSummary(db, sql.NullInt64{})
+		if err != nil {
+			t.Fatalf("roster summary: %v", err)
+		}
+		if len(all) != 4 {
+			t.Errorf("got %d athletes, want 4", len(all))
+		}
+	})
+}
+
+func TestRosterEntryAdherencePercent(t *testing.T) {
+	tests := []struct {
+		logged, expected, want int
+	}{
+		{0, 0, 0},
+		{2, 4, 50},
+		{5, 3, 100},
+	}
+	for _, tt := range tests {
+		e := &RosterEntry{LoggedSessions: tt.logged, ExpectedSessions: tt.expected}
+		if got := e.AdherencePercent(); got != tt.want {
+			t.Errorf("AdherencePercent(%d/%d) = %d, want %d", tt.logged, tt.expected, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"bytes"
 	"database/sql"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/carpenike/replog/internal/database"
@@ -51,7 +53,7 @@ func TestSeedCatalogImport(t *testing.T) {
 	}
 
 	// Verify data is queryable.
-	exercises, err := ListExercises(db, "")
+	exercises, err := ListExercises(db, ExerciseFilter{})
 	if err != nil {
 		t.Fatalf("ListExercises: %v", err)
 	}
@@ -135,7 +137,7 @@ func TestSeedCatalogImport_Idempotent(t *testing.T) {
 // listEntityExercises returns exercises as ExistingEntity for mapping tests.
 func listEntityExercises(t testing.TB, db *sql.DB) []importers.ExistingEntity {
 	t.Helper()
-	exercises, err := ListExercises(db, "")
+	exercises, err := ListExercises(db, ExerciseFilter{})
 	if err != nil {
 		t.Fatalf("list exercises: %v", err)
 	}
@@ -167,13 +169,76 @@ func listEntityPrograms(t testing.TB, db *sql.DB) []importers.ExistingEntity {
 	if err != nil {
 		t.Fatalf("list programs: %v", err)
 	}
+	hashes, err := ProgramTemplateHashes(db)
+	if err != nil {
+		t.Fatalf("list program hashes: %v", err)
+	}
 	result := make([]importers.ExistingEntity, len(programs))
 	for i, p := range programs {
-		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name}
+		result[i] = importers.ExistingEntity{ID: p.ID, Name: p.Name, Hash: hashes[p.ID]}
 	}
 	return result
 }
 
+func TestCatalogImport_ProgramContentHash(t *testing.T) {
+	db := testDB(t)
+
+	five := 5
+	pct := 0.75
+	parsed := &importers.ParsedFile{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: []importers.ParsedExercise{{Name: "Squat"}},
+		Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+			Name: "Block A", NumWeeks: 1, NumDays: 1,
+			PrescribedSets: []importers.ParsedPrescribedSet{
+				{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five, Percentage: &pct},
+			},
+		}}},
+	}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: importers.BuildExerciseMappings(parsed.Exercises, nil),
+		Programs:  importers.BuildProgramMappings(parsed.Programs, nil),
+		Parsed:    parsed,
+	}
+	result, err := ExecuteCatalogImport(db, ms, nil)
+	if err != nil || len(result.CreatedTemplateIDs) != 1 {
+		t.Fatalf("import: result=%+v err=%v", result, err)
+	}
+	templateID := result.CreatedTemplateIDs[0]
+
+	hashes, _ := ProgramTemplateHashes(db)
+	want := importers.ProgramTemplateHash(parsed.Programs[0].Template)
+	if hashes[templateID] != want {
+		t.Fatalf("stored hash = %q, want %q", hashes[templateID], want)
+	}
+
+	// Re-importing the same content maps onto the template by hash.
+	mappings := importers.BuildProgramMappings(parsed.Programs, listEntityPrograms(t, db))
+	if mappings[0].MappedID != templateID || mappings[0].Create {
+		t.Errorf("re-import mapping = %+v, want template %d", mappings[0], templateID)
+	}
+
+	// Editing a prescribed set clears the hash so stale content never matches,
+	// including a change to its rest time.
+	sets, _ := ListPrescribedSets(db, templateID)
+	if _, err := db.Exec(`UPDATE prescribed_sets SET rest_seconds = 180 WHERE id = ?`, sets[0].ID); err != nil {
+		t.Fatalf("set rest: %v", err)
+	}
+	hashes, _ = ProgramTemplateHashes(db)
+	if _, ok := hashes[templateID]; ok {
+		t.Error("hash should be cleared after a prescribed set's rest time changes")
+	}
+	db.Exec(`UPDATE program_templates SET content_hash = ? WHERE id = ?`, want, templateID)
+	if err := DeletePrescribedSet(db, sets[0].ID); err != nil {
+		t.Fatalf("delete set: %v", err)
+	}
+	hashes, _ = ProgramTemplateHashes(db)
+	if _, ok := hashes[templateID]; ok {
+		t.Error("hash should be cleared after a prescribed set changes")
+	}
+}
+
 func TestCatalogImport_AssignsToAthlete(t *testing.T) {
 	db := testDB(t)
 
@@ -257,7 +322,7 @@ func TestCatalogImport_DeactivatesPriorProgram(t *testing.T) {
 	}
 
 	// Create and assign a program manually first.
-	tmpl, err := CreateProgramTemplate(db, nil, "Old Program", "", 4, 3, false, "")
+	tmpl, err := CreateProgramTemplate(db, nil, "Old Program", "", 4, 3, false, "", 0, "")
 	if err != nil {
 		t.Fatalf("create template: %v", err)
 	}
@@ -382,3 +447,49 @@ func TestCatalogImport_NilAthlete_NoAssignment(t *testing.T) {
 		t.Errorf("ProgramsAssigned: got %d, want 0 (no athlete)", result.ProgramsAssigned)
 	}
 }
+
+func TestValidateCatalogReferences(t *testing.T) {
+	db := testDB(t)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	five := 5
+	parsed := &importers.ParsedFile{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: []importers.ParsedExercise{{Name: "Goblet Squat"}},
+		Programs: []importers.ParsedProgram{{Template: importers.ParsedProgramTemplate{
+			Name: "Block B", NumWeeks: 1, NumDays: 1,
+			PrescribedSets: []importers.ParsedPrescribedSet{
+				{Exercise: "Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+				{Exercise: "Goblet Squat", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+				{Exercise: "Sled Push", Week: 1, Day: 1, SetNumber: 1, Reps: &five},
+			},
+			ProgressionRules: []importers.ParsedProgressionRule{{Exercise: "Trap Bar Deadlift", Increment: 5}},
+		}}},
+	}
+	names := importers.CollectProgramExerciseNames(parsed.Programs)
+	refs := make([]importers.ParsedExercise, len(names))
+	for i, n := range names {
+		refs[i] = importers.ParsedExercise{Name: n}
+	}
+	ms := &importers.MappingState{
+		Format:    importers.FormatCatalogJSON,
+		Exercises: importers.BuildExerciseMappings(refs, []importers.ExistingEntity{{ID: squat.ID, Name: "Squat"}}),
+		Programs:  importers.BuildProgramMappings(parsed.Programs, nil),
+		Parsed:    parsed,
+	}
+
+	got := ValidateCatalogReferences(ms)
+	if len(got) != 1 || got[0].Program != "Block B" {
+		t.Fatalf("unresolved = %+v, want one entry for Block B", got)
+	}
+	if strings.Join(got[0].Exercises, ",") != "Sled Push,Trap Bar Deadlift" {
+		t.Errorf("unresolved exercises = %v, want Sled Push and Trap Bar Deadlift", got[0].Exercises)
+	}
+
+	if _, err := ExecuteCatalogImport(db, ms, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ExecuteCatalogImport err = %v, want ErrInvalidInput", err)
+	}
+	if programs, _ := ListProgramTemplates(db); len(programs) != 0 {
+		t.Errorf("programs = %d, want none imported", len(programs))
+	}
+}
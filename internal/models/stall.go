@@ -0,0 +1,212 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// StallFlag marks an exercise whose training max has stopped moving and
+// whose AMRAP performance has fallen short of its targets.
+type StallFlag struct {
+	ExerciseID   int64
+	ExerciseName string
+	TrainingMax  float64 // current (latest) training max
+	Since        string  // effective date of the first entry in the flat window
+	Entries      int     // number of flat training max entries
+	Reason       string
+}
+
+// DetectStalls returns stall flags for an athlete's exercises. A lift is
+// stalled when its last N training max entries (N from the
+// defaults.stall_entries setting) show no increase, and its AMRAP sets
+// logged since the start of that window missed their targets. The target is
+// the threshold of an AMRAP-conditioned progression rule on one of the
+// athlete's active programs; without one, the AMRAP target is to beat the
+// first AMRAP of the window. Exercises with no AMRAP evidence are not
+// flagged. Results are ordered by exercise name.
+func DetectStalls(db *sql.DB, athleteID int64) ([]*StallFlag, error) {
+	n := GetStallEntries(db)
+
+	rows, err := db.Query(
+		`SELECT tm.exercise_id, e.name, tm.weight, tm.effective_date
+		 FROM training_maxes tm
+		 JOIN exercises e ON e.id = tm.exercise_id
+		 WHERE tm.athlete_id = ?
+		 ORDER BY e.name COLLATE NOCASE, tm.exercise_id, tm.effective_date DESC, tm.id DESC`,
+		athleteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list TM history for stall detection (athlete=%d): %w", athleteID, err)
+	}
+
+	type tmEntry struct {
+		weight float64
+		date   string
+	}
+	var order []int64
+	names := make(map[int64]string)
+	recent := make(map[int64][]tmEntry) // newest first, at most n entries
+	for rows.Next() {
+		var exID int64
+		var name string
+		var e tmEntry
+		if err := rows.Scan(&exID, &name, &e.weight, &e.date); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("models: scan TM for stall detection: %w", err)
+		}
+		if _, ok := names[exID]; !ok {
+			order = append(order, exID)
+			names[exID] = name
+		}
+		if len(recent[exID]) < n {
+			recent[exID] = append(recent[exID], tmEntry{e.weight, normalizeDate(e.date)})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate TMs for stall detection: %w", err)
+	}
+
+	thresholds, err := activeAMRAPThresholds(db, athleteID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []*StallFlag
+	for _, exID := range order {
+		entries := recent[exID]
+		if len(entries) < n {
+			continue
+		}
+		// Entries are newest first; any increase over the previous entry
+		// means the lift is still moving.
+		flat := true
+		for i := 0; i < len(entries)-1; i++ {
+			if entries[i].weight > entries[i+1].weight {
+				flat = false
+				break
+			}
+		}
+		if !flat {
+			continue
+		}
+
+		since := entries[len(entries)-1].date
+		amraps, err := amrapRepsSince(db, athleteID, exID, since)
+		if err != nil {
+			return nil, err
+		}
+		if len(amraps) == 0 {
+			continue
+		}
+
+		last := amraps[len(amraps)-1]
+		var missed string
+		if threshold, ok := thresholds[exID]; ok {
+			if last >= threshold {
+				continue
+			}
+			missed = fmt.Sprintf("last AMRAP %d reps < %d target", last, threshold)
+		} else {
+			if len(amraps) < 2 || last > amraps[0] {
+				continue
+			}
+			missed = fmt.Sprintf("AMRAP reps not improving (%d → %d)", amraps[0], last)
+		}
+
+		flags = append(flags, &StallFlag{
+			ExerciseID:   exID,
+			ExerciseName: names[exID],
+			TrainingMax:  entries[0].weight,
+			Since:        since,
+			Entries:      len(entries),
+			Reason:       fmt.Sprintf("No TM increase across %d entries since %s; %s", len(entries), since, missed),
+		})
+	}
+	return flags, nil
+}
+
+// activeAMRAPThresholds maps exercise ID to the AMRAP rep threshold of an
+// AMRAP-conditioned progression rule on the athlete's active programs. When
+// several programs set a threshold for the same exercise, the highest wins.
+func activeAMRAPThresholds(db *sql.DB, athleteID int64) (map[int64]int, error) {
+	rows, err := db.Query(
+		`SELECT pr.exercise_id, MAX(pr.threshold)
+		 FROM progression_rules pr
+		 JOIN athlete_programs ap ON ap.template_id = pr.template_id
+		 WHERE ap.athlete_id = ? AND ap.active = 1
+		   AND pr.condition = ? AND pr.threshold IS NOT NULL
+		 GROUP BY pr.exercise_id`,
+		athleteID, ConditionAMRAPMinReps,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list AMRAP thresholds (athlete=%d): %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	thresholds := make(map[int64]int)
+	for rows.Next() {
+		var exID int64
+		var threshold int
+		if err := rows.Scan(&exID, &threshold); err != nil {
+			return nil, fmt.Errorf("models: scan AMRAP threshold: %w", err)
+		}
+		thresholds[exID] = threshold
+	}
+	return thresholds, rows.Err()
+}
+
+// amrapRepsSince returns the reps of the athlete's AMRAP sets for an
+// exercise logged on or after since, one per workout (the heaviest set),
+// in date order. A set counts as AMRAP when its workout's program
+// prescribes the exercise with open-ended reps.
+func amrapRepsSince(db *sql.DB, athleteID, exerciseID int64, since string) ([]int, error) {
+	rows, err := db.Query(
+		`SELECT ws.workout_id, ws.reps
+		 FROM workout_sets ws
+		 JOIN workouts w ON w.id = ws.workout_id
+		 JOIN athlete_programs ap ON ap.id = w.assignment_id
+		 WHERE w.athlete_id = ? AND ws.exercise_id = ?
+		   AND date(w.date) >= date(?)
+		   AND ws.weight IS NOT NULL
+		   AND EXISTS (SELECT 1 FROM prescribed_sets ps
+		               WHERE ps.template_id = ap.template_id
+		                 AND ps.exercise_id = ws.exercise_id
+		                 AND ps.reps IS NULL)
+		 ORDER BY date(w.date), w.id, ws.weight DESC, ws.set_number DESC`,
+		athleteID, exerciseID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list AMRAP sets (athlete=%d, exercise=%d): %w", athleteID, exerciseID, err)
+	}
+	defer rows.Close()
+
+	var reps []int
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var workoutID int64
+		var r int
+		if err := rows.Scan(&workoutID, &r); err != nil {
+			return nil, fmt.Errorf("models: scan AMRAP set: %w", err)
+		}
+		if seen[workoutID] {
+			continue
+		}
+		seen[workoutID] = true
+		reps = append(reps, r)
+	}
+	return reps, rows.Err()
+}
+
+// RecordStallAlert marks a stall as reported to the athlete's coach. A stall
+// is identified by its exercise and the start of its flat window. Returns
+// false if it had already been recorded.
+func RecordStallAlert(db *sql.DB, athleteID, exerciseID int64, since string) (bool, error) {
+	result, err := db.Exec(`INSERT OR IGNORE INTO stall_alerts (athlete_id, exercise_id, since) VALUES (?, ?, ?)`,
+		athleteID, exerciseID, since)
+	if err != nil {
+		return false, fmt.Errorf("models: record stall alert (athlete=%d, exercise=%d, since=%s): %w", athleteID, exerciseID, since, err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
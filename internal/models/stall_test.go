@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestDetectStalls(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Stall Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	press, _ := CreateExercise(db, "Press", "", "", "", "", 0)
+
+	tmpl, _ := CreateProgramTemplate(db, nil, "Stall Program", "", 1, 1, true, "", 0, "")
+	for _, ex := range []*Exercise{squat, bench, press} {
+		CreatePrescribedSet(db, tmpl.ID, ex.ID, 1, 1, 1, nil, nil, ptrFloat(85), nil, nil, nil, 0, "", "")
+	}
+	SetProgressionRule(db, tmpl.ID, squat.ID, 10, ConditionAMRAPMinReps, 5)
+	ap, _ := AssignProgram(db, a.ID, tmpl.ID, "2026-01-01", "", "", "primary", "")
+
+	// Squat: flat TM for 3 entries, AMRAP below the rule threshold.
+	for _, d := range []string{"2026-01-01", "2026-02-01", "2026-03-01"} {
+		SetTrainingMax(db, a.ID, squat.ID, 300, d, "")
+	}
+	// Bench: flat TM, no rule, AMRAP reps going down.
+	for _, d := range []string{"2026-01-01", "2026-02-01", "2026-03-01"} {
+		SetTrainingMax(db, a.ID, bench.ID, 200, d, "")
+	}
+	// Press: TM still climbing.
+	SetTrainingMax(db, a.ID, press.ID, 100, "2026-01-01", "")
+	SetTrainingMax(db, a.ID, press.ID, 100, "2026-02-01", "")
+	SetTrainingMax(db, a.ID, press.ID, 105, "2026-03-01", "")
+
+	w1, _ := CreateWorkout(db, a.ID, "2026-01-15", "", ap.ID)
+	AddSet(db, w1.ID, squat.ID, 4, 255, 0, "reps", "", "")
+	AddSet(db, w1.ID, bench.ID, 8, 170, 0, "reps", "", "")
+	AddSet(db, w1.ID, press.ID, 3, 85, 0, "reps", "", "")
+	w2, _ := CreateWorkout(db, a.ID, "2026-02-15", "", ap.ID)
+	AddSet(db, w2.ID, squat.ID, 3, 255, 0, "reps", "", "")
+	AddSet(db, w2.ID, bench.ID, 6, 170, 0, "reps", "", "")
+	AddSet(db, w2.ID, press.ID, 2, 85, 0, "reps", "", "")
+
+	flags, err := DetectStalls(db, a.ID)
+	if err != nil {
+		t.Fatalf("detect stalls: %v", err)
+	}
+	if len(flags) != 2 {
+		t.Fatalf("flags = %d, want 2 (bench, squat)", len(flags))
+	}
+	if flags[0].ExerciseName != "Bench" || flags[1].ExerciseName != "Squat" {
+		t.Errorf("flagged %s, %s; want Bench, Squat", flags[0].ExerciseName, flags[1].ExerciseName)
+	}
+	if !strings.Contains(flags[0].Reason, "not improving (8 → 6)") {
+		t.Errorf("bench reason = %q", flags[0].Reason)
+	}
+	if sq := flags[1]; sq.TrainingMax != 300 || sq.Since != "2026-01-01" || !strings.Contains(sq.Reason, "3 reps < 5 target") {
+		t.Errorf("squat flag = %+v", sq)
+	}
+
+	// Hitting the AMRAP target clears the squat flag.
+	w3, _ := CreateWorkout(db, a.ID, "2026-03-15", "", ap.ID)
+	AddSet(db, w3.ID, squat.ID, 6, 255, 0, "reps", "", "")
+	flags, _ = DetectStalls(db, a.ID)
+	for _, f := range flags {
+		if f.ExerciseID == squat.ID {
+			t.Errorf("squat still flagged after hitting target: %s", f.Reason)
+		}
+	}
+
+	// A wider window needs more flat entries.
+	SetSetting(db, "defaults.stall_entries", "4")
+	flags, _ = DetectStalls(db, a.ID)
+	if len(flags) != 0 {
+		t.Errorf("flags with window 4 = %d, want 0", len(flags))
+	}
+}
+
+func TestRecordStallAlert(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Stalled", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+
+	recorded, err := RecordStallAlert(db, athlete.ID, squat.ID, "2026-01-01")
+	if err != nil || !recorded {
+		t.Fatalf("first record = %v, %v; want true", recorded, err)
+	}
+	again, err := RecordStallAlert(db, athlete.ID, squat.ID, "2026-01-01")
+	if err != nil || again {
+		t.Errorf("repeat record = %v, %v; want false", again, err)
+	}
+	// A new flat window is a new stall.
+	later, err := RecordStallAlert(db, athlete.ID, squat.ID, "2026-04-01")
+	if err != nil || !later {
+		t.Errorf("later stall record = %v, %v; want true", later, err)
+	}
+}
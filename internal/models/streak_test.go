@@ -89,8 +89,8 @@ func TestWeeklyStreaks_DefaultWeeks(t *testing.T) {
 func TestWeeklyStreaks_WithData(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Streak Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	bench, _ := CreateExercise(db, "Bench Press", "", "", "", 0)
-	squat, _ := CreateExercise(db, "Back Squat", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench Press", "", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
 
 	// Assign both exercises.
 	AssignExercise(db, a.ID, bench.ID, 0)
@@ -130,9 +130,9 @@ func TestWeeklyStreaks_WithData(t *testing.T) {
 func TestWeeklyStreaks_PartialCompletion(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Partial Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	bench, _ := CreateExercise(db, "Bench", "", "", "", 0)
-	squat, _ := CreateExercise(db, "Squat", "", "", "", 0)
-	deadlift, _ := CreateExercise(db, "Deadlift", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	deadlift, _ := CreateExercise(db, "Deadlift", "", "", "", "", 0)
 
 	AssignExercise(db, a.ID, bench.ID, 0)
 	AssignExercise(db, a.ID, squat.ID, 0)
@@ -162,8 +162,8 @@ func TestWeeklyStreaks_PartialCompletion(t *testing.T) {
 func TestWeeklyStreaks_UnassignedExercisesNotCounted(t *testing.T) {
 	db := testDB(t)
 	a, _ := CreateAthlete(db, "Unassigned Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	bench, _ := CreateExercise(db, "Press", "", "", "", 0)
-	extra, _ := CreateExercise(db, "Extra Move", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Press", "", "", "", "", 0)
+	extra, _ := CreateExercise(db, "Extra Move", "", "", "", "", 0)
 
 	// Only assign bench.
 	AssignExercise(db, a.ID, bench.ID, 0)
@@ -0,0 +1,368 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTOTPCode is returned when a one-time or recovery code does not
+// match, has already been used, or no TOTP enrollment exists.
+var ErrInvalidTOTPCode = errors.New("invalid two-factor code")
+
+// ErrTOTPAlreadyEnabled is returned when starting enrollment for a user who
+// already has two-factor authentication enabled.
+var ErrTOTPAlreadyEnabled = errors.New("two-factor authentication already enabled")
+
+const (
+	totpPeriod         = 30 // seconds per time step
+	totpDigits         = 6
+	totpSkew           = 1 // time steps accepted either side of now
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10 // hex characters, shown as two groups of five
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// UserTOTP is a user's TOTP enrollment.
+type UserTOTP struct {
+	UserID    int64
+	Secret    string // Decrypted base32 secret
+	Enabled   bool
+	LastStep  int64
+	EnabledAt sql.NullTime
+	CreatedAt time.Time
+}
+
+// GenerateTOTPSecret returns a new random 160-bit secret, base32-encoded as
+// authenticator apps expect.
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("models: generate TOTP secret: %w", err)
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPCode returns the 6-digit code for secret at time t (RFC 6238,
+// HMAC-SHA1, 30-second steps).
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("models: decode TOTP secret: %w", err)
+	}
+	return hotp(key, t.Unix()/totpPeriod), nil
+}
+
+// hotp computes the HOTP value (RFC 4226) of key for counter.
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// matchTOTP returns the time step at which code is valid for secret, allowing
+// one step of clock drift either way. Steps at or before lastStep are
+// rejected so an accepted code cannot be replayed.
+func matchTOTP(secret, code string, t time.Time, lastStep int64) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	now := t.Unix() / totpPeriod
+	for step := now - totpSkew; step <= now+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(hotp(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI that authenticator apps use
+// to add an account for secret.
+func TOTPProvisioningURI(secret, issuer, account string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// GetUserTOTP returns a user's TOTP enrollment, confirmed or pending.
+// Returns ErrNotFound if the user has none.
+func GetUserTOTP(db *sql.DB, userID int64) (*UserTOTP, error) {
+	t := &UserTOTP{UserID: userID}
+	var secret string
+	err := db.QueryRow(
+		`SELECT secret, enabled, last_step, enabled_at, created_at
+		 FROM user_totp WHERE user_id = ?`, userID,
+	).Scan(&secret, &t.Enabled, &t.LastStep, &t.EnabledAt, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get TOTP for user %d: %w", userID, err)
+	}
+
+	t.Secret, err = decryptValue(strings.TrimPrefix(secret, "enc:"))
+	if err != nil {
+		return nil, fmt.Errorf("models: decrypt TOTP secret for user %d: %w", userID, err)
+	}
+	return t, nil
+}
+
+// IsTOTPEnabled reports whether the user has confirmed TOTP enrollment.
+func IsTOTPEnabled(db *sql.DB, userID int64) bool {
+	var enabled bool
+	err := db.QueryRow(`SELECT enabled FROM user_totp WHERE user_id = ?`, userID).Scan(&enabled)
+	return err == nil && enabled
+}
+
+// IsTOTPRequired reports whether the user must use two-factor authentication
+// for password logins. Admins can require it for coach and admin accounts.
+func IsTOTPRequired(db *sql.DB, user *User) bool {
+	return (user.IsCoach || user.IsAdmin) && GetSetting(db, "security.require_totp") == "true"
+}
+
+// BeginTOTPEnrollment generates a new secret for the user and stores it as a
+// pending enrollment, replacing any earlier pending one. The secret is
+// encrypted the same way as sensitive app settings, so REPLOG_SECRET_KEY must
+// be available. Returns ErrTOTPAlreadyEnabled if TOTP is already on.
+func BeginTOTPEnrollment(db *sql.DB, userID int64) (string, error) {
+	if IsTOTPEnabled(db, userID) {
+		return "", ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := encryptValue(secret)
+	if err != nil {
+		return "", fmt.Errorf("models: encrypt TOTP secret for user %d: %w", userID, err)
+	}
+
+	_, err = db.Exec(
+		`INSERT INTO user_totp (user_id, secret) VALUES (?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET secret = excluded.secret, last_step = 0
+		 WHERE user_totp.enabled = 0`,
+		userID, "enc:"+encrypted,
+	)
+	if err != nil {
+		return "", fmt.Errorf("models: begin TOTP enrollment for user %d: %w", userID, err)
+	}
+	return secret, nil
+}
+
+// ConfirmTOTPEnrollment enables a pending enrollment once the user proves
+// their authenticator works by entering a current code, and returns a fresh
+// set of recovery codes. The plaintext codes are only available here.
+// Returns ErrInvalidTOTPCode if the code does not match or nothing is pending.
+func ConfirmTOTPEnrollment(db *sql.DB, userID int64, code string) ([]string, error) {
+	t, err := GetUserTOTP(db, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrInvalidTOTPCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	step, ok := matchTOTP(t.Secret, normalizeTOTPCode(code), time.Now(), t.LastStep)
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin TOTP confirm tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE user_totp SET enabled = 1, last_step = ?, enabled_at = CURRENT_TIMESTAMP
+		 WHERE user_id = ?`, step, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: enable TOTP for user %d: %w", userID, err)
+	}
+	codes, err := replaceRecoveryCodes(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit TOTP confirm: %w", err)
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes with a new set,
+// invalidating the old ones. Returns ErrNotFound if TOTP is not enabled.
+func RegenerateRecoveryCodes(db *sql.DB, userID int64) ([]string, error) {
+	if !IsTOTPEnabled(db, userID) {
+		return nil, ErrNotFound
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin recovery codes tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	codes, err := replaceRecoveryCodes(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// replaceRecoveryCodes deletes a user's recovery codes and stores hashes of
+// a new set, returning the plaintext codes.
+func replaceRecoveryCodes(tx *sql.Tx, userID int64) ([]string, error) {
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return nil, fmt.Errorf("models: clear recovery codes for user %d: %w", userID, err)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeLength/2)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("models: generate recovery code: %w", err)
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+
+		if _, err := tx.Exec(
+			`INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES (?, ?)`,
+			userID, hashRecoveryCode(raw),
+		); err != nil {
+			return nil, fmt.Errorf("models: store recovery code for user %d: %w", userID, err)
+		}
+	}
+	return codes, nil
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes the user has.
+func CountRecoveryCodes(db *sql.DB, userID int64) (int, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = ? AND used_at IS NULL`, userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("models: count recovery codes for user %d: %w", userID, err)
+	}
+	return n, nil
+}
+
+// VerifyTOTP checks a second-factor code for a user with TOTP enabled. The
+// code may be a current authenticator code or an unused recovery code, which
+// is consumed. Returns ErrInvalidTOTPCode if neither matches.
+func VerifyTOTP(db *sql.DB, userID int64, code string) error {
+	t, err := GetUserTOTP(db, userID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidTOTPCode
+	}
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return ErrInvalidTOTPCode
+	}
+
+	code = normalizeTOTPCode(code)
+	if step, ok := matchTOTP(t.Secret, code, time.Now(), t.LastStep); ok {
+		// Guard on last_step so two concurrent logins can't both use the code.
+		res, err := db.Exec(
+			`UPDATE user_totp SET last_step = ? WHERE user_id = ? AND last_step < ?`,
+			step, userID, step,
+		)
+		if err != nil {
+			return fmt.Errorf("models: record TOTP step for user %d: %w", userID, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrInvalidTOTPCode
+		}
+		return nil
+	}
+
+	res, err := db.Exec(
+		`UPDATE totp_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		 WHERE id = (SELECT id FROM totp_recovery_codes
+		             WHERE user_id = ? AND code_hash = ? AND used_at IS NULL LIMIT 1)`,
+		userID, hashRecoveryCode(code),
+	)
+	if err != nil {
+		return fmt.Errorf("models: use recovery code for user %d: %w", userID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// CancelTOTPEnrollment discards a pending enrollment. A confirmed enrollment
+// is left alone; cancelling when nothing is pending is not an error.
+func CancelTOTPEnrollment(db *sql.DB, userID int64) error {
+	if _, err := db.Exec(`DELETE FROM user_totp WHERE user_id = ? AND enabled = 0`, userID); err != nil {
+		return fmt.Errorf("models: cancel TOTP enrollment for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// DisableTOTP removes a user's TOTP enrollment and recovery codes. Disabling
+// when nothing is enrolled is not an error.
+func DisableTOTP(db *sql.DB, userID int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("models: begin disable TOTP tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("models: delete recovery codes for user %d: %w", userID, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("models: delete TOTP for user %d: %w", userID, err)
+	}
+	return tx.Commit()
+}
+
+// normalizeTOTPCode strips the spaces and dashes users type or paste into
+// codes and lowercases recovery codes.
+func normalizeTOTPCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// hashRecoveryCode returns the stored hash of a normalized recovery code.
+// Codes carry 40 bits of randomness and are single-use, so a fast hash is
+// sufficient.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// RFC 6238 Appendix B SHA-1 secret "12345678901234567890", truncated to
+	// the 6 digits authenticator apps use.
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode(%d) = %q, want %q", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTOTP_DriftAndReplay(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generate secret: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	prev, _ := TOTPCode(secret, now.Add(-30*time.Second))
+	stale, _ := TOTPCode(secret, now.Add(-90*time.Second))
+
+	step, ok := matchTOTP(secret, prev, now, 0)
+	if !ok || step != now.Unix()/30-1 {
+		t.Errorf("previous-step code: step=%d ok=%v, want %d true", step, ok, now.Unix()/30-1)
+	}
+	if _, ok := matchTOTP(secret, stale, now, 0); ok {
+		t.Error("code three steps old should not match")
+	}
+	if _, ok := matchTOTP(secret, prev, now, step); ok {
+		t.Error("code at an already used step should not match")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "RepLog", "coach")
+	if !strings.HasPrefix(uri, "otpauth://totp/RepLog:coach?") {
+		t.Errorf("uri = %q, want otpauth://totp/RepLog:coach?...", uri)
+	}
+	for _, part := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=RepLog", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("uri = %q, missing %q", uri, part)
+		}
+	}
+}
+
+func TestTOTPEnrollmentAndVerify(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "totpuser", "", "password123", "", true, false, sql.NullInt64{})
+
+	secret, err := BeginTOTPEnrollment(db, u.ID)
+	if err != nil {
+		t.Fatalf("begin enrollment: %v", err)
+	}
+	if IsTOTPEnabled(db, u.ID) {
+		t.Fatal("pending enrollment should not be enabled")
+	}
+
+	var stored string
+	db.QueryRow(`SELECT secret FROM user_totp WHERE user_id = ?`, u.ID).Scan(&stored)
+	if !strings.HasPrefix(stored, "enc:") || strings.Contains(stored, secret) {
+		t.Errorf("stored secret %q should be encrypted", stored)
+	}
+
+	if _, err := ConfirmTOTPEnrollment(db, u.ID, "000000x"); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Fatalf("confirm with bad code: err = %v, want ErrInvalidTOTPCode", err)
+	}
+
+	code, _ := TOTPCode(secret, time.Now())
+	codes, err := ConfirmTOTPEnrollment(db, u.ID, code[:3]+" "+code[3:])
+	if err != nil {
+		t.Fatalf("confirm enrollment: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	if !IsTOTPEnabled(db, u.ID) {
+		t.Fatal("TOTP should be enabled after confirming")
+	}
+	if _, err := BeginTOTPEnrollment(db, u.ID); !errors.Is(err, ErrTOTPAlreadyEnabled) {
+		t.Errorf("begin while enabled: err = %v, want ErrTOTPAlreadyEnabled", err)
+	}
+
+	// The confirming code can't be replayed to log in.
+	if err := VerifyTOTP(db, u.ID, code); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Errorf("replayed code: err = %v, want ErrInvalidTOTPCode", err)
+	}
+	next, _ := TOTPCode(secret, time.Now().Add(30*time.Second))
+	if err := VerifyTOTP(db, u.ID, next); err != nil {
+		t.Errorf("next code: %v", err)
+	}
+
+	// Recovery codes work once, with or without the dash and in any case.
+	if err := VerifyTOTP(db, u.ID, strings.ToUpper(codes[0])); err != nil {
+		t.Errorf("recovery code: %v", err)
+	}
+	if err := VerifyTOTP(db, u.ID, strings.ReplaceAll(codes[0], "-", "")); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Errorf("reused recovery code: err = %v, want ErrInvalidTOTPCode", err)
+	}
+	if n, _ := CountRecoveryCodes(db, u.ID); n != recoveryCodeCount-1 {
+		t.Errorf("recovery codes left = %d, want %d", n, recoveryCodeCount-1)
+	}
+
+	fresh, err := RegenerateRecoveryCodes(db, u.ID)
+	if err != nil {
+		t.Fatalf("regenerate recovery codes: %v", err)
+	}
+	if err := VerifyTOTP(db, u.ID, codes[1]); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Errorf("old recovery code after regenerate: err = %v, want ErrInvalidTOTPCode", err)
+	}
+	if err := VerifyTOTP(db, u.ID, fresh[0]); err != nil {
+		t.Errorf("new recovery code: %v", err)
+	}
+
+	if err := DisableTOTP(db, u.ID); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if IsTOTPEnabled(db, u.ID) {
+		t.Error("TOTP should be disabled")
+	}
+	if n, _ := CountRecoveryCodes(db, u.ID); n != 0 {
+		t.Errorf("recovery codes after disable = %d, want 0", n)
+	}
+}
+
+func TestIsTOTPRequired(t *testing.T) {
+	db := testDB(t)
+
+	coach, _ := CreateUser(db, "reqcoach", "", "password123", "", true, false, sql.NullInt64{})
+	kid, _ := CreateUser(db, "reqkid", "", "password123", "", false, false, sql.NullInt64{})
+
+	if IsTOTPRequired(db, coach) {
+		t.Error("TOTP should not be required by default")
+	}
+	if err := SetSetting(db, "security.require_totp", "true"); err != nil {
+		t.Fatalf("set setting: %v", err)
+	}
+	if !IsTOTPRequired(db, coach) {
+		t.Error("TOTP should be required for coaches")
+	}
+	if IsTOTPRequired(db, kid) {
+		t.Error("TOTP should not be required for non-coaches")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -116,6 +118,126 @@ func ListTrainingMaxHistory(db *sql.DB, athleteID, exerciseID int64) ([]*Trainin
 	return maxes, nil
 }
 
+// TMTrendPoint is one training max entry in a trend series.
+type TMTrendPoint struct {
+	Date   string
+	Weight float64
+}
+
+// TMTrend summarizes an athlete's training max progression for one exercise.
+type TMTrend struct {
+	Points        []TMTrendPoint // chronological
+	PercentChange float64        // latest vs. first entry
+	Sparkline     string         // SVG polyline points for a sparkWidth × sparkHeight viewBox
+}
+
+// Sparkline viewBox dimensions.
+const (
+	sparkWidth  = 100.0
+	sparkHeight = 24.0
+)
+
+// HasTrend reports whether there are enough entries to show a trend.
+func (t *TMTrend) HasTrend() bool {
+	return len(t.Points) >= 2
+}
+
+// ChangeLabel formats the percent change as e.g. "+12%" or "-3%".
+func (t *TMTrend) ChangeLabel() string {
+	pct := math.Round(t.PercentChange)
+	if pct > 0 {
+		return fmt.Sprintf("+%.0f%%", pct)
+	}
+	return fmt.Sprintf("%.0f%%", pct)
+}
+
+// SpanLabel describes the time between the first and latest entries, e.g.
+// "6 months" or "3 weeks".
+func (t *TMTrend) SpanLabel() string {
+	if len(t.Points) < 2 {
+		return ""
+	}
+	first, err1 := time.Parse("2006-01-02", t.Points[0].Date)
+	last, err2 := time.Parse("2006-01-02", t.Points[len(t.Points)-1].Date)
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	days := int(last.Sub(first).Hours() / 24)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case days < 14:
+		return plural(days, "day")
+	case days < 60:
+		return plural(days/7, "week")
+	case days < 730:
+		return plural(int(math.Round(float64(days)/30.44)), "month")
+	default:
+		return plural(days/365, "year")
+	}
+}
+
+// TrainingMaxTrend returns the athlete's training max entries for an
+// exercise in chronological order, with the total percent change since the
+// first entry and a pre-computed sparkline. A single entry yields no change
+// and an empty sparkline.
+func TrainingMaxTrend(db *sql.DB, athleteID, exerciseID int64) (*TMTrend, error) {
+	rows, err := db.Query(`
+		SELECT effective_date, weight FROM training_maxes
+		WHERE athlete_id = ? AND exercise_id = ?
+		ORDER BY effective_date ASC, id ASC`, athleteID, exerciseID)
+	if err != nil {
+		return nil, fmt.Errorf("models: TM trend (athlete=%d, exercise=%d): %w", athleteID, exerciseID, err)
+	}
+	defer rows.Close()
+
+	trend := &TMTrend{}
+	for rows.Next() {
+		var p TMTrendPoint
+		if err := rows.Scan(&p.Date, &p.Weight); err != nil {
+			return nil, fmt.Errorf("models: scan TM trend: %w", err)
+		}
+		p.Date = normalizeDate(p.Date)
+		trend.Points = append(trend.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("models: iterate TM trend: %w", err)
+	}
+
+	if !trend.HasTrend() {
+		return trend, nil
+	}
+
+	first := trend.Points[0].Weight
+	last := trend.Points[len(trend.Points)-1].Weight
+	if first > 0 {
+		trend.PercentChange = (last - first) / first * 100
+	}
+
+	minW, maxW := first, first
+	for _, p := range trend.Points {
+		minW = math.Min(minW, p.Weight)
+		maxW = math.Max(maxW, p.Weight)
+	}
+	span := maxW - minW
+	coords := make([]string, len(trend.Points))
+	for i, p := range trend.Points {
+		x := float64(i) / float64(len(trend.Points)-1) * sparkWidth
+		y := sparkHeight / 2
+		if span > 0 {
+			y = sparkHeight - (p.Weight-minW)/span*sparkHeight
+		}
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	trend.Sparkline = strings.Join(coords, " ")
+
+	return trend, nil
+}
+
 // ListCurrentTrainingMaxes returns the current (latest) training max for each
 // exercise assigned to an athlete.
 func ListCurrentTrainingMaxes(db *sql.DB, athleteID int64) ([]*TrainingMax, error) {
@@ -160,9 +282,17 @@ type ProgramExerciseTM struct {
 }
 
 // ListProgramExerciseTMs returns all distinct exercises in a program template,
-// each paired with the athlete's current TM (if one exists). Results are
-// ordered by exercise name.
+// each paired with the athlete's current TM (if one exists). Current TMs are
+// rounded with the template's rounding settings so forms pre-fill with
+// loadable values. Results are ordered by exercise name.
 func ListProgramExerciseTMs(db *sql.DB, templateID, athleteID int64) ([]*ProgramExerciseTM, error) {
+	var increment float64
+	var mode string
+	err := db.QueryRow(`SELECT rounding_increment, rounding_mode FROM program_templates WHERE id = ?`, templateID).Scan(&increment, &mode)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("models: get rounding for template %d: %w", templateID, err)
+	}
+
 	rows, err := db.Query(`
 		SELECT DISTINCT e.id, e.name,
 		       (SELECT tm.weight FROM training_maxes tm
@@ -186,7 +316,8 @@ func ListProgramExerciseTMs(db *sql.DB, templateID, athleteID int64) ([]*Program
 			return nil, fmt.Errorf("models: scan program exercise TM: %w", err)
 		}
 		if tm.Valid {
-			pet.CurrentTM = &tm.Float64
+			rounded := roundToIncrement(tm.Float64, increment, mode)
+			pet.CurrentTM = &rounded
 		}
 		results = append(results, pet)
 	}
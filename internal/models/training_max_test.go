@@ -11,7 +11,7 @@ func TestTrainingMaxCRUD(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "TM Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "TM Exercise", "", "", "", 0)
+	e, _ := CreateExercise(db, "TM Exercise", "", "", "", "", 0)
 
 	t.Run("set training max", func(t *testing.T) {
 		tm, err := SetTrainingMax(db, a.ID, e.ID, 100.0, "2024-01-01", "")
@@ -93,8 +93,8 @@ func TestListCurrentTrainingMaxes(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "TM List Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e1, _ := CreateExercise(db, "TM List Ex 1", "", "", "", 0)
-	e2, _ := CreateExercise(db, "TM List Ex 2", "", "", "", 0)
+	e1, _ := CreateExercise(db, "TM List Ex 1", "", "", "", "", 0)
+	e2, _ := CreateExercise(db, "TM List Ex 2", "", "", "", "", 0)
 
 	today := time.Now().Format("2006-01-02")
 	SetTrainingMax(db, a.ID, e1.ID, 200.0, today, "")
@@ -108,3 +108,45 @@ func TestListCurrentTrainingMaxes(t *testing.T) {
 		t.Fatalf("count = %d, want 2", len(maxes))
 	}
 }
+
+func TestTrainingMaxTrend(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Trend Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	e, _ := CreateExercise(db, "Trend Exercise", "", "", "", "", 0)
+
+	// No entries.
+	trend, err := TrainingMaxTrend(db, a.ID, e.ID)
+	if err != nil {
+		t.Fatalf("trend: %v", err)
+	}
+	if trend.HasTrend() || len(trend.Points) != 0 {
+		t.Errorf("expected empty trend, got %+v", trend)
+	}
+
+	// A single entry has no trend.
+	SetTrainingMax(db, a.ID, e.ID, 200, "2026-01-01", "")
+	trend, _ = TrainingMaxTrend(db, a.ID, e.ID)
+	if trend.HasTrend() || trend.Sparkline != "" || trend.SpanLabel() != "" {
+		t.Errorf("single entry: HasTrend=%v sparkline=%q span=%q", trend.HasTrend(), trend.Sparkline, trend.SpanLabel())
+	}
+
+	SetTrainingMax(db, a.ID, e.ID, 215, "2026-04-01", "")
+	SetTrainingMax(db, a.ID, e.ID, 224, "2026-07-01", "")
+	trend, _ = TrainingMaxTrend(db, a.ID, e.ID)
+	if !trend.HasTrend() || len(trend.Points) != 3 {
+		t.Fatalf("points = %d, want 3", len(trend.Points))
+	}
+	if trend.Points[0].Date != "2026-01-01" || trend.Points[2].Weight != 224 {
+		t.Errorf("points not chronological: %+v", trend.Points)
+	}
+	if got := trend.ChangeLabel(); got != "+12%" {
+		t.Errorf("change = %q, want +12%%", got)
+	}
+	if got := trend.SpanLabel(); got != "6 months" {
+		t.Errorf("span = %q, want 6 months", got)
+	}
+	if !strings.HasPrefix(trend.Sparkline, "0.0,24.0 ") || !strings.HasSuffix(trend.Sparkline, " 100.0,0.0") {
+		t.Errorf("sparkline = %q", trend.Sparkline)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -22,6 +24,10 @@ var ErrAthleteAlreadyLinked = errors.New("athlete already linked to another user
 // ErrNoPassword is returned when authenticating a user that has no password set.
 var ErrNoPassword = errors.New("account has no password")
 
+// ErrNoPasskeys is returned when removing the password of an account that
+// has no registered passkey to sign in with instead.
+var ErrNoPasskeys = errors.New("account has no passkeys")
+
 // User represents a login account in the system.
 type User struct {
 	ID           int64
@@ -51,6 +57,44 @@ func (u *User) AvatarURL() string {
 	return "/avatars/" + u.AvatarPath.String
 }
 
+// AvatarThumbURL returns the URL path for the small version of the user's
+// avatar, for lists and the sidebar. Returns empty string if no avatar is
+// set.
+func (u *User) AvatarThumbURL() string {
+	if !u.HasAvatar() {
+		return ""
+	}
+	return "/avatars/" + AvatarThumbName(u.AvatarPath.String)
+}
+
+// DefaultAvatarURL returns the URL path of the generated image shown for a
+// user without an uploaded avatar: an identicon, or their Gravatar when that
+// is enabled.
+func (u *User) DefaultAvatarURL() string {
+	return fmt.Sprintf("/avatars/default/%d", u.ID)
+}
+
+// avatarThumbSuffix marks the thumbnail stored beside each avatar file.
+const avatarThumbSuffix = "_thumb"
+
+// AvatarThumbName returns the filename of the thumbnail stored beside the
+// avatar file filename, e.g. "3_ab12.jpg" → "3_ab12_thumb.jpg".
+func AvatarThumbName(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + avatarThumbSuffix + ext
+}
+
+// AvatarNameFromThumb returns the avatar filename a thumbnail name belongs
+// to, and false if name isn't a thumbnail name.
+func AvatarNameFromThumb(name string) (string, bool) {
+	ext := filepath.Ext(name)
+	base, ok := strings.CutSuffix(strings.TrimSuffix(name, ext), avatarThumbSuffix)
+	if !ok {
+		return "", false
+	}
+	return base + ext, true
+}
+
 // HasPassword reports whether the user has a password set.
 // Passwordless users authenticate via magic links or passkeys.
 func (u *User) HasPassword() bool {
@@ -160,6 +204,22 @@ func GetUserByUsername(db *sql.DB, username string) (*User, error) {
 	return u, nil
 }
 
+// GetUserByEmail retrieves a user by email address (case-insensitive).
+func GetUserByEmail(db *sql.DB, email string) (*User, error) {
+	u := &User{}
+	err := db.QueryRow(
+		`SELECT id, username, name, email, COALESCE(password_hash, ''), athlete_id, is_coach, is_admin, avatar_path, created_at, updated_at
+		 FROM users WHERE email = ?`, email,
+	).Scan(&u.ID, &u.Username, &u.Name, &u.Email, &u.PasswordHash, &u.AthleteID, &u.IsCoach, &u.IsAdmin, &u.AvatarPath, &u.CreatedAt, &u.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: get user by email: %w", err)
+	}
+	return u, nil
+}
+
 // Authenticate verifies a username/password combination and returns the user
 // if valid. Returns ErrNotFound if credentials are wrong, or ErrNoPassword
 // if the account has no password set (passwordless-only).
@@ -216,6 +276,45 @@ func ListUsers(db *sql.DB) ([]*UserWithAthlete, error) {
 	return users, nil
 }
 
+// ListAdminIDs returns the IDs of all admin users.
+func ListAdminIDs(db *sql.DB) ([]int64, error) {
+	rows, err := db.Query(`SELECT id FROM users WHERE is_admin = 1 ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list admin IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("models: scan admin ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListAthleteUserIDs returns the IDs of the user accounts linked to an
+// athlete profile.
+func ListAthleteUserIDs(db *sql.DB, athleteID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT id FROM users WHERE athlete_id = ? ORDER BY id`, athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("models: list users for athlete %d: %w", athleteID, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("models: scan athlete user ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // UpdateUser updates a user's profile fields (not password).
 // Returns ErrDuplicateUsername if the new username conflicts.
 func UpdateUser(db *sql.DB, id int64, username, name, email string, athleteID sql.NullInt64, isCoach bool, isAdmin bool) (*User, error) {
@@ -281,6 +380,27 @@ func UpdatePassword(db *sql.DB, id int64, newPassword string) error {
 	return nil
 }
 
+// RemovePassword makes an account passkey-only by clearing its password, so
+// password login is disabled for it. The account must already have at least
+// one registered passkey; otherwise ErrNoPasskeys is returned.
+func RemovePassword(db *sql.DB, id int64) error {
+	result, err := db.Exec(
+		`UPDATE users SET password_hash = NULL
+		 WHERE id = ? AND EXISTS (SELECT 1 FROM webauthn_credentials WHERE user_id = users.id)`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("models: remove password for user %d: %w", id, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := GetUserByID(db, id); err != nil {
+			return err
+		}
+		return ErrNoPasskeys
+	}
+	return nil
+}
+
 // UpdateAvatarPath sets the avatar_path for a user.
 func UpdateAvatarPath(db *sql.DB, id int64, avatarPath sql.NullString) error {
 	result, err := db.Exec(`UPDATE users SET avatar_path = ? WHERE id = ?`, avatarPath, id)
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 )
 
@@ -17,6 +19,41 @@ const (
 // ValidWeightUnits lists acceptable values for weight_unit.
 var ValidWeightUnits = []string{"lbs", "kg"}
 
+// LbsPerKg is the conversion factor between the two weight units.
+const LbsPerKg = 2.20462262
+
+// ConvertWeight converts a weight between "lbs" and "kg". Unknown or equal
+// units return the value unchanged.
+func ConvertWeight(value float64, from, to string) float64 {
+	switch {
+	case from == "kg" && to == "lbs":
+		return value * LbsPerKg
+	case from == "lbs" && to == "kg":
+		return value / LbsPerKg
+	}
+	return value
+}
+
+// FormatWeight converts a canonical lbs weight to unit and formats it for
+// display, dropping the decimal for whole numbers: "225", "102.1".
+func FormatWeight(value float64, unit string) string {
+	v := math.Round(ConvertWeight(value, "lbs", unit)*10) / 10
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// Digest frequencies for bundling external notifications into one email.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// ValidDigestFrequencies lists acceptable values for digest_frequency.
+var ValidDigestFrequencies = []string{DigestOff, DigestDaily, DigestWeekly}
+
 // ValidDateFormats maps display labels to Go format strings.
 var ValidDateFormats = map[string]string{
 	"Jan 2, 2006":   "Jan 2, 2006",
@@ -34,8 +71,12 @@ type UserPreferences struct {
 	WeightUnit string
 	Timezone   string
 	DateFormat string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	// DigestFrequency is one of ValidDigestFrequencies. When not "off",
+	// external notifications are batched into a scheduled digest email.
+	DigestFrequency string
+	LastDigestAt    sql.NullTime
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // WeightLabel returns the display label for the user's weight unit.
@@ -47,16 +88,19 @@ func (p *UserPreferences) WeightLabel() string {
 func GetUserPreferences(db *sql.DB, userID int64) (*UserPreferences, error) {
 	p := &UserPreferences{}
 	err := db.QueryRow(
-		`SELECT id, user_id, weight_unit, timezone, date_format, created_at, updated_at
+		`SELECT id, user_id, weight_unit, timezone, date_format, digest_frequency, last_digest_at,
+		        created_at, updated_at
 		 FROM user_preferences WHERE user_id = ?`, userID,
-	).Scan(&p.ID, &p.UserID, &p.WeightUnit, &p.Timezone, &p.DateFormat, &p.CreatedAt, &p.UpdatedAt)
+	).Scan(&p.ID, &p.UserID, &p.WeightUnit, &p.Timezone, &p.DateFormat, &p.DigestFrequency, &p.LastDigestAt,
+		&p.CreatedAt, &p.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		// Return defaults from app settings (or hardcoded fallback).
 		return &UserPreferences{
-			UserID:     userID,
-			WeightUnit: GetDefaultWeightUnit(db),
-			Timezone:   GetDefaultTimezone(db),
-			DateFormat: GetDefaultDateFormat(db),
+			UserID:          userID,
+			WeightUnit:      GetDefaultWeightUnit(db),
+			Timezone:        GetDefaultTimezone(db),
+			DateFormat:      GetDefaultDateFormat(db),
+			DigestFrequency: DigestOff,
 		}, nil
 	}
 	if err != nil {
@@ -104,6 +148,78 @@ func EnsureUserPreferences(db *sql.DB, userID int64) error {
 	return nil
 }
 
+// SetDigestFrequency sets how often a user's external notifications are
+// bundled into a digest email. Other preferences are left unchanged.
+func SetDigestFrequency(db *sql.DB, userID int64, frequency string) error {
+	if !isValidDigestFrequency(frequency) {
+		return fmt.Errorf("models: invalid digest frequency %q: %w", frequency, ErrInvalidInput)
+	}
+	_, err := db.Exec(
+		`INSERT INTO user_preferences (user_id, digest_frequency) VALUES (?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET digest_frequency = excluded.digest_frequency`,
+		userID, frequency,
+	)
+	if err != nil {
+		return fmt.Errorf("models: set digest frequency for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// ListDigestDueUsers returns the IDs of users whose digest is due: daily
+// digests not yet sent today (UTC) and weekly digests not sent in the last
+// seven days.
+func ListDigestDueUsers(db *sql.DB) ([]int64, error) {
+	rows, err := db.Query(
+		`SELECT user_id FROM user_preferences
+		 WHERE (digest_frequency = 'daily'
+		        AND (last_digest_at IS NULL OR last_digest_at < date('now')))
+		    OR (digest_frequency = 'weekly'
+		        AND (last_digest_at IS NULL OR last_digest_at < date('now', '-6 days')))
+		 ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("models: list digest due users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("models: scan digest due user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MarkDigestSent records that a digest was delivered to the user, so the next
+// digest starts after lastID, the last notification it included. Digests are
+// capped, so the cursor is that notification's created_at rather than the
+// send time; anything left over goes out in the next one. A lastID of 0
+// means the digest was empty and moves the cursor to now.
+func MarkDigestSent(db *sql.DB, userID, lastID int64) error {
+	var err error
+	if lastID == 0 {
+		_, err = db.Exec(
+			`INSERT INTO user_preferences (user_id, last_digest_at) VALUES (?, CURRENT_TIMESTAMP)
+			 ON CONFLICT(user_id) DO UPDATE SET last_digest_at = CURRENT_TIMESTAMP`,
+			userID,
+		)
+	} else {
+		_, err = db.Exec(
+			`INSERT INTO user_preferences (user_id, last_digest_at, last_digest_id)
+			 SELECT ?, created_at, id FROM notifications WHERE id = ?
+			 ON CONFLICT(user_id) DO UPDATE SET last_digest_at = excluded.last_digest_at,
+			                                   last_digest_id = excluded.last_digest_id`,
+			userID, lastID,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("models: mark digest sent for user %d: %w", userID, err)
+	}
+	return nil
+}
+
 func isValidWeightUnit(unit string) bool {
 	for _, v := range ValidWeightUnits {
 		if v == unit {
@@ -113,6 +229,15 @@ func isValidWeightUnit(unit string) bool {
 	return false
 }
 
+func isValidDigestFrequency(frequency string) bool {
+	for _, v := range ValidDigestFrequencies {
+		if v == frequency {
+			return true
+		}
+	}
+	return false
+}
+
 func isValidDateFormat(format string) bool {
 	for _, v := range ValidDateFormats {
 		if v == format {
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -154,3 +155,108 @@ func TestEnsureUserPreferences(t *testing.T) {
 		t.Fatalf("ensure preferences (second call): %v", err)
 	}
 }
+
+func TestSetDigestFrequency(t *testing.T) {
+	db := testDB(t)
+
+	u, err := CreateUser(db, "digestuser", "", "password123", "", false, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if _, err := UpsertUserPreferences(db, u.ID, "kg", "UTC", "2006-01-02"); err != nil {
+		t.Fatalf("upsert preferences: %v", err)
+	}
+
+	if err := SetDigestFrequency(db, u.ID, DigestWeekly); err != nil {
+		t.Fatalf("set digest frequency: %v", err)
+	}
+	prefs, _ := GetUserPreferences(db, u.ID)
+	if prefs.DigestFrequency != DigestWeekly {
+		t.Errorf("digest_frequency = %q, want %q", prefs.DigestFrequency, DigestWeekly)
+	}
+	if prefs.WeightUnit != "kg" {
+		t.Errorf("weight_unit = %q, want other preferences unchanged", prefs.WeightUnit)
+	}
+
+	if err := SetDigestFrequency(db, u.ID, "hourly"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("invalid frequency: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestListDigestDueUsers(t *testing.T) {
+	db := testDB(t)
+
+	daily, _ := CreateUser(db, "daily", "", "password123", "", false, false, sql.NullInt64{})
+	weekly, _ := CreateUser(db, "weekly", "", "password123", "", false, false, sql.NullInt64{})
+	off, _ := CreateUser(db, "off", "", "password123", "", false, false, sql.NullInt64{})
+	SetDigestFrequency(db, daily.ID, DigestDaily)
+	SetDigestFrequency(db, weekly.ID, DigestWeekly)
+	EnsureUserPreferences(db, off.ID)
+
+	due, err := ListDigestDueUsers(db)
+	if err != nil {
+		t.Fatalf("list digest due users: %v", err)
+	}
+	if len(due) != 2 || due[0] != daily.ID || due[1] != weekly.ID {
+		t.Fatalf("due = %v, want [%d %d]", due, daily.ID, weekly.ID)
+	}
+
+	// Two days after the last digest, only the daily one is due again.
+	MarkDigestSent(db, daily.ID, 0)
+	MarkDigestSent(db, weekly.ID, 0)
+	db.Exec(`UPDATE user_preferences SET last_digest_at = datetime('now', '-2 days')`)
+	due, _ = ListDigestDueUsers(db)
+	if len(due) != 1 || due[0] != daily.ID {
+		t.Errorf("due = %v, want [%d]", due, daily.ID)
+	}
+}
+
+func TestPendingDigest(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "pending", "", "password123", "", true, false, sql.NullInt64{})
+	SetNotificationPreference(db, u.ID, NotifyWorkoutLogged, true, true)
+
+	CreateNotification(db, u.ID, NotifyWorkoutLogged, "Old", "", "", sql.NullInt64{})
+	read, _ := CreateNotification(db, u.ID, NotifyWorkoutLogged, "Read", "", "", sql.NullInt64{})
+	MarkAsRead(db, read.ID, u.ID)
+	CreateNotification(db, u.ID, NotifyTMUpdated, "In-app only", "", "", sql.NullInt64{})
+
+	pending, err := PendingDigest(db, u.ID)
+	if err != nil {
+		t.Fatalf("pending digest: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Title != "Old" {
+		t.Fatalf("pending = %v, want only the unread external notification", pending)
+	}
+
+	// Notifications from before the last digest are left out.
+	MarkDigestSent(db, u.ID, 0)
+	db.Exec(`UPDATE notifications SET created_at = datetime('now', '-1 hour')`)
+	CreateNotification(db, u.ID, NotifyWorkoutLogged, "New", "", "", sql.NullInt64{})
+	db.Exec(`UPDATE notifications SET created_at = datetime('now', '+1 minute') WHERE title = 'New'`)
+
+	pending, _ = PendingDigest(db, u.ID)
+	if len(pending) != 1 || pending[0].Title != "New" {
+		t.Errorf("pending after digest = %v, want only the new notification", pending)
+	}
+}
+
+func TestFormatWeight(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  string
+	}{
+		{225, "lbs", "225"},
+		{132.5, "lbs", "132.5"},
+		{220.46226, "kg", "100"},
+		{225, "kg", "102.1"},
+		{185, "", "185"},
+	}
+	for _, tt := range tests {
+		if got := FormatWeight(tt.value, tt.unit); got != tt.want {
+			t.Errorf("FormatWeight(%v, %q) = %q, want %q", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserSession is an active login session belonging to a user.
+type UserSession struct {
+	ID         int64
+	UserID     int64
+	IPAddress  sql.NullString
+	UserAgent  sql.NullString
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	Current    bool // True for the session making the request
+}
+
+// Device returns a short browser and platform description parsed from the
+// user agent, e.g. "Safari on iPhone".
+func (s *UserSession) Device() string {
+	ua := s.UserAgent.String
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	switch {
+	case strings.Contains(ua, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "Firefox/") || strings.Contains(ua, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "Chrome/") || strings.Contains(ua, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		browser = "Safari"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(ua, "iPhone"):
+		platform = "iPhone"
+	case strings.Contains(ua, "iPad"):
+		platform = "iPad"
+	case strings.Contains(ua, "Android"):
+		platform = "Android"
+	case strings.Contains(ua, "Macintosh"):
+		platform = "Mac"
+	case strings.Contains(ua, "Windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "Linux"):
+		platform = "Linux"
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+	return "Unknown device"
+}
+
+// CreateUserSession records a newly authenticated session, identified by
+// its token, in userID's session list. A token already recorded is left
+// unchanged, so it can't be claimed by a different user.
+func CreateUserSession(db *sql.DB, token string, userID int64, ip, userAgent string) error {
+	_, err := db.Exec(
+		`INSERT INTO user_sessions (token, user_id, ip_address, user_agent) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(token) DO NOTHING`,
+		token, userID, nullString(ip), nullString(userAgent),
+	)
+	if err != nil {
+		return fmt.Errorf("models: create session for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// TouchUserSession records that the session identified by token is in use by
+// userID from the given client. Returns ErrNotFound if the session isn't in
+// the user's session list, either because it was revoked or because it was
+// never recorded at login. Repeat calls within a minute only check the
+// session, so this can run on every authenticated request.
+func TouchUserSession(db *sql.DB, token string, userID int64, ip, userAgent string) error {
+	var stale bool
+	err := db.QueryRow(
+		`SELECT last_seen_at < datetime('now', '-1 minute') FROM user_sessions WHERE token = ? AND user_id = ?`,
+		token, userID,
+	).Scan(&stale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("models: get session for user %d: %w", userID, err)
+	}
+	if !stale {
+		return nil
+	}
+
+	_, err = db.Exec(
+		`UPDATE user_sessions SET last_seen_at = CURRENT_TIMESTAMP, ip_address = ?, user_agent = ?
+		 WHERE token = ? AND user_id = ?`,
+		nullString(ip), nullString(userAgent), token, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("models: touch session for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// ListUserSessions returns a user's unexpired sessions, most recently used
+// first. The session whose token is currentToken is marked Current.
+func ListUserSessions(db *sql.DB, userID int64, currentToken string) ([]*UserSession, error) {
+	rows, err := db.Query(
+		`SELECT us.id, us.user_id, us.ip_address, us.user_agent, us.created_at, us.last_seen_at,
+		        us.token = ?
+		 FROM user_sessions us
+		 JOIN sessions s ON s.token = us.token
+		 WHERE us.user_id = ? AND julianday('now') < s.expiry
+		 ORDER BY us.last_seen_at DESC, us.id DESC`,
+		currentToken, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("models: list sessions for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var sessions []*UserSession
+	for rows.Next() {
+		s := &UserSession{}
+		if err := rows.Scan(&s.ID, &s.UserID, &s.IPAddress, &s.UserAgent, &s.CreatedAt, &s.LastSeenAt, &s.Current); err != nil {
+			return nil, fmt.Errorf("models: scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeUserSession logs out one of a user's sessions by deleting it from
+// the session store. Returns ErrNotFound if the session doesn't belong to
+// the user.
+func RevokeUserSession(db *sql.DB, userID, sessionID int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("models: begin revoke session tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`DELETE FROM sessions WHERE token = (SELECT token FROM user_sessions WHERE id = ? AND user_id = ?)`,
+		sessionID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("models: revoke session %d: %w", sessionID, err)
+	}
+	res, err := tx.Exec(`DELETE FROM user_sessions WHERE id = ? AND user_id = ?`, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("models: delete session %d: %w", sessionID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
+// RevokeUserSessions logs out all of a user's sessions except the one whose
+// token is keepToken (pass "" to revoke every session) and returns how many
+// were revoked.
+func RevokeUserSessions(db *sql.DB, userID int64, keepToken string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("models: begin revoke sessions tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`DELETE FROM sessions WHERE token IN (SELECT token FROM user_sessions WHERE user_id = ? AND token != ?)`,
+		userID, keepToken,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: revoke sessions for user %d: %w", userID, err)
+	}
+	res, err := tx.Exec(`DELETE FROM user_sessions WHERE user_id = ? AND token != ?`, userID, keepToken)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete sessions for user %d: %w", userID, err)
+	}
+	n, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("models: commit revoke sessions: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteStaleUserSessions removes session records whose session has expired
+// or been logged out. Returns the number of rows deleted.
+func DeleteStaleUserSessions(db *sql.DB) (int64, error) {
+	res, err := db.Exec(
+		`DELETE FROM user_sessions WHERE NOT EXISTS (
+		     SELECT 1 FROM sessions s
+		     WHERE s.token = user_sessions.token AND julianday('now') < s.expiry
+		 )`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete stale user sessions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// nullString returns s as a NullString that is NULL when s is empty.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
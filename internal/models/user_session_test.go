@@ -0,0 +1,138 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// seedSession inserts an scs session row that expires in a day.
+func seedSession(t *testing.T, db *sql.DB, token string) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT INTO sessions (token, data, expiry) VALUES (?, x'00', julianday('now', '+1 day'))`, token); err != nil {
+		t.Fatalf("seed session %q: %v", token, err)
+	}
+}
+
+func TestUserSessions(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "sessuser", "", "password123", "", false, false, sql.NullInt64{})
+	other, _ := CreateUser(db, "otheruser", "", "password123", "", false, false, sql.NullInt64{})
+
+	for _, token := range []string{"tok-a", "tok-b", "tok-c", "tok-other"} {
+		seedSession(t, db, token)
+	}
+	CreateUserSession(db, "tok-a", u.ID, "10.0.0.1", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Version/17.0 Mobile/15E148 Safari/604.1")
+	CreateUserSession(db, "tok-b", u.ID, "10.0.0.2", "")
+	CreateUserSession(db, "tok-c", u.ID, "10.0.0.3", "")
+	CreateUserSession(db, "tok-other", other.ID, "10.0.0.9", "")
+	// A record whose session is gone is not listed.
+	CreateUserSession(db, "tok-expired", u.ID, "10.0.0.4", "")
+
+	// A token can't be claimed by a different user.
+	if err := CreateUserSession(db, "tok-other", u.ID, "10.0.0.5", ""); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if err := TouchUserSession(db, "tok-other", u.ID, "10.0.0.5", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("touch another user's session: err = %v, want ErrNotFound", err)
+	}
+	if err := TouchUserSession(db, "tok-a", u.ID, "10.0.0.1", ""); err != nil {
+		t.Errorf("touch recorded session: %v", err)
+	}
+	if err := TouchUserSession(db, "tok-unrecorded", u.ID, "", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("touch unrecorded session: err = %v, want ErrNotFound", err)
+	}
+
+	sessions, err := ListUserSessions(db, u.ID, "tok-a")
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(sessions))
+	}
+	var current *UserSession
+	for _, s := range sessions {
+		if s.Current {
+			current = s
+		}
+	}
+	if current == nil || current.IPAddress.String != "10.0.0.1" {
+		t.Fatalf("current session = %+v, want tok-a", current)
+	}
+	if got := current.Device(); got != "Safari on iPhone" {
+		t.Errorf("Device() = %q, want %q", got, "Safari on iPhone")
+	}
+
+	var revokeID int64
+	for _, s := range sessions {
+		if s.IPAddress.String == "10.0.0.2" {
+			revokeID = s.ID
+		}
+	}
+	otherSessions, _ := ListUserSessions(db, other.ID, "")
+	if err := RevokeUserSession(db, u.ID, otherSessions[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoke another user's session: err = %v, want ErrNotFound", err)
+	}
+	if err := RevokeUserSession(db, u.ID, revokeID); err != nil {
+		t.Fatalf("revoke session: %v", err)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE token = 'tok-b'`).Scan(&n)
+	if n != 0 {
+		t.Error("revoked session should be removed from the session store")
+	}
+
+	revoked, err := RevokeUserSessions(db, u.ID, "tok-a")
+	if err != nil {
+		t.Fatalf("revoke other sessions: %v", err)
+	}
+	if revoked != 2 { // tok-c and the stale tok-expired record
+		t.Errorf("revoked = %d, want 2", revoked)
+	}
+	sessions, _ = ListUserSessions(db, u.ID, "tok-a")
+	if len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("after revoking others: %d sessions, want only the current one", len(sessions))
+	}
+	if otherSessions, _ := ListUserSessions(db, other.ID, ""); len(otherSessions) != 1 {
+		t.Errorf("other user's sessions = %d, want 1", len(otherSessions))
+	}
+}
+
+func TestDeleteStaleUserSessions(t *testing.T) {
+	db := testDB(t)
+
+	u, _ := CreateUser(db, "staleuser", "", "password123", "", false, false, sql.NullInt64{})
+	seedSession(t, db, "live")
+	db.Exec(`INSERT INTO sessions (token, data, expiry) VALUES ('expired', x'00', julianday('now', '-1 day'))`)
+	CreateUserSession(db, "live", u.ID, "", "")
+	CreateUserSession(db, "expired", u.ID, "", "")
+	CreateUserSession(db, "gone", u.ID, "", "")
+
+	deleted, err := DeleteStaleUserSessions(db)
+	if err != nil {
+		t.Fatalf("delete stale sessions: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+}
+
+func TestUserSessionDevice(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{"", "Unknown device"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on Mac"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"curl/8.4.0", "Unknown device"},
+	}
+	for _, tt := range tests {
+		s := &UserSession{UserAgent: sql.NullString{String: tt.ua, Valid: tt.ua != ""}}
+		if got := s.Device(); got != tt.want {
+			t.Errorf("Device(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+}
@@ -300,3 +300,16 @@ func TestUpdateAvatarPath(t *testing.T) {
 		}
 	})
 }
+
+func TestAvatarThumbName(t *testing.T) {
+	thumb := AvatarThumbName("3_ab12.jpg")
+	if thumb != "3_ab12_thumb.jpg" {
+		t.Errorf("AvatarThumbName = %q, want %q", thumb, "3_ab12_thumb.jpg")
+	}
+	if name, ok := AvatarNameFromThumb(thumb); !ok || name != "3_ab12.jpg" {
+		t.Errorf("AvatarNameFromThumb(%q) = %q, %v", thumb, name, ok)
+	}
+	if _, ok := AvatarNameFromThumb("3_ab12.jpg"); ok {
+		t.Error("AvatarNameFromThumb accepted a full-size name")
+	}
+}
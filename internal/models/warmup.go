@@ -0,0 +1,48 @@
+package models
+
+import "math"
+
+// WarmupSet is one suggested warm-up set leading into a working weight.
+type WarmupSet struct {
+	Weight  float64
+	Reps    int
+	Percent int // percentage of the working weight; 0 for the empty bar
+}
+
+// warmupRamp is the percentage ramp used for warm-up suggestions.
+var warmupRamp = []struct {
+	percent int
+	reps    int
+}{
+	{40, 5},
+	{60, 3},
+	{80, 2},
+}
+
+// SuggestWarmups returns a warm-up ramp for the given working weight: the
+// empty bar for 10 reps, then 40/60/80% of the working weight. Each weight
+// is rounded to the nearest 5 and clamped to at least the bar. Sets that
+// would not be lighter than the working weight, or that repeat the previous
+// weight, are dropped. Returns nil when the working weight is at or below
+// the bar.
+func SuggestWarmups(workingWeight, barWeight float64) []WarmupSet {
+	if barWeight <= 0 {
+		barWeight = DefaultBarWeight
+	}
+	if workingWeight <= barWeight {
+		return nil
+	}
+
+	sets := []WarmupSet{{Weight: barWeight, Reps: 10}}
+	for _, step := range warmupRamp {
+		weight := math.Round(workingWeight*float64(step.percent)/100/5) * 5
+		if weight < barWeight {
+			weight = barWeight
+		}
+		if weight >= workingWeight || weight <= sets[len(sets)-1].Weight {
+			continue
+		}
+		sets = append(sets, WarmupSet{Weight: weight, Reps: step.reps, Percent: step.percent})
+	}
+	return sets
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggestWarmups(t *testing.T) {
+	tests := []struct {
+		name    string
+		working float64
+		bar     float64
+		want    []float64
+	}{
+		{"full ramp", 225, 45, []float64{45, 90, 135, 180}},
+		{"rounds to nearest 5", 200, 45, []float64{45, 80, 120, 160}},
+		{"drops sets at or below the bar", 95, 45, []float64{45, 55, 75}},
+		{"light working weight", 50, 45, []float64{45}},
+		{"working at bar", 45, 45, nil},
+		{"default bar", 135, 0, []float64{45, 55, 80, 110}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			for _, s := range SuggestWarmups(tt.working, tt.bar) {
+				if s.Weight >= tt.working {
+					t.Errorf("warmup %v not lighter than working weight %v", s.Weight, tt.working)
+				}
+				got = append(got, s.Weight)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SuggestWarmups(%v, %v) = %v, want %v", tt.working, tt.bar, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,6 +30,7 @@ type WebAuthnCredential struct {
 	BackupState      bool
 	Label            sql.NullString
 	CreatedAt        time.Time
+	LastUsedAt       sql.NullTime
 }
 
 // ToLibrary converts a stored credential to the go-webauthn library Credential type.
@@ -52,6 +55,54 @@ func (c *WebAuthnCredential) ToLibrary() webauthn.Credential {
 	}
 }
 
+// knownAuthenticators maps the AAGUIDs of common passkey providers to a
+// display name. AAGUIDs identify the authenticator model, not the device.
+var knownAuthenticators = map[string]string{
+	"fbfc3007-154e-4ecc-8c0b-6e020557d7bd": "iCloud Keychain",
+	"dd4ec289-e01d-41c9-bb89-70fa845d4bf2": "iCloud Keychain",
+	"ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4": "Google Password Manager",
+	"adce0002-35bc-c60a-648b-0b25f1f05503": "Chrome on Mac",
+	"08987058-cadc-4b81-b6e1-30de50dcbe96": "Windows Hello",
+	"9ddd1817-af5a-4672-a2b9-3e3dd95000a9": "Windows Hello",
+	"6028b017-b1d4-4c02-b4b3-afcdafc96bb2": "Windows Hello",
+	"bada5566-a7aa-401f-bd96-45619a55120d": "1Password",
+	"d548826e-79b4-db40-a3d8-11116f7e8349": "Bitwarden",
+	"53414d53-554e-4700-0000-000000000000": "Samsung Pass",
+}
+
+// DeviceHint describes what kind of authenticator holds the passkey, e.g.
+// "iCloud Keychain" or "Security key", from its AAGUID and transports.
+func (c *WebAuthnCredential) DeviceHint() string {
+	if len(c.AAGUID) == 16 {
+		a := hex.EncodeToString(c.AAGUID)
+		uuid := a[0:8] + "-" + a[8:12] + "-" + a[12:16] + "-" + a[16:20] + "-" + a[20:32]
+		if name, ok := knownAuthenticators[uuid]; ok {
+			return name
+		}
+	}
+
+	has := func(t protocol.AuthenticatorTransport) bool {
+		for _, tr := range c.Transport {
+			if tr == t {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has(protocol.Internal) || c.Attachment == protocol.Platform:
+		if c.BackupEligible {
+			return "Synced passkey"
+		}
+		return "Built-in authenticator"
+	case has(protocol.USB) || has(protocol.NFC) || has(protocol.BLE):
+		return "Security key"
+	case has(protocol.Hybrid):
+		return "Phone or tablet"
+	}
+	return "Unknown authenticator"
+}
+
 // marshalTransport serializes transport list to JSON for storage.
 func marshalTransport(transports []protocol.AuthenticatorTransport) (sql.NullString, error) {
 	if len(transports) == 0 {
@@ -100,7 +151,7 @@ func CreateWebAuthnCredential(db *sql.DB, userID int64, cred *webauthn.Credentia
 		RETURNING id, user_id, credential_id, public_key, attestation_type, transport,
 		          sign_count, clone_warning, attachment, aaguid,
 		          flags_user_present, flags_user_verified, flags_backup_eligible, flags_backup_state,
-		          label, created_at`,
+		          label, created_at, last_used_at`,
 		userID, cred.ID, cred.PublicKey, cred.AttestationType, transport,
 		cred.Authenticator.SignCount,
 		boolToInt(cred.Authenticator.CloneWarning),
@@ -115,7 +166,7 @@ func CreateWebAuthnCredential(db *sql.DB, userID int64, cred *webauthn.Credentia
 		&wc.ID, &wc.UserID, &wc.CredentialID, &wc.PublicKey, &wc.AttestationType,
 		&transportStr, &wc.SignCount, &wc.CloneWarning, &wc.Attachment, &wc.AAGUID,
 		&wc.UserPresent, &wc.UserVerified, &wc.BackupEligible, &wc.BackupState,
-		&wc.Label, &wc.CreatedAt,
+		&wc.Label, &wc.CreatedAt, &wc.LastUsedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("models: create webauthn credential: %w", err)
@@ -135,7 +186,7 @@ func ListWebAuthnCredentialsByUser(db *sql.DB, userID int64) ([]*WebAuthnCredent
 		SELECT id, user_id, credential_id, public_key, attestation_type, transport,
 		       sign_count, clone_warning, attachment, aaguid,
 		       flags_user_present, flags_user_verified, flags_backup_eligible, flags_backup_state,
-		       label, created_at
+		       label, created_at, last_used_at
 		FROM webauthn_credentials
 		WHERE user_id = ?
 		ORDER BY created_at DESC`, userID)
@@ -152,7 +203,7 @@ func ListWebAuthnCredentialsByUser(db *sql.DB, userID int64) ([]*WebAuthnCredent
 			&wc.ID, &wc.UserID, &wc.CredentialID, &wc.PublicKey, &wc.AttestationType,
 			&transportStr, &wc.SignCount, &wc.CloneWarning, &wc.Attachment, &wc.AAGUID,
 			&wc.UserPresent, &wc.UserVerified, &wc.BackupEligible, &wc.BackupState,
-			&wc.Label, &wc.CreatedAt,
+			&wc.Label, &wc.CreatedAt, &wc.LastUsedAt,
 		); err != nil {
 			return nil, fmt.Errorf("models: scan webauthn credential: %w", err)
 		}
@@ -199,11 +250,13 @@ func GetUserByCredentialID(db *sql.DB, credentialID []byte) (*User, error) {
 	return &user, nil
 }
 
-// UpdateWebAuthnCredentialSignCount updates the sign count and clone warning for a credential.
+// UpdateWebAuthnCredentialSignCount updates the sign count and clone warning
+// for a credential after a successful login, and records the login time in
+// last_used_at.
 func UpdateWebAuthnCredentialSignCount(db *sql.DB, credentialID []byte, signCount uint32, cloneWarning bool) error {
 	_, err := db.Exec(`
 		UPDATE webauthn_credentials
-		SET sign_count = ?, clone_warning = ?
+		SET sign_count = ?, clone_warning = ?, last_used_at = CURRENT_TIMESTAMP
 		WHERE credential_id = ?`,
 		signCount, boolToInt(cloneWarning), credentialID)
 	if err != nil {
@@ -212,15 +265,32 @@ func UpdateWebAuthnCredentialSignCount(db *sql.DB, credentialID []byte, signCoun
 	return nil
 }
 
+// ErrLastPasskey is returned when removing the only passkey of an account
+// that has no password, which would leave it unable to sign in.
+var ErrLastPasskey = errors.New("last passkey of a passwordless account")
+
 // DeleteWebAuthnCredential removes a WebAuthn credential by its row ID,
-// scoped to the specified user to prevent cross-user deletion.
+// scoped to the specified user to prevent cross-user deletion. A
+// passwordless account's last credential can't be removed (ErrLastPasskey).
 func DeleteWebAuthnCredential(db *sql.DB, id, userID int64) error {
-	result, err := db.Exec(`DELETE FROM webauthn_credentials WHERE id = ? AND user_id = ?`, id, userID)
+	result, err := db.Exec(
+		`DELETE FROM webauthn_credentials WHERE id = ? AND user_id = ?
+		   AND (EXISTS (SELECT 1 FROM users WHERE id = ? AND password_hash IS NOT NULL AND password_hash != '')
+		        OR (SELECT COUNT(*) FROM webauthn_credentials WHERE user_id = ?) > 1)`,
+		id, userID, userID, userID,
+	)
 	if err != nil {
 		return fmt.Errorf("models: delete webauthn credential %d: %w", id, err)
 	}
 	n, _ := result.RowsAffected()
 	if n == 0 {
+		var exists bool
+		if err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM webauthn_credentials WHERE id = ? AND user_id = ?)`, id, userID).Scan(&exists); err != nil {
+			return fmt.Errorf("models: check webauthn credential %d: %w", id, err)
+		}
+		if exists {
+			return ErrLastPasskey
+		}
 		return ErrNotFound
 	}
 	return nil
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/go-webauthn/webauthn/protocol"
@@ -246,6 +247,58 @@ func TestDeleteWebAuthnCredential(t *testing.T) {
 	})
 }
 
+func TestDeleteWebAuthnCredential_LastPasskey(t *testing.T) {
+	db := testDB(t)
+	user, _ := CreateUser(db, "passkeyonly", "", "", "", false, false, sql.NullInt64{})
+
+	var ids []int64
+	for _, name := range []string{"phone", "laptop"} {
+		wc, err := CreateWebAuthnCredential(db, user.ID, &webauthn.Credential{
+			ID:              []byte("last-" + name),
+			PublicKey:       []byte("key-" + name),
+			AttestationType: "none",
+		}, name)
+		if err != nil {
+			t.Fatalf("create credential: %v", err)
+		}
+		ids = append(ids, wc.ID)
+	}
+
+	if err := DeleteWebAuthnCredential(db, ids[0], user.ID); err != nil {
+		t.Fatalf("delete one of two: %v", err)
+	}
+	if err := DeleteWebAuthnCredential(db, ids[1], user.ID); !errors.Is(err, ErrLastPasskey) {
+		t.Fatalf("delete last: err = %v, want ErrLastPasskey", err)
+	}
+
+	// With a password the account can't be locked out, so the last one can go.
+	if err := UpdatePassword(db, user.ID, "password123"); err != nil {
+		t.Fatalf("set password: %v", err)
+	}
+	if err := DeleteWebAuthnCredential(db, ids[1], user.ID); err != nil {
+		t.Errorf("delete last with password: %v", err)
+	}
+}
+
+func TestRemovePassword(t *testing.T) {
+	db := testDB(t)
+	user, _ := CreateUser(db, "goingpasskey", "", "password123", "", false, false, sql.NullInt64{})
+
+	if err := RemovePassword(db, user.ID); !errors.Is(err, ErrNoPasskeys) {
+		t.Fatalf("remove without passkey: err = %v, want ErrNoPasskeys", err)
+	}
+	CreateWebAuthnCredential(db, user.ID, &webauthn.Credential{ID: []byte("rp-1"), PublicKey: []byte("k"), AttestationType: "none"}, "phone")
+	if err := RemovePassword(db, user.ID); err != nil {
+		t.Fatalf("remove password: %v", err)
+	}
+	if _, err := Authenticate(db, "goingpasskey", "password123"); !errors.Is(err, ErrNoPassword) {
+		t.Errorf("password login: err = %v, want ErrNoPassword", err)
+	}
+	if err := RemovePassword(db, 99999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown user: err = %v, want ErrNotFound", err)
+	}
+}
+
 func TestUpdateWebAuthnCredentialSignCount(t *testing.T) {
 	db := testDB(t)
 	user, _ := CreateUser(db, "signcount", "", "pass", "", false, false, sql.NullInt64{})
@@ -261,9 +314,16 @@ func TestUpdateWebAuthnCredentialSignCount(t *testing.T) {
 		t.Fatalf("create credential: %v", err)
 	}
 
+	if creds, _ := ListWebAuthnCredentialsByUser(db, user.ID); creds[0].LastUsedAt.Valid {
+		t.Error("new credential should not have a last used time")
+	}
+
 	if err := UpdateWebAuthnCredentialSignCount(db, credID, 10, false); err != nil {
 		t.Fatalf("update sign count: %v", err)
 	}
+	if creds, _ := ListWebAuthnCredentialsByUser(db, user.ID); !creds[0].LastUsedAt.Valid {
+		t.Error("login should record the last used time")
+	}
 
 	// Verify the update.
 	libCreds, err := GetWebAuthnCredentialsByUser(db, user.ID)
@@ -278,6 +338,27 @@ func TestUpdateWebAuthnCredentialSignCount(t *testing.T) {
 	}
 }
 
+func TestWebAuthnCredential_DeviceHint(t *testing.T) {
+	iCloud := []byte{0xfb, 0xfc, 0x30, 0x07, 0x15, 0x4e, 0x4e, 0xcc, 0x8c, 0x0b, 0x6e, 0x02, 0x05, 0x57, 0xd7, 0xbd}
+	tests := []struct {
+		name string
+		cred WebAuthnCredential
+		want string
+	}{
+		{"known AAGUID", WebAuthnCredential{AAGUID: iCloud, Transport: []protocol.AuthenticatorTransport{protocol.Internal}}, "iCloud Keychain"},
+		{"synced platform", WebAuthnCredential{Attachment: protocol.Platform, BackupEligible: true}, "Synced passkey"},
+		{"device-bound platform", WebAuthnCredential{Transport: []protocol.AuthenticatorTransport{protocol.Internal}}, "Built-in authenticator"},
+		{"security key", WebAuthnCredential{AAGUID: make([]byte, 16), Transport: []protocol.AuthenticatorTransport{protocol.USB, protocol.NFC}}, "Security key"},
+		{"hybrid", WebAuthnCredential{Transport: []protocol.AuthenticatorTransport{protocol.Hybrid}}, "Phone or tablet"},
+		{"nothing known", WebAuthnCredential{}, "Unknown authenticator"},
+	}
+	for _, tt := range tests {
+		if got := tt.cred.DeviceHint(); got != tt.want {
+			t.Errorf("%s: DeviceHint() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestWebAuthnUser(t *testing.T) {
 	db := testDB(t)
 	user, _ := CreateUser(db, "wauser", "", "pass", "wa@test.com", false, false, sql.NullInt64{})
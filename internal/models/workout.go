@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,18 +13,21 @@ var ErrWorkoutExists = errors.New("workout already exists for this date")
 
 // Workout represents a training session for one athlete on one date.
 type Workout struct {
-	ID           int64
-	AthleteID    int64
-	Date         string // DATE as string (YYYY-MM-DD)
-	AssignmentID sql.NullInt64  // FK to athlete_programs — which assignment prescribed this workout
-	Notes        sql.NullString
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              int64
+	AthleteID       int64
+	Date            string        // DATE as string (YYYY-MM-DD)
+	AssignmentID    sql.NullInt64 // FK to athlete_programs — which assignment prescribed this workout
+	Notes           sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CompletedAt     sql.NullTime  // Set when the athlete marks the workout complete
+	StartedAt       sql.NullTime  // Set while the session timer is running
+	DurationMinutes sql.NullInt64 // Session length, from the timer or entered by hand
 
 	// Joined fields populated by list queries.
 	AthleteName  string
 	SetCount     int
-	ReviewStatus sql.NullString // NULL = unreviewed, "approved", "needs_work"
+	ReviewStatus sql.NullString // NULL = unreviewed, or one of ReviewStatuses
 	ProgramName  string         // Joined from athlete_programs → program_templates
 }
 
@@ -54,12 +58,55 @@ func CreateWorkout(db *sql.DB, athleteID int64, date, notes string, assignmentID
 	return GetWorkoutByID(db, id)
 }
 
+// CreateWorkoutWithBodyWeight starts a new workout and, when bodyWeight > 0,
+// records a body weight entry (in lbs) for the same date in the same transaction.
+// The body weight insert is skipped if an entry already exists for that date.
+func CreateWorkoutWithBodyWeight(db *sql.DB, athleteID int64, date, notes string, assignmentID int64, bodyWeight float64) (*Workout, error) {
+	if bodyWeight <= 0 {
+		return CreateWorkout(db, athleteID, date, notes, assignmentID)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("models: begin create workout tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertWorkout(tx, athleteID, date, notes, assignmentID, nil)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrWorkoutExists
+		}
+		return nil, fmt.Errorf("models: create workout for athlete %d on %s: %w", athleteID, date, err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM body_weights WHERE athlete_id = ? AND date = ?)`,
+		athleteID, date,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("models: check body weight for athlete %d on %s: %w", athleteID, date, err)
+	}
+	if !exists {
+		if err := insertBodyWeight(tx, athleteID, date, bodyWeight, "lbs", ""); err != nil {
+			return nil, fmt.Errorf("models: create body weight for athlete %d: %w", athleteID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("models: commit create workout tx: %w", err)
+	}
+
+	return GetWorkoutByID(db, id)
+}
+
 // GetWorkoutByID retrieves a workout by primary key.
 func GetWorkoutByID(db *sql.DB, id int64) (*Workout, error) {
 	w := &Workout{}
 	var programName sql.NullString
 	err := db.QueryRow(
-		`SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, a.name,
+		`SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, w.completed_at, w.started_at, w.duration_minutes, a.name,
 		        (SELECT COUNT(*) FROM workout_sets ws WHERE ws.workout_id = w.id),
 		        COALESCE(pt.name, '')
 		 FROM workouts w
@@ -67,7 +114,7 @@ func GetWorkoutByID(db *sql.DB, id int64) (*Workout, error) {
 		 LEFT JOIN athlete_programs ap ON ap.id = w.assignment_id
 		 LEFT JOIN program_templates pt ON pt.id = ap.template_id
 		 WHERE w.id = ?`, id,
-	).Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.AthleteName, &w.SetCount, &programName)
+	).Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.CompletedAt, &w.StartedAt, &w.DurationMinutes, &w.AthleteName, &w.SetCount, &programName)
 	w.ProgramName = programName.String
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -83,7 +130,7 @@ func GetWorkoutByAthleteDate(db *sql.DB, athleteID int64, date string) (*Workout
 	w := &Workout{}
 	var programName sql.NullString
 	err := db.QueryRow(
-		`SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, a.name,
+		`SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, w.completed_at, w.started_at, w.duration_minutes, a.name,
 		        (SELECT COUNT(*) FROM workout_sets ws WHERE ws.workout_id = w.id),
 		        COALESCE(pt.name, '')
 		 FROM workouts w
@@ -91,7 +138,7 @@ func GetWorkoutByAthleteDate(db *sql.DB, athleteID int64, date string) (*Workout
 		 LEFT JOIN athlete_programs ap ON ap.id = w.assignment_id
 		 LEFT JOIN program_templates pt ON pt.id = ap.template_id
 		 WHERE w.athlete_id = ? AND w.date = ?`, athleteID, date,
-	).Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.AthleteName, &w.SetCount, &programName)
+	).Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.CompletedAt, &w.StartedAt, &w.DurationMinutes, &w.AthleteName, &w.SetCount, &programName)
 	w.ProgramName = programName.String
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -102,6 +149,36 @@ func GetWorkoutByAthleteDate(db *sql.DB, athleteID int64, date string) (*Workout
 	return w, nil
 }
 
+// MostRecentWorkoutBefore returns the athlete's most recent workout dated
+// before beforeDate that has at least one logged set. When exerciseIDs is
+// non-empty, only workouts containing at least one of those exercises are
+// considered. Returns ErrNotFound if no such workout exists.
+func MostRecentWorkoutBefore(db *sql.DB, athleteID int64, beforeDate string, exerciseIDs []int64) (*Workout, error) {
+	query := `SELECT w.id FROM workouts w
+		WHERE w.athlete_id = ? AND date(w.date) < date(?)
+		  AND EXISTS (SELECT 1 FROM workout_sets ws WHERE ws.workout_id = w.id`
+	args := []any{athleteID, beforeDate}
+	if len(exerciseIDs) > 0 {
+		query += ` AND ws.exercise_id IN (?` + strings.Repeat(", ?", len(exerciseIDs)-1) + `)`
+		for _, id := range exerciseIDs {
+			args = append(args, id)
+		}
+	}
+	query += `)
+		ORDER BY w.date DESC
+		LIMIT 1`
+
+	var id int64
+	err := db.QueryRow(query, args...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("models: most recent workout before %s for athlete %d: %w", beforeDate, athleteID, err)
+	}
+	return GetWorkoutByID(db, id)
+}
+
 // UpdateWorkoutNotes updates the notes on an existing workout.
 func UpdateWorkoutNotes(db *sql.DB, id int64, notes string) error {
 	var notesVal sql.NullString
@@ -120,6 +197,96 @@ func UpdateWorkoutNotes(db *sql.DB, id int64, notes string) error {
 	return nil
 }
 
+// SetWorkoutPendingReview marks a workout complete, which places it in the
+// coach's pending-review queue until it is reviewed. Marking a workout that is
+// already complete keeps the original completion time.
+func SetWorkoutPendingReview(db *sql.DB, id int64) error {
+	result, err := db.Exec(
+		`UPDATE workouts SET completed_at = COALESCE(completed_at, CURRENT_TIMESTAMP) WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("models: set workout %d pending review: %w", id, err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// StartWorkoutSession starts timing a workout session, discarding any duration
+// previously recorded.
+func StartWorkoutSession(db *sql.DB, id int64) error {
+	result, err := db.Exec(
+		`UPDATE workouts SET started_at = CURRENT_TIMESTAMP, duration_minutes = NULL WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("models: start workout %d session: %w", id, err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// StopWorkoutSession stops a running session timer and records the elapsed
+// time, rounded to the nearest minute (at least one), as the workout's
+// duration. Returns the recorded minutes, or ErrInvalidInput if the timer
+// isn't running.
+func StopWorkoutSession(db *sql.DB, id int64) (int, error) {
+	var minutes int
+	err := db.QueryRow(
+		`UPDATE workouts
+		 SET duration_minutes = MAX(1, CAST(ROUND((julianday('now') - julianday(started_at)) * 1440) AS INTEGER)),
+		     started_at = NULL
+		 WHERE id = ? AND started_at IS NOT NULL
+		 RETURNING duration_minutes`, id,
+	).Scan(&minutes)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := GetWorkoutByID(db, id); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("models: stop workout %d session: not running: %w", id, ErrInvalidInput)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("models: stop workout %d session: %w", id, err)
+	}
+	return minutes, nil
+}
+
+// SetWorkoutDuration records a workout's session length in minutes and stops
+// any running timer. Zero clears the duration. Returns ErrInvalidInput for a
+// negative value.
+func SetWorkoutDuration(db *sql.DB, id int64, minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("models: set workout %d duration %d: %w", id, minutes, ErrInvalidInput)
+	}
+	var val sql.NullInt64
+	if minutes > 0 {
+		val = sql.NullInt64{Int64: int64(minutes), Valid: true}
+	}
+	result, err := db.Exec(
+		`UPDATE workouts SET duration_minutes = ?, started_at = NULL WHERE id = ?`, val, id)
+	if err != nil {
+		return fmt.Errorf("models: set workout %d duration: %w", id, err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FormatDuration renders a session length as "45m" or "1h 5m".
+func FormatDuration(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
+
 // DeleteWorkout removes a workout and all its sets (CASCADE).
 func DeleteWorkout(db *sql.DB, id int64) error {
 	result, err := db.Exec(`DELETE FROM workouts WHERE id = ?`, id)
@@ -133,9 +300,13 @@ func DeleteWorkout(db *sql.DB, id int64) error {
 	return nil
 }
 
-// WorkoutPageSize is the number of workouts returned per page.
+// WorkoutPageSize is the default number of workouts returned per page.
 const WorkoutPageSize = 50
 
+// workoutBatchSize is the page size ListAllWorkouts uses when walking an
+// athlete's full history.
+const workoutBatchSize = 500
+
 // WorkoutPage holds a page of workouts and whether more rows exist.
 type WorkoutPage struct {
 	Workouts []*Workout
@@ -143,11 +314,15 @@ type WorkoutPage struct {
 }
 
 // ListWorkouts returns workouts for an athlete, ordered by date descending.
-// Pass offset=0 for the first page. Returns up to WorkoutPageSize rows and
-// sets HasMore if additional rows exist beyond the current page.
-func ListWorkouts(db *sql.DB, athleteID int64, offset int) (*WorkoutPage, error) {
+// Pass offset=0 for the first page. Returns up to limit rows (WorkoutPageSize
+// if limit <= 0) and sets HasMore if additional rows exist beyond the current
+// page.
+func ListWorkouts(db *sql.DB, athleteID int64, offset, limit int) (*WorkoutPage, error) {
+	if limit <= 0 {
+		limit = WorkoutPageSize
+	}
 	rows, err := db.Query(`
-		SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, a.name,
+		SELECT w.id, w.athlete_id, w.date, w.assignment_id, w.notes, w.created_at, w.updated_at, w.completed_at, w.started_at, w.duration_minutes, a.name,
 		       (SELECT COUNT(*) FROM workout_sets ws WHERE ws.workout_id = w.id),
 		       wr.status, COALESCE(pt.name, '')
 		FROM workouts w
@@ -157,7 +332,7 @@ func ListWorkouts(db *sql.DB, athleteID int64, offset int) (*WorkoutPage, error)
 		LEFT JOIN program_templates pt ON pt.id = ap.template_id
 		WHERE w.athlete_id = ?
 		ORDER BY w.date DESC
-		LIMIT ? OFFSET ?`, athleteID, WorkoutPageSize+1, offset)
+		LIMIT ? OFFSET ?`, athleteID, limit+1, offset)
 	if err != nil {
 		return nil, fmt.Errorf("models: list workouts for athlete %d: %w", athleteID, err)
 	}
@@ -167,7 +342,7 @@ func ListWorkouts(db *sql.DB, athleteID int64, offset int) (*WorkoutPage, error)
 	for rows.Next() {
 		w := &Workout{}
 		var programName sql.NullString
-		if err := rows.Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.AthleteName, &w.SetCount, &w.ReviewStatus, &programName); err != nil {
+		if err := rows.Scan(&w.ID, &w.AthleteID, &w.Date, &w.AssignmentID, &w.Notes, &w.CreatedAt, &w.UpdatedAt, &w.CompletedAt, &w.StartedAt, &w.DurationMinutes, &w.AthleteName, &w.SetCount, &w.ReviewStatus, &programName); err != nil {
 			return nil, fmt.Errorf("models: scan workout: %w", err)
 		}
 		w.ProgramName = programName.String
@@ -177,13 +352,32 @@ func ListWorkouts(db *sql.DB, athleteID int64, offset int) (*WorkoutPage, error)
 		return nil, err
 	}
 
-	hasMore := len(workouts) > WorkoutPageSize
+	hasMore := len(workouts) > limit
 	if hasMore {
-		workouts = workouts[:WorkoutPageSize]
+		workouts = workouts[:limit]
 	}
 	return &WorkoutPage{Workouts: workouts, HasMore: hasMore}, nil
 }
 
+// ListAllWorkouts returns every workout for an athlete, ordered by date
+// descending. It pages through ListWorkouts in large batches and is meant
+// for bulk paths such as export.
+func ListAllWorkouts(db *sql.DB, athleteID int64) ([]*Workout, error) {
+	var all []*Workout
+	offset := 0
+	for {
+		page, err := ListWorkouts(db, athleteID, offset, workoutBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Workouts...)
+		if !page.HasMore {
+			return all, nil
+		}
+		offset += workoutBatchSize
+	}
+}
+
 // WorkoutStats returns the total workout count and earliest workout date for
 // an athlete in a single query. Returns count=0Source and earliest="" if no workouts exist.
 func WorkoutStats(db *sql.DB, athleteID int64) (count int, earliest string, err error) {
@@ -12,7 +12,7 @@ type WorkoutReview struct {
 	ID        int64
 	WorkoutID int64
 	CoachID   sql.NullInt64
-	Status    string // "approved" or "needs_work"
+	Status    string // one of ReviewStatuses
 	Notes     sql.NullString
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -24,8 +24,34 @@ type WorkoutReview struct {
 // ReviewStatusApproved indicates the coach approved the workout.
 const ReviewStatusApproved = "approved"
 
-// ReviewStatusNeedsWork indicates the coach wants the athlete to address feedback.
-const ReviewStatusNeedsWork = "needs_work"
+// ReviewStatusNeedsChanges indicates the coach wants the athlete to address feedback.
+const ReviewStatusNeedsChanges = "needs_changes"
+
+// ReviewStatusFlaggedInjury indicates the coach saw signs of an injury or pain
+// that needs follow-up. Flagging notifies the athlete's coach and admins.
+const ReviewStatusFlaggedInjury = "flagged_injury"
+
+// ReviewStatuses lists the valid review statuses in display order.
+var ReviewStatuses = []string{ReviewStatusApproved, ReviewStatusNeedsChanges, ReviewStatusFlaggedInjury}
+
+// legacyReviewStatusNeedsWork is the pre-enum name for needs_changes. It
+// still appears in older RepLog JSON exports.
+const legacyReviewStatusNeedsWork = "needs_work"
+
+// NormalizeReviewStatus maps a submitted review status onto ReviewStatuses,
+// accepting the legacy "needs_work" name. Returns ErrInvalidInput for an
+// unknown status.
+func NormalizeReviewStatus(status string) (string, error) {
+	if status == legacyReviewStatusNeedsWork {
+		return ReviewStatusNeedsChanges, nil
+	}
+	for _, s := range ReviewStatuses {
+		if status == s {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("models: review status %q: %w", status, ErrInvalidInput)
+}
 
 // UnreviewedWorkout represents a workout pending coach review, with joined fields
 // for display in the review dashboard.
@@ -42,7 +68,8 @@ type UnreviewedWorkout struct {
 type ReviewStats struct {
 	PendingCount  int
 	ApprovedCount int
-	NeedsWork     int
+	NeedsChanges  int
+	FlaggedInjury int
 }
 
 // CreateWorkoutReview inserts a new review for a workout. Returns ErrWorkoutExists
@@ -147,8 +174,9 @@ func DeleteWorkoutReview(db *sql.DB, id int64) error {
 	return nil
 }
 
-// ListUnreviewedWorkouts returns all workouts that have not been reviewed,
-// ordered by date descending. Useful for the coach review dashboard.
+// ListUnreviewedWorkouts returns completed workouts that have not been
+// reviewed, ordered by date descending. Workouts still in progress are left
+// out. Useful for the coach review dashboard.
 func ListUnreviewedWorkouts(db *sql.DB) ([]*UnreviewedWorkout, error) {
 	rows, err := db.Query(`
 		SELECT w.id, w.athlete_id, a.name, w.date, w.notes,
@@ -156,7 +184,7 @@ func ListUnreviewedWorkouts(db *sql.DB) ([]*UnreviewedWorkout, error) {
 		FROM workouts w
 		JOIN athletes a ON a.id = w.athlete_id
 		LEFT JOIN workout_reviews wr ON wr.workout_id = w.id
-		WHERE wr.id IS NULL
+		WHERE wr.id IS NULL AND w.completed_at IS NOT NULL
 		ORDER BY w.date DESC
 		LIMIT 100`)
 	if err != nil {
@@ -179,21 +207,36 @@ func ListUnreviewedWorkouts(db *sql.DB) ([]*UnreviewedWorkout, error) {
 	return workouts, nil
 }
 
+// CountPendingReviews returns how many of an athlete's completed workouts
+// are still awaiting review.
+func CountPendingReviews(db *sql.DB, athleteID int64) (int, error) {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*)
+		FROM workouts w
+		LEFT JOIN workout_reviews wr ON wr.workout_id = w.id
+		WHERE w.athlete_id = ? AND wr.id IS NULL AND w.completed_at IS NOT NULL`, athleteID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("models: count pending reviews for athlete %d: %w", athleteID, err)
+	}
+	return count, nil
+}
+
 // GetReviewStats returns aggregate counts of review statuses for the coach dashboard.
 func GetReviewStats(db *sql.DB) (*ReviewStats, error) {
 	stats := &ReviewStats{}
 
-	// Count pending (unreviewed) workouts.
+	// Count pending (completed but unreviewed) workouts.
 	err := db.QueryRow(`
 		SELECT COUNT(*)
 		FROM workouts w
 		LEFT JOIN workout_reviews wr ON wr.workout_id = w.id
-		WHERE wr.id IS NULL`).Scan(&stats.PendingCount)
+		WHERE wr.id IS NULL AND w.completed_at IS NOT NULL`).Scan(&stats.PendingCount)
 	if err != nil {
 		return nil, fmt.Errorf("models: count pending reviews: %w", err)
 	}
 
-	// Count approved and needs_work.
+	// Count each review status.
 	rows, err := db.Query(`
 		SELECT status, COUNT(*)
 		FROM workout_reviews
@@ -212,8 +255,10 @@ func GetReviewStats(db *sql.DB) (*ReviewStats, error) {
 		switch status {
 		case ReviewStatusApproved:
 			stats.ApprovedCount = count
-		case ReviewStatusNeedsWork:
-			stats.NeedsWork = count
+		case ReviewStatusNeedsChanges:
+			stats.NeedsChanges = count
+		case ReviewStatusFlaggedInjury:
+			stats.FlaggedInjury = count
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -257,3 +302,41 @@ func AutoApproveWorkout(db *sql.DB, workoutID, coachUserID int64) error {
 	}
 	return nil
 }
+
+// ApproveWorkoutsBatch creates "approved" reviews by coachID for each of
+// workoutIDs in a single transaction. Workouts that already have a review,
+// don't exist, or are repeated in the list are skipped. Returns the IDs of
+// the workouts approved and the number skipped.
+func ApproveWorkoutsBatch(db *sql.DB, workoutIDs []int64, coachID int64) (approved []int64, skipped int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("models: begin batch approve: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO workout_reviews (workout_id, coach_id, status)
+		SELECT id, ?, ? FROM workouts WHERE id = ?
+		ON CONFLICT(workout_id) DO NOTHING`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("models: prepare batch approve: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, id := range workoutIDs {
+		result, err := stmt.Exec(coachID, ReviewStatusApproved, id)
+		if err != nil {
+			return nil, 0, fmt.Errorf("models: batch approve workout %d: %w", id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			approved = append(approved, id)
+		} else {
+			skipped++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("models: commit batch approve: %w", err)
+	}
+	return approved, skipped, nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -67,12 +68,12 @@ func TestWorkoutReviewCRUD(t *testing.T) {
 
 	t.Run("update review", func(t *testing.T) {
 		existing, _ := GetWorkoutReviewByWorkoutID(db, workout.ID)
-		rev, err := UpdateWorkoutReview(db, existing.ID, coach.ID, ReviewStatusNeedsWork, "Try heavier next time")
+		rev, err := UpdateWorkoutReview(db, existing.ID, coach.ID, ReviewStatusNeedsChanges, "Try heavier next time")
 		if err != nil {
 			t.Fatalf("update review: %v", err)
 		}
-		if rev.Status != ReviewStatusNeedsWork {
-			t.Errorf("status = %q, want %q", rev.Status, ReviewStatusNeedsWork)
+		if rev.Status != ReviewStatusNeedsChanges {
+			t.Errorf("status = %q, want %q", rev.Status, ReviewStatusNeedsChanges)
 		}
 		if !rev.Notes.Valid || rev.Notes.String != "Try heavier next time" {
 			t.Errorf("notes = %v, want 'Try heavier next time'", rev.Notes)
@@ -141,12 +142,12 @@ func TestCreateOrUpdateWorkoutReview(t *testing.T) {
 	})
 
 	t.Run("updates when already exists", func(t *testing.T) {
-		rev, err := CreateOrUpdateWorkoutReview(db, workout.ID, coach.ID, ReviewStatusNeedsWork, "Go heavier")
+		rev, err := CreateOrUpdateWorkoutReview(db, workout.ID, coach.ID, ReviewStatusNeedsChanges, "Go heavier")
 		if err != nil {
 			t.Fatalf("create or update: %v", err)
 		}
-		if rev.Status != ReviewStatusNeedsWork {
-			t.Errorf("status = %q, want %q", rev.Status, ReviewStatusNeedsWork)
+		if rev.Status != ReviewStatusNeedsChanges {
+			t.Errorf("status = %q, want %q", rev.Status, ReviewStatusNeedsChanges)
 		}
 		if !rev.Notes.Valid || rev.Notes.String != "Go heavier" {
 			t.Errorf("notes = %v, want 'Go heavier'", rev.Notes)
@@ -162,7 +163,13 @@ func TestListUnreviewedWorkouts(t *testing.T) {
 
 	w1, _ := CreateWorkout(db, athlete.ID, "2026-02-10", "", 0)
 	w2, _ := CreateWorkout(db, athlete.ID, "2026-02-11", "", 0)
-	CreateWorkout(db, athlete.ID, "2026-02-12", "", 0)
+	w3, _ := CreateWorkout(db, athlete.ID, "2026-02-12", "", 0)
+	inProgress, _ := CreateWorkout(db, athlete.ID, "2026-02-13", "", 0)
+	for _, w := range []*Workout{w1, w2, w3} {
+		if err := SetWorkoutPendingReview(db, w.ID); err != nil {
+			t.Fatalf("set pending review: %v", err)
+		}
+	}
 
 	// Review w1 only.
 	CreateWorkoutReview(db, w1.ID, coach.ID, ReviewStatusApproved, "")
@@ -182,11 +189,15 @@ func TestListUnreviewedWorkouts(t *testing.T) {
 		t.Error("unreviewed entries should have different workout IDs")
 	}
 
-	// Verify none of them are w1 (which was reviewed).
+	// Verify none of them are w1 (which was reviewed) or the workout that
+	// was never marked complete.
 	for _, uw := range unreviewed {
 		if uw.WorkoutID == w1.ID {
 			t.Errorf("reviewed workout %d should not appear in unreviewed list", w1.ID)
 		}
+		if uw.WorkoutID == inProgress.ID {
+			t.Errorf("in-progress workout %d should not appear in unreviewed list", inProgress.ID)
+		}
 	}
 
 	_ = w2 // suppress unused
@@ -200,10 +211,14 @@ func TestGetReviewStats(t *testing.T) {
 
 	w1, _ := CreateWorkout(db, athlete.ID, "2026-02-01", "", 0)
 	w2, _ := CreateWorkout(db, athlete.ID, "2026-02-02", "", 0)
-	CreateWorkout(db, athlete.ID, "2026-02-03", "", 0) // unreviewed
+	w3, _ := CreateWorkout(db, athlete.ID, "2026-02-03", "", 0) // unreviewed
+	CreateWorkout(db, athlete.ID, "2026-02-04", "", 0)          // in progress
+	if err := SetWorkoutPendingReview(db, w3.ID); err != nil {
+		t.Fatalf("set pending review: %v", err)
+	}
 
 	CreateWorkoutReview(db, w1.ID, coach.ID, ReviewStatusApproved, "")
-	CreateWorkoutReview(db, w2.ID, coach.ID, ReviewStatusNeedsWork, "Fix form")
+	CreateWorkoutReview(db, w2.ID, coach.ID, ReviewStatusNeedsChanges, "Fix form")
 
 	stats, err := GetReviewStats(db)
 	if err != nil {
@@ -216,8 +231,8 @@ func TestGetReviewStats(t *testing.T) {
 	if stats.ApprovedCount != 1 {
 		t.Errorf("approved = %d, want 1", stats.ApprovedCount)
 	}
-	if stats.NeedsWork != 1 {
-		t.Errorf("needs_work = %d, want 1", stats.NeedsWork)
+	if stats.NeedsChanges != 1 {
+		t.Errorf("needs_changes = %d, want 1", stats.NeedsChanges)
 	}
 }
 
@@ -301,8 +316,8 @@ func TestAutoApproveWorkout_SkipsExistingReview(t *testing.T) {
 	coach := seedCoachUser(t, db)
 	workout, _ := CreateWorkout(db, athlete.ID, "2026-04-02", "", 0)
 
-	// Manually mark as needs_work.
-	_, err := CreateWorkoutReview(db, workout.ID, coach.ID, ReviewStatusNeedsWork, "Fix your form")
+	// Manually mark as needs_changes.
+	_, err := CreateWorkoutReview(db, workout.ID, coach.ID, ReviewStatusNeedsChanges, "Fix your form")
 	if err != nil {
 		t.Fatalf("create review: %v", err)
 	}
@@ -316,7 +331,92 @@ func TestAutoApproveWorkout_SkipsExistingReview(t *testing.T) {
 	if err != nil {
 		t.Fatalf("get review: %v", err)
 	}
-	if rev.Status != ReviewStatusNeedsWork {
-		t.Errorf("status = %q, want %q (should not be overwritten)", rev.Status, ReviewStatusNeedsWork)
+	if rev.Status != ReviewStatusNeedsChanges {
+		t.Errorf("status = %q, want %q (should not be overwritten)", rev.Status, ReviewStatusNeedsChanges)
+	}
+}
+
+func TestApproveWorkoutsBatch(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Camp Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	coach := seedCoachUser(t, db)
+	w1, _ := CreateWorkout(db, athlete.ID, "2026-02-14", "", 0)
+	w2, _ := CreateWorkout(db, athlete.ID, "2026-02-15", "", 0)
+	w3, _ := CreateWorkout(db, athlete.ID, "2026-02-16", "", 0)
+	if _, err := CreateWorkoutReview(db, w3.ID, coach.ID, ReviewStatusNeedsChanges, "Redo"); err != nil {
+		t.Fatalf("create review: %v", err)
+	}
+
+	approved, skipped, err := ApproveWorkoutsBatch(db, []int64{w1.ID, w2.ID, w3.ID, w1.ID, 99999}, coach.ID)
+	if err != nil {
+		t.Fatalf("batch approve: %v", err)
+	}
+	if len(approved) != 2 || approved[0] != w1.ID || approved[1] != w2.ID || skipped != 3 {
+		t.Errorf("approved, skipped = %v, %d; want [%d %d], 3", approved, skipped, w1.ID, w2.ID)
+	}
+
+	for _, id := range []int64{w1.ID, w2.ID} {
+		rev, err := GetWorkoutReviewByWorkoutID(db, id)
+		if err != nil {
+			t.Fatalf("get review for workout %d: %v", id, err)
+		}
+		if rev.Status != ReviewStatusApproved || rev.CoachID.Int64 != coach.ID {
+			t.Errorf("workout %d review = %s by %d, want approved by %d", id, rev.Status, rev.CoachID.Int64, coach.ID)
+		}
+	}
+
+	rev, _ := GetWorkoutReviewByWorkoutID(db, w3.ID)
+	if rev.Status != ReviewStatusNeedsChanges {
+		t.Errorf("existing review status = %q, want it left as %q", rev.Status, ReviewStatusNeedsChanges)
+	}
+}
+
+func TestNormalizeReviewStatus(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"approved", ReviewStatusApproved, false},
+		{"needs_changes", ReviewStatusNeedsChanges, false},
+		{"flagged_injury", ReviewStatusFlaggedInjury, false},
+		{"needs_work", ReviewStatusNeedsChanges, false}, // legacy name
+		{"", "", true},
+		{"rejected", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeReviewStatus(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("NormalizeReviewStatus(%q) err = %v, want ErrInvalidInput", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeReviewStatus(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetReviewStats_FlaggedInjury(t *testing.T) {
+	db := testDB(t)
+
+	athlete, _ := CreateAthlete(db, "Flagged Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	coach := seedCoachUser(t, db)
+	w, _ := CreateWorkout(db, athlete.ID, "2026-02-01", "", 0)
+	if _, err := CreateWorkoutReview(db, w.ID, coach.ID, ReviewStatusFlaggedInjury, "Knee pain on squats"); err != nil {
+		t.Fatalf("create flagged review: %v", err)
+	}
+
+	stats, err := GetReviewStats(db)
+	if err != nil {
+		t.Fatalf("get review stats: %v", err)
+	}
+	if stats.FlaggedInjury != 1 || stats.NeedsChanges != 0 {
+		t.Errorf("flagged, needs changes = %d, %d; want 1, 0", stats.FlaggedInjury, stats.NeedsChanges)
+	}
+
+	if _, err := db.Exec(`UPDATE workout_reviews SET status = 'needs_work' WHERE workout_id = ?`, w.ID); err == nil {
+		t.Error("expected the legacy needs_work status to be rejected by the schema")
 	}
 }
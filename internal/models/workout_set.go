@@ -40,8 +40,26 @@ func (ws *WorkoutSet) RepsLabel() string {
 	}
 }
 
+// defaultRepType returns the rep type for a set logged without one:
+// "each_side" for unilateral exercises, otherwise "reps".
+func defaultRepType(tx *sql.Tx, exerciseID int64) (string, error) {
+	var unilateral bool
+	err := tx.QueryRow(`SELECT unilateral FROM exercises WHERE id = ?`, exerciseID).Scan(&unilateral)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "reps", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("models: get unilateral for exercise %d: %w", exerciseID, err)
+	}
+	if unilateral {
+		return "each_side", nil
+	}
+	return "reps", nil
+}
+
 // AddSet inserts a new set into a workout. set_number is auto-calculated as the
-// next number for the given workout+exercise. The read-then-write is wrapped in
+// next number for the given workout+exercise. An empty repType defaults to
+// "each_side" for unilateral exercises and "reps" otherwise. The read-then-write is wrapped in
 // a transaction to prevent duplicate set numbers under concurrent requests.
 func AddSet(db *sql.DB, workoutID, exerciseID int64, reps int, weight float64, rpe float64, repType, category, notes string) (*WorkoutSet, error) {
 	var weightVal sql.NullFloat64
@@ -56,9 +74,6 @@ func AddSet(db *sql.DB, workoutID, exerciseID int64, reps int, weight float64, r
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
 	}
-	if repType == "" {
-		repType = "reps"
-	}
 	if category == "" {
 		category = "main"
 	}
@@ -69,6 +84,13 @@ func AddSet(db *sql.DB, workoutID, exerciseID int64, reps int, weight float64, r
 	}
 	defer tx.Rollback()
 
+	if repType == "" {
+		repType, err = defaultRepType(tx, exerciseID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Compute next set_number for this workout+exercise.
 	var nextSet int
 	err = tx.QueryRow(
@@ -95,6 +117,37 @@ func AddSet(db *sql.DB, workoutID, exerciseID int64, reps int, weight float64, r
 	return GetSetByID(db, id)
 }
 
+// CopyWorkoutSets clones the set structure (exercise, reps, weight, rep type,
+// category) of sourceWorkoutID into targetWorkoutID, leaving RPE and notes
+// blank. Exercises already logged in the target are skipped. Returns the
+// number of sets copied.
+func CopyWorkoutSets(db *sql.DB, sourceWorkoutID, targetWorkoutID int64) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("models: begin tx for copy sets: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO workout_sets (workout_id, exercise_id, set_number, reps, weight, rep_type, category)
+		SELECT ?, src.exercise_id, src.set_number, src.reps, src.weight, src.rep_type, src.category
+		FROM workout_sets src
+		WHERE src.workout_id = ?
+		  AND src.exercise_id NOT IN (SELECT exercise_id FROM workout_sets WHERE workout_id = ?)
+		ORDER BY src.id`,
+		targetWorkoutID, sourceWorkoutID, targetWorkoutID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: copy sets from workout %d to %d: %w", sourceWorkoutID, targetWorkoutID, err)
+	}
+	n, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("models: commit copy sets: %w", err)
+	}
+	return int(n), nil
+}
+
 // AddMultipleSets inserts count identical sets for a workout+exercise in a
 // single transaction. Returns the created sets. Useful for logging e.g.
 // "5×5 @ 135 lbs" in one action.
@@ -122,9 +175,6 @@ func AddMultipleSets(db *sql.DB, workoutID, exerciseID int64, count, reps int, w
 	if notes != "" {
 		notesVal = sql.NullString{String: notes, Valid: true}
 	}
-	if repType == "" {
-		repType = "reps"
-	}
 	if category == "" {
 		category = "main"
 	}
@@ -135,6 +185,13 @@ func AddMultipleSets(db *sql.DB, workoutID, exerciseID int64, count, reps int, w
 	}
 	defer tx.Rollback()
 
+	if repType == "" {
+		repType, err = defaultRepType(tx, exerciseID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Compute next set_number for this workout+exercise.
 	var nextSet int
 	err = tx.QueryRow(
@@ -289,6 +346,24 @@ func DeleteSet(db *sql.DB, id int64) error {
 	return tx.Commit()
 }
 
+// DeleteSetsByWorkoutExercise removes every set for one exercise within a
+// workout in a single statement. Returns the number of sets removed, or
+// ErrNotFound if the exercise has no sets in that workout.
+func DeleteSetsByWorkoutExercise(db *sql.DB, workoutID, exerciseID int64) (int, error) {
+	result, err := db.Exec(
+		`DELETE FROM workout_sets WHERE workout_id = ? AND exercise_id = ?`,
+		workoutID, exerciseID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("models: delete sets for workout %d exercise %d: %w", workoutID, exerciseID, err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return 0, ErrNotFound
+	}
+	return int(n), nil
+}
+
 // ExerciseGroup groups sets by exercise for a workout detail view.
 type ExerciseGroup struct {
 	ExerciseID   int64
@@ -297,14 +372,19 @@ type ExerciseGroup struct {
 }
 
 // ListSetsByWorkout returns all sets for a workout, grouped by exercise.
+// Groups are ordered by their reordered position (see ReorderWorkoutExercises),
+// falling back to the order in which each exercise was first logged.
 func ListSetsByWorkout(db *sql.DB, workoutID int64) ([]*ExerciseGroup, error) {
 	rows, err := db.Query(`
 		SELECT ws.id, ws.workout_id, ws.exercise_id, ws.set_number, ws.reps, ws.weight, ws.rpe, ws.rep_type, ws.category, ws.notes, ws.created_at, ws.updated_at,
 		       e.name
 		FROM workout_sets ws
 		JOIN exercises e ON e.id = ws.exercise_id
+		JOIN (SELECT exercise_id, MIN(sort_order) AS sort_order, MIN(id) AS first_id
+		      FROM workout_sets WHERE workout_id = ? GROUP BY exercise_id) g
+		  ON g.exercise_id = ws.exercise_id
 		WHERE ws.workout_id = ?
-		ORDER BY e.name COLLATE NOCASE, ws.set_number`, workoutID)
+		ORDER BY g.sort_order IS NULL, g.sort_order, g.first_id, ws.set_number`, workoutID, workoutID)
 	if err != nil {
 		return nil, fmt.Errorf("models: list sets for workout %d: %w", workoutID, err)
 	}
@@ -343,6 +423,33 @@ func ListSetsByWorkout(db *sql.DB, workoutID int64) ([]*ExerciseGroup, error) {
 	return groups, nil
 }
 
+// ReorderWorkoutExercises sets the display order of exercise groups within a
+// workout. exerciseIDs lists the exercises in their new order; exercises not
+// listed lose any previous position and fall back to first-logged order.
+func ReorderWorkoutExercises(db *sql.DB, workoutID int64, exerciseIDs []int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("models: begin tx for reorder workout %d: %w", workoutID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE workout_sets SET sort_order = NULL WHERE workout_id = ?`, workoutID); err != nil {
+		return fmt.Errorf("models: clear exercise order for workout %d: %w", workoutID, err)
+	}
+
+	for i, exerciseID := range exerciseIDs {
+		_, err := tx.Exec(
+			`UPDATE workout_sets SET sort_order = ? WHERE workout_id = ? AND exercise_id = ?`,
+			i+1, workoutID, exerciseID,
+		)
+		if err != nil {
+			return fmt.Errorf("models: set order for workout %d exercise %d: %w", workoutID, exerciseID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // ListSetsByWorkoutIDs returns all sets for multiple workouts in a single query,
 // keyed by workout ID. Each value is a slice of ExerciseGroups for that workout.
 // This replaces N calls to ListSetsByWorkout with 1 query.
@@ -367,8 +474,11 @@ func ListSetsByWorkoutIDs(db *sql.DB, workoutIDs []int64) (map[int64][]*Exercise
 		       e.name
 		FROM workout_sets ws
 		JOIN exercises e ON e.id = ws.exercise_id
+		JOIN (SELECT workout_id, exercise_id, MIN(sort_order) AS sort_order, MIN(id) AS first_id
+		      FROM workout_sets WHERE workout_id IN (`+string(placeholders)+`) GROUP BY workout_id, exercise_id) g
+		  ON g.workout_id = ws.workout_id AND g.exercise_id = ws.exercise_id
 		WHERE ws.workout_id IN (`+string(placeholders)+`)
-		ORDER BY ws.workout_id, e.name COLLATE NOCASE, ws.set_number`, args...)
+		ORDER BY ws.workout_id, g.sort_order IS NULL, g.sort_order, g.first_id, ws.set_number`, append(args, args...)...)
 	if err != nil {
 		return nil, fmt.Errorf("models: list sets for %d workouts: %w", len(workoutIDs), err)
 	}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -9,7 +11,7 @@ func TestSetCRUD(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Set Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Test Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Test Lift", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-05-01", "", 0)
 
 	t.Run("add sets with auto set_number", func(t *testing.T) {
@@ -44,11 +46,55 @@ func TestSetCRUD(t *testing.T) {
 	})
 }
 
+func TestAddSet_UnilateralDefaultsEachSide(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Split Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	lunge, _ := CreateExercise(db, "Walking Lunge", "", "", "", "", 0)
+	if err := SetExerciseUnilateral(db, lunge.ID, true); err != nil {
+		t.Fatalf("set unilateral: %v", err)
+	}
+	squat, _ := CreateExercise(db, "Back Squat", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-05-01", "", 0)
+
+	tests := []struct {
+		name       string
+		exerciseID int64
+		repType    string
+		want       string
+	}{
+		{"unilateral default", lunge.ID, "", "each_side"},
+		{"unilateral explicit", lunge.ID, "seconds", "seconds"},
+		{"bilateral default", squat.ID, "", "reps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := AddSet(db, w.ID, tt.exerciseID, 10, 0, 0, tt.repType, "", "")
+			if err != nil {
+				t.Fatalf("add set: %v", err)
+			}
+			if s.RepType != tt.want {
+				t.Errorf("rep_type = %q, want %q", s.RepType, tt.want)
+			}
+		})
+	}
+
+	sets, err := AddMultipleSets(db, w.ID, lunge.ID, 3, 10, 25, 0, "", "", "")
+	if err != nil {
+		t.Fatalf("add multiple sets: %v", err)
+	}
+	for _, s := range sets {
+		if s.RepType != "each_side" {
+			t.Errorf("multi set rep_type = %q, want each_side", s.RepType)
+		}
+	}
+}
+
 func TestUpdateSet(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Update Set Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Update Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Update Lift", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-06-01", "", 0)
 	s, _ := AddSet(db, w.ID, e.ID, 5, 100, 0, "", "", "")
 
@@ -71,7 +117,7 @@ func TestDeleteSet(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Del Set Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Del Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Del Lift", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-07-01", "", 0)
 	s, _ := AddSet(db, w.ID, e.ID, 5, 100, 0, "", "", "")
 
@@ -88,8 +134,8 @@ func TestListSetsByWorkout(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Group Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e1, _ := CreateExercise(db, "Lift A", "", "", "", 0)
-	e2, _ := CreateExercise(db, "Lift B", "", "", "", 0)
+	e1, _ := CreateExercise(db, "Lift A", "", "", "", "", 0)
+	e2, _ := CreateExercise(db, "Lift B", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-08-01", "", 0)
 
 	AddSet(db, w.ID, e1.ID, 5, 100, 0, "", "", "")
@@ -109,7 +155,7 @@ func TestDeleteSet_Renumbers(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Renum Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Renum Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Renum Lift", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-09-01", "", 0)
 
 	s1, _ := AddSet(db, w.ID, e.ID, 5, 100, 0, "", "", "")
@@ -141,11 +187,92 @@ func TestDeleteSet_NotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteSetsByWorkoutExercise(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Bulk Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	wrong, _ := CreateExercise(db, "Wrong Lift", "", "", "", "", 0)
+	keep, _ := CreateExercise(db, "Keep Lift", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-09-02", "", 0)
+
+	AddMultipleSets(db, w.ID, wrong.ID, 5, 5, 100, 0, "", "", "")
+	AddSet(db, w.ID, keep.ID, 5, 100, 0, "", "", "")
+
+	n, err := DeleteSetsByWorkoutExercise(db, w.ID, wrong.ID)
+	if err != nil {
+		t.Fatalf("delete sets: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("deleted = %d, want 5", n)
+	}
+
+	groups, _ := ListSetsByWorkout(db, w.ID)
+	if len(groups) != 1 || groups[0].ExerciseID != keep.ID {
+		t.Errorf("remaining groups = %+v, want only Keep Lift", groups)
+	}
+
+	if _, err := DeleteSetsByWorkoutExercise(db, w.ID, wrong.ID); err != ErrNotFound {
+		t.Errorf("second delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReorderWorkoutExercises(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Order Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	row, _ := CreateExercise(db, "Row", "", "", "", "", 0)
+	w, _ := CreateWorkout(db, a.ID, "2026-09-03", "", 0)
+
+	AddSet(db, w.ID, squat.ID, 5, 200, 0, "", "", "")
+	AddSet(db, w.ID, bench.ID, 5, 150, 0, "", "", "")
+	AddSet(db, w.ID, row.ID, 8, 100, 0, "", "", "")
+
+	order := func() []int64 {
+		t.Helper()
+		groups, err := ListSetsByWorkout(db, w.ID)
+		if err != nil {
+			t.Fatalf("list sets: %v", err)
+		}
+		var ids []int64
+		for _, g := range groups {
+			ids = append(ids, g.ExerciseID)
+		}
+		return ids
+	}
+
+	t.Run("defaults to first-logged order", func(t *testing.T) {
+		if got := order(); !slices.Equal(got, []int64{squat.ID, bench.ID, row.ID}) {
+			t.Errorf("order = %v, want squat, bench, row", got)
+		}
+	})
+
+	t.Run("explicit order wins", func(t *testing.T) {
+		if err := ReorderWorkoutExercises(db, w.ID, []int64{row.ID, squat.ID}); err != nil {
+			t.Fatalf("reorder: %v", err)
+		}
+		// Later sets for an ordered exercise keep the group's position.
+		AddSet(db, w.ID, squat.ID, 5, 210, 0, "", "", "")
+		if got := order(); !slices.Equal(got, []int64{row.ID, squat.ID, bench.ID}) {
+			t.Errorf("order = %v, want row, squat, bench", got)
+		}
+
+		batch, err := ListSetsByWorkoutIDs(db, []int64{w.ID})
+		if err != nil {
+			t.Fatalf("list batch: %v", err)
+		}
+		if g := batch[w.ID]; len(g) != 3 || g[0].ExerciseID != row.ID || len(g[1].Sets) != 2 {
+			t.Errorf("batch groups out of order: %+v", g)
+		}
+	})
+}
+
 func TestAddMultipleSets(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "Multi Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Multi Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Multi Lift", "", "", "", "", 0)
 	w, _ := CreateWorkout(db, a.ID, "2026-10-01", "", 0)
 
 	t.Run("creates correct number of sets", func(t *testing.T) {
@@ -184,7 +311,7 @@ func TestAddMultipleSets(t *testing.T) {
 	})
 
 	t.Run("count=1 delegates to AddSet", func(t *testing.T) {
-		e2, _ := CreateExercise(db, "Single Lift", "", "", "", 0)
+		e2, _ := CreateExercise(db, "Single Lift", "", "", "", "", 0)
 		sets, err := AddMultipleSets(db, w.ID, e2.ID, 1, 10, 50, 0, "", "", "")
 		if err != nil {
 			t.Fatalf("add single set via multi: %v", err)
@@ -205,7 +332,7 @@ func TestAddMultipleSets(t *testing.T) {
 	})
 
 	t.Run("preserves RPE and notes", func(t *testing.T) {
-		e3, _ := CreateExercise(db, "RPE Lift", "", "", "", 0)
+		e3, _ := CreateExercise(db, "RPE Lift", "", "", "", "", 0)
 		sets, err := AddMultipleSets(db, w.ID, e3.ID, 2, 5, 100, 8.5, "", "", "heavy")
 		if err != nil {
 			t.Fatalf("add sets with RPE: %v", err)
@@ -225,7 +352,7 @@ func TestListExerciseHistory(t *testing.T) {
 	db := testDB(t)
 
 	a, _ := CreateAthlete(db, "History Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "History Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "History Lift", "", "", "", "", 0)
 
 	t.Run("empty history", func(t *testing.T) {
 		page, err := ListExerciseHistory(db, a.ID, e.ID, 0)
@@ -269,7 +396,7 @@ func TestListExerciseHistory(t *testing.T) {
 	})
 
 	t.Run("different exercise not included", func(t *testing.T) {
-		e2, _ := CreateExercise(db, "Other Lift", "", "", "", 0)
+		e2, _ := CreateExercise(db, "Other Lift", "", "", "", "", 0)
 		page, err := ListExerciseHistory(db, a.ID, e2.ID, 0)
 		if err != nil {
 			t.Fatalf("list exercise history: %v", err)
@@ -285,7 +412,7 @@ func TestListRecentSetsForExercise(t *testing.T) {
 
 	a1, _ := CreateAthlete(db, "Athlete A", "", "", "", "", "", "", sql.NullInt64{}, true)
 	a2, _ := CreateAthlete(db, "Athlete B", "", "", "", "", "", "", sql.NullInt64{}, true)
-	e, _ := CreateExercise(db, "Shared Lift", "", "", "", 0)
+	e, _ := CreateExercise(db, "Shared Lift", "", "", "", "", 0)
 
 	w1, _ := CreateWorkout(db, a1.ID, "2026-01-01", "", 0)
 	AddSet(db, w1.ID, e.ID, 5, 135, 0, "", "", "")
@@ -306,7 +433,7 @@ func TestListRecentSetsForExercise(t *testing.T) {
 	}
 
 	t.Run("empty for unused exercise", func(t *testing.T) {
-		e2, _ := CreateExercise(db, "Unused Lift", "", "", "", 0)
+		e2, _ := CreateExercise(db, "Unused Lift", "", "", "", "", 0)
 		sets, err := ListRecentSetsForExercise(db, e2.ID)
 		if err != nil {
 			t.Fatalf("list recent sets: %v", err)
@@ -316,3 +443,65 @@ func TestListRecentSetsForExercise(t *testing.T) {
 		}
 	})
 }
+
+func TestCopyWorkoutSets(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Copy Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	squat, _ := CreateExercise(db, "Squat", "", "", "", "", 0)
+	bench, _ := CreateExercise(db, "Bench", "", "", "", "", 0)
+	row, _ := CreateExercise(db, "Row", "", "", "", "", 0)
+	older, _ := CreateWorkout(db, a.ID, "2026-09-01", "", 0)
+	prev, _ := CreateWorkout(db, a.ID, "2026-09-03", "", 0)
+	CreateWorkout(db, a.ID, "2026-09-04", "", 0) // no sets logged
+	target, _ := CreateWorkout(db, a.ID, "2026-09-05", "", 0)
+
+	AddSet(db, older.ID, row.ID, 10, 95, 0, "", "", "")
+	AddMultipleSets(db, prev.ID, squat.ID, 2, 5, 200, 8, "", "", "")
+	AddSet(db, prev.ID, bench.ID, 30, 0, 0, "seconds", "accessory", "hold")
+
+	t.Run("most recent skips empty workouts", func(t *testing.T) {
+		got, err := MostRecentWorkoutBefore(db, a.ID, target.Date, nil)
+		if err != nil {
+			t.Fatalf("most recent: %v", err)
+		}
+		if got.ID != prev.ID {
+			t.Errorf("workout = %d, want %d", got.ID, prev.ID)
+		}
+	})
+
+	t.Run("most recent filtered by exercise", func(t *testing.T) {
+		got, err := MostRecentWorkoutBefore(db, a.ID, target.Date, []int64{row.ID})
+		if err != nil {
+			t.Fatalf("most recent: %v", err)
+		}
+		if got.ID != older.ID {
+			t.Errorf("workout = %d, want %d", got.ID, older.ID)
+		}
+	})
+
+	t.Run("none before first workout", func(t *testing.T) {
+		if _, err := MostRecentWorkoutBefore(db, a.ID, older.Date, nil); !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("copies structure and skips logged exercises", func(t *testing.T) {
+		AddSet(db, target.ID, bench.ID, 45, 0, 0, "seconds", "", "")
+		n, err := CopyWorkoutSets(db, prev.ID, target.ID)
+		if err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("copied = %d, want 2", n)
+		}
+		groups, _ := ListSetsByWorkout(db, target.ID)
+		if len(groups) != 2 || len(groups[0].Sets) != 1 || len(groups[1].Sets) != 2 {
+			t.Fatalf("groups = %+v, want bench x1 then squat x2", groups)
+		}
+		s := groups[1].Sets[1]
+		if s.SetNumber != 2 || s.Reps != 5 || s.Weight.Float64 != 200 || s.RPE.Valid {
+			t.Errorf("copied set = %+v", s)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -39,6 +40,49 @@ func TestWorkoutCRUD(t *testing.T) {
 	})
 }
 
+func TestCreateWorkoutWithBodyWeight(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "BW Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+
+	t.Run("records body weight", func(t *testing.T) {
+		if _, err := CreateWorkoutWithBodyWeight(db, a.ID, "2026-02-01", "", 0, 181.5); err != nil {
+			t.Fatalf("create workout: %v", err)
+		}
+		bw, err := LatestBodyWeight(db, a.ID)
+		if err != nil {
+			t.Fatalf("latest body weight: %v", err)
+		}
+		if bw.Weight != 181.5 || !strings.HasPrefix(bw.Date, "2026-02-01") {
+			t.Errorf("body weight = %v on %s, want 181.5 on 2026-02-01", bw.Weight, bw.Date)
+		}
+	})
+
+	t.Run("keeps existing body weight", func(t *testing.T) {
+		if _, err := CreateBodyWeight(db, a.ID, "2026-02-02", 180, ""); err != nil {
+			t.Fatalf("create body weight: %v", err)
+		}
+		if _, err := CreateWorkoutWithBodyWeight(db, a.ID, "2026-02-02", "", 0, 190); err != nil {
+			t.Fatalf("create workout: %v", err)
+		}
+		bw, _ := LatestBodyWeight(db, a.ID)
+		if bw.Weight != 180 {
+			t.Errorf("weight = %v, want existing 180", bw.Weight)
+		}
+	})
+
+	t.Run("duplicate workout rolls back body weight", func(t *testing.T) {
+		_, err := CreateWorkoutWithBodyWeight(db, a.ID, "2026-02-01", "", 0, 175)
+		if err != ErrWorkoutExists {
+			t.Errorf("err = %v, want ErrWorkoutExists", err)
+		}
+		page, _ := ListBodyWeights(db, a.ID, 0)
+		if len(page.Entries) != 2 {
+			t.Errorf("body weights = %d, want 2", len(page.Entries))
+		}
+	})
+}
+
 func TestUpdateWorkoutNotes(t *testing.T) {
 	db := testDB(t)
 
@@ -55,6 +99,38 @@ func TestUpdateWorkoutNotes(t *testing.T) {
 	}
 }
 
+func TestSetWorkoutPendingReview(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Complete Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-02", "", 0)
+	if w.CompletedAt.Valid {
+		t.Fatal("new workout should not be complete")
+	}
+
+	if err := SetWorkoutPendingReview(db, w.ID); err != nil {
+		t.Fatalf("set pending review: %v", err)
+	}
+	completed, _ := GetWorkoutByID(db, w.ID)
+	if !completed.CompletedAt.Valid {
+		t.Fatal("expected completed_at to be set")
+	}
+
+	// Marking again keeps the original completion time.
+	db.Exec(`UPDATE workouts SET completed_at = '2026-03-02 10:00:00' WHERE id = ?`, w.ID)
+	if err := SetWorkoutPendingReview(db, w.ID); err != nil {
+		t.Fatalf("set pending review again: %v", err)
+	}
+	again, _ := GetWorkoutByID(db, w.ID)
+	if got := again.CompletedAt.Time.Format("2006-01-02 15:04:05"); got != "2026-03-02 10:00:00" {
+		t.Errorf("completed_at = %s, want original time kept", got)
+	}
+
+	if err := SetWorkoutPendingReview(db, 99999); err != ErrNotFound {
+		t.Errorf("missing workout: err = %v, want ErrNotFound", err)
+	}
+}
+
 func TestDeleteWorkout(t *testing.T) {
 	db := testDB(t)
 
@@ -78,7 +154,7 @@ func TestListWorkouts(t *testing.T) {
 	CreateWorkout(db, a.ID, "2026-01-15", "", 0)
 	CreateWorkout(db, a.ID, "2026-01-10", "", 0)
 
-	workouts, err := ListWorkouts(db, a.ID, 0)
+	workouts, err := ListWorkouts(db, a.ID, 0, WorkoutPageSize)
 	if err != nil {
 		t.Fatalf("list workouts: %v", err)
 	}
@@ -89,6 +165,34 @@ func TestListWorkouts(t *testing.T) {
 	if !strings.HasPrefix(workouts.Workouts[0].Date, "2026-01-15") {
 		t.Errorf("first date = %q, want prefix 2026-01-15", workouts.Workouts[0].Date)
 	}
+
+	t.Run("limit", func(t *testing.T) {
+		page, err := ListWorkouts(db, a.ID, 0, 2)
+		if err != nil {
+			t.Fatalf("list workouts: %v", err)
+		}
+		if len(page.Workouts) != 2 || !page.HasMore {
+			t.Errorf("got %d workouts, HasMore=%v; want 2, true", len(page.Workouts), page.HasMore)
+		}
+
+		page, err = ListWorkouts(db, a.ID, 2, 2)
+		if err != nil {
+			t.Fatalf("list workouts: %v", err)
+		}
+		if len(page.Workouts) != 1 || page.HasMore {
+			t.Errorf("got %d workouts, HasMore=%v; want 1, false", len(page.Workouts), page.HasMore)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		all, err := ListAllWorkouts(db, a.ID)
+		if err != nil {
+			t.Fatalf("list all workouts: %v", err)
+		}
+		if len(all) != 3 {
+			t.Errorf("count = %d, want 3", len(all))
+		}
+	})
 }
 
 func TestGetWorkoutByAthleteDate(t *testing.T) {
@@ -124,3 +228,82 @@ func TestGetWorkoutByAthleteDate(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkoutSessionDuration(t *testing.T) {
+	db := testDB(t)
+
+	a, _ := CreateAthlete(db, "Timed Athlete", "", "", "", "", "", "", sql.NullInt64{}, true)
+	w, _ := CreateWorkout(db, a.ID, "2026-03-02", "", 0)
+
+	if _, err := StopWorkoutSession(db, w.ID); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("stop without start: err = %v, want ErrInvalidInput", err)
+	}
+
+	if err := StartWorkoutSession(db, w.ID); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	started, _ := GetWorkoutByID(db, w.ID)
+	if !started.StartedAt.Valid {
+		t.Fatal("expected started_at to be set")
+	}
+
+	// Backdate the start so the timer has run for 45 minutes.
+	db.Exec(`UPDATE workouts SET started_at = datetime('now', '-45 minutes') WHERE id = ?`, w.ID)
+	minutes, err := StopWorkoutSession(db, w.ID)
+	if err != nil {
+		t.Fatalf("stop session: %v", err)
+	}
+	if minutes != 45 {
+		t.Errorf("minutes = %d, want 45", minutes)
+	}
+	stopped, _ := GetWorkoutByID(db, w.ID)
+	if stopped.StartedAt.Valid {
+		t.Error("expected started_at cleared after stop")
+	}
+	if !stopped.DurationMinutes.Valid || stopped.DurationMinutes.Int64 != 45 {
+		t.Errorf("duration = %v, want 45", stopped.DurationMinutes)
+	}
+
+	if err := SetWorkoutDuration(db, w.ID, 70); err != nil {
+		t.Fatalf("set duration: %v", err)
+	}
+	set, _ := GetWorkoutByID(db, w.ID)
+	if set.DurationMinutes.Int64 != 70 {
+		t.Errorf("duration = %d, want 70", set.DurationMinutes.Int64)
+	}
+
+	if err := SetWorkoutDuration(db, w.ID, 0); err != nil {
+		t.Fatalf("clear duration: %v", err)
+	}
+	cleared, _ := GetWorkoutByID(db, w.ID)
+	if cleared.DurationMinutes.Valid {
+		t.Error("expected duration cleared")
+	}
+
+	if err := SetWorkoutDuration(db, w.ID, -5); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("negative duration: err = %v, want ErrInvalidInput", err)
+	}
+	if err := SetWorkoutDuration(db, 99999, 30); err != ErrNotFound {
+		t.Errorf("missing workout: err = %v, want ErrNotFound", err)
+	}
+	if _, err := StopWorkoutSession(db, 99999); err != ErrNotFound {
+		t.Errorf("stop missing workout: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{45, "45m"},
+		{60, "1h"},
+		{65, "1h 5m"},
+		{135, "2h 15m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.minutes); got != tt.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
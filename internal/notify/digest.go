@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+// DigestItem is one notification listed in a digest email.
+type DigestItem struct {
+	Title   string
+	Message string
+	Link    string // Absolute URL when a base URL is configured.
+}
+
+// digestEnabled reports whether the user batches external notifications
+// into a digest instead of receiving one email per event.
+func digestEnabled(db *sql.DB, userID int64) bool {
+	prefs, err := models.GetUserPreferences(db, userID)
+	if err != nil {
+		log.Printf("notify: get preferences for user %d: %v", userID, err)
+		return false
+	}
+	return prefs.DigestFrequency != models.DigestOff
+}
+
+// SendDigest bundles a user's pending notifications (see
+// models.PendingDigest) into a single email and records the delivery so the
+// next digest starts from here. Returns the number of notifications
+// included; zero means there was nothing to send.
+func SendDigest(db *sql.DB, userID int64) (int, error) {
+	pending, err := models.PendingDigest(db, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pending) > 0 {
+		items := make([]DigestItem, len(pending))
+		for i, n := range pending {
+			items[i] = DigestItem{
+				Title:   n.Title,
+				Message: n.Message.String,
+				Link:    absoluteLink(db, n.Link.String),
+			}
+		}
+
+		appName := models.GetAppName(db)
+		title := fmt.Sprintf("%d new notification", len(items))
+		if len(items) != 1 {
+			title += "s"
+		}
+		subject := title + " — " + appName
+		htmlBody := renderEmail("digest.html", EmailData{
+			AppName: appName,
+			BaseURL: models.GetSetting(db, "app.base_url"),
+			Title:   title,
+			Link:    absoluteLink(db, "/notifications"),
+			Items:   items,
+		})
+		if htmlBody != "" {
+			sendHTMLToUser(db, userID, subject, htmlBody)
+		} else {
+			// Fallback to plain text if template rendering fails.
+			sendToUser(db, userID, subject, buildDigestBody(items))
+		}
+	}
+
+	var lastID int64
+	if len(pending) > 0 {
+		lastID = pending[len(pending)-1].ID
+	}
+	if err := models.MarkDigestSent(db, userID, lastID); err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// buildDigestBody constructs a plain text digest, one notification per
+// paragraph.
+func buildDigestBody(items []DigestItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = buildBody(Request{Title: item.Title, Message: item.Message, Link: item.Link})
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/carpenike/replog/internal/database"
+	"github.com/carpenike/replog/internal/models"
+)
+
+// testDB creates a fresh in-memory SQLite database with migrations applied.
+func testDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if err := database.RunMigrations(db); err != nil {
+		db.Close()
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSendDigest(t *testing.T) {
+	db := testDB(t)
+
+	user, err := models.CreateUser(db, "coach", "", "password", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	models.SetNotificationPreference(db, user.ID, models.NotifyWorkoutLogged, true, true)
+	if err := models.SetDigestFrequency(db, user.ID, models.DigestDaily); err != nil {
+		t.Fatalf("set digest frequency: %v", err)
+	}
+
+	Send(db, Request{UserID: user.ID, Type: models.NotifyWorkoutLogged, Title: "Workout ready for review"})
+	// In-app only types are not part of the email digest.
+	Send(db, Request{UserID: user.ID, Type: models.NotifyTMUpdated, Title: "Training max updated"})
+
+	n, err := SendDigest(db, user.ID)
+	if err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("digest items = %d, want 1", n)
+	}
+
+	prefs, _ := models.GetUserPreferences(db, user.ID)
+	if !prefs.LastDigestAt.Valid {
+		t.Error("expected last_digest_at to be recorded")
+	}
+
+	// Already-digested notifications aren't sent again.
+	n, err = SendDigest(db, user.ID)
+	if err != nil {
+		t.Fatalf("send second digest: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("second digest items = %d, want 0", n)
+	}
+}
+
+func TestSendDigest_CarriesOverBacklog(t *testing.T) {
+	db := testDB(t)
+
+	user, err := models.CreateUser(db, "coach", "", "password", "", true, false, sql.NullInt64{})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	models.SetNotificationPreference(db, user.ID, models.NotifyWorkoutLogged, true, true)
+	if err := models.SetDigestFrequency(db, user.ID, models.DigestDaily); err != nil {
+		t.Fatalf("set digest frequency: %v", err)
+	}
+
+	// More than one digest's worth, most created within the same second.
+	total := models.DigestMaxItems + 5
+	for i := 0; i < total; i++ {
+		Send(db, Request{UserID: user.ID, Type: models.NotifyWorkoutLogged, Title: fmt.Sprintf("Workout %d", i)})
+	}
+
+	var got []int
+	for i := 0; i < 3; i++ {
+		n, err := SendDigest(db, user.ID)
+		if err != nil {
+			t.Fatalf("send digest %d: %v", i, err)
+		}
+		got = append(got, n)
+	}
+	if got[0] != models.DigestMaxItems || got[1] != 5 || got[2] != 0 {
+		t.Errorf("digest sizes = %v, want [%d 5 0]", got, models.DigestMaxItems)
+	}
+}
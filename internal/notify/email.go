@@ -21,13 +21,16 @@ var (
 
 // EmailData holds the common fields available to all email templates.
 type EmailData struct {
-	AppName  string // Application name (from app settings).
-	BaseURL  string // Application base URL (optional).
-	Title    string // Notification title / heading.
-	Message  string // Longer body text (optional).
-	Link     string // Action URL (optional).
-	LinkText string // CTA button label (optional, defaults to "View Details").
-	LoginURL string // Magic link URL (magic_link template only).
+	AppName   string       // Application name (from app settings).
+	BaseURL   string       // Application base URL (optional).
+	Title     string       // Notification title / heading.
+	Message   string       // Longer body text (optional).
+	Link      string       // Action URL (optional).
+	LinkText  string       // CTA button label (optional, defaults to "View Details").
+	LoginURL  string       // Magic link URL (magic_link template only).
+	ResetURL  string       // Password reset URL (password_reset template only).
+	VerifyURL string       // Email verification URL (email_verification template only).
+	Items     []DigestItem // Bundled notifications (digest template only).
 }
 
 // parseEmailTemplates parses all email templates once on first use.
@@ -41,7 +44,7 @@ func parseEmailTemplates() {
 			return
 		}
 
-		pages := []string{"magic_link.html", "notification.html"}
+		pages := []string{"magic_link.html", "password_reset.html", "email_verification.html", "notification.html", "digest.html"}
 		for _, page := range pages {
 			content, err := emailFS.ReadFile("templates/" + page)
 			if err != nil {
@@ -97,6 +100,26 @@ func RenderMagicLinkEmail(db *sql.DB, loginURL string) string {
 	})
 }
 
+// RenderPasswordResetEmail renders the password reset email template.
+// Returns the full HTML body ready to pass to SendToUser.
+func RenderPasswordResetEmail(db *sql.DB, resetURL string) string {
+	return renderEmail("password_reset.html", EmailData{
+		AppName:  models.GetAppName(db),
+		BaseURL:  models.GetSetting(db, "app.base_url"),
+		ResetURL: resetURL,
+	})
+}
+
+// RenderEmailVerificationEmail renders the email verification template.
+// Returns the full HTML body ready to pass to SendToUser.
+func RenderEmailVerificationEmail(db *sql.DB, verifyURL string) string {
+	return renderEmail("email_verification.html", EmailData{
+		AppName:   models.GetAppName(db),
+		BaseURL:   models.GetSetting(db, "app.base_url"),
+		VerifyURL: verifyURL,
+	})
+}
+
 // RenderNotificationEmail renders the general notification email template.
 // Returns the full HTML body ready to pass to SendToUser.
 func RenderNotificationEmail(db *sql.DB, title, message, link string) string {
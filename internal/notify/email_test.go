@@ -38,6 +38,40 @@ func TestRenderEmail_MagicLink(t *testing.T) {
 	}
 }
 
+func TestRenderEmail_PasswordReset(t *testing.T) {
+	html := renderEmail("password_reset.html", EmailData{
+		AppName:  "RepLog",
+		BaseURL:  "https://replog.example.com",
+		ResetURL: "https://replog.example.com/auth/reset/abc123",
+	})
+
+	if html == "" {
+		t.Fatal("renderEmail returned empty string for password_reset.html")
+	}
+	for _, want := range []string{"Reset your password", "https://replog.example.com/auth/reset/abc123", "expires in 30 minutes"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestRenderEmail_EmailVerification(t *testing.T) {
+	html := renderEmail("email_verification.html", EmailData{
+		AppName:   "RepLog",
+		BaseURL:   "https://replog.example.com",
+		VerifyURL: "https://replog.example.com/auth/verify-email/abc123",
+	})
+
+	if html == "" {
+		t.Fatal("renderEmail returned empty string for email_verification.html")
+	}
+	for _, want := range []string{"Verify your email address", "https://replog.example.com/auth/verify-email/abc123", "expires in 24 hours"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+}
+
 func TestRenderEmail_Notification(t *testing.T) {
 	html := renderEmail("notification.html", EmailData{
 		AppName: "Smith Gym",
@@ -116,3 +150,32 @@ func TestRenderEmail_UnknownTemplate(t *testing.T) {
 		t.Errorf("expected empty string for unknown template, got %d bytes", len(html))
 	}
 }
+
+func TestRenderEmail_Digest(t *testing.T) {
+	html := renderEmail("digest.html", EmailData{
+		AppName: "RepLog",
+		Title:   "2 new notifications",
+		Link:    "https://replog.example.com/notifications",
+		Items: []DigestItem{
+			{Title: "Workout ready for review", Message: "Kid completed their workout.", Link: "https://replog.example.com/athletes/1/workouts/2"},
+			{Title: "Training Max Updated"},
+		},
+	})
+
+	if html == "" {
+		t.Fatal("renderEmail returned empty string for digest.html")
+	}
+
+	for _, want := range []string{
+		"2 new notifications",
+		"Workout ready for review",
+		"Kid completed their workout.",
+		"https://replog.example.com/athletes/1/workouts/2",
+		"Training Max Updated",
+		"View All Notifications",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+}
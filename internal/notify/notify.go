@@ -1,7 +1,8 @@
 // Package notify provides channel-agnostic notification dispatch.
 //
-// Two delivery modes:
+// Three delivery modes:
 //   - Per-user: email sent to the target user's address via app-level SMTP config.
+//   - Per-user webhook: JSON POSTed to a URL the user configured.
 //   - Broadcast: sent to globally configured Shoutrrr URLs (ntfy, Discord, etc.).
 //
 // Producers call Send() with a notification request. The dispatcher checks
@@ -50,32 +51,52 @@ func Send(db *sql.DB, req Request) {
 
 	// External channel: email the target user and/or broadcast.
 	if pref.External {
-		// HTML email to the target user.
-		link := req.Link
-		if link != "" && !strings.HasPrefix(link, "http") {
-			if baseURL := models.GetSetting(db, "app.base_url"); baseURL != "" {
-				link = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(link, "/")
+		// HTML email to the target user, unless it will go out in their
+		// digest instead. Only in-app notifications can be batched, since
+		// the digest is built from the notifications table.
+		if !pref.InApp || !digestEnabled(db, req.UserID) {
+			htmlBody := renderEmail("notification.html", EmailData{
+				AppName: models.GetAppName(db),
+				BaseURL: models.GetSetting(db, "app.base_url"),
+				Title:   req.Title,
+				Message: req.Message,
+				Link:    absoluteLink(db, req.Link),
+			})
+			if htmlBody != "" {
+				sendHTMLToUser(db, req.UserID, req.Title, htmlBody)
+			} else {
+				// Fallback to plain text if template rendering fails.
+				sendToUser(db, req.UserID, req.Title, buildBody(req))
 			}
 		}
-		htmlBody := renderEmail("notification.html", EmailData{
-			AppName: models.GetAppName(db),
-			BaseURL: models.GetSetting(db, "app.base_url"),
-			Title:   req.Title,
-			Message: req.Message,
-			Link:    link,
-		})
-		if htmlBody != "" {
-			sendHTMLToUser(db, req.UserID, req.Title, htmlBody)
-		} else {
-			// Fallback to plain text if template rendering fails.
-			sendToUser(db, req.UserID, req.Title, buildBody(req))
-		}
+
+		// JSON to the user's own webhook, if configured.
+		sendWebhook(db, req)
 
 		// Plain text to broadcast channels (ntfy, Discord, etc.).
 		sendBroadcast(db, buildBody(req))
 	}
 }
 
+// SendToAthlete sends the notification to every user account linked to the
+// athlete, except exceptUserID (typically the user who triggered it). The
+// request's UserID is ignored and its AthleteID is set to the athlete.
+func SendToAthlete(db *sql.DB, athleteID, exceptUserID int64, req Request) {
+	userIDs, err := models.ListAthleteUserIDs(db, athleteID)
+	if err != nil {
+		log.Printf("notify: list users for athlete %d: %v", athleteID, err)
+		return
+	}
+	req.AthleteID = sql.NullInt64{Int64: athleteID, Valid: true}
+	for _, id := range userIDs {
+		if id == exceptUserID {
+			continue
+		}
+		req.UserID = id
+		Send(db, req)
+	}
+}
+
 // SendToUser sends an HTML email to a specific user's email address.
 // Used for targeted delivery like magic links where only the recipient should
 // see the message. Does not check preferences or create in-app notifications.
@@ -240,6 +261,18 @@ func buildSMTPURLDirect(db *sql.DB, toEmail, subject string, html bool) string {
 
 // --- Helpers ---
 
+// absoluteLink prefixes a relative link with the configured base URL so it
+// works outside the app, e.g. in an email. Links are returned unchanged when
+// no base URL is set.
+func absoluteLink(db *sql.DB, link string) string {
+	if link != "" && !strings.HasPrefix(link, "http") {
+		if baseURL := models.GetSetting(db, "app.base_url"); baseURL != "" {
+			return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(link, "/")
+		}
+	}
+	return link
+}
+
 // buildBody constructs the message body from a Request.
 func buildBody(req Request) string {
 	body := req.Title
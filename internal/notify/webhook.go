@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed
+// with the webhook secret, as "sha256=<hex>". Only sent when a secret is set.
+const SignatureHeader = "X-RepLog-Signature"
+
+// WebhookPayload is the JSON body POSTed to a user's notification webhook.
+type WebhookPayload struct {
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Message   string          `json:"message,omitempty"`
+	Link      string          `json:"link,omitempty"`
+	Athlete   *WebhookAthlete `json:"athlete,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// WebhookAthlete identifies the athlete a webhook notification is about.
+type WebhookAthlete struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// webhookRetryDelays is the backoff between delivery attempts. A request
+// that fails or gets a non-2xx response is retried once per entry.
+var webhookRetryDelays = []time.Duration{2 * time.Second, 10 * time.Second, 60 * time.Second}
+
+// webhookClient refuses to connect to addresses models.WebhookAddrAllowed
+// rejects. The check runs on the resolved address at dial time, so it also
+// covers redirects and host names that resolve (or later re-resolve) to a
+// private address. Proxies are bypassed so the check sees the real target.
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		Proxy:       nil,
+		DialContext: (&net.Dialer{Timeout: 5 * time.Second, Control: webhookDialControl}).DialContext,
+	},
+}
+
+// webhookDialControl rejects connections to disallowed addresses.
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook address %q: %w", address, err)
+	}
+	if !models.WebhookAddrAllowed(ap.Addr()) {
+		return fmt.Errorf("webhook address %s is not allowed: %w", ap.Addr(), models.ErrPrivateWebhookAddress)
+	}
+	return nil
+}
+
+// sendWebhook POSTs the notification to the user's webhook, if one is
+// configured. Delivery, including retries, runs in the background.
+func sendWebhook(db *sql.DB, req Request) {
+	hook, err := models.GetNotificationWebhook(db, req.UserID)
+	if errors.Is(err, models.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("notify: get webhook for user %d: %v", req.UserID, err)
+		return
+	}
+
+	payload := WebhookPayload{
+		Type:      req.Type,
+		Title:     req.Title,
+		Message:   req.Message,
+		Link:      absoluteLink(db, req.Link),
+		Timestamp: time.Now().UTC(),
+	}
+	if req.AthleteID.Valid {
+		payload.Athlete = &WebhookAthlete{ID: req.AthleteID.Int64}
+		if athlete, err := models.GetAthleteByID(db, req.AthleteID.Int64); err == nil {
+			payload.Athlete.Name = athlete.Name
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("notify: encode webhook payload for user %d: %v", req.UserID, err)
+		return
+	}
+
+	go func() {
+		if err := deliverWebhook(hook.URL, hook.Secret, body); err != nil {
+			log.Printf("notify: webhook delivery failed for user %d: %v", req.UserID, err)
+		}
+	}()
+}
+
+// deliverWebhook POSTs body to url, retrying with backoff until a 2xx
+// response or the retries run out. A disallowed address is not retried.
+// Returns the last error.
+func deliverWebhook(url, secret string, body []byte) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = postWebhook(url, secret, body); err == nil {
+			return nil
+		}
+		if errors.Is(err, models.ErrPrivateWebhookAddress) {
+			return err
+		}
+		if attempt >= len(webhookRetryDelays) {
+			return fmt.Errorf("after %d attempts: %w", attempt+1, err)
+		}
+		time.Sleep(webhookRetryDelays[attempt])
+	}
+}
+
+// postWebhook makes a single delivery attempt.
+func postWebhook(url, secret string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RepLog-Webhook")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+signWebhook(secret, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signWebhook returns the hex HMAC-SHA256 of body keyed with secret.
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+// useTestWebhookServer points webhook deliveries at srv whatever the URL,
+// bypassing the private-address check that would refuse a loopback server.
+func useTestWebhookServer(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	old := webhookClient
+	webhookClient = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+	t.Cleanup(func() { webhookClient = old })
+}
+
+func TestDeliverWebhook_RetriesUntilSuccess(t *testing.T) {
+	old := webhookRetryDelays
+	webhookRetryDelays = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	t.Cleanup(func() { webhookRetryDelays = old })
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	useTestWebhookServer(t, srv)
+
+	if err := deliverWebhook(srv.URL, "", []byte(`{}`)); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+}
+
+func TestDeliverWebhook_GivesUp(t *testing.T) {
+	old := webhookRetryDelays
+	webhookRetryDelays = []time.Duration{time.Millisecond}
+	t.Cleanup(func() { webhookRetryDelays = old })
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	useTestWebhookServer(t, srv)
+
+	if err := deliverWebhook(srv.URL, "", []byte(`{}`)); err == nil {
+		t.Fatal("expected error after retries run out")
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestDeliverWebhook_RefusesPrivateAddress(t *testing.T) {
+	old := webhookRetryDelays
+	webhookRetryDelays = []time.Duration{time.Millisecond}
+	t.Cleanup(func() { webhookRetryDelays = old })
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+	}))
+	defer srv.Close()
+
+	err := deliverWebhook(srv.URL, "", []byte(`{}`))
+	if !errors.Is(err, models.ErrPrivateWebhookAddress) {
+		t.Fatalf("err = %v, want ErrPrivateWebhookAddress", err)
+	}
+	if n := attempts.Load(); n != 0 {
+		t.Errorf("attempts = %d, want 0", n)
+	}
+}
+
+func TestSend_Webhook(t *testing.T) {
+	t.Setenv("REPLOG_SECRET_KEY", "test-secret-key")
+	db := testDB(t)
+
+	type delivery struct {
+		payload   WebhookPayload
+		signature string
+		body      []byte
+	}
+	received := make(chan delivery, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p WebhookPayload
+		json.Unmarshal(body, &p)
+		received <- delivery{payload: p, signature: r.Header.Get(SignatureHeader), body: body}
+	}))
+	defer srv.Close()
+	useTestWebhookServer(t, srv)
+
+	user, _ := models.CreateUser(db, "coach", "", "password", "", true, false, sql.NullInt64{})
+	athlete, _ := models.CreateAthlete(db, "Kid", "", "", "", "", "", "", sql.NullInt64{}, true)
+	models.SetNotificationPreference(db, user.ID, models.NotifyWorkoutLogged, true, true)
+	if err := models.SetNotificationWebhook(db, user.ID, "http://hooks.example.com/replog", "hook-secret"); err != nil {
+		t.Fatalf("set webhook: %v", err)
+	}
+
+	Send(db, Request{
+		UserID:    user.ID,
+		Type:      models.NotifyWorkoutLogged,
+		Title:     "Workout ready for review",
+		Link:      "/athletes/1/workouts/2",
+		AthleteID: sql.NullInt64{Int64: athlete.ID, Valid: true},
+	})
+
+	select {
+	case d := <-received:
+		if d.payload.Type != models.NotifyWorkoutLogged || d.payload.Title != "Workout ready for review" {
+			t.Errorf("payload = %+v", d.payload)
+		}
+		if d.payload.Athlete == nil || d.payload.Athlete.Name != "Kid" {
+			t.Errorf("athlete = %+v, want Kid", d.payload.Athlete)
+		}
+		if d.payload.Link != "/athletes/1/workouts/2" {
+			t.Errorf("link = %q", d.payload.Link)
+		}
+		if want := "sha256=" + signWebhook("hook-secret", d.body); d.signature != want {
+			t.Errorf("signature = %q, want %q", d.signature, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+}
@@ -1,12 +1,16 @@
 package scheduler
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/carpenike/replog/internal/models"
+	"github.com/carpenike/replog/internal/notify"
+	"github.com/carpenike/replog/internal/thumbnails"
 )
 
 // Status holds the result of the last maintenance run.
@@ -15,15 +19,18 @@ type Status struct {
 	NextRun           time.Time
 	TokensDeleted     int64
 	NotificationsPruned int64
+	DigestsSent       int64
+	MissedSessions    int64
 	IntervalHours     int
 	RetentionDays     int
 }
 
 // Scheduler runs periodic maintenance tasks in the background.
 type Scheduler struct {
-	db   *sql.DB
-	stop chan struct{}
-	done chan struct{}
+	db     *sql.DB
+	thumbs *thumbnails.Cache
+	stop   chan struct{}
+	done   chan struct{}
 
 	mu     sync.RWMutex
 	status Status
@@ -38,6 +45,12 @@ func New(db *sql.DB) *Scheduler {
 	}
 }
 
+// SetThumbnails enables refreshing cached exercise demo thumbnails on each
+// run. Call before Start.
+func (s *Scheduler) SetThumbnails(c *thumbnails.Cache) {
+	s.thumbs = c
+}
+
 // Start begins running maintenance tasks. It runs an initial pass immediately,
 // then repeats at the configured interval. Call Stop to shut down gracefully.
 func (s *Scheduler) Start() {
@@ -96,7 +109,14 @@ func (s *Scheduler) runMaintenance() {
 	log.Println("Running scheduled maintenance...")
 
 	tokensDeleted := s.cleanExpiredTokens()
+	s.pruneStaleSessions()
+	s.cleanPasswordResetTokens()
+	s.cleanEmailVerificationTokens()
+	s.pruneLoginAttempts()
 	notifsPruned := s.pruneOldNotifications()
+	digestsSent := s.sendDigests()
+	missedSessions := s.notifyMissedSessions()
+	s.refreshThumbnails()
 
 	now := time.Now()
 	interval := s.getInterval()
@@ -107,6 +127,8 @@ func (s *Scheduler) runMaintenance() {
 		NextRun:             now.Add(interval),
 		TokensDeleted:       tokensDeleted,
 		NotificationsPruned: notifsPruned,
+		DigestsSent:         digestsSent,
+		MissedSessions:      missedSessions,
 		IntervalHours:       models.GetMaintenanceIntervalHours(s.db),
 		RetentionDays:       models.GetMaintenanceRetentionDays(s.db),
 	}
@@ -128,6 +150,73 @@ func (s *Scheduler) cleanExpiredTokens() int64 {
 	return deleted
 }
 
+// pruneStaleSessions removes session-list records for sessions that have
+// expired or been logged out.
+func (s *Scheduler) pruneStaleSessions() {
+	deleted, err := models.DeleteStaleUserSessions(s.db)
+	if err != nil {
+		log.Printf("Maintenance: prune stale sessions: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Maintenance: pruned %d stale session record(s)", deleted)
+	}
+}
+
+// cleanPasswordResetTokens removes password reset tokens that have expired
+// or been used.
+func (s *Scheduler) cleanPasswordResetTokens() {
+	deleted, err := models.DeleteExpiredPasswordResetTokens(s.db)
+	if err != nil {
+		log.Printf("Maintenance: clean password reset tokens: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Maintenance: deleted %d password reset token(s)", deleted)
+	}
+}
+
+// cleanEmailVerificationTokens removes email verification tokens that have
+// expired or been used.
+func (s *Scheduler) cleanEmailVerificationTokens() {
+	deleted, err := models.DeleteExpiredEmailVerificationTokens(s.db)
+	if err != nil {
+		log.Printf("Maintenance: clean email verification tokens: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Maintenance: deleted %d email verification token(s)", deleted)
+	}
+}
+
+// pruneLoginAttempts removes failed-login counts that have aged out.
+func (s *Scheduler) pruneLoginAttempts() {
+	deleted, err := models.DeleteStaleLoginAttempts(s.db)
+	if err != nil {
+		log.Printf("Maintenance: prune login attempts: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Maintenance: pruned %d login attempt record(s)", deleted)
+	}
+}
+
+// refreshThumbnails fetches missing or outdated exercise demo thumbnails
+// and removes unused ones.
+func (s *Scheduler) refreshThumbnails() {
+	if s.thumbs == nil {
+		return
+	}
+	fetched, err := s.thumbs.Refresh(context.Background(), s.db)
+	if err != nil {
+		log.Printf("Maintenance: refresh demo thumbnails: %v", err)
+		return
+	}
+	if fetched > 0 {
+		log.Printf("Maintenance: fetched %d demo thumbnail(s)", fetched)
+	}
+}
+
 // pruneOldNotifications removes read notifications older than the configured retention period.
 func (s *Scheduler) pruneOldNotifications() int64 {
 	cutoff := time.Now().Add(-s.getRetention())
@@ -141,3 +230,66 @@ func (s *Scheduler) pruneOldNotifications() int64 {
 	}
 	return deleted
 }
+
+// sendDigests delivers notification digests to users whose daily or weekly
+// digest is due. Returns the number of digests that contained notifications.
+func (s *Scheduler) sendDigests() int64 {
+	userIDs, err := models.ListDigestDueUsers(s.db)
+	if err != nil {
+		log.Printf("Maintenance: list digest users: %v", err)
+		return 0
+	}
+
+	var sent int64
+	for _, id := range userIDs {
+		n, err := notify.SendDigest(s.db, id)
+		if err != nil {
+			log.Printf("Maintenance: send digest to user %d: %v", id, err)
+			continue
+		}
+		if n > 0 {
+			sent++
+		}
+	}
+	if sent > 0 {
+		log.Printf("Maintenance: sent %d notification digest(s)", sent)
+	}
+	return sent
+}
+
+// notifyMissedSessions tells coaches about athletes who had a scheduled
+// training day yesterday but logged no workout. Each athlete and date is
+// reported once, however often maintenance runs.
+func (s *Scheduler) notifyMissedSessions() int64 {
+	day := time.Now().AddDate(0, 0, -1)
+	missed, err := models.AthletesWithMissedSessions(s.db, day)
+	if err != nil {
+		log.Printf("Maintenance: list missed sessions: %v", err)
+		return 0
+	}
+
+	var notified int64
+	for _, m := range missed {
+		recorded, err := models.RecordMissedSessionAlert(s.db, m.AthleteID, m.Date)
+		if err != nil {
+			log.Printf("Maintenance: record missed session for athlete %d: %v", m.AthleteID, err)
+			continue
+		}
+		if !recorded {
+			continue
+		}
+		notify.Send(s.db, notify.Request{
+			UserID:    m.CoachID,
+			Type:      models.NotifyMissedSession,
+			Title:     fmt.Sprintf("%s missed a scheduled session", m.AthleteName),
+			Message:   fmt.Sprintf("No workout was logged on %s.", day.Format("Mon, Jan 2")),
+			Link:      fmt.Sprintf("/athletes/%d", m.AthleteID),
+			AthleteID: sql.NullInt64{Int64: m.AthleteID, Valid: true},
+		})
+		notified++
+	}
+	if notified > 0 {
+		log.Printf("Maintenance: reported %d missed session(s)", notified)
+	}
+	return notified
+}
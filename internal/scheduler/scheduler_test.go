@@ -128,3 +128,59 @@ func TestMaintenanceReadsSettings(t *testing.T) {
 		t.Errorf("RetentionDays = %d, want 30", st.RetentionDays)
 	}
 }
+
+func TestMaintenanceSendsDigests(t *testing.T) {
+	db := testDB(t)
+
+	daily, _ := models.CreateUser(db, "daily", "", "password", "", true, false, sql.NullInt64{})
+	off, _ := models.CreateUser(db, "off", "", "password", "", true, false, sql.NullInt64{})
+	for _, u := range []*models.User{daily, off} {
+		models.SetNotificationPreference(db, u.ID, models.NotifyWorkoutLogged, true, true)
+		models.CreateNotification(db, u.ID, models.NotifyWorkoutLogged, "Workout ready", "", "/test", sql.NullInt64{})
+	}
+	models.SetDigestFrequency(db, daily.ID, models.DigestDaily)
+
+	s := &Scheduler{db: db}
+	s.runMaintenance()
+
+	if st := s.Status(); st.DigestsSent != 1 {
+		t.Errorf("DigestsSent = %d, want 1", st.DigestsSent)
+	}
+
+	// A daily digest already sent today isn't due again.
+	s.runMaintenance()
+	if st := s.Status(); st.DigestsSent != 0 {
+		t.Errorf("DigestsSent on second run = %d, want 0", st.DigestsSent)
+	}
+}
+
+func TestMaintenanceNotifiesMissedSessions(t *testing.T) {
+	db := testDB(t)
+
+	coach, _ := models.CreateUser(db, "coach", "", "password", "", true, false, sql.NullInt64{})
+	athlete, _ := models.CreateAthlete(db, "Skipper", "", "", "", "", "", "", sql.NullInt64{Int64: coach.ID, Valid: true}, true)
+	tmpl, _ := models.CreateProgramTemplate(db, nil, "Program", "", 4, 3, false, "", 0, "")
+	ap, _ := models.AssignProgram(db, athlete.ID, tmpl.ID, "2020-01-01", "", "", "primary", "")
+	models.SetTrainingDays(db, ap.ID, 127) // every day
+
+	s := &Scheduler{db: db}
+	s.runMaintenance()
+
+	if st := s.Status(); st.MissedSessions != 1 {
+		t.Errorf("MissedSessions = %d, want 1", st.MissedSessions)
+	}
+	notifications, _ := models.ListNotifications(db, coach.ID, 10, 0)
+	if len(notifications) != 1 || notifications[0].Type != models.NotifyMissedSession {
+		t.Fatalf("coach notifications = %+v, want one missed_session", notifications)
+	}
+
+	// The same missed day is only reported once.
+	s.runMaintenance()
+	if st := s.Status(); st.MissedSessions != 0 {
+		t.Errorf("MissedSessions on second run = %d, want 0", st.MissedSessions)
+	}
+	notifications, _ = models.ListNotifications(db, coach.ID, 10, 0)
+	if len(notifications) != 1 {
+		t.Errorf("coach notifications after second run = %d, want 1", len(notifications))
+	}
+}
@@ -0,0 +1,195 @@
+// Package thumbnails fetches and caches YouTube thumbnails for exercise demo
+// videos, so the exercise catalog doesn't depend on YouTube being reachable
+// each time it renders.
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // thumbnails are JPEGs
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carpenike/replog/internal/models"
+)
+
+const (
+	// MaxAge is how long a cached thumbnail is kept before Refresh fetches
+	// it again, picking up thumbnails changed on YouTube.
+	MaxAge = 7 * 24 * time.Hour
+
+	// retryAfter is how long Get waits after a failed fetch before trying
+	// the same video again, so an offline server doesn't stall every page
+	// view on the fetch timeout.
+	retryAfter = time.Hour
+
+	// maxThumbnailSize caps the bytes read from the thumbnail host.
+	maxThumbnailSize = 2 << 20
+)
+
+// DefaultBaseURL is where YouTube serves video thumbnails.
+const DefaultBaseURL = "https://i.ytimg.com/vi/"
+
+// ErrRecentFailure is returned by Get when fetching the thumbnail failed
+// less than retryAfter ago.
+var ErrRecentFailure = errors.New("thumbnails: fetch failed recently")
+
+// Cache stores demo video thumbnails in Dir, one file per YouTube video ID.
+type Cache struct {
+	Dir     string
+	BaseURL string
+	Client  *http.Client
+
+	mu     sync.Mutex
+	failed map[string]time.Time // video ID -> time of last failed fetch
+}
+
+// New creates a Cache storing thumbnails in dir.
+func New(dir string) *Cache {
+	return &Cache{
+		Dir:     dir,
+		BaseURL: DefaultBaseURL,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+		failed:  make(map[string]time.Time),
+	}
+}
+
+// Path returns where the thumbnail for videoID is cached.
+func (c *Cache) Path(videoID string) string {
+	return filepath.Join(c.Dir, videoID+".jpg")
+}
+
+// Get returns the path of the cached thumbnail for videoID, fetching it
+// first if it isn't cached yet.
+func (c *Cache) Get(ctx context.Context, videoID string) (string, error) {
+	path := c.Path(videoID)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	c.mu.Lock()
+	last, ok := c.failed[videoID]
+	c.mu.Unlock()
+	if ok && time.Since(last) < retryAfter {
+		return "", ErrRecentFailure
+	}
+
+	if err := c.Fetch(ctx, videoID); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Fetch downloads the thumbnail for videoID and stores it, replacing any
+// cached copy. A failed fetch leaves the existing copy in place.
+func (c *Cache) Fetch(ctx context.Context, videoID string) error {
+	err := c.fetch(ctx, videoID)
+	c.mu.Lock()
+	if err != nil {
+		c.failed[videoID] = time.Now()
+	} else {
+		delete(c.failed, videoID)
+	}
+	c.mu.Unlock()
+	return err
+}
+
+func (c *Cache) fetch(ctx context.Context, videoID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+videoID+"/hqdefault.jpg", nil)
+	if err != nil {
+		return fmt.Errorf("thumbnails: build request for %s: %w", videoID, err)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("thumbnails: fetch %s: %w", videoID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("thumbnails: fetch %s: status %d", videoID, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailSize+1))
+	if err != nil {
+		return fmt.Errorf("thumbnails: read %s: %w", videoID, err)
+	}
+	if len(data) > maxThumbnailSize {
+		return fmt.Errorf("thumbnails: %s is larger than %d bytes", videoID, maxThumbnailSize)
+	}
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || format != "jpeg" {
+		return fmt.Errorf("thumbnails: %s is not a JPEG", videoID)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("thumbnails: create dir: %w", err)
+	}
+	// Write to a temporary file and rename so readers never see a partial
+	// thumbnail.
+	tmp, err := os.CreateTemp(c.Dir, videoID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("thumbnails: create temp file: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.Path(videoID))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("thumbnails: save %s: %w", videoID, err)
+	}
+	return nil
+}
+
+// Refresh fetches thumbnails for exercise demos that are missing or older
+// than MaxAge, and deletes cached thumbnails no exercise uses any more.
+// Returns the number of thumbnails fetched.
+func (c *Cache) Refresh(ctx context.Context, db *sql.DB) (int, error) {
+	ids, err := models.ListDemoVideoIDs(db)
+	if err != nil {
+		return 0, err
+	}
+
+	used := make(map[string]bool, len(ids))
+	fetched := 0
+	for _, id := range ids {
+		used[id] = true
+		if info, err := os.Stat(c.Path(id)); err == nil && time.Since(info.ModTime()) < MaxAge {
+			continue
+		}
+		if err := c.Fetch(ctx, id); err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		fetched++
+	}
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fetched, nil
+		}
+		return fetched, fmt.Errorf("thumbnails: read dir: %w", err)
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".jpg")
+		if !ok || used[id] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.Dir, e.Name())); err != nil {
+			log.Printf("thumbnails: remove unused %s: %v", e.Name(), err)
+		}
+	}
+	return fetched, nil
+}
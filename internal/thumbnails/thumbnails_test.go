@@ -0,0 +1,166 @@
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/carpenike/replog/internal/database"
+	"github.com/carpenike/replog/internal/models"
+)
+
+const videoID = "dQw4w9WgXcQ"
+
+// testDB creates a fresh in-memory SQLite database with migrations applied.
+func testDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	if err := database.RunMigrations(db); err != nil {
+		db.Close()
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// thumbServer serves a small JPEG for any path while ok is true, and 404
+// otherwise. It counts requests.
+func thumbServer(t *testing.T, ok *atomic.Bool, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 3)), nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !ok.Load() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGet(t *testing.T) {
+	var ok atomic.Bool
+	var hits atomic.Int32
+	ok.Store(true)
+	srv := thumbServer(t, &ok, &hits)
+
+	c := New(t.TempDir())
+	c.BaseURL = srv.URL + "/vi/"
+
+	path, err := c.Get(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("thumbnail not cached: %v", err)
+	}
+
+	// A cached thumbnail is served without fetching again, even offline.
+	ok.Store(false)
+	if _, err := c.Get(context.Background(), videoID); err != nil {
+		t.Errorf("Get cached: %v", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+}
+
+func TestGet_BacksOffAfterFailure(t *testing.T) {
+	var ok atomic.Bool
+	var hits atomic.Int32
+	srv := thumbServer(t, &ok, &hits)
+
+	c := New(t.TempDir())
+	c.BaseURL = srv.URL + "/vi/"
+
+	if _, err := c.Get(context.Background(), videoID); err == nil {
+		t.Fatal("Get succeeded against a failing server")
+	}
+	if _, err := c.Get(context.Background(), videoID); !errors.Is(err, ErrRecentFailure) {
+		t.Errorf("second Get err = %v, want ErrRecentFailure", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+}
+
+func TestFetch_RejectsNonJPEG(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>not an image</html>"))
+	}))
+	defer srv.Close()
+
+	c := New(t.TempDir())
+	c.BaseURL = srv.URL + "/vi/"
+
+	if err := c.Fetch(context.Background(), videoID); err == nil {
+		t.Fatal("Fetch accepted a non-image response")
+	}
+	if _, err := os.Stat(c.Path(videoID)); !os.IsNotExist(err) {
+		t.Errorf("non-image response was cached")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	db := testDB(t)
+	var ok atomic.Bool
+	var hits atomic.Int32
+	ok.Store(true)
+	srv := thumbServer(t, &ok, &hits)
+
+	dir := t.TempDir()
+	c := New(dir)
+	c.BaseURL = srv.URL + "/vi/"
+
+	if _, err := models.CreateExercise(db, "Squat", "", "", "", "https://youtu.be/"+videoID, 0); err != nil {
+		t.Fatalf("create exercise: %v", err)
+	}
+	stale := filepath.Join(dir, "oldvideo123.jpg")
+	os.WriteFile(stale, []byte("x"), 0o644)
+
+	n, err := c.Refresh(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("fetched = %d, want 1", n)
+	}
+	if _, err := os.Stat(c.Path(videoID)); err != nil {
+		t.Errorf("thumbnail not cached: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("unused thumbnail was not removed")
+	}
+
+	// Fresh thumbnails are left alone.
+	if n, _ := c.Refresh(context.Background(), db); n != 0 {
+		t.Errorf("second refresh fetched = %d, want 0", n)
+	}
+
+	// Old ones are fetched again.
+	old := time.Now().Add(-MaxAge - time.Hour)
+	os.Chtimes(c.Path(videoID), old, old)
+	if n, _ := c.Refresh(context.Background(), db); n != 1 {
+		t.Errorf("refresh of old thumbnail fetched = %d, want 1", n)
+	}
+}